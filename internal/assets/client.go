@@ -0,0 +1,166 @@
+package assets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	cloudasset "google.golang.org/api/cloudasset/v1"
+	"google.golang.org/api/option"
+)
+
+// SearchParams are the parameters for assets.search
+type SearchParams struct {
+	ProjectID  string   `json:"project_id"`
+	Query      string   `json:"query"`       // Asset search query (e.g., 'state:RUNNABLE')
+	AssetTypes []string `json:"asset_types"` // e.g., ["sqladmin.googleapis.com/Instance"]
+	Limit      int      `json:"limit"`
+}
+
+// SearchResult is the result of assets.search
+type SearchResult struct {
+	QueryMeta QueryMeta   `json:"query_meta"`
+	Assets    []Asset     `json:"assets"`
+	Stats     ResultStats `json:"stats"`
+}
+
+type QueryMeta struct {
+	ProjectID  string   `json:"project_id"`
+	Query      string   `json:"query,omitempty"`
+	AssetTypes []string `json:"asset_types,omitempty"`
+	Limit      int      `json:"limit"`
+}
+
+type Asset struct {
+	Name                 string            `json:"name"`
+	AssetType            string            `json:"asset_type"`
+	DisplayName          string            `json:"display_name,omitempty"`
+	Location             string            `json:"location,omitempty"`
+	State                string            `json:"state,omitempty"`
+	Labels               map[string]string `json:"labels,omitempty"`
+	CreateTime           string            `json:"create_time,omitempty"`
+	UpdateTime           string            `json:"update_time,omitempty"`
+	AdditionalAttributes map[string]any    `json:"additional_attributes,omitempty"`
+}
+
+type ResultStats struct {
+	ReturnedCount int  `json:"returned_count"`
+	Truncated     bool `json:"truncated"`
+}
+
+// Client is the Cloud Asset Inventory client
+type Client struct {
+	service *cloudasset.Service
+}
+
+// NewClient creates a new Cloud Asset Inventory client
+func NewClient(ctx context.Context, opts ...option.ClientOption) (*Client, error) {
+	service, err := cloudasset.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cloud asset client: %w", err)
+	}
+	return &Client{service: service}, nil
+}
+
+// Search searches resources in a project via Cloud Asset Inventory
+func (c *Client) Search(ctx context.Context, params SearchParams) (*SearchResult, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	assets := []Asset{}
+	truncated := false
+	pageToken := ""
+
+	for {
+		call := c.service.V1.SearchAllResources(fmt.Sprintf("projects/%s", params.ProjectID)).
+			Context(ctx).
+			Query(params.Query).
+			PageSize(int64(min(limit, 500))).
+			PageToken(pageToken)
+		if len(params.AssetTypes) > 0 {
+			call = call.AssetTypes(params.AssetTypes...)
+		}
+
+		resp, err := call.Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to search assets: %w", err)
+		}
+
+		for _, r := range resp.Results {
+			if len(assets) >= limit {
+				truncated = true
+				break
+			}
+			assets = append(assets, convertAsset(r))
+		}
+
+		if truncated || resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	return &SearchResult{
+		QueryMeta: QueryMeta{
+			ProjectID:  params.ProjectID,
+			Query:      params.Query,
+			AssetTypes: params.AssetTypes,
+			Limit:      limit,
+		},
+		Assets: assets,
+		Stats: ResultStats{
+			ReturnedCount: len(assets),
+			Truncated:     truncated,
+		},
+	}, nil
+}
+
+func convertAsset(r *cloudasset.ResourceSearchResult) Asset {
+	a := Asset{
+		Name:        r.Name,
+		AssetType:   r.AssetType,
+		DisplayName: r.DisplayName,
+		Location:    r.Location,
+		State:       r.State,
+		Labels:      r.Labels,
+		CreateTime:  r.CreateTime,
+		UpdateTime:  r.UpdateTime,
+	}
+	if len(r.AdditionalAttributes) > 0 {
+		var attrs map[string]any
+		if err := json.Unmarshal(r.AdditionalAttributes, &attrs); err == nil {
+			a.AdditionalAttributes = attrs
+		}
+	}
+	return a
+}
+
+// Validator はガードレール検証用インターフェース
+type Validator interface {
+	RestrictAssetTypes(assetTypes []string) ([]string, error)
+	ClampAssetResults(limit int) int
+}
+
+// SearchHandlerWithGuardrail returns a handler with guardrail validation
+func (c *Client) SearchHandlerWithGuardrail(v Validator) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params SearchParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		// ガードレール: アセット種別の制限
+		assetTypes, err := v.RestrictAssetTypes(params.AssetTypes)
+		if err != nil {
+			return nil, err
+		}
+		params.AssetTypes = assetTypes
+
+		// ガードレール: 件数制限
+		params.Limit = v.ClampAssetResults(params.Limit)
+
+		return c.Search(ctx, params)
+	}
+}
@@ -0,0 +1,76 @@
+package assets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/guardrail"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/provider"
+)
+
+func init() {
+	provider.Register("assets", newProvider)
+}
+
+// toolProvider は Cloud Asset Inventory のツール（assets.*）を提供する
+type toolProvider struct {
+	client *provider.Lazy[*Client]
+	cfg    *config.Config
+	guard  *guardrail.Guardrail
+}
+
+func newProvider(ctx context.Context, env provider.Env) (provider.ToolProvider, error) {
+	return &toolProvider{client: provider.NewLazy(ctx, "assets", func(ctx context.Context) (*Client, error) {
+		return NewClient(ctx, env.HTTPOptions...)
+	}), cfg: env.Config, guard: env.Guard}, nil
+}
+
+func (p *toolProvider) Name() string {
+	return "assets"
+}
+
+func (p *toolProvider) RequiredAPIs() []string {
+	return []string{"cloudasset.googleapis.com"}
+}
+
+func (p *toolProvider) Tools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "assets.search",
+			Description: "Search resources in a project via Cloud Asset Inventory (e.g., Cloud SQL instances with public IP).",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"query": {
+						Type:        "string",
+						Description: "Asset search query (e.g., 'state:RUNNABLE', 'labels.env:prod')",
+					},
+					"asset_types": {
+						Type:        "array",
+						Description: "Asset types to search (e.g., ['sqladmin.googleapis.com/Instance']; default: all allowed)",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"limit": {
+						Type:        "integer",
+						Description: fmt.Sprintf("Maximum number of assets to return (default: 100, max: %d)", p.cfg.Assets.MaxResults),
+						Default:     100,
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[SearchResult](),
+		},
+	}
+}
+
+func (p *toolProvider) Handlers() map[string]mcp.ToolHandler {
+	return map[string]mcp.ToolHandler{
+		"assets.search": p.client.Handler(func(c *Client) mcp.ToolHandler { return c.SearchHandlerWithGuardrail(p.guard) }),
+	}
+}
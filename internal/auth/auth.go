@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"google.golang.org/api/idtoken"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+)
+
+type contextKey struct{}
+
+type profileKey struct{}
+
+// selectedProfile は呼び出しに適用するプロファイルとその名前
+type selectedProfile struct {
+	name    string
+	profile *config.Profile
+}
+
+// WithClient は認証済みのクライアントを ctx に設定する
+func WithClient(ctx context.Context, client *config.HTTPClient) context.Context {
+	return context.WithValue(ctx, contextKey{}, client)
+}
+
+// ClientFrom は ctx の認証済みクライアントを返す（stdio や認証なしの HTTP では nil）
+func ClientFrom(ctx context.Context) *config.HTTPClient {
+	client, _ := ctx.Value(contextKey{}).(*config.HTTPClient)
+	return client
+}
+
+// ClientName は ctx の認証済みクライアントの名前を返す（なければ空）
+func ClientName(ctx context.Context) string {
+	if client := ClientFrom(ctx); client != nil {
+		return client.Name
+	}
+	return ""
+}
+
+// WithProfile は呼び出しに適用するガードレールプロファイルを ctx に設定する
+func WithProfile(ctx context.Context, name string, profile *config.Profile) context.Context {
+	return context.WithValue(ctx, profileKey{}, selectedProfile{name: name, profile: profile})
+}
+
+// ProfileFrom は ctx のプロファイルとその名前を返す（なければ "" と nil）
+func ProfileFrom(ctx context.Context) (string, *config.Profile) {
+	p, _ := ctx.Value(profileKey{}).(selectedProfile)
+	return p.name, p.profile
+}
+
+// tokenClient は静的な bearer トークンとそのクライアント
+type tokenClient struct {
+	token  []byte
+	client *config.HTTPClient
+}
+
+// Authenticator は HTTP リクエストの bearer トークンから接続元のクライアントを特定する
+//   - 静的なトークン: clients[].token_env の環境変数の値と一致するもの
+//   - ID トークン: Google が発行し、audience が oidc_audience のもの（email または sub を clients[].oidc_principals と照合）
+type Authenticator struct {
+	tokens     []tokenClient
+	audience   string
+	principals map[string]*config.HTTPClient
+	profiles   map[string]*config.Profile // 接続元の profile 名 → プロファイル
+	validateID func(ctx context.Context, token, audience string) (*idtoken.Payload, error)
+}
+
+// New は設定（http.clients）から Authenticator を作成する
+// トークンは起動時に環境変数から読む（空の場合はエラー）
+func New(cfg *config.Config) (*Authenticator, error) {
+	a := &Authenticator{
+		audience:   cfg.HTTP.OIDCAudience,
+		principals: map[string]*config.HTTPClient{},
+		profiles:   map[string]*config.Profile{},
+		validateID: idtoken.Validate,
+	}
+	for i := range cfg.HTTP.Clients {
+		client := &cfg.HTTP.Clients[i]
+		if client.Profile != "" {
+			a.profiles[client.Profile] = cfg.ProfileByName(client.Profile)
+		}
+		if client.TokenEnv != "" {
+			token := os.Getenv(client.TokenEnv)
+			if token == "" {
+				return nil, fmt.Errorf("http client %q: environment variable %s is not set", client.Name, client.TokenEnv)
+			}
+			a.tokens = append(a.tokens, tokenClient{token: []byte(token), client: client})
+		}
+		for _, p := range client.OIDCPrincipals {
+			a.principals[p] = client
+		}
+	}
+	return a, nil
+}
+
+// Authenticate は bearer トークンのクライアントを返す
+func (a *Authenticator) Authenticate(ctx context.Context, token string) (*config.HTTPClient, error) {
+	// 一致の有無で処理時間が変わらないよう、全てのトークンと比較する
+	var matched *config.HTTPClient
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare(t.token, []byte(token)) == 1 {
+			matched = t.client
+		}
+	}
+	if matched != nil {
+		return matched, nil
+	}
+
+	if a.audience == "" || strings.Count(token, ".") != 2 {
+		return nil, fmt.Errorf("unknown token")
+	}
+	payload, err := a.validateID(ctx, token, a.audience)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token: %w", err)
+	}
+	if email, _ := payload.Claims["email"].(string); email != "" {
+		if verified, _ := payload.Claims["email_verified"].(bool); verified {
+			if client, ok := a.principals[email]; ok {
+				return client, nil
+			}
+		}
+	}
+	if client, ok := a.principals[payload.Subject]; ok {
+		return client, nil
+	}
+	return nil, fmt.Errorf("ID token principal %q is not a configured client", payload.Subject)
+}
+
+// Middleware は認証できたリクエストだけを next に渡し、クライアントとその profile を ctx に設定する
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			unauthorized(w, r, "missing bearer token")
+			return
+		}
+		client, err := a.Authenticate(r.Context(), token)
+		if err != nil {
+			unauthorized(w, r, err.Error())
+			return
+		}
+		ctx := WithClient(r.Context(), client)
+		if client.Profile != "" {
+			ctx = WithProfile(ctx, client.Profile, a.profiles[client.Profile])
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// unauthorized は 401 を返す（理由はクライアントに返さずログにだけ出す）
+func unauthorized(w http.ResponseWriter, r *http.Request, reason string) {
+	slog.Warn("rejected unauthenticated request", "remote", r.RemoteAddr, "reason", reason)
+	w.Header().Set("WWW-Authenticate", `Bearer realm="gcp-ops-mcp"`)
+	http.Error(w, "unauthorized", http.StatusUnauthorized)
+}
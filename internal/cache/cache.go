@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/auth"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/telemetry"
+)
+
+// entry はキャッシュした結果1件
+type entry struct {
+	result  any
+	expires time.Time
+}
+
+// Cache は読み取りツールの結果を同じ引数の呼び出しに短時間だけ再利用する
+// 同じ調査の中で同じクエリを繰り返す場合の API 呼び出しとクォータ消費を減らすため
+type Cache struct {
+	ttl        time.Duration
+	maxEntries int
+	skip       map[string]bool
+
+	mu      sync.Mutex
+	entries map[string]entry
+	order   []string // 古い順（上限を超えたら先頭から捨てる）
+}
+
+// New はCache設定からCacheを作成する。skipTools のツールはキャッシュしない
+func New(cfg config.Cache, skipTools ...string) *Cache {
+	c := &Cache{
+		ttl:        time.Duration(cfg.TTLSeconds) * time.Second,
+		maxEntries: cfg.MaxEntries,
+		skip:       map[string]bool{},
+		entries:    map[string]entry{},
+	}
+	for _, t := range skipTools {
+		c.skip[t] = true
+	}
+	return c
+}
+
+// Middleware は読み取りツールの成功した結果をキャッシュするミドルウェアを返す
+// キーはツール名と引数（エイリアス解決後、JSON を正規化したもの）
+// HTTP トランスポートでは接続元ごとに許可されるプロジェクトが違うため、接続元もキーに含める
+func (c *Cache) Middleware() mcp.Middleware {
+	return func(tool *mcp.Tool, next mcp.ToolHandler) mcp.ToolHandler {
+		if !tool.IsReadOnly() || c.skip[tool.Name] {
+			return next
+		}
+		name := tool.Name
+		return func(ctx context.Context, args json.RawMessage) (any, error) {
+			key := auth.ClientName(ctx) + "\x00" + name + "\x00" + canonical(args)
+			if result, ok := c.get(key); ok {
+				telemetry.RecordCacheLookup(ctx, "tool_result", true)
+				return result, nil
+			}
+			telemetry.RecordCacheLookup(ctx, "tool_result", false)
+
+			result, err := next(ctx, args)
+			if err == nil {
+				c.put(key, result)
+			}
+			return result, err
+		}
+	}
+}
+
+func (c *Cache) get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || time.Now().After(e.expires) {
+		return nil, false
+	}
+	return e.result, true
+}
+
+func (c *Cache) put(key string, result any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok {
+		c.order = append(c.order, key)
+	}
+	c.entries[key] = entry{result: result, expires: time.Now().Add(c.ttl)}
+	for len(c.order) > c.maxEntries {
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+// canonical はキーの揺れ（空白・キー順）をなくすため引数を正規化する
+func canonical(args json.RawMessage) string {
+	var v any
+	if len(bytes.TrimSpace(args)) == 0 || json.Unmarshal(args, &v) != nil {
+		return string(args)
+	}
+	b, err := json.Marshal(v) // map のキーはソートされる
+	if err != nil {
+		return string(args)
+	}
+	return string(b)
+}
@@ -0,0 +1,262 @@
+package cloudrun
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/api/option"
+	runv2 "google.golang.org/api/run/v2"
+)
+
+// DescribeServiceParams are the parameters for run.describe_service
+type DescribeServiceParams struct {
+	ProjectID      string `json:"project_id"`
+	Location       string `json:"location"` // Region (e.g., "asia-northeast1")
+	Service        string `json:"service"`
+	RevisionsLimit int    `json:"revisions_limit"` // Recent revisions to return
+}
+
+// DescribeServiceResult is the result of run.describe_service
+type DescribeServiceResult struct {
+	QueryMeta QueryMeta  `json:"query_meta"`
+	Service   Service    `json:"service"`
+	Traffic   []Traffic  `json:"traffic"`
+	Revisions []Revision `json:"revisions"`
+	Errors    []string   `json:"errors,omitempty"`
+}
+
+type QueryMeta struct {
+	ProjectID string `json:"project_id"`
+	Location  string `json:"location"`
+	Service   string `json:"service"`
+}
+
+type Service struct {
+	Name                  string `json:"name"`
+	URI                   string `json:"uri,omitempty"`
+	LatestReadyRevision   string `json:"latest_ready_revision"`
+	LatestCreatedRevision string `json:"latest_created_revision"`
+	Ready                 string `json:"ready,omitempty"` // TerminalCondition state
+	ReadyMessage          string `json:"ready_message,omitempty"`
+	UpdateTime            string `json:"update_time"`
+	LastModifier          string `json:"last_modifier,omitempty"`
+}
+
+// Traffic は実際に配信されているトラフィック配分（TrafficStatuses）
+type Traffic struct {
+	Revision string `json:"revision"`
+	Percent  int64  `json:"percent"`
+	Tag      string `json:"tag,omitempty"`
+	Type     string `json:"type,omitempty"`
+}
+
+type Revision struct {
+	Name           string   `json:"name"`
+	CreateTime     string   `json:"create_time"`
+	Images         []string `json:"images"`
+	EnvNames       []string `json:"env_names,omitempty"` // 値は返さない（シークレット混入防止）
+	ConfigDigest   string   `json:"config_digest"`       // image/args/env/resources のハッシュ
+	EnvDigest      string   `json:"env_digest"`          // env のみのハッシュ
+	ServiceAccount string   `json:"service_account,omitempty"`
+	MinInstances   int64    `json:"min_instances"`
+	MaxInstances   int64    `json:"max_instances,omitempty"`
+	TrafficPercent int64    `json:"traffic_percent"`
+	Ready          string   `json:"ready,omitempty"`
+}
+
+// Client is the Cloud Run Admin API client
+type Client struct {
+	service *runv2.Service
+}
+
+// NewClient creates a new Cloud Run client
+func NewClient(ctx context.Context, opts ...option.ClientOption) (*Client, error) {
+	service, err := runv2.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cloud run client: %w", err)
+	}
+	return &Client{service: service}, nil
+}
+
+// DescribeService returns traffic splits and recent revisions of a Cloud Run service
+func (c *Client) DescribeService(ctx context.Context, params DescribeServiceParams) (*DescribeServiceResult, error) {
+	limit := params.RevisionsLimit
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	name := fmt.Sprintf("projects/%s/locations/%s/services/%s", params.ProjectID, params.Location, params.Service)
+	svc, err := c.service.Projects.Locations.Services.Get(name).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %w", err)
+	}
+
+	service := Service{
+		Name:                  lastSegment(svc.Name),
+		URI:                   svc.Uri,
+		LatestReadyRevision:   lastSegment(svc.LatestReadyRevision),
+		LatestCreatedRevision: lastSegment(svc.LatestCreatedRevision),
+		UpdateTime:            svc.UpdateTime,
+		LastModifier:          svc.LastModifier,
+	}
+	if svc.TerminalCondition != nil {
+		service.Ready = svc.TerminalCondition.State
+		service.ReadyMessage = svc.TerminalCondition.Message
+	}
+
+	traffic := []Traffic{}
+	percentByRevision := map[string]int64{}
+	for _, t := range svc.TrafficStatuses {
+		rev := lastSegment(t.Revision)
+		// LATEST 指定の場合 revision は空なので最新Readyリビジョンに読み替える
+		if rev == "" && t.Type == "TRAFFIC_TARGET_ALLOCATION_TYPE_LATEST" {
+			rev = service.LatestReadyRevision
+		}
+		traffic = append(traffic, Traffic{
+			Revision: rev,
+			Percent:  t.Percent,
+			Tag:      t.Tag,
+			Type:     t.Type,
+		})
+		percentByRevision[rev] += t.Percent
+	}
+
+	result := &DescribeServiceResult{
+		QueryMeta: QueryMeta{
+			ProjectID: params.ProjectID,
+			Location:  params.Location,
+			Service:   params.Service,
+		},
+		Service:   service,
+		Traffic:   traffic,
+		Revisions: []Revision{},
+	}
+
+	// リビジョン一覧の取得に失敗してもサービス情報は返す
+	revisions, err := c.listRevisions(ctx, name, limit)
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result, nil
+	}
+	for i := range revisions {
+		revisions[i].TrafficPercent = percentByRevision[revisions[i].Name]
+	}
+	result.Revisions = revisions
+
+	return result, nil
+}
+
+// listRevisions はサービスのリビジョンを作成日時の新しい順に返す
+func (c *Client) listRevisions(ctx context.Context, serviceName string, limit int) ([]Revision, error) {
+	revisions := []Revision{}
+	pageToken := ""
+	for {
+		resp, err := c.service.Projects.Locations.Services.Revisions.List(serviceName).
+			Context(ctx).
+			PageSize(100).
+			PageToken(pageToken).
+			Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list revisions: %w", err)
+		}
+		for _, r := range resp.Revisions {
+			revisions = append(revisions, toRevision(r))
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].CreateTime > revisions[j].CreateTime
+	})
+	if len(revisions) > limit {
+		revisions = revisions[:limit]
+	}
+	return revisions, nil
+}
+
+func toRevision(r *runv2.GoogleCloudRunV2Revision) Revision {
+	rev := Revision{
+		Name:           lastSegment(r.Name),
+		CreateTime:     r.CreateTime,
+		Images:         []string{},
+		ServiceAccount: r.ServiceAccount,
+	}
+	if r.Scaling != nil {
+		rev.MinInstances = r.Scaling.MinInstanceCount
+		rev.MaxInstances = r.Scaling.MaxInstanceCount
+	}
+	for _, cond := range r.Conditions {
+		if cond.Type == "Ready" {
+			rev.Ready = cond.State
+		}
+	}
+
+	envNames := []string{}
+	for _, ctr := range r.Containers {
+		rev.Images = append(rev.Images, ctr.Image)
+		for _, e := range ctr.Env {
+			envNames = append(envNames, e.Name)
+		}
+	}
+	sort.Strings(envNames)
+	if len(envNames) > 0 {
+		rev.EnvNames = envNames
+	}
+
+	envs := make([]any, 0, len(r.Containers))
+	for _, ctr := range r.Containers {
+		envs = append(envs, ctr.Env)
+	}
+	rev.EnvDigest = digest(envs)
+	rev.ConfigDigest = digest(r.Containers)
+
+	return rev
+}
+
+// digest はJSON表現のSHA-256先頭12桁を返す（値そのものを出さずに差分有無を比較するため）
+func digest(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// lastSegment はリソース名の最後の要素を返す
+func lastSegment(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// DescribeServiceHandler returns a handler for the run.describe_service tool
+func (c *Client) DescribeServiceHandler() func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params DescribeServiceParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.Location == "" {
+			return nil, fmt.Errorf("location is required")
+		}
+		if params.Service == "" {
+			return nil, fmt.Errorf("service is required")
+		}
+
+		return c.DescribeService(ctx, params)
+	}
+}
@@ -0,0 +1,70 @@
+package cloudrun
+
+import (
+	"context"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/provider"
+)
+
+func init() {
+	provider.Register("cloudrun", newProvider)
+}
+
+// toolProvider は Cloud Run Admin API のツール（run.*）を提供する
+type toolProvider struct {
+	client *provider.Lazy[*Client]
+}
+
+func newProvider(ctx context.Context, env provider.Env) (provider.ToolProvider, error) {
+	return &toolProvider{client: provider.NewLazy(ctx, "cloudrun", func(ctx context.Context) (*Client, error) {
+		return NewClient(ctx, env.HTTPOptions...)
+	})}, nil
+}
+
+func (p *toolProvider) Name() string {
+	return "cloudrun"
+}
+
+func (p *toolProvider) RequiredAPIs() []string {
+	return []string{"run.googleapis.com"}
+}
+
+func (p *toolProvider) Tools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "run.describe_service",
+			Description: "Describe a Cloud Run service: traffic splits, recent revisions with creation times, images, and config/env digests.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"location": {
+						Type:        "string",
+						Description: "Service region (e.g., 'asia-northeast1')",
+					},
+					"service": {
+						Type:        "string",
+						Description: "Cloud Run service name",
+					},
+					"revisions_limit": {
+						Type:        "integer",
+						Description: "Maximum number of recent revisions to return (default: 10, max: 50)",
+						Default:     10,
+					},
+				},
+				Required: []string{"project_id", "location", "service"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[DescribeServiceResult](),
+		},
+	}
+}
+
+func (p *toolProvider) Handlers() map[string]mcp.ToolHandler {
+	return map[string]mcp.ToolHandler{
+		"run.describe_service": p.client.Handler(func(c *Client) mcp.ToolHandler { return c.DescribeServiceHandler() }),
+	}
+}
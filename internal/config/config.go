@@ -1,81 +1,649 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
+	"path"
+	"slices"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
 // Config はMCPサーバーの設定
 type Config struct {
-	AllowedProjectIDs []string `yaml:"allowed_project_ids"`
-	Limits            Limits   `yaml:"limits"`
+	Mode              string             `yaml:"mode"`                 // "readonly"（デフォルト）or "standard"（書き込みツールを有効化）
+	LogLevel          string             `yaml:"log_level"`            // stderr に出すログのレベル: debug, info（デフォルト）, warn, error
+	LogFile           string             `yaml:"log_file"`             // ログを stderr ではなくこのファイルに追記する（stderr をエラー扱いするクライアント向け）
+	Quiet             bool               `yaml:"quiet"`                // 起動時のメッセージなど info 以下のログを出さない（log_level より優先）
+	Locale            string             `yaml:"locale"`               // ツールの説明・エラーメッセージの言語: en（デフォルト）or ja
+	Transport         string             `yaml:"transport"`            // stdio, http, unix（空 = http.listen があれば http、なければ stdio）
+	Socket            string             `yaml:"socket"`               // transport: unix で待ち受けるソケットのパス
+	ShutdownTimeout   int                `yaml:"shutdown_timeout_sec"` // 終了シグナル後、処理中のツール呼び出しの完了を待つ秒数
+	MaxParamsBytes    int                `yaml:"max_params_bytes"`     // リクエストの params の上限。超えたものはツールに渡さずに -32602 で拒否する
+	CredentialsFile   string             `yaml:"credentials_file"`     // ADC の代わりに使う認証情報ファイル（external_account の WIF 構成ファイル等）
+	GRPC              GRPC               `yaml:"grpc"`                 // Logging・Monitoring の gRPC の接続先・keepalive
+	AllowedProjectIDs []string           `yaml:"allowed_project_ids"`  // globパターン可（例: team-a-*）
+	DeniedProjectIDs  []string           `yaml:"denied_project_ids"`   // 許可より優先。globパターン可
+	AllowedFolders    []string           `yaml:"allowed_folders"`      // 配下のプロジェクトを許可（例: "123456" or "folders/123456"）
+	AllowedOrgs       []string           `yaml:"allowed_organizations"`
+	AllowedLogViews   []string           `yaml:"allowed_log_views"`  // 指定時はログをこのビュー経由でのみ読む（例: projects/X/locations/global/buckets/B/views/V）
+	ResourceRules     []ResourceRule     `yaml:"resource_rules"`     // 共有プロジェクトで読めるリソースを絞る
+	DefaultProjectID  string             `yaml:"default_project_id"` // project_id 省略時に使う（エイリアス可）
+	ProjectAliases    map[string]string  `yaml:"project_aliases"`    // 例: prod → my-company-prod-1234
+	Limits            Limits             `yaml:"limits"`
+	Assets            Assets             `yaml:"assets"`
+	Billing           Billing            `yaml:"billing"`
+	Security          Security           `yaml:"security"`
+	History           History            `yaml:"history"`
+	Spillover         Spillover          `yaml:"spillover"`
+	TokenBudget       TokenBudget        `yaml:"token_budget"`
+	Export            Export             `yaml:"export"`
+	Watches           Watches            `yaml:"watches"`
+	Notifications     Notifications      `yaml:"notifications"`
+	Telemetry         Telemetry          `yaml:"telemetry"`
+	Cache             Cache              `yaml:"cache"`
+	Redaction         Redaction          `yaml:"redaction"`
+	Providers         Providers          `yaml:"providers"`
+	Tools             Tools              `yaml:"tools"` // モデルに見せるツールの説明・一覧の調整
+	Preflight         Preflight          `yaml:"preflight"`
+	Logging           Logging            `yaml:"logging"`
+	HTTP              HTTP               `yaml:"http"`
+	Profiles          map[string]Profile `yaml:"profiles"` // 名前付きのガードレールプロファイル
+	Profile           string             `yaml:"profile"`  // 既定で適用するプロファイル（HTTP では接続元の profile が優先）
+	SavedQueries      []SavedQuery       `yaml:"saved_queries"`
+	SavedQueriesFile  string             `yaml:"saved_queries_file"` // 保存クエリを別ファイルで管理する場合
+	ResourceKinds     ResourceKinds      `yaml:"resource_kinds"`     // 組み込みのリソース種別の定義に重ねる（同名は置き換え）
 }
 
+// GRPC は gRPC の API クライアント（Cloud Logging・Cloud Monitoring）の接続設定
+// VPC Service Controls・限定公開の Google アクセス・Private Service Connect の環境向け
+type GRPC struct {
+	Endpoints           map[string]string `yaml:"endpoints"`             // サービス（logging, monitoring）ごとの接続先 host:port（例: logging.us-central1.rep.googleapis.com:443）
+	DialTimeoutSec      int               `yaml:"dial_timeout_sec"`      // 接続確立1回のタイムアウト（0 = gRPC のデフォルト 20 秒）
+	KeepaliveTimeSec    int               `yaml:"keepalive_time_sec"`    // 無通信がこの秒数続いたら ping を送る（0 = 送らない）
+	KeepaliveTimeoutSec int               `yaml:"keepalive_timeout_sec"` // ping の応答を待つ秒数。応答がなければ接続を張り直す（0 = gRPC のデフォルト 20 秒）
+}
+
+// GRPCServices は grpc.endpoints に指定できるサービス
+var GRPCServices = []string{"logging", "monitoring"}
+
 // Limits はクエリ制限の設定
 type Limits struct {
-	MaxRangeHours int `yaml:"max_range_hours"`
-	MaxLogEntries int `yaml:"max_log_entries"`
-	MaxTimeSeries int `yaml:"max_time_series"`
+	MaxRangeHours      int `yaml:"max_range_hours"`
+	MaxLogEntries      int `yaml:"max_log_entries"`
+	MaxTimeSeries      int `yaml:"max_time_series"`
+	MaxPointsPerSeries int `yaml:"max_points_per_series"` // 超えた系列はダウンサンプリングする
+}
+
+// Narrow は l を by の上限（0 = 制限なし）で絞り込んだ値を返す
+func (l Limits) Narrow(by Limits) Limits {
+	narrow := func(v, upper int) int {
+		if upper > 0 && upper < v {
+			return upper
+		}
+		return v
+	}
+	return Limits{
+		MaxRangeHours:      narrow(l.MaxRangeHours, by.MaxRangeHours),
+		MaxLogEntries:      narrow(l.MaxLogEntries, by.MaxLogEntries),
+		MaxTimeSeries:      narrow(l.MaxTimeSeries, by.MaxTimeSeries),
+		MaxPointsPerSeries: narrow(l.MaxPointsPerSeries, by.MaxPointsPerSeries),
+	}
+}
+
+// Assets はCloud Asset Inventory検索の設定
+type Assets struct {
+	AllowedAssetTypes []string `yaml:"allowed_asset_types"` // 空 = 制限なし
+	MaxResults        int      `yaml:"max_results"`
+}
+
+// Billing は課金エクスポートの設定
+type Billing struct {
+	ExportTable string `yaml:"export_table"` // "project.dataset.table"（空 = ops.cost_signal 無効）
+}
+
+// Security はSecurity Command Center連携の設定
+type Security struct {
+	Enabled     bool `yaml:"enabled"` // false の場合 security.* ツールを登録しない
+	MaxFindings int  `yaml:"max_findings"`
+}
+
+// History はツール呼び出し履歴（ops.recent_queries）の設定
+type History struct {
+	MaxEntries int `yaml:"max_entries"` // メモリ上に保持する件数
+}
+
+// Spillover は大きなツール結果をファイル/GCSに退避する設定
+type Spillover struct {
+	Enabled        bool   `yaml:"enabled"`
+	MaxResultBytes int    `yaml:"max_result_bytes"` // これを超える結果を退避し、要約とリソースURIを返す
+	Dir            string `yaml:"dir"`              // ローカルの退避先（空 = OSの一時ディレクトリ）
+	GCSBucket      string `yaml:"gcs_bucket"`       // 指定時はローカルではなくGCSに退避
+}
+
+// TokenBudget は結果のトークン数の見積もり（stats.estimated_tokens）の設定
+type TokenBudget struct {
+	SoftLimit int  `yaml:"soft_limit"` // 見積もりがこれを超えた結果に絞り込み方の提案を付ける
+	MaxTokens int  `yaml:"max_tokens"` // 見積もりがこれを超えた結果は要約に置き換える（全体は ops.result_page で読む）
+	Summarize bool `yaml:"summarize"`  // false なら max_tokens を超えた結果もそのまま返す
+}
+
+// Export はクエリ結果の書き出し（ops.export_result）の設定
+// 書き出し先（GCS バケットか BigQuery データセット）を指定した場合のみツールを登録する
+type Export struct {
+	GCSBucket       string `yaml:"gcs_bucket"`       // NDJSON / CSV の書き出し先
+	BigQueryDataset string `yaml:"bigquery_dataset"` // "project.dataset"（結果ごとにテーブルを作る）
+	MaxLogEntries   int    `yaml:"max_log_entries"`  // 1回の書き出しのログ件数の上限（limits.max_log_entries とは別）
+	MaxTimeSeries   int    `yaml:"max_time_series"`  // 1回の書き出しの系列数の上限
+}
+
+// Enabled は書き出し先が設定されているか返す
+func (e Export) Enabled() bool {
+	return e.GCSBucket != "" || e.BigQueryDataset != ""
+}
+
+// Watches はバックグラウンドの監視（ops.create_watch）の設定
+type Watches struct {
+	Enabled            bool `yaml:"enabled"`              // false の場合 ops.*_watch(es) ツールを登録しない
+	MaxWatches         int  `yaml:"max_watches"`          // 同時に有効な監視の数の上限
+	MinIntervalSec     int  `yaml:"min_interval_sec"`     // 評価間隔の下限
+	MaxDurationMinutes int  `yaml:"max_duration_minutes"` // 1つの監視を続ける時間の上限
+}
+
+// 通知のイベントの種類
+const (
+	NotifyWatch     = "watch"     // 監視の発火・解消・エラー
+	NotifyGuardrail = "guardrail" // ガードレール（許可されないプロジェクト・時間範囲の上限）による拒否
+)
+
+// NotifyEvents は通知先の events に指定できる種類
+var NotifyEvents = []string{NotifyWatch, NotifyGuardrail}
+
+// NotifySinkTypes は通知先の種類
+var NotifySinkTypes = []string{"slack", "http"}
+
+// Notifications はチャットの外（Slack・Webhook）への通知の設定
+type Notifications struct {
+	Sinks []NotificationSink `yaml:"sinks"`
+}
+
+// NotificationSink は通知先1件
+type NotificationSink struct {
+	Name   string   `yaml:"name"`
+	Type   string   `yaml:"type"`    // "slack"（Incoming Webhook）か "http"（イベントの JSON を POST）
+	URL    string   `yaml:"url"`     // URL（url_env とどちらか一方）
+	URLEnv string   `yaml:"url_env"` // URL を読む環境変数（Slack の Webhook URL など秘密を設定ファイルに書かない）
+	Events []string `yaml:"events"`  // 送るイベントの種類（空 = すべて）
+}
+
+// Wants は通知先が event の種類を受け取るか返す
+func (s NotificationSink) Wants(event string) bool {
+	return len(s.Events) == 0 || slices.Contains(s.Events, event)
+}
+
+// Telemetry はサーバー自身のメトリクス（ops.server_stats）のエクスポート設定
+type Telemetry struct {
+	OTLPEndpoint   string `yaml:"otlp_endpoint"`   // OTLP/HTTP の送信先（例: http://localhost:4318/v1/metrics。空 = 送信しない）
+	PrometheusAddr string `yaml:"prometheus_addr"` // /metrics の待ち受けアドレス（例: 127.0.0.1:9464。空 = 公開しない）
+}
+
+// Cache は読み取りツールの結果キャッシュの設定
+type Cache struct {
+	TTLSeconds int `yaml:"ttl_sec"`     // 同じ引数の呼び出しに結果を再利用する秒数（0 = 無効）
+	MaxEntries int `yaml:"max_entries"` // 保持する結果の件数
+}
+
+// Redaction はツール結果のマスキング設定
+type Redaction struct {
+	Patterns []string `yaml:"patterns"` // 正規表現。結果の文字列中の一致部分を [REDACTED] に置き換える（空 = 無効）
+}
+
+// Providers はツールプロバイダ（GCP連携ごとのツール群）の設定
+type Providers struct {
+	Disabled []string `yaml:"disabled"` // 登録しないプロバイダ（例: assets, gke）。空 = すべて有効
 }
 
+// Tools はモデルに見せるツールの説明と一覧（tools/list）の調整
+// 似たツールが多いとモデルが選び間違えるので、使い方に合わせて説明を書き換えたり一覧から外したりする
+type Tools struct {
+	Descriptions map[string]string `yaml:"descriptions"` // ツール名 → 置き換える説明（翻訳より優先）
+	Hidden       []string          `yaml:"hidden"`       // tools/list に出さないツール（globパターン可）。登録はされ、呼び出すことはできる
+}
+
+// IsHidden は tools/list からツールを外すか確認
+func (t Tools) IsHidden(name string) bool {
+	return matchAny(t.Hidden, name)
+}
+
+// Preflight は重いクエリの前に IAM 権限を確認する設定
+type Preflight struct {
+	Enabled         bool `yaml:"enabled"`       // testIamPermissions で必要な権限を確認し、足りなければ実行前にエラーにする
+	CacheTTLSeconds int  `yaml:"cache_ttl_sec"` // プロジェクトごとの確認結果を再利用する秒数
+}
+
+// Logging は logging.* ツールの設定
+type Logging struct {
+	DefaultMinSeverity string   `yaml:"default_min_severity"` // logging.query で重大度の指定がないときの下限（例: WARNING。空 = 絞り込まない）
+	ExcludeFilters     []string `yaml:"exclude_filters"`      // 既知のノイズの LQL。すべてのログ読み取りに NOT (...) で付け足す
+}
+
+// HTTP は HTTP トランスポートの設定（listen が空なら stdio で動く）
+type HTTP struct {
+	Listen               string       `yaml:"listen"`                // 待ち受けアドレス（例: ":8080"）
+	Path                 string       `yaml:"path"`                  // MCP エンドポイントのパス
+	WebSocketPath        string       `yaml:"websocket_path"`        // 同じアドレスで WebSocket でも待ち受けるパス（空 = 無効）
+	OIDCAudience         string       `yaml:"oidc_audience"`         // Google が発行した ID トークンを受け付ける場合の audience
+	Clients              []HTTPClient `yaml:"clients"`               // 接続を許可するクライアント
+	AllowUnauthenticated bool         `yaml:"allow_unauthenticated"` // clients なしで認証せずに待ち受ける（ローカル検証用）
+}
+
+// HTTPClient は HTTP トランスポートの接続元ごとの認証と制限
+// 制限は全体の設定をさらに絞り込むだけで、広げることはない
+type HTTPClient struct {
+	Name              string   `yaml:"name"`
+	TokenEnv          string   `yaml:"token_env"`           // 静的な bearer トークンを読む環境変数
+	OIDCPrincipals    []string `yaml:"oidc_principals"`     // ID トークンの email または sub
+	AllowedProjectIDs []string `yaml:"allowed_project_ids"` // globパターン可（空 = 全体の許可ルールのみ）
+	Limits            Limits   `yaml:"limits"`              // 0 = 全体の上限
+	Profile           string   `yaml:"profile"`             // 接続元に適用するプロファイル（空 = 既定の profile）
+	VisibleTools      []string `yaml:"visible_tools"`       // tools.hidden のうち、この接続元の tools/list には出すツール（globパターン可）
+}
+
+// ShowsTool は tools.hidden のツールをこの接続元の tools/list に出すか確認
+func (c *HTTPClient) ShowsTool(name string) bool {
+	return matchAny(c.VisibleTools, name)
+}
+
+// IsProjectAllowed はクライアントの許可リストにプロジェクトIDが一致するか確認（許可リストがなければ true）
+func (c *HTTPClient) IsProjectAllowed(projectID string) bool {
+	return len(c.AllowedProjectIDs) == 0 || matchAny(c.AllowedProjectIDs, projectID)
+}
+
+// Profile は名前付きのガードレールプロファイル（例: dev / prod のアシスタント向け）
+// 全体の設定をさらに絞り込むだけで、広げることはない
+type Profile struct {
+	AllowedProjectIDs []string  `yaml:"allowed_project_ids"` // globパターン可（空 = 全体の許可ルールのみ）
+	DeniedProjectIDs  []string  `yaml:"denied_project_ids"`
+	Limits            Limits    `yaml:"limits"`    // 0 = 全体の上限
+	Redaction         Redaction `yaml:"redaction"` // 全体のパターンに加えてマスクする
+}
+
+// IsProjectAllowed はプロファイルでプロジェクトIDが許可されているか確認
+func (p *Profile) IsProjectAllowed(projectID string) bool {
+	if matchAny(p.DeniedProjectIDs, projectID) {
+		return false
+	}
+	return len(p.AllowedProjectIDs) == 0 || matchAny(p.AllowedProjectIDs, projectID)
+}
+
+// HasRedaction は全体かいずれかのプロファイルにマスキングのパターンがあるか確認
+func (c *Config) HasRedaction() bool {
+	if len(c.Redaction.Patterns) > 0 {
+		return true
+	}
+	for _, p := range c.Profiles {
+		if len(p.Redaction.Patterns) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ProfileByName は名前のプロファイルを返す（空・未定義なら nil）
+func (c *Config) ProfileByName(name string) *Profile {
+	p, ok := c.Profiles[name]
+	if !ok {
+		return nil
+	}
+	return &p
+}
+
+// ResourceRule はプロジェクト内で読めるリソースを絞るルール（チーム共有のプロジェクト向け）
+// ログ・時系列の読み取りのフィルタに AND で付け足す
+type ResourceRule struct {
+	Projects      []string          `yaml:"projects"`       // 対象プロジェクト（globパターン可）
+	ResourceTypes []string          `yaml:"resource_types"` // 読めるリソース種別（空 = 制限なし）
+	DeniedLabels  map[string]string `yaml:"denied_labels"`  // 読まないラベル値（例: resource.labels.namespace_name: prod）
+}
+
+// ResourceRulesFor はプロジェクトに適用するリソースルールを返す
+func (c *Config) ResourceRulesFor(projectID string) []ResourceRule {
+	var rules []ResourceRule
+	for _, r := range c.ResourceRules {
+		if matchAny(r.Projects, projectID) {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// LogSeverities は Cloud Logging の重大度（低い順）
+var LogSeverities = []string{"DEFAULT", "DEBUG", "INFO", "NOTICE", "WARNING", "ERROR", "CRITICAL", "ALERT", "EMERGENCY"}
+
+// 動作モード
+const (
+	ModeReadOnly = "readonly" // 読み取りツールのみ登録
+	ModeStandard = "standard" // 書き込みツール（アラートのスヌーズ等）も登録
+)
+
+// トランスポート
+const (
+	TransportStdio = "stdio" // 標準入出力（クライアントが起動する1プロセス）
+	TransportHTTP  = "http"  // HTTP（http.listen）
+	TransportUnix  = "unix"  // Unix ドメインソケット（同じホストの複数のプロセスで1つのサーバーを共有する）
+)
+
+// EffectiveTransport は使うトランスポートを返す（transport 未指定なら http.listen の有無で決める）
+func (c *Config) EffectiveTransport() string {
+	switch {
+	case c.Transport != "":
+		return c.Transport
+	case c.HTTP.Listen != "":
+		return TransportHTTP
+	default:
+		return TransportStdio
+	}
+}
+
+const (
+	LocaleEN = "en" // 英語（コード中の文言そのまま）
+	LocaleJA = "ja" // 日本語（internal/i18n のカタログで置き換える）
+)
+
+// SlogLevel は log_level を slog のレベルに変換する（不正な値は info。quiet なら warn 以上）
+func (c *Config) SlogLevel() slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.LogLevel)); err != nil {
+		level = slog.LevelInfo
+	}
+	if c.Quiet {
+		level = max(level, slog.LevelWarn)
+	}
+	return level
+}
+
+// WriteEnabled は書き込みツールを登録してよいか返す
+func (c *Config) WriteEnabled() bool {
+	return c.Mode == ModeStandard
+}
+
+// defaultMaxParamsBytes はリクエストの params の上限の既定値（ツールの引数は大きくても数 KB）
+const defaultMaxParamsBytes = 1 << 20
+
 // DefaultConfig はデフォルト設定を返す
 func DefaultConfig() *Config {
 	return &Config{
+		Mode:              ModeReadOnly,
+		LogLevel:          "info",
+		Locale:            LocaleEN,
+		ShutdownTimeout:   30,
+		MaxParamsBytes:    defaultMaxParamsBytes,
 		AllowedProjectIDs: []string{}, // 空 = 制限なし
 		Limits: Limits{
-			MaxRangeHours: 72,
-			MaxLogEntries: 500,
-			MaxTimeSeries: 50,
+			MaxRangeHours:      72,
+			MaxLogEntries:      500,
+			MaxTimeSeries:      50,
+			MaxPointsPerSeries: 1000,
 		},
+		Assets: Assets{
+			AllowedAssetTypes: []string{},
+			MaxResults:        200,
+		},
+		Security: Security{
+			Enabled:     false,
+			MaxFindings: 200,
+		},
+		History: History{
+			MaxEntries: 50,
+		},
+		Spillover: Spillover{
+			Enabled:        false,
+			MaxResultBytes: 200000,
+		},
+		TokenBudget: TokenBudget{
+			SoftLimit: 20000,
+			MaxTokens: 25000,
+			Summarize: true,
+		},
+		Export: Export{
+			MaxLogEntries: 50000,
+			MaxTimeSeries: 500,
+		},
+		Watches: Watches{
+			Enabled:            false,
+			MaxWatches:         10,
+			MinIntervalSec:     60,
+			MaxDurationMinutes: 1440,
+		},
+		Cache: Cache{
+			TTLSeconds: 0,
+			MaxEntries: 100,
+		},
+		Preflight: Preflight{
+			Enabled:         false,
+			CacheTTLSeconds: 600,
+		},
+		HTTP: HTTP{
+			Path: "/mcp",
+		},
+		ResourceKinds: BuiltinResourceKinds(),
 	}
 }
 
 // Load は設定ファイルを読み込む
-func Load(path string) (*Config, error) {
+// 優先順位: フラグ > 環境変数 > 設定ファイル > デフォルト値
+// flagValues はオーバーライドのキー（例: "max-range-hours"）→ 値。未指定のキーは含めない
+func Load(path string, flagValues map[string]string) (*Config, error) {
 	cfg := DefaultConfig()
 
-	if path == "" {
-		return cfg, nil
-	}
-
-	data, err := os.ReadFile(path)
-	if err != nil {
-		if os.IsNotExist(err) {
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case os.IsNotExist(err):
 			// 設定ファイルがなければデフォルト設定を使用
-			return cfg, nil
+		case err != nil:
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		default:
+			if err := yaml.Unmarshal(data, cfg); err != nil {
+				return nil, fmt.Errorf("failed to parse config file: %w", err)
+			}
 		}
-		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	if err := yaml.Unmarshal(data, cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	// 環境変数・フラグによる上書き
+	if err := applyOverrides(cfg, os.LookupEnv, flagValues); err != nil {
+		return nil, err
+	}
+
+	// 保存クエリファイルの読み込み
+	if err := loadSavedQueriesFile(cfg); err != nil {
+		return nil, err
 	}
 
+	// 組み込みのリソース種別の定義に設定の定義を重ねる
+	mergeResourceKinds(cfg)
+
 	// デフォルト値の補完
-	if cfg.Limits.MaxRangeHours <= 0 {
+	if cfg.Mode == "" {
+		cfg.Mode = ModeReadOnly
+	}
+	if cfg.LogLevel == "" {
+		cfg.LogLevel = "info"
+	}
+	if cfg.Locale == "" {
+		cfg.Locale = LocaleEN
+	}
+	if cfg.ShutdownTimeout == 0 {
+		cfg.ShutdownTimeout = 30
+	}
+	if cfg.MaxParamsBytes == 0 {
+		cfg.MaxParamsBytes = defaultMaxParamsBytes
+	}
+	if cfg.Limits.MaxRangeHours == 0 {
 		cfg.Limits.MaxRangeHours = 72
 	}
-	if cfg.Limits.MaxLogEntries <= 0 {
+	if cfg.Limits.MaxLogEntries == 0 {
 		cfg.Limits.MaxLogEntries = 500
 	}
-	if cfg.Limits.MaxTimeSeries <= 0 {
+	if cfg.Limits.MaxTimeSeries == 0 {
 		cfg.Limits.MaxTimeSeries = 50
 	}
+	if cfg.Limits.MaxPointsPerSeries == 0 {
+		cfg.Limits.MaxPointsPerSeries = 1000
+	}
+	if cfg.Assets.MaxResults == 0 {
+		cfg.Assets.MaxResults = 200
+	}
+	if cfg.Security.MaxFindings == 0 {
+		cfg.Security.MaxFindings = 200
+	}
+	if cfg.History.MaxEntries == 0 {
+		cfg.History.MaxEntries = 50
+	}
+	if cfg.Spillover.MaxResultBytes == 0 {
+		cfg.Spillover.MaxResultBytes = 200000
+	}
+	if cfg.TokenBudget.SoftLimit == 0 {
+		cfg.TokenBudget.SoftLimit = 20000
+	}
+	if cfg.TokenBudget.MaxTokens == 0 {
+		cfg.TokenBudget.MaxTokens = 25000
+	}
+	if cfg.Export.MaxLogEntries == 0 {
+		cfg.Export.MaxLogEntries = 50000
+	}
+	if cfg.Export.MaxTimeSeries == 0 {
+		cfg.Export.MaxTimeSeries = 500
+	}
+	if cfg.Watches.MaxWatches == 0 {
+		cfg.Watches.MaxWatches = 10
+	}
+	if cfg.Watches.MinIntervalSec == 0 {
+		cfg.Watches.MinIntervalSec = 60
+	}
+	if cfg.Watches.MaxDurationMinutes == 0 {
+		cfg.Watches.MaxDurationMinutes = 1440
+	}
+	if cfg.Cache.MaxEntries == 0 {
+		cfg.Cache.MaxEntries = 100
+	}
+	if cfg.Preflight.CacheTTLSeconds == 0 {
+		cfg.Preflight.CacheTTLSeconds = 600
+	}
+	if cfg.HTTP.Path == "" {
+		cfg.HTTP.Path = "/mcp"
+	}
+
+	// 重大度は大文字小文字を問わない（warning → WARNING）
+	cfg.Logging.DefaultMinSeverity = strings.ToUpper(cfg.Logging.DefaultMinSeverity)
+
+	// デフォルトプロジェクトにエイリアスを指定した場合は実IDに展開
+	cfg.DefaultProjectID = cfg.ResolveProjectAlias(cfg.DefaultProjectID)
+
+	// 値の範囲・整合性チェック
+	if problems := cfg.Validate(); len(problems) > 0 {
+		return nil, fmt.Errorf("invalid config: %s", strings.Join(problems, "; "))
+	}
 
 	return cfg, nil
 }
 
-// IsProjectAllowed はプロジェクトIDが許可されているか確認
+// CredentialTypes は credentials_file に指定できる認証情報の種類
+var CredentialTypes = []string{"service_account", "authorized_user", "impersonated_service_account", "external_account"}
+
+// CredentialsFileType は credentials_file の認証情報の種類（JSON の "type"）を返す
+func (c *Config) CredentialsFileType() (string, error) {
+	data, err := os.ReadFile(c.CredentialsFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read credentials_file: %w", err)
+	}
+	var f struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return "", fmt.Errorf("credentials_file %s is not a JSON credentials file: %w", c.CredentialsFile, err)
+	}
+	if !slices.Contains(CredentialTypes, f.Type) {
+		return "", fmt.Errorf("credentials_file %s has unsupported type %q (supported: %s)", c.CredentialsFile, f.Type, strings.Join(CredentialTypes, ", "))
+	}
+	return f.Type, nil
+}
+
+// ResolveProjectAlias はエイリアスを実プロジェクトIDに変換する（エイリアスでなければそのまま返す）
+func (c *Config) ResolveProjectAlias(projectID string) string {
+	if id, ok := c.ProjectAliases[projectID]; ok {
+		return id
+	}
+	return projectID
+}
+
+// IsProjectAllowed はプロジェクトIDが許可リスト（パターン）に一致するか確認
+// フォルダ・組織単位の許可は祖先の解決が必要なため IsAncestorAllowed で別途判定する
 func (c *Config) IsProjectAllowed(projectID string) bool {
+	if c.IsProjectDenied(projectID) {
+		return false
+	}
+
+	// 許可ルールが何もない場合は全て許可
+	if len(c.AllowedProjectIDs) == 0 && !c.HasAncestorRules() {
+		return true
+	}
+
+	return matchAny(c.AllowedProjectIDs, projectID)
+}
+
+// IsProjectDenied はプロジェクトIDが拒否リストに一致するか確認
+func (c *Config) IsProjectDenied(projectID string) bool {
+	return matchAny(c.DeniedProjectIDs, projectID)
+}
+
+// HasAncestorRules はフォルダ・組織単位の許可ルールがあるか確認
+func (c *Config) HasAncestorRules() bool {
+	return len(c.AllowedFolders) > 0 || len(c.AllowedOrgs) > 0
+}
+
+// IsAncestorAllowed は祖先（"folders/123", "organizations/456"）のいずれかが許可されているか確認
+func (c *Config) IsAncestorAllowed(ancestors []string) bool {
+	for _, a := range ancestors {
+		for _, f := range c.AllowedFolders {
+			if a == "folders/"+strings.TrimPrefix(f, "folders/") {
+				return true
+			}
+		}
+		for _, o := range c.AllowedOrgs {
+			if a == "organizations/"+strings.TrimPrefix(o, "organizations/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchAny はvalueがいずれかのglobパターンに一致するか確認
+func matchAny(patterns []string, value string) bool {
+	for _, p := range patterns {
+		if p == value {
+			return true
+		}
+		if ok, err := path.Match(p, value); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAssetTypeAllowed はアセット種別が許可されているか確認
+func (c *Config) IsAssetTypeAllowed(assetType string) bool {
 	// 許可リストが空の場合は全て許可
-	if len(c.AllowedProjectIDs) == 0 {
+	if len(c.Assets.AllowedAssetTypes) == 0 {
 		return true
 	}
 
-	for _, allowed := range c.AllowedProjectIDs {
-		if allowed == projectID {
+	for _, allowed := range c.Assets.AllowedAssetTypes {
+		if allowed == assetType {
 			return true
 		}
 	}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix は設定を上書きする環境変数のプレフィックス
+const EnvPrefix = "GCP_OPS_MCP_"
+
+// Override は環境変数・フラグで上書きできる設定項目
+type Override struct {
+	Key   string // フラグ名（例: "max-range-hours"）。環境変数名は EnvName で導出
+	Usage string
+	apply func(cfg *Config, value string) error
+}
+
+// switches は値なしで指定できるフラグ（-quiet は -quiet=true と同じ）
+var switches = map[string]bool{"quiet": true}
+
+// IsSwitch はフラグを値なしで指定できるか返す
+func (o Override) IsSwitch() bool {
+	return switches[o.Key]
+}
+
+// EnvName は上書き用の環境変数名を返す（例: GCP_OPS_MCP_MAX_RANGE_HOURS）
+func (o Override) EnvName() string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(o.Key, "-", "_"))
+}
+
+// Overrides は上書き可能な設定項目の一覧
+// リストはカンマ区切り、マップは "key=value" のカンマ区切りで指定する
+var Overrides = []Override{
+	{"mode", "Server mode: readonly or standard (enables write tools)", setString(func(c *Config) *string { return &c.Mode })},
+	{"locale", "Language of tool descriptions and error messages: en or ja", setString(func(c *Config) *string { return &c.Locale })},
+	{"log-level", "Log level for stderr: debug, info, warn or error", setString(func(c *Config) *string { return &c.LogLevel })},
+	{"log-file", "Append logs to this file instead of stderr (for clients that treat stderr output as errors)", setString(func(c *Config) *string { return &c.LogFile })},
+	{"quiet", "Log only warnings and errors (no startup messages), overriding log-level (true/false)", setBool(func(c *Config) *bool { return &c.Quiet })},
+	{"transport", "Transport: stdio, http or unix (default: http when http-listen is set, otherwise stdio)", setString(func(c *Config) *string { return &c.Transport })},
+	{"socket", "Path of the Unix domain socket to listen on with transport unix", setString(func(c *Config) *string { return &c.Socket })},
+	{"shutdown-timeout-sec", "Seconds to wait for in-flight tool calls after SIGINT/SIGTERM", setInt(func(c *Config) *int { return &c.ShutdownTimeout })},
+	{"max-params-bytes", "Reject requests whose params exceed this many bytes with -32602 (Invalid params)", setInt(func(c *Config) *int { return &c.MaxParamsBytes })},
+	{"credentials-file", "Credentials file used instead of Application Default Credentials (e.g. an external_account config for Workload Identity Federation)", setString(func(c *Config) *string { return &c.CredentialsFile })},
+	{"grpc-endpoints", "Endpoints (host:port) of the gRPC APIs, e.g. regional or Private Service Connect endpoints (comma-separated service=host:port; services: logging, monitoring)", setMap(func(c *Config) *map[string]string { return &c.GRPC.Endpoints })},
+	{"grpc-dial-timeout-sec", "Seconds to wait for a gRPC connection to be established (0 = gRPC default of 20)", setInt(func(c *Config) *int { return &c.GRPC.DialTimeoutSec })},
+	{"grpc-keepalive-time-sec", "Seconds of inactivity after which gRPC connections send a keepalive ping (0 = no pings, minimum 10)", setInt(func(c *Config) *int { return &c.GRPC.KeepaliveTimeSec })},
+	{"grpc-keepalive-timeout-sec", "Seconds to wait for a keepalive ping response before reconnecting (0 = gRPC default of 20)", setInt(func(c *Config) *int { return &c.GRPC.KeepaliveTimeoutSec })},
+	{"allowed-projects", "Allowed project IDs or glob patterns (comma-separated)", setList(func(c *Config) *[]string { return &c.AllowedProjectIDs })},
+	{"denied-projects", "Denied project IDs or glob patterns (comma-separated)", setList(func(c *Config) *[]string { return &c.DeniedProjectIDs })},
+	{"allowed-folders", "Folder IDs whose projects are allowed (comma-separated)", setList(func(c *Config) *[]string { return &c.AllowedFolders })},
+	{"allowed-organizations", "Organization IDs whose projects are allowed (comma-separated)", setList(func(c *Config) *[]string { return &c.AllowedOrgs })},
+	{"allowed-log-views", "Log views that logs are read through exclusively (comma-separated projects/X/locations/L/buckets/B/views/V)", setList(func(c *Config) *[]string { return &c.AllowedLogViews })},
+	{"default-project", "Project ID (or alias) used when project_id is omitted", setString(func(c *Config) *string { return &c.DefaultProjectID })},
+	{"project-aliases", "Project aliases (comma-separated alias=project-id)", setMap(func(c *Config) *map[string]string { return &c.ProjectAliases })},
+	{"max-range-hours", "Maximum query time range in hours", setInt(func(c *Config) *int { return &c.Limits.MaxRangeHours })},
+	{"max-log-entries", "Maximum log entries to return", setInt(func(c *Config) *int { return &c.Limits.MaxLogEntries })},
+	{"max-time-series", "Maximum time series to return", setInt(func(c *Config) *int { return &c.Limits.MaxTimeSeries })},
+	{"max-points-per-series", "Maximum data points per time series before downsampling", setInt(func(c *Config) *int { return &c.Limits.MaxPointsPerSeries })},
+	{"allowed-asset-types", "Asset types allowed for assets.search (comma-separated)", setList(func(c *Config) *[]string { return &c.Assets.AllowedAssetTypes })},
+	{"max-asset-results", "Maximum assets to return", setInt(func(c *Config) *int { return &c.Assets.MaxResults })},
+	{"billing-export-table", "Billing export table (project.dataset.table) for ops.cost_signal", setString(func(c *Config) *string { return &c.Billing.ExportTable })},
+	{"security-enabled", "Register security.* tools (true/false)", setBool(func(c *Config) *bool { return &c.Security.Enabled })},
+	{"history-max-entries", "Number of recent tool calls kept for ops.recent_queries", setInt(func(c *Config) *int { return &c.History.MaxEntries })},
+	{"saved-queries-file", "Path to a YAML file with additional saved queries", setString(func(c *Config) *string { return &c.SavedQueriesFile })},
+	{"max-findings", "Maximum SCC findings to return", setInt(func(c *Config) *int { return &c.Security.MaxFindings })},
+	{"spillover-enabled", "Write results larger than spillover-max-bytes to a file/GCS and return a summary (true/false)", setBool(func(c *Config) *bool { return &c.Spillover.Enabled })},
+	{"spillover-max-bytes", "Result size in bytes above which results are spilled over", setInt(func(c *Config) *int { return &c.Spillover.MaxResultBytes })},
+	{"spillover-dir", "Local directory for spilled results (default: OS temp dir)", setString(func(c *Config) *string { return &c.Spillover.Dir })},
+	{"spillover-gcs-bucket", "GCS bucket for spilled results (instead of a local directory)", setString(func(c *Config) *string { return &c.Spillover.GCSBucket })},
+	{"token-budget-soft-limit", "Estimated tokens above which results carry narrowing suggestions in stats", setInt(func(c *Config) *int { return &c.TokenBudget.SoftLimit })},
+	{"token-budget-max-tokens", "Estimated tokens above which results are replaced by a summary (full data via ops.result_page)", setInt(func(c *Config) *int { return &c.TokenBudget.MaxTokens })},
+	{"token-budget-summarize", "Replace results larger than token-budget-max-tokens by a summary (true/false)", setBool(func(c *Config) *bool { return &c.TokenBudget.Summarize })},
+	{"export-gcs-bucket", "GCS bucket ops.export_result writes NDJSON/CSV to", setString(func(c *Config) *string { return &c.Export.GCSBucket })},
+	{"export-bigquery-dataset", "BigQuery dataset (project.dataset) ops.export_result creates tables in", setString(func(c *Config) *string { return &c.Export.BigQueryDataset })},
+	{"export-max-log-entries", "Maximum log entries per ops.export_result", setInt(func(c *Config) *int { return &c.Export.MaxLogEntries })},
+	{"export-max-time-series", "Maximum time series per ops.export_result", setInt(func(c *Config) *int { return &c.Export.MaxTimeSeries })},
+	{"watches-enabled", "Register ops.create_watch / ops.list_watches / ops.delete_watch (true/false)", setBool(func(c *Config) *bool { return &c.Watches.Enabled })},
+	{"watches-max", "Maximum number of active watches", setInt(func(c *Config) *int { return &c.Watches.MaxWatches })},
+	{"telemetry-otlp-endpoint", "OTLP/HTTP endpoint URL for server metrics (e.g. http://localhost:4318/v1/metrics)", setString(func(c *Config) *string { return &c.Telemetry.OTLPEndpoint })},
+	{"telemetry-prometheus-addr", "Listen address for the Prometheus /metrics endpoint (e.g. 127.0.0.1:9464)", setString(func(c *Config) *string { return &c.Telemetry.PrometheusAddr })},
+	{"cache-ttl-sec", "Seconds to reuse a read tool's result for the same arguments (0 = disabled)", setInt(func(c *Config) *int { return &c.Cache.TTLSeconds })},
+	{"cache-max-entries", "Number of cached tool results", setInt(func(c *Config) *int { return &c.Cache.MaxEntries })},
+	{"redaction-patterns", "Regular expressions masked in tool results (comma-separated; use the config file for patterns containing commas)", setList(func(c *Config) *[]string { return &c.Redaction.Patterns })},
+	{"logging-default-min-severity", "Minimum severity for logging.query when neither min_severity nor a severity filter is given (e.g. WARNING)", setString(func(c *Config) *string { return &c.Logging.DefaultMinSeverity })},
+	{"logging-exclude-filters", "LQL snippets of known noise ANDed as NOT clauses into every log query (comma-separated; use the config file for snippets containing commas)", setList(func(c *Config) *[]string { return &c.Logging.ExcludeFilters })},
+	{"profile", "Guardrail profile (a key of profiles) applied to calls; HTTP clients with their own profile use theirs", setString(func(c *Config) *string { return &c.Profile })},
+	{"http-listen", "Serve MCP over HTTP on this address (e.g. :8080) instead of stdio", setString(func(c *Config) *string { return &c.HTTP.Listen })},
+	{"http-websocket-path", "Also serve MCP over WebSocket on this path of http-listen (e.g. /ws)", setString(func(c *Config) *string { return &c.HTTP.WebSocketPath })},
+	{"preflight-enabled", "Check the IAM permissions of expensive queries with testIamPermissions before running them (true/false)", setBool(func(c *Config) *bool { return &c.Preflight.Enabled })},
+	{"preflight-cache-ttl-sec", "Seconds to reuse the IAM permission check of a project", setInt(func(c *Config) *int { return &c.Preflight.CacheTTLSeconds })},
+	{"providers-disabled", "Tool providers not to register (comma-separated, e.g. assets,gke)", setList(func(c *Config) *[]string { return &c.Providers.Disabled })},
+	{"tools-hidden", "Tools to leave out of tools/list (comma-separated, globs allowed, e.g. gke.*); they can still be called", setList(func(c *Config) *[]string { return &c.Tools.Hidden })},
+}
+
+// applyOverrides は環境変数 → フラグの順に設定を上書きする
+func applyOverrides(cfg *Config, lookupEnv func(string) (string, bool), flagValues map[string]string) error {
+	for _, o := range Overrides {
+		if v, ok := lookupEnv(o.EnvName()); ok && v != "" {
+			if err := o.apply(cfg, v); err != nil {
+				return fmt.Errorf("invalid %s: %w", o.EnvName(), err)
+			}
+		}
+	}
+	for _, o := range Overrides {
+		if v, ok := flagValues[o.Key]; ok {
+			if err := o.apply(cfg, v); err != nil {
+				return fmt.Errorf("invalid -%s: %w", o.Key, err)
+			}
+		}
+	}
+	return nil
+}
+
+func setString(field func(*Config) *string) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		*field(c) = strings.TrimSpace(v)
+		return nil
+	}
+}
+
+func setInt(field func(*Config) *int) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return fmt.Errorf("not an integer: %q", v)
+		}
+		*field(c) = n
+		return nil
+	}
+}
+
+func setBool(field func(*Config) *bool) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return fmt.Errorf("not a boolean: %q", v)
+		}
+		*field(c) = b
+		return nil
+	}
+}
+
+func setList(field func(*Config) *[]string) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		*field(c) = splitList(v)
+		return nil
+	}
+}
+
+func setMap(field func(*Config) *map[string]string) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		m := map[string]string{}
+		for _, kv := range splitList(v) {
+			key, value, ok := strings.Cut(kv, "=")
+			if !ok || key == "" || value == "" {
+				return fmt.Errorf("expected key=value, got %q", kv)
+			}
+			m[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+		*field(c) = m
+		return nil
+	}
+}
+
+// splitList はカンマ区切りの値を空要素を除いて分割する
+func splitList(v string) []string {
+	items := []string{}
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			items = append(items, s)
+		}
+	}
+	return items
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	_ "embed"
+	"fmt"
+	"sort"
+	"strings"
+
+	"cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	"gopkg.in/yaml.v3"
+)
+
+// ResourceKind はリソース種別ごとのメトリクスとログの定義（ops.golden_signals と ops.*_overview が読む）
+// 組み込みの定義は resource_kinds.yaml にあり、設定の resource_kinds で同名の種別を丸ごと置き換える・種別を追加できる
+type ResourceKind struct {
+	GoldenSignals bool           `yaml:"golden_signals,omitempty"` // ops.golden_signals / ops.generate_report の kind に指定できる
+	ResourceType  string         `yaml:"resource_type"`            // Monitoring のリソース種別（シグナルごとに上書き可）
+	NameLabel     string         `yaml:"name_label,omitempty"`     // 対象の名前を照合するフィルタキー（例: resource.labels.service_name）
+	LocationLabel string         `yaml:"location_label,omitempty"` // リージョン・ロケーションを照合するフィルタキー
+	LogFilter     string         `yaml:"log_filter,omitempty"`     // 同じリソースのログのフィルタ（{{name}} と {{project_id}} を置換する）
+	Signals       []MetricSignal `yaml:"signals"`
+}
+
+// ResourceKinds は種別名 → 定義
+type ResourceKinds map[string]ResourceKind
+
+// MetricSignal は1シグナル分のメトリクス定義
+type MetricSignal struct {
+	Name         string   `yaml:"name"`
+	MetricType   string   `yaml:"metric_type"`
+	ResourceType string   `yaml:"resource_type,omitempty"` // 空なら種別の resource_type を使う
+	Filter       string   `yaml:"filter,omitempty"`        // 追加の Monitoring フィルタ
+	Aligner      string   `yaml:"aligner"`
+	Reducer      string   `yaml:"reducer,omitempty"`
+	GroupBy      []string `yaml:"group_by,omitempty"`
+}
+
+//go:embed resource_kinds.yaml
+var builtinResourceKindsYAML []byte
+
+// BuiltinResourceKinds は組み込みのリソース種別の定義を返す（呼び出しごとに新しいマップ）
+func BuiltinResourceKinds() ResourceKinds {
+	kinds := ResourceKinds{}
+	if err := yaml.Unmarshal(builtinResourceKindsYAML, &kinds); err != nil {
+		panic(fmt.Sprintf("invalid built-in resource_kinds.yaml: %v", err))
+	}
+	return kinds
+}
+
+// mergeResourceKinds は組み込みの定義に設定の resource_kinds を重ねる（同名の種別は丸ごと置き換える）
+func mergeResourceKinds(cfg *Config) {
+	kinds := BuiltinResourceKinds()
+	for name, kind := range cfg.ResourceKinds {
+		kinds[name] = kind
+	}
+	cfg.ResourceKinds = kinds
+}
+
+// GoldenSignalKinds は ops.golden_signals に指定できる種別を返す
+func (k ResourceKinds) GoldenSignalKinds() []string {
+	kinds := []string{}
+	for name, kind := range k {
+		if kind.GoldenSignals {
+			kinds = append(kinds, name)
+		}
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// validateResourceKinds はリソース種別の定義の問題点を返す
+func (c *Config) validateResourceKinds() []string {
+	problems := []string{}
+	names := make([]string, 0, len(c.ResourceKinds))
+	for name := range c.ResourceKinds {
+		names = append(names, name)
+	}
+	sort.Strings(names) // エラーの順序を安定させる
+	for _, name := range names {
+		kind := c.ResourceKinds[name]
+		if kind.ResourceType == "" {
+			problems = append(problems, fmt.Sprintf("resource_kinds %q: resource_type is required", name))
+		}
+		if kind.GoldenSignals && (kind.NameLabel == "" || kind.LogFilter == "") {
+			problems = append(problems, fmt.Sprintf("resource_kinds %q: name_label and log_filter are required for golden_signals", name))
+		}
+		seen := map[string]bool{}
+		for i, s := range kind.Signals {
+			if s.Name == "" || s.MetricType == "" || s.Aligner == "" {
+				problems = append(problems, fmt.Sprintf("resource_kinds %q: signals[%d]: name, metric_type and aligner are required", name, i))
+				continue
+			}
+			if !knownAggregation(s.Aligner, "ALIGN_", monitoringpb.Aggregation_Aligner_value) {
+				problems = append(problems, fmt.Sprintf("resource_kinds %q: signals[%d]: unknown aligner %q", name, i, s.Aligner))
+			}
+			if s.Reducer != "" && !knownAggregation(s.Reducer, "REDUCE_", monitoringpb.Aggregation_Reducer_value) {
+				problems = append(problems, fmt.Sprintf("resource_kinds %q: signals[%d]: unknown reducer %q", name, i, s.Reducer))
+			}
+			if seen[s.Name] {
+				problems = append(problems, fmt.Sprintf("resource_kinds %q: duplicate signal %q", name, s.Name))
+			}
+			seen[s.Name] = true
+		}
+	}
+	return problems
+}
+
+// knownAggregation は aligner / reducer の名前が Monitoring API にあるか確かめる
+// （クエリ時と同じく大文字小文字を区別せず、ALIGN_ / REDUCE_ は省略できる）
+func knownAggregation(name, prefix string, values map[string]int32) bool {
+	name = strings.ToUpper(name)
+	if !strings.HasPrefix(name, prefix) {
+		name = prefix + name
+	}
+	_, ok := values[name]
+	return ok
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinResourceKinds(t *testing.T) {
+	kinds := BuiltinResourceKinds()
+	kind, ok := kinds["cloud_run"]
+	if !ok {
+		t.Fatalf("cloud_run is not in the built-in kinds: %v", kinds)
+	}
+	if kind.ResourceType != "cloud_run_revision" || len(kind.Signals) == 0 {
+		t.Errorf("cloud_run = %+v", kind)
+	}
+	if !slices.Contains(kinds.GoldenSignalKinds(), "cloud_run") {
+		t.Errorf("golden signal kinds %v do not include cloud_run", kinds.GoldenSignalKinds())
+	}
+
+	// 組み込みの定義そのものが検証を通ること
+	cfg := DefaultConfig()
+	if problems := cfg.validateResourceKinds(); len(problems) > 0 {
+		t.Errorf("built-in resource_kinds.yaml is invalid: %v", problems)
+	}
+
+	// 呼び出しごとに別のマップを返す（設定で書き換えても組み込みの定義は変わらない）
+	kinds["cloud_run"] = ResourceKind{}
+	if BuiltinResourceKinds()["cloud_run"].ResourceType == "" {
+		t.Error("modifying the returned kinds changed the built-in ones")
+	}
+}
+
+func TestLoadResourceKindsOverride(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `
+resource_kinds:
+  cloud_run:
+    resource_type: cloud_run_revision
+    signals:
+      - name: traffic
+        metric_type: run.googleapis.com/request_count
+        aligner: rate
+  memorystore:
+    golden_signals: true
+    resource_type: redis_instance
+    name_label: resource.labels.instance_id
+    log_filter: 'resource.type = "redis_instance" AND resource.labels.instance_id = "{{name}}"'
+    signals:
+      - name: saturation
+        metric_type: redis.googleapis.com/stats/memory/usage_ratio
+        aligner: ALIGN_MEAN
+        reducer: max
+`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 同名の種別は丸ごと置き換える（組み込みの errors などのシグナルは残らない）
+	run := cfg.ResourceKinds["cloud_run"]
+	if len(run.Signals) != 1 || run.Signals[0].Name != "traffic" || run.GoldenSignals {
+		t.Errorf("cloud_run was not replaced: %+v", run)
+	}
+	// 新しい名前は追加する
+	if cfg.ResourceKinds["memorystore"].ResourceType != "redis_instance" {
+		t.Errorf("memorystore was not added: %+v", cfg.ResourceKinds["memorystore"])
+	}
+	if !slices.Contains(cfg.ResourceKinds.GoldenSignalKinds(), "memorystore") {
+		t.Error("memorystore is not selectable in ops.golden_signals")
+	}
+	// 書かなかった組み込みの種別は残る
+	for name := range BuiltinResourceKinds() {
+		if _, ok := cfg.ResourceKinds[name]; !ok {
+			t.Errorf("built-in kind %s was dropped", name)
+		}
+	}
+}
+
+func TestLoadRejectsMalformedResourceKinds(t *testing.T) {
+	tests := []struct {
+		name    string
+		signal  string
+		wantErr string
+	}{
+		{"unknown aligner", "{name: traffic, metric_type: run.googleapis.com/request_count, aligner: ALIGN_FASTEST}", `signals[0]: unknown aligner "ALIGN_FASTEST"`},
+		{"unknown reducer", "{name: traffic, metric_type: run.googleapis.com/request_count, aligner: ALIGN_RATE, reducer: REDUCE_AVERAGE}", `signals[0]: unknown reducer "REDUCE_AVERAGE"`},
+		{"missing metric type", "{name: traffic, aligner: ALIGN_RATE}", "signals[0]: name, metric_type and aligner are required"},
+		{"missing aligner", "{name: traffic, metric_type: run.googleapis.com/request_count}", "signals[0]: name, metric_type and aligner are required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeConfig(t, "resource_kinds:\n  my_kind:\n    resource_type: cloud_run_revision\n    signals:\n      - "+tt.signal+"\n"), nil)
+			if err == nil || !strings.Contains(err.Error(), `resource_kinds "my_kind": `+tt.wantErr) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// 種別単位の誤り
+	_, err := Load(writeConfig(t, `
+resource_kinds:
+  no_type:
+    signals: []
+  golden_without_logs:
+    golden_signals: true
+    resource_type: gce_instance
+    signals: []
+`), nil)
+	for _, want := range []string{`"no_type": resource_type is required`, `"golden_without_logs": name_label and log_filter are required`} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %v, want it to contain %q", err, want)
+		}
+	}
+}
+
+// writeConfig は一時ディレクトリに設定ファイルを書き、そのパスを返す
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
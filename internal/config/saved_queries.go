@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SavedQuery は名前付きの保存クエリ（チームの定番フィルタ・メトリクスクエリ）
+// Filter 等に含まれる {{param}} は実行時に置換される
+type SavedQuery struct {
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description"`
+	Kind        string            `yaml:"kind"`                 // "logs" or "metrics"
+	ProjectID   string            `yaml:"project_id,omitempty"` // 省略時は実行時に指定（エイリアス可）
+	Filter      string            `yaml:"filter"`               // logs: LQLフィルタ / metrics: 追加のMonitoringフィルタ
+	MetricType  string            `yaml:"metric_type,omitempty"`
+	Resource    string            `yaml:"resource_type,omitempty"`
+	Aligner     string            `yaml:"aligner,omitempty"`
+	Reducer     string            `yaml:"reducer,omitempty"`
+	GroupBy     []string          `yaml:"group_by,omitempty"`
+	Defaults    map[string]string `yaml:"defaults,omitempty"`   // パラメータのデフォルト値
+	TimeRange   string            `yaml:"time_range,omitempty"` // 相対指定のデフォルト（例: "-1h"）
+}
+
+// 保存クエリの種別
+const (
+	SavedQueryLogs    = "logs"
+	SavedQueryMetrics = "metrics"
+)
+
+// FindSavedQuery は名前で保存クエリを探す
+func (c *Config) FindSavedQuery(name string) (SavedQuery, bool) {
+	for _, q := range c.SavedQueries {
+		if q.Name == name {
+			return q, true
+		}
+	}
+	return SavedQuery{}, false
+}
+
+// loadSavedQueriesFile は保存クエリファイル（SavedQuery のリスト）を読み込み、設定ファイルの定義に追加する
+func loadSavedQueriesFile(cfg *Config) error {
+	if cfg.SavedQueriesFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(cfg.SavedQueriesFile)
+	if err != nil {
+		return fmt.Errorf("failed to read saved queries file: %w", err)
+	}
+	var queries []SavedQuery
+	if err := yaml.Unmarshal(data, &queries); err != nil {
+		return fmt.Errorf("failed to parse saved queries file: %w", err)
+	}
+	cfg.SavedQueries = append(cfg.SavedQueries, queries...)
+	return nil
+}
+
+// validateSavedQueries は保存クエリ定義の問題点を返す
+func (c *Config) validateSavedQueries() []string {
+	problems := []string{}
+	seen := map[string]bool{}
+	for i, q := range c.SavedQueries {
+		if q.Name == "" {
+			problems = append(problems, fmt.Sprintf("saved_queries[%d]: name is required", i))
+			continue
+		}
+		if seen[q.Name] {
+			problems = append(problems, fmt.Sprintf("saved_queries: duplicate name %q", q.Name))
+		}
+		seen[q.Name] = true
+
+		switch q.Kind {
+		case SavedQueryLogs:
+			if q.Filter == "" {
+				problems = append(problems, fmt.Sprintf("saved_queries %q: filter is required for kind logs", q.Name))
+			}
+		case SavedQueryMetrics:
+			if q.MetricType == "" {
+				problems = append(problems, fmt.Sprintf("saved_queries %q: metric_type is required for kind metrics", q.Name))
+			}
+		default:
+			problems = append(problems, fmt.Sprintf("saved_queries %q: kind must be %q or %q", q.Name, SavedQueryLogs, SavedQueryMetrics))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,435 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	numericIDPattern   = regexp.MustCompile(`^[0-9]+$`)
+	labelKeyPattern    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_-]*)+$`)
+	logViewPattern     = regexp.MustCompile(`^projects/([^/]+)/locations/[^/]+/buckets/[^/]+/views/[^/]+$`)
+	exportTablePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-:.]*\.[A-Za-z0-9_]+\.[A-Za-z0-9_$-]+$`)
+	datasetPattern     = regexp.MustCompile(`^[a-z0-9][a-z0-9-:.]*\.[A-Za-z0-9_]+$`)
+	bucketPattern      = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{1,220}[a-z0-9]$`)
+)
+
+// 上限値（大きすぎる値はAPI負荷・応答サイズの面で危険）
+const (
+	maxRangeHoursLimit  = 24 * 30
+	maxLogEntriesLimit  = 10000
+	maxTimeSeriesLimit  = 500
+	maxExportEntries    = 1000000
+	maxWatches          = 100
+	maxWatchIntervalSec = 3600
+	maxWatchMinutes     = 7 * 24 * 60
+	maxPointsLimit      = 100000
+	maxResultsLimit     = 1000
+	maxResultBytes      = 50 << 20
+	maxTokenBudget      = 10000000
+	maxShutdownSec      = 600
+	maxParamsBytesLimit = 64 << 20 // メッセージ1件の上限と同じ
+	maxCacheTTLSec      = 3600
+	maxPreflightTTLSec  = 86400
+	maxDialTimeoutSec   = 300
+	minKeepaliveSec     = 10 // gRPC はこれより短い keepalive を 10 秒に切り上げる
+	maxKeepaliveSec     = 3600
+)
+
+// LogViewProject はログビューのリソース名（projects/X/locations/L/buckets/B/views/V）の
+// プロジェクトIDを返す。ログビューでなければ false
+func LogViewProject(name string) (string, bool) {
+	m := logViewPattern.FindStringSubmatch(name)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// Validate は値の範囲と整合性を検証し、問題点の一覧を返す
+func (c *Config) Validate() []string {
+	problems := []string{}
+
+	if c.Mode != ModeReadOnly && c.Mode != ModeStandard {
+		problems = append(problems, fmt.Sprintf("mode must be %q or %q (got %q)", ModeReadOnly, ModeStandard, c.Mode))
+	}
+
+	if c.Locale != LocaleEN && c.Locale != LocaleJA {
+		problems = append(problems, fmt.Sprintf("locale must be %q or %q (got %q)", LocaleEN, LocaleJA, c.Locale))
+	}
+
+	switch transport := c.EffectiveTransport(); {
+	case transport != TransportStdio && transport != TransportHTTP && transport != TransportUnix:
+		problems = append(problems, fmt.Sprintf("transport must be %q, %q or %q (got %q)", TransportStdio, TransportHTTP, TransportUnix, c.Transport))
+	case transport == TransportHTTP && c.HTTP.Listen == "":
+		problems = append(problems, "transport http requires http.listen")
+	case transport != TransportHTTP && c.HTTP.Listen != "":
+		problems = append(problems, fmt.Sprintf("http.listen is only used with transport %q (got %q)", TransportHTTP, transport))
+	case transport == TransportUnix && c.Socket == "":
+		problems = append(problems, "transport unix requires socket")
+	case transport != TransportUnix && c.Socket != "":
+		problems = append(problems, fmt.Sprintf("socket is only used with transport %q (got %q)", TransportUnix, transport))
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.LogLevel)); err != nil {
+		problems = append(problems, fmt.Sprintf("log_level must be debug, info, warn or error (got %q)", c.LogLevel))
+	}
+
+	checkRange := func(name string, v, upper int) {
+		if v <= 0 || v > upper {
+			problems = append(problems, fmt.Sprintf("%s must be between 1 and %d (got %d)", name, upper, v))
+		}
+	}
+	checkRange("shutdown_timeout_sec", c.ShutdownTimeout, maxShutdownSec)
+	checkRange("max_params_bytes", c.MaxParamsBytes, maxParamsBytesLimit)
+	checkRange("limits.max_range_hours", c.Limits.MaxRangeHours, maxRangeHoursLimit)
+	checkRange("limits.max_log_entries", c.Limits.MaxLogEntries, maxLogEntriesLimit)
+	checkRange("limits.max_time_series", c.Limits.MaxTimeSeries, maxTimeSeriesLimit)
+	checkRange("limits.max_points_per_series", c.Limits.MaxPointsPerSeries, maxPointsLimit)
+	checkRange("assets.max_results", c.Assets.MaxResults, maxResultsLimit)
+	checkRange("security.max_findings", c.Security.MaxFindings, maxResultsLimit)
+	checkRange("history.max_entries", c.History.MaxEntries, maxResultsLimit)
+	checkRange("spillover.max_result_bytes", c.Spillover.MaxResultBytes, maxResultBytes)
+	checkRange("token_budget.soft_limit", c.TokenBudget.SoftLimit, maxTokenBudget)
+	checkRange("token_budget.max_tokens", c.TokenBudget.MaxTokens, maxTokenBudget)
+	checkRange("export.max_log_entries", c.Export.MaxLogEntries, maxExportEntries)
+	checkRange("export.max_time_series", c.Export.MaxTimeSeries, maxTimeSeriesLimit)
+	checkRange("watches.max_watches", c.Watches.MaxWatches, maxWatches)
+	checkRange("watches.min_interval_sec", c.Watches.MinIntervalSec, maxWatchIntervalSec)
+	checkRange("watches.max_duration_minutes", c.Watches.MaxDurationMinutes, maxWatchMinutes)
+	checkRange("cache.max_entries", c.Cache.MaxEntries, maxResultsLimit)
+	checkRange("preflight.cache_ttl_sec", c.Preflight.CacheTTLSeconds, maxPreflightTTLSec)
+	if c.Cache.TTLSeconds < 0 || c.Cache.TTLSeconds > maxCacheTTLSec {
+		problems = append(problems, fmt.Sprintf("cache.ttl_sec must be between 0 and %d (got %d)", maxCacheTTLSec, c.Cache.TTLSeconds))
+	}
+
+	// パターンの構文チェック
+	for _, p := range append(append([]string{}, c.AllowedProjectIDs...), c.DeniedProjectIDs...) {
+		if _, err := path.Match(p, ""); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid project pattern %q: %v", p, err))
+		}
+	}
+
+	for _, f := range c.AllowedFolders {
+		if !numericIDPattern.MatchString(strings.TrimPrefix(f, "folders/")) {
+			problems = append(problems, fmt.Sprintf("allowed_folders: %q is not a folder ID", f))
+		}
+	}
+	for _, o := range c.AllowedOrgs {
+		if !numericIDPattern.MatchString(strings.TrimPrefix(o, "organizations/")) {
+			problems = append(problems, fmt.Sprintf("allowed_organizations: %q is not an organization ID", o))
+		}
+	}
+
+	for _, v := range c.AllowedLogViews {
+		project, ok := LogViewProject(v)
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("allowed_log_views: %q is not a log view (projects/X/locations/L/buckets/B/views/V)", v))
+		case !c.HasAncestorRules() && !c.IsProjectAllowed(project):
+			problems = append(problems, fmt.Sprintf("allowed_log_views: project of %q is not allowed by allowed_project_ids/denied_project_ids", v))
+		}
+	}
+
+	for i, r := range c.ResourceRules {
+		if len(r.Projects) == 0 {
+			problems = append(problems, fmt.Sprintf("resource_rules[%d].projects must not be empty", i))
+		}
+		for _, p := range r.Projects {
+			if _, err := path.Match(p, ""); err != nil {
+				problems = append(problems, fmt.Sprintf("resource_rules[%d]: invalid project pattern %q: %v", i, p, err))
+			}
+		}
+		if len(r.ResourceTypes) == 0 && len(r.DeniedLabels) == 0 {
+			problems = append(problems, fmt.Sprintf("resource_rules[%d] must set resource_types or denied_labels", i))
+		}
+		for key := range r.DeniedLabels {
+			if !labelKeyPattern.MatchString(key) {
+				problems = append(problems, fmt.Sprintf("resource_rules[%d].denied_labels: %q is not a label field (e.g. resource.labels.namespace_name)", i, key))
+			}
+		}
+	}
+
+	for alias, id := range c.ProjectAliases {
+		if alias == "" || id == "" {
+			problems = append(problems, fmt.Sprintf("project_aliases: empty alias or project ID (%q: %q)", alias, id))
+		}
+	}
+
+	// デフォルトプロジェクトは許可リストに含まれている必要がある（フォルダ・組織ルールは起動時に判定できない）
+	if c.DefaultProjectID != "" && !c.HasAncestorRules() && !c.IsProjectAllowed(c.DefaultProjectID) {
+		problems = append(problems, fmt.Sprintf("default_project_id %q is not allowed by allowed_project_ids/denied_project_ids", c.DefaultProjectID))
+	}
+
+	if c.CredentialsFile != "" {
+		if _, err := c.CredentialsFileType(); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	problems = append(problems, c.GRPC.validate()...)
+	problems = append(problems, c.Tools.validate()...)
+	problems = append(problems, c.HTTP.validate()...)
+
+	if c.Profile != "" && c.ProfileByName(c.Profile) == nil {
+		problems = append(problems, fmt.Sprintf("profile %q is not defined in profiles", c.Profile))
+	}
+	for i, client := range c.HTTP.Clients {
+		if client.Profile != "" && c.ProfileByName(client.Profile) == nil {
+			problems = append(problems, fmt.Sprintf("http.clients[%d].profile %q is not defined in profiles", i, client.Profile))
+		}
+	}
+	for name, p := range c.Profiles {
+		for _, pattern := range append(append([]string{}, p.AllowedProjectIDs...), p.DeniedProjectIDs...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				problems = append(problems, fmt.Sprintf("profiles.%s: invalid project pattern %q: %v", name, pattern, err))
+			}
+		}
+		if l := p.Limits; l.MaxRangeHours < 0 || l.MaxLogEntries < 0 || l.MaxTimeSeries < 0 || l.MaxPointsPerSeries < 0 {
+			problems = append(problems, fmt.Sprintf("profiles.%s.limits must not be negative", name))
+		}
+		for _, pattern := range p.Redaction.Patterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				problems = append(problems, fmt.Sprintf("profiles.%s.redaction.patterns: invalid pattern %q: %v", name, pattern, err))
+			}
+		}
+	}
+
+	if c.Billing.ExportTable != "" && !exportTablePattern.MatchString(c.Billing.ExportTable) {
+		problems = append(problems, fmt.Sprintf("billing.export_table %q must be in project.dataset.table format", c.Billing.ExportTable))
+	}
+
+	if c.Spillover.GCSBucket != "" && !bucketPattern.MatchString(strings.TrimPrefix(c.Spillover.GCSBucket, "gs://")) {
+		problems = append(problems, fmt.Sprintf("spillover.gcs_bucket %q is not a valid bucket name", c.Spillover.GCSBucket))
+	}
+
+	if c.Export.GCSBucket != "" && !bucketPattern.MatchString(strings.TrimPrefix(c.Export.GCSBucket, "gs://")) {
+		problems = append(problems, fmt.Sprintf("export.gcs_bucket %q is not a valid bucket name", c.Export.GCSBucket))
+	}
+	if c.Export.BigQueryDataset != "" && !datasetPattern.MatchString(c.Export.BigQueryDataset) {
+		problems = append(problems, fmt.Sprintf("export.bigquery_dataset %q must be in project.dataset format", c.Export.BigQueryDataset))
+	}
+
+	problems = append(problems, c.Notifications.validate()...)
+
+	for _, p := range c.Redaction.Patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			problems = append(problems, fmt.Sprintf("redaction.patterns: invalid pattern %q: %v", p, err))
+		}
+	}
+
+	if c.Logging.DefaultMinSeverity != "" && !slices.Contains(LogSeverities, c.Logging.DefaultMinSeverity) {
+		problems = append(problems, fmt.Sprintf("logging.default_min_severity must be one of %s (got %q)", strings.Join(LogSeverities, ", "), c.Logging.DefaultMinSeverity))
+	}
+
+	for _, f := range c.Logging.ExcludeFilters {
+		if strings.TrimSpace(f) == "" {
+			problems = append(problems, "logging.exclude_filters must not contain empty filters")
+			break
+		}
+	}
+
+	// フォルダ・組織単位の許可判定は ops プロバイダの Resource Manager クライアントで祖先を解決する
+	if c.HasAncestorRules() && slices.Contains(c.Providers.Disabled, "ops") {
+		problems = append(problems, "providers.disabled must not contain ops while allowed_folders or allowed_organizations is set")
+	}
+	// IAM の事前確認も ops プロバイダの Resource Manager クライアントを使う
+	if c.Preflight.Enabled && slices.Contains(c.Providers.Disabled, "ops") {
+		problems = append(problems, "providers.disabled must not contain ops while preflight.enabled is true")
+	}
+
+	problems = append(problems, c.validateSavedQueries()...)
+	problems = append(problems, c.validateResourceKinds()...)
+
+	return problems
+}
+
+// CheckUnknownKeys は設定ファイルに未知のキーがないか検証する
+// 通常の Load は未知のキーを無視するため、タイプミスの検出に使う
+func CheckUnknownKeys(configPath string) ([]string, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	problems := []string{}
+	var cfg Config
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		var typeErr *yaml.TypeError
+		if errors.As(err, &typeErr) {
+			problems = append(problems, typeErr.Errors...)
+		} else {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+	}
+	return problems, nil
+}
+
+// Sanitized はツール出力用に設定のコピーを返す
+// 課金エクスポートのテーブル名など内部のリソース名は設定有無のみを示す
+func (c *Config) Sanitized() *Config {
+	cp := *c
+	if cp.Billing.ExportTable != "" {
+		cp.Billing.ExportTable = "(configured)"
+	}
+	if cp.Spillover.GCSBucket != "" {
+		cp.Spillover.GCSBucket = "(configured)"
+	}
+	if cp.Export.GCSBucket != "" {
+		cp.Export.GCSBucket = "(configured)"
+	}
+	if cp.Export.BigQueryDataset != "" {
+		cp.Export.BigQueryDataset = "(configured)"
+	}
+	// Webhook の URL はトークンを含むことが多い
+	cp.Notifications.Sinks = slices.Clone(cp.Notifications.Sinks)
+	for i := range cp.Notifications.Sinks {
+		if cp.Notifications.Sinks[i].URL != "" {
+			cp.Notifications.Sinks[i].URL = "(configured)"
+		}
+	}
+	// HTTP の接続元とプロファイル（他チームのプリンシパル・許可リスト）は公開しない
+	cp.HTTP.Clients = nil
+	cp.Profiles = nil
+	return &cp
+}
+
+// YAML は正規化した実効設定をYAMLで返す
+func (c *Config) YAML() ([]byte, error) {
+	return yaml.Marshal(c)
+}
+
+// validate は通知先の設定を検証する
+func (n *Notifications) validate() []string {
+	problems := []string{}
+	names := map[string]bool{}
+	for i, s := range n.Sinks {
+		if s.Name == "" {
+			problems = append(problems, fmt.Sprintf("notifications.sinks[%d].name must not be empty", i))
+		} else if names[s.Name] {
+			problems = append(problems, fmt.Sprintf("notifications.sinks: duplicate name %q", s.Name))
+		}
+		names[s.Name] = true
+
+		if !slices.Contains(NotifySinkTypes, s.Type) {
+			problems = append(problems, fmt.Sprintf("notifications.sinks[%d].type must be one of %s (got %q)", i, strings.Join(NotifySinkTypes, ", "), s.Type))
+		}
+		if (s.URL == "") == (s.URLEnv == "") {
+			problems = append(problems, fmt.Sprintf("notifications.sinks[%d] must set exactly one of url or url_env", i))
+		}
+		if s.URL != "" {
+			if u, err := url.Parse(s.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				problems = append(problems, fmt.Sprintf("notifications.sinks[%d].url must be an http(s) URL", i))
+			}
+		}
+		for _, e := range s.Events {
+			if !slices.Contains(NotifyEvents, e) {
+				problems = append(problems, fmt.Sprintf("notifications.sinks[%d]: unknown event %q (supported: %s)", i, e, strings.Join(NotifyEvents, ", ")))
+			}
+		}
+	}
+	return problems
+}
+
+// validate は gRPC の接続設定を検証する
+func (g *GRPC) validate() []string {
+	problems := []string{}
+	for service, endpoint := range g.Endpoints {
+		if !slices.Contains(GRPCServices, service) {
+			problems = append(problems, fmt.Sprintf("grpc.endpoints: unknown service %q (supported: %s)", service, strings.Join(GRPCServices, ", ")))
+		}
+		if host, port, err := net.SplitHostPort(endpoint); err != nil || host == "" || port == "" {
+			problems = append(problems, fmt.Sprintf("grpc.endpoints.%s must be host:port (got %q)", service, endpoint))
+		}
+	}
+	if g.DialTimeoutSec < 0 || g.DialTimeoutSec > maxDialTimeoutSec {
+		problems = append(problems, fmt.Sprintf("grpc.dial_timeout_sec must be between 0 and %d (got %d)", maxDialTimeoutSec, g.DialTimeoutSec))
+	}
+	if g.KeepaliveTimeSec != 0 && (g.KeepaliveTimeSec < minKeepaliveSec || g.KeepaliveTimeSec > maxKeepaliveSec) {
+		problems = append(problems, fmt.Sprintf("grpc.keepalive_time_sec must be 0 or between %d and %d (got %d)", minKeepaliveSec, maxKeepaliveSec, g.KeepaliveTimeSec))
+	}
+	if g.KeepaliveTimeoutSec < 0 || g.KeepaliveTimeoutSec > maxDialTimeoutSec {
+		problems = append(problems, fmt.Sprintf("grpc.keepalive_timeout_sec must be between 0 and %d (got %d)", maxDialTimeoutSec, g.KeepaliveTimeoutSec))
+	}
+	if g.KeepaliveTimeoutSec > 0 && g.KeepaliveTimeSec == 0 {
+		problems = append(problems, "grpc.keepalive_timeout_sec requires grpc.keepalive_time_sec")
+	}
+	return problems
+}
+
+// validate は HTTP トランスポートの設定を検証する
+func (t *Tools) validate() []string {
+	problems := []string{}
+	for name, description := range t.Descriptions {
+		if strings.TrimSpace(description) == "" {
+			problems = append(problems, fmt.Sprintf("tools.descriptions.%s must not be empty (hide the tool with tools.hidden instead)", name))
+		}
+	}
+	for _, p := range t.Hidden {
+		if _, err := path.Match(p, ""); err != nil {
+			problems = append(problems, fmt.Sprintf("tools.hidden: invalid tool pattern %q: %v", p, err))
+		}
+	}
+	return problems
+}
+
+func (h *HTTP) validate() []string {
+	problems := []string{}
+	if !strings.HasPrefix(h.Path, "/") {
+		problems = append(problems, fmt.Sprintf("http.path must start with / (got %q)", h.Path))
+	}
+	if h.WebSocketPath != "" && (!strings.HasPrefix(h.WebSocketPath, "/") || h.WebSocketPath == h.Path) {
+		problems = append(problems, fmt.Sprintf("http.websocket_path must start with / and differ from http.path (got %q)", h.WebSocketPath))
+	}
+	if h.Listen != "" && len(h.Clients) == 0 && !h.AllowUnauthenticated {
+		problems = append(problems, "http.clients is required when http.listen is set (or set http.allow_unauthenticated for local testing)")
+	}
+
+	names := map[string]bool{}
+	for i, c := range h.Clients {
+		if c.Name == "" {
+			problems = append(problems, fmt.Sprintf("http.clients[%d].name must not be empty", i))
+		} else if names[c.Name] {
+			problems = append(problems, fmt.Sprintf("http.clients: duplicate name %q", c.Name))
+		}
+		names[c.Name] = true
+
+		if c.TokenEnv == "" && len(c.OIDCPrincipals) == 0 {
+			problems = append(problems, fmt.Sprintf("http.clients[%d] must set token_env or oidc_principals", i))
+		}
+		if len(c.OIDCPrincipals) > 0 && h.OIDCAudience == "" {
+			problems = append(problems, fmt.Sprintf("http.clients[%d].oidc_principals requires http.oidc_audience", i))
+		}
+		for _, p := range c.AllowedProjectIDs {
+			if _, err := path.Match(p, ""); err != nil {
+				problems = append(problems, fmt.Sprintf("http.clients[%d]: invalid project pattern %q: %v", i, p, err))
+			}
+		}
+		for _, p := range c.VisibleTools {
+			if _, err := path.Match(p, ""); err != nil {
+				problems = append(problems, fmt.Sprintf("http.clients[%d].visible_tools: invalid tool pattern %q: %v", i, p, err))
+			}
+		}
+		l := c.Limits
+		if l.MaxRangeHours < 0 || l.MaxLogEntries < 0 || l.MaxTimeSeries < 0 || l.MaxPointsPerSeries < 0 {
+			problems = append(problems, fmt.Sprintf("http.clients[%d].limits must not be negative", i))
+		}
+	}
+	return problems
+}
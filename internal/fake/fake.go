@@ -0,0 +1,86 @@
+// Package fake provides in-memory implementations of the Cloud Logging and
+// Cloud Monitoring API surfaces (logging.API, monitoring.API) with canned fixtures,
+// so that handlers and guardrails can be exercised without GCP.
+package fake
+
+import (
+	"strings"
+	"sync"
+
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Project is the project ID the default fixtures belong to
+const Project = "fake-project"
+
+// iter は固定のスライスを返すイテレータ（実クライアントと同じく終端で iterator.Done を返す）
+type iter[T any] struct {
+	items []T
+	err   error
+}
+
+func (it *iter[T]) Next() (T, error) {
+	var zero T
+	if it.err != nil {
+		return zero, it.err
+	}
+	if len(it.items) == 0 {
+		return zero, iterator.Done
+	}
+	item := it.items[0]
+	it.items = it.items[1:]
+	return item, nil
+}
+
+// recorder は受け取ったリクエストと注入するエラーを保持する（Logging / Monitoring 共通）
+type recorder struct {
+	mu       sync.Mutex
+	requests []any
+	errs     map[string]error
+}
+
+// FailWith makes every later call of method (e.g. "ListTimeSeries") return err; nil clears it
+func (r *recorder) FailWith(method string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.errs == nil {
+		r.errs = map[string]error{}
+	}
+	if err == nil {
+		delete(r.errs, method)
+		return
+	}
+	r.errs[method] = err
+}
+
+// Requests returns the requests received so far, oldest first
+func (r *recorder) Requests() []any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]any{}, r.requests...)
+}
+
+// record はリクエストを記録し、注入されたエラーがあれば返す
+func (r *recorder) record(method string, req any) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	return r.errs[method]
+}
+
+// projectOf は "projects/X" や "projects/X/..." から X を取り出す
+func projectOf(name string) string {
+	rest, ok := strings.CutPrefix(name, "projects/")
+	if !ok {
+		return ""
+	}
+	project, _, _ := strings.Cut(rest, "/")
+	return project
+}
+
+// notFound は実 API と同じく gRPC の NotFound を返す
+func notFound(kind, name string) error {
+	return status.Errorf(codes.NotFound, "fake: %s %q not found", kind, name)
+}
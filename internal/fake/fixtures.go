@@ -0,0 +1,175 @@
+package fake
+
+import (
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/logging/apiv2/loggingpb"
+	"cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	"google.golang.org/genproto/googleapis/api/label"
+	metricpb "google.golang.org/genproto/googleapis/api/metric"
+	monitoredrespb "google.golang.org/genproto/googleapis/api/monitoredres"
+	ltype "google.golang.org/genproto/googleapis/logging/type"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// NewLoggingWithFixtures returns a Logging holding LogEntries(time.Now())
+func NewLoggingWithFixtures() *Logging {
+	return NewLogging(LogEntries(time.Now())...)
+}
+
+// NewMonitoringWithFixtures returns a Monitoring holding the default fixtures:
+// request counts of the Cloud Run service "checkout" (2xx and 5xx) for the last 30 minutes,
+// their metric descriptors, a group of two GCE instances and a Cloud Run service with an availability SLO.
+func NewMonitoringWithFixtures() *Monitoring {
+	f := NewMonitoring()
+	now := time.Now()
+	f.AddTimeSeries(Project, requestCountSeries(now, "2xx", 120)...)
+	f.AddTimeSeries(Project, requestCountSeries(now, "5xx", 3)...)
+	f.AddMetricDescriptors(Project,
+		&metricpb.MetricDescriptor{
+			Name:        fmt.Sprintf("projects/%s/metricDescriptors/run.googleapis.com/request_count", Project),
+			Type:        "run.googleapis.com/request_count",
+			MetricKind:  metricpb.MetricDescriptor_DELTA,
+			ValueType:   metricpb.MetricDescriptor_INT64,
+			Unit:        "1",
+			DisplayName: "Request Count",
+			Description: "Number of requests reaching the revision.",
+			Labels: []*label.LabelDescriptor{
+				{Key: "response_code_class", ValueType: label.LabelDescriptor_STRING},
+			},
+		},
+		&metricpb.MetricDescriptor{
+			Name:        fmt.Sprintf("projects/%s/metricDescriptors/compute.googleapis.com/instance/cpu/utilization", Project),
+			Type:        "compute.googleapis.com/instance/cpu/utilization",
+			MetricKind:  metricpb.MetricDescriptor_GAUGE,
+			ValueType:   metricpb.MetricDescriptor_DOUBLE,
+			Unit:        "10^2.%",
+			DisplayName: "CPU utilization",
+		},
+	)
+	f.AddGroup(&monitoringpb.Group{
+		Name:        fmt.Sprintf("projects/%s/groups/1001", Project),
+		DisplayName: "backend",
+		Filter:      `resource.metadata.tag."role"="backend"`,
+	},
+		gceInstance("backend-1"),
+		gceInstance("backend-2"),
+	)
+	f.AddServices(&monitoringpb.Service{
+		Name:        fmt.Sprintf("projects/%s/services/checkout", Project),
+		DisplayName: "checkout",
+		Identifier: &monitoringpb.Service_CloudRun_{CloudRun: &monitoringpb.Service_CloudRun{
+			ServiceName: "checkout",
+			Location:    "asia-northeast1",
+		}},
+	})
+	f.AddServiceLevelObjectives(fmt.Sprintf("projects/%s/services/checkout", Project), &monitoringpb.ServiceLevelObjective{
+		Name:        fmt.Sprintf("projects/%s/services/checkout/serviceLevelObjectives/availability", Project),
+		DisplayName: "99.9% availability",
+		Goal:        0.999,
+		Period:      &monitoringpb.ServiceLevelObjective_RollingPeriod{RollingPeriod: durationpb.New(28 * 24 * time.Hour)},
+	})
+	return f
+}
+
+// LogEntries returns the default log entries of Project, relative to now:
+// three identical connection errors, a JSON payment error, a warning and a request log
+// of the Cloud Run service "checkout".
+func LogEntries(now time.Time) []*loggingpb.LogEntry {
+	entries := []*loggingpb.LogEntry{}
+	for i, ago := range []time.Duration{2 * time.Minute, 5 * time.Minute, 9 * time.Minute} {
+		entries = append(entries, &loggingpb.LogEntry{
+			LogName:   fmt.Sprintf("projects/%s/logs/run.googleapis.com%%2Fstderr", Project),
+			Resource:  cloudRunRevision(),
+			Timestamp: timestamppb.New(now.Add(-ago)),
+			Severity:  ltype.LogSeverity_ERROR,
+			InsertId:  fmt.Sprintf("conn-%d", i),
+			Payload:   &loggingpb.LogEntry_TextPayload{TextPayload: "dial tcp 10.0.0.5:5432: connect: connection refused"},
+		})
+	}
+	entries = append(entries,
+		&loggingpb.LogEntry{
+			LogName:   fmt.Sprintf("projects/%s/logs/run.googleapis.com%%2Fstdout", Project),
+			Resource:  cloudRunRevision(),
+			Timestamp: timestamppb.New(now.Add(-4 * time.Minute)),
+			Severity:  ltype.LogSeverity_ERROR,
+			InsertId:  "payment-0",
+			Trace:     fmt.Sprintf("projects/%s/traces/4bf92f3577b34da6a3ce929d0e0e4736", Project),
+			Payload: &loggingpb.LogEntry_JsonPayload{JsonPayload: mustStruct(map[string]any{
+				"message": "payment declined",
+				"order":   map[string]any{"id": "o-123", "amount": 4200},
+			})},
+		},
+		&loggingpb.LogEntry{
+			LogName:   fmt.Sprintf("projects/%s/logs/run.googleapis.com%%2Fstdout", Project),
+			Resource:  cloudRunRevision(),
+			Timestamp: timestamppb.New(now.Add(-7 * time.Minute)),
+			Severity:  ltype.LogSeverity_WARNING,
+			InsertId:  "slow-0",
+			Payload:   &loggingpb.LogEntry_TextPayload{TextPayload: "slow query: 2.3s"},
+		},
+		&loggingpb.LogEntry{
+			LogName:   fmt.Sprintf("projects/%s/logs/run.googleapis.com%%2Frequests", Project),
+			Resource:  cloudRunRevision(),
+			Timestamp: timestamppb.New(now.Add(-1 * time.Minute)),
+			Severity:  ltype.LogSeverity_INFO,
+			InsertId:  "req-0",
+			Payload:   &loggingpb.LogEntry_TextPayload{TextPayload: "POST 200 /api/checkout"},
+		},
+	)
+	return entries
+}
+
+func cloudRunRevision() *monitoredrespb.MonitoredResource {
+	return &monitoredrespb.MonitoredResource{
+		Type: "cloud_run_revision",
+		Labels: map[string]string{
+			"project_id":    Project,
+			"service_name":  "checkout",
+			"revision_name": "checkout-00042-abc",
+			"location":      "asia-northeast1",
+		},
+	}
+}
+
+func gceInstance(name string) *monitoredrespb.MonitoredResource {
+	return &monitoredrespb.MonitoredResource{
+		Type:   "gce_instance",
+		Labels: map[string]string{"project_id": Project, "instance_id": name, "zone": "asia-northeast1-a"},
+	}
+}
+
+// requestCountSeries は1分ごとの30ポイントのリクエスト数の系列（新しい順）
+func requestCountSeries(now time.Time, codeClass string, perMinute int64) []*monitoringpb.TimeSeries {
+	end := now.Truncate(time.Minute)
+	points := []*monitoringpb.Point{}
+	for i := 0; i < 30; i++ {
+		t := end.Add(-time.Duration(i) * time.Minute)
+		points = append(points, &monitoringpb.Point{
+			Interval: &monitoringpb.TimeInterval{StartTime: timestamppb.New(t.Add(-time.Minute)), EndTime: timestamppb.New(t)},
+			Value:    &monitoringpb.TypedValue{Value: &monitoringpb.TypedValue_Int64Value{Int64Value: perMinute + int64(i%3)}},
+		})
+	}
+	return []*monitoringpb.TimeSeries{{
+		Metric: &metricpb.Metric{
+			Type:   "run.googleapis.com/request_count",
+			Labels: map[string]string{"response_code_class": codeClass},
+		},
+		Resource:   cloudRunRevision(),
+		MetricKind: metricpb.MetricDescriptor_DELTA,
+		ValueType:  metricpb.MetricDescriptor_INT64,
+		Unit:       "1",
+		Points:     points,
+	}}
+}
+
+func mustStruct(m map[string]any) *structpb.Struct {
+	s, err := structpb.NewStruct(m)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
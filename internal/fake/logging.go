@@ -0,0 +1,139 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"cloud.google.com/go/logging/apiv2/loggingpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+)
+
+// Logging is an in-memory logging.API.
+// ListLogEntries returns the entries of the requested projects newest first (oldest first for "timestamp asc");
+// the filter is not evaluated (tests assert on Requests instead).
+type Logging struct {
+	recorder
+
+	mu      sync.Mutex
+	entries []*loggingpb.LogEntry
+	metrics map[string]*loggingpb.LogMetric // "projects/X/metrics/NAME" → metric
+}
+
+var _ logging.API = (*Logging)(nil)
+
+// NewLogging returns a fake holding entries (see NewLoggingWithFixtures for the default fixtures)
+func NewLogging(entries ...*loggingpb.LogEntry) *Logging {
+	return &Logging{entries: entries, metrics: map[string]*loggingpb.LogMetric{}}
+}
+
+// AddEntries adds log entries
+func (f *Logging) AddEntries(entries ...*loggingpb.LogEntry) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.entries = append(f.entries, entries...)
+}
+
+func (f *Logging) ListLogEntries(ctx context.Context, req *loggingpb.ListLogEntriesRequest) logging.Iterator[*loggingpb.LogEntry] {
+	if err := f.record("ListLogEntries", req); err != nil {
+		return &iter[*loggingpb.LogEntry]{err: err}
+	}
+	projects := []string{}
+	for _, name := range req.GetResourceNames() {
+		projects = append(projects, projectOf(name))
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	entries := []*loggingpb.LogEntry{}
+	for _, e := range f.entries {
+		if slices.Contains(projects, projectOf(e.GetLogName())) {
+			entries = append(entries, e)
+		}
+	}
+	// OrderBy "timestamp asc" 以外は新しい順に返す
+	slices.SortStableFunc(entries, func(a, b *loggingpb.LogEntry) int {
+		if req.GetOrderBy() == "timestamp asc" {
+			return a.GetTimestamp().AsTime().Compare(b.GetTimestamp().AsTime())
+		}
+		return b.GetTimestamp().AsTime().Compare(a.GetTimestamp().AsTime())
+	})
+	return &iter[*loggingpb.LogEntry]{items: entries}
+}
+
+// ListLogs returns the distinct log names of the entries of the requested projects (the parent when resource names are empty)
+func (f *Logging) ListLogs(ctx context.Context, req *loggingpb.ListLogsRequest) logging.Iterator[string] {
+	if err := f.record("ListLogs", req); err != nil {
+		return &iter[string]{err: err}
+	}
+	projects := []string{projectOf(req.GetParent())}
+	for _, name := range req.GetResourceNames() {
+		projects = append(projects, projectOf(name))
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	names := []string{}
+	for _, e := range f.entries {
+		if slices.Contains(projects, projectOf(e.GetLogName())) && !slices.Contains(names, e.GetLogName()) {
+			names = append(names, e.GetLogName())
+		}
+	}
+	slices.Sort(names)
+	return &iter[string]{items: names}
+}
+
+func (f *Logging) CreateLogMetric(ctx context.Context, req *loggingpb.CreateLogMetricRequest) (*loggingpb.LogMetric, error) {
+	if err := f.record("CreateLogMetric", req); err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("%s/metrics/%s", req.GetParent(), req.GetMetric().GetName())
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.metrics[name]; ok {
+		return nil, status.Errorf(codes.AlreadyExists, "fake: metric %q already exists", name)
+	}
+	f.metrics[name] = req.GetMetric()
+	return req.GetMetric(), nil
+}
+
+// LogMetric returns a metric created with CreateLogMetric ("projects/X/metrics/NAME")
+func (f *Logging) LogMetric(name string) (*loggingpb.LogMetric, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	m, ok := f.metrics[name]
+	if !ok {
+		return nil, notFound("metric", name)
+	}
+	return m, nil
+}
+
+// WriteLogEntries stores the entries (filling log name and resource from the request) so that later queries return them
+func (f *Logging) WriteLogEntries(ctx context.Context, req *loggingpb.WriteLogEntriesRequest) (*loggingpb.WriteLogEntriesResponse, error) {
+	if err := f.record("WriteLogEntries", req); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, e := range req.GetEntries() {
+		e = proto.Clone(e).(*loggingpb.LogEntry)
+		if e.GetLogName() == "" {
+			e.LogName = req.GetLogName()
+		}
+		if e.GetResource() == nil {
+			e.Resource = req.GetResource()
+		}
+		f.entries = append(f.entries, e)
+	}
+	return &loggingpb.WriteLogEntriesResponse{}, nil
+}
+
+func (f *Logging) Close() error {
+	return nil
+}
@@ -0,0 +1,294 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	metricpb "google.golang.org/genproto/googleapis/api/metric"
+	monitoredrespb "google.golang.org/genproto/googleapis/api/monitoredres"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+)
+
+// Monitoring is an in-memory monitoring.API.
+// Time series and metric descriptors are matched on project and metric type
+// (metric.type = "X" / starts_with("X") in the filter); other filter terms are not evaluated.
+type Monitoring struct {
+	recorder
+
+	mu          sync.Mutex
+	series      map[string][]*monitoringpb.TimeSeries // "project/metricType" → series
+	descriptors map[string][]*metricpb.MetricDescriptor
+	groups      map[string][]*monitoringpb.Group
+	members     map[string][]*monitoredrespb.MonitoredResource // group name → members
+	services    map[string][]*monitoringpb.Service
+	slos        map[string][]*monitoringpb.ServiceLevelObjective // service name → SLOs
+	snoozes     map[string]*monitoringpb.Snooze                  // snooze name → snooze
+	nextSnooze  int
+	policies    map[string]*monitoringpb.AlertPolicy // policy name → policy
+}
+
+var _ monitoring.API = (*Monitoring)(nil)
+
+// NewMonitoring returns an empty fake (see NewMonitoringWithFixtures for the default fixtures)
+func NewMonitoring() *Monitoring {
+	return &Monitoring{
+		series:      map[string][]*monitoringpb.TimeSeries{},
+		descriptors: map[string][]*metricpb.MetricDescriptor{},
+		groups:      map[string][]*monitoringpb.Group{},
+		members:     map[string][]*monitoredrespb.MonitoredResource{},
+		services:    map[string][]*monitoringpb.Service{},
+		slos:        map[string][]*monitoringpb.ServiceLevelObjective{},
+		snoozes:     map[string]*monitoringpb.Snooze{},
+		policies:    map[string]*monitoringpb.AlertPolicy{},
+	}
+}
+
+// AddTimeSeries adds time series of a project (their metric type is taken from ts.Metric.Type)
+func (f *Monitoring) AddTimeSeries(project string, series ...*monitoringpb.TimeSeries) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, ts := range series {
+		key := project + "/" + ts.GetMetric().GetType()
+		f.series[key] = append(f.series[key], ts)
+	}
+}
+
+// AddMetricDescriptors adds metric descriptors of a project
+func (f *Monitoring) AddMetricDescriptors(project string, descriptors ...*metricpb.MetricDescriptor) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.descriptors[project] = append(f.descriptors[project], descriptors...)
+}
+
+// AddGroup adds a group (named "projects/X/groups/ID") with its members
+func (f *Monitoring) AddGroup(group *monitoringpb.Group, members ...*monitoredrespb.MonitoredResource) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	project := projectOf(group.GetName())
+	f.groups[project] = append(f.groups[project], group)
+	f.members[group.GetName()] = append(f.members[group.GetName()], members...)
+}
+
+// AddServices adds Service Monitoring services (named "projects/X/services/ID")
+func (f *Monitoring) AddServices(services ...*monitoringpb.Service) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, s := range services {
+		project := projectOf(s.GetName())
+		f.services[project] = append(f.services[project], s)
+	}
+}
+
+// AddServiceLevelObjectives adds SLOs (named "projects/X/services/ID/serviceLevelObjectives/SLO") of a service
+func (f *Monitoring) AddServiceLevelObjectives(service string, slos ...*monitoringpb.ServiceLevelObjective) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.slos[service] = append(f.slos[service], slos...)
+}
+
+// AddAlertPolicies adds alert policies (named "projects/X/alertPolicies/ID")
+func (f *Monitoring) AddAlertPolicies(policies ...*monitoringpb.AlertPolicy) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, p := range policies {
+		f.policies[p.GetName()] = p
+	}
+}
+
+var (
+	metricTypeEquals     = regexp.MustCompile(`metric\.type\s*=\s*"([^"]+)"`)
+	metricTypeStartsWith = regexp.MustCompile(`metric\.type\s*=\s*starts_with\("([^"]+)"\)`)
+)
+
+func (f *Monitoring) ListTimeSeries(ctx context.Context, req *monitoringpb.ListTimeSeriesRequest) monitoring.Iterator[*monitoringpb.TimeSeries] {
+	if err := f.record("ListTimeSeries", req); err != nil {
+		return &iter[*monitoringpb.TimeSeries]{err: err}
+	}
+	m := metricTypeEquals.FindStringSubmatch(req.GetFilter())
+	if m == nil {
+		return &iter[*monitoringpb.TimeSeries]{}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	series := []*monitoringpb.TimeSeries{}
+	for _, ts := range f.series[projectOf(req.GetName())+"/"+m[1]] {
+		// HEADERS ビューではポイントを返さない
+		if req.GetView() == monitoringpb.ListTimeSeriesRequest_HEADERS {
+			ts = proto.Clone(ts).(*monitoringpb.TimeSeries)
+			ts.Points = nil
+		}
+		series = append(series, ts)
+	}
+	return &iter[*monitoringpb.TimeSeries]{items: series}
+}
+
+func (f *Monitoring) ListMetricDescriptors(ctx context.Context, req *monitoringpb.ListMetricDescriptorsRequest) monitoring.Iterator[*metricpb.MetricDescriptor] {
+	if err := f.record("ListMetricDescriptors", req); err != nil {
+		return &iter[*metricpb.MetricDescriptor]{err: err}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	descriptors := []*metricpb.MetricDescriptor{}
+	for _, d := range f.descriptors[projectOf(req.GetName())] {
+		if m := metricTypeStartsWith.FindStringSubmatch(req.GetFilter()); m != nil && !strings.HasPrefix(d.GetType(), m[1]) {
+			continue
+		}
+		if m := metricTypeEquals.FindStringSubmatch(req.GetFilter()); m != nil && d.GetType() != m[1] {
+			continue
+		}
+		descriptors = append(descriptors, d)
+	}
+	return &iter[*metricpb.MetricDescriptor]{items: descriptors}
+}
+
+func (f *Monitoring) GetMetricDescriptor(ctx context.Context, req *monitoringpb.GetMetricDescriptorRequest) (*metricpb.MetricDescriptor, error) {
+	if err := f.record("GetMetricDescriptor", req); err != nil {
+		return nil, err
+	}
+	_, metricType, _ := strings.Cut(req.GetName(), "/metricDescriptors/")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, d := range f.descriptors[projectOf(req.GetName())] {
+		if d.GetType() == metricType {
+			return d, nil
+		}
+	}
+	return nil, notFound("metric descriptor", req.GetName())
+}
+
+func (f *Monitoring) ListGroups(ctx context.Context, req *monitoringpb.ListGroupsRequest) monitoring.Iterator[*monitoringpb.Group] {
+	if err := f.record("ListGroups", req); err != nil {
+		return &iter[*monitoringpb.Group]{err: err}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &iter[*monitoringpb.Group]{items: append([]*monitoringpb.Group{}, f.groups[projectOf(req.GetName())]...)}
+}
+
+func (f *Monitoring) ListGroupMembers(ctx context.Context, req *monitoringpb.ListGroupMembersRequest) monitoring.Iterator[*monitoredrespb.MonitoredResource] {
+	if err := f.record("ListGroupMembers", req); err != nil {
+		return &iter[*monitoredrespb.MonitoredResource]{err: err}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	members, ok := f.members[req.GetName()]
+	if !ok {
+		return &iter[*monitoredrespb.MonitoredResource]{err: notFound("group", req.GetName())}
+	}
+	return &iter[*monitoredrespb.MonitoredResource]{items: append([]*monitoredrespb.MonitoredResource{}, members...)}
+}
+
+func (f *Monitoring) ListServices(ctx context.Context, req *monitoringpb.ListServicesRequest) monitoring.Iterator[*monitoringpb.Service] {
+	if err := f.record("ListServices", req); err != nil {
+		return &iter[*monitoringpb.Service]{err: err}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &iter[*monitoringpb.Service]{items: append([]*monitoringpb.Service{}, f.services[projectOf(req.GetParent())]...)}
+}
+
+func (f *Monitoring) ListServiceLevelObjectives(ctx context.Context, req *monitoringpb.ListServiceLevelObjectivesRequest) monitoring.Iterator[*monitoringpb.ServiceLevelObjective] {
+	if err := f.record("ListServiceLevelObjectives", req); err != nil {
+		return &iter[*monitoringpb.ServiceLevelObjective]{err: err}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &iter[*monitoringpb.ServiceLevelObjective]{items: append([]*monitoringpb.ServiceLevelObjective{}, f.slos[req.GetParent()]...)}
+}
+
+func (f *Monitoring) ListSnoozes(ctx context.Context, req *monitoringpb.ListSnoozesRequest) monitoring.Iterator[*monitoringpb.Snooze] {
+	if err := f.record("ListSnoozes", req); err != nil {
+		return &iter[*monitoringpb.Snooze]{err: err}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	snoozes := []*monitoringpb.Snooze{}
+	for name, s := range f.snoozes {
+		if projectOf(name) == projectOf(req.GetParent()) {
+			snoozes = append(snoozes, s)
+		}
+	}
+	return &iter[*monitoringpb.Snooze]{items: snoozes}
+}
+
+func (f *Monitoring) GetSnooze(ctx context.Context, req *monitoringpb.GetSnoozeRequest) (*monitoringpb.Snooze, error) {
+	if err := f.record("GetSnooze", req); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s, ok := f.snoozes[req.GetName()]
+	if !ok {
+		return nil, notFound("snooze", req.GetName())
+	}
+	return proto.Clone(s).(*monitoringpb.Snooze), nil
+}
+
+func (f *Monitoring) CreateSnooze(ctx context.Context, req *monitoringpb.CreateSnoozeRequest) (*monitoringpb.Snooze, error) {
+	if err := f.record("CreateSnooze", req); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextSnooze++
+	s := proto.Clone(req.GetSnooze()).(*monitoringpb.Snooze)
+	s.Name = fmt.Sprintf("%s/snoozes/%d", req.GetParent(), f.nextSnooze)
+	f.snoozes[s.Name] = s
+	return proto.Clone(s).(*monitoringpb.Snooze), nil
+}
+
+// UpdateSnooze replaces the snooze (the update mask is not evaluated)
+func (f *Monitoring) UpdateSnooze(ctx context.Context, req *monitoringpb.UpdateSnoozeRequest) (*monitoringpb.Snooze, error) {
+	if err := f.record("UpdateSnooze", req); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	name := req.GetSnooze().GetName()
+	if _, ok := f.snoozes[name]; !ok {
+		return nil, notFound("snooze", name)
+	}
+	f.snoozes[name] = proto.Clone(req.GetSnooze()).(*monitoringpb.Snooze)
+	return proto.Clone(f.snoozes[name]).(*monitoringpb.Snooze), nil
+}
+
+func (f *Monitoring) GetAlertPolicy(ctx context.Context, req *monitoringpb.GetAlertPolicyRequest) (*monitoringpb.AlertPolicy, error) {
+	if err := f.record("GetAlertPolicy", req); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p, ok := f.policies[req.GetName()]
+	if !ok {
+		return nil, notFound("alert policy", req.GetName())
+	}
+	return proto.Clone(p).(*monitoringpb.AlertPolicy), nil
+}
+
+// CreateTimeSeries stores the written series so that later ListTimeSeries calls return them
+func (f *Monitoring) CreateTimeSeries(ctx context.Context, req *monitoringpb.CreateTimeSeriesRequest) error {
+	if err := f.record("CreateTimeSeries", req); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	project := projectOf(req.GetName())
+	for _, ts := range req.GetTimeSeries() {
+		key := project + "/" + ts.GetMetric().GetType()
+		f.series[key] = append(f.series[key], proto.Clone(ts).(*monitoringpb.TimeSeries))
+	}
+	return nil
+}
+
+func (f *Monitoring) Close() error {
+	return nil
+}
@@ -0,0 +1,346 @@
+package format
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// 出力形式
+const (
+	JSON          = "json"
+	Compact       = "compact"
+	CSV           = "csv"
+	MarkdownTable = "markdown_table"
+)
+
+// Formats はサポートする出力形式の一覧
+var Formats = []string{JSON, Compact, CSV, MarkdownTable}
+
+// Render はツール結果を指定形式の文字列に変換する
+// csv / markdown_table では結果内の「オブジェクトの配列」を表に展開し、それ以外の値は前置きのメタ情報として出力する
+func Render(result any, format string) (string, error) {
+	switch format {
+	case "", JSON:
+		b, err := json.MarshalIndent(result, "", "  ")
+		return string(b), err
+	case Compact:
+		b, err := json.Marshal(result)
+		return string(b), err
+	case CSV, MarkdownTable:
+	default:
+		return "", fmt.Errorf("unsupported output_format: %s (supported: %s)", format, strings.Join(Formats, ", "))
+	}
+
+	b, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+	root, err := decodeOrdered(b)
+	if err != nil {
+		return "", err
+	}
+
+	meta := []field{}
+	tables := []table{}
+	collect(root, "", &meta, &tables)
+
+	var out strings.Builder
+	if format == CSV {
+		for _, m := range meta {
+			fmt.Fprintf(&out, "# %s: %s\n", m.key, m.value)
+		}
+		for _, t := range tables {
+			if t.name != "" {
+				fmt.Fprintf(&out, "# %s\n", t.name)
+			}
+			w := csv.NewWriter(&out)
+			_ = w.Write(t.columns)
+			for _, row := range t.rows {
+				_ = w.Write(t.values(row))
+			}
+			w.Flush()
+		}
+		return out.String(), nil
+	}
+
+	for _, m := range meta {
+		fmt.Fprintf(&out, "- **%s**: %s\n", m.key, escapeCell(m.value))
+	}
+	for _, t := range tables {
+		if out.Len() > 0 {
+			out.WriteString("\n")
+		}
+		if t.name != "" {
+			fmt.Fprintf(&out, "### %s\n\n", t.name)
+		}
+		if len(t.rows) == 0 {
+			out.WriteString("(no rows)\n")
+			continue
+		}
+		out.WriteString("| " + strings.Join(escapeAll(t.columns), " | ") + " |\n")
+		out.WriteString("|" + strings.Repeat(" --- |", len(t.columns)) + "\n")
+		for _, row := range t.rows {
+			out.WriteString("| " + strings.Join(escapeAll(t.values(row)), " | ") + " |\n")
+		}
+	}
+	return out.String(), nil
+}
+
+type field struct {
+	key   string
+	value string
+}
+
+type table struct {
+	name    string
+	columns []string
+	rows    []map[string]string
+}
+
+func (t table) values(row map[string]string) []string {
+	vals := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		vals[i] = row[c]
+	}
+	return vals
+}
+
+// collect は値を走査し、オブジェクトの配列を表、それ以外をメタ情報として振り分ける
+func collect(v any, path string, meta *[]field, tables *[]table) {
+	switch val := v.(type) {
+	case *object:
+		for _, k := range val.keys {
+			collect(val.vals[k], join(path, k), meta, tables)
+		}
+	case []any:
+		if isObjectArray(val) {
+			*tables = append(*tables, buildTable(path, val))
+			return
+		}
+		*meta = append(*meta, field{key: path, value: scalarList(val)})
+	default:
+		if path == "" {
+			path = "value"
+		}
+		*meta = append(*meta, field{key: path, value: scalar(val)})
+	}
+}
+
+// buildTable はオブジェクトの配列を行に展開する
+// 要素が更にオブジェクトの配列（例: 時系列の points）を持つ場合は子要素ごとに1行とし、親の列を繰り返す
+func buildTable(name string, items []any) table {
+	t := table{name: name}
+	seen := map[string]bool{}
+	addRow := func(row map[string]string, order []string) {
+		for _, c := range order {
+			if !seen[c] {
+				seen[c] = true
+				t.columns = append(t.columns, c)
+			}
+		}
+		t.rows = append(t.rows, row)
+	}
+
+	for _, item := range items {
+		obj, ok := item.(*object)
+		if !ok {
+			addRow(map[string]string{"value": scalar(item)}, []string{"value"})
+			continue
+		}
+
+		base := map[string]string{}
+		order := []string{}
+		var childKey string
+		var children []any
+		flatten(obj, "", base, &order, func(key string, arr []any) bool {
+			if childKey == "" && len(arr) > 0 {
+				childKey, children = key, arr
+				return true
+			}
+			return false
+		})
+
+		if childKey == "" {
+			addRow(base, order)
+			continue
+		}
+		for _, child := range children {
+			row := make(map[string]string, len(base))
+			for k, v := range base {
+				row[k] = v
+			}
+			childOrder := append([]string{}, order...)
+			if co, ok := child.(*object); ok {
+				flatten(co, childKey, row, &childOrder, nil)
+			} else {
+				row[childKey] = scalar(child)
+				childOrder = append(childOrder, childKey)
+			}
+			addRow(row, childOrder)
+		}
+	}
+	return t
+}
+
+// flatten はネストしたオブジェクトをドット区切りのキーに展開する
+// expand がオブジェクトの配列を受け取った場合は展開対象として列に含めない
+func flatten(obj *object, prefix string, out map[string]string, order *[]string, expand func(key string, arr []any) bool) {
+	for _, k := range obj.keys {
+		key := join(prefix, k)
+		switch val := obj.vals[k].(type) {
+		case *object:
+			flatten(val, key, out, order, expand)
+			continue
+		case []any:
+			if isObjectArray(val) && expand != nil && expand(key, val) {
+				continue
+			}
+			if isObjectArray(val) {
+				b, _ := json.Marshal(toPlain(val))
+				out[key] = string(b)
+			} else {
+				out[key] = scalarList(val)
+			}
+		default:
+			out[key] = scalar(val)
+		}
+		*order = append(*order, key)
+	}
+}
+
+func isObjectArray(arr []any) bool {
+	if len(arr) == 0 {
+		return false
+	}
+	for _, v := range arr {
+		if _, ok := v.(*object); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func scalar(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case json.Number:
+		return val.String()
+	case bool:
+		if val {
+			return "true"
+		}
+		return "false"
+	default:
+		b, _ := json.Marshal(toPlain(val))
+		return string(b)
+	}
+}
+
+func scalarList(arr []any) string {
+	parts := make([]string, len(arr))
+	for i, v := range arr {
+		parts[i] = scalar(v)
+	}
+	return strings.Join(parts, ";")
+}
+
+func join(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func escapeCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\r", "")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+func escapeAll(vals []string) []string {
+	out := make([]string, len(vals))
+	for i, v := range vals {
+		out[i] = escapeCell(v)
+	}
+	return out
+}
+
+// object はキーの順序を保持するJSONオブジェクト（構造体のフィールド順で列を並べるため）
+type object struct {
+	keys []string
+	vals map[string]any
+}
+
+// decodeOrdered はキー順を保ったままJSONをデコードする
+func decodeOrdered(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return decodeValue(dec)
+}
+
+func decodeValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			obj := &object{vals: map[string]any{}}
+			for dec.More() {
+				kt, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, _ := kt.(string)
+				v, err := decodeValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				obj.keys = append(obj.keys, key)
+				obj.vals[key] = v
+			}
+			_, err := dec.Token() // '}'
+			return obj, err
+		case '[':
+			arr := []any{}
+			for dec.More() {
+				v, err := decodeValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, v)
+			}
+			_, err := dec.Token() // ']'
+			return arr, err
+		}
+	}
+	return tok, nil
+}
+
+// toPlain は順序付きオブジェクトを通常のマップに戻す（JSON文字列化用）
+func toPlain(v any) any {
+	switch val := v.(type) {
+	case *object:
+		m := make(map[string]any, len(val.keys))
+		for _, k := range val.keys {
+			m[k] = toPlain(val.vals[k])
+		}
+		return m
+	case []any:
+		out := make([]any, len(val))
+		for i, e := range val {
+			out[i] = toPlain(e)
+		}
+		return out
+	default:
+		return val
+	}
+}
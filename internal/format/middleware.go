@@ -0,0 +1,49 @@
+package format
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+)
+
+// Middleware は全ツールに output_format 引数を追加し、指定された形式で結果を返す
+func Middleware() mcp.Middleware {
+	return func(tool *mcp.Tool, next mcp.ToolHandler) mcp.ToolHandler {
+		if tool.InputSchema.Properties == nil {
+			tool.InputSchema.Properties = map[string]mcp.Property{}
+		}
+		tool.InputSchema.Properties["output_format"] = mcp.Property{
+			Type:        "string",
+			Description: "Output format: json (default), compact (single-line JSON), csv or markdown_table (tabular parts of the result as tables; fewer tokens)",
+			Enum:        Formats,
+			Default:     JSON,
+		}
+
+		return func(ctx context.Context, args json.RawMessage) (any, error) {
+			var opts struct {
+				OutputFormat string `json:"output_format"`
+			}
+			if len(args) > 0 {
+				_ = json.Unmarshal(args, &opts)
+			}
+
+			result, err := next(ctx, args)
+			if err != nil || opts.OutputFormat == "" || opts.OutputFormat == JSON {
+				return result, err
+			}
+			// 描画済みのコンテンツ（チャート画像など）はそのまま返す
+			switch result.(type) {
+			case mcp.Content, mcp.Structured:
+				return result, nil
+			}
+
+			text, err := Render(result, opts.OutputFormat)
+			if err != nil {
+				return nil, err
+			}
+			// structuredContent には元の結果を返す（出力スキーマは出力形式によらない）
+			return mcp.Structured{Content: mcp.Text(text), Result: result}, nil
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package gke
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	container "google.golang.org/api/container/v1"
+	"google.golang.org/api/option"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+)
+
+// DescribeClusterParams are the parameters for gke.describe_cluster
+type DescribeClusterParams struct {
+	ProjectID       string `json:"project_id"`
+	Location        string `json:"location"` // Region or zone (e.g., "asia-northeast1")
+	Cluster         string `json:"cluster"`
+	OperationsLimit int    `json:"operations_limit"` // Recent operations to return
+}
+
+// DescribeClusterResult is the result of gke.describe_cluster
+type DescribeClusterResult struct {
+	QueryMeta  QueryMeta   `json:"query_meta"`
+	Cluster    Cluster     `json:"cluster"`
+	NodePools  []NodePool  `json:"node_pools"`
+	Operations []Operation `json:"recent_operations"`
+	Errors     []string    `json:"errors,omitempty"`
+}
+
+type QueryMeta struct {
+	ProjectID string `json:"project_id"`
+	Location  string `json:"location"`
+	Cluster   string `json:"cluster"`
+}
+
+type Cluster struct {
+	Name           string   `json:"name"`
+	Location       string   `json:"location"`
+	Status         string   `json:"status"`
+	StatusMessage  string   `json:"status_message,omitempty"`
+	MasterVersion  string   `json:"master_version"`
+	NodeVersion    string   `json:"node_version,omitempty"`
+	ReleaseChannel string   `json:"release_channel,omitempty"`
+	Autopilot      bool     `json:"autopilot"`
+	NodeCount      int64    `json:"node_count"`
+	Locations      []string `json:"locations,omitempty"`
+	CreateTime     string   `json:"create_time,omitempty"`
+}
+
+type NodePool struct {
+	Name             string       `json:"name"`
+	Status           string       `json:"status"`
+	StatusMessage    string       `json:"status_message,omitempty"`
+	Version          string       `json:"version"`
+	MachineType      string       `json:"machine_type,omitempty"`
+	InitialNodeCount int64        `json:"initial_node_count"`
+	Autoscaling      *Autoscaling `json:"autoscaling,omitempty"`
+	AutoUpgrade      bool         `json:"auto_upgrade"`
+	AutoRepair       bool         `json:"auto_repair"`
+	Locations        []string     `json:"locations,omitempty"`
+}
+
+type Autoscaling struct {
+	Enabled           bool  `json:"enabled"`
+	MinNodeCount      int64 `json:"min_node_count,omitempty"`
+	MaxNodeCount      int64 `json:"max_node_count,omitempty"`
+	TotalMinNodeCount int64 `json:"total_min_node_count,omitempty"`
+	TotalMaxNodeCount int64 `json:"total_max_node_count,omitempty"`
+}
+
+type Operation struct {
+	Name          string `json:"name"`
+	OperationType string `json:"operation_type"`
+	Status        string `json:"status"`
+	Target        string `json:"target"`
+	StartTime     string `json:"start_time"`
+	EndTime       string `json:"end_time,omitempty"`
+	StatusMessage string `json:"status_message,omitempty"`
+}
+
+// Client is the GKE client (Container API, plus Cloud Logging / Monitoring for events and container metrics)
+type Client struct {
+	service    *container.Service
+	logging    *logging.Client
+	monitoring *monitoring.Client
+}
+
+// NewClient creates a new GKE client that reads logs and metrics with the given clients
+func NewClient(ctx context.Context, loggingClient *logging.Client, monitoringClient *monitoring.Client, opts ...option.ClientOption) (*Client, error) {
+	service, err := container.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create container client: %w", err)
+	}
+	return &Client{service: service, logging: loggingClient, monitoring: monitoringClient}, nil
+}
+
+// DescribeCluster returns cluster version, node pools and recent operations
+func (c *Client) DescribeCluster(ctx context.Context, params DescribeClusterParams) (*DescribeClusterResult, error) {
+	opsLimit := params.OperationsLimit
+	if opsLimit <= 0 {
+		opsLimit = 10
+	}
+	if opsLimit > 50 {
+		opsLimit = 50
+	}
+
+	name := fmt.Sprintf("projects/%s/locations/%s/clusters/%s", params.ProjectID, params.Location, params.Cluster)
+	cl, err := c.service.Projects.Locations.Clusters.Get(name).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	cluster := Cluster{
+		Name:          cl.Name,
+		Location:      cl.Location,
+		Status:        cl.Status,
+		StatusMessage: cl.StatusMessage,
+		MasterVersion: cl.CurrentMasterVersion,
+		NodeVersion:   cl.CurrentNodeVersion,
+		NodeCount:     cl.CurrentNodeCount,
+		Locations:     cl.Locations,
+		CreateTime:    cl.CreateTime,
+	}
+	if cl.ReleaseChannel != nil {
+		cluster.ReleaseChannel = cl.ReleaseChannel.Channel
+	}
+	if cl.Autopilot != nil {
+		cluster.Autopilot = cl.Autopilot.Enabled
+	}
+
+	nodePools := make([]NodePool, 0, len(cl.NodePools))
+	for _, np := range cl.NodePools {
+		pool := NodePool{
+			Name:             np.Name,
+			Status:           np.Status,
+			StatusMessage:    np.StatusMessage,
+			Version:          np.Version,
+			InitialNodeCount: np.InitialNodeCount,
+			Locations:        np.Locations,
+		}
+		if np.Config != nil {
+			pool.MachineType = np.Config.MachineType
+		}
+		if np.Autoscaling != nil {
+			pool.Autoscaling = &Autoscaling{
+				Enabled:           np.Autoscaling.Enabled,
+				MinNodeCount:      np.Autoscaling.MinNodeCount,
+				MaxNodeCount:      np.Autoscaling.MaxNodeCount,
+				TotalMinNodeCount: np.Autoscaling.TotalMinNodeCount,
+				TotalMaxNodeCount: np.Autoscaling.TotalMaxNodeCount,
+			}
+		}
+		if np.Management != nil {
+			pool.AutoUpgrade = np.Management.AutoUpgrade
+			pool.AutoRepair = np.Management.AutoRepair
+		}
+		nodePools = append(nodePools, pool)
+	}
+
+	result := &DescribeClusterResult{
+		QueryMeta: QueryMeta{
+			ProjectID: params.ProjectID,
+			Location:  params.Location,
+			Cluster:   params.Cluster,
+		},
+		Cluster:    cluster,
+		NodePools:  nodePools,
+		Operations: []Operation{},
+	}
+
+	// 操作履歴の取得に失敗してもクラスタ情報は返す
+	operations, err := c.recentOperations(ctx, params, opsLimit)
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	} else {
+		result.Operations = operations
+	}
+
+	return result, nil
+}
+
+// recentOperations は対象クラスタ（とそのノードプール）の操作を新しい順に返す
+func (c *Client) recentOperations(ctx context.Context, params DescribeClusterParams, limit int) ([]Operation, error) {
+	parent := fmt.Sprintf("projects/%s/locations/%s", params.ProjectID, params.Location)
+	resp, err := c.service.Projects.Locations.Operations.List(parent).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list operations: %w", err)
+	}
+
+	marker := "/clusters/" + params.Cluster
+	operations := []Operation{}
+	for _, op := range resp.Operations {
+		// targetLink は .../clusters/NAME または .../clusters/NAME/nodePools/POOL
+		i := strings.Index(op.TargetLink, marker)
+		if i < 0 {
+			continue
+		}
+		if rest := op.TargetLink[i+len(marker):]; rest != "" && !strings.HasPrefix(rest, "/") {
+			continue
+		}
+		target := op.TargetLink[i+1:]
+		operations = append(operations, Operation{
+			Name:          op.Name,
+			OperationType: op.OperationType,
+			Status:        op.Status,
+			Target:        target,
+			StartTime:     op.StartTime,
+			EndTime:       op.EndTime,
+			StatusMessage: op.StatusMessage,
+		})
+	}
+
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].StartTime > operations[j].StartTime
+	})
+	if len(operations) > limit {
+		operations = operations[:limit]
+	}
+	return operations, nil
+}
+
+// DescribeClusterHandler returns a handler for the gke.describe_cluster tool
+func (c *Client) DescribeClusterHandler() func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params DescribeClusterParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.Location == "" {
+			return nil, fmt.Errorf("location is required")
+		}
+		if params.Cluster == "" {
+			return nil, fmt.Errorf("cluster is required")
+		}
+
+		return c.DescribeCluster(ctx, params)
+	}
+}
@@ -0,0 +1,468 @@
+package gke
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/timerange"
+)
+
+// クラッシュの分析に使うメトリクス（k8s_container）
+const (
+	restartMetric     = "kubernetes.io/container/restart_count"
+	memoryUsedMetric  = "kubernetes.io/container/memory/used_bytes"
+	memoryLimitMetric = "kubernetes.io/container/memory/limit_bytes"
+)
+
+const (
+	// crashAlignmentSec はクラッシュ時刻とメモリを突き合わせる粒度
+	crashAlignmentSec = 60
+	// oomUtilization はクラッシュ直前のメモリ使用量がこの割合（limit 比）以上なら OOMKill とみなす
+	oomUtilization = 0.95
+	// tightUtilization はピークがこの割合以上なら余裕が少ないとみなす
+	tightUtilization = 0.8
+	// maxCrashEvents は結果に含めるイベントの数
+	maxCrashEvents = 20
+	// maxErrorLogs はコンテナごとに含めるエラーログの数
+	maxErrorLogs = 3
+)
+
+// crashEventReasons はクラッシュに関係するイベントの理由
+var crashEventReasons = []string{"BackOff", "Unhealthy", "Killing", "Failed", "OOMKilling"}
+
+// CrashReportParams are the parameters for gke.crash_report
+type CrashReportParams struct {
+	ProjectID string            `json:"project_id"`
+	Cluster   string            `json:"cluster,omitempty"`
+	Location  string            `json:"location,omitempty"`
+	Namespace string            `json:"namespace"`
+	Workload  string            `json:"workload"` // Top-level controller name (Deployment, StatefulSet, ...)
+	TimeRange logging.TimeRange `json:"time_range"`
+}
+
+// CrashReportResult is the result of gke.crash_report
+type CrashReportResult struct {
+	QueryMeta  CrashQueryMeta     `json:"query_meta"`
+	Containers []ContainerCrashes `json:"containers"` // Most restarts first
+	Events     []Event            `json:"events"`     // Crash-related events of the workload, newest first
+	Stats      CrashStats         `json:"stats"`
+	Errors     map[string]string  `json:"errors,omitempty"` // source -> error
+}
+
+type CrashQueryMeta struct {
+	ProjectID string `json:"project_id"`
+	Cluster   string `json:"cluster,omitempty"`
+	Namespace string `json:"namespace"`
+	Workload  string `json:"workload"`
+	Start     string `json:"start"`
+	End       string `json:"end"`
+}
+
+// ContainerCrashes はコンテナ（名前単位、Pod をまたいで集計）のクラッシュ
+type ContainerCrashes struct {
+	Container       string          `json:"container"`
+	Restarts        int             `json:"restarts"`
+	SuspectedOOM    int             `json:"suspected_oom_kills"` // Restarts with memory at >= 95% of the limit right before
+	RestartsByPod   map[string]int  `json:"restarts_by_pod,omitempty"`
+	LastCrash       string          `json:"last_crash,omitempty"`
+	LastCrashPod    string          `json:"last_crash_pod,omitempty"`
+	LastTermination *Termination    `json:"last_termination,omitempty"`
+	BackOffEvents   int             `json:"backoff_events"`
+	ProbeFailures   int             `json:"probe_failures"` // Unhealthy events (liveness/readiness/startup)
+	Memory          *MemoryHeadroom `json:"memory,omitempty"`
+	RecentErrors    []string        `json:"recent_errors,omitempty"` // Latest ERROR logs of the container
+}
+
+// Termination は直近のクラッシュの推定理由と根拠
+type Termination struct {
+	Reason   string `json:"reason"` // OOMKilled, LivenessProbeFailed, CrashLoopBackOff or Restarted
+	Evidence string `json:"evidence"`
+}
+
+// MemoryHeadroom はメモリの limit に対する余裕
+type MemoryHeadroom struct {
+	LimitBytes             float64  `json:"limit_bytes"` // 0 = no limit
+	PeakBytes              float64  `json:"peak_bytes"`  // Non-evictable memory, max over pods
+	PeakUtilization        *float64 `json:"peak_utilization,omitempty"`
+	AtLastCrashBytes       *float64 `json:"at_last_crash_bytes,omitempty"`
+	AtLastCrashUtilization *float64 `json:"at_last_crash_utilization,omitempty"`
+	HeadroomBytes          *float64 `json:"headroom_bytes,omitempty"` // limit - peak
+	Assessment             string   `json:"assessment"`
+}
+
+type CrashStats struct {
+	TotalRestarts      int `json:"total_restarts"`
+	CrashingContainers int `json:"crashing_containers"`
+	SuspectedOOMKills  int `json:"suspected_oom_kills"`
+}
+
+// crashPoint は1回の再起動（アライメント期間内の restart_count の増分）
+type crashPoint struct {
+	pod   string
+	time  time.Time
+	count int
+}
+
+// containerKey はコンテナ名と Pod 名
+type containerKey struct {
+	container string
+	pod       string
+}
+
+// CrashReport finds restarts of a workload's containers and explains them with events,
+// memory usage at the time of each crash and the containers' latest error logs
+func (c *Client) CrashReport(ctx context.Context, params CrashReportParams, maxSeries int) (*CrashReportResult, error) {
+	start, end, err := timerange.Parse(params.TimeRange.Start, params.TimeRange.End)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse time range: %w", err)
+	}
+	tr := monitoring.TimeRange{Start: start.UTC().Format(time.RFC3339), End: end.UTC().Format(time.RFC3339)}
+
+	result := &CrashReportResult{
+		QueryMeta: CrashQueryMeta{
+			ProjectID: params.ProjectID,
+			Cluster:   params.Cluster,
+			Namespace: params.Namespace,
+			Workload:  params.Workload,
+			Start:     tr.Start,
+			End:       tr.End,
+		},
+		Containers: []ContainerCrashes{},
+		Events:     []Event{},
+	}
+	errs := map[string]string{}
+
+	filter := fmt.Sprintf(`metadata.system_labels.top_level_controller_name = "%s" AND resource.labels.namespace_name = "%s"`, params.Workload, params.Namespace)
+	if params.Cluster != "" {
+		filter += fmt.Sprintf(` AND resource.labels.cluster_name = "%s"`, params.Cluster)
+	}
+	if params.Location != "" {
+		filter += fmt.Sprintf(` AND resource.labels.location = "%s"`, params.Location)
+	}
+	query := func(metricType, aligner, extraFilter string) (*monitoring.QueryTimeSeriesResult, error) {
+		f := filter
+		if extraFilter != "" {
+			f += " AND " + extraFilter
+		}
+		return c.monitoring.QueryTimeSeries(ctx, monitoring.QueryTimeSeriesParams{
+			ProjectID:          params.ProjectID,
+			MetricType:         metricType,
+			ResourceType:       "k8s_container",
+			Filter:             f,
+			AlignmentPeriodSec: crashAlignmentSec,
+			PerSeriesAligner:   aligner,
+			TimeRange:          tr,
+			MaxSeries:          maxSeries,
+		})
+	}
+
+	// 再起動（コンテナ → Pod ごとの回数と時刻）
+	containers := map[string]*ContainerCrashes{}
+	crashes := map[string][]crashPoint{}
+	get := func(name string) *ContainerCrashes {
+		if cc, ok := containers[name]; ok {
+			return cc
+		}
+		cc := &ContainerCrashes{Container: name, RestartsByPod: map[string]int{}}
+		containers[name] = cc
+		return cc
+	}
+	restarts, err := query(restartMetric, "ALIGN_DELTA", "")
+	if err != nil {
+		errs["restart_count"] = err.Error()
+	} else {
+		for _, ts := range restarts.Series {
+			key := seriesKey(ts)
+			cc := get(key.container)
+			for _, p := range ts.Points {
+				if p.Value <= 0 {
+					continue
+				}
+				t, err := time.Parse(time.RFC3339, p.Time)
+				if err != nil {
+					continue
+				}
+				n := int(p.Value)
+				cc.Restarts += n
+				cc.RestartsByPod[key.pod] += n
+				crashes[key.container] = append(crashes[key.container], crashPoint{pod: key.pod, time: t, count: n})
+			}
+		}
+	}
+
+	// メモリ（使用量は non-evictable = OOM killer が見る量、limit は Pod ごとの最新値）
+	used := map[containerKey][]monitoring.DataPoint{}
+	limits := map[containerKey]float64{}
+	memErrs := []string{}
+	if r, err := query(memoryUsedMetric, "ALIGN_MAX", `metric.labels.memory_type = "non-evictable"`); err != nil {
+		memErrs = append(memErrs, err.Error())
+	} else {
+		for _, ts := range r.Series {
+			used[seriesKey(ts)] = ts.Points
+		}
+	}
+	if r, err := query(memoryLimitMetric, "ALIGN_MAX", ""); err != nil {
+		memErrs = append(memErrs, err.Error())
+	} else {
+		for _, ts := range r.Series {
+			if len(ts.Points) > 0 {
+				limits[seriesKey(ts)] = ts.Points[0].Value
+			}
+		}
+	}
+	if len(memErrs) > 0 {
+		errs["memory"] = strings.Join(memErrs, "; ")
+	}
+
+	// イベント（対象のフィールドパス spec.containers{NAME} でコンテナに対応付ける）
+	events, err := c.QueryEvents(ctx, QueryEventsParams{
+		ProjectID: params.ProjectID,
+		Cluster:   params.Cluster,
+		Location:  params.Location,
+		Namespace: params.Namespace,
+		Name:      params.Workload + "-*",
+		Reasons:   crashEventReasons,
+		TimeRange: logging.TimeRange(tr),
+		Limit:     200,
+	})
+	lastEvent := map[string]Event{}
+	if err != nil {
+		errs["events"] = err.Error()
+	} else {
+		for _, ev := range events.Events {
+			name := eventContainer(ev)
+			if name == "" {
+				continue
+			}
+			n := max(ev.Count, 1)
+			cc := get(name)
+			switch ev.Reason {
+			case "BackOff":
+				cc.BackOffEvents += n
+			case "Unhealthy":
+				cc.ProbeFailures += n
+			}
+			if _, ok := lastEvent[name]; !ok {
+				lastEvent[name] = ev // 新しい順
+			}
+		}
+		result.Events = events.Events
+		if len(result.Events) > maxCrashEvents {
+			result.Events = result.Events[:maxCrashEvents]
+		}
+	}
+
+	for name, cc := range containers {
+		points := crashes[name]
+		sort.Slice(points, func(i, j int) bool { return points[i].time.After(points[j].time) })
+
+		// クラッシュごとに直前のメモリ使用量を limit と比べる
+		for _, p := range points {
+			key := containerKey{container: name, pod: p.pod}
+			if v := memoryAt(used[key], p.time); v != nil && limits[key] > 0 && *v/limits[key] >= oomUtilization {
+				cc.SuspectedOOM += p.count
+			}
+		}
+		if len(points) > 0 {
+			cc.LastCrash = points[0].time.Format(time.RFC3339)
+			cc.LastCrashPod = points[0].pod
+		}
+		cc.Memory = headroom(name, points, used, limits)
+
+		ev, hasEvent := lastEvent[name]
+		switch {
+		case cc.Memory != nil && cc.Memory.AtLastCrashUtilization != nil && *cc.Memory.AtLastCrashUtilization >= oomUtilization:
+			cc.LastTermination = &Termination{Reason: "OOMKilled", Evidence: fmt.Sprintf("memory at %.0f%% of the limit right before the crash", *cc.Memory.AtLastCrashUtilization*100)}
+		case hasEvent && ev.Reason == "Unhealthy":
+			cc.LastTermination = &Termination{Reason: "LivenessProbeFailed", Evidence: ev.Message}
+		case cc.BackOffEvents > 0:
+			cc.LastTermination = &Termination{Reason: "CrashLoopBackOff", Evidence: "the container keeps exiting and is backing off; see recent_errors for why it exits"}
+		case cc.Restarts > 0:
+			cc.LastTermination = &Termination{Reason: "Restarted", Evidence: "restart_count increased without crash-related events"}
+		}
+
+		if cc.Restarts > 0 || cc.BackOffEvents > 0 {
+			logs, err := c.containerErrors(ctx, params, name, tr)
+			if err != nil {
+				errs["logs"] = err.Error()
+			} else {
+				cc.RecentErrors = logs
+			}
+		}
+
+		result.Stats.TotalRestarts += cc.Restarts
+		result.Stats.SuspectedOOMKills += cc.SuspectedOOM
+		if cc.Restarts > 0 {
+			result.Stats.CrashingContainers++
+		}
+		result.Containers = append(result.Containers, *cc)
+	}
+	sort.Slice(result.Containers, func(i, j int) bool {
+		a, b := result.Containers[i], result.Containers[j]
+		if a.Restarts != b.Restarts {
+			return a.Restarts > b.Restarts
+		}
+		return a.Container < b.Container
+	})
+
+	if len(errs) > 0 {
+		result.Errors = errs
+	}
+	return result, nil
+}
+
+// seriesKey は k8s_container の系列のコンテナ名と Pod 名
+func seriesKey(ts monitoring.TimeSeries) containerKey {
+	return containerKey{container: ts.Resource.Labels["container_name"], pod: ts.Resource.Labels["pod_name"]}
+}
+
+// eventContainer はイベントの対象のコンテナ名（fieldPath が spec.containers{NAME} のとき）
+func eventContainer(ev Event) string {
+	rest, ok := strings.CutPrefix(ev.Object.FieldPath, "spec.containers{")
+	if !ok {
+		return ""
+	}
+	name, _, _ := strings.Cut(rest, "}")
+	return name
+}
+
+// memoryAt はクラッシュ時刻 t の直前（2アライメント期間以内）のメモリ使用量の最大値を返す
+func memoryAt(points []monitoring.DataPoint, t time.Time) *float64 {
+	var found *float64
+	from := t.Add(-2 * crashAlignmentSec * time.Second)
+	for _, p := range points {
+		pt, err := time.Parse(time.RFC3339, p.Time)
+		if err != nil || pt.Before(from) || pt.After(t) {
+			continue
+		}
+		if found == nil || p.Value > *found {
+			v := p.Value
+			found = &v
+		}
+	}
+	return found
+}
+
+// headroom はコンテナのメモリの limit に対する余裕を評価する（メモリのデータがなければ nil）
+func headroom(name string, crashes []crashPoint, used map[containerKey][]monitoring.DataPoint, limits map[containerKey]float64) *MemoryHeadroom {
+	h := &MemoryHeadroom{}
+	seen := false
+	for key, points := range used {
+		if key.container != name {
+			continue
+		}
+		seen = true
+		for _, p := range points {
+			h.PeakBytes = max(h.PeakBytes, p.Value)
+		}
+	}
+	for key, limit := range limits {
+		if key.container == name {
+			seen = true
+			h.LimitBytes = max(h.LimitBytes, limit)
+		}
+	}
+	if !seen {
+		return nil
+	}
+	if len(crashes) > 0 {
+		key := containerKey{container: name, pod: crashes[0].pod}
+		if v := memoryAt(used[key], crashes[0].time); v != nil {
+			h.AtLastCrashBytes = v
+			if limit := limits[key]; limit > 0 {
+				u := *v / limit
+				h.AtLastCrashUtilization = &u
+			}
+		}
+	}
+
+	if h.LimitBytes <= 0 {
+		h.Assessment = "no memory limit: the container is only OOM-killed under node memory pressure"
+		return h
+	}
+	peak := h.PeakBytes / h.LimitBytes
+	room := h.LimitBytes - h.PeakBytes
+	h.PeakUtilization, h.HeadroomBytes = &peak, &room
+	switch {
+	case h.AtLastCrashUtilization != nil && *h.AtLastCrashUtilization >= oomUtilization:
+		h.Assessment = fmt.Sprintf("memory was at %.0f%% of the limit right before the last crash: likely OOMKilled, raise the limit or look for a leak", *h.AtLastCrashUtilization*100)
+	case peak >= oomUtilization:
+		h.Assessment = fmt.Sprintf("peak at %.0f%% of the limit: OOM kills are likely", peak*100)
+	case peak >= tightUtilization:
+		h.Assessment = fmt.Sprintf("peak at %.0f%% of the limit: little headroom", peak*100)
+	default:
+		h.Assessment = fmt.Sprintf("peak at %.0f%% of the limit: crashes are unlikely to be memory-related", peak*100)
+	}
+	return h
+}
+
+// containerErrors はコンテナの直近の ERROR 以上のログのメッセージを返す
+func (c *Client) containerErrors(ctx context.Context, params CrashReportParams, container string, tr monitoring.TimeRange) ([]string, error) {
+	filter := fmt.Sprintf(`resource.type = "k8s_container" AND resource.labels.namespace_name = "%s" AND resource.labels.container_name = "%s" AND resource.labels.pod_name : "%s-"`,
+		params.Namespace, container, params.Workload)
+	if params.Cluster != "" {
+		filter += fmt.Sprintf(` AND resource.labels.cluster_name = "%s"`, params.Cluster)
+	}
+	result, err := c.logging.Query(ctx, logging.QueryParams{
+		ProjectID:   params.ProjectID,
+		Filter:      filter,
+		TimeRange:   logging.TimeRange(tr),
+		Limit:       maxErrorLogs,
+		MinSeverity: "ERROR",
+	})
+	if err != nil {
+		return nil, err
+	}
+	messages := []string{}
+	for _, e := range result.Entries {
+		message := e.TextPayload
+		if message == "" {
+			message = payloadString(e.JSONPayload, "message")
+		}
+		if message == "" {
+			data, _ := json.Marshal(e.JSONPayload)
+			message = string(data)
+		}
+		messages = append(messages, fmt.Sprintf("%s %s", e.Timestamp, message))
+	}
+	return messages, nil
+}
+
+// CrashReportHandlerWithGuardrail returns a handler with guardrail validation
+func (c *Client) CrashReportHandlerWithGuardrail(v Validator) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params CrashReportParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.Namespace == "" {
+			return nil, fmt.Errorf("namespace is required")
+		}
+		if params.Workload == "" {
+			return nil, fmt.Errorf("workload is required")
+		}
+
+		// 省略時は直近6時間（クラッシュループは時間をかけて積み上がるため）
+		// 既定値は共通のガードレールを通らないのでここで検証する
+		if params.TimeRange.Start == "" {
+			params.TimeRange.Start = "-6h"
+			start, end, err := timerange.Parse(params.TimeRange.Start, params.TimeRange.End)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse time range: %w", err)
+			}
+			if err := v.ValidateTimeRange(ctx, start, end); err != nil {
+				return nil, err
+			}
+		}
+
+		// ガードレール: 系列数制限（Pod × コンテナの系列を読む）
+		return c.CrashReport(ctx, params, v.ClampTimeSeriesLimit(ctx, 50))
+	}
+}
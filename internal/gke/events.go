@@ -0,0 +1,245 @@
+package gke
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+)
+
+// eventsLogFilter は GKE が Cloud Logging に書き出す Kubernetes イベントのログ
+// （リソースは対象に応じて k8s_cluster / k8s_pod / k8s_node など）
+const eventsLogFilter = `log_id("events")`
+
+// EventTypes are the Kubernetes event types
+var EventTypes = []string{"Normal", "Warning"}
+
+// QueryEventsParams are the parameters for gke.query_events
+type QueryEventsParams struct {
+	ProjectID string            `json:"project_id"`
+	Cluster   string            `json:"cluster,omitempty"`
+	Location  string            `json:"location,omitempty"`  // Cluster region or zone
+	Namespace string            `json:"namespace,omitempty"` // Namespace of the involved object
+	Kind      string            `json:"kind,omitempty"`      // Involved object kind (e.g. "Pod", "Deployment", "Node")
+	Name      string            `json:"name,omitempty"`      // Involved object name; a trailing "*" matches a prefix (e.g. "checkout-*")
+	Reasons   []string          `json:"reasons,omitempty"`   // e.g. "BackOff", "OOMKilling", "FailedScheduling"
+	Type      string            `json:"type,omitempty"`      // "Normal" or "Warning"
+	Filter    string            `json:"filter,omitempty"`    // Additional LQL ANDed to the generated filter
+	TimeRange logging.TimeRange `json:"time_range"`
+	Limit     int               `json:"limit"`
+}
+
+// QueryEventsResult is the result of gke.query_events
+type QueryEventsResult struct {
+	QueryMeta EventsQueryMeta `json:"query_meta"`
+	Events    []Event         `json:"events"` // Newest first
+	Stats     EventsStats     `json:"stats"`
+}
+
+type EventsQueryMeta struct {
+	ProjectID string `json:"project_id"`
+	Start     string `json:"start"`
+	End       string `json:"end"`
+	Filter    string `json:"filter"` // The generated LQL (reusable with logging.query)
+	Limit     int    `json:"limit"`
+}
+
+// Event は Kubernetes のイベント1件（ログエントリから正規化したもの）
+type Event struct {
+	Time      string    `json:"time"`
+	Type      string    `json:"type"`
+	Reason    string    `json:"reason"`
+	Message   string    `json:"message"`
+	Object    ObjectRef `json:"object"`
+	Count     int       `json:"count,omitempty"`      // Times the event was seen (aggregated by Kubernetes)
+	FirstSeen string    `json:"first_seen,omitempty"` // First occurrence of an aggregated event
+	LastSeen  string    `json:"last_seen,omitempty"`
+	Source    string    `json:"source,omitempty"` // Reporting component (and host)
+	Cluster   string    `json:"cluster,omitempty"`
+	Location  string    `json:"location,omitempty"`
+}
+
+// ObjectRef はイベントの対象（involvedObject）
+type ObjectRef struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	FieldPath string `json:"field_path,omitempty"` // e.g. "spec.containers{app}" for container events
+}
+
+type EventsStats struct {
+	ReturnedCount int            `json:"returned_count"`
+	WarningCount  int            `json:"warning_count"`
+	ByReason      map[string]int `json:"by_reason"` // Among the returned events
+	Truncated     bool           `json:"truncated"` // More events matched than limit
+}
+
+// eventsFilter は構造化パラメータから LQL を組み立てる
+func eventsFilter(params QueryEventsParams) string {
+	clauses := []string{eventsLogFilter}
+	if params.Cluster != "" {
+		clauses = append(clauses, fmt.Sprintf("resource.labels.cluster_name = %q", params.Cluster))
+	}
+	if params.Location != "" {
+		clauses = append(clauses, fmt.Sprintf("resource.labels.location = %q", params.Location))
+	}
+	if params.Namespace != "" {
+		clauses = append(clauses, fmt.Sprintf("jsonPayload.involvedObject.namespace = %q", params.Namespace))
+	}
+	if params.Kind != "" {
+		clauses = append(clauses, fmt.Sprintf("jsonPayload.involvedObject.kind = %q", params.Kind))
+	}
+	if params.Name != "" {
+		if prefix, ok := strings.CutSuffix(params.Name, "*"); ok {
+			// LQL の文字列中のバックスラッシュは二重にする
+			pattern := "^" + strings.ReplaceAll(regexp.QuoteMeta(prefix), `\`, `\\`)
+			clauses = append(clauses, fmt.Sprintf(`jsonPayload.involvedObject.name =~ "%s"`, pattern))
+		} else {
+			clauses = append(clauses, fmt.Sprintf("jsonPayload.involvedObject.name = %q", params.Name))
+		}
+	}
+	if len(params.Reasons) > 0 {
+		reasons := make([]string, len(params.Reasons))
+		for i, r := range params.Reasons {
+			reasons[i] = fmt.Sprintf("%q", r)
+		}
+		clauses = append(clauses, fmt.Sprintf("jsonPayload.reason = (%s)", strings.Join(reasons, " OR ")))
+	}
+	if params.Type != "" {
+		clauses = append(clauses, fmt.Sprintf("jsonPayload.type = %q", params.Type))
+	}
+	if params.Filter != "" {
+		clauses = append(clauses, fmt.Sprintf("(%s)", params.Filter))
+	}
+	return strings.Join(clauses, " AND ")
+}
+
+// QueryEvents returns Kubernetes events matching the structured parameters, newest first
+func (c *Client) QueryEvents(ctx context.Context, params QueryEventsParams) (*QueryEventsResult, error) {
+	filter := eventsFilter(params)
+	// 上限に達したかを知るため1件多く読む
+	result, err := c.logging.Query(ctx, logging.QueryParams{
+		ProjectID: params.ProjectID,
+		Filter:    filter,
+		TimeRange: params.TimeRange,
+		Limit:     params.Limit + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := result.Entries
+	truncated := len(entries) > params.Limit
+	if truncated {
+		entries = entries[:params.Limit]
+	}
+	events := make([]Event, 0, len(entries))
+	stats := EventsStats{ByReason: map[string]int{}, Truncated: truncated}
+	for _, e := range entries {
+		ev := normalizeEvent(e)
+		events = append(events, ev)
+		stats.ByReason[ev.Reason]++
+		if ev.Type == "Warning" {
+			stats.WarningCount++
+		}
+	}
+	stats.ReturnedCount = len(events)
+
+	return &QueryEventsResult{
+		QueryMeta: EventsQueryMeta{
+			ProjectID: params.ProjectID,
+			Start:     result.QueryMeta.Start,
+			End:       result.QueryMeta.End,
+			Filter:    filter,
+			Limit:     params.Limit,
+		},
+		Events: events,
+		Stats:  stats,
+	}, nil
+}
+
+// normalizeEvent はイベントのログエントリ（jsonPayload が Event オブジェクト）を Event にする
+func normalizeEvent(e logging.LogEntry) Event {
+	p := e.JSONPayload
+	object := payloadMap(p, "involvedObject")
+	source := payloadMap(p, "source")
+
+	ev := Event{
+		Time:    e.Timestamp,
+		Type:    payloadString(p, "type"),
+		Reason:  payloadString(p, "reason"),
+		Message: payloadString(p, "message"),
+		Object: ObjectRef{
+			Kind:      payloadString(object, "kind"),
+			Namespace: payloadString(object, "namespace"),
+			Name:      payloadString(object, "name"),
+			FieldPath: payloadString(object, "fieldPath"),
+		},
+		FirstSeen: payloadString(p, "firstTimestamp"),
+		LastSeen:  payloadString(p, "lastTimestamp"),
+		Cluster:   e.Resource.Labels["cluster_name"],
+		Location:  e.Resource.Labels["location"],
+	}
+	if count, ok := p["count"].(float64); ok {
+		ev.Count = int(count)
+	}
+	// 新しい形式（events.k8s.io/v1）は reportingComponent / eventTime を使う
+	ev.Source = payloadString(source, "component")
+	if ev.Source == "" {
+		ev.Source = payloadString(p, "reportingComponent")
+	}
+	if host := payloadString(source, "host"); host != "" {
+		ev.Source += " on " + host
+	}
+	if ev.LastSeen == "" {
+		ev.LastSeen = payloadString(p, "eventTime")
+	}
+	if ev.Message == "" {
+		ev.Message = e.TextPayload
+	}
+	return ev
+}
+
+// payloadMap は m[key] がオブジェクトならそれを返す
+func payloadMap(m map[string]any, key string) map[string]any {
+	v, _ := m[key].(map[string]any)
+	return v
+}
+
+// payloadString は m[key] が文字列ならそれを返す
+func payloadString(m map[string]any, key string) string {
+	v, _ := m[key].(string)
+	return v
+}
+
+// Validator はガードレール検証用インターフェース
+type Validator interface {
+	ValidateTimeRange(ctx context.Context, start, end time.Time) error
+	ClampLogLimit(ctx context.Context, limit int) int
+	ClampTimeSeriesLimit(ctx context.Context, limit int) int
+}
+
+// QueryEventsHandlerWithGuardrail returns a handler with guardrail validation
+func (c *Client) QueryEventsHandlerWithGuardrail(v Validator) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params QueryEventsParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.Type != "" && params.Type != "Normal" && params.Type != "Warning" {
+			return nil, fmt.Errorf("unsupported type: %s (supported: %s)", params.Type, strings.Join(EventTypes, ", "))
+		}
+		if params.Limit <= 0 {
+			params.Limit = 100
+		}
+		// ガードレール: 件数制限
+		params.Limit = v.ClampLogLimit(ctx, params.Limit)
+
+		return c.QueryEvents(ctx, params)
+	}
+}
@@ -0,0 +1,228 @@
+package gke
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/guardrail"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/provider"
+)
+
+func init() {
+	provider.Register("gke", newProvider)
+}
+
+// toolProvider は GKE (Container API) のツール（gke.*）を提供する
+type toolProvider struct {
+	client *provider.Lazy[*Client]
+	cfg    *config.Config
+	guard  *guardrail.Guardrail
+}
+
+func newProvider(ctx context.Context, env provider.Env) (provider.ToolProvider, error) {
+	client := provider.NewLazy(ctx, "gke", func(ctx context.Context) (*Client, error) {
+		return newOwnedClient(ctx, env)
+	})
+	return &toolProvider{client: client, cfg: env.Config, guard: env.Guard}, nil
+}
+
+// newOwnedClient はイベントとコンテナのメトリクスを読む logging / monitoring クライアントも自前で作る
+// （logging / monitoring プロバイダを無効にしても gke.* は使える）
+func newOwnedClient(ctx context.Context, env provider.Env) (*Client, error) {
+	loggingClient, err := logging.NewClient(ctx, env.GRPCOptionsFor("logging")...)
+	if err != nil {
+		return nil, err
+	}
+	loggingClient.SetAllowedLogViews(env.Config.AllowedLogViews)
+	loggingClient.SetResourceRules(env.Config.ResourceRulesFor)
+	// logging.exclude_filters はアプリのログのノイズ向けなのでイベントには適用しない
+	monitoringClient, err := monitoring.NewClient(ctx, env.GRPCOptionsFor("monitoring")...)
+	if err != nil {
+		_ = loggingClient.Close()
+		return nil, err
+	}
+	monitoringClient.SetResourceRules(env.Config.ResourceRulesFor)
+	client, err := NewClient(ctx, loggingClient, monitoringClient, env.HTTPOptions...)
+	if err != nil {
+		_ = monitoringClient.Close()
+		_ = loggingClient.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+func (p *toolProvider) Name() string {
+	return "gke"
+}
+
+func (p *toolProvider) RequiredAPIs() []string {
+	return []string{"container.googleapis.com", "logging.googleapis.com", "monitoring.googleapis.com"}
+}
+
+func (p *toolProvider) Tools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "gke.describe_cluster",
+			Description: "Describe a GKE cluster: versions, node pool sizes/autoscaling, upgrade status, and recent cluster operations.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"location": {
+						Type:        "string",
+						Description: "Cluster region or zone (e.g., 'asia-northeast1')",
+					},
+					"cluster": {
+						Type:        "string",
+						Description: "Cluster name",
+					},
+					"operations_limit": {
+						Type:        "integer",
+						Description: "Maximum number of recent operations to return (default: 10, max: 50)",
+						Default:     10,
+					},
+				},
+				Required: []string{"project_id", "location", "cluster"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[DescribeClusterResult](),
+		},
+		{
+			Name:        "gke.query_events",
+			Description: "Query Kubernetes events (the 'events' log GKE writes to Cloud Logging) with structured filters instead of hand-written LQL: namespace, involved object kind/name, reasons and type. Returns normalized events (time, type, reason, message, object, count, source) newest first, counts by reason, and the generated filter for reuse with logging.query.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"cluster": {
+						Type:        "string",
+						Description: "Cluster name (default: all clusters in the project)",
+					},
+					"location": {
+						Type:        "string",
+						Description: "Cluster region or zone (e.g., 'asia-northeast1')",
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the involved object",
+					},
+					"kind": {
+						Type:        "string",
+						Description: "Kind of the involved object (e.g., 'Pod', 'Deployment', 'ReplicaSet', 'Node', 'HorizontalPodAutoscaler')",
+					},
+					"name": {
+						Type:        "string",
+						Description: "Name of the involved object. A trailing '*' matches a prefix, e.g. 'checkout-*' for the pods of a workload",
+					},
+					"reasons": {
+						Type:        "array",
+						Description: "Event reasons to match (any of), e.g. ['BackOff', 'OOMKilling', 'FailedScheduling', 'Unhealthy', 'Killing']",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"type": {
+						Type:        "string",
+						Description: "Event type (default: both)",
+						Enum:        EventTypes,
+					},
+					"filter": {
+						Type:        "string",
+						Description: "Additional Logging Query Language filter ANDed to the generated one",
+					},
+					"time_range": {
+						Type:        "object",
+						Description: "Time range for the query",
+						Properties: map[string]mcp.Property{
+							"start": {
+								Type:        "string",
+								Description: "Start time (RFC3339 or relative like '-1h', '-30m')",
+							},
+							"end": {
+								Type:        "string",
+								Description: "End time (RFC3339 or 'now')",
+								Default:     "now",
+							},
+						},
+					},
+					"limit": {
+						Type:        "integer",
+						Description: fmt.Sprintf("Maximum number of events to return (default: 100, max: %d)", p.cfg.Limits.MaxLogEntries),
+						Default:     100,
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[QueryEventsResult](),
+		},
+		{
+			Name:        "gke.crash_report",
+			Description: "Analyze container crashes of a GKE workload: restarts per container (and pod) from restart_count, crash-related events (BackOff, Unhealthy, Killing), memory usage right before each crash compared with the limit, and the containers' latest error logs. Returns the inferred last termination reason (OOMKilled, LivenessProbeFailed, CrashLoopBackOff) with evidence and a memory headroom assessment per container.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"cluster": {
+						Type:        "string",
+						Description: "Cluster name (default: all clusters in the project)",
+					},
+					"location": {
+						Type:        "string",
+						Description: "Cluster region or zone (e.g., 'asia-northeast1')",
+					},
+					"namespace": {
+						Type:        "string",
+						Description: "Namespace of the workload",
+					},
+					"workload": {
+						Type:        "string",
+						Description: "Workload name (top-level controller such as a Deployment or StatefulSet, e.g. 'checkout')",
+					},
+					"time_range": {
+						Type:        "object",
+						Description: "Time range to analyze (default: last 6 hours)",
+						Properties: map[string]mcp.Property{
+							"start": {
+								Type:        "string",
+								Description: "Start time (RFC3339 or relative like '-1h', '-30m')",
+								Default:     "-6h",
+							},
+							"end": {
+								Type:        "string",
+								Description: "End time (RFC3339 or 'now')",
+								Default:     "now",
+							},
+						},
+					},
+				},
+				Required: []string{"project_id", "namespace", "workload"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[CrashReportResult](),
+		},
+	}
+}
+
+func (p *toolProvider) Handlers() map[string]mcp.ToolHandler {
+	return map[string]mcp.ToolHandler{
+		"gke.describe_cluster": p.client.Handler(func(c *Client) mcp.ToolHandler { return c.DescribeClusterHandler() }),
+		"gke.query_events":     p.client.Handler(func(c *Client) mcp.ToolHandler { return c.QueryEventsHandlerWithGuardrail(p.guard) }),
+		"gke.crash_report":     p.client.Handler(func(c *Client) mcp.ToolHandler { return c.CrashReportHandlerWithGuardrail(p.guard) }),
+	}
+}
+
+func (p *toolProvider) Close() error {
+	return p.client.Close(func(c *Client) error {
+		return errors.Join(c.monitoring.Close(), c.logging.Close())
+	})
+}
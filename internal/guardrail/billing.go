@@ -0,0 +1,50 @@
+package guardrail
+
+import (
+	"context"
+
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// quotaProjectHeader は API の利用量・課金を付け替えるプロジェクトのヘッダ（gcloud の --billing-project と同じ）
+const quotaProjectHeader = "x-goog-user-project"
+
+// billingProjectDescription は project_id を持つツールに追加する billing_project の説明
+const billingProjectDescription = "Project to bill the API usage of this call to (sent as the quota project, like gcloud --billing-project), e.g. the cost center's project when reading a centralized logging bucket. Accepts project aliases and must be allowed like project_id; the caller needs serviceusage.services.use on it. Applies to Cloud Logging and Cloud Monitoring reads"
+
+type billingProjectKey struct{}
+
+// WithBillingProject は呼び出しの API 利用を付け替えるプロジェクトを ctx に設定する
+func WithBillingProject(ctx context.Context, projectID string) context.Context {
+	return context.WithValue(ctx, billingProjectKey{}, projectID)
+}
+
+// BillingProject は ctx の billing_project を返す（なければ空 = 認証情報の quota project）
+func BillingProject(ctx context.Context) string {
+	projectID, _ := ctx.Value(billingProjectKey{}).(string)
+	return projectID
+}
+
+// BillingGRPCOptions returns client options for gRPC API clients (Logging, Monitoring)
+// that send the call's billing_project as the quota project.
+// REST API clients are not covered and keep the quota project of the credentials.
+func BillingGRPCOptions() []option.ClientOption {
+	return []option.ClientOption{
+		option.WithGRPCDialOption(grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(withQuotaProject(ctx), method, req, reply, cc, opts...)
+		})),
+		option.WithGRPCDialOption(grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(withQuotaProject(ctx), desc, cc, method, opts...)
+		})),
+	}
+}
+
+// withQuotaProject は billing_project があれば送信メタデータに quota project を付ける
+func withQuotaProject(ctx context.Context) context.Context {
+	if projectID := BillingProject(ctx); projectID != "" {
+		return metadata.AppendToOutgoingContext(ctx, quotaProjectHeader, projectID)
+	}
+	return ctx
+}
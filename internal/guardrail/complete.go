@@ -0,0 +1,43 @@
+package guardrail
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/auth"
+)
+
+// Complete completes project_id from the allow-list: project aliases, the default project and
+// allowed_project_ids without glob patterns, limited to those the caller may use (see provider.Completer)
+func (g *Guardrail) Complete(ctx context.Context, argument, prefix string, args map[string]string) ([]string, bool, error) {
+	if argument != "project_id" {
+		return nil, false, nil
+	}
+	// ツール呼び出しと同じく、接続元の profile がなければ設定の profile で判定する
+	if _, p := auth.ProfileFrom(ctx); p == nil && g.cfg.Profile != "" {
+		ctx = auth.WithProfile(ctx, g.cfg.Profile, g.cfg.ProfileByName(g.cfg.Profile))
+	}
+
+	candidates := map[string]string{} // 候補 → 実際のプロジェクトID
+	for alias, id := range g.cfg.ProjectAliases {
+		candidates[alias] = id
+	}
+	if g.cfg.DefaultProjectID != "" {
+		candidates[g.cfg.DefaultProjectID] = g.cfg.DefaultProjectID
+	}
+	for _, id := range g.cfg.AllowedProjectIDs {
+		if !strings.ContainsAny(id, "*?[") {
+			candidates[id] = id
+		}
+	}
+
+	values := []string{}
+	for candidate, id := range candidates {
+		if strings.HasPrefix(candidate, prefix) && g.ValidateProjectID(ctx, id) == nil {
+			values = append(values, candidate)
+		}
+	}
+	sort.Strings(values)
+	return values, true, nil
+}
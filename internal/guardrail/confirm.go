@@ -0,0 +1,81 @@
+package guardrail
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// confirmTokenTTL は確認トークンの有効期限
+const confirmTokenTTL = 5 * time.Minute
+
+// IssueConfirmToken は書き込み操作の内容に紐づく確認トークンを発行する
+// 書き込みツールは1回目の呼び出しでプレビューとトークンを返し、同じ内容＋トークンの2回目で実行する
+func (g *Guardrail) IssueConfirmToken(action string, payload any) (string, error) {
+	expires := time.Now().Add(confirmTokenTTL).Unix()
+	sig, err := g.signConfirm(action, payload, expires)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(expires, 10) + "." + sig, nil
+}
+
+// VerifyConfirmToken は確認トークンが同じ操作内容に対して発行され、期限内か検証する
+func (g *Guardrail) VerifyConfirmToken(token, action string, payload any) error {
+	expStr, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return fmt.Errorf("invalid confirm_token")
+	}
+	expires, err := strconv.ParseInt(expStr, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid confirm_token")
+	}
+	if time.Now().Unix() > expires {
+		return fmt.Errorf("confirm_token expired; call again without confirm_token to get a new one")
+	}
+	want, err := g.signConfirm(action, payload, expires)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(sig), []byte(want)) {
+		return fmt.Errorf("confirm_token does not match the requested operation; call again without confirm_token to preview")
+	}
+	return nil
+}
+
+func (g *Guardrail) signConfirm(action string, payload any, expires int64) (string, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode operation: %w", err)
+	}
+	mac := hmac.New(sha256.New, g.confirmKey)
+	fmt.Fprintf(mac, "%s\n%d\n", action, expires)
+	mac.Write(body)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Audit は書き込み操作の監査ログを構造化ログ（stderr）に出力する
+// ログレベルの設定に関わらず記録されるよう、最も高いレベルで出力する
+func (g *Guardrail) Audit(action string, payload any) {
+	slog.Log(context.Background(), AuditLevel, "audit", "audit", true, "action", action, "payload", payload)
+}
+
+// AuditLevel は監査ログのレベル（log_level: error でも出力される）
+const AuditLevel = slog.LevelError + 4
+
+// newConfirmKey はプロセスごとのランダムな署名鍵を生成する（再起動で既存トークンは無効になる）
+func newConfirmKey() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(fmt.Sprintf("failed to generate confirm key: %v", err))
+	}
+	return key
+}
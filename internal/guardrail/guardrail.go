@@ -1,38 +1,133 @@
 package guardrail
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/auth"
 	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/telemetry"
 )
 
+// AncestryLookup はプロジェクトの祖先（"folders/123", "organizations/456"）を返す
+type AncestryLookup func(projectID string) ([]string, error)
+
+// ViolationHook はガードレールが呼び出しを拒否したとき（許可されていないプロジェクト、時間範囲の上限超過）に呼ばれる
+type ViolationHook func(ctx context.Context, tool string, err error)
+
 // Guardrail はクエリのガードレールを実装
 type Guardrail struct {
 	cfg *config.Config
+
+	ancestry      AncestryLookup
+	mu            sync.Mutex
+	ancestorCache map[string]bool // projectID → フォルダ・組織ルールで許可されるか
+
+	permissionTester PermissionTester
+	preflightCache   map[string]preflightEntry // projectID → 付与されている権限（preflight）
+
+	vpcscLookup VPCSCLookup // VPC Service Controls の拒否の詳細（ポリシー監査ログ）
+
+	confirmKey []byte // 書き込み操作の確認トークン署名用
+
+	onViolation ViolationHook
 }
 
 // New は新しいGuardrailを作成
 func New(cfg *config.Config) *Guardrail {
-	return &Guardrail{cfg: cfg}
+	return &Guardrail{cfg: cfg, ancestorCache: map[string]bool{}, preflightCache: map[string]preflightEntry{}, confirmKey: newConfirmKey()}
+}
+
+// SetAncestryLookup はフォルダ・組織単位の許可判定に使う祖先解決関数を設定する
+func (g *Guardrail) SetAncestryLookup(lookup AncestryLookup) {
+	g.ancestry = lookup
+}
+
+// SetViolationHook はガードレールの拒否を通知する関数を設定する（notifications の guardrail イベント）
+func (g *Guardrail) SetViolationHook(hook ViolationHook) {
+	g.onViolation = hook
 }
 
 // ValidateProjectID はプロジェクトIDが許可されているか検証
-func (g *Guardrail) ValidateProjectID(projectID string) error {
-	if !g.cfg.IsProjectAllowed(projectID) {
-		return fmt.Errorf("project_id '%s' is not in the allowed list", projectID)
+// HTTP トランスポートの接続元・プロファイルの許可リストがあれば、それにも一致する必要がある
+func (g *Guardrail) ValidateProjectID(ctx context.Context, projectID string) error {
+	if g.cfg.IsProjectDenied(projectID) {
+		return fmt.Errorf("project_id '%s' is denied by configuration", projectID)
 	}
-	return nil
+	if client := auth.ClientFrom(ctx); client != nil && !client.IsProjectAllowed(projectID) {
+		return fmt.Errorf("project_id '%s' is not allowed for client '%s'", projectID, client.Name)
+	}
+	if name, profile := auth.ProfileFrom(ctx); profile != nil && !profile.IsProjectAllowed(projectID) {
+		return fmt.Errorf("project_id '%s' is not allowed by profile '%s'", projectID, name)
+	}
+	if g.cfg.IsProjectAllowed(projectID) {
+		return nil
+	}
+	if g.cfg.HasAncestorRules() && g.ancestry != nil {
+		allowed, err := g.isAllowedByAncestor(projectID)
+		if err != nil {
+			return fmt.Errorf("failed to resolve ancestry of project_id '%s': %w", projectID, err)
+		}
+		if allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("project_id '%s' is not in the allowed list", projectID)
+}
+
+// isAllowedByAncestor はフォルダ・組織ルールで許可されるか判定する（結果はキャッシュ）
+func (g *Guardrail) isAllowedByAncestor(projectID string) (bool, error) {
+	g.mu.Lock()
+	allowed, ok := g.ancestorCache[projectID]
+	g.mu.Unlock()
+	telemetry.RecordCacheLookup(context.Background(), "project_ancestry", ok)
+	if ok {
+		return allowed, nil
+	}
+
+	ancestors, err := g.ancestry(projectID)
+	if err != nil {
+		return false, err
+	}
+	allowed = g.cfg.IsAncestorAllowed(ancestors)
+
+	g.mu.Lock()
+	g.ancestorCache[projectID] = allowed
+	g.mu.Unlock()
+	return allowed, nil
+}
+
+// ResolveProjectID はエイリアスを実IDに変換し、省略時はデフォルトプロジェクトを補完する
+func (g *Guardrail) ResolveProjectID(projectID string) string {
+	if projectID == "" {
+		return g.cfg.DefaultProjectID
+	}
+	return g.cfg.ResolveProjectAlias(projectID)
+}
+
+// Limits は呼び出しに適用する上限を返す（プロファイルと HTTP トランスポートの接続元の上限で絞り込む）
+func (g *Guardrail) Limits(ctx context.Context) config.Limits {
+	limits := g.cfg.Limits
+	if _, profile := auth.ProfileFrom(ctx); profile != nil {
+		limits = limits.Narrow(profile.Limits)
+	}
+	if client := auth.ClientFrom(ctx); client != nil {
+		limits = limits.Narrow(client.Limits)
+	}
+	return limits
 }
 
 // ValidateTimeRange は時間範囲が制限内か検証
-func (g *Guardrail) ValidateTimeRange(start, end time.Time) error {
+func (g *Guardrail) ValidateTimeRange(ctx context.Context, start, end time.Time) error {
 	duration := end.Sub(start)
-	maxDuration := time.Duration(g.cfg.Limits.MaxRangeHours) * time.Hour
+	maxHours := g.Limits(ctx).MaxRangeHours
+	maxDuration := time.Duration(maxHours) * time.Hour
 
 	if duration > maxDuration {
 		return fmt.Errorf("time range %.1f hours exceeds maximum %d hours",
-			duration.Hours(), g.cfg.Limits.MaxRangeHours)
+			duration.Hours(), maxHours)
 	}
 
 	if duration < 0 {
@@ -43,23 +138,62 @@ func (g *Guardrail) ValidateTimeRange(start, end time.Time) error {
 }
 
 // ClampLogLimit はログ件数を制限内に収める
-func (g *Guardrail) ClampLogLimit(limit int) int {
+func (g *Guardrail) ClampLogLimit(ctx context.Context, limit int) int {
+	if limit <= 0 {
+		limit = 200 // デフォルト
+	}
+	return min(limit, g.Limits(ctx).MaxLogEntries)
+}
+
+// ClampTimeSeriesLimit は時系列数を制限内に収める
+func (g *Guardrail) ClampTimeSeriesLimit(ctx context.Context, limit int) int {
 	if limit <= 0 {
-		return 200 // デフォルト
+		limit = 20 // デフォルト
 	}
-	if limit > g.cfg.Limits.MaxLogEntries {
-		return g.cfg.Limits.MaxLogEntries
+	return min(limit, g.Limits(ctx).MaxTimeSeries)
+}
+
+// ClampPointsPerSeries は1系列あたりのデータポイント数を制限内に収める
+func (g *Guardrail) ClampPointsPerSeries(ctx context.Context, limit int) int {
+	maxPoints := g.Limits(ctx).MaxPointsPerSeries
+	if limit <= 0 || limit > maxPoints {
+		return maxPoints
 	}
 	return limit
 }
 
-// ClampTimeSeriesLimit は時系列数を制限内に収める
-func (g *Guardrail) ClampTimeSeriesLimit(limit int) int {
+// RestrictAssetTypes はアセット種別を許可リストで検証する
+// 指定がなく許可リストがある場合は許可リスト全体に絞り込む
+func (g *Guardrail) RestrictAssetTypes(assetTypes []string) ([]string, error) {
+	if len(assetTypes) == 0 {
+		return g.cfg.Assets.AllowedAssetTypes, nil
+	}
+	for _, t := range assetTypes {
+		if !g.cfg.IsAssetTypeAllowed(t) {
+			return nil, fmt.Errorf("asset_type '%s' is not in the allowed list", t)
+		}
+	}
+	return assetTypes, nil
+}
+
+// ClampAssetResults はアセット検索の件数を制限内に収める
+func (g *Guardrail) ClampAssetResults(limit int) int {
+	if limit <= 0 {
+		return 100 // デフォルト
+	}
+	if limit > g.cfg.Assets.MaxResults {
+		return g.cfg.Assets.MaxResults
+	}
+	return limit
+}
+
+// ClampFindingsLimit はSCC findingsの件数を制限内に収める
+func (g *Guardrail) ClampFindingsLimit(limit int) int {
 	if limit <= 0 {
-		return 20 // デフォルト
+		return 50 // デフォルト
 	}
-	if limit > g.cfg.Limits.MaxTimeSeries {
-		return g.cfg.Limits.MaxTimeSeries
+	if limit > g.cfg.Security.MaxFindings {
+		return g.cfg.Security.MaxFindings
 	}
 	return limit
 }
@@ -0,0 +1,85 @@
+package guardrail
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+)
+
+func TestMiddlewareDenies(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.AllowedProjectIDs = []string{"team-a-*"}
+	cfg.DeniedProjectIDs = []string{"team-a-secret"}
+	cfg.Limits.MaxRangeHours = 24
+	g := New(cfg)
+	var violations []string
+	g.SetViolationHook(func(ctx context.Context, tool string, err error) {
+		violations = append(violations, tool)
+	})
+
+	tool := &mcp.Tool{
+		Name: "logging.query",
+		InputSchema: mcp.ToolSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"project_id": {Type: "string"},
+				"time_range": {Type: "object"},
+			},
+			Required: []string{"project_id"},
+		},
+	}
+	called := false
+	handler := g.Middleware()(tool, func(ctx context.Context, args json.RawMessage) (any, error) {
+		called = true
+		return "ok", nil
+	})
+
+	tests := []struct {
+		name      string
+		args      string
+		wantErr   string // 空なら許可
+		violation bool   // 拒否としてフックに渡るか（引数の誤りは渡らない）
+	}{
+		{"allowed project", `{"project_id":"team-a-prod"}`, "", false},
+		{"not in the allowed list", `{"project_id":"team-b-prod"}`, "is not in the allowed list", true},
+		{"denied project", `{"project_id":"team-a-secret"}`, "is denied by configuration", true},
+		{"denied billing project", `{"project_id":"team-a-prod","billing_project":"team-b-billing"}`, "billing_project: project_id 'team-b-billing' is not in the allowed list", true},
+		{"time range over the limit", `{"project_id":"team-a-prod","time_range":{"start":"-48h"}}`, "exceeds maximum 24 hours", true},
+		{"time range within the limit", `{"project_id":"team-a-prod","time_range":{"start":"-2h"}}`, "", false},
+		{"missing project_id", `{}`, "project_id is required", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called, violations = false, nil
+			_, err := handler(context.Background(), json.RawMessage(tt.args))
+			if tt.wantErr == "" {
+				if err != nil || !called {
+					t.Fatalf("err = %v, called = %v; want the call to pass through", err, called)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want it to contain %q", err, tt.wantErr)
+			}
+			if called {
+				t.Error("the tool ran although the call was denied")
+			}
+			if got := len(violations) == 1 && violations[0] == tool.Name; got != tt.violation {
+				t.Errorf("violations = %v, want reported = %v", violations, tt.violation)
+			}
+		})
+	}
+}
+
+func TestMiddlewareAddsBillingProject(t *testing.T) {
+	g := New(config.DefaultConfig())
+	tool := &mcp.Tool{Name: "logging.query", InputSchema: mcp.ToolSchema{Type: "object", Properties: map[string]mcp.Property{"project_id": {Type: "string"}}}}
+	g.Middleware()(tool, nil)
+	if _, ok := tool.InputSchema.Properties["billing_project"]; !ok {
+		t.Error("billing_project was not added to a tool taking project_id")
+	}
+}
@@ -0,0 +1,149 @@
+package guardrail
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/auth"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/timerange"
+)
+
+// commonArgs はガードレールが共通で検証する引数
+type commonArgs struct {
+	ProjectID      string `json:"project_id"`
+	BillingProject string `json:"billing_project"`
+	TimeRange      *struct {
+		Start string `json:"start"`
+		End   string `json:"end"`
+	} `json:"time_range"`
+}
+
+// Middleware は入力スキーマに project_id / time_range を持つツールに共通のガードレールを適用する
+//   - project_id: 必須チェック（スキーマで必須の場合）と許可判定
+//   - time_range: パースと最大範囲の検証
+//   - billing_project: project_id を持つツールに追加し、project_id と同じ許可判定の後 ctx に設定する
+//
+// エイリアス解決・デフォルトプロジェクト補完の後に動くよう、それより後に Use すること
+// ツール固有の検証（件数の上限、保存クエリの既定値を含む時間範囲など）は各ハンドラで行う
+func (g *Guardrail) Middleware() mcp.Middleware {
+	return func(tool *mcp.Tool, next mcp.ToolHandler) mcp.ToolHandler {
+		_, hasProject := tool.InputSchema.Properties["project_id"]
+		_, hasTimeRange := tool.InputSchema.Properties["time_range"]
+		if !hasProject && !hasTimeRange {
+			return next
+		}
+		projectRequired := slices.Contains(tool.InputSchema.Required, "project_id")
+		if hasProject {
+			tool.InputSchema.Properties["billing_project"] = mcp.Property{
+				Type:        "string",
+				Description: billingProjectDescription,
+			}
+		}
+
+		return func(ctx context.Context, args json.RawMessage) (any, error) {
+			var common commonArgs
+			if len(args) > 0 {
+				if err := json.Unmarshal(args, &common); err != nil {
+					return nil, fmt.Errorf("failed to parse arguments: %w", err)
+				}
+			}
+
+			// ガードレール: プロジェクトID検証
+			if hasProject {
+				if common.ProjectID == "" {
+					if projectRequired {
+						return nil, fmt.Errorf("project_id is required")
+					}
+				} else if err := g.ValidateProjectID(ctx, common.ProjectID); err != nil {
+					g.violation(ctx, tool.Name, err)
+					return nil, err
+				}
+				if common.BillingProject != "" {
+					billing := g.ResolveProjectID(common.BillingProject)
+					if err := g.ValidateProjectID(ctx, billing); err != nil {
+						err = fmt.Errorf("billing_project: %w", err)
+						g.violation(ctx, tool.Name, err)
+						return nil, err
+					}
+					ctx = WithBillingProject(ctx, billing)
+				}
+			}
+
+			// ガードレール: 時間範囲検証（省略時は既定の30分なので検証不要）
+			if hasTimeRange && common.TimeRange != nil {
+				start, end, err := timerange.Parse(common.TimeRange.Start, common.TimeRange.End)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse time range: %w", err)
+				}
+				if err := g.ValidateTimeRange(ctx, start, end); err != nil {
+					g.violation(ctx, tool.Name, err)
+					return nil, err
+				}
+			}
+
+			return next(ctx, args)
+		}
+	}
+}
+
+// violation はガードレールの拒否をフックに渡す
+// 引数の誤り（必須漏れ・パース失敗）は拒否ではないので渡さない
+func (g *Guardrail) violation(ctx context.Context, tool string, err error) {
+	if g.onViolation != nil {
+		g.onViolation(ctx, tool, err)
+	}
+}
+
+// AuditMiddleware は書き込みツールの実行（confirm_token 付きの呼び出し）を監査ログに記録する
+// プレビュー（confirm_token なし）は何も変更しないので記録しない
+func (g *Guardrail) AuditMiddleware() mcp.Middleware {
+	return func(tool *mcp.Tool, next mcp.ToolHandler) mcp.ToolHandler {
+		if tool.IsReadOnly() {
+			return next
+		}
+		name := tool.Name
+		return func(ctx context.Context, args json.RawMessage) (any, error) {
+			fields := map[string]any{}
+			if len(args) > 0 {
+				if err := json.Unmarshal(args, &fields); err != nil {
+					return nil, fmt.Errorf("failed to parse arguments: %w", err)
+				}
+			}
+			if token, _ := fields["confirm_token"].(string); token == "" {
+				return next(ctx, args)
+			}
+			delete(fields, "confirm_token")
+
+			result, err := next(ctx, args)
+			payload := map[string]any{"arguments": fields}
+			if err != nil {
+				payload["error"] = err.Error()
+			} else {
+				payload["result"] = result
+			}
+			g.Audit(name, payload)
+			return result, err
+		}
+	}
+}
+
+// ProfileMiddleware は呼び出しに適用するプロファイルを ctx に設定する
+// 接続元の profile（HTTP トランスポートの認証で設定済み）がなければ設定の profile を使う
+// キャッシュ・マスキング・ガードレールより前に動くよう、最初の方で Use すること
+func (g *Guardrail) ProfileMiddleware() mcp.Middleware {
+	return func(tool *mcp.Tool, next mcp.ToolHandler) mcp.ToolHandler {
+		if g.cfg.Profile == "" {
+			return next
+		}
+		profile := g.cfg.ProfileByName(g.cfg.Profile)
+		return func(ctx context.Context, args json.RawMessage) (any, error) {
+			if _, p := auth.ProfileFrom(ctx); p == nil {
+				ctx = auth.WithProfile(ctx, g.cfg.Profile, profile)
+			}
+			return next(ctx, args)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package guardrail
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/telemetry"
+)
+
+// PermissionTester は testIamPermissions で projectID に対して付与されている権限を返す
+type PermissionTester func(ctx context.Context, projectID string, permissions []string) ([]string, error)
+
+// preflightPermissions は事前に確認するツールごとの IAM 権限（重いクエリを投げるツールのみ）
+var preflightPermissions = map[string][]string{
+	"logging.query":                    {"logging.logEntries.list"},
+	"logging.top_errors":               {"logging.logEntries.list"},
+	"logging.count":                    {"logging.logEntries.list"},
+	"monitoring.query_time_series":     {"monitoring.timeSeries.list"},
+	"monitoring.evaluate_threshold":    {"monitoring.timeSeries.list"},
+	"monitoring.forecast":              {"monitoring.timeSeries.list"},
+	"monitoring.backtest_alert_policy": {"monitoring.alertPolicies.get", "monitoring.timeSeries.list"},
+	"monitoring.explain_spike":         {"monitoring.timeSeries.list", "logging.logEntries.list"},
+	"ops.golden_signals":               {"monitoring.timeSeries.list"},
+	"ops.generate_report":              {"monitoring.timeSeries.list", "logging.logEntries.list"},
+	"ops.list_resources":               {"monitoring.timeSeries.list"},
+	"ops.functions_overview":           {"monitoring.timeSeries.list"},
+	"ops.bigquery_overview":            {"monitoring.timeSeries.list"},
+	"ops.network_flows":                {"logging.logEntries.list"},
+	"ops.nat_overview":                 {"monitoring.timeSeries.list", "logging.logEntries.list"},
+	"ops.dataflow_overview":            {"monitoring.timeSeries.list", "logging.logEntries.list"},
+	"ops.gcs_overview":                 {"monitoring.timeSeries.list", "logging.privateLogEntries.list"},
+	"ops.scheduler_failures":           {"monitoring.timeSeries.list", "logging.logEntries.list"},
+	"ops.vertex_overview":              {"monitoring.timeSeries.list", "logging.logEntries.list"},
+	"ops.api_gateway_overview":         {"monitoring.timeSeries.list", "logging.logEntries.list"},
+	"gke.query_events":                 {"logging.logEntries.list"},
+	"gke.crash_report":                 {"monitoring.timeSeries.list", "logging.logEntries.list"},
+}
+
+// permissionRoles は権限が足りないときに案内する事前定義ロール
+var permissionRoles = map[string]string{
+	"logging.logEntries.list":        "roles/logging.viewer",
+	"logging.privateLogEntries.list": "roles/logging.privateLogViewer",
+	"monitoring.timeSeries.list":     "roles/monitoring.viewer",
+}
+
+// preflightEntry はプロジェクトごとの権限確認結果のキャッシュ
+type preflightEntry struct {
+	granted map[string]bool
+	expires time.Time
+}
+
+// SetPermissionTester は IAM の事前確認（preflight.enabled）に使う関数を設定する
+func (g *Guardrail) SetPermissionTester(tester PermissionTester) {
+	g.permissionTester = tester
+}
+
+// PreflightMiddleware は重いクエリを投げる前に必要な IAM 権限があるか testIamPermissions で確認し、
+// 足りなければ付与すべきロールを添えたエラーを返す（結果はプロジェクトごとに preflight.cache_ttl_sec キャッシュ）
+// project_id の検証（Middleware）の後に Use すること
+func (g *Guardrail) PreflightMiddleware() mcp.Middleware {
+	return func(tool *mcp.Tool, next mcp.ToolHandler) mcp.ToolHandler {
+		perms, ok := preflightPermissions[tool.Name]
+		if !ok || !g.cfg.Preflight.Enabled {
+			return next
+		}
+		return func(ctx context.Context, args json.RawMessage) (any, error) {
+			var common commonArgs
+			if len(args) > 0 {
+				if err := json.Unmarshal(args, &common); err != nil {
+					return nil, fmt.Errorf("failed to parse arguments: %w", err)
+				}
+			}
+			if common.ProjectID != "" && g.permissionTester != nil {
+				if err := g.checkPermissions(ctx, common.ProjectID, perms); err != nil {
+					return nil, err
+				}
+			}
+			return next(ctx, args)
+		}
+	}
+}
+
+// checkPermissions は perms がすべて付与されているか確認する
+// 確認自体に失敗した場合（Resource Manager API が無効など）はツールの実行を妨げない
+// ただし VPC Service Controls の境界による拒否は本体のクエリも同じく拒否されるので、そのエラーを返す
+func (g *Guardrail) checkPermissions(ctx context.Context, projectID string, perms []string) error {
+	granted, err := g.grantedPermissions(ctx, projectID)
+	if _, blocked := VPCSCUniqueID(err); blocked {
+		return fmt.Errorf("IAM preflight check on project '%s' failed: %w", projectID, err)
+	}
+	if err != nil {
+		slog.Debug("IAM preflight check skipped", "project_id", projectID, "error", err)
+		return nil
+	}
+	var missing, roles []string
+	for _, p := range perms {
+		if !granted[p] {
+			missing = append(missing, p)
+			roles = append(roles, permissionRoles[p])
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("missing IAM permission %s on project '%s': grant %s to the credentials of this server (ops.health shows all permissions)",
+		strings.Join(missing, ", "), projectID, strings.Join(roles, ", "))
+}
+
+// grantedPermissions は事前確認の対象となる権限のうち付与されているものを返す（キャッシュ付き）
+func (g *Guardrail) grantedPermissions(ctx context.Context, projectID string) (map[string]bool, error) {
+	g.mu.Lock()
+	entry, ok := g.preflightCache[projectID]
+	g.mu.Unlock()
+	ok = ok && time.Now().Before(entry.expires)
+	telemetry.RecordCacheLookup(ctx, "iam_preflight", ok)
+	if ok {
+		return entry.granted, nil
+	}
+
+	// 全ツール分の権限をまとめて1回で確認する
+	all := map[string]bool{}
+	for _, perms := range preflightPermissions {
+		for _, p := range perms {
+			all[p] = true
+		}
+	}
+	perms := make([]string, 0, len(all))
+	for p := range all {
+		perms = append(perms, p)
+	}
+	sort.Strings(perms)
+
+	testCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	grantedList, err := g.permissionTester(testCtx, projectID, perms)
+	if err != nil {
+		return nil, err
+	}
+	granted := map[string]bool{}
+	for _, p := range grantedList {
+		granted[p] = true
+	}
+
+	g.mu.Lock()
+	g.preflightCache[projectID] = preflightEntry{
+		granted: granted,
+		expires: time.Now().Add(time.Duration(g.cfg.Preflight.CacheTTLSeconds) * time.Second),
+	}
+	g.mu.Unlock()
+	return granted, nil
+}
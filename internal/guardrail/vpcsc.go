@@ -0,0 +1,149 @@
+package guardrail
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+)
+
+// vpcscIDPattern は VPC Service Controls の拒否のエラーメッセージに含まれる一意 ID
+// （gRPC・REST とも "Request is prohibited by organization's policy. vpcServiceControlsUniqueIdentifier: X"）
+var vpcscIDPattern = regexp.MustCompile(`vpcServiceControlsUniqueIdentifier:\s*([A-Za-z0-9_-]+)`)
+
+// VPCSCDetails は VPC Service Controls の拒否の詳細（対象プロジェクトのポリシー監査ログから引く）
+type VPCSCDetails struct {
+	Perimeter       string   // accessPolicies/P/servicePerimeters/N
+	ViolationReason string   // NO_MATCHING_ACCESS_LEVEL, RESOURCES_NOT_IN_SAME_SERVICE_PERIMETER など
+	Principal       string   // 呼び出し元のプリンシパル
+	CallerIP        string   // 呼び出し元の IP（VPC 内からなら "private"）
+	AccessLevels    []string // 境界の access level（Access Context Manager で読めた場合のみ）
+}
+
+// VPCSCLookup は拒否の一意 ID から projectID のポリシー監査ログの詳細を返す（見つからなければ nil）
+type VPCSCLookup func(ctx context.Context, projectID, uniqueID string) (*VPCSCDetails, error)
+
+// SetVPCSCLookup は VPC Service Controls の拒否の詳細を引く関数を設定する
+func (g *Guardrail) SetVPCSCLookup(lookup VPCSCLookup) {
+	g.vpcscLookup = lookup
+}
+
+// VPCSCUniqueID は err が VPC Service Controls の境界による拒否なら、その一意 ID を返す
+// 一見ただの PERMISSION_DENIED なので、エラーの詳細（ErrorInfo の SECURITY_POLICY_VIOLATED・
+// PreconditionFailure の VPC_SERVICE_CONTROLS）かメッセージの一意 ID で見分ける
+func VPCSCUniqueID(err error) (string, bool) {
+	if err == nil {
+		return "", false
+	}
+	found := false
+	if s, ok := status.FromError(err); ok {
+		for _, d := range s.Details() {
+			switch d := d.(type) {
+			case *errdetails.ErrorInfo:
+				found = found || d.GetReason() == "SECURITY_POLICY_VIOLATED"
+			case *errdetails.PreconditionFailure:
+				for _, v := range d.GetViolations() {
+					if v.GetType() == "VPC_SERVICE_CONTROLS" {
+						if v.GetDescription() != "" {
+							return v.GetDescription(), true
+						}
+						found = true
+					}
+				}
+			}
+		}
+	}
+	if m := vpcscIDPattern.FindStringSubmatch(err.Error()); m != nil {
+		return m[1], true
+	}
+	return "", found || strings.Contains(err.Error(), "SECURITY_POLICY_VIOLATED")
+}
+
+// VPCSCMiddleware は VPC Service Controls の境界による拒否を、境界の名前・理由・足りない access level を
+// 添えたエラーに置き換える（IAM の権限不足と見分けがつかず、ロールを付与しても直らないため）
+// IAM の事前確認（PreflightMiddleware）の拒否も説明できるよう、その外側に Use すること
+func (g *Guardrail) VPCSCMiddleware() mcp.Middleware {
+	return func(tool *mcp.Tool, next mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, args json.RawMessage) (any, error) {
+			result, err := next(ctx, args)
+			uniqueID, ok := VPCSCUniqueID(err)
+			if !ok {
+				return result, err
+			}
+			var common commonArgs
+			if len(args) > 0 {
+				_ = json.Unmarshal(args, &common)
+			}
+			return nil, g.explainVPCSC(ctx, common.ProjectID, uniqueID, err)
+		}
+	}
+}
+
+// explainVPCSC は拒否の詳細を引いて説明を付けたエラーを返す（詳細が引けなければ監査ログの探し方を添える）
+func (g *Guardrail) explainVPCSC(ctx context.Context, projectID, uniqueID string, err error) error {
+	var details *VPCSCDetails
+	if projectID != "" && uniqueID != "" && g.vpcscLookup != nil {
+		d, lookupErr := g.vpcscLookup(ctx, projectID, uniqueID)
+		if lookupErr != nil {
+			// ログの読み取り自体が同じ境界で拒否されることも多い
+			slog.Debug("VPC Service Controls violation lookup failed", "project_id", projectID, "unique_id", uniqueID, "error", lookupErr)
+		}
+		details = d
+	}
+
+	var b strings.Builder
+	b.WriteString("blocked by a VPC Service Controls perimeter, not by IAM (granting roles does not help)")
+	if details == nil {
+		if projectID != "" && uniqueID != "" {
+			fmt.Fprintf(&b, ". Find the perimeter and the reason in the policy audit log of project '%s' with the filter: %s", projectID, VPCSCAuditFilter(projectID, uniqueID))
+		}
+		return fmt.Errorf("%s: %w", b.String(), err)
+	}
+
+	fmt.Fprintf(&b, ". Perimeter: %s, reason: %s", details.Perimeter, details.ViolationReason)
+	if details.Principal != "" || details.CallerIP != "" {
+		fmt.Fprintf(&b, ", caller: %s from %s", orUnknown(details.Principal), orUnknown(details.CallerIP))
+	}
+	b.WriteString(". ")
+	b.WriteString(vpcscHint(details))
+	return fmt.Errorf("%s: %w", b.String(), err)
+}
+
+// VPCSCAuditFilter は拒否の一意 ID のポリシー監査ログを読む LQL を返す
+func VPCSCAuditFilter(projectID, uniqueID string) string {
+	return fmt.Sprintf(`logName="projects/%s/logs/cloudaudit.googleapis.com%%2Fpolicy" AND protoPayload.metadata.vpcServiceControlsUniqueId="%s"`, projectID, uniqueID)
+}
+
+// vpcscHint は拒否の理由ごとの直し方を返す
+func vpcscHint(d *VPCSCDetails) string {
+	switch d.ViolationReason {
+	case "NO_MATCHING_ACCESS_LEVEL":
+		levels := ""
+		if len(d.AccessLevels) > 0 {
+			levels = fmt.Sprintf(" (%s)", strings.Join(d.AccessLevels, ", "))
+		}
+		return fmt.Sprintf("The caller matches none of the perimeter's access levels%s: add the caller's IP range or identity to one of them, add an ingress rule for the caller, or run this server inside the perimeter", levels)
+	case "NETWORK_NOT_IN_SAME_SERVICE_PERIMETER":
+		return "The caller's VPC network is outside the perimeter: add the network's project to the perimeter or add an ingress rule for the caller"
+	case "RESOURCES_NOT_IN_SAME_SERVICE_PERIMETER":
+		return "The call spans projects in different perimeters: add an ingress or egress rule, or a perimeter bridge"
+	case "SERVICE_NOT_ALLOWED_FROM_VPC":
+		return "The API is not in the perimeter's VPC accessible services: add it there"
+	default:
+		return "Ask the perimeter's administrators for an access level or ingress rule that covers this server"
+	}
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
@@ -0,0 +1,411 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+)
+
+// DiffToolName は差分ツールの名前（履歴の再実行と同じく、これ自体は履歴に記録しない）
+const DiffToolName = "ops.diff_results"
+
+// savedQueryTool は saved_query で指定したときに再実行するツール
+const savedQueryTool = "ops.run_saved_query"
+
+const (
+	maxSnapshotBytes = 1 << 20 // これより大きな結果は差分の比較用に保持しない
+	maxListedItems   = 20      // added / removed に載せる件数
+)
+
+// ignoredFields は比べないフィールド（呼び出しごとに変わるもの、別に比べるもの）
+var ignoredFields = map[string]bool{"stats": true, "query_meta": true, "points": true, "freshness": true, "duration_ms": true}
+
+// identityFields は配列の要素を前回と突き合わせるキー（先に見つかったもの）
+var identityFields = []string{"insert_id", "key", "id", "name", "service", "target", "job", "gateway"}
+
+// DiffParams are the parameters for ops.diff_results
+type DiffParams struct {
+	Index      int               `json:"index,omitempty" description:"Index of a previous call to re-run (see ops.recent_queries)"`
+	SavedQuery string            `json:"saved_query,omitempty" description:"Name of a saved query to re-run (see ops.list_saved_queries); its latest run is the baseline"`
+	Params     map[string]string `json:"params,omitempty" description:"Values for the saved query's {{param}} placeholders, used when it has not been run yet"`
+}
+
+// DiffResult is the result of ops.diff_results
+type DiffResult struct {
+	Tool      string           `json:"tool"`
+	Arguments json.RawMessage  `json:"arguments"`
+	Previous  *DiffRun         `json:"previous,omitempty"` // Absent on the first run (the baseline)
+	Current   DiffRun          `json:"current"`
+	Changed   bool             `json:"changed"`
+	Stats     map[string]Delta `json:"stats,omitempty"`    // Changed numbers of the result's stats
+	Sections  []SectionDiff    `json:"sections,omitempty"` // Arrays of the result with changes
+	Note      string           `json:"note,omitempty"`
+}
+
+type DiffRun struct {
+	Index int    `json:"index"` // In ops.recent_queries
+	Time  string `json:"time"`
+}
+
+// SectionDiff is the change of one array of the result (e.g. error_groups, entries, time_series)
+type SectionDiff struct {
+	Path         string       `json:"path"`
+	Added        []any        `json:"added,omitempty"` // New items (e.g. new error groups or log entries)
+	AddedCount   int          `json:"added_count"`
+	Removed      []string     `json:"removed,omitempty"` // Keys of items that are gone
+	RemovedCount int          `json:"removed_count"`
+	Changed      []ItemChange `json:"changed,omitempty"` // Items in both runs whose numbers changed
+	Unchanged    int          `json:"unchanged"`
+}
+
+type ItemChange struct {
+	Key    string           `json:"key"`
+	Fields map[string]Delta `json:"fields"` // e.g. count, points.last, points.mean
+}
+
+type Delta struct {
+	Previous float64 `json:"previous"`
+	Current  float64 `json:"current"`
+	Delta    float64 `json:"delta"`
+}
+
+// DiffTool returns the definition of ops.diff_results
+func DiffTool() mcp.Tool {
+	return mcp.ToolFor[DiffParams](mcp.Tool{
+		Name:         DiffToolName,
+		Description:  "Re-run a previous query (by ops.recent_queries index or saved query name) and return only what changed since its last run: new or gone error groups and log entries, changed counts, and metric deltas (last and mean value per series). Relative time ranges move with the clock, so it suits 'check again in 10 minutes' loops.",
+		OutputSchema: mcp.OutputSchemaFor[DiffResult](),
+	})
+}
+
+// DiffHandler returns a handler for ops.diff_results
+func (r *Recorder) DiffHandler() mcp.ToolHandler {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params DiffParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+		if (params.Index > 0) == (params.SavedQuery != "") {
+			return nil, fmt.Errorf("specify either index or saved_query")
+		}
+
+		// 再実行する呼び出しを決める（保存クエリは最後に実行したときの引数を使う）
+		var tool string
+		var callArgs json.RawMessage
+		if params.Index > 0 {
+			target := r.find(func(e *Entry) bool { return e.Index == params.Index && e.visibleTo(ctx) })
+			if target == nil {
+				return nil, fmt.Errorf("no recorded query with index %d (it may have been evicted)", params.Index)
+			}
+			tool, callArgs = target.Tool, target.Arguments
+		} else {
+			target := r.find(func(e *Entry) bool {
+				return e.visibleTo(ctx) && e.Tool == savedQueryTool && savedQueryName(e.Arguments) == params.SavedQuery
+			})
+			tool = savedQueryTool
+			if target != nil {
+				callArgs = target.Arguments
+			} else {
+				callArgs, _ = json.Marshal(map[string]any{"name": params.SavedQuery, "params": params.Params})
+			}
+		}
+
+		r.mu.Lock()
+		handler := r.handlers[tool]
+		r.mu.Unlock()
+		if handler == nil {
+			return nil, fmt.Errorf("tool %s cannot be re-run", tool)
+		}
+
+		key := canonical(callArgs)
+		sameCall := func(e *Entry) bool {
+			return e.visibleTo(ctx) && e.Tool == tool && canonical(e.Arguments) == key
+		}
+		previous := r.find(func(e *Entry) bool { return sameCall(e) && e.result != nil })
+
+		if _, err := handler(ctx, callArgs); err != nil {
+			return nil, err
+		}
+		current := r.find(func(e *Entry) bool { return sameCall(e) && e.Error == "" })
+		if current == nil {
+			return nil, fmt.Errorf("the re-run of %s was not recorded", tool)
+		}
+
+		result := &DiffResult{
+			Tool:      tool,
+			Arguments: callArgs,
+			Current:   DiffRun{Index: current.Index, Time: current.Time},
+		}
+		switch {
+		case previous == nil:
+			result.Note = "no earlier result of this query to compare with; this run is the baseline, call again later"
+			return result, nil
+		case current.result == nil:
+			result.Previous = &DiffRun{Index: previous.Index, Time: previous.Time}
+			result.Note = fmt.Sprintf("the result is larger than %d bytes and was not kept for comparison; narrow the query", maxSnapshotBytes)
+			return result, nil
+		}
+		result.Previous = &DiffRun{Index: previous.Index, Time: previous.Time}
+		diffResults(result, decode(previous.result), decode(current.result))
+		return result, nil
+	}
+}
+
+// find は条件に合う最新の記録のコピーを返す
+func (r *Recorder) find(match func(e *Entry) bool) *Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		if match(&r.entries[i]) {
+			e := r.entries[i]
+			return &e
+		}
+	}
+	return nil
+}
+
+func savedQueryName(args json.RawMessage) string {
+	var p struct {
+		Name string `json:"name"`
+	}
+	_ = json.Unmarshal(args, &p)
+	return p.Name
+}
+
+// canonical は引数をキー順に並べ直した JSON（同じ呼び出しかの判定用）
+func canonical(args json.RawMessage) string {
+	var v any
+	if err := json.Unmarshal(args, &v); err != nil {
+		return string(args)
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func decode(data []byte) any {
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	_ = dec.Decode(&v)
+	return v
+}
+
+// diffResults は前回と今回の結果を比べ、stats の数値の変化と配列ごとの差分を result に書く
+func diffResults(result *DiffResult, prev, cur any) {
+	prevObj, _ := prev.(map[string]any)
+	curObj, _ := cur.(map[string]any)
+
+	prevStats, _ := prevObj["stats"].(map[string]any)
+	curStats, _ := curObj["stats"].(map[string]any)
+	for name, delta := range numberDeltas(flattenNumbers(prevStats, ""), flattenNumbers(curStats, "")) {
+		if result.Stats == nil {
+			result.Stats = map[string]Delta{}
+		}
+		result.Stats[name] = delta
+	}
+
+	result.Sections = diffObject("", prevObj, curObj)
+	result.Changed = len(result.Stats) > 0 || len(result.Sections) > 0
+}
+
+// diffObject はオブジェクトのフィールドをたどり、配列ごとの差分を返す（変化のない配列は含めない）
+func diffObject(path string, prev, cur map[string]any) []SectionDiff {
+	names := make([]string, 0, len(cur))
+	for name := range cur {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sections := []SectionDiff{}
+	for _, name := range names {
+		if ignoredFields[name] {
+			continue
+		}
+		fieldPath := joinPath(path, name)
+		switch v := cur[name].(type) {
+		case map[string]any:
+			p, _ := prev[name].(map[string]any)
+			sections = append(sections, diffObject(fieldPath, p, v)...)
+		case []any:
+			p, _ := prev[name].([]any)
+			sections = append(sections, diffArray(fieldPath, p, v)...)
+		}
+	}
+	return sections
+}
+
+// diffArray は要素をキーで突き合わせ、増えた・消えた要素と数値の変わった要素を返す
+// 両方にある要素の中の配列（シグナルごとの系列など）も再帰的に比べる
+func diffArray(path string, prev, cur []any) []SectionDiff {
+	prevByKey := map[string]any{}
+	for _, item := range prev {
+		prevByKey[itemKey(item)] = item
+	}
+
+	section := SectionDiff{Path: path}
+	nested := []SectionDiff{}
+	seen := map[string]bool{}
+	for _, item := range cur {
+		key := itemKey(item)
+		seen[key] = true
+		old, ok := prevByKey[key]
+		if !ok {
+			section.AddedCount++
+			if len(section.Added) < maxListedItems {
+				section.Added = append(section.Added, item)
+			}
+			continue
+		}
+		deltas := numberDeltas(itemNumbers(old), itemNumbers(item))
+		if len(deltas) > 0 {
+			section.Changed = append(section.Changed, ItemChange{Key: key, Fields: deltas})
+		} else {
+			section.Unchanged++
+		}
+		curObj, _ := item.(map[string]any)
+		prevObj, _ := old.(map[string]any)
+		if curObj != nil {
+			nested = append(nested, diffObject(joinPath(path, key), prevObj, curObj)...)
+		}
+	}
+	for _, item := range prev {
+		if key := itemKey(item); !seen[key] {
+			seen[key] = true
+			section.RemovedCount++
+			if len(section.Removed) < maxListedItems {
+				section.Removed = append(section.Removed, key)
+			}
+		}
+	}
+
+	sections := []SectionDiff{}
+	if section.AddedCount > 0 || section.RemovedCount > 0 || len(section.Changed) > 0 {
+		sections = append(sections, section)
+	}
+	return append(sections, nested...)
+}
+
+// itemKey は配列の要素を識別する文字列を返す
+// 系列（metric / resource を持つ要素）はラベルの組、それ以外は identityFields のうち最初にあるもの
+func itemKey(item any) string {
+	m, ok := item.(map[string]any)
+	if !ok {
+		b, _ := json.Marshal(item)
+		return string(b)
+	}
+	if metric, ok := m["metric"].(map[string]any); ok {
+		parts := []string{labelsKey(metric)}
+		if resource, ok := m["resource"].(map[string]any); ok {
+			parts = append(parts, labelsKey(resource))
+		}
+		return strings.Join(parts, " ")
+	}
+	for _, field := range identityFields {
+		if s, ok := m[field].(string); ok && s != "" {
+			return s
+		}
+	}
+	// 識別するフィールドがなければ文字列のフィールドの組
+	strs := []string{}
+	for k, v := range m {
+		if s, ok := v.(string); ok {
+			strs = append(strs, k+"="+s)
+		}
+	}
+	sort.Strings(strs)
+	return strings.Join(strs, ",")
+}
+
+// labelsKey は {type, labels} を "type{k=v,...}" にする
+func labelsKey(m map[string]any) string {
+	typ, _ := m["type"].(string)
+	labels, _ := m["labels"].(map[string]any)
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", k, v))
+	}
+	sort.Strings(pairs)
+	return typ + "{" + strings.Join(pairs, ",") + "}"
+}
+
+// itemNumbers は要素の比較する数値: 数値のフィールド（1段下のオブジェクトは "a.b"）と、
+// ポイントの配列（points）の最後の値と平均
+func itemNumbers(item any) map[string]float64 {
+	m, ok := item.(map[string]any)
+	if !ok {
+		return nil
+	}
+	numbers := flattenNumbers(m, "")
+	if points, ok := m["points"].([]any); ok && len(points) > 0 {
+		var sum float64
+		var n int
+		var last float64
+		for _, p := range points {
+			pm, _ := p.(map[string]any)
+			if v, ok := number(pm["value"]); ok {
+				sum += v
+				n++
+				last = v
+			}
+		}
+		if n > 0 {
+			numbers["points.last"] = last
+			numbers["points.mean"] = sum / float64(n)
+		}
+	}
+	return numbers
+}
+
+// flattenNumbers はオブジェクトの数値のフィールドを返す（1段下のオブジェクトまで）
+func flattenNumbers(m map[string]any, prefix string) map[string]float64 {
+	numbers := map[string]float64{}
+	for k, v := range m {
+		if ignoredFields[k] {
+			continue
+		}
+		if n, ok := number(v); ok {
+			numbers[prefix+k] = n
+		} else if nested, ok := v.(map[string]any); ok && prefix == "" {
+			for nk, nv := range flattenNumbers(nested, k+".") {
+				numbers[nk] = nv
+			}
+		}
+	}
+	return numbers
+}
+
+// numberDeltas は値の変わった数値を返す（片方にしかない数値は比べない）
+func numberDeltas(prev, cur map[string]float64) map[string]Delta {
+	deltas := map[string]Delta{}
+	for name, c := range cur {
+		p, ok := prev[name]
+		if ok && p != c {
+			deltas[name] = Delta{Previous: p, Current: c, Delta: c - p}
+		}
+	}
+	if len(deltas) == 0 {
+		return nil
+	}
+	return deltas
+}
+
+func number(v any) (float64, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	f, err := n.Float64()
+	return f, err == nil
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
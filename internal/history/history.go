@@ -0,0 +1,208 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/auth"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+)
+
+// ToolName は履歴ツール自身の名前（履歴には記録しない）
+const ToolName = "ops.recent_queries"
+
+// Entry は1回分のツール呼び出しの記録
+type Entry struct {
+	Index      int             `json:"index"`
+	Tool       string          `json:"tool"`
+	Arguments  json.RawMessage `json:"arguments"`
+	Time       string          `json:"time"`
+	DurationMs int64           `json:"duration_ms"`
+	Error      string          `json:"error,omitempty"`
+	Stats      json.RawMessage `json:"stats,omitempty"` // 結果の stats（あれば）
+	QueryMeta  json.RawMessage `json:"query_meta,omitempty"`
+	ResultSize int             `json:"result_bytes"`
+	Client     string          `json:"client,omitempty"` // HTTP トランスポートの接続元（他の接続元の記録は見せない）
+	session    string          // 記録したセッション（他のセッションの記録は見せない）
+	result     []byte          // ops.diff_results で比べる結果（maxSnapshotBytes 以下のときのみ）
+}
+
+// visibleTo は ctx の接続元・セッションの記録か
+func (e *Entry) visibleTo(ctx context.Context) bool {
+	return e.Client == auth.ClientName(ctx) && e.session == mcp.SessionID(ctx)
+}
+
+// Recorder は直近のツール呼び出しをメモリ上に保持する
+// 記録はセッション（stdio・ソケットや WebSocket の接続、HTTP のセッション）ごとに見せ、
+// 同じサーバーを使う別のセッションの呼び出しは見せない
+type Recorder struct {
+	mu         sync.Mutex
+	maxEntries int
+	nextIndex  int
+	entries    []Entry
+	handlers   map[string]mcp.ToolHandler // 再実行用（読み取りツールのみ）
+}
+
+// NewRecorder は最大 maxEntries 件を保持するRecorderを作成
+func NewRecorder(maxEntries int) *Recorder {
+	if maxEntries <= 0 {
+		maxEntries = 50
+	}
+	return &Recorder{
+		maxEntries: maxEntries,
+		nextIndex:  1,
+		handlers:   map[string]mcp.ToolHandler{},
+	}
+}
+
+// Middleware はツール呼び出しを記録するミドルウェアを返す
+func (r *Recorder) Middleware() mcp.Middleware {
+	return func(tool *mcp.Tool, next mcp.ToolHandler) mcp.ToolHandler {
+		if tool.Name == ToolName || tool.Name == DiffToolName {
+			return next
+		}
+		name := tool.Name
+		handler := func(ctx context.Context, args json.RawMessage) (any, error) {
+			start := time.Now()
+			result, err := next(ctx, args)
+			r.record(ctx, name, args, start, result, err)
+			return result, err
+		}
+		// 書き込みツールは再実行の対象にしない
+		if tool.IsReadOnly() {
+			r.mu.Lock()
+			r.handlers[name] = handler
+			r.mu.Unlock()
+		}
+		return handler
+	}
+}
+
+func (r *Recorder) record(ctx context.Context, tool string, args json.RawMessage, start time.Time, result any, err error) {
+	entry := Entry{
+		Client:     auth.ClientName(ctx),
+		session:    mcp.SessionID(ctx),
+		Tool:       tool,
+		Arguments:  append(json.RawMessage(nil), args...),
+		Time:       start.UTC().Format(time.RFC3339),
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if len(entry.Arguments) == 0 {
+		entry.Arguments = json.RawMessage("{}")
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	} else if b, merr := json.Marshal(result); merr == nil {
+		entry.ResultSize = len(b)
+		// 結果本体は差分の比較用に小さなものだけ保持し、表示するのはメタデータのみ
+		if len(b) <= maxSnapshotBytes {
+			entry.result = b
+		}
+		var meta struct {
+			Stats     json.RawMessage `json:"stats"`
+			QueryMeta json.RawMessage `json:"query_meta"`
+		}
+		if json.Unmarshal(b, &meta) == nil {
+			entry.Stats = meta.Stats
+			entry.QueryMeta = meta.QueryMeta
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry.Index = r.nextIndex
+	r.nextIndex++
+	r.entries = append(r.entries, entry)
+	if len(r.entries) > r.maxEntries {
+		r.entries = r.entries[len(r.entries)-r.maxEntries:]
+	}
+}
+
+// RecentQueriesParams are the parameters for ops.recent_queries
+type RecentQueriesParams struct {
+	Limit      int    `json:"limit"`
+	Tool       string `json:"tool,omitempty"`        // Optional: only this tool
+	RerunIndex int    `json:"rerun_index,omitempty"` // Re-run the entry with this index
+}
+
+// RecentQueriesResult is the result of ops.recent_queries
+type RecentQueriesResult struct {
+	Entries []Entry `json:"entries"` // Newest first
+}
+
+// RerunResult is the result of ops.recent_queries with rerun_index
+type RerunResult struct {
+	Rerun  Entry `json:"rerun"`
+	Result any   `json:"result"`
+}
+
+// Recent は ctx の接続元・セッションの直近の記録を新しい順に返す
+func (r *Recorder) Recent(ctx context.Context, tool string, limit int) []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := []Entry{}
+	for i := len(r.entries) - 1; i >= 0 && len(entries) < limit; i-- {
+		if !r.entries[i].visibleTo(ctx) || tool != "" && r.entries[i].Tool != tool {
+			continue
+		}
+		entries = append(entries, r.entries[i])
+	}
+	return entries
+}
+
+// Rerun は指定したindexの呼び出しを同じ引数で再実行する
+func (r *Recorder) Rerun(ctx context.Context, index int) (*RerunResult, error) {
+	r.mu.Lock()
+	var entry *Entry
+	for i := range r.entries {
+		if r.entries[i].Index == index && r.entries[i].visibleTo(ctx) {
+			e := r.entries[i]
+			entry = &e
+			break
+		}
+	}
+	var handler mcp.ToolHandler
+	if entry != nil {
+		handler = r.handlers[entry.Tool]
+	}
+	r.mu.Unlock()
+
+	if entry == nil {
+		return nil, fmt.Errorf("no recorded query with index %d (it may have been evicted)", index)
+	}
+	if handler == nil {
+		return nil, fmt.Errorf("tool %s cannot be re-run", entry.Tool)
+	}
+
+	result, err := handler(ctx, entry.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	return &RerunResult{Rerun: *entry, Result: result}, nil
+}
+
+// Handler returns a handler for ops.recent_queries
+func (r *Recorder) Handler() func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params RecentQueriesParams
+		if len(args) > 0 {
+			if err := json.Unmarshal(args, &params); err != nil {
+				return nil, fmt.Errorf("failed to parse arguments: %w", err)
+			}
+		}
+
+		if params.RerunIndex > 0 {
+			return r.Rerun(ctx, params.RerunIndex)
+		}
+
+		limit := params.Limit
+		if limit <= 0 {
+			limit = 20
+		}
+		return &RecentQueriesResult{Entries: r.Recent(ctx, params.Tool, limit)}, nil
+	}
+}
@@ -0,0 +1,170 @@
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+)
+
+// 英語以外のメッセージカタログ（<locale>.yaml）
+//
+//go:embed *.yaml
+var catalogs embed.FS
+
+// Catalog translates tool descriptions and error messages into one locale
+type Catalog struct {
+	tools        map[string]string // ツール名 → 説明
+	descriptions []description     // 英語の説明の長い順
+	messages     []message
+}
+
+type description struct {
+	english string
+	text    string
+}
+
+type message struct {
+	match *regexp.Regexp
+	text  string
+}
+
+// catalogFile は <locale>.yaml の形式
+type catalogFile struct {
+	Tools        map[string]string `yaml:"tools"`
+	Descriptions map[string]string `yaml:"descriptions"`
+	Messages     []struct {
+		Match string `yaml:"match"`
+		Text  string `yaml:"text"`
+	} `yaml:"messages"`
+}
+
+// Load returns the catalog of locale, or nil for English (the language of the source)
+func Load(locale string) (*Catalog, error) {
+	if locale == "" || locale == config.LocaleEN {
+		return nil, nil
+	}
+	data, err := catalogs.ReadFile(locale + ".yaml")
+	if err != nil {
+		return nil, fmt.Errorf("no message catalog for locale %q", locale)
+	}
+	var file catalogFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid message catalog %s.yaml: %w", locale, err)
+	}
+
+	c := &Catalog{tools: file.Tools}
+	for english, text := range file.Descriptions {
+		c.descriptions = append(c.descriptions, description{english: english, text: text})
+	}
+	// 長い方を優先する（"Time range for the query" より先に "Time range for the query (default: ...)" など）
+	sort.Slice(c.descriptions, func(i, j int) bool {
+		return len(c.descriptions[i].english) > len(c.descriptions[j].english)
+	})
+	for _, m := range file.Messages {
+		re, err := regexp.Compile(m.Match)
+		if err != nil {
+			return nil, fmt.Errorf("invalid message catalog %s.yaml: match %q: %w", locale, m.Match, err)
+		}
+		c.messages = append(c.messages, message{match: re, text: m.Text})
+	}
+	return c, nil
+}
+
+// Middleware translates the tool description and argument descriptions at registration
+// and error messages at call time. Use it outermost so that it sees what the other middlewares add.
+func (c *Catalog) Middleware() mcp.Middleware {
+	return func(tool *mcp.Tool, next mcp.ToolHandler) mcp.ToolHandler {
+		if text, ok := c.tools[tool.Name]; ok {
+			tool.Description = text
+		}
+		tool.InputSchema.Properties = c.properties(tool.InputSchema.Properties)
+		tool.Examples = c.examples(tool.Examples)
+
+		return func(ctx context.Context, args json.RawMessage) (any, error) {
+			result, err := next(ctx, args)
+			if err != nil {
+				if msg := c.Message(err.Error()); msg != err.Error() {
+					err = &localizedError{msg: msg, err: err}
+				}
+			}
+			return result, err
+		}
+	}
+}
+
+// examples は呼び出し例の説明と結果を訳したコピーを返す（引数は訳さない）
+func (c *Catalog) examples(examples []mcp.ToolExample) []mcp.ToolExample {
+	if examples == nil {
+		return nil
+	}
+	translated := make([]mcp.ToolExample, len(examples))
+	for i, ex := range examples {
+		ex.Description = c.Description(ex.Description)
+		ex.Result = c.Description(ex.Result)
+		translated[i] = ex
+	}
+	return translated
+}
+
+// properties は引数の説明を訳したコピーを返す（プロパティのマップはツール間で共有されていることがある）
+func (c *Catalog) properties(props map[string]mcp.Property) map[string]mcp.Property {
+	if props == nil {
+		return nil
+	}
+	translated := make(map[string]mcp.Property, len(props))
+	for name, prop := range props {
+		prop.Description = c.Description(prop.Description)
+		prop.Properties = c.properties(prop.Properties)
+		if prop.Items != nil {
+			items := *prop.Items
+			items.Description = c.Description(items.Description)
+			items.Properties = c.properties(items.Properties)
+			prop.Items = &items
+		}
+		translated[name] = prop
+	}
+	return translated
+}
+
+// Description translates an argument description. The catalog holds the leading English part;
+// what follows it (e.g. " (default: 60)" or the project aliases) is kept as is.
+func (c *Catalog) Description(s string) string {
+	for _, d := range c.descriptions {
+		rest, ok := strings.CutPrefix(s, d.english)
+		if !ok {
+			continue
+		}
+		// 単語の途中で切れる一致（"Cluster name" と "Cluster names" など）は使わない
+		if rest == "" || strings.ContainsRune(" .,;:(", rune(rest[0])) {
+			return d.text + rest
+		}
+	}
+	return s
+}
+
+// Message translates every part of an error message that the catalog knows, leaving the rest
+// (e.g. GCP API errors) in English
+func (c *Catalog) Message(s string) string {
+	for _, m := range c.messages {
+		s = m.match.ReplaceAllString(s, m.text)
+	}
+	return s
+}
+
+// localizedError は訳したメッセージを返し、元のエラーは errors.Is / As で辿れるようにする
+type localizedError struct {
+	msg string
+	err error
+}
+
+func (e *localizedError) Error() string { return e.msg }
+func (e *localizedError) Unwrap() error { return e.err }
@@ -0,0 +1,162 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/logging/apiv2/loggingpb"
+	monitoredrespb "google.golang.org/genproto/googleapis/api/monitoredres"
+	ltype "google.golang.org/genproto/googleapis/logging/type"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AnnotationLogPrefix は logging.write_entry が書き込むログ名の接頭辞
+// 既存のログに紛れ込まないよう、書き込み先は常にこの名前（または "mcp-annotations.<suffix>"）に固定する
+const AnnotationLogPrefix = "mcp-annotations"
+
+var (
+	// annotationSuffixPattern はログ名の接尾辞（英数字と _ -）
+	annotationSuffixPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+	// annotationSeverities は注釈に使える重大度
+	annotationSeverities = []string{"DEBUG", "INFO", "NOTICE", "WARNING", "ERROR"}
+)
+
+// WriteEntryParams are the parameters for logging.write_entry
+type WriteEntryParams struct {
+	ProjectID    string            `json:"project_id" required:"true" description:"GCP project ID"`
+	Message      string            `json:"message" required:"true" description:"Breadcrumb message (e.g., 'Investigation started: checkout 5xx spike')"`
+	Fields       map[string]any    `json:"fields,omitempty" description:"Structured fields added to json_payload next to message (e.g., {'service': 'checkout', 'hypothesis': 'bad deploy'})"`
+	Labels       map[string]string `json:"labels,omitempty" description:"Entry labels, for filtering with labels.KEY"`
+	Severity     string            `json:"severity,omitempty" enum:"DEBUG,INFO,NOTICE,WARNING,ERROR" default:"NOTICE" description:"Entry severity"`
+	LogSuffix    string            `json:"log_suffix,omitempty" description:"Write to mcp-annotations.<log_suffix> instead of mcp-annotations (letters, digits, '_' and '-')"`
+	ConfirmToken string            `json:"confirm_token,omitempty" description:"Token returned by the preview call. Omit to preview."`
+}
+
+// WriteEntryResult is the result of logging.write_entry
+// ConfirmToken が返った場合は未実行（プレビュー）。同じ引数に confirm_token を付けて再度呼ぶと実行される
+type WriteEntryResult struct {
+	Executed     bool            `json:"executed"`
+	Preview      string          `json:"preview,omitempty"`
+	ConfirmToken string          `json:"confirm_token,omitempty"`
+	Entry        AnnotationEntry `json:"entry"`
+}
+
+// AnnotationEntry is the written (or to-be-written) entry
+type AnnotationEntry struct {
+	LogName     string            `json:"log_name"`
+	Severity    string            `json:"severity"`
+	Timestamp   string            `json:"timestamp,omitempty"`
+	JSONPayload map[string]any    `json:"json_payload"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Filter      string            `json:"filter"` // Filter that finds the annotations with logging.query
+}
+
+// annotationEntry は書き込むエントリ（時刻以外）を組み立てる
+func annotationEntry(params WriteEntryParams) AnnotationEntry {
+	logID := AnnotationLogPrefix
+	if params.LogSuffix != "" {
+		logID += "." + params.LogSuffix
+	}
+	payload := map[string]any{}
+	for k, v := range params.Fields {
+		payload[k] = v
+	}
+	payload["message"] = params.Message
+	logName := fmt.Sprintf("projects/%s/logs/%s", params.ProjectID, logID)
+	return AnnotationEntry{
+		LogName:     logName,
+		Severity:    params.Severity,
+		JSONPayload: payload,
+		Labels:      params.Labels,
+		Filter:      fmt.Sprintf(`logName = "%s"`, logName),
+	}
+}
+
+// WriteEntry writes one annotation entry on the global resource
+func (c *Client) WriteEntry(ctx context.Context, params WriteEntryParams, at time.Time) (*AnnotationEntry, error) {
+	entry := annotationEntry(params)
+	entry.Timestamp = at.Format(time.RFC3339)
+
+	payload, err := structpb.NewStruct(entry.JSONPayload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fields: %w", err)
+	}
+	_, err = c.api.WriteLogEntries(ctx, &loggingpb.WriteLogEntriesRequest{
+		LogName: entry.LogName,
+		Resource: &monitoredrespb.MonitoredResource{
+			Type:   "global",
+			Labels: map[string]string{"project_id": params.ProjectID},
+		},
+		Entries: []*loggingpb.LogEntry{{
+			Timestamp: timestamppb.New(at),
+			Severity:  ltype.LogSeverity(ltype.LogSeverity_value[entry.Severity]),
+			Payload:   &loggingpb.LogEntry_JsonPayload{JsonPayload: payload},
+			Labels:    entry.Labels,
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write log entry: %w", err)
+	}
+	return &entry, nil
+}
+
+// WriteEntryHandlerWithGuardrail returns a handler with guardrail validation and confirmation
+func (c *Client) WriteEntryHandlerWithGuardrail(v WriteValidator) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params WriteEntryParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.Message == "" {
+			return nil, fmt.Errorf("message is required")
+		}
+		if _, ok := params.Fields["message"]; ok {
+			return nil, fmt.Errorf("fields must not contain 'message' (use the message argument)")
+		}
+		if params.LogSuffix != "" && !annotationSuffixPattern.MatchString(params.LogSuffix) {
+			return nil, fmt.Errorf("invalid log_suffix: %s (letters, digits, '_' and '-')", params.LogSuffix)
+		}
+		params.Severity = strings.ToUpper(params.Severity)
+		if params.Severity == "" {
+			params.Severity = "NOTICE"
+		}
+		if !slices.Contains(annotationSeverities, params.Severity) {
+			return nil, fmt.Errorf("unsupported severity: %s (supported: %s)", params.Severity, strings.Join(annotationSeverities, ", "))
+		}
+
+		// 確認トークンは実行内容（トークン以外の引数）に紐づける
+		token := params.ConfirmToken
+		params.ConfirmToken = ""
+		preview := annotationEntry(params)
+
+		// ガードレール: 確認トークンがなければプレビューのみ返す
+		if token == "" {
+			issued, err := v.IssueConfirmToken("logging.write_entry", params)
+			if err != nil {
+				return nil, err
+			}
+			return &WriteEntryResult{
+				Executed:     false,
+				Preview:      fmt.Sprintf("Will write a %s entry to %s. Call again with the same arguments and confirm_token to execute.", preview.Severity, preview.LogName),
+				ConfirmToken: issued,
+				Entry:        preview,
+			}, nil
+		}
+		if err := v.VerifyConfirmToken(token, "logging.write_entry", params); err != nil {
+			return nil, err
+		}
+
+		entry, err := c.WriteEntry(ctx, params, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		return &WriteEntryResult{Executed: true, Entry: *entry}, nil
+	}
+}
@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"context"
+
+	logging "cloud.google.com/go/logging/apiv2"
+	"cloud.google.com/go/logging/apiv2/loggingpb"
+)
+
+// Iterator is the part of the Cloud Logging iterators the tools use
+type Iterator[T any] interface {
+	Next() (T, error)
+}
+
+// API is the Cloud Logging API surface the tools use.
+// It is implemented by the real clients and by the in-memory fake (internal/fake).
+type API interface {
+	ListLogEntries(ctx context.Context, req *loggingpb.ListLogEntriesRequest) Iterator[*loggingpb.LogEntry]
+	ListLogs(ctx context.Context, req *loggingpb.ListLogsRequest) Iterator[string]
+	CreateLogMetric(ctx context.Context, req *loggingpb.CreateLogMetricRequest) (*loggingpb.LogMetric, error)
+	WriteLogEntries(ctx context.Context, req *loggingpb.WriteLogEntriesRequest) (*loggingpb.WriteLogEntriesResponse, error)
+	Close() error
+}
+
+// gcpAPI は実際の Cloud Logging クライアントによる API の実装
+type gcpAPI struct {
+	client        *logging.Client
+	metricsClient *logging.MetricsClient
+}
+
+func (a *gcpAPI) ListLogEntries(ctx context.Context, req *loggingpb.ListLogEntriesRequest) Iterator[*loggingpb.LogEntry] {
+	return a.client.ListLogEntries(ctx, req)
+}
+
+func (a *gcpAPI) ListLogs(ctx context.Context, req *loggingpb.ListLogsRequest) Iterator[string] {
+	return a.client.ListLogs(ctx, req)
+}
+
+func (a *gcpAPI) CreateLogMetric(ctx context.Context, req *loggingpb.CreateLogMetricRequest) (*loggingpb.LogMetric, error) {
+	return a.metricsClient.CreateLogMetric(ctx, req)
+}
+
+func (a *gcpAPI) WriteLogEntries(ctx context.Context, req *loggingpb.WriteLogEntriesRequest) (*loggingpb.WriteLogEntriesResponse, error) {
+	return a.client.WriteLogEntries(ctx, req)
+}
+
+func (a *gcpAPI) Close() error {
+	err := a.metricsClient.Close()
+	if cerr := a.client.Close(); cerr != nil {
+		return cerr
+	}
+	return err
+}
@@ -4,24 +4,46 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	logging "cloud.google.com/go/logging/apiv2"
 	"cloud.google.com/go/logging/apiv2/loggingpb"
 	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	_ "google.golang.org/genproto/googleapis/cloud/audit" // protoPayload の AuditLog を展開するため型を登録する
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/timerange"
 )
 
 // QueryParams are the parameters for logging.query
+// ツールの入力スキーマはタグ（description / default / required）から生成する（mcp.SchemaFor）
 type QueryParams struct {
-	ProjectID string    `json:"project_id"`
-	Filter    string    `json:"filter"`
-	TimeRange TimeRange `json:"time_range"`
-	Limit     int       `json:"limit"`
+	ProjectID string    `json:"project_id" required:"true" description:"GCP project ID"`
+	Filter    string    `json:"filter" description:"Logging Query Language filter (e.g., 'severity>=ERROR')"`
+	TimeRange TimeRange `json:"time_range" description:"Time range for the query"`
+	Limit     int       `json:"limit" default:"200" description:"Maximum number of entries to return (default: 200)"`
+	// filter に severity >= X を付け足す（LQL の書き方を知らなくても絞り込めるように）
+	MinSeverity string `json:"min_severity,omitempty" description:"Only return entries at or above this severity (added to filter as 'severity >= X'); DEFAULT returns all severities"`
+	// 制限付きのログビュー（PII を除いたビュー等）だけを読む場合に指定する
+	ResourceNames []string `json:"resource_names,omitempty" description:"Read through these log views instead of the whole project, e.g. ['projects/my-project/locations/global/buckets/my-bucket/views/my-view']. Must belong to project_id. When allowed_log_views is configured, only those views can be read and they are used by default"`
+	// 除外していたノイズ自体を調べるときは false にする
+	ApplyExcludeFilters *bool `json:"apply_exclude_filters,omitempty" default:"true" description:"AND the configured exclude_filters (known noise such as health checks) into the filter as NOT clauses; set false to see the excluded entries too"`
+	// 障害に至る経緯を追うときは古い順に読む
+	OrderBy string `json:"order_by,omitempty" enum:"timestamp desc,timestamp asc" default:"timestamp desc" description:"timestamp desc: newest first. timestamp asc: oldest first from the start of time_range, e.g. to reconstruct the sequence of events leading up to a crash"`
+	// 構造化ログの平坦化（"a.b.0.c" 形式のキー）と値の切り詰め
+	FlattenJSON    bool `json:"flatten_json,omitempty" default:"false" description:"Flatten nested json_payload into dotted keys (e.g. 'httpRequest.status', 'items.0.id') and truncate long string values; useful with output_format csv"`
+	MaxValueLength int  `json:"max_value_length,omitempty" default:"256" description:"Maximum characters per string value when flatten_json is true (default: 256)"` // flatten_json 時のみ
 }
 
 type TimeRange struct {
-	Start string `json:"start"` // RFC3339 or relative ("-1h", "-30m")
-	End   string `json:"end"`   // RFC3339 or "now"
+	Start string `json:"start" description:"Start time (RFC3339 or relative like '-1h', '-30m')"`
+	End   string `json:"end" default:"now" description:"End time (RFC3339 or 'now')"`
 }
 
 // QueryResult is the result of logging.query
@@ -36,7 +58,16 @@ type QueryMeta struct {
 	Start     string `json:"start"`
 	End       string `json:"end"`
 	Filter    string `json:"filter"`
-	Limit     int    `json:"limit"`
+	// 読んだログビュー（プロジェクト全体なら projects/X）
+	ResourceNames []string `json:"resource_names"`
+	// 実際に適用した重大度の下限（設定のデフォルトを含む）
+	MinSeverity string `json:"min_severity,omitempty"`
+	// resource_rules で付け足した条件
+	Restriction string `json:"restriction,omitempty"`
+	// 付け足した除外条件（logging.exclude_filters）
+	ExcludeFilters []string `json:"exclude_filters,omitempty"`
+	OrderBy        string   `json:"order_by"`
+	Limit          int      `json:"limit"`
 }
 
 type LogEntry struct {
@@ -49,7 +80,9 @@ type LogEntry struct {
 	SpanID      string            `json:"span_id,omitempty"`
 	TextPayload string            `json:"text_payload,omitempty"`
 	JSONPayload map[string]any    `json:"json_payload,omitempty"`
-	InsertID    string            `json:"insert_id"`
+	// 監査ログ（AuditLog）など。型が登録されていないペイロードは省く
+	ProtoPayload map[string]any `json:"proto_payload,omitempty"`
+	InsertID     string         `json:"insert_id"`
 }
 
 type Resource struct {
@@ -58,37 +91,52 @@ type Resource struct {
 }
 
 type ResultStats struct {
-	ReturnedCount int  `json:"returned_count"`
-	Sampled       bool `json:"sampled"`
+	ReturnedCount   int  `json:"returned_count"`
+	Sampled         bool `json:"sampled"`
+	TruncatedValues int  `json:"truncated_values,omitempty"` // flatten_json で切り詰めた値の数
 }
 
 // Client is the Cloud Logging client
 type Client struct {
-	client *logging.Client
+	api API
+
+	allowedViews   []string                                     // allowed_log_views（空 = プロジェクト単位で読む）
+	excludeFilters []string                                     // logging.exclude_filters（既知のノイズ）
+	rulesFor       func(projectID string) []config.ResourceRule // resource_rules
+
+	catalogMu sync.Mutex
+	catalogs  map[string]logCatalog // project → ログ名の一覧（引数の補完用）
 }
 
 // NewClient creates a new Cloud Logging client
-func NewClient(ctx context.Context) (*Client, error) {
-	client, err := logging.NewClient(ctx)
+func NewClient(ctx context.Context, opts ...option.ClientOption) (*Client, error) {
+	client, err := logging.NewClient(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create logging client: %w", err)
 	}
-	return &Client{client: client}, nil
+	metricsClient, err := logging.NewMetricsClient(ctx, opts...)
+	if err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to create log metrics client: %w", err)
+	}
+	return NewClientWithAPI(&gcpAPI{client: client, metricsClient: metricsClient}), nil
+}
+
+// NewClientWithAPI creates a client backed by api (e.g. a fake in tests)
+func NewClientWithAPI(api API) *Client {
+	return &Client{api: api}
 }
 
 // Close closes the client
 func (c *Client) Close() error {
-	return c.client.Close()
+	return c.api.Close()
 }
 
+// maxPageSize は ListLogEntries の1ページの上限（API の制限）
+const maxPageSize = 1000
+
 // Query executes a log query
 func (c *Client) Query(ctx context.Context, params QueryParams) (*QueryResult, error) {
-	// Parse time range
-	startTime, endTime, err := parseTimeRange(params.TimeRange)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse time range: %w", err)
-	}
-
 	// Set default limit
 	limit := params.Limit
 	if limit <= 0 {
@@ -97,28 +145,79 @@ func (c *Client) Query(ctx context.Context, params QueryParams) (*QueryResult, e
 	if limit > 500 {
 		limit = 500
 	}
+	return c.query(ctx, params, limit)
+}
 
-	// Build filter with time range
-	filter := params.Filter
+// QueryAll executes a log query reading up to maxEntries entries, beyond the per-call cap of Query
+// (for exports that write the entries elsewhere instead of returning them to the model)
+func (c *Client) QueryAll(ctx context.Context, params QueryParams, maxEntries int) (*QueryResult, error) {
+	if maxEntries <= 0 {
+		return nil, fmt.Errorf("maxEntries must be positive")
+	}
+	return c.query(ctx, params, maxEntries)
+}
+
+func (c *Client) query(ctx context.Context, params QueryParams, limit int) (*QueryResult, error) {
+	// Parse time range
+	startTime, endTime, err := parseTimeRange(params.TimeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse time range: %w", err)
+	}
+
+	resourceNames, err := c.resourceNames(params.ProjectID, params.ResourceNames)
+	if err != nil {
+		return nil, err
+	}
+
+	minSeverity, err := normalizeSeverity(params.MinSeverity)
+	if err != nil {
+		return nil, err
+	}
+
+	orderBy := params.OrderBy
+	switch orderBy {
+	case "":
+		orderBy = orderNewestFirst
+	case orderNewestFirst, orderOldestFirst:
+	default:
+		return nil, fmt.Errorf("unsupported order_by: %s (supported: %s, %s)", orderBy, orderNewestFirst, orderOldestFirst)
+	}
+
+	var excludes []string
+	if params.ApplyExcludeFilters == nil || *params.ApplyExcludeFilters {
+		excludes = c.excludeFilters
+	}
+
+	// Build filter with excludes, severity and time range
+	filter, restriction := c.withRestriction(params.ProjectID, withExcludes(userFilter(params.Filter), excludes))
 	if filter != "" {
 		filter += " AND "
 	}
+	if minSeverity != "" {
+		filter += fmt.Sprintf("severity >= %s AND ", minSeverity)
+	}
 	filter += fmt.Sprintf(`timestamp >= "%s" AND timestamp <= "%s"`,
 		startTime.Format(time.RFC3339),
 		endTime.Format(time.RFC3339))
 
 	// Create request
 	req := &loggingpb.ListLogEntriesRequest{
-		ResourceNames: []string{fmt.Sprintf("projects/%s", params.ProjectID)},
+		ResourceNames: resourceNames,
 		Filter:        filter,
-		OrderBy:       "timestamp desc",
-		PageSize:      int32(limit),
+		OrderBy:       orderBy,
+		PageSize:      int32(min(limit, maxPageSize)),
 	}
 
 	// Execute query
-	it := c.client.ListLogEntries(ctx, req)
+	it := c.api.ListLogEntries(ctx, req)
+
+	maxValueLength := params.MaxValueLength
+	if maxValueLength <= 0 {
+		maxValueLength = defaultMaxValueLength
+	}
 
 	entries := []LogEntry{}
+	truncated := 0
 	for {
 		entry, err := it.Next()
 		if err == iterator.Done {
@@ -129,6 +228,11 @@ func (c *Client) Query(ctx context.Context, params QueryParams) (*QueryResult, e
 		}
 
 		logEntry := convertLogEntry(entry)
+		if params.FlattenJSON && logEntry.JSONPayload != nil {
+			var n int
+			logEntry.JSONPayload, n = FlattenPayload(logEntry.JSONPayload, maxValueLength)
+			truncated += n
+		}
 		entries = append(entries, logEntry)
 
 		if len(entries) >= limit {
@@ -138,54 +242,51 @@ func (c *Client) Query(ctx context.Context, params QueryParams) (*QueryResult, e
 
 	return &QueryResult{
 		QueryMeta: QueryMeta{
-			ProjectID: params.ProjectID,
-			Start:     startTime.Format(time.RFC3339),
-			End:       endTime.Format(time.RFC3339),
-			Filter:    params.Filter,
-			Limit:     limit,
+			ProjectID:      params.ProjectID,
+			Start:          startTime.Format(time.RFC3339),
+			End:            endTime.Format(time.RFC3339),
+			Filter:         params.Filter,
+			ResourceNames:  resourceNames,
+			MinSeverity:    minSeverity,
+			Restriction:    restriction,
+			ExcludeFilters: excludes,
+			OrderBy:        orderBy,
+			Limit:          limit,
 		},
 		Entries: entries,
 		Stats: ResultStats{
-			ReturnedCount: len(entries),
-			Sampled:       false,
+			ReturnedCount:   len(entries),
+			Sampled:         false,
+			TruncatedValues: truncated,
 		},
 	}, nil
 }
 
-func parseTimeRange(tr TimeRange) (time.Time, time.Time, error) {
-	now := time.Now()
-	var startTime, endTime time.Time
-	var err error
-
-	// Parse end time
-	if tr.End == "" || tr.End == "now" {
-		endTime = now
-	} else {
-		endTime, err = time.Parse(time.RFC3339, tr.End)
-		if err != nil {
-			return time.Time{}, time.Time{}, fmt.Errorf("invalid end time: %w", err)
-		}
-	}
+// order_by の値
+const (
+	orderNewestFirst = "timestamp desc"
+	orderOldestFirst = "timestamp asc"
+)
 
-	// Parse start time
-	switch {
-	case tr.Start == "":
-		startTime = now.Add(-30 * time.Minute) // default: 30 minutes ago
-	case len(tr.Start) > 0 && tr.Start[0] == '-':
-		// Relative time (e.g., "-1h", "-30m")
-		duration, err := time.ParseDuration(tr.Start[1:])
-		if err != nil {
-			return time.Time{}, time.Time{}, fmt.Errorf("invalid relative start time: %w", err)
-		}
-		startTime = now.Add(-duration)
-	default:
-		startTime, err = time.Parse(time.RFC3339, tr.Start)
-		if err != nil {
-			return time.Time{}, time.Time{}, fmt.Errorf("invalid start time: %w", err)
-		}
+// normalizeSeverity は min_severity を検証し、大文字にそろえる（空はそのまま）
+func normalizeSeverity(severity string) (string, error) {
+	if severity == "" {
+		return "", nil
+	}
+	upper := strings.ToUpper(severity)
+	if !slices.Contains(config.LogSeverities, upper) {
+		return "", fmt.Errorf("unsupported min_severity: %s (supported: %s)", severity, strings.Join(config.LogSeverities, ", "))
 	}
+	return upper, nil
+}
 
-	return startTime, endTime, nil
+// hasSeverityFilter は filter が既に重大度で絞り込んでいるか返す
+func hasSeverityFilter(filter string) bool {
+	return strings.Contains(strings.ToLower(filter), "severity")
+}
+
+func parseTimeRange(tr TimeRange) (time.Time, time.Time, error) {
+	return timerange.Parse(tr.Start, tr.End)
 }
 
 func convertLogEntry(entry *loggingpb.LogEntry) LogEntry {
@@ -215,6 +316,8 @@ func convertLogEntry(entry *loggingpb.LogEntry) LogEntry {
 		if p.JsonPayload != nil {
 			le.JSONPayload = structToMap(p.JsonPayload)
 		}
+	case *loggingpb.LogEntry_ProtoPayload:
+		le.ProtoPayload = protoToMap(p.ProtoPayload)
 	}
 
 	return le
@@ -227,6 +330,22 @@ func structToMap(s interface{ AsMap() map[string]any }) map[string]any {
 	return s.AsMap()
 }
 
+// protoToMap は Any のペイロードを JSON と同じ形の map にする（"@type" を含む）
+func protoToMap(a *anypb.Any) map[string]any {
+	if a == nil {
+		return nil
+	}
+	data, err := protojson.Marshal(a)
+	if err != nil {
+		return nil
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil
+	}
+	return m
+}
+
 // QueryHandler returns a handler for the logging.query tool
 func (c *Client) QueryHandler() func(ctx context.Context, args json.RawMessage) (any, error) {
 	return func(ctx context.Context, args json.RawMessage) (any, error) {
@@ -245,42 +364,25 @@ func (c *Client) QueryHandler() func(ctx context.Context, args json.RawMessage)
 
 // Validator はガードレール検証用インターフェース
 type Validator interface {
-	ValidateProjectID(projectID string) error
-	ValidateTimeRange(start, end time.Time) error
-	ClampLogLimit(limit int) int
+	ClampLogLimit(ctx context.Context, limit int) int
 }
 
 // QueryHandlerWithGuardrail returns a handler with guardrail validation
-func (c *Client) QueryHandlerWithGuardrail(v Validator) func(ctx context.Context, args json.RawMessage) (any, error) {
+func (c *Client) QueryHandlerWithGuardrail(v Validator, cfg *config.Config) func(ctx context.Context, args json.RawMessage) (any, error) {
 	return func(ctx context.Context, args json.RawMessage) (any, error) {
 		var params QueryParams
 		if err := json.Unmarshal(args, &params); err != nil {
 			return nil, fmt.Errorf("failed to parse arguments: %w", err)
 		}
 
-		if params.ProjectID == "" {
-			return nil, fmt.Errorf("project_id is required")
-		}
-
-		// ガードレール: プロジェクトID検証
-		if err := v.ValidateProjectID(params.ProjectID); err != nil {
-			return nil, err
-		}
-
-		// 時間範囲のパース
-		startTime, endTime, err := parseTimeRange(params.TimeRange)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse time range: %w", err)
-		}
+		// ガードレール: 件数制限
+		params.Limit = v.ClampLogLimit(ctx, params.Limit)
 
-		// ガードレール: 時間範囲検証
-		if err := v.ValidateTimeRange(startTime, endTime); err != nil {
-			return nil, err
+		// 重大度の指定がなければ設定のデフォルトで絞り込む
+		if params.MinSeverity == "" && !hasSeverityFilter(params.Filter) {
+			params.MinSeverity = cfg.Logging.DefaultMinSeverity
 		}
 
-		// ガードレール: 件数制限
-		params.Limit = v.ClampLogLimit(params.Limit)
-
 		return c.Query(ctx, params)
 	}
 }
@@ -0,0 +1,156 @@
+package logging_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/logging/apiv2/loggingpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/fake"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+)
+
+func TestQueryHandler(t *testing.T) {
+	api := fake.NewLoggingWithFixtures()
+	handler := logging.NewClientWithAPI(api).QueryHandler()
+
+	args := `{"project_id":"` + fake.Project + `","filter":"resource.type=\"cloud_run_revision\"","min_severity":"warning","limit":2,"time_range":{"start":"-1h"}}`
+	got, err := handler(context.Background(), json.RawMessage(args))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := got.(*logging.QueryResult)
+	if len(result.Entries) != 2 || result.Stats.ReturnedCount != 2 {
+		t.Fatalf("got %d entries (returned_count %d), want the limit of 2", len(result.Entries), result.Stats.ReturnedCount)
+	}
+	if result.Entries[0].Timestamp < result.Entries[1].Timestamp {
+		t.Errorf("entries are not newest first: %s, %s", result.Entries[0].Timestamp, result.Entries[1].Timestamp)
+	}
+	if result.QueryMeta.MinSeverity != "WARNING" {
+		t.Errorf("min_severity = %q, want WARNING", result.QueryMeta.MinSeverity)
+	}
+
+	// フェイクはフィルタを評価しないので、API に送ったリクエストを確かめる
+	requests := api.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	req := requests[0].(*loggingpb.ListLogEntriesRequest)
+	if len(req.ResourceNames) != 1 || req.ResourceNames[0] != "projects/"+fake.Project {
+		t.Errorf("resource_names = %v, want [projects/%s]", req.ResourceNames, fake.Project)
+	}
+	for _, want := range []string{`resource.type="cloud_run_revision"`, "severity >= WARNING", "timestamp >= "} {
+		if !strings.Contains(req.Filter, want) {
+			t.Errorf("filter %q does not contain %q", req.Filter, want)
+		}
+	}
+	if req.OrderBy != "timestamp desc" {
+		t.Errorf("order_by = %q, want timestamp desc", req.OrderBy)
+	}
+}
+
+func TestQueryHandlerErrors(t *testing.T) {
+	api := fake.NewLoggingWithFixtures()
+	handler := logging.NewClientWithAPI(api).QueryHandler()
+
+	if _, err := handler(context.Background(), json.RawMessage(`{}`)); err == nil || !strings.Contains(err.Error(), "project_id is required") {
+		t.Errorf("without project_id: err = %v", err)
+	}
+	if _, err := handler(context.Background(), json.RawMessage(`{"project_id":"p","min_severity":"LOUD"}`)); err == nil {
+		t.Error("an unknown min_severity was accepted")
+	}
+
+	api.FailWith("ListLogEntries", status.Error(codes.PermissionDenied, "logging.logEntries.list denied"))
+	_, err := handler(context.Background(), json.RawMessage(`{"project_id":"`+fake.Project+`"}`))
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("err = %v, want the API's PermissionDenied", err)
+	}
+}
+
+func TestResourceRulesSurviveUserFilter(t *testing.T) {
+	const restriction = `resource.type = ("k8s_container") AND NOT resource.labels.namespace_name = "prod"`
+	filters := map[string]string{
+		"trailing comment":     `severity >= ERROR --`,
+		"comment with a quote": "severity >= ERROR -- \"quoted",
+		"unbalanced parens":    `severity >= ERROR) OR (resource.type = "gce_instance"`,
+	}
+	handlers := map[string]func(c *logging.Client) func(context.Context, json.RawMessage) (any, error){
+		"query":      (*logging.Client).QueryHandler,
+		"count":      (*logging.Client).CountHandler,
+		"top_errors": (*logging.Client).TopErrorsHandler,
+	}
+	for handlerName, newHandler := range handlers {
+		for filterName, filter := range filters {
+			t.Run(handlerName+"/"+filterName, func(t *testing.T) {
+				api := fake.NewLoggingWithFixtures()
+				client := logging.NewClientWithAPI(api)
+				client.SetResourceRules(func(string) []config.ResourceRule {
+					return []config.ResourceRule{{
+						ResourceTypes: []string{"k8s_container"},
+						DeniedLabels:  map[string]string{"resource.labels.namespace_name": "prod"},
+					}}
+				})
+				args, _ := json.Marshal(map[string]any{"project_id": fake.Project, "filter": filter, "time_range": map[string]string{"start": "-1h"}})
+				if _, err := newHandler(client)(context.Background(), args); err != nil {
+					t.Fatal(err)
+				}
+
+				// フェイクはフィルタを評価しないので、API と同じくコメントを取り除いたうえで、
+				// ルールと時間範囲が括弧の外（トップレベルの AND）に残っていることを確かめる
+				sent := stripComments(api.Requests()[0].(*loggingpb.ListLogEntriesRequest).Filter)
+				for _, want := range []string{restriction, "timestamp >= ", "timestamp <= "} {
+					i := strings.Index(sent, want)
+					if i < 0 {
+						t.Fatalf("filter %q lost %q", sent, want)
+					}
+					if depth := parenDepth(sent[:i]); depth != 0 {
+						t.Errorf("%q is inside %d parentheses of the filter %q", want, depth, sent)
+					}
+				}
+			})
+		}
+	}
+}
+
+// stripComments は LQL の -- から行末までのコメントを取り除く（文字列の中は除く）
+func stripComments(filter string) string {
+	var b strings.Builder
+	inString := false
+	for i := 0; i < len(filter); i++ {
+		switch {
+		case filter[i] == '"' && (i == 0 || filter[i-1] != '\\'):
+			inString = !inString
+		case !inString && strings.HasPrefix(filter[i:], "--"):
+			for i < len(filter) && filter[i] != '\n' {
+				i++
+			}
+			if i == len(filter) {
+				return b.String()
+			}
+		}
+		b.WriteByte(filter[i])
+	}
+	return b.String()
+}
+
+// parenDepth は filter の末尾での括弧の深さを返す（文字列の中は数えない）
+func parenDepth(filter string) int {
+	depth, inString := 0, false
+	for i := 0; i < len(filter); i++ {
+		switch c := filter[i]; {
+		case c == '"' && (i == 0 || filter[i-1] != '\\'):
+			inString = !inString
+		case inString:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		}
+	}
+	return depth
+}
@@ -0,0 +1,86 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/logging/apiv2/loggingpb"
+	"google.golang.org/api/iterator"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/provider"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/telemetry"
+)
+
+// logCatalogTTL はログ名の一覧を再取得するまでの時間
+const logCatalogTTL = 10 * time.Minute
+
+// logCatalog はプロジェクトのログ名の一覧
+type logCatalog struct {
+	names   []string
+	fetched time.Time
+}
+
+// LogNames returns the names of the logs with entries in the project (through allowed_log_views
+// when configured), e.g. "projects/my-project/logs/run.googleapis.com%2Frequests", cached for 10 minutes
+func (c *Client) LogNames(ctx context.Context, projectID string) ([]string, error) {
+	c.catalogMu.Lock()
+	cached, ok := c.catalogs[projectID]
+	c.catalogMu.Unlock()
+	ok = ok && time.Since(cached.fetched) < logCatalogTTL
+	telemetry.RecordCacheLookup(ctx, "log_catalog", ok)
+	if ok {
+		return cached.names, nil
+	}
+
+	resourceNames, err := c.resourceNames(projectID, nil)
+	if err != nil {
+		return nil, err
+	}
+	it := c.api.ListLogs(ctx, &loggingpb.ListLogsRequest{
+		Parent:        "projects/" + projectID,
+		ResourceNames: resourceNames,
+	})
+	names := []string{}
+	for {
+		name, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list logs: %w", err)
+		}
+		names = append(names, name)
+	}
+
+	c.catalogMu.Lock()
+	if c.catalogs == nil {
+		c.catalogs = map[string]logCatalog{}
+	}
+	c.catalogs[projectID] = logCatalog{names: names, fetched: time.Now()}
+	c.catalogMu.Unlock()
+	return names, nil
+}
+
+// Complete completes log_name from the logs of project_id (see provider.Completer)
+func (p *toolProvider) Complete(ctx context.Context, argument, prefix string, args map[string]string) ([]string, bool, error) {
+	if argument != "log_name" {
+		return nil, false, nil
+	}
+	projectID := p.guard.ResolveProjectID(args["project_id"])
+	if projectID == "" {
+		return []string{}, true, nil
+	}
+	if err := p.guard.ValidateProjectID(ctx, projectID); err != nil {
+		return nil, true, err
+	}
+	client, err := p.client.Get()
+	if err != nil {
+		return nil, true, err
+	}
+	names, err := client.LogNames(ctx, projectID)
+	if err != nil {
+		return nil, true, err
+	}
+	return provider.MatchCompletions(names, prefix), true, nil
+}
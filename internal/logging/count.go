@@ -0,0 +1,158 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/logging/apiv2/loggingpb"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+)
+
+const (
+	// defaultCountScan / maxCountScan は logging.count が数えるエントリの上限
+	defaultCountScan = 20000
+	maxCountScan     = 100000
+	// countFieldMask は件数を数えるのに要るフィールドだけを返させる（gRPC のシステムパラメータ）
+	// エントリ本体（ペイロード）を転送しないので、同じ件数でも logging.query よりずっと軽い
+	countFieldMask = "entries.severity,nextPageToken"
+)
+
+// CountParams are the parameters for logging.count
+type CountParams struct {
+	ProjectID           string    `json:"project_id" required:"true" description:"GCP project ID"`
+	Filter              string    `json:"filter" description:"Logging Query Language filter (e.g., 'httpRequest.status>=500')"`
+	TimeRange           TimeRange `json:"time_range" description:"Time range to count in"`
+	MinSeverity         string    `json:"min_severity,omitempty" description:"Only count entries at or above this severity (added to filter as 'severity >= X'); unlike logging.query, no default is applied"`
+	MaxScan             int       `json:"max_scan,omitempty" default:"20000" description:"Stop counting after this many entries (default: 20000, max: 100000); counts are then lower bounds"`
+	ApplyExcludeFilters *bool     `json:"apply_exclude_filters,omitempty" default:"true" description:"AND the configured exclude_filters (known noise such as health checks) into the filter as NOT clauses; set false to count the excluded entries too"`
+}
+
+// CountResult is the result of logging.count
+type CountResult struct {
+	QueryMeta  CountQueryMeta  `json:"query_meta"`
+	Total      int             `json:"total"`
+	BySeverity []SeverityCount `json:"by_severity"` // Most severe first; only severities with entries
+	Capped     bool            `json:"capped"`      // max_scan was reached; total is a lower bound
+	Note       string          `json:"note,omitempty"`
+}
+
+type CountQueryMeta struct {
+	ProjectID      string   `json:"project_id"`
+	Start          string   `json:"start"`
+	End            string   `json:"end"`
+	Filter         string   `json:"filter"`
+	MinSeverity    string   `json:"min_severity,omitempty"`
+	Restriction    string   `json:"restriction,omitempty"`
+	ExcludeFilters []string `json:"exclude_filters,omitempty"`
+	MaxScan        int      `json:"max_scan"`
+}
+
+type SeverityCount struct {
+	Severity string `json:"severity"`
+	Count    int    `json:"count"`
+}
+
+// Count counts the entries matching the filter per severity, reading only the severity of each entry
+func (c *Client) Count(ctx context.Context, params CountParams) (*CountResult, error) {
+	startTime, endTime, err := parseTimeRange(params.TimeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse time range: %w", err)
+	}
+	resourceNames, err := c.resourceNames(params.ProjectID, nil)
+	if err != nil {
+		return nil, err
+	}
+	minSeverity, err := normalizeSeverity(params.MinSeverity)
+	if err != nil {
+		return nil, err
+	}
+	maxScan := params.MaxScan
+	if maxScan <= 0 {
+		maxScan = defaultCountScan
+	}
+	maxScan = min(maxScan, maxCountScan)
+
+	var excludes []string
+	if params.ApplyExcludeFilters == nil || *params.ApplyExcludeFilters {
+		excludes = c.excludeFilters
+	}
+	filter, restriction := c.withRestriction(params.ProjectID, withExcludes(userFilter(params.Filter), excludes))
+	if filter != "" {
+		filter += " AND "
+	}
+	if minSeverity != "" {
+		filter += fmt.Sprintf("severity >= %s AND ", minSeverity)
+	}
+	filter += fmt.Sprintf(`timestamp >= "%s" AND timestamp <= "%s"`,
+		startTime.Format(time.RFC3339),
+		endTime.Format(time.RFC3339))
+
+	req := &loggingpb.ListLogEntriesRequest{
+		ResourceNames: resourceNames,
+		Filter:        filter,
+		OrderBy:       orderNewestFirst,
+		PageSize:      maxPageSize,
+	}
+	it := c.api.ListLogEntries(metadata.AppendToOutgoingContext(ctx, "x-goog-fieldmask", countFieldMask), req)
+
+	counts := map[string]int{}
+	total := 0
+	for total < maxScan {
+		entry, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate log entries: %w", err)
+		}
+		counts[entry.GetSeverity().String()]++
+		total++
+	}
+	// ちょうど max_scan 件で終わった場合は打ち切りではない
+	capped := false
+	if total >= maxScan {
+		_, err := it.Next()
+		capped = err != iterator.Done
+	}
+
+	result := &CountResult{
+		QueryMeta: CountQueryMeta{
+			ProjectID:      params.ProjectID,
+			Start:          startTime.Format(time.RFC3339),
+			End:            endTime.Format(time.RFC3339),
+			Filter:         params.Filter,
+			MinSeverity:    minSeverity,
+			Restriction:    restriction,
+			ExcludeFilters: excludes,
+			MaxScan:        maxScan,
+		},
+		Total:      total,
+		BySeverity: []SeverityCount{},
+		Capped:     capped,
+	}
+	for i := len(config.LogSeverities) - 1; i >= 0; i-- {
+		if n := counts[config.LogSeverities[i]]; n > 0 {
+			result.BySeverity = append(result.BySeverity, SeverityCount{Severity: config.LogSeverities[i], Count: n})
+		}
+	}
+	if result.Capped {
+		result.Note = fmt.Sprintf("Counting stopped at %d entries, so the counts are lower bounds. Narrow the filter or time_range, raise max_scan (max: %d), or for recurring counts create a log-based metric with logging.create_log_metric.", maxScan, maxCountScan)
+	}
+	return result, nil
+}
+
+// CountHandler returns a handler for the logging.count tool
+func (c *Client) CountHandler() func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params CountParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+		return c.Count(ctx, params)
+	}
+}
@@ -0,0 +1,23 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetExcludeFilters sets the LQL snippets of known noise (logging.exclude_filters)
+// that are ANDed as NOT clauses into every log read of the client
+func (c *Client) SetExcludeFilters(filters []string) {
+	c.excludeFilters = filters
+}
+
+// withExcludes は filter に除外条件（NOT (...)）を付け足す
+func withExcludes(filter string, excludes []string) string {
+	for _, e := range excludes {
+		if filter != "" {
+			filter += " AND "
+		}
+		filter += fmt.Sprintf("NOT (%s)", strings.TrimSpace(e))
+	}
+	return filter
+}
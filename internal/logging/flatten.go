@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+// defaultMaxValueLength は flatten_json 時の値の最大文字数のデフォルト
+const defaultMaxValueLength = 256
+
+// FlattenPayload はネストしたJSONペイロードを "a.b.0.c" 形式のキーに平坦化し、
+// maxLen を超える文字列値を切り詰める。切り詰めた値の数を返す
+func FlattenPayload(payload map[string]any, maxLen int) (map[string]any, int) {
+	flat := map[string]any{}
+	truncated := 0
+
+	var walk func(prefix string, v any)
+	walk = func(prefix string, v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if len(v) == 0 && prefix != "" {
+				flat[prefix] = v
+			}
+			for k, child := range v {
+				walk(joinKey(prefix, k), child)
+			}
+		case []any:
+			if len(v) == 0 {
+				flat[prefix] = v
+			}
+			for i, child := range v {
+				walk(joinKey(prefix, strconv.Itoa(i)), child)
+			}
+		case string:
+			if maxLen > 0 && utf8.RuneCountInString(v) > maxLen {
+				runes := []rune(v)
+				v = fmt.Sprintf("%s...(truncated %d chars)", string(runes[:maxLen]), len(runes)-maxLen)
+				truncated++
+			}
+			flat[prefix] = v
+		default:
+			flat[prefix] = v
+		}
+	}
+	walk("", payload)
+
+	return flat, truncated
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
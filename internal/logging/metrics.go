@@ -0,0 +1,125 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"cloud.google.com/go/logging/apiv2/loggingpb"
+)
+
+// logMetricNamePattern はログベース指標名の制約（英数字と _-.,+!*',()%/、100文字以内）
+var logMetricNamePattern = regexp.MustCompile(`^[A-Za-z0-9_\-.,+!*'()%/]{1,100}$`)
+
+// CreateLogMetricParams are the parameters for logging.create_log_metric
+type CreateLogMetricParams struct {
+	ProjectID    string `json:"project_id" required:"true" description:"GCP project ID"`
+	Name         string `json:"name" required:"true" description:"Metric name (e.g., 'checkout_payment_timeouts')"`
+	Filter       string `json:"filter" required:"true" description:"Cloud Logging filter whose matching entries are counted"`
+	Description  string `json:"description" description:"Metric description"`
+	ConfirmToken string `json:"confirm_token,omitempty" description:"Token returned by the preview call. Omit to preview."`
+}
+
+// CreateLogMetricResult is the result of logging.create_log_metric
+// ConfirmToken が返った場合は未実行（プレビュー）。同じ引数に confirm_token を付けて再度呼ぶと実行される
+type CreateLogMetricResult struct {
+	Executed     bool      `json:"executed"`
+	Preview      string    `json:"preview,omitempty"`
+	ConfirmToken string    `json:"confirm_token,omitempty"`
+	Metric       LogMetric `json:"metric"`
+}
+
+type LogMetric struct {
+	Name        string `json:"name"`
+	MetricType  string `json:"metric_type"` // Use with monitoring.query_time_series / alert policies
+	Filter      string `json:"filter"`
+	Description string `json:"description,omitempty"`
+	CreateTime  string `json:"create_time,omitempty"`
+}
+
+// WriteValidator は書き込みツール用のガードレール検証インターフェース
+type WriteValidator interface {
+	IssueConfirmToken(action string, payload any) (string, error)
+	VerifyConfirmToken(token, action string, payload any) error
+}
+
+// CreateLogMetric creates a counter log-based metric
+func (c *Client) CreateLogMetric(ctx context.Context, params CreateLogMetricParams) (*LogMetric, error) {
+	m, err := c.api.CreateLogMetric(ctx, &loggingpb.CreateLogMetricRequest{
+		Parent: fmt.Sprintf("projects/%s", params.ProjectID),
+		Metric: &loggingpb.LogMetric{
+			Name:        params.Name,
+			Description: params.Description,
+			Filter:      params.Filter,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log metric: %w", err)
+	}
+
+	metric := &LogMetric{
+		Name:        m.GetName(),
+		MetricType:  "logging.googleapis.com/user/" + m.GetName(),
+		Filter:      m.GetFilter(),
+		Description: m.GetDescription(),
+	}
+	if m.GetCreateTime() != nil {
+		metric.CreateTime = m.GetCreateTime().AsTime().Format(time.RFC3339)
+	}
+	return metric, nil
+}
+
+// CreateLogMetricHandlerWithGuardrail returns a handler with guardrail validation and confirmation
+func (c *Client) CreateLogMetricHandlerWithGuardrail(v WriteValidator) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params CreateLogMetricParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.Name == "" {
+			return nil, fmt.Errorf("name is required")
+		}
+		if params.Filter == "" {
+			return nil, fmt.Errorf("filter is required")
+		}
+		if !logMetricNamePattern.MatchString(params.Name) {
+			return nil, fmt.Errorf("invalid metric name: %s", params.Name)
+		}
+
+		// 確認トークンは実行内容（トークン以外の引数）に紐づける
+		token := params.ConfirmToken
+		params.ConfirmToken = ""
+		preview := LogMetric{
+			Name:        params.Name,
+			MetricType:  "logging.googleapis.com/user/" + params.Name,
+			Filter:      params.Filter,
+			Description: params.Description,
+		}
+
+		// ガードレール: 確認トークンがなければプレビューのみ返す
+		if token == "" {
+			issued, err := v.IssueConfirmToken("logging.create_log_metric", params)
+			if err != nil {
+				return nil, err
+			}
+			return &CreateLogMetricResult{
+				Executed:     false,
+				Preview:      fmt.Sprintf("Will create counter metric %s. Call again with the same arguments and confirm_token to execute.", preview.MetricType),
+				ConfirmToken: issued,
+				Metric:       preview,
+			}, nil
+		}
+		if err := v.VerifyConfirmToken(token, "logging.create_log_metric", params); err != nil {
+			return nil, err
+		}
+
+		metric, err := c.CreateLogMetric(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return &CreateLogMetricResult{Executed: true, Metric: *metric}, nil
+	}
+}
@@ -0,0 +1,161 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/guardrail"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/provider"
+)
+
+func init() {
+	provider.Register("logging", newProvider)
+}
+
+// toolProvider は Cloud Logging のツール（logging.*）を提供する
+type toolProvider struct {
+	client *provider.Lazy[*Client]
+	cfg    *config.Config
+	guard  *guardrail.Guardrail
+}
+
+func newProvider(ctx context.Context, env provider.Env) (provider.ToolProvider, error) {
+	return &toolProvider{client: provider.NewLazy(ctx, "logging", func(ctx context.Context) (*Client, error) {
+		client, err := NewClient(ctx, env.GRPCOptionsFor("logging")...)
+		if err != nil {
+			return nil, err
+		}
+		client.SetAllowedLogViews(env.Config.AllowedLogViews)
+		client.SetExcludeFilters(env.Config.Logging.ExcludeFilters)
+		client.SetResourceRules(env.Config.ResourceRulesFor)
+		return client, nil
+	}), cfg: env.Config, guard: env.Guard}, nil
+}
+
+// NewProviderWithClient returns the provider backed by an existing client
+// (e.g. NewClientWithAPI with a fake), for tests
+func NewProviderWithClient(env provider.Env, client *Client) provider.ToolProvider {
+	client.SetAllowedLogViews(env.Config.AllowedLogViews)
+	client.SetExcludeFilters(env.Config.Logging.ExcludeFilters)
+	client.SetResourceRules(env.Config.ResourceRulesFor)
+	return &toolProvider{client: provider.Ready(client), cfg: env.Config, guard: env.Guard}
+}
+
+func (p *toolProvider) Name() string {
+	return "logging"
+}
+
+func (p *toolProvider) RequiredAPIs() []string {
+	return []string{"logging.googleapis.com"}
+}
+
+func (p *toolProvider) Tools() []mcp.Tool {
+	return []mcp.Tool{
+		mcp.ToolFor[QueryParams](mcp.Tool{
+			Name:        "logging.query",
+			Description: "Search Cloud Logging logs. Equivalent to Logs Explorer.",
+			InputSchema: mcp.ToolSchema{
+				Properties: map[string]mcp.Property{
+					"limit": {
+						Type:        "integer",
+						Description: fmt.Sprintf("Maximum number of entries to return (default: 200, max: %d)", p.cfg.Limits.MaxLogEntries),
+						Default:     200,
+					},
+					"min_severity": {
+						Type:        "string",
+						Description: minSeverityDescription(p.cfg.Logging.DefaultMinSeverity),
+						Enum:        config.LogSeverities,
+					},
+					"apply_exclude_filters": {
+						Type:        "boolean",
+						Description: excludeFiltersDescription(p.cfg.Logging.ExcludeFilters),
+						Default:     true,
+					},
+				},
+			},
+			OutputSchema: mcp.OutputSchemaFor[QueryResult](),
+			Examples:     queryExamples,
+		}),
+		mcp.ToolFor[TopErrorsParams](mcp.Tool{
+			Name:         "logging.top_errors",
+			Description:  "Aggregate error logs and return top N most frequent errors. Useful for identifying common issues.",
+			OutputSchema: mcp.OutputSchemaFor[TopErrorsResult](),
+		}),
+		mcp.ToolFor[CountParams](mcp.Tool{
+			Name:         "logging.count",
+			Description:  "Count the log entries matching a filter in a time range, per severity, without returning the entries (e.g. 'how many 500s today'). Only the severity of each entry is transferred; counting stops at max_scan entries.",
+			OutputSchema: mcp.OutputSchemaFor[CountResult](),
+		}),
+		mcp.ToolFor[CreateLogMetricParams](mcp.Tool{
+			Name:         "logging.create_log_metric",
+			Description:  "Create a counter log-based metric from a Cloud Logging filter, e.g. to alert on the filter found during an investigation. Two-step: the first call returns a preview and confirm_token; call again with the same arguments plus confirm_token to execute.",
+			OutputSchema: mcp.OutputSchemaFor[CreateLogMetricResult](),
+			Annotations:  &mcp.ToolAnnotations{ReadOnlyHint: false, DestructiveHint: false},
+		}),
+		mcp.ToolFor[WriteEntryParams](mcp.Tool{
+			Name:         "logging.write_entry",
+			Description:  "Leave a structured breadcrumb (e.g. 'investigation started', a hypothesis or a finding) in Cloud Logging under the mcp-annotations log, so future queries and teammates can find it. Two-step: the first call returns a preview and confirm_token; call again with the same arguments plus confirm_token to execute.",
+			OutputSchema: mcp.OutputSchemaFor[WriteEntryResult](),
+			Annotations:  &mcp.ToolAnnotations{ReadOnlyHint: false, DestructiveHint: false},
+		}),
+	}
+}
+
+// minSeverityDescription は設定のデフォルトを含めた min_severity の説明を返す
+func minSeverityDescription(defaultSeverity string) string {
+	desc := "Only return entries at or above this severity (added to filter as 'severity >= X'); DEFAULT returns all severities"
+	if defaultSeverity != "" {
+		desc += fmt.Sprintf(". When omitted and filter has no severity condition, %s is used", defaultSeverity)
+	}
+	return desc
+}
+
+// excludeFiltersDescription は設定された除外条件を含めた apply_exclude_filters の説明を返す
+func excludeFiltersDescription(filters []string) string {
+	if len(filters) == 0 {
+		return "AND the configured exclude_filters into the filter as NOT clauses (none are configured)"
+	}
+	return fmt.Sprintf("AND the configured exclude_filters (known noise) into the filter as NOT clauses: %s. Set false to see the excluded entries too", strings.Join(filters, "; "))
+}
+
+func (p *toolProvider) Handlers() map[string]mcp.ToolHandler {
+	return map[string]mcp.ToolHandler{
+		"logging.query":             p.client.Handler(func(c *Client) mcp.ToolHandler { return c.QueryHandlerWithGuardrail(p.guard, p.cfg) }),
+		"logging.top_errors":        p.client.Handler(func(c *Client) mcp.ToolHandler { return c.TopErrorsHandler() }),
+		"logging.count":             p.client.Handler(func(c *Client) mcp.ToolHandler { return c.CountHandler() }),
+		"logging.create_log_metric": p.client.Handler(func(c *Client) mcp.ToolHandler { return c.CreateLogMetricHandlerWithGuardrail(p.guard) }),
+		"logging.write_entry":       p.client.Handler(func(c *Client) mcp.ToolHandler { return c.WriteEntryHandlerWithGuardrail(p.guard) }),
+	}
+}
+
+func (p *toolProvider) Close() error {
+	return p.client.Close((*Client).Close)
+}
+
+// queryExamples は logging.query の呼び出し例（LQL の書き方と min_severity・order_by の使い分け）
+var queryExamples = []mcp.ToolExample{
+	{
+		Description: "Errors of a Cloud Run service in the last hour",
+		Arguments: map[string]any{
+			"project_id":   "my-project",
+			"filter":       `resource.type="cloud_run_revision" AND resource.labels.service_name="api"`,
+			"min_severity": "ERROR",
+			"time_range":   map[string]any{"start": "-1h"},
+			"limit":        50,
+		},
+		Result: "the newest 50 entries at ERROR or above",
+	},
+	{
+		Description: "What happened before a pod crashed",
+		Arguments: map[string]any{
+			"project_id": "my-project",
+			"filter":     `resource.type="k8s_container" AND resource.labels.pod_name="api-7d9f8b6c4-x2k5p"`,
+			"time_range": map[string]any{"start": "2025-01-15T09:50:00Z", "end": "2025-01-15T10:05:00Z"},
+			"order_by":   "timestamp asc",
+		},
+		Result: "the entries of the pod oldest first from the start of the range",
+	},
+}
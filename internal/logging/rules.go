@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+)
+
+// SetResourceRules sets the lookup of resource_rules; every log read of a matching project
+// is restricted to the allowed resource types and excludes the denied labels
+func (c *Client) SetResourceRules(rulesFor func(projectID string) []config.ResourceRule) {
+	c.rulesFor = rulesFor
+}
+
+// restriction はプロジェクトのリソースルールを LQL の条件にする（ルールがなければ空）
+func (c *Client) restriction(projectID string) string {
+	if c.rulesFor == nil {
+		return ""
+	}
+	var conds []string
+	for _, r := range c.rulesFor(projectID) {
+		if len(r.ResourceTypes) > 0 {
+			types := make([]string, len(r.ResourceTypes))
+			for i, t := range r.ResourceTypes {
+				types[i] = strconv.Quote(t)
+			}
+			conds = append(conds, "resource.type = ("+strings.Join(types, " OR ")+")")
+		}
+		keys := make([]string, 0, len(r.DeniedLabels))
+		for k := range r.DeniedLabels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			conds = append(conds, "NOT "+k+" = "+strconv.Quote(r.DeniedLabels[k]))
+		}
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// userFilter は呼び出し元の filter を改行と括弧で囲む。末尾の -- コメントや対応しない括弧で、
+// 後から付け足す除外条件・リソースルール・重大度・時間範囲が無効になったり組み変わったりしないように
+func userFilter(filter string) string {
+	if strings.TrimSpace(filter) == "" {
+		return ""
+	}
+	return "(\n" + filter + "\n)"
+}
+
+// withRestriction は filter にプロジェクトのリソースルールの条件を付け足す
+func (c *Client) withRestriction(projectID, filter string) (string, string) {
+	restriction := c.restriction(projectID)
+	if restriction == "" {
+		return filter, ""
+	}
+	if filter != "" {
+		filter += " AND "
+	}
+	return filter + restriction, restriction
+}
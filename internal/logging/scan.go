@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/logging/apiv2/loggingpb"
+	"google.golang.org/api/iterator"
+)
+
+// ScanEntries はフィルタに一致するエントリを最大maxEntries件まで走査し、fnに渡す
+// 集計系ツール（クライアント側でグルーピングする thin集計）向け。走査件数を返す
+func (c *Client) ScanEntries(ctx context.Context, projectID, filter string, start, end time.Time, maxEntries int, fn func(LogEntry)) (int, error) {
+	resourceNames, err := c.resourceNames(projectID, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	filter, _ = c.withRestriction(projectID, withExcludes(userFilter(filter), c.excludeFilters))
+	if filter != "" {
+		filter += " AND "
+	}
+	filter += fmt.Sprintf(`timestamp >= "%s" AND timestamp <= "%s"`,
+		start.Format(time.RFC3339),
+		end.Format(time.RFC3339))
+
+	req := &loggingpb.ListLogEntriesRequest{
+		ResourceNames: resourceNames,
+		Filter:        filter,
+		OrderBy:       "timestamp desc",
+		PageSize:      int32(min(maxEntries, 1000)),
+	}
+
+	it := c.api.ListLogEntries(ctx, req)
+
+	scanned := 0
+	for scanned < maxEntries {
+		entry, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return scanned, fmt.Errorf("failed to iterate log entries: %w", err)
+		}
+		scanned++
+		fn(convertLogEntry(entry))
+	}
+	return scanned, nil
+}
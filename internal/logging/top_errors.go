@@ -13,10 +13,11 @@ import (
 
 // TopErrorsParams are the parameters for logging.top_errors
 type TopErrorsParams struct {
-	ProjectID string    `json:"project_id"`
-	TimeRange TimeRange `json:"time_range"`
-	GroupBy   string    `json:"group_by"` // "log_name", "message", "resource_type"
-	Limit     int       `json:"limit"`    // Top N errors to return
+	ProjectID string    `json:"project_id" required:"true" description:"GCP project ID"`
+	TimeRange TimeRange `json:"time_range" description:"Time range for the query"`
+	GroupBy   string    `json:"group_by" enum:"log_name,resource_type,message" default:"log_name" description:"How to group errors: 'log_name', 'resource_type', or 'message' (default: 'log_name')"`
+	Limit     int       `json:"limit" default:"10" description:"Number of top error groups to return (default: 10, max: 50)"`
+	Filter    string    `json:"filter,omitempty" description:"Optional Logging Query Language filter ANDed to the error filter (e.g., 'resource.labels.service_name=checkout')"`
 }
 
 // TopErrorsResult is the result of logging.top_errors
@@ -31,6 +32,7 @@ type TopErrorsQueryMeta struct {
 	Start     string `json:"start"`
 	End       string `json:"end"`
 	GroupBy   string `json:"group_by"`
+	Filter    string `json:"filter,omitempty"`
 }
 
 type ErrorGroup struct {
@@ -56,6 +58,11 @@ func (c *Client) TopErrors(ctx context.Context, params TopErrorsParams) (*TopErr
 		return nil, fmt.Errorf("failed to parse time range: %w", err)
 	}
 
+	resourceNames, err := c.resourceNames(params.ProjectID, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	// Set defaults
 	limit := params.Limit
 	if limit <= 0 {
@@ -74,17 +81,21 @@ func (c *Client) TopErrors(ctx context.Context, params TopErrorsParams) (*TopErr
 	filter := fmt.Sprintf(`severity >= ERROR AND timestamp >= "%s" AND timestamp <= "%s"`,
 		startTime.Format(time.RFC3339),
 		endTime.Format(time.RFC3339))
+	if params.Filter != "" {
+		filter += " AND " + userFilter(params.Filter)
+	}
+	filter, _ = c.withRestriction(params.ProjectID, withExcludes(filter, c.excludeFilters))
 
 	// Create request - fetch more entries to get good aggregation
 	req := &loggingpb.ListLogEntriesRequest{
-		ResourceNames: []string{fmt.Sprintf("projects/%s", params.ProjectID)},
+		ResourceNames: resourceNames,
 		Filter:        filter,
 		OrderBy:       "timestamp desc",
 		PageSize:      1000, // Scan up to 1000 entries for aggregation
 	}
 
 	// Execute query and aggregate
-	it := c.client.ListLogEntries(ctx, req)
+	it := c.api.ListLogEntries(ctx, req)
 
 	groups := make(map[string]*errorGroupBuilder)
 	scannedCount := 0
@@ -163,6 +174,7 @@ func (c *Client) TopErrors(ctx context.Context, params TopErrorsParams) (*TopErr
 			Start:     startTime.Format(time.RFC3339),
 			End:       endTime.Format(time.RFC3339),
 			GroupBy:   groupBy,
+			Filter:    params.Filter,
 		},
 		ErrorGroups: errorGroups,
 		Stats: TopErrorsStats{
@@ -212,42 +224,6 @@ func (c *Client) TopErrorsHandler() func(ctx context.Context, args json.RawMessa
 			return nil, fmt.Errorf("failed to parse arguments: %w", err)
 		}
 
-		if params.ProjectID == "" {
-			return nil, fmt.Errorf("project_id is required")
-		}
-
-		return c.TopErrors(ctx, params)
-	}
-}
-
-// TopErrorsHandlerWithGuardrail returns a handler with guardrail validation
-func (c *Client) TopErrorsHandlerWithGuardrail(v Validator) func(ctx context.Context, args json.RawMessage) (any, error) {
-	return func(ctx context.Context, args json.RawMessage) (any, error) {
-		var params TopErrorsParams
-		if err := json.Unmarshal(args, &params); err != nil {
-			return nil, fmt.Errorf("failed to parse arguments: %w", err)
-		}
-
-		if params.ProjectID == "" {
-			return nil, fmt.Errorf("project_id is required")
-		}
-
-		// ガードレール: プロジェクトID検証
-		if err := v.ValidateProjectID(params.ProjectID); err != nil {
-			return nil, err
-		}
-
-		// 時間範囲のパース
-		startTime, endTime, err := parseTimeRange(params.TimeRange)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse time range: %w", err)
-		}
-
-		// ガードレール: 時間範囲検証
-		if err := v.ValidateTimeRange(startTime, endTime); err != nil {
-			return nil, err
-		}
-
 		return c.TopErrors(ctx, params)
 	}
 }
@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+)
+
+// SetAllowedLogViews restricts every log read of the client to the given log views
+// (allowed_log_views). Reads that do not name views use the allowed views of the project.
+func (c *Client) SetAllowedLogViews(views []string) {
+	c.allowedViews = views
+}
+
+// resourceNames は ListLogEntries の resource_names を決める
+// 指定された名前は project_id のプロジェクトのもの（プロジェクトの許可判定をすり抜けない）に限り、
+// allowed_log_views があればその中のビューに限る。指定がなければプロジェクト
+// （allowed_log_views があればそのプロジェクトの許可ビューすべて）を読む
+func (c *Client) resourceNames(projectID string, requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		if len(c.allowedViews) == 0 {
+			return []string{"projects/" + projectID}, nil
+		}
+		prefix := "projects/" + projectID + "/"
+		var views []string
+		for _, v := range c.allowedViews {
+			if strings.HasPrefix(v, prefix) {
+				views = append(views, v)
+			}
+		}
+		if len(views) == 0 {
+			return nil, fmt.Errorf("logs of project '%s' can only be read through allowed_log_views, and none is configured for it", projectID)
+		}
+		return views, nil
+	}
+
+	for _, name := range requested {
+		project, isView := config.LogViewProject(name)
+		switch {
+		case !isView && name != "projects/"+projectID:
+			return nil, fmt.Errorf("unsupported resource name: %s (use projects/%s or projects/%s/locations/LOCATION/buckets/BUCKET/views/VIEW)", name, projectID, projectID)
+		case isView && project != projectID:
+			return nil, fmt.Errorf("resource name %s is not in project '%s'", name, projectID)
+		case len(c.allowedViews) > 0 && !slices.Contains(c.allowedViews, name):
+			return nil, fmt.Errorf("resource name %s is not in allowed_log_views", name)
+		}
+	}
+	return requested, nil
+}
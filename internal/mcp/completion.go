@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// CompletionsCapability advertises argument autocompletion (completion/complete)
+type CompletionsCapability struct{}
+
+// CompleteParams are the params of completion/complete
+type CompleteParams struct {
+	Ref      CompletionRef      `json:"ref"`
+	Argument CompletionArgument `json:"argument"`
+	Context  *CompletionContext `json:"context,omitempty"`
+}
+
+// CompletionRef is what the argument belongs to ("ref/prompt" or "ref/resource" in the spec)
+type CompletionRef struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+	URI  string `json:"uri,omitempty"`
+}
+
+// CompletionArgument is the argument being completed and what has been typed so far
+type CompletionArgument struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CompletionContext holds the arguments already filled in (e.g. project_id)
+type CompletionContext struct {
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+type CompleteResult struct {
+	Completion Completion `json:"completion"`
+}
+
+type Completion struct {
+	Values  []string `json:"values"`
+	Total   int      `json:"total,omitempty"`
+	HasMore bool     `json:"hasMore,omitempty"`
+}
+
+// maxCompletionValues is the most values one completion/complete response may carry (MCP spec)
+const maxCompletionValues = 100
+
+// CompletionProvider serves completion/complete.
+// It returns the candidate values of the argument (all of them; the server truncates the response).
+type CompletionProvider interface {
+	Complete(ctx context.Context, params CompleteParams) ([]string, error)
+}
+
+// SetCompletionProvider enables the completions capability backed by p
+func (s *Server) SetCompletionProvider(p CompletionProvider) {
+	s.completions = p
+}
+
+func (s *Server) handleComplete(ctx context.Context, req *Request) *Response {
+	var params CompleteParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return &Response{JSONRPC: "2.0", ID: req.ID, Error: &Error{Code: -32602, Message: "Invalid params", Data: err.Error()}}
+	}
+
+	values, err := s.completions.Complete(ctx, params)
+	if err != nil {
+		return &Response{JSONRPC: "2.0", ID: req.ID, Error: &Error{Code: -32603, Message: "Completion failed", Data: err.Error()}}
+	}
+	completion := Completion{Values: values, Total: len(values)}
+	if completion.Values == nil {
+		completion.Values = []string{}
+	}
+	if len(values) > maxCompletionValues {
+		completion.Values, completion.HasMore = values[:maxCompletionValues], true
+	}
+	return &Response{JSONRPC: "2.0", ID: req.ID, Result: CompleteResult{Completion: completion}}
+}
@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// recentIDsLimit is how many request IDs are remembered per session.
+// Clients number requests sequentially, so a retransmission is always among the latest ones.
+const recentIDsLimit = 1024
+
+// recentIDs remembers the latest request IDs of a session so that a retransmitted request
+// (a flaky client retrying after a timeout with the same ID) is rejected instead of re-run.
+// JSON-RPC requires request IDs to be unique within a session.
+type recentIDs struct {
+	mu    sync.Mutex
+	seen  map[string]struct{}
+	order []string // Ring buffer of the keys in seen, oldest at next
+	next  int
+}
+
+func newRecentIDs() *recentIDs {
+	return &recentIDs{seen: make(map[string]struct{})}
+}
+
+// add records id and reports whether it was already recorded
+func (r *recentIDs) add(id any) bool {
+	key := idKey(id)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.seen[key]; ok {
+		return true
+	}
+	if len(r.order) < recentIDsLimit {
+		r.order = append(r.order, key)
+	} else {
+		delete(r.seen, r.order[r.next])
+		r.order[r.next] = key
+		r.next = (r.next + 1) % recentIDsLimit
+	}
+	r.seen[key] = struct{}{}
+	return false
+}
+
+// reset forgets all IDs: a new initialize starts a new session, which may number requests from the start again
+func (r *recentIDs) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seen = make(map[string]struct{})
+	r.order = nil
+	r.next = 0
+}
+
+// idKey keeps the type of the ID so that 1 and "1" are different IDs
+func idKey(id any) string {
+	data, err := json.Marshal(id)
+	if err != nil {
+		return fmt.Sprint(id)
+	}
+	return string(data)
+}
+
+// checkDuplicate returns an error response if req reuses the ID of an earlier request of
+// the session in ctx. Requests without a session (HTTP without a session ID) are not checked.
+func checkDuplicate(ctx context.Context, req *Request) *Response {
+	sess := sessionFrom(ctx)
+	if sess == nil || req.ID == nil {
+		return nil
+	}
+	ids := sess.ids
+	if req.Method == "initialize" {
+		ids.reset()
+	}
+	if !ids.add(req.ID) {
+		return nil
+	}
+	return &Response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Error: &Error{
+			Code:    -32600,
+			Message: "Invalid Request",
+			Data:    fmt.Sprintf("duplicate request id %s: a request with this id was already received in this session and is not run again (send a new id to run it again)", idKey(req.ID)),
+		},
+	}
+}
@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// ToolExample is a sample call of a tool listed with its description.
+// Sample arguments with the result they give measurably improve the accuracy of tool calls
+// with intricate arguments (aggregations, filters) over the argument descriptions alone.
+type ToolExample struct {
+	Description string         // What the call answers, e.g. "Request rate per Cloud Run service"
+	Arguments   map[string]any // Sample arguments
+	Result      string         // Summary of the expected result
+}
+
+// withExamples appends the examples to a tool description, one per line
+// ("- <description>: <arguments as JSON> → <result>").
+// Clients hand tool descriptions to the model as is, while extension fields are often dropped.
+func withExamples(description string, examples []ToolExample) string {
+	if len(examples) == 0 {
+		return description
+	}
+	var b strings.Builder
+	b.WriteString(description)
+	b.WriteString("\n\nExamples:")
+	for _, ex := range examples {
+		// Keep < > & of filters readable instead of \u003c ...
+		var args bytes.Buffer
+		enc := json.NewEncoder(&args)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(ex.Arguments); err != nil {
+			continue
+		}
+		b.WriteString("\n- ")
+		if ex.Description != "" {
+			b.WriteString(ex.Description)
+			b.WriteString(": ")
+		}
+		b.Write(bytes.TrimSpace(args.Bytes()))
+		if ex.Result != "" {
+			b.WriteString(" → ")
+			b.WriteString(ex.Result)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// framing is how JSON-RPC messages are delimited on stdio.
+type framing int
+
+const (
+	framingLine   framing = iota // One message per line (MCP stdio transport)
+	framingHeader                // LSP-style "Content-Length: N\r\n\r\n" headers
+)
+
+// maxMessageBytes bounds a single message so that a bogus Content-Length cannot exhaust memory.
+// Newline-delimited messages have no line length limit other than this one.
+const maxMessageBytes = 64 << 20
+
+const contentLengthHeader = "content-length:"
+
+// message is one framed JSON-RPC message (a request, a notification or a batch).
+type message struct {
+	data    []byte
+	framing framing
+}
+
+// readMessage reads the next message, detecting the framing per message:
+// a line starting with Content-Length starts a header block, anything else is a whole message.
+// Blank lines between messages are skipped.
+func readMessage(r *bufio.Reader) (*message, error) {
+	for {
+		line, err := readLine(r)
+		if err != nil && (err != io.EOF || len(line) == 0) {
+			return nil, err
+		}
+		trimmed := bytes.TrimSpace(line)
+		if len(trimmed) == 0 {
+			if err == io.EOF {
+				return nil, err
+			}
+			continue
+		}
+
+		if !strings.HasPrefix(strings.ToLower(string(trimmed)), contentLengthHeader) {
+			return &message{data: trimmed, framing: framingLine}, nil
+		}
+
+		length, err := strconv.Atoi(strings.TrimSpace(string(trimmed[len(contentLengthHeader):])))
+		if err != nil || length < 0 {
+			return nil, fmt.Errorf("invalid Content-Length header: %q", trimmed)
+		}
+		if length > maxMessageBytes {
+			return nil, fmt.Errorf("message of %d bytes exceeds the limit of %d bytes", length, maxMessageBytes)
+		}
+		// Skip the remaining headers (e.g. Content-Type) up to the empty line
+		for {
+			header, err := readLine(r)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read message headers: %w", err)
+			}
+			if len(bytes.TrimSpace(header)) == 0 {
+				break
+			}
+		}
+		data := make([]byte, length)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, fmt.Errorf("failed to read message body: %w", err)
+		}
+		return &message{data: data, framing: framingHeader}, nil
+	}
+}
+
+// readLine reads up to and including '\n' without a line length limit (unlike bufio.Scanner).
+func readLine(r *bufio.Reader) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		line = append(line, chunk...)
+		if len(line) > maxMessageBytes {
+			return nil, fmt.Errorf("line exceeds the limit of %d bytes", maxMessageBytes)
+		}
+		if err != bufio.ErrBufferFull {
+			return line, err
+		}
+	}
+}
+
+// frame encodes data for writing with the given framing.
+func frame(data []byte, f framing) []byte {
+	if f == framingHeader {
+		header := fmt.Sprintf("Content-Length: %d\r\n\r\n", len(data))
+		return append([]byte(header), data...)
+	}
+	return append(data, '\n')
+}
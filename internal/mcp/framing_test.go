@@ -0,0 +1,206 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReadMessage(t *testing.T) {
+	long := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"logging.query","arguments":{"filter":"` + strings.Repeat("x", 100<<10) + `"}}}`
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		framing []framing
+	}{
+		{
+			name:    "newline delimited",
+			input:   "{\"id\":1}\n{\"id\":2}\n",
+			want:    []string{`{"id":1}`, `{"id":2}`},
+			framing: []framing{framingLine, framingLine},
+		},
+		{
+			name:    "last line without newline",
+			input:   `{"id":1}`,
+			want:    []string{`{"id":1}`},
+			framing: []framing{framingLine},
+		},
+		{
+			name:    "Content-Length",
+			input:   "Content-Length: 8\r\n\r\n{\"id\":1}",
+			want:    []string{`{"id":1}`},
+			framing: []framing{framingHeader},
+		},
+		{
+			name:    "Content-Length with other headers, any case",
+			input:   "content-length: 8\r\nContent-Type: application/vscode-jsonrpc; charset=utf-8\r\n\r\n{\"id\":1}",
+			want:    []string{`{"id":1}`},
+			framing: []framing{framingHeader},
+		},
+		{
+			name:    "framing detected per message",
+			input:   "{\"id\":1}\nContent-Length: 8\r\n\r\n{\"id\":2}\n\n{\"id\":3}\n",
+			want:    []string{`{"id":1}`, `{"id":2}`, `{"id":3}`},
+			framing: []framing{framingLine, framingHeader, framingLine},
+		},
+		{
+			name:    "blank lines skipped",
+			input:   "\n\r\n  \n{\"id\":1}\n\n",
+			want:    []string{`{"id":1}`},
+			framing: []framing{framingLine},
+		},
+		{
+			name:    "line over 64KB",
+			input:   long + "\n" + `{"id":2}` + "\n",
+			want:    []string{long, `{"id":2}`},
+			framing: []framing{framingLine, framingLine},
+		},
+		{
+			name:    "Content-Length body over 64KB",
+			input:   fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(long), long),
+			want:    []string{long},
+			framing: []framing{framingHeader},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A small buffer so that long lines span several reads
+			r := bufio.NewReaderSize(strings.NewReader(tt.input), 4096)
+			for i, want := range tt.want {
+				msg, err := readMessage(r)
+				if err != nil {
+					t.Fatalf("message %d: %v", i, err)
+				}
+				if string(msg.data) != want {
+					t.Errorf("message %d = %.60q..., want %.60q...", i, msg.data, want)
+				}
+				if msg.framing != tt.framing[i] {
+					t.Errorf("message %d framing = %d, want %d", i, msg.framing, tt.framing[i])
+				}
+			}
+			if _, err := readMessage(r); err != io.EOF {
+				t.Errorf("after the last message: err = %v, want io.EOF", err)
+			}
+		})
+	}
+}
+
+func TestReadMessageErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"truncated Content-Length body", "Content-Length: 20\r\n\r\n{\"id\":1}", "failed to read message body"},
+		{"headers without end", "Content-Length: 8\r\n", "failed to read message headers"},
+		{"invalid Content-Length", "Content-Length: abc\r\n\r\n", "invalid Content-Length header"},
+		{"negative Content-Length", "Content-Length: -1\r\n\r\n", "invalid Content-Length header"},
+		{"Content-Length over the limit", fmt.Sprintf("Content-Length: %d\r\n\r\n", maxMessageBytes+1), "exceeds the limit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readMessage(bufio.NewReader(strings.NewReader(tt.input)))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFrameRoundTrip(t *testing.T) {
+	data := []byte(`{"jsonrpc":"2.0","id":1,"result":{}}`)
+	for _, f := range []framing{framingLine, framingHeader} {
+		msg, err := readMessage(bufio.NewReader(strings.NewReader(string(frame(append([]byte{}, data...), f)))))
+		if err != nil {
+			t.Fatalf("framing %d: %v", f, err)
+		}
+		if string(msg.data) != string(data) || msg.framing != f {
+			t.Errorf("framing %d: got %q (framing %d)", f, msg.data, msg.framing)
+		}
+	}
+}
+
+func TestProcessBatch(t *testing.T) {
+	s := NewServer("test", "0.0.0")
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		input   string
+		wantIDs []float64 // IDs of the responses in order; nil when no response is expected
+		wantErr int       // Code of a single error response instead of an array
+	}{
+		{
+			name:    "requests and notifications",
+			input:   `[{"jsonrpc":"2.0","id":1,"method":"ping"},{"jsonrpc":"2.0","method":"notifications/initialized"},{"jsonrpc":"2.0","id":2,"method":"ping"}]`,
+			wantIDs: []float64{1, 2},
+		},
+		{
+			name:  "notifications only",
+			input: `[{"jsonrpc":"2.0","method":"notifications/initialized"},{"jsonrpc":"2.0","method":"notifications/cancelled"}]`,
+		},
+		{
+			name:    "unknown method and invalid item",
+			input:   `[{"jsonrpc":"2.0","id":1,"method":"no/such"},{"id":2},1]`,
+			wantIDs: []float64{1, 0, 0},
+		},
+		{
+			name:    "empty batch",
+			input:   `[]`,
+			wantErr: -32600,
+		},
+		{
+			name:    "malformed batch",
+			input:   `[{"jsonrpc":"2.0","id":1,"method":"ping"}`,
+			wantErr: -32700,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := s.processMessage(ctx, ctx, []byte(tt.input))
+			if tt.wantErr != 0 {
+				resp, ok := out.(*Response)
+				if !ok || resp.Error == nil || resp.Error.Code != tt.wantErr {
+					t.Fatalf("got %#v, want a single error response with code %d", out, tt.wantErr)
+				}
+				return
+			}
+			if tt.wantIDs == nil {
+				if out != nil {
+					t.Fatalf("got %#v, want no response", out)
+				}
+				return
+			}
+
+			data, err := json.Marshal(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var responses []struct {
+				ID    float64 `json:"id"`
+				Error *Error  `json:"error"`
+			}
+			if err := json.Unmarshal(data, &responses); err != nil {
+				t.Fatalf("response is not an array: %s", data)
+			}
+			if len(responses) != len(tt.wantIDs) {
+				t.Fatalf("got %d responses, want %d: %s", len(responses), len(tt.wantIDs), data)
+			}
+			for i, resp := range responses {
+				if resp.ID != tt.wantIDs[i] {
+					t.Errorf("response %d id = %v, want %v", i, resp.ID, tt.wantIDs[i])
+				}
+				// Responses without an ID are the errors of invalid items
+				if resp.ID == 0 && (resp.Error == nil || resp.Error.Code != -32600) {
+					t.Errorf("response %d = %s, want -32600 Invalid Request", i, data)
+				}
+			}
+		})
+	}
+}
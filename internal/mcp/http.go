@@ -0,0 +1,132 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+)
+
+// maxHTTPBody is the largest request body accepted over HTTP
+const maxHTTPBody = 10 << 20
+
+// HTTPHandler returns a handler for the Streamable HTTP transport in its simplest form:
+// each POST carries one JSON-RPC message (or batch) and is answered with application/json.
+// No SSE stream is opened, so GET is rejected and server-initiated notifications
+// (notifications/message) are not delivered over HTTP.
+//
+// Tool handlers get the request's context, so values set by HTTP middleware in front of
+// this handler (e.g. the authenticated client) reach them. Once ctx is cancelled,
+// new requests are rejected as in Run.
+//
+// An initialize request without the Mcp-Session-Id header starts a session, whose random ID is
+// returned in that header. Requests with the header use the session, and DELETE ends it; an ID the
+// server does not know for the request's owner (see SetSessionOwner) gets 404 Not Found, so that the
+// client initializes again. Requests without the header have no session: their protocol version is
+// the newest and duplicate request IDs are not rejected, since stateless clients may number the
+// requests of each connection from the start.
+func (s *Server) HTTPHandler(ctx context.Context) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+			w.Header().Set("Allow", "POST, DELETE")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if !sameOrigin(r) {
+			http.Error(w, "forbidden origin", http.StatusForbidden)
+			return
+		}
+
+		owner := ""
+		if s.sessionOwner != nil {
+			owner = s.sessionOwner(r.Context())
+		}
+		sessionID := r.Header.Get(sessionIDHeader)
+		var sess *session
+		if sessionID != "" {
+			if sess = s.httpSessions.get(owner, sessionID); sess == nil {
+				http.Error(w, "unknown session", http.StatusNotFound)
+				return
+			}
+		}
+		if r.Method == http.MethodDelete {
+			if sess == nil {
+				http.Error(w, "missing "+sessionIDHeader+" header", http.StatusBadRequest)
+				return
+			}
+			s.httpSessions.end(owner, sessionID)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxHTTPBody))
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusRequestEntityTooLarge)
+			return
+		}
+		data = bytes.TrimSpace(data)
+
+		reqCtx := r.Context()
+		if sess != nil {
+			reqCtx = withSession(reqCtx, sess)
+		}
+
+		var resp any
+		if len(data) > 0 && data[0] == '[' {
+			resp = s.processBatch(ctx, reqCtx, data)
+		} else {
+			var req Request
+			if err := json.Unmarshal(data, &req); err != nil {
+				slog.Warn("parse error", "error", err, "bytes", len(data))
+				resp = &Response{JSONRPC: "2.0", Error: &Error{Code: -32700, Message: "Parse error", Data: err.Error()}}
+			} else {
+				started := ""
+				if sess == nil && req.Method == "initialize" {
+					sess = newSession(s.newSessionID("http"))
+					if started, err = s.httpSessions.start(owner, sess); err != nil {
+						slog.Error("failed to start HTTP session", "error", err)
+						http.Error(w, "failed to start session", http.StatusInternalServerError)
+						return
+					}
+					reqCtx = withSession(reqCtx, sess)
+				}
+				result := s.process(ctx, reqCtx, &req)
+				if started != "" {
+					// A failed initialize leaves no session behind
+					if result != nil && result.Error == nil {
+						w.Header().Set(sessionIDHeader, started)
+					} else {
+						s.httpSessions.end(owner, started)
+					}
+				}
+				if result != nil {
+					resp = result
+				}
+			}
+		}
+
+		// Notifications and responses only get 202 Accepted with no body
+		if resp == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			slog.Error("failed to write response", "error", err)
+		}
+	})
+}
+
+// sameOrigin rejects cross-origin requests from browsers, which send Origin
+// (DNS rebinding against a local server). Requests without Origin are not from browsers.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type testOwnerKey struct{}
+
+// newSessionTestHandler returns the HTTP handler of s, with the owner of each request taken from X-Test-Client
+// as authentication middleware would
+func newSessionTestHandler(s *Server) http.Handler {
+	s.SetSessionOwner(func(ctx context.Context) string {
+		owner, _ := ctx.Value(testOwnerKey{}).(string)
+		return owner
+	})
+	h := s.HTTPHandler(context.Background())
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), testOwnerKey{}, r.Header.Get("X-Test-Client"))))
+	})
+}
+
+func httpRequest(h http.Handler, method, client, sessionID, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, "/mcp", strings.NewReader(body))
+	r.Header.Set("X-Test-Client", client)
+	if sessionID != "" {
+		r.Header.Set(sessionIDHeader, sessionID)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+const (
+	initializeBody = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`
+	pingBody       = `{"jsonrpc":"2.0","id":2,"method":"ping"}`
+)
+
+func TestHTTPSessions(t *testing.T) {
+	s := NewServer("test", "0.0.0")
+	h := newSessionTestHandler(s)
+
+	w := httpRequest(h, http.MethodPost, "alice", "", initializeBody)
+	sessionID := w.Header().Get(sessionIDHeader)
+	if w.Code != http.StatusOK || len(sessionID) != 32 {
+		t.Fatalf("initialize: status %d, session ID %q; want 200 and a random ID", w.Code, sessionID)
+	}
+	if other := httpRequest(h, http.MethodPost, "alice", "", initializeBody).Header().Get(sessionIDHeader); other == sessionID {
+		t.Errorf("two initialize requests got the same session ID %q", sessionID)
+	}
+
+	// The session keeps the request IDs it has seen
+	if w := httpRequest(h, http.MethodPost, "alice", sessionID, pingBody); w.Code != http.StatusOK || strings.Contains(w.Body.String(), "error") {
+		t.Fatalf("ping in the session: status %d, body %s", w.Code, w.Body)
+	}
+	var resp Response
+	w = httpRequest(h, http.MethodPost, "alice", sessionID, pingBody)
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Error == nil || resp.Error.Code != -32600 {
+		t.Errorf("duplicate ping in the session: %s, want -32600", w.Body)
+	}
+
+	tests := []struct {
+		name      string
+		method    string
+		client    string
+		sessionID string
+		body      string
+		want      int
+	}{
+		{"unknown session ID", http.MethodPost, "alice", "0123456789abcdef0123456789abcdef", pingBody, http.StatusNotFound},
+		{"initialize with unknown session ID", http.MethodPost, "alice", "0123456789abcdef0123456789abcdef", initializeBody, http.StatusNotFound},
+		{"session ID of another client", http.MethodPost, "bob", sessionID, pingBody, http.StatusNotFound},
+		{"DELETE by another client", http.MethodDelete, "bob", sessionID, "", http.StatusNotFound},
+		{"DELETE without session ID", http.MethodDelete, "alice", "", "", http.StatusBadRequest},
+		{"request without session", http.MethodPost, "bob", "", pingBody, http.StatusOK},
+		{"DELETE", http.MethodDelete, "alice", sessionID, "", http.StatusNoContent},
+		{"ended session", http.MethodPost, "alice", sessionID, pingBody, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := httpRequest(h, tt.method, tt.client, tt.sessionID, tt.body); w.Code != tt.want {
+				t.Errorf("status = %d, want %d (%s)", w.Code, tt.want, strings.TrimSpace(w.Body.String()))
+			}
+		})
+	}
+}
+
+func TestHTTPSessionsNotStarted(t *testing.T) {
+	s := NewServer("test", "0.0.0")
+	h := newSessionTestHandler(s)
+
+	for name, body := range map[string]string{
+		"failed initialize":   `{"jsonrpc":"2.0","id":1,"method":"initialize","params":"invalid"}`,
+		"initialize in batch": "[" + initializeBody + "]",
+		"other method":        pingBody,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if sessionID := httpRequest(h, http.MethodPost, "alice", "", body).Header().Get(sessionIDHeader); sessionID != "" {
+				t.Errorf("got session ID %q, want none", sessionID)
+			}
+		})
+	}
+	if n := len(s.httpSessions.sessions); n != 0 {
+		t.Errorf("%d sessions remembered, want 0", n)
+	}
+}
+
+func TestHTTPSessionsLimitPerOwner(t *testing.T) {
+	var h httpSessions
+	bob, err := h.start("bob", newSession("bob"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var alice []string
+	for range maxHTTPSessions + 1 {
+		id, err := h.start("alice", newSession("alice"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		alice = append(alice, id)
+	}
+
+	// Only the oldest session of the client starting too many is forgotten
+	if h.get("alice", alice[0]) != nil {
+		t.Error("the oldest session of alice is still remembered")
+	}
+	if h.get("alice", alice[1]) == nil || h.get("alice", alice[maxHTTPSessions]) == nil {
+		t.Error("a newer session of alice was forgotten")
+	}
+	if h.get("bob", bob) == nil {
+		t.Error("the session of bob was forgotten by the sessions of alice")
+	}
+}
@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// RequestStats is what the server measured of a tools/call request (see SetRequestObserver)
+type RequestStats struct {
+	Tool        string
+	ParamsBytes int  // Size of the params (tool name and arguments)
+	ResultBytes int  // Size of the text, data and structured content of the result (0 when rejected)
+	Rejected    bool // Rejected because the params exceeded the limit (see SetMaxParamsBytes)
+}
+
+// SetMaxParamsBytes rejects requests whose params are larger than n bytes with -32602 (Invalid params)
+// before they are parsed or reach a tool. 0 means no limit other than the message size limit.
+func (s *Server) SetMaxParamsBytes(n int) {
+	s.maxParamsBytes = n
+}
+
+// SetRequestObserver sets a function called after each tools/call request that was run
+// or rejected for its size, e.g. to record size and latency metrics.
+func (s *Server) SetRequestObserver(observe func(ctx context.Context, stats RequestStats)) {
+	s.observer = observe
+}
+
+// paramsTooLarge reports whether the params of req exceed the limit
+func (s *Server) paramsTooLarge(req *Request) bool {
+	return s.maxParamsBytes > 0 && len(req.Params) > s.maxParamsBytes
+}
+
+// paramsTooLargeError is the response to a request with params over the limit (nil for notifications, which are dropped)
+func paramsTooLargeError(req *Request, limit int) *Response {
+	if req.ID == nil {
+		return nil
+	}
+	return &Response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Error: &Error{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    fmt.Sprintf("params of %d bytes exceed the limit of %d bytes (max_params_bytes); pass narrower arguments instead of large inline data", len(req.Params), limit),
+		},
+	}
+}
+
+// observe reports a tools/call request to the observer.
+// Requests rejected for other reasons (duplicate IDs, shutdown) are not reported.
+func (s *Server) observe(ctx context.Context, req *Request, resp *Response) {
+	if s.observer == nil || req.Method != "tools/call" || resp == nil {
+		return
+	}
+	rejected := s.paramsTooLarge(req)
+	if resp.Error != nil && !rejected {
+		return
+	}
+	var params ToolCallParams
+	_ = json.Unmarshal(req.Params, &params)
+	stats := RequestStats{Tool: params.Name, ParamsBytes: len(req.Params), Rejected: rejected}
+	if result, ok := resp.Result.(ToolCallResult); ok {
+		for _, c := range result.Content {
+			stats.ResultBytes += len(c.Text) + len(c.Data)
+		}
+		stats.ResultBytes += len(result.StructuredContent)
+	}
+	s.observer(ctx, stats)
+}
@@ -0,0 +1,159 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+)
+
+// LoggingCapability advertises that the server sends log messages (notifications/message)
+type LoggingCapability struct{}
+
+type SetLevelParams struct {
+	Level string `json:"level"`
+}
+
+// LogMessageParams are the params of notifications/message
+type LogMessageParams struct {
+	Level  string `json:"level"`
+	Logger string `json:"logger,omitempty"`
+	Data   any    `json:"data"`
+}
+
+// logLevels maps MCP (syslog) log levels to slog levels.
+// slog has no levels between info/warn and above error, so those share the nearest one.
+var logLevels = map[string]slog.Level{
+	"debug":     slog.LevelDebug,
+	"info":      slog.LevelInfo,
+	"notice":    slog.LevelInfo + 2,
+	"warning":   slog.LevelWarn,
+	"error":     slog.LevelError,
+	"critical":  slog.LevelError + 1,
+	"alert":     slog.LevelError + 2,
+	"emergency": slog.LevelError + 3,
+}
+
+// mcpLevel returns the MCP log level for a slog level
+func mcpLevel(level slog.Level) string {
+	switch {
+	case level < slog.LevelInfo:
+		return "debug"
+	case level < slog.LevelInfo+2:
+		return "info"
+	case level < slog.LevelWarn:
+		return "notice"
+	case level < slog.LevelError:
+		return "warning"
+	default:
+		return "error"
+	}
+}
+
+// handleSetLevel records the level for the session of the request.
+// Only the stdio session receives notifications, but the level is kept for every session.
+func (s *Server) handleSetLevel(ctx context.Context, req *Request) *Response {
+	var params SetLevelParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return &Response{JSONRPC: "2.0", ID: req.ID, Error: &Error{Code: -32602, Message: "Invalid params", Data: err.Error()}}
+	}
+	level, ok := logLevels[strings.ToLower(params.Level)]
+	if !ok {
+		return &Response{JSONRPC: "2.0", ID: req.ID, Error: &Error{Code: -32602, Message: "Invalid params", Data: fmt.Sprintf("unknown log level: %q", params.Level)}}
+	}
+	if sess := sessionFrom(ctx); sess != nil {
+		sess.logLevel.Store(int64(level))
+		sess.logging.Store(true)
+	}
+	return &Response{JSONRPC: "2.0", ID: req.ID, Result: struct{}{}}
+}
+
+// SendLogMessage sends data to the client as notifications/message, for events that happen
+// outside a tool call (e.g. a watch firing). Unlike LogHandler it does not wait for
+// logging/setLevel, but a minimum level the client has chosen is still respected.
+func (s *Server) SendLogMessage(level, logger string, data any) {
+	if l, ok := logLevels[level]; ok && s.stdioSession.logging.Load() && !s.stdioSession.wantsLog(int64(l)) {
+		return
+	}
+	s.notify("notifications/message", LogMessageParams{Level: level, Logger: logger, Data: data})
+}
+
+// LogHandler wraps h so that records are also sent to the client as notifications/message
+// once the client has chosen a level with logging/setLevel.
+func (s *Server) LogHandler(h slog.Handler) slog.Handler {
+	return &clientLogHandler{server: s, next: h}
+}
+
+type clientLogHandler struct {
+	server *Server
+	next   slog.Handler
+	attrs  []slog.Attr
+	group  string
+}
+
+// sendingLog guards against a log record emitted while sending a log notification
+// (e.g. a write error) being sent again
+var sendingLog atomic.Bool
+
+func (h *clientLogHandler) clientEnabled(level slog.Level) bool {
+	return h.server.stdioSession.wantsLog(int64(level))
+}
+
+func (h *clientLogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level) || h.clientEnabled(level)
+}
+
+func (h *clientLogHandler) Handle(ctx context.Context, r slog.Record) error {
+	if h.clientEnabled(r.Level) && sendingLog.CompareAndSwap(false, true) {
+		data := map[string]any{"message": r.Message}
+		for _, a := range h.attrs {
+			data[a.Key] = logValue(a.Value)
+		}
+		r.Attrs(func(a slog.Attr) bool {
+			data[h.key(a.Key)] = logValue(a.Value)
+			return true
+		})
+		h.server.notify("notifications/message", LogMessageParams{Level: mcpLevel(r.Level), Logger: h.server.name, Data: data})
+		sendingLog.Store(false)
+	}
+	if !h.next.Enabled(ctx, r.Level) {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *clientLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.next = h.next.WithAttrs(attrs)
+	clone.attrs = append([]slog.Attr{}, h.attrs...)
+	for _, a := range attrs {
+		clone.attrs = append(clone.attrs, slog.Attr{Key: h.key(a.Key), Value: a.Value})
+	}
+	return &clone
+}
+
+// logValue converts an attribute value for JSON (errors would otherwise encode as {})
+func logValue(v slog.Value) any {
+	v = v.Resolve()
+	if err, ok := v.Any().(error); ok {
+		return err.Error()
+	}
+	return v.Any()
+}
+
+// key qualifies an attribute key with the current group (e.g. "group.key")
+func (h *clientLogHandler) key(k string) string {
+	if h.group == "" {
+		return k
+	}
+	return h.group + "." + k
+}
+
+func (h *clientLogHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.next = h.next.WithGroup(name)
+	clone.group = h.key(name)
+	return &clone
+}
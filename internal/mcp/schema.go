@@ -0,0 +1,247 @@
+package mcp
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// RegisterTool registers a tool whose input schema is derived from its parameter struct
+// (see SchemaFor), so that the schema cannot drift from what the handler decodes.
+// Properties set on tool.InputSchema take precedence over the derived ones,
+// e.g. for descriptions that depend on the config.
+func RegisterTool[TParams any](s *Server, tool Tool, handler ToolHandler) {
+	s.RegisterTool(ToolFor[TParams](tool), handler)
+}
+
+// ToolFor returns tool with its input schema derived from the parameter struct,
+// merged like RegisterTool. Use it where tools are defined apart from their registration.
+func ToolFor[TParams any](tool Tool) Tool {
+	schema := SchemaFor[TParams]()
+	for name, prop := range tool.InputSchema.Properties {
+		schema.Properties[name] = prop
+	}
+	if tool.InputSchema.Required != nil {
+		schema.Required = tool.InputSchema.Required
+	}
+	tool.InputSchema = schema
+	return tool
+}
+
+// SchemaFor derives a tool input schema from a parameter struct.
+// Properties are named after the json tags; these tags add details:
+//
+//	description:"..."  property description
+//	default:"..."      default value, parsed as the field type
+//	enum:"a,b,c"       allowed values (strings)
+//	required:"true"    the property is required
+//
+// Nested structs become object properties and embedded structs are flattened.
+// It panics on field types that have no JSON schema counterpart (a programming error).
+func SchemaFor[T any]() ToolSchema {
+	props, required := structProperties(reflect.TypeFor[T]())
+	return ToolSchema{Type: "object", Properties: props, Required: required}
+}
+
+func structProperties(t reflect.Type) (map[string]Property, []string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("mcp: parameters must be a struct, got %s", t))
+	}
+
+	props := map[string]Property{}
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			embedded, req := structProperties(f.Type)
+			for k, v := range embedded {
+				props[k] = v
+			}
+			required = append(required, req...)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := property(f.Type, t.Name()+"."+f.Name)
+		prop.Description = f.Tag.Get("description")
+		if enum := f.Tag.Get("enum"); enum != "" {
+			prop.Enum = strings.Split(enum, ",")
+		}
+		if def, ok := f.Tag.Lookup("default"); ok {
+			prop.Default = parseDefault(f.Type, def, t.Name()+"."+f.Name)
+		}
+		props[name] = prop
+		if f.Tag.Get("required") == "true" {
+			required = append(required, name)
+		}
+	}
+	return props, required
+}
+
+// property maps a Go type to a schema property (without description/default/enum)
+func property(t reflect.Type, field string) Property {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return Property{Type: "string"}
+	case reflect.Bool:
+		return Property{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Property{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Property{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		items := property(t.Elem(), field)
+		return Property{Type: "array", Items: &items}
+	case reflect.Map:
+		return Property{Type: "object"}
+	case reflect.Struct:
+		props, required := structProperties(t)
+		return Property{Type: "object", Properties: props, Required: required}
+	}
+	panic(fmt.Sprintf("mcp: unsupported parameter type %s for %s", t, field))
+}
+
+// OutputSchemaFor derives a tool output schema from a result struct, for Tool.OutputSchema.
+// Properties are named after the json tags. None are required, so that results reduced
+// by middlewares (e.g. spilled results) still validate. Pointers, slices and maps without
+// omitempty are nullable, types with their own JSON encoding are strings (encoding.TextMarshaler)
+// or left untyped (json.Marshaler, interfaces), and recursive types stop at a plain object.
+// It panics on field types that have no JSON schema counterpart (a programming error).
+func OutputSchemaFor[T any]() *ToolSchema {
+	t := reflect.TypeFor[T]()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("mcp: results must be a struct, got %s", t))
+	}
+	return &ToolSchema{Type: "object", Properties: outputProperties(t, map[reflect.Type]bool{t: true})}
+}
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+func outputProperties(t reflect.Type, visiting map[reflect.Type]bool) map[string]Property {
+	props := map[string]Property{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			for k, v := range outputProperties(ft, visiting) {
+				props[k] = v
+			}
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = outputProperty(f.Type, strings.Contains(","+opts+",", ",omitempty,"), visiting, t.Name()+"."+f.Name)
+	}
+	return props
+}
+
+// outputProperty maps a Go type to a schema property as encoding/json encodes it
+func outputProperty(t reflect.Type, omitempty bool, visiting map[reflect.Type]bool, field string) Property {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t, nullable = t.Elem(), true
+	}
+	pt := reflect.PointerTo(t)
+	switch {
+	case t.Kind() == reflect.Interface || t.Implements(jsonMarshalerType) || pt.Implements(jsonMarshalerType):
+		return Property{}
+	case t.Implements(textMarshalerType) || pt.Implements(textMarshalerType):
+		return Property{Type: "string", Nullable: nullable && !omitempty}
+	}
+
+	var prop Property
+	switch t.Kind() {
+	case reflect.String:
+		prop = Property{Type: "string"}
+	case reflect.Bool:
+		prop = Property{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		prop = Property{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		prop = Property{Type: "number"}
+	case reflect.Slice:
+		nullable = true
+		if t.Elem().Kind() == reflect.Uint8 {
+			prop = Property{Type: "string"} // base64
+			break
+		}
+		items := outputProperty(t.Elem(), false, visiting, field)
+		prop = Property{Type: "array", Items: &items}
+	case reflect.Array:
+		items := outputProperty(t.Elem(), false, visiting, field)
+		prop = Property{Type: "array", Items: &items}
+	case reflect.Map:
+		nullable = true
+		prop = Property{Type: "object"}
+	case reflect.Struct:
+		prop = Property{Type: "object"}
+		if !visiting[t] {
+			visiting[t] = true
+			prop.Properties = outputProperties(t, visiting)
+			delete(visiting, t)
+		}
+	default:
+		panic(fmt.Sprintf("mcp: unsupported result type %s for %s", t, field))
+	}
+	prop.Nullable = nullable && !omitempty
+	return prop
+}
+
+// parseDefault converts a default tag to a value of the field type
+func parseDefault(t reflect.Type, def, field string) any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	var (
+		v   any
+		err error
+	)
+	switch t.Kind() {
+	case reflect.String:
+		v = def
+	case reflect.Bool:
+		v, err = strconv.ParseBool(def)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err = strconv.ParseInt(def, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		v, err = strconv.ParseFloat(def, 64)
+	default:
+		err = fmt.Errorf("defaults are not supported for %s", t)
+	}
+	if err != nil {
+		panic(fmt.Sprintf("mcp: invalid default %q for %s: %v", def, field, err))
+	}
+	return v
+}
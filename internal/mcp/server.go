@@ -2,11 +2,17 @@ package mcp
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // JSON-RPC 2.0
@@ -17,6 +23,13 @@ type Request struct {
 	Params  json.RawMessage `json:"params,omitempty"`
 }
 
+// Notification is a JSON-RPC message without an ID sent by the server (e.g. notifications/message)
+type Notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
 type Response struct {
 	JSONRPC string `json:"jsonrpc"`
 	ID      any    `json:"id,omitempty"`
@@ -37,11 +50,26 @@ type ServerInfo struct {
 }
 
 type ServerCapabilities struct {
-	Tools *ToolsCapability `json:"tools,omitempty"`
+	Tools       *ToolsCapability       `json:"tools,omitempty"`
+	Resources   *ResourcesCapability   `json:"resources,omitempty"`
+	Logging     *LoggingCapability     `json:"logging,omitempty"`
+	Completions *CompletionsCapability `json:"completions,omitempty"`
 }
 
 type ToolsCapability struct{}
 
+type ResourcesCapability struct{}
+
+type InitializeParams struct {
+	ProtocolVersion string          `json:"protocolVersion"`
+	Capabilities    json.RawMessage `json:"capabilities,omitempty"`
+	ClientInfo      *ServerInfo     `json:"clientInfo,omitempty"`
+}
+
+// supportedProtocolVersions lists the MCP protocol versions the server speaks, newest first.
+// A client asking for another version gets the newest one and decides whether to continue.
+var supportedProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
 type InitializeResult struct {
 	ProtocolVersion string             `json:"protocolVersion"`
 	Capabilities    ServerCapabilities `json:"capabilities"`
@@ -49,9 +77,27 @@ type InitializeResult struct {
 }
 
 type Tool struct {
-	Name        string     `json:"name"`
-	Description string     `json:"description,omitempty"`
-	InputSchema ToolSchema `json:"inputSchema"`
+	Name         string           `json:"name"`
+	Description  string           `json:"description,omitempty"`
+	InputSchema  ToolSchema       `json:"inputSchema"`
+	OutputSchema *ToolSchema      `json:"outputSchema,omitempty"` // Shape of structuredContent (see OutputSchemaFor); nil if it depends on the arguments
+	Annotations  *ToolAnnotations `json:"annotations,omitempty"`
+	Examples     []ToolExample    `json:"-"` // Appended to the description at registration (see withExamples)
+}
+
+// ToolAnnotations are behavior hints for clients (MCP tool annotations).
+// Tools registered without annotations are treated as read-only.
+type ToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    bool   `json:"readOnlyHint"`
+	DestructiveHint bool   `json:"destructiveHint,omitempty"`
+	IdempotentHint  bool   `json:"idempotentHint,omitempty"`
+	OpenWorldHint   bool   `json:"openWorldHint,omitempty"`
+}
+
+// IsReadOnly reports whether the tool only reads data
+func (t Tool) IsReadOnly() bool {
+	return t.Annotations == nil || t.Annotations.ReadOnlyHint
 }
 
 type ToolSchema struct {
@@ -61,13 +107,55 @@ type ToolSchema struct {
 }
 
 type Property struct {
-	Type        string              `json:"type"`
+	Type        string              `json:"type,omitempty"` // Empty for values of any type
+	Nullable    bool                `json:"-"`              // Rendered as "type": [Type, "null"]
 	Description string              `json:"description,omitempty"`
 	Properties  map[string]Property `json:"properties,omitempty"`
 	Required    []string            `json:"required,omitempty"`
+	Items       *Property           `json:"items,omitempty"`
+	Enum        []string            `json:"enum,omitempty"`
 	Default     any                 `json:"default,omitempty"`
 }
 
+// MarshalJSON renders the type of a nullable property as [Type, "null"]
+func (p Property) MarshalJSON() ([]byte, error) {
+	type plain Property
+	if !p.Nullable || p.Type == "" {
+		return json.Marshal(plain(p))
+	}
+	return json.Marshal(struct {
+		Type []string `json:"type"`
+		plain
+	}{Type: []string{p.Type, "null"}, plain: plain(p)})
+}
+
+// UnmarshalJSON accepts both a single type and a [type, "null"] pair
+func (p *Property) UnmarshalJSON(data []byte) error {
+	type plain Property
+	v := struct {
+		Type json.RawMessage `json:"type"`
+		*plain
+	}{plain: (*plain)(p)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if len(v.Type) == 0 || json.Unmarshal(v.Type, &p.Type) == nil {
+		return nil
+	}
+	var types []string
+	if err := json.Unmarshal(v.Type, &types); err != nil {
+		return fmt.Errorf("invalid property type: %s", v.Type)
+	}
+	for _, t := range types {
+		if t == "null" {
+			p.Nullable = true
+		} else {
+			p.Type = t
+		}
+	}
+	return nil
+}
+
 type ToolsListResult struct {
 	Tools []Tool `json:"tools"`
 }
@@ -78,26 +166,112 @@ type ToolCallParams struct {
 }
 
 type ToolCallResult struct {
-	Content []ContentBlock `json:"content"`
-	IsError bool           `json:"isError,omitempty"`
+	Content           []ContentBlock  `json:"content"`
+	StructuredContent json.RawMessage `json:"structuredContent,omitempty"` // The result as a JSON object
+	IsError           bool            `json:"isError,omitempty"`
 }
 
 type ContentBlock struct {
-	Type string `json:"type"`
-	Text string `json:"text,omitempty"`
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	Data     string `json:"data,omitempty"`     // Base64-encoded data for image blocks
+	MimeType string `json:"mimeType,omitempty"` // e.g. "image/png"
+}
+
+// Content is a tool result made of pre-rendered content blocks.
+// Handlers (or middlewares) return it instead of a JSON-serializable value
+// when the result is already text or includes non-text blocks.
+type Content []ContentBlock
+
+// Text returns a Content with a single text block
+func Text(text string) Content {
+	return Content{{Type: "text", Text: text}}
+}
+
+// Structured is pre-rendered content together with the result it was rendered from
+// (e.g. a chart or a CSV table). Clients read the content; the result is returned as
+// structuredContent so that it still matches the tool's output schema.
+// It marshals as the result.
+type Structured struct {
+	Content Content
+	Result  any
+}
+
+func (s Structured) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Result)
+}
+
+// Image returns an image content block
+func Image(data []byte, mimeType string) ContentBlock {
+	return ContentBlock{Type: "image", Data: base64.StdEncoding.EncodeToString(data), MimeType: mimeType}
+}
+
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+type ResourcesListResult struct {
+	Resources []Resource `json:"resources"`
+}
+
+type ResourceReadParams struct {
+	URI string `json:"uri"`
+}
+
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text"`
+}
+
+type ResourceReadResult struct {
+	Contents []ResourceContents `json:"contents"`
+}
+
+// ResourceProvider serves MCP resources (resources/list and resources/read)
+type ResourceProvider interface {
+	ListResources(ctx context.Context) []Resource
+	ReadResource(ctx context.Context, uri string) (*ResourceContents, error)
 }
 
 // ToolHandler is a function that handles tool calls
 type ToolHandler func(ctx context.Context, args json.RawMessage) (any, error)
 
+// Middleware wraps a tool handler at registration time.
+// It may also adjust the tool definition (e.g., input schema) before it is listed.
+type Middleware func(tool *Tool, next ToolHandler) ToolHandler
+
 // Server is the MCP server
 type Server struct {
-	name     string
-	version  string
-	tools    []Tool
-	handlers map[string]ToolHandler
+	name        string
+	version     string
+	tools       []Tool
+	handlers    map[string]ToolHandler
+	middlewares []Middleware
+	allowWrite  bool
+	resources   ResourceProvider
+	completions CompletionProvider
+	toolFilter  func(ctx context.Context, name string) bool
+
+	maxParamsBytes int
+	observer       func(ctx context.Context, stats RequestStats)
+
+	drainTimeout time.Duration
+	in           io.Reader
+	stdio        *stream
+	stdioSession *session // Notifications (logs, watches) go to this session only
+	httpSessions httpSessions
+	sessionOwner func(ctx context.Context) string // Owner of the HTTP sessions a request may use
+	webSockets   sync.WaitGroup                   // Open WebSocket connections (see DrainWebSockets)
+	sessionCount atomic.Int64                     // Sessions started, for session IDs
 }
 
+// defaultDrainTimeout is how long Run waits for an in-flight request after shutdown starts.
+const defaultDrainTimeout = 30 * time.Second
+
 // NewServer creates a new MCP server
 func NewServer(name, version string) *Server {
 	return &Server{
@@ -105,85 +279,299 @@ func NewServer(name, version string) *Server {
 		version:  version,
 		tools:    []Tool{},
 		handlers: make(map[string]ToolHandler),
+
+		drainTimeout: defaultDrainTimeout,
+		in:           os.Stdin,
+		stdio:        &stream{out: os.Stdout},
+		stdioSession: newSession("stdio"),
 	}
 }
 
+// SetDrainTimeout sets how long Run lets an in-flight request finish after ctx is cancelled.
+func (s *Server) SetDrainTimeout(d time.Duration) {
+	s.drainTimeout = d
+}
+
+// SetIO replaces stdin/stdout, e.g. with pipes in end-to-end tests (see internal/mcptest).
+// It must be called before Run.
+func (s *Server) SetIO(in io.Reader, out io.Writer) {
+	s.in = in
+	s.stdio = &stream{out: out}
+}
+
+// AllowWriteTools enables registration of tools that are not read-only.
+// Without it, such tools are skipped at registration time.
+func (s *Server) AllowWriteTools(allow bool) {
+	s.allowWrite = allow
+}
+
+// SetResourceProvider enables the resources capability backed by p
+func (s *Server) SetResourceProvider(p ResourceProvider) {
+	s.resources = p
+}
+
+// SetToolFilter leaves the tools for which visible returns false out of tools/list.
+// They stay registered and can still be called by name.
+func (s *Server) SetToolFilter(visible func(ctx context.Context, name string) bool) {
+	s.toolFilter = visible
+}
+
+// SetSessionOwner scopes HTTP sessions (Mcp-Session-Id) by the owner of the request, e.g. the client
+// authenticated by HTTP middleware, so that a session ID is unknown to other clients.
+func (s *Server) SetSessionOwner(owner func(ctx context.Context) string) {
+	s.sessionOwner = owner
+}
+
+// Use adds a middleware applied to tools registered after this call
+func (s *Server) Use(mw Middleware) {
+	s.middlewares = append(s.middlewares, mw)
+}
+
 // RegisterTool registers a tool with its handler
 func (s *Server) RegisterTool(tool Tool, handler ToolHandler) {
+	if tool.Annotations == nil {
+		tool.Annotations = &ToolAnnotations{ReadOnlyHint: true}
+	}
+	if !tool.IsReadOnly() && !s.allowWrite {
+		slog.Info("skipping write tool (read-only mode)", "tool", tool.Name)
+		return
+	}
+	for i := len(s.middlewares) - 1; i >= 0; i-- {
+		handler = s.middlewares[i](&tool, handler)
+	}
+	// Middlewares may replace the description (translation, tools.descriptions), so the examples come last
+	tool.Description = withExamples(tool.Description, tool.Examples)
 	s.tools = append(s.tools, tool)
 	s.handlers[tool.Name] = handler
 }
 
-// Run starts the server and processes stdin/stdout
+// Run starts the server and processes requests from stdin until stdin is closed or ctx is cancelled.
+// Cancelling ctx stops accepting new requests only: the request being processed keeps running
+// (its context is cancelled after the drain timeout) and its response is written before Run returns.
 func (s *Server) Run(ctx context.Context) error {
-	reader := bufio.NewReader(os.Stdin)
+	err := s.serveStream(ctx, s.in, s.stdio, s.stdioSession)
+	if err == nil && ctx.Err() != nil {
+		slog.Info("shut down: stopped accepting requests and drained in-flight requests")
+	}
+	return err
+}
 
-	for {
+// serveStream processes the requests of a stream connection (stdio or a Unix socket connection)
+// one at a time until the input is closed or ctx is cancelled, as described for Run.
+// The connection is the session sess: request IDs must not repeat until the next initialize.
+func (s *Server) serveStream(ctx context.Context, in io.Reader, out *stream, sess *session) error {
+	// Requests get a context that survives shutdown so in-flight API calls are not killed mid-query
+	reqCtx, cancelRequests := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelRequests()
+	reqCtx = withSession(reqCtx, sess)
+	go func() {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
-		default:
+			timer := time.NewTimer(s.drainTimeout)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+				slog.Warn("drain timeout exceeded: cancelling in-flight request")
+				cancelRequests()
+			case <-reqCtx.Done():
+			}
+		case <-reqCtx.Done():
 		}
+	}()
+
+	// Read the input in the background so that shutdown does not wait for the next message
+	messages := make(chan *message)
+	readErr := make(chan error, 1)
+	go func() {
+		reader := bufio.NewReaderSize(in, 1<<20)
+		for {
+			msg, err := readMessage(reader)
+			if err != nil {
+				readErr <- err
+				return
+			}
+			select {
+			case messages <- msg:
+			case <-reqCtx.Done():
+				return
+			}
+		}
+	}()
 
-		line, err := reader.ReadBytes('\n')
-		if err != nil {
+	for {
+		// Check shutdown first so that a pending message is not picked up after cancellation
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		var msg *message
+		select {
+		case <-ctx.Done():
+			continue
+		case err := <-readErr:
 			if err == io.EOF {
 				return nil
 			}
 			return fmt.Errorf("failed to read input: %w", err)
+		case msg = <-messages:
 		}
 
-		if len(line) == 0 {
-			continue
+		// Reply in the framing the client used
+		out.setFraming(msg.framing)
+		if resp := s.processMessage(ctx, reqCtx, msg.data); resp != nil {
+			out.write(resp)
+		}
+	}
+}
+
+// process handles one request unless shutdown has started.
+// A request read after shutdown started is rejected rather than started,
+// and so is a request with params over the size limit (see SetMaxParamsBytes)
+// or reusing the ID of an earlier one (see checkDuplicate). The size is checked first,
+// so that the ID of a request rejected for its size is not used up and can be retried.
+func (s *Server) process(ctx, reqCtx context.Context, req *Request) *Response {
+	if ctx.Err() != nil {
+		if req.ID == nil {
+			return nil
 		}
+		return &Response{JSONRPC: "2.0", ID: req.ID, Error: &Error{Code: -32000, Message: "Server is shutting down"}}
+	}
+
+	start := time.Now()
+	var resp *Response
+	if s.paramsTooLarge(req) {
+		resp = paramsTooLargeError(req, s.maxParamsBytes)
+	} else if resp = checkDuplicate(reqCtx, req); resp == nil {
+		resp = s.handleRequest(reqCtx, req)
+	}
+	logRequest(reqCtx, req, resp, time.Since(start))
+	s.observe(reqCtx, req, resp)
+	return resp
+}
+
+// processMessage handles one JSON-RPC message or batch and returns what to send back (nil for notifications)
+func (s *Server) processMessage(ctx, reqCtx context.Context, data []byte) any {
+	if len(data) > 0 && data[0] == '[' {
+		return s.processBatch(ctx, reqCtx, data)
+	}
+	var req Request
+	if err := json.Unmarshal(data, &req); err != nil {
+		slog.Warn("parse error", "error", err, "bytes", len(data))
+		return &Response{JSONRPC: "2.0", Error: &Error{Code: -32700, Message: "Parse error", Data: err.Error()}}
+	}
+	if resp := s.process(ctx, reqCtx, &req); resp != nil {
+		return resp
+	}
+	return nil
+}
+
+// processBatch processes a JSON-RPC batch and returns what to send back:
+// the array of responses, a single error response, or nil for notifications only.
+func (s *Server) processBatch(ctx, reqCtx context.Context, data []byte) any {
+	var batch []json.RawMessage
+	if err := json.Unmarshal(data, &batch); err != nil {
+		slog.Warn("parse error", "error", err, "bytes", len(data))
+		return &Response{JSONRPC: "2.0", Error: &Error{Code: -32700, Message: "Parse error", Data: err.Error()}}
+	}
+	if len(batch) == 0 {
+		return &Response{JSONRPC: "2.0", Error: &Error{Code: -32600, Message: "Invalid Request", Data: "empty batch"}}
+	}
 
+	responses := []*Response{}
+	for _, raw := range batch {
 		var req Request
-		if err := json.Unmarshal(line, &req); err != nil {
-			s.sendError(nil, -32700, "Parse error", err.Error())
+		if err := json.Unmarshal(raw, &req); err != nil || req.Method == "" {
+			responses = append(responses, &Response{JSONRPC: "2.0", Error: &Error{Code: -32600, Message: "Invalid Request"}})
 			continue
 		}
-
-		resp := s.handleRequest(ctx, &req)
-		if resp != nil {
-			s.sendResponse(resp)
+		if resp := s.process(ctx, reqCtx, &req); resp != nil {
+			responses = append(responses, resp)
 		}
 	}
+	if len(responses) == 0 {
+		return nil
+	}
+	return responses
 }
 
 func (s *Server) handleRequest(ctx context.Context, req *Request) *Response {
 	switch req.Method {
 	case "initialize":
-		return s.handleInitialize(req)
-	case "initialized":
-		// Notification, no response needed
-		return nil
+		return s.handleInitialize(ctx, req)
+	case "ping":
+		return &Response{JSONRPC: "2.0", ID: req.ID, Result: struct{}{}}
+	case "logging/setLevel":
+		return s.handleSetLevel(ctx, req)
 	case "tools/list":
-		return s.handleToolsList(req)
+		return s.handleToolsList(ctx, req)
 	case "tools/call":
 		return s.handleToolsCall(ctx, req)
-	default:
-		return &Response{
-			JSONRPC: "2.0",
-			ID:      req.ID,
-			Error: &Error{
-				Code:    -32601,
-				Message: "Method not found",
-			},
+	case "resources/list", "resources/read":
+		if s.resources == nil {
+			break
+		}
+		if req.Method == "resources/list" {
+			return s.handleResourcesList(ctx, req)
 		}
+		return s.handleResourcesRead(ctx, req)
+	case "completion/complete":
+		if s.completions == nil {
+			break
+		}
+		return s.handleComplete(ctx, req)
+	}
+
+	// Notifications (initialized, notifications/cancelled, ...) never get a response
+	if req.ID == nil {
+		return nil
+	}
+	return &Response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Error: &Error{
+			Code:    -32601,
+			Message: "Method not found",
+		},
 	}
 }
 
-func (s *Server) handleInitialize(req *Request) *Response {
+func (s *Server) handleInitialize(ctx context.Context, req *Request) *Response {
+	var params InitializeParams
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return &Response{JSONRPC: "2.0", ID: req.ID, Error: &Error{Code: -32602, Message: "Invalid params", Data: err.Error()}}
+		}
+	}
+	version := supportedProtocolVersions[0]
+	for _, v := range supportedProtocolVersions {
+		if v == params.ProtocolVersion {
+			version = v
+		}
+	}
+	if version != params.ProtocolVersion {
+		slog.Warn("unsupported protocol version requested", "requested", params.ProtocolVersion, "using", version, "session", SessionID(ctx))
+	}
+	if sess := sessionFrom(ctx); sess != nil {
+		sess.setProtocolVersion(version)
+	}
+
 	result := InitializeResult{
-		ProtocolVersion: "2024-11-05",
+		ProtocolVersion: version,
 		Capabilities: ServerCapabilities{
-			Tools: &ToolsCapability{},
+			Tools:   &ToolsCapability{},
+			Logging: &LoggingCapability{},
 		},
 		ServerInfo: ServerInfo{
 			Name:    s.name,
 			Version: s.version,
 		},
 	}
+	if s.resources != nil {
+		result.Capabilities.Resources = &ResourcesCapability{}
+	}
+	if s.completions != nil {
+		result.Capabilities.Completions = &CompletionsCapability{}
+	}
 
 	return &Response{
 		JSONRPC: "2.0",
@@ -192,12 +580,73 @@ func (s *Server) handleInitialize(req *Request) *Response {
 	}
 }
 
-func (s *Server) handleToolsList(req *Request) *Response {
+func (s *Server) handleResourcesList(ctx context.Context, req *Request) *Response {
+	return &Response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result: ResourcesListResult{
+			Resources: s.resources.ListResources(ctx),
+		},
+	}
+}
+
+func (s *Server) handleResourcesRead(ctx context.Context, req *Request) *Response {
+	var params ResourceReadParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return &Response{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error: &Error{
+				Code:    -32602,
+				Message: "Invalid params",
+				Data:    err.Error(),
+			},
+		}
+	}
+
+	contents, err := s.resources.ReadResource(ctx, params.URI)
+	if err != nil {
+		return &Response{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error: &Error{
+				Code:    -32002,
+				Message: "Resource not found",
+				Data:    err.Error(),
+			},
+		}
+	}
+
+	return &Response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result: ResourceReadResult{
+			Contents: []ResourceContents{*contents},
+		},
+	}
+}
+
+func (s *Server) handleToolsList(ctx context.Context, req *Request) *Response {
+	structured := structuredOutput(ctx)
+	tools := s.tools
+	if s.toolFilter != nil || !structured {
+		tools = []Tool{}
+		for _, tool := range s.tools {
+			if s.toolFilter != nil && !s.toolFilter(ctx, tool.Name) {
+				continue
+			}
+			// Output schemas came with structured content (2025-06-18); older clients do not expect them
+			if !structured {
+				tool.OutputSchema = nil
+			}
+			tools = append(tools, tool)
+		}
+	}
 	return &Response{
 		JSONRPC: "2.0",
 		ID:      req.ID,
 		Result: ToolsListResult{
-			Tools: s.tools,
+			Tools: tools,
 		},
 	}
 }
@@ -243,6 +692,29 @@ func (s *Server) handleToolsCall(ctx context.Context, req *Request) *Response {
 		}
 	}
 
+	switch r := result.(type) {
+	case Content:
+		// Pre-rendered content is returned as is
+		return &Response{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Result:  ToolCallResult{Content: r},
+		}
+	case Structured:
+		callResult := ToolCallResult{Content: r.Content}
+		// Clients of earlier protocol versions read the content only
+		if structuredOutput(ctx) {
+			if data, err := json.Marshal(r.Result); err == nil {
+				callResult.StructuredContent = structuredContent(data)
+			}
+		}
+		return &Response{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Result:  callResult,
+		}
+	}
+
 	// Convert result to JSON text
 	resultJSON, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
@@ -258,36 +730,106 @@ func (s *Server) handleToolsCall(ctx context.Context, req *Request) *Response {
 		}
 	}
 
+	// The same result is returned as text for clients without structured content support
+	callResult := ToolCallResult{Content: []ContentBlock{{Type: "text", Text: string(resultJSON)}}}
+	if structuredOutput(ctx) {
+		callResult.StructuredContent = structuredContent(resultJSON)
+	}
 	return &Response{
 		JSONRPC: "2.0",
 		ID:      req.ID,
-		Result: ToolCallResult{
-			Content: []ContentBlock{
-				{Type: "text", Text: string(resultJSON)},
-			},
-		},
+		Result:  callResult,
+	}
+}
+
+// structuredContent compacts an encoded result for structuredContent, which must be a JSON object
+func structuredContent(data []byte) json.RawMessage {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// logRequest writes one structured log line per request to stderr (stdout is reserved for the protocol).
+// Tool calls are logged at info, other methods at debug, failures at warn.
+func logRequest(ctx context.Context, req *Request, resp *Response, elapsed time.Duration) {
+	level := slog.LevelDebug
+	attrs := []any{"method", req.Method, "duration_ms", elapsed.Milliseconds()}
+	if req.ID != nil {
+		attrs = append(attrs, "id", req.ID)
+	}
+	if id := SessionID(ctx); id != "" {
+		attrs = append(attrs, "session", id)
+	}
+	if req.Method == "tools/call" {
+		level = slog.LevelInfo
+		var params ToolCallParams
+		if json.Unmarshal(req.Params, &params) == nil {
+			attrs = append(attrs, "tool", params.Name)
+			if slog.Default().Enabled(ctx, slog.LevelDebug) {
+				attrs = append(attrs, "arguments", params.Arguments)
+			}
+		}
+	}
+
+	outcome := "ok"
+	switch {
+	case resp == nil:
+		outcome = "notification"
+	case resp.Error != nil:
+		outcome = "rpc_error"
+		level = slog.LevelWarn
+		attrs = append(attrs, "error", resp.Error.Message)
+	default:
+		if result, ok := resp.Result.(ToolCallResult); ok && result.IsError {
+			outcome = "tool_error"
+			level = slog.LevelWarn
+			if len(result.Content) > 0 {
+				attrs = append(attrs, "error", result.Content[0].Text)
+			}
+		}
 	}
+	attrs = append(attrs, "outcome", outcome)
+
+	slog.Log(ctx, level, "request", attrs...)
+}
+
+// notify sends a server-initiated notification on stdio
+func (s *Server) notify(method string, params any) {
+	s.stdio.write(&Notification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+// stream is the output of a stream connection (stdio or a Unix socket connection)
+type stream struct {
+	mu      sync.Mutex
+	out     io.Writer
+	framing framing // Framing of the last message read; responses use the same
+}
+
+func (st *stream) setFraming(f framing) {
+	st.mu.Lock()
+	st.framing = f
+	st.mu.Unlock()
 }
 
-func (s *Server) sendResponse(resp *Response) {
-	data, err := json.Marshal(resp)
+// write encodes v (a response, a batch of responses or a notification) and writes it with
+// a single call so that a response is never interleaved or torn.
+func (st *stream) write(v any) {
+	data, err := json.Marshal(v)
 	if err != nil {
 		// Log error but can't send response
-		fmt.Fprintf(os.Stderr, "Error marshaling response: %v\n", err)
+		slog.Error("failed to marshal response", "error", err)
 		return
 	}
-	fmt.Println(string(data))
-}
-
-func (s *Server) sendError(id any, code int, message, data string) {
-	resp := &Response{
-		JSONRPC: "2.0",
-		ID:      id,
-		Error: &Error{
-			Code:    code,
-			Message: message,
-			Data:    data,
-		},
+	st.mu.Lock()
+	_, err = st.out.Write(frame(data, st.framing))
+	st.mu.Unlock()
+	if err != nil {
+		slog.Error("failed to write response", "error", err)
 	}
-	s.sendResponse(resp)
 }
@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestStructuredOutputFollowsProtocolVersion(t *testing.T) {
+	s := NewServer("test", "0.0.0")
+	type result struct {
+		Count int `json:"count"`
+	}
+	s.RegisterTool(Tool{Name: "test.count", InputSchema: ToolSchema{Type: "object"}, OutputSchema: OutputSchemaFor[result]()},
+		func(ctx context.Context, args json.RawMessage) (any, error) { return result{Count: 3}, nil })
+
+	tests := []struct {
+		version    string
+		structured bool
+	}{
+		{"2025-06-18", true},
+		{"2025-03-26", false},
+		{"2024-11-05", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			ctx := withSession(context.Background(), newSession("test"))
+			call := func(method, params string) *Response {
+				return s.handleRequest(ctx, &Request{JSONRPC: "2.0", ID: 1, Method: method, Params: json.RawMessage(params)})
+			}
+			if resp := call("initialize", `{"protocolVersion":"`+tt.version+`"}`); resp.Error != nil {
+				t.Fatal(resp.Error.Message)
+			}
+
+			tools := call("tools/list", "").Result.(ToolsListResult).Tools
+			if got := tools[0].OutputSchema != nil; got != tt.structured {
+				t.Errorf("outputSchema listed = %v, want %v", got, tt.structured)
+			}
+			callResult := call("tools/call", `{"name":"test.count"}`).Result.(ToolCallResult)
+			if got := callResult.StructuredContent != nil; got != tt.structured {
+				t.Errorf("structuredContent returned = %v, want %v", got, tt.structured)
+			}
+			if len(callResult.Content) != 1 || callResult.Content[0].Text == "" {
+				t.Errorf("content = %+v, want the result as text", callResult.Content)
+			}
+		})
+	}
+
+	// The registered definition keeps its schema for later sessions
+	if s.tools[0].OutputSchema == nil {
+		t.Error("tools/list of an older session removed the registered output schema")
+	}
+}
+
+func TestOversizedRequestKeepsItsID(t *testing.T) {
+	s := NewServer("test", "0.0.0")
+	s.SetMaxParamsBytes(64)
+	var observed []RequestStats
+	s.SetRequestObserver(func(ctx context.Context, stats RequestStats) { observed = append(observed, stats) })
+	s.RegisterTool(Tool{Name: "test.echo", InputSchema: ToolSchema{Type: "object"}},
+		func(ctx context.Context, args json.RawMessage) (any, error) {
+			return map[string]string{"ok": "yes"}, nil
+		})
+
+	ctx := withSession(context.Background(), newSession("test"))
+	small := `{"name":"test.echo"}`
+	large := `{"name":"test.echo","arguments":{"data":"` + strings.Repeat("x", 100) + `"}}`
+	steps := []struct {
+		name     string
+		params   string
+		wantCode int  // Error code, 0 for success
+		rejected bool // Reported to the observer as a size rejection
+		observed bool // Reported to the observer at all
+	}{
+		{"oversized", large, -32602, true, true},
+		{"retried smaller with the same id", small, 0, false, true},
+		{"oversized with a used id", large, -32602, true, true},
+		{"duplicate", small, -32600, false, false},
+	}
+	for _, step := range steps {
+		observed = nil
+		resp := s.process(ctx, ctx, &Request{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: json.RawMessage(step.params)})
+		code := 0
+		if resp.Error != nil {
+			code = resp.Error.Code
+		}
+		if code != step.wantCode {
+			t.Errorf("%s: error code = %d, want %d", step.name, code, step.wantCode)
+		}
+		if got := len(observed) == 1; got != step.observed {
+			t.Errorf("%s: observed = %v, want %v", step.name, got, step.observed)
+		} else if got && observed[0].Rejected != step.rejected {
+			t.Errorf("%s: rejected = %v, want %v", step.name, observed[0].Rejected, step.rejected)
+		}
+	}
+}
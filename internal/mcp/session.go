@@ -0,0 +1,151 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// maxHTTPSessions bounds the HTTP sessions the server remembers for each client (the oldest is forgotten first).
+const maxHTTPSessions = 256
+
+// sessionIDHeader identifies an HTTP session (Streamable HTTP transport)
+const sessionIDHeader = "Mcp-Session-Id"
+
+// session is the state of one client session: stdio, a Unix socket or WebSocket connection,
+// or the requests of an HTTP session (Mcp-Session-Id). Sessions share the tools (and their caches)
+// but each has its own protocol version, log level and request IDs, so that several clients can
+// use one server at the same time.
+type session struct {
+	id  string
+	ids *recentIDs
+
+	mu              sync.Mutex
+	protocolVersion string // Negotiated in initialize
+
+	logLevel atomic.Int64 // Minimum slog level sent as notifications/message
+	logging  atomic.Bool  // Set once the client calls logging/setLevel
+}
+
+func newSession(id string) *session {
+	return &session{id: id, ids: newRecentIDs()}
+}
+
+// newSessionID numbers the sessions of a transport in the order they start (e.g. "unix-3")
+func (s *Server) newSessionID(transport string) string {
+	return fmt.Sprintf("%s-%d", transport, s.sessionCount.Add(1))
+}
+
+func (sess *session) setProtocolVersion(v string) {
+	sess.mu.Lock()
+	sess.protocolVersion = v
+	sess.mu.Unlock()
+}
+
+// structuredOutputVersion is the first protocol version with output schemas and structured content
+const structuredOutputVersion = "2025-06-18"
+
+// structuredOutput reports whether the session negotiated a protocol version with output schemas and
+// structured content. Requests without a session, or before initialize, get the newest version.
+func structuredOutput(ctx context.Context) bool {
+	sess := sessionFrom(ctx)
+	if sess == nil {
+		return true
+	}
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	// Protocol versions are dates, so they compare as strings
+	return sess.protocolVersion == "" || sess.protocolVersion >= structuredOutputVersion
+}
+
+// wantsLog reports whether the client chose a level with logging/setLevel that includes level
+func (sess *session) wantsLog(level int64) bool {
+	return sess.logging.Load() && level >= sess.logLevel.Load()
+}
+
+type sessionKey struct{}
+
+func withSession(ctx context.Context, sess *session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, sess)
+}
+
+// sessionFrom returns the session of a request, or nil for an HTTP request without a session ID
+func sessionFrom(ctx context.Context) *session {
+	sess, _ := ctx.Value(sessionKey{}).(*session)
+	return sess
+}
+
+// SessionID returns the ID of the client session a request belongs to, or "" for an HTTP request
+// without an Mcp-Session-Id header. State that tools keep for a client between calls (recent queries,
+// summarized results) is scoped by it in addition to the HTTP client (auth.ClientName).
+func SessionID(ctx context.Context) string {
+	if sess := sessionFrom(ctx); sess != nil {
+		return sess.id
+	}
+	return ""
+}
+
+// httpSessionKey identifies an HTTP session: the Mcp-Session-Id the server issued and its owner
+// (see SetSessionOwner), so that a session ID presented by another client is unknown
+type httpSessionKey struct {
+	owner string
+	id    string
+}
+
+// httpSessions holds the HTTP sessions started by initialize
+type httpSessions struct {
+	mu       sync.Mutex
+	sessions map[httpSessionKey]*session
+	order    map[string][]string // Session IDs of each owner, oldest first
+}
+
+// start begins a session of owner with a random ID. Each owner keeps at most maxHTTPSessions,
+// so that one client starting sessions does not forget those of others.
+func (h *httpSessions) start(owner string, sess *session) (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate session ID: %w", err)
+	}
+	id := hex.EncodeToString(b[:])
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.sessions == nil {
+		h.sessions = make(map[httpSessionKey]*session)
+		h.order = make(map[string][]string)
+	}
+	if order := h.order[owner]; len(order) >= maxHTTPSessions {
+		delete(h.sessions, httpSessionKey{owner, order[0]})
+		h.order[owner] = order[1:]
+	}
+	h.sessions[httpSessionKey{owner, id}] = sess
+	h.order[owner] = append(h.order[owner], id)
+	return id, nil
+}
+
+// get returns the session of owner with the ID, or nil if there is none
+func (h *httpSessions) get(owner, id string) *session {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.sessions[httpSessionKey{owner, id}]
+}
+
+// end forgets the session of owner with the ID and reports whether there was one
+func (h *httpSessions) end(owner, id string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	key := httpSessionKey{owner, id}
+	if _, ok := h.sessions[key]; !ok {
+		return false
+	}
+	delete(h.sessions, key)
+	h.order[owner] = slices.DeleteFunc(h.order[owner], func(other string) bool { return other == id })
+	if len(h.order[owner]) == 0 {
+		delete(h.order, owner)
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+)
+
+// ServeUnix accepts connections on ln (a Unix domain socket) until ctx is cancelled and serves each
+// like stdio: newline-delimited or Content-Length framed JSON-RPC, one request at a time.
+// Connections are separate sessions sharing the server, e.g. several assistant processes on one host
+// using one server instance; a client connects with e.g. `socat - UNIX-CONNECT:/path`.
+// As with HTTP, server-initiated notifications are not delivered.
+//
+// After ctx is cancelled no connection is accepted, and ServeUnix returns once every connection
+// has finished its in-flight request (see Run for the drain timeout).
+func (s *Server) ServeUnix(ctx context.Context, ln net.Listener) error {
+	stopped := make(chan struct{})
+	defer close(stopped)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = ln.Close()
+		case <-stopped:
+		}
+	}()
+
+	var conns sync.WaitGroup
+	defer conns.Wait()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+		conns.Add(1)
+		go func() {
+			defer conns.Done()
+			// Closing the connection also stops its reader, which may be blocked on the next message
+			defer conn.Close()
+			sess := newSession(s.newSessionID("unix"))
+			slog.Debug("socket connection opened", "session", sess.id)
+			if err := s.serveStream(ctx, conn, &stream{out: conn}, sess); err != nil {
+				slog.Warn("socket connection failed", "session", sess.id, "error", err)
+			}
+			slog.Debug("socket connection closed", "session", sess.id)
+		}()
+	}
+}
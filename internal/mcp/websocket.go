@@ -0,0 +1,391 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// websocketGUID is appended to Sec-WebSocket-Key to compute Sec-WebSocket-Accept (RFC 6455 section 1.3)
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// websocketSubprotocol is echoed back when the client offers it in Sec-WebSocket-Protocol
+const websocketSubprotocol = "mcp"
+
+// WebSocket opcodes (RFC 6455 section 5.2)
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// WebSocket close codes (RFC 6455 section 7.4.1)
+const (
+	closeNormal        = 1000
+	closeGoingAway     = 1001
+	closeProtocolError = 1002
+	closeTooBig        = 1009
+)
+
+// maxWebSocketInFlight bounds the requests of one WebSocket connection running at the same time.
+// Further messages are not read until one of them finishes.
+const maxWebSocketInFlight = 8
+
+// errWebSocketClosed is returned by readMessage when the client closed the connection
+var errWebSocketClosed = errors.New("websocket closed by client")
+
+// WebSocketHandler returns a handler for the WebSocket transport (RFC 6455, without extensions).
+// Each text or binary message carries one JSON-RPC message (or batch) and is answered with one
+// text message. Up to maxWebSocketInFlight requests of a connection run concurrently, so responses
+// may come out of order. As with HTTPHandler, server-initiated notifications are not delivered.
+//
+// Tool handlers get the values of the upgrade request's context, so the client authenticated by HTTP
+// middleware in front of this handler applies to every request of the connection. Their context is
+// cancelled when the connection closes. Once ctx is cancelled, the connection stops reading, waits for
+// its in-flight requests (cancelling them after the drain timeout) and closes with 1001 (going away).
+func (s *Server) WebSocketHandler(ctx context.Context) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if !sameOrigin(r) {
+			http.Error(w, "forbidden origin", http.StatusForbidden)
+			return
+		}
+		key := r.Header.Get("Sec-WebSocket-Key")
+		if !headerHasToken(r.Header, "Connection", "upgrade") || !headerHasToken(r.Header, "Upgrade", "websocket") || key == "" {
+			http.Error(w, "expected a WebSocket upgrade request", http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("Sec-WebSocket-Version") != "13" {
+			w.Header().Set("Sec-WebSocket-Version", "13")
+			http.Error(w, "unsupported WebSocket version", http.StatusUpgradeRequired)
+			return
+		}
+
+		netConn, brw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			slog.Error("failed to hijack connection for WebSocket", "error", err)
+			http.Error(w, "WebSocket is not supported", http.StatusInternalServerError)
+			return
+		}
+		defer netConn.Close()
+		// Clear the deadlines the HTTP server set for reading the request
+		_ = netConn.SetDeadline(time.Time{})
+
+		handshake := "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: " + websocketAccept(key) + "\r\n"
+		if headerHasToken(r.Header, "Sec-WebSocket-Protocol", websocketSubprotocol) {
+			handshake += "Sec-WebSocket-Protocol: " + websocketSubprotocol + "\r\n"
+		}
+		if _, err := netConn.Write([]byte(handshake + "\r\n")); err != nil {
+			return
+		}
+
+		s.webSockets.Add(1)
+		defer s.webSockets.Done()
+		conn := &wsConn{conn: netConn, reader: brw.Reader}
+		sess := newSession(s.newSessionID("websocket"))
+		slog.Debug("WebSocket connection opened", "remote", r.RemoteAddr, "session", sess.id)
+		code := s.serveWebSocket(ctx, withSession(r.Context(), sess), conn)
+		_ = conn.writeClose(code)
+		slog.Debug("WebSocket connection closed", "remote", r.RemoteAddr, "session", sess.id, "code", code)
+	})
+}
+
+// serveWebSocket reads the messages of a connection until it is closed or ctx is cancelled,
+// waits for the requests in flight and returns the close code to send
+func (s *Server) serveWebSocket(ctx, reqCtx context.Context, conn *wsConn) int {
+	// Requests outlive shutdown until the drain timeout, but not the connection
+	reqCtx, cancelRequests := context.WithCancel(context.WithoutCancel(reqCtx))
+	defer cancelRequests()
+
+	// Unblock the read on shutdown
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.conn.SetReadDeadline(time.Now())
+			timer := time.NewTimer(s.drainTimeout)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+				slog.Warn("drain timeout exceeded: cancelling in-flight WebSocket requests")
+				cancelRequests()
+			case <-done:
+			}
+		case <-done:
+		}
+	}()
+
+	reply := func(data []byte) {
+		if resp := s.processMessage(ctx, reqCtx, data); resp != nil {
+			if err := conn.writeJSON(resp); err != nil {
+				slog.Error("failed to write response", "error", err)
+			}
+		}
+	}
+
+	var inFlight sync.WaitGroup
+	defer inFlight.Wait()
+	slots := make(chan struct{}, maxWebSocketInFlight)
+	for {
+		data, err := conn.readMessage()
+		if err != nil {
+			var netErr net.Error
+			if ctx.Err() != nil && errors.As(err, &netErr) && netErr.Timeout() {
+				return closeGoingAway
+			}
+			// Nobody reads the responses of a closing connection
+			cancelRequests()
+			switch {
+			case errors.Is(err, errWebSocketClosed), errors.Is(err, io.EOF), errors.Is(err, net.ErrClosed):
+				return closeNormal
+			case errors.Is(err, errMessageTooBig):
+				slog.Warn("WebSocket message too big", "error", err)
+				return closeTooBig
+			default:
+				slog.Warn("WebSocket read failed", "error", err)
+				return closeProtocolError
+			}
+		}
+
+		if !acquireSlot(ctx, conn, slots, cancelRequests) {
+			// Shutting down: the message is rejected without running (see process)
+			reply(data)
+			continue
+		}
+		inFlight.Add(1)
+		go func() {
+			defer inFlight.Done()
+			defer func() { <-slots }()
+			reply(data)
+		}()
+	}
+}
+
+// acquireSlot waits for a free slot of the connection. At the limit it keeps watching the connection,
+// so that its closing still cancels the requests in flight (once the messages sent before the close are read).
+// It returns false when ctx is cancelled first.
+func acquireSlot(ctx context.Context, conn *wsConn, slots chan struct{}, cancelRequests context.CancelFunc) bool {
+	select {
+	case slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	// Peek does not consume the next message, but the next read must wait for it to return
+	peeked := make(chan error, 1)
+	go func() {
+		_, err := conn.reader.Peek(1)
+		peeked <- err
+	}()
+	defer func() {
+		if peeked != nil {
+			<-peeked
+		}
+	}()
+	for {
+		select {
+		case slots <- struct{}{}:
+			return true
+		case <-ctx.Done():
+			return false
+		case err := <-peeked:
+			peeked = nil
+			if err != nil {
+				cancelRequests()
+			}
+		}
+	}
+}
+
+// DrainWebSockets waits until the WebSocket connections have finished their in-flight requests
+// and closed after shutdown started (http.Server.Shutdown does not wait for hijacked connections).
+// It returns ctx's error if ctx is done first.
+func (s *Server) DrainWebSockets(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.webSockets.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// websocketAccept computes Sec-WebSocket-Accept for a Sec-WebSocket-Key
+func websocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// headerHasToken reports whether a comma-separated header contains token (case-insensitive)
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		if slices.ContainsFunc(strings.Split(v, ","), func(t string) bool {
+			return strings.EqualFold(strings.TrimSpace(t), token)
+		}) {
+			return true
+		}
+	}
+	return false
+}
+
+var errMessageTooBig = fmt.Errorf("message exceeds %d bytes", maxMessageBytes)
+
+// wsConn is the server side of a WebSocket connection
+type wsConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	mu     sync.Mutex // Serializes writes (responses of concurrent requests, pongs, close)
+}
+
+// readMessage returns the payload of the next text or binary message, reassembling fragments
+// and answering control frames in between
+func (c *wsConn) readMessage() ([]byte, error) {
+	var message []byte
+	started := false
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			return nil, errWebSocketClosed
+		case opText, opBinary:
+			if started {
+				return nil, errors.New("new message before the previous one was finished")
+			}
+			started = true
+		case opContinuation:
+			if !started {
+				return nil, errors.New("continuation frame without a message")
+			}
+		default:
+			return nil, fmt.Errorf("unknown opcode %#x", opcode)
+		}
+
+		if len(message)+len(payload) > maxMessageBytes {
+			return nil, errMessageTooBig
+		}
+		message = append(message, payload...)
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+// readFrame reads one frame and unmasks its payload (frames from clients must be masked)
+func (c *wsConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = header[0]&0x80 != 0
+	if header[0]&0x70 != 0 {
+		return false, 0, nil, errors.New("reserved bits set without a negotiated extension")
+	}
+	opcode = header[0] & 0x0F
+	if header[1]&0x80 == 0 {
+		return false, 0, nil, errors.New("unmasked frame from client")
+	}
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if opcode >= opClose && (length > 125 || !fin) {
+		return false, 0, nil, errors.New("invalid control frame")
+	}
+	if length > maxMessageBytes {
+		return false, 0, nil, errMessageTooBig
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, opcode, payload, nil
+}
+
+// writeFrame writes one unfragmented, unmasked frame
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n <= 125:
+		header = append(header, byte(n))
+	case n <= 0xFFFF:
+		header = append(header, 126)
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header = append(header, 127)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Write the frame with a single call so that frames of concurrent responses are never interleaved
+	_, err := c.conn.Write(append(header, payload...))
+	return err
+}
+
+// writeJSON sends v as a text message
+func (c *wsConn) writeJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response: %w", err)
+	}
+	return c.writeFrame(opText, data)
+}
+
+// writeClose sends a close frame with the code
+func (c *wsConn) writeClose(code int) error {
+	return c.writeFrame(opClose, binary.BigEndian.AppendUint16(nil, uint16(code)))
+}
@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// blockingTool counts the calls running at the same time; each call blocks until its context is done
+type blockingTool struct {
+	running   atomic.Int32
+	peak      atomic.Int32
+	cancelled atomic.Int32
+}
+
+func (b *blockingTool) handler(ctx context.Context, args json.RawMessage) (any, error) {
+	n := b.running.Add(1)
+	defer b.running.Add(-1)
+	for {
+		peak := b.peak.Load()
+		if n <= peak || b.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	<-ctx.Done()
+	b.cancelled.Add(1)
+	return nil, ctx.Err()
+}
+
+// dialWebSocket starts a WebSocket server for s and returns a connection that completed the handshake
+func dialWebSocket(t *testing.T, ctx context.Context, s *Server) (net.Conn, *bufio.Reader) {
+	t.Helper()
+	srv := httptest.NewServer(s.WebSocketHandler(ctx))
+	t.Cleanup(srv.Close)
+	conn, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	fmt.Fprintf(conn, "GET / HTTP/1.1\r\nHost: %s\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n", strings.TrimPrefix(srv.URL, "http://"))
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake status = %d", resp.StatusCode)
+	}
+	return conn, reader
+}
+
+// writeClientFrame writes a masked frame, as clients must
+func writeClientFrame(t *testing.T, conn net.Conn, opcode byte, payload []byte) {
+	t.Helper()
+	mask := [4]byte{1, 2, 3, 4}
+	frame := []byte{0x80 | opcode, 0x80 | 126}
+	frame = binary.BigEndian.AppendUint16(frame, uint16(len(payload)))
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := conn.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func newBlockingServer() (*Server, *blockingTool) {
+	s := NewServer("test", "0.0.0")
+	tool := &blockingTool{}
+	s.RegisterTool(Tool{Name: "test.block", InputSchema: ToolSchema{Type: "object"}}, tool.handler)
+	return s, tool
+}
+
+func callBlock(id int) []byte {
+	return []byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":"test.block"}}`, id))
+}
+
+func TestWebSocketBoundsInFlightAndCancelsOnClose(t *testing.T) {
+	s, tool := newBlockingServer()
+	conn, _ := dialWebSocket(t, context.Background(), s)
+
+	// One message more than the limit waits for a slot
+	for i := range maxWebSocketInFlight + 1 {
+		writeClientFrame(t, conn, opText, callBlock(i+1))
+	}
+	waitFor(t, "the in-flight limit to fill", func() bool { return tool.running.Load() == maxWebSocketInFlight })
+	time.Sleep(50 * time.Millisecond)
+	if peak := tool.peak.Load(); peak != maxWebSocketInFlight {
+		t.Fatalf("%d calls ran at the same time, want at most %d", peak, maxWebSocketInFlight)
+	}
+
+	// Closing the connection cancels the calls that nobody will read the responses of
+	conn.Close()
+	waitFor(t, "the calls to be cancelled", func() bool { return tool.running.Load() == 0 })
+	if tool.cancelled.Load() < maxWebSocketInFlight {
+		t.Errorf("%d calls were cancelled, want at least %d", tool.cancelled.Load(), maxWebSocketInFlight)
+	}
+}
+
+func TestWebSocketCancelsAfterDrainTimeout(t *testing.T) {
+	s, tool := newBlockingServer()
+	s.SetDrainTimeout(100 * time.Millisecond)
+	ctx, shutdown := context.WithCancel(context.Background())
+	defer shutdown()
+	conn, reader := dialWebSocket(t, ctx, s)
+
+	writeClientFrame(t, conn, opText, callBlock(1))
+	waitFor(t, "the call to start", func() bool { return tool.running.Load() == 1 })
+
+	start := time.Now()
+	shutdown()
+	waitFor(t, "the call to be cancelled", func() bool { return tool.cancelled.Load() == 1 })
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("the call was cancelled after %v, before the drain timeout", elapsed)
+	}
+
+	// The error response comes before the close frame with 1001 (going away)
+	sawResponse := false
+	for {
+		opcode, payload := readServerFrame(t, reader)
+		switch opcode {
+		case opText:
+			sawResponse = strings.Contains(string(payload), `"isError":true`)
+		case opClose:
+			if code := binary.BigEndian.Uint16(payload); code != closeGoingAway {
+				t.Errorf("close code = %d, want %d", code, closeGoingAway)
+			}
+			if !sawResponse {
+				t.Error("the cancelled call got no response before the close frame")
+			}
+			return
+		}
+	}
+}
+
+// readServerFrame reads an unmasked frame of up to 64KB
+func readServerFrame(t *testing.T, r *bufio.Reader) (byte, []byte) {
+	t.Helper()
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		t.Fatal(err)
+	}
+	length := int(header[1] & 0x7F)
+	if length == 126 {
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			t.Fatal(err)
+		}
+		length = int(binary.BigEndian.Uint16(ext[:]))
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		t.Fatal(err)
+	}
+	return header[0] & 0x0F, payload
+}
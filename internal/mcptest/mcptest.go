@@ -0,0 +1,190 @@
+// Package mcptest runs an mcp.Server over in-memory pipes and speaks JSON-RPC to it,
+// for end-to-end tests of tools (typically registered from providers backed by internal/fake).
+package mcptest
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+)
+
+// Client is the client side of a server started with Start
+type Client struct {
+	in     *io.PipeWriter // サーバーの stdin
+	out    *bufio.Reader  // サーバーの stdout
+	cancel context.CancelFunc
+	done   chan error
+
+	mu            sync.Mutex
+	nextID        int
+	notifications []mcp.Notification
+}
+
+// Start runs server in the background and initializes the MCP session
+func Start(server *mcp.Server) (*Client, error) {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	server.SetIO(inR, outW)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &Client{in: inW, out: bufio.NewReader(outR), cancel: cancel, done: make(chan error, 1)}
+	go func() {
+		err := server.Run(ctx)
+		_ = outW.Close()
+		c.done <- err
+	}()
+
+	if _, err := c.Call("initialize", mcp.InitializeParams{ProtocolVersion: "2025-03-26"}); err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	if err := c.Notify("notifications/initialized", nil); err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Call sends a request and returns its response; notifications received meanwhile are kept
+func (c *Client) Call(method string, params any) (*mcp.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nextID++
+	id := c.nextID
+	if err := c.send(message(id, method, params)); err != nil {
+		return nil, err
+	}
+
+	for {
+		line, err := c.out.ReadBytes('\n')
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response to %s: %w", method, err)
+		}
+		var msg struct {
+			ID     *int            `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(line, &msg); err != nil {
+			return nil, fmt.Errorf("invalid message from server: %w", err)
+		}
+		if msg.ID == nil {
+			c.notifications = append(c.notifications, mcp.Notification{JSONRPC: "2.0", Method: msg.Method, Params: msg.Params})
+			continue
+		}
+		if *msg.ID != id {
+			return nil, fmt.Errorf("response id %d does not match request id %d", *msg.ID, id)
+		}
+		var resp mcp.Response
+		if err := json.Unmarshal(line, &resp); err != nil {
+			return nil, fmt.Errorf("invalid response from server: %w", err)
+		}
+		if resp.Error != nil {
+			return &resp, fmt.Errorf("%s failed: %d %s", method, resp.Error.Code, resp.Error.Message)
+		}
+		return &resp, nil
+	}
+}
+
+// Notify sends a notification (no response is expected)
+func (c *Client) Notify(method string, params any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.send(message(nil, method, params))
+}
+
+// ListTools returns the registered tools
+func (c *Client) ListTools() ([]mcp.Tool, error) {
+	resp, err := c.Call("tools/list", nil)
+	if err != nil {
+		return nil, err
+	}
+	var result mcp.ToolsListResult
+	if err := decodeResult(resp, &result); err != nil {
+		return nil, err
+	}
+	return result.Tools, nil
+}
+
+// CallTool calls a tool. A tool error is returned as a result with IsError set, not as an error.
+func (c *Client) CallTool(name string, args any) (*mcp.ToolCallResult, error) {
+	raw, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode arguments: %w", err)
+	}
+	resp, err := c.Call("tools/call", mcp.ToolCallParams{Name: name, Arguments: raw})
+	if err != nil {
+		return nil, err
+	}
+	var result mcp.ToolCallResult
+	if err := decodeResult(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CallToolJSON calls a tool and decodes its JSON text result into v
+func (c *Client) CallToolJSON(name string, args any, v any) error {
+	result, err := c.CallTool(name, args)
+	if err != nil {
+		return err
+	}
+	if len(result.Content) == 0 {
+		return fmt.Errorf("%s returned no content", name)
+	}
+	if result.IsError {
+		return fmt.Errorf("%s returned an error: %s", name, result.Content[0].Text)
+	}
+	return json.Unmarshal([]byte(result.Content[0].Text), v)
+}
+
+// Notifications returns the notifications received so far (e.g. notifications/message)
+func (c *Client) Notifications() []mcp.Notification {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]mcp.Notification{}, c.notifications...)
+}
+
+// Close ends the session (closes the server's stdin) and waits for the server to stop
+func (c *Client) Close() error {
+	_ = c.in.Close()
+	c.cancel()
+	return <-c.done
+}
+
+// message は JSON-RPC のリクエスト（id が nil なら通知）を組み立てる。params が nil なら省略する
+func message(id any, method string, params any) map[string]any {
+	msg := map[string]any{"jsonrpc": "2.0", "method": method}
+	if id != nil {
+		msg["id"] = id
+	}
+	if params != nil {
+		msg["params"] = params
+	}
+	return msg
+}
+
+func (c *Client) send(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := c.in.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write to server: %w", err)
+	}
+	return nil
+}
+
+// decodeResult は Response.Result（any にデコード済み）を型付きの値に変換する
+func decodeResult(resp *mcp.Response, v any) error {
+	data, err := json.Marshal(resp.Result)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,88 @@
+package mcptest_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/fake"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/guardrail"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcptest"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/provider"
+)
+
+// start はフェイクの Logging を使う logging.* ツールとガードレールを登録したサーバーを起動する
+func start(t *testing.T) *mcptest.Client {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.AllowedProjectIDs = []string{fake.Project}
+	guard := guardrail.New(cfg)
+
+	server := mcp.NewServer("test", "0.0.0")
+	server.Use(guard.Middleware())
+	env := provider.Env{Config: cfg, Guard: guard}
+	p := logging.NewProviderWithClient(env, logging.NewClientWithAPI(fake.NewLoggingWithFixtures()))
+	if err := provider.RegisterTools(server, []provider.ToolProvider{p}); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := mcptest.Start(server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Errorf("server stopped with an error: %v", err)
+		}
+	})
+	return client
+}
+
+func TestEndToEnd(t *testing.T) {
+	client := start(t)
+
+	tools, err := client.ListTools()
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := slices.IndexFunc(tools, func(tool mcp.Tool) bool { return tool.Name == "logging.query" })
+	if i < 0 {
+		t.Fatalf("logging.query is not listed: %v", tools)
+	}
+	// 読み取り専用モードでは書き込みツールを登録しない
+	if slices.ContainsFunc(tools, func(tool mcp.Tool) bool { return !tool.IsReadOnly() }) {
+		t.Error("a write tool is listed in read-only mode")
+	}
+	if _, ok := tools[i].InputSchema.Properties["billing_project"]; !ok {
+		t.Error("the guardrail did not add billing_project to logging.query")
+	}
+
+	var result logging.QueryResult
+	if err := client.CallToolJSON("logging.query", map[string]any{"project_id": fake.Project, "min_severity": "DEFAULT"}, &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Entries) == 0 || result.QueryMeta.ProjectID != fake.Project {
+		t.Errorf("got %d entries for %q, want the fixtures of %s", len(result.Entries), result.QueryMeta.ProjectID, fake.Project)
+	}
+}
+
+func TestEndToEndGuardrailDenies(t *testing.T) {
+	client := start(t)
+
+	// ガードレールの拒否は JSON-RPC のエラーではなくツールのエラー（isError）として返る
+	result, err := client.CallTool("logging.query", map[string]any{"project_id": "other-project"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsError || len(result.Content) == 0 || !strings.Contains(result.Content[0].Text, "not in the allowed list") {
+		t.Errorf("got %+v, want a tool error saying the project is not allowed", result)
+	}
+
+	// 未知のツールは JSON-RPC のエラーになる
+	if _, err := client.CallTool("no.such_tool", map[string]any{}); err == nil {
+		t.Error("calling an unknown tool succeeded")
+	}
+}
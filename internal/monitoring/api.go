@@ -0,0 +1,105 @@
+package monitoring
+
+import (
+	"context"
+
+	monitoring "cloud.google.com/go/monitoring/apiv3/v2"
+	"cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	metricpb "google.golang.org/genproto/googleapis/api/metric"
+	monitoredrespb "google.golang.org/genproto/googleapis/api/monitoredres"
+)
+
+// Iterator is the part of the Cloud Monitoring iterators the tools use
+type Iterator[T any] interface {
+	Next() (T, error)
+}
+
+// API is the Cloud Monitoring API surface the tools use.
+// It is implemented by the real clients and by the in-memory fake (internal/fake).
+type API interface {
+	ListTimeSeries(ctx context.Context, req *monitoringpb.ListTimeSeriesRequest) Iterator[*monitoringpb.TimeSeries]
+	ListMetricDescriptors(ctx context.Context, req *monitoringpb.ListMetricDescriptorsRequest) Iterator[*metricpb.MetricDescriptor]
+	GetMetricDescriptor(ctx context.Context, req *monitoringpb.GetMetricDescriptorRequest) (*metricpb.MetricDescriptor, error)
+	ListGroups(ctx context.Context, req *monitoringpb.ListGroupsRequest) Iterator[*monitoringpb.Group]
+	ListGroupMembers(ctx context.Context, req *monitoringpb.ListGroupMembersRequest) Iterator[*monitoredrespb.MonitoredResource]
+	ListServices(ctx context.Context, req *monitoringpb.ListServicesRequest) Iterator[*monitoringpb.Service]
+	ListServiceLevelObjectives(ctx context.Context, req *monitoringpb.ListServiceLevelObjectivesRequest) Iterator[*monitoringpb.ServiceLevelObjective]
+	ListSnoozes(ctx context.Context, req *monitoringpb.ListSnoozesRequest) Iterator[*monitoringpb.Snooze]
+	GetSnooze(ctx context.Context, req *monitoringpb.GetSnoozeRequest) (*monitoringpb.Snooze, error)
+	CreateSnooze(ctx context.Context, req *monitoringpb.CreateSnoozeRequest) (*monitoringpb.Snooze, error)
+	UpdateSnooze(ctx context.Context, req *monitoringpb.UpdateSnoozeRequest) (*monitoringpb.Snooze, error)
+	GetAlertPolicy(ctx context.Context, req *monitoringpb.GetAlertPolicyRequest) (*monitoringpb.AlertPolicy, error)
+	CreateTimeSeries(ctx context.Context, req *monitoringpb.CreateTimeSeriesRequest) error
+	Close() error
+}
+
+// gcpAPI は実際の Cloud Monitoring クライアントによる API の実装
+type gcpAPI struct {
+	metricClient  *monitoring.MetricClient
+	groupClient   *monitoring.GroupClient
+	serviceClient *monitoring.ServiceMonitoringClient
+	snoozeClient  *monitoring.SnoozeClient
+	alertClient   *monitoring.AlertPolicyClient
+}
+
+func (a *gcpAPI) ListTimeSeries(ctx context.Context, req *monitoringpb.ListTimeSeriesRequest) Iterator[*monitoringpb.TimeSeries] {
+	return a.metricClient.ListTimeSeries(ctx, req)
+}
+
+func (a *gcpAPI) ListMetricDescriptors(ctx context.Context, req *monitoringpb.ListMetricDescriptorsRequest) Iterator[*metricpb.MetricDescriptor] {
+	return a.metricClient.ListMetricDescriptors(ctx, req)
+}
+
+func (a *gcpAPI) GetMetricDescriptor(ctx context.Context, req *monitoringpb.GetMetricDescriptorRequest) (*metricpb.MetricDescriptor, error) {
+	return a.metricClient.GetMetricDescriptor(ctx, req)
+}
+
+func (a *gcpAPI) ListGroups(ctx context.Context, req *monitoringpb.ListGroupsRequest) Iterator[*monitoringpb.Group] {
+	return a.groupClient.ListGroups(ctx, req)
+}
+
+func (a *gcpAPI) ListGroupMembers(ctx context.Context, req *monitoringpb.ListGroupMembersRequest) Iterator[*monitoredrespb.MonitoredResource] {
+	return a.groupClient.ListGroupMembers(ctx, req)
+}
+
+func (a *gcpAPI) ListServices(ctx context.Context, req *monitoringpb.ListServicesRequest) Iterator[*monitoringpb.Service] {
+	return a.serviceClient.ListServices(ctx, req)
+}
+
+func (a *gcpAPI) ListServiceLevelObjectives(ctx context.Context, req *monitoringpb.ListServiceLevelObjectivesRequest) Iterator[*monitoringpb.ServiceLevelObjective] {
+	return a.serviceClient.ListServiceLevelObjectives(ctx, req)
+}
+
+func (a *gcpAPI) ListSnoozes(ctx context.Context, req *monitoringpb.ListSnoozesRequest) Iterator[*monitoringpb.Snooze] {
+	return a.snoozeClient.ListSnoozes(ctx, req)
+}
+
+func (a *gcpAPI) GetSnooze(ctx context.Context, req *monitoringpb.GetSnoozeRequest) (*monitoringpb.Snooze, error) {
+	return a.snoozeClient.GetSnooze(ctx, req)
+}
+
+func (a *gcpAPI) CreateSnooze(ctx context.Context, req *monitoringpb.CreateSnoozeRequest) (*monitoringpb.Snooze, error) {
+	return a.snoozeClient.CreateSnooze(ctx, req)
+}
+
+func (a *gcpAPI) UpdateSnooze(ctx context.Context, req *monitoringpb.UpdateSnoozeRequest) (*monitoringpb.Snooze, error) {
+	return a.snoozeClient.UpdateSnooze(ctx, req)
+}
+
+func (a *gcpAPI) GetAlertPolicy(ctx context.Context, req *monitoringpb.GetAlertPolicyRequest) (*monitoringpb.AlertPolicy, error) {
+	return a.alertClient.GetAlertPolicy(ctx, req)
+}
+
+func (a *gcpAPI) CreateTimeSeries(ctx context.Context, req *monitoringpb.CreateTimeSeriesRequest) error {
+	return a.metricClient.CreateTimeSeries(ctx, req)
+}
+
+func (a *gcpAPI) Close() error {
+	var firstErr error
+	for _, closer := range []interface{ Close() error }{a.alertClient, a.snoozeClient, a.serviceClient, a.groupClient, a.metricClient} {
+		if err := closer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
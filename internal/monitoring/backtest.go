@@ -0,0 +1,373 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/timerange"
+)
+
+// BacktestAlertPolicyParams are the parameters for monitoring.backtest_alert_policy
+type BacktestAlertPolicyParams struct {
+	ProjectID string             `json:"project_id"`
+	PolicyID  string             `json:"policy_id,omitempty"` // ID or "projects/X/alertPolicies/ID"
+	Condition *BacktestCondition `json:"condition,omitempty"` // Inline threshold condition (instead of policy_id)
+	Days      int                `json:"days"`
+	MaxSeries int                `json:"max_series"`
+}
+
+// BacktestCondition is a threshold condition in the shape of an alert policy's conditionThreshold
+type BacktestCondition struct {
+	DisplayName          string       `json:"display_name,omitempty"`
+	Filter               string       `json:"filter"` // Must contain metric.type = "..."
+	AlignmentPeriodSec   int          `json:"alignment_period_sec,omitempty"`
+	PerSeriesAligner     string       `json:"per_series_aligner,omitempty"`
+	CrossSeriesReducer   string       `json:"cross_series_reducer,omitempty"`
+	GroupByFields        []string     `json:"group_by_fields,omitempty"`
+	SecondaryAggregation *Aggregation `json:"secondary_aggregation,omitempty"`
+	Comparison           string       `json:"comparison"`
+	ThresholdValue       float64      `json:"threshold_value"`
+	DurationSec          int          `json:"duration_sec,omitempty"`
+	TriggerCount         int          `json:"trigger_count,omitempty"`   // Series that must violate at once (default: 1)
+	TriggerPercent       float64      `json:"trigger_percent,omitempty"` // Or the percentage of series
+}
+
+// BacktestAlertPolicyResult is the result of monitoring.backtest_alert_policy
+type BacktestAlertPolicyResult struct {
+	QueryMeta  BacktestQueryMeta         `json:"query_meta"`
+	Conditions []BacktestConditionResult `json:"conditions"`
+	Incidents  []Incident                `json:"incidents"` // When the policy as a whole would have fired
+	Stats      BacktestStats             `json:"stats"`
+}
+
+type BacktestQueryMeta struct {
+	ProjectID   string   `json:"project_id"`
+	Policy      string   `json:"policy,omitempty"`
+	DisplayName string   `json:"display_name,omitempty"`
+	Combiner    string   `json:"combiner,omitempty"`
+	Start       string   `json:"start"`
+	End         string   `json:"end"`
+	Warnings    []string `json:"warnings,omitempty"`
+}
+
+// BacktestConditionResult is the replay of one condition
+type BacktestConditionResult struct {
+	DisplayName  string     `json:"display_name,omitempty"`
+	Condition    string     `json:"condition,omitempty"` // e.g. "value > 0.9 for 300s"
+	Skipped      string     `json:"skipped,omitempty"`   // Why the condition could not be replayed
+	SeriesCount  int        `json:"series_count"`
+	FiringSeries int        `json:"firing_series"`
+	Incidents    []Incident `json:"incidents"`
+}
+
+// Incident is an interval during which the condition (or the policy) would have been firing
+type Incident struct {
+	Start       string `json:"start"` // When it would have fired
+	End         string `json:"end"`   // When it would have recovered (or the end of the range)
+	DurationSec int64  `json:"duration_sec"`
+}
+
+type BacktestStats struct {
+	IncidentCount int   `json:"incident_count"`
+	FiringSec     int64 `json:"firing_sec"` // Total time the policy would have been firing
+}
+
+// span は発火していた区間 [start, end)
+type span struct {
+	start, end time.Time
+}
+
+// BacktestAlertPolicy replays an alert policy's threshold conditions (or an inline condition)
+// over the last days and reports when it would have fired
+func (c *Client) BacktestAlertPolicy(ctx context.Context, params BacktestAlertPolicyParams) (*BacktestAlertPolicyResult, error) {
+	end := timerange.Now()
+	start := end.Add(-time.Duration(params.Days) * 24 * time.Hour)
+	result := &BacktestAlertPolicyResult{
+		QueryMeta: BacktestQueryMeta{
+			ProjectID: params.ProjectID,
+			Start:     start.Format(time.RFC3339),
+			End:       end.Format(time.RFC3339),
+		},
+		Conditions: []BacktestConditionResult{},
+		Incidents:  []Incident{},
+	}
+
+	// 条件: ポリシーの threshold 条件、またはインラインの条件
+	type namedCondition struct {
+		name      string
+		condition *BacktestCondition
+		skipped   string
+	}
+	conditions := []namedCondition{}
+	combiner := "OR"
+	if params.Condition != nil {
+		conditions = append(conditions, namedCondition{name: params.Condition.DisplayName, condition: params.Condition})
+	} else {
+		name := params.PolicyID
+		if !strings.HasPrefix(name, "projects/") {
+			name = fmt.Sprintf("projects/%s/alertPolicies/%s", params.ProjectID, name)
+		}
+		policy, err := c.api.GetAlertPolicy(ctx, &monitoringpb.GetAlertPolicyRequest{Name: name})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get alert policy: %w", err)
+		}
+		result.QueryMeta.Policy = policy.GetName()
+		result.QueryMeta.DisplayName = policy.GetDisplayName()
+		if policy.GetCombiner() != monitoringpb.AlertPolicy_COMBINE_UNSPECIFIED {
+			combiner = policy.GetCombiner().String()
+		}
+		for _, pc := range policy.GetConditions() {
+			cond, err := conditionFromPolicy(pc)
+			nc := namedCondition{name: pc.GetDisplayName(), condition: cond}
+			if err != nil {
+				nc.skipped = err.Error()
+			}
+			conditions = append(conditions, nc)
+		}
+	}
+	result.QueryMeta.Combiner = combiner
+
+	var evaluated [][]span
+	for _, nc := range conditions {
+		cr := BacktestConditionResult{DisplayName: nc.name, Skipped: nc.skipped, Incidents: []Incident{}}
+		if nc.skipped == "" {
+			spans, err := c.backtestCondition(ctx, params, nc.condition, start, end, &cr)
+			if err != nil {
+				return nil, fmt.Errorf("condition %q: %w", nc.name, err)
+			}
+			evaluated = append(evaluated, spans)
+		}
+		result.Conditions = append(result.Conditions, cr)
+	}
+
+	if len(evaluated) < len(conditions) {
+		result.QueryMeta.Warnings = append(result.QueryMeta.Warnings,
+			"some conditions were skipped; policy incidents only reflect the replayed conditions")
+	}
+	if combiner == "AND_WITH_MATCHING_RESOURCE" {
+		result.QueryMeta.Warnings = append(result.QueryMeta.Warnings,
+			"AND_WITH_MATCHING_RESOURCE is approximated as AND (resources are not matched across conditions)")
+	}
+
+	// ポリシー全体: OR はどれかの条件、AND はすべての条件が発火していた区間
+	need := 1
+	if combiner != "OR" {
+		need = len(evaluated)
+	}
+	for _, s := range coverage(evaluated, need) {
+		result.Incidents = append(result.Incidents, toIncident(s))
+		result.Stats.FiringSec += int64(s.end.Sub(s.start) / time.Second)
+	}
+	result.Stats.IncidentCount = len(result.Incidents)
+	return result, nil
+}
+
+// backtestCondition は1つの条件を期間全体で評価し、条件として発火していた区間を返す
+func (c *Client) backtestCondition(ctx context.Context, params BacktestAlertPolicyParams, cond *BacktestCondition, start, end time.Time, cr *BacktestConditionResult) ([]span, error) {
+	m := metricTypeInFilter.FindStringSubmatch(cond.Filter)
+	if m == nil {
+		return nil, fmt.Errorf(`filter must contain metric.type = "..."`)
+	}
+
+	threshold := cond.ThresholdValue
+	eval, err := c.EvaluateThreshold(ctx, EvaluateThresholdParams{
+		QueryTimeSeriesParams: QueryTimeSeriesParams{
+			ProjectID:            params.ProjectID,
+			MetricType:           m[1],
+			Filter:               cond.Filter,
+			AlignmentPeriodSec:   cond.AlignmentPeriodSec,
+			PerSeriesAligner:     cond.PerSeriesAligner,
+			CrossSeriesReducer:   cond.CrossSeriesReducer,
+			GroupByFields:        cond.GroupByFields,
+			SecondaryAggregation: cond.SecondaryAggregation,
+			TimeRange:            TimeRange{Start: start.Format(time.RFC3339), End: end.Format(time.RFC3339)},
+			MaxSeries:            params.MaxSeries,
+		},
+		Comparison:  cond.Comparison,
+		Threshold:   &threshold,
+		DurationSec: cond.DurationSec,
+	})
+	if err != nil {
+		return nil, err
+	}
+	cr.Condition = eval.QueryMeta.Condition
+	cr.SeriesCount = eval.Stats.SeriesCount
+	cr.FiringSeries = eval.Stats.FiringSeries
+
+	period := evaluationPeriod(cond.AlignmentPeriodSec, cond.SecondaryAggregation)
+
+	// 系列ごとの違反区間: 発火した時刻から、最後に違反したポイントのアライメント期間の終わりまで
+	perSeries := make([][]span, 0, len(eval.Series))
+	for _, ts := range eval.Series {
+		spans := []span{}
+		for _, iv := range ts.Intervals {
+			fired, err1 := time.Parse(time.RFC3339, iv.FiredAt)
+			last, err2 := time.Parse(time.RFC3339, iv.End)
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			spans = append(spans, span{start: fired, end: last.Add(period)})
+		}
+		perSeries = append(perSeries, spans)
+	}
+
+	// trigger: 同時に違反している系列が count（または percent）以上で条件として発火
+	need := max(cond.TriggerCount, 1)
+	if cond.TriggerPercent > 0 {
+		need = max(int(math.Ceil(cond.TriggerPercent/100*float64(len(perSeries)))), 1)
+	}
+	spans := coverage(perSeries, need)
+	for _, s := range spans {
+		cr.Incidents = append(cr.Incidents, toIncident(s))
+	}
+	return spans, nil
+}
+
+// metricTypeInFilter はフィルタ中の metric.type = "X"
+var metricTypeInFilter = regexp.MustCompile(`metric\.type\s*=\s*"([^"]+)"`)
+
+// conditionFromPolicy はアラートポリシーの threshold 条件を BacktestCondition に変換する
+// それ以外の条件（absent / MQL / PromQL / ログ一致）と比率の条件は再生できない
+func conditionFromPolicy(pc *monitoringpb.AlertPolicy_Condition) (*BacktestCondition, error) {
+	th := pc.GetConditionThreshold()
+	if th == nil {
+		return nil, fmt.Errorf("only threshold conditions can be replayed")
+	}
+	if th.GetDenominatorFilter() != "" {
+		return nil, fmt.Errorf("ratio conditions (denominator_filter) cannot be replayed")
+	}
+	if th.GetComparison() == monitoringpb.ComparisonType_COMPARISON_UNSPECIFIED {
+		return nil, fmt.Errorf("conditions without a comparison cannot be replayed")
+	}
+	if th.GetForecastOptions() != nil {
+		return nil, fmt.Errorf("forecast conditions cannot be replayed")
+	}
+	aggs := th.GetAggregations()
+	if len(aggs) > 2 {
+		return nil, fmt.Errorf("conditions with more than two aggregations cannot be replayed")
+	}
+
+	cond := &BacktestCondition{
+		DisplayName:    pc.GetDisplayName(),
+		Filter:         th.GetFilter(),
+		Comparison:     th.GetComparison().String(),
+		ThresholdValue: th.GetThresholdValue(),
+		DurationSec:    int(th.GetDuration().AsDuration() / time.Second),
+		TriggerCount:   int(th.GetTrigger().GetCount()),
+		TriggerPercent: th.GetTrigger().GetPercent(),
+	}
+	if len(aggs) > 0 {
+		cond.AlignmentPeriodSec = int(aggs[0].GetAlignmentPeriod().AsDuration() / time.Second)
+		cond.PerSeriesAligner = aggs[0].GetPerSeriesAligner().String()
+		cond.CrossSeriesReducer = aggs[0].GetCrossSeriesReducer().String()
+		cond.GroupByFields = aggs[0].GetGroupByFields()
+	}
+	if len(aggs) > 1 {
+		cond.SecondaryAggregation = &Aggregation{
+			AlignmentPeriodSec: int(aggs[1].GetAlignmentPeriod().AsDuration() / time.Second),
+			PerSeriesAligner:   aggs[1].GetPerSeriesAligner().String(),
+			CrossSeriesReducer: aggs[1].GetCrossSeriesReducer().String(),
+			GroupByFields:      aggs[1].GetGroupByFields(),
+		}
+	}
+	return cond, nil
+}
+
+// coverage は区間のリスト群のうち need 個以上が同時に重なっている区間を返す
+func coverage(lists [][]span, need int) []span {
+	if need <= 0 || len(lists) < need {
+		return nil
+	}
+	type event struct {
+		t     time.Time
+		delta int
+	}
+	events := []event{}
+	for _, list := range lists {
+		// 1つのリスト内の重なりを先にまとめ、同じリストが二重に数えられないようにする
+		for _, s := range merge(list) {
+			events = append(events, event{s.start, 1}, event{s.end, -1})
+		}
+	}
+	// 同時刻は開始を先に処理し、接した区間をつなげる
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].t.Equal(events[j].t) {
+			return events[i].t.Before(events[j].t)
+		}
+		return events[i].delta > events[j].delta
+	})
+
+	result := []span{}
+	active := 0
+	var open time.Time
+	for _, e := range events {
+		before := active
+		active += e.delta
+		switch {
+		case before < need && active >= need:
+			open = e.t
+		case before >= need && active < need:
+			result = append(result, span{start: open, end: e.t})
+		}
+	}
+	return result
+}
+
+// merge は重なる・接する区間をまとめる
+func merge(spans []span) []span {
+	sorted := append([]span(nil), spans...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].start.Before(sorted[j].start) })
+	merged := []span{}
+	for _, s := range sorted {
+		if n := len(merged); n > 0 && !s.start.After(merged[n-1].end) {
+			if s.end.After(merged[n-1].end) {
+				merged[n-1].end = s.end
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+func toIncident(s span) Incident {
+	return Incident{
+		Start:       s.start.Format(time.RFC3339),
+		End:         s.end.Format(time.RFC3339),
+		DurationSec: int64(s.end.Sub(s.start) / time.Second),
+	}
+}
+
+// BacktestAlertPolicyHandlerWithGuardrail returns a handler for the monitoring.backtest_alert_policy tool
+func (c *Client) BacktestAlertPolicyHandlerWithGuardrail(v Validator) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params BacktestAlertPolicyParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if (params.PolicyID == "") == (params.Condition == nil) {
+			return nil, fmt.Errorf("exactly one of policy_id or condition is required")
+		}
+		if params.Days <= 0 {
+			params.Days = 3
+		}
+
+		// ガードレール: 再生する期間は最大範囲まで、系列数は上限まで
+		end := timerange.Now()
+		if err := v.ValidateTimeRange(ctx, end.Add(-time.Duration(params.Days)*24*time.Hour), end); err != nil {
+			return nil, fmt.Errorf("days: %w", err)
+		}
+		params.MaxSeries = v.ClampTimeSeriesLimit(ctx, params.MaxSeries)
+
+		return c.BacktestAlertPolicy(ctx, params)
+	}
+}
@@ -0,0 +1,232 @@
+package monitoring
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"time"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+)
+
+// チャート画像のサイズと余白（ピクセル）
+const (
+	chartWidth  = 800
+	chartHeight = 400
+	chartMargin = 20
+	chartGridN  = 4 // 横方向のグリッド線の分割数
+)
+
+// chartPalette は系列ごとの線の色（凡例の color と対応）
+var chartPalette = []struct {
+	name string
+	rgba color.RGBA
+}{
+	{"blue", color.RGBA{0x1f, 0x77, 0xb4, 0xff}},
+	{"orange", color.RGBA{0xff, 0x7f, 0x0e, 0xff}},
+	{"green", color.RGBA{0x2c, 0xa0, 0x2c, 0xff}},
+	{"red", color.RGBA{0xd6, 0x27, 0x28, 0xff}},
+	{"purple", color.RGBA{0x94, 0x67, 0xbd, 0xff}},
+	{"brown", color.RGBA{0x8c, 0x56, 0x4b, 0xff}},
+	{"pink", color.RGBA{0xe3, 0x77, 0xc2, 0xff}},
+	{"gray", color.RGBA{0x7f, 0x7f, 0x7f, 0xff}},
+	{"olive", color.RGBA{0xbc, 0xbd, 0x22, 0xff}},
+	{"cyan", color.RGBA{0x17, 0xbe, 0xcf, 0xff}},
+}
+
+// Chart describes the axes and legend of a rendered chart image.
+// The image itself has no text, so clients read labels from here.
+type Chart struct {
+	Title  string        `json:"title,omitempty"`
+	Start  string        `json:"start"` // Left edge of the x axis
+	End    string        `json:"end"`   // Right edge of the x axis
+	YMin   float64       `json:"y_min"` // Bottom edge of the y axis
+	YMax   float64       `json:"y_max"` // Top edge of the y axis
+	Legend []ChartLegend `json:"legend"`
+}
+
+type ChartLegend struct {
+	Color string `json:"color"`
+	Label string `json:"label"`
+}
+
+// ChartResult is the text part of monitoring.query_time_series with render="chart"
+type ChartResult struct {
+	QueryMeta QueryMeta         `json:"query_meta"`
+	Chart     Chart             `json:"chart"`
+	Series    []SparklineSeries `json:"series"`
+	Stats     ResultStats       `json:"stats"`
+}
+
+// chartPoint は時刻をパース済みのデータポイント
+type chartPoint struct {
+	t time.Time
+	v float64
+}
+
+// RenderChart draws the series as a PNG line chart and returns the image with its axes/legend
+func RenderChart(title string, series []TimeSeries) ([]byte, Chart, error) {
+	chart := Chart{Title: title, Legend: []ChartLegend{}}
+
+	// 時刻をパースし、軸の範囲を求める
+	lines := make([][]chartPoint, len(series))
+	var tMin, tMax time.Time
+	yMin, yMax := math.Inf(1), math.Inf(-1)
+	for i, ts := range series {
+		for _, p := range ts.Points {
+			t, err := time.Parse(time.RFC3339, p.Time)
+			if err != nil {
+				continue
+			}
+			lines[i] = append(lines[i], chartPoint{t: t, v: p.Value})
+			if tMin.IsZero() || t.Before(tMin) {
+				tMin = t
+			}
+			if t.After(tMax) {
+				tMax = t
+			}
+			yMin = math.Min(yMin, p.Value)
+			yMax = math.Max(yMax, p.Value)
+		}
+		// APIは新しい順に返すので古い順に並べ替える
+		for l, r := 0, len(lines[i])-1; l < r; l, r = l+1, r-1 {
+			lines[i][l], lines[i][r] = lines[i][r], lines[i][l]
+		}
+	}
+	if tMin.IsZero() {
+		return nil, chart, fmt.Errorf("no data points to chart")
+	}
+
+	// 非負の値は0を下端にする（値の大小を見誤らないため）
+	if yMin > 0 {
+		yMin = 0
+	}
+	if yMax == yMin {
+		yMax = yMin + 1
+	}
+	chart.Start = tMin.Format(time.RFC3339)
+	chart.End = tMax.Format(time.RFC3339)
+	chart.YMin = yMin
+	chart.YMax = yMax
+
+	img := image.NewRGBA(image.Rect(0, 0, chartWidth, chartHeight))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	plotW := chartWidth - 2*chartMargin
+	plotH := chartHeight - 2*chartMargin
+	grid := color.RGBA{0xe0, 0xe0, 0xe0, 0xff}
+	for i := 0; i <= chartGridN; i++ {
+		y := chartMargin + plotH*i/chartGridN
+		drawLine(img, chartMargin, y, chartMargin+plotW, y, grid)
+	}
+	axis := color.RGBA{0x40, 0x40, 0x40, 0xff}
+	drawLine(img, chartMargin, chartMargin, chartMargin, chartMargin+plotH, axis)
+	drawLine(img, chartMargin, chartMargin+plotH, chartMargin+plotW, chartMargin+plotH, axis)
+
+	span := tMax.Sub(tMin).Seconds()
+	toXY := func(p chartPoint) (int, int) {
+		x := chartMargin
+		if span > 0 {
+			x += int(p.t.Sub(tMin).Seconds() / span * float64(plotW))
+		}
+		y := chartMargin + plotH - int((p.v-yMin)/(yMax-yMin)*float64(plotH))
+		return x, y
+	}
+
+	for i, pts := range lines {
+		c := chartPalette[i%len(chartPalette)]
+		chart.Legend = append(chart.Legend, ChartLegend{Color: c.name, Label: SeriesLabel(series[i])})
+		for j, p := range pts {
+			x, y := toXY(p)
+			if j == 0 {
+				// 1点だけの系列も見えるように点を打つ
+				fillRect(img, x-1, y-1, x+1, y+1, c.rgba)
+				continue
+			}
+			px, py := toXY(pts[j-1])
+			drawLine(img, px, py, x, y, c.rgba)
+			drawLine(img, px, py+1, x, y+1, c.rgba) // 2px幅
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, chart, fmt.Errorf("failed to encode chart: %w", err)
+	}
+	return buf.Bytes(), chart, nil
+}
+
+// ChartContent renders query_time_series results as a text summary plus a PNG image.
+// The structured content stays the full result.
+func ChartContent(result *QueryTimeSeriesResult) (mcp.Structured, error) {
+	data, chart, err := RenderChart(result.QueryMeta.MetricType, result.Series)
+	if err != nil {
+		return mcp.Structured{}, err
+	}
+
+	summary := ChartResult{
+		QueryMeta: result.QueryMeta,
+		Chart:     chart,
+		Series:    ToSparklines(result).Series,
+		Stats:     result.Stats,
+	}
+	text, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return mcp.Structured{}, fmt.Errorf("failed to marshal chart summary: %w", err)
+	}
+
+	content := mcp.Content{
+		{Type: "text", Text: string(text)},
+		mcp.Image(data, "image/png"),
+	}
+	return mcp.Structured{Content: content, Result: result}, nil
+}
+
+// drawLine はブレゼンハムのアルゴリズムで線分を描く
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA) {
+	dx := abs(x1 - x0)
+	dy := -abs(y1 - y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		img.SetRGBA(x0, y0, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func fillRect(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA) {
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
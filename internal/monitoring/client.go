@@ -4,13 +4,22 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	monitoring "cloud.google.com/go/monitoring/apiv3/v2"
 	"cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
 	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/timerange"
 )
 
 // QueryTimeSeriesParams are the parameters for monitoring.query_time_series
@@ -19,9 +28,32 @@ type QueryTimeSeriesParams struct {
 	MetricType         string            `json:"metric_type"`
 	ResourceType       string            `json:"resource_type,omitempty"`
 	Filters            map[string]string `json:"filters,omitempty"`
+	Filter             string            `json:"filter,omitempty"` // Raw Monitoring filter ANDed to the query
 	AlignmentPeriodSec int               `json:"alignment_period_sec"`
+	PerSeriesAligner   string            `json:"per_series_aligner,omitempty"`   // e.g. "ALIGN_RATE", "RATE"
+	CrossSeriesReducer string            `json:"cross_series_reducer,omitempty"` // e.g. "REDUCE_SUM", "SUM"
+	GroupByFields      []string          `json:"group_by_fields,omitempty"`
 	TimeRange          TimeRange         `json:"time_range"`
 	MaxSeries          int               `json:"max_series"`
+	MaxPointsPerSeries int               `json:"max_points_per_series,omitempty"` // 0 = 制限なし
+	Downsample         string            `json:"downsample,omitempty"`            // "mean" (default), "min", "max"
+	StatsOnly          bool              `json:"stats_only,omitempty"`            // true = ポイントを返さず要約統計のみ
+	Normalize          bool              `json:"normalize,omitempty"`             // true = 表示単位に換算した値も返す
+	Render             string            `json:"render,omitempty"`                // "points" (default), "sparkline" or "chart"
+	// 一次集約の結果にさらにかける二次集約（secondaryAggregation）
+	SecondaryAggregation *Aggregation `json:"secondary_aggregation,omitempty"`
+	// 同じ系列を指定期間だけ過去にずらした比較用の系列も返す（"1d", "7d", "1w" など）
+	TimeShift string `json:"time_shift,omitempty"`
+	// true = ポイントを含まない系列のヘッダー（ラベル）だけを返す（View=HEADERS）
+	SeriesOnly bool `json:"series_only,omitempty"`
+}
+
+// Aggregation is an aggregation stage given by name (used for secondary_aggregation)
+type Aggregation struct {
+	AlignmentPeriodSec int      `json:"alignment_period_sec,omitempty"` // 0 = 一次集約と同じ
+	PerSeriesAligner   string   `json:"per_series_aligner,omitempty"`
+	CrossSeriesReducer string   `json:"cross_series_reducer,omitempty"`
+	GroupByFields      []string `json:"group_by_fields,omitempty"`
 }
 
 type TimeRange struct {
@@ -37,16 +69,32 @@ type QueryTimeSeriesResult struct {
 }
 
 type QueryMeta struct {
-	ProjectID  string `json:"project_id"`
-	MetricType string `json:"metric_type"`
-	Start      string `json:"start"`
-	End        string `json:"end"`
+	ProjectID  string    `json:"project_id"`
+	MetricType string    `json:"metric_type"`
+	Start      string    `json:"start"`
+	End        string    `json:"end"`
+	Unit       *UnitInfo `json:"unit,omitempty"`
+	// resource_rules で付け足した条件
+	Restriction string `json:"restriction,omitempty"`
+	// time_shift 指定時のずらした期間（比較用の系列は series[].time_shift を持つ）
+	TimeShift string `json:"time_shift,omitempty"`
+	// 実際に使ったアライナ（カウンタでは省略時に ALIGN_RATE を選ぶ）
+	PerSeriesAligner string   `json:"per_series_aligner,omitempty"`
+	Warnings         []string `json:"warnings,omitempty"`
 }
 
 type TimeSeries struct {
-	Metric   MetricLabels   `json:"metric"`
-	Resource ResourceLabels `json:"resource"`
-	Points   []DataPoint    `json:"points"`
+	Metric    MetricLabels   `json:"metric"`
+	Resource  ResourceLabels `json:"resource"`
+	Summary   *SeriesSummary `json:"summary,omitempty"` // series_only では省略
+	Points    []DataPoint    `json:"points"`
+	Freshness *Freshness     `json:"freshness,omitempty"` // 最新ポイントの鮮度と欠損区間（series_only では省略）
+	// time_shift で取得した比較用の系列（ポイントの時刻は現在の期間に合わせて戻してある）
+	TimeShift string `json:"time_shift,omitempty"`
+	// 系列のリソースのログを読む LQL（logging.query の filter にそのまま渡せる）
+	SuggestedLogFilter string `json:"suggested_log_filter,omitempty"`
+	// 分布のポイントに付いたトレース付きのサンプル（値の大きい順）
+	Exemplars []Exemplar `json:"exemplars,omitempty"`
 }
 
 type MetricLabels struct {
@@ -60,36 +108,106 @@ type ResourceLabels struct {
 }
 
 type DataPoint struct {
-	Time  string  `json:"time"`
-	Value float64 `json:"value"`
+	Time       string   `json:"time"`
+	Value      float64  `json:"value"`
+	Normalized *float64 `json:"normalized,omitempty"` // value * unit.scale（normalize 指定時）
 }
 
 type ResultStats struct {
-	SeriesCount     int `json:"series_count"`
-	PointCountTotal int `json:"point_count_total"`
+	SeriesCount        int `json:"series_count"`
+	PointCountTotal    int `json:"point_count_total"`
+	OriginalPointCount int `json:"original_point_count,omitempty"` // ダウンサンプリング前のポイント数
+	DownsampledSeries  int `json:"downsampled_series,omitempty"`
+	StaleSeries        int `json:"stale_series,omitempty"` // 範囲の終端より前に報告が止まった系列の数
+	ExemplarCount      int `json:"exemplar_count,omitempty"`
 }
 
 // Client is the Cloud Monitoring client
 type Client struct {
-	metricClient *monitoring.MetricClient
+	api API
+
+	unitMu sync.Mutex
+	units  map[string]metricInfo // "project/metricType" → ディスクリプタの単位・値型・種類
+
+	catalogMu sync.Mutex
+	catalogs  map[string]metricCatalog // project → メトリクス種別の一覧（引数の補完用）
+
+	rulesFor func(projectID string) []config.ResourceRule // resource_rules
 }
 
 // NewClient creates a new Cloud Monitoring client
-func NewClient(ctx context.Context) (*Client, error) {
-	metricClient, err := monitoring.NewMetricClient(ctx)
+func NewClient(ctx context.Context, opts ...option.ClientOption) (*Client, error) {
+	metricClient, err := monitoring.NewMetricClient(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create monitoring client: %w", err)
 	}
-	return &Client{metricClient: metricClient}, nil
+	groupClient, err := monitoring.NewGroupClient(ctx, opts...)
+	if err != nil {
+		_ = metricClient.Close()
+		return nil, fmt.Errorf("failed to create monitoring group client: %w", err)
+	}
+	serviceClient, err := monitoring.NewServiceMonitoringClient(ctx, opts...)
+	if err != nil {
+		_ = groupClient.Close()
+		_ = metricClient.Close()
+		return nil, fmt.Errorf("failed to create service monitoring client: %w", err)
+	}
+	snoozeClient, err := monitoring.NewSnoozeClient(ctx, opts...)
+	if err != nil {
+		_ = serviceClient.Close()
+		_ = groupClient.Close()
+		_ = metricClient.Close()
+		return nil, fmt.Errorf("failed to create snooze client: %w", err)
+	}
+	alertClient, err := monitoring.NewAlertPolicyClient(ctx, opts...)
+	if err != nil {
+		_ = snoozeClient.Close()
+		_ = serviceClient.Close()
+		_ = groupClient.Close()
+		_ = metricClient.Close()
+		return nil, fmt.Errorf("failed to create alert policy client: %w", err)
+	}
+	return NewClientWithAPI(&gcpAPI{
+		metricClient:  metricClient,
+		groupClient:   groupClient,
+		serviceClient: serviceClient,
+		snoozeClient:  snoozeClient,
+		alertClient:   alertClient,
+	}), nil
+}
+
+// NewClientWithAPI creates a client backed by api (e.g. a fake in tests)
+func NewClientWithAPI(api API) *Client {
+	return &Client{api: api}
 }
 
 // Close closes the client
 func (c *Client) Close() error {
-	return c.metricClient.Close()
+	return c.api.Close()
 }
 
 // QueryTimeSeries queries time series data
 func (c *Client) QueryTimeSeries(ctx context.Context, params QueryTimeSeriesParams) (*QueryTimeSeriesResult, error) {
+	maxSeries := params.MaxSeries
+	if maxSeries <= 0 {
+		maxSeries = 20
+	}
+	if maxSeries > 50 {
+		maxSeries = 50
+	}
+	return c.queryTimeSeries(ctx, params, maxSeries)
+}
+
+// QueryTimeSeriesAll queries up to maxSeries series, beyond the per-call cap of QueryTimeSeries
+// (for exports that write the series elsewhere instead of returning them to the model)
+func (c *Client) QueryTimeSeriesAll(ctx context.Context, params QueryTimeSeriesParams, maxSeries int) (*QueryTimeSeriesResult, error) {
+	if maxSeries <= 0 {
+		return nil, fmt.Errorf("maxSeries must be positive")
+	}
+	return c.queryTimeSeries(ctx, params, maxSeries)
+}
+
+func (c *Client) queryTimeSeries(ctx context.Context, params QueryTimeSeriesParams, maxSeries int) (*QueryTimeSeriesResult, error) {
 	// Parse time range
 	startTime, endTime, err := parseTimeRange(params.TimeRange)
 	if err != nil {
@@ -102,21 +220,54 @@ func (c *Client) QueryTimeSeries(ctx context.Context, params QueryTimeSeriesPara
 		alignmentPeriod = 60
 	}
 
-	maxSeries := params.MaxSeries
-	if maxSeries <= 0 {
-		maxSeries = 20
+	// Build filter
+	filter := "metric.type = " + strconv.Quote(params.MetricType)
+	if params.ResourceType != "" {
+		filter += " AND resource.type = " + strconv.Quote(params.ResourceType)
 	}
-	if maxSeries > 50 {
-		maxSeries = 50
+	keys := make([]string, 0, len(params.Filters))
+	for k := range params.Filters {
+		if !labelPathPattern.MatchString(k) {
+			return nil, fmt.Errorf("invalid filters key %q: must be a label path such as resource.labels.service_name", k)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		filter += " AND " + k + " = " + strconv.Quote(params.Filters[k])
+	}
+	if params.Filter != "" {
+		filter += fmt.Sprintf(` AND (%s)`, params.Filter)
 	}
+	filter, restriction := c.withRestriction(params.ProjectID, filter)
 
-	// Build filter
-	filter := fmt.Sprintf(`metric.type = "%s"`, params.MetricType)
-	if params.ResourceType != "" {
-		filter += fmt.Sprintf(` AND resource.type = "%s"`, params.ResourceType)
+	// series_only では値を返さないので、カウンタ向けのアライナ選択は不要
+	aligner, warnings := alignerOrDefault(params.PerSeriesAligner), []string(nil)
+	if !params.SeriesOnly {
+		aligner, warnings = c.counterAligner(ctx, params)
 	}
-	for k, v := range params.Filters {
-		filter += fmt.Sprintf(` AND %s = "%s"`, k, v)
+	aggregation, err := buildAggregation(alignmentPeriod, aligner, params.CrossSeriesReducer, params.GroupByFields)
+	if err != nil {
+		return nil, err
+	}
+	var secondary *monitoringpb.Aggregation
+	if sa := params.SecondaryAggregation; sa != nil {
+		period := sa.AlignmentPeriodSec
+		if period <= 0 {
+			period = alignmentPeriod
+		}
+		secondary, err = buildAggregation(period, sa.PerSeriesAligner, sa.CrossSeriesReducer, sa.GroupByFields)
+		if err != nil {
+			return nil, fmt.Errorf("secondary_aggregation: %w", err)
+		}
+	}
+
+	var shift time.Duration
+	if params.TimeShift != "" {
+		shift, err = timerange.ParseShift(params.TimeShift)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Create request
@@ -127,38 +278,111 @@ func (c *Client) QueryTimeSeries(ctx context.Context, params QueryTimeSeriesPara
 			StartTime: timestamppb.New(startTime),
 			EndTime:   timestamppb.New(endTime),
 		},
-		Aggregation: &monitoringpb.Aggregation{
-			AlignmentPeriod:  durationpb.New(time.Duration(alignmentPeriod) * time.Second),
-			PerSeriesAligner: monitoringpb.Aggregation_ALIGN_MEAN,
-		},
-		View: monitoringpb.ListTimeSeriesRequest_FULL,
+		Aggregation:          aggregation,
+		SecondaryAggregation: secondary,
+		View:                 monitoringpb.ListTimeSeriesRequest_FULL,
+	}
+	if params.SeriesOnly {
+		req.View = monitoringpb.ListTimeSeriesRequest_HEADERS
 	}
 
 	// Execute query
-	it := c.metricClient.ListTimeSeries(ctx, req)
+	acc := &seriesAccumulator{series: []TimeSeries{}}
+	if err := c.collectSeries(ctx, req, params, maxSeries, 0, acc); err != nil {
+		return nil, err
+	}
+
+	// time_shift: 同じリクエストを過去にずらして実行し、時刻を戻して重ねられるようにする
+	if shift > 0 {
+		shifted := proto.Clone(req).(*monitoringpb.ListTimeSeriesRequest)
+		shifted.Interval = &monitoringpb.TimeInterval{
+			StartTime: timestamppb.New(startTime.Add(-shift)),
+			EndTime:   timestamppb.New(endTime.Add(-shift)),
+		}
+		if err := c.collectSeries(ctx, shifted, params, maxSeries, shift, acc); err != nil {
+			return nil, fmt.Errorf("time_shift %s: %w", params.TimeShift, err)
+		}
+	}
+
+	if acc.stale > 0 {
+		warnings = append(warnings, fmt.Sprintf(
+			"%d series stopped reporting before the end of the range (see series[].freshness.last_point)", acc.stale))
+	}
+
+	stats := ResultStats{
+		SeriesCount:     len(acc.series),
+		PointCountTotal: acc.totalPoints,
+		StaleSeries:     acc.stale,
+		ExemplarCount:   acc.exemplars,
+	}
+	if acc.downsampled > 0 {
+		stats.OriginalPointCount = acc.originalPoints
+		stats.DownsampledSeries = acc.downsampled
+	}
+
+	return &QueryTimeSeriesResult{
+		QueryMeta: QueryMeta{
+			ProjectID:        params.ProjectID,
+			MetricType:       params.MetricType,
+			Start:            startTime.Format(time.RFC3339),
+			End:              endTime.Format(time.RFC3339),
+			Restriction:      restriction,
+			TimeShift:        params.TimeShift,
+			PerSeriesAligner: aggregation.GetPerSeriesAligner().String(),
+			Warnings:         warnings,
+		},
+		Series: acc.series,
+		Stats:  stats,
+	}, nil
+}
 
-	series := []TimeSeries{}
-	totalPoints := 0
+// seriesAccumulator は collectSeries が集めた系列とポイント数
+type seriesAccumulator struct {
+	series         []TimeSeries
+	totalPoints    int
+	originalPoints int
+	downsampled    int
+	stale          int
+	exemplars      int
+}
+
+// collectSeries は req の系列を最大 maxSeries 件 acc に追加する
+// shift > 0 のときはポイントの時刻を shift だけ進め、系列に time_shift を付ける
+func (c *Client) collectSeries(ctx context.Context, req *monitoringpb.ListTimeSeriesRequest, params QueryTimeSeriesParams, maxSeries int, shift time.Duration, acc *seriesAccumulator) error {
+	it := c.api.ListTimeSeries(ctx, req)
 
+	count := 0
 	for {
 		ts, err := it.Next()
 		if err == iterator.Done {
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to iterate time series: %w", err)
+			return fmt.Errorf("failed to iterate time series: %w", err)
 		}
 
 		points := []DataPoint{}
 		for _, p := range ts.GetPoints() {
 			value := extractValue(p.GetValue())
 			points = append(points, DataPoint{
-				Time:  p.GetInterval().GetEndTime().AsTime().Format(time.RFC3339),
+				Time:  p.GetInterval().GetEndTime().AsTime().Add(shift).Format(time.RFC3339),
 				Value: value,
 			})
 		}
 
-		series = append(series, TimeSeries{
+		// 要約統計はダウンサンプリング前のポイントから計算する
+		summary := Summarize(points)
+
+		// ポイント数の上限を超えた系列はダウンサンプリングする
+		acc.originalPoints += len(points)
+		if params.StatsOnly {
+			points = []DataPoint{}
+		} else if params.MaxPointsPerSeries > 0 && len(points) > params.MaxPointsPerSeries {
+			points = Downsample(points, params.MaxPointsPerSeries, params.Downsample)
+			acc.downsampled++
+		}
+
+		s := TimeSeries{
 			Metric: MetricLabels{
 				Type:   ts.GetMetric().GetType(),
 				Labels: ts.GetMetric().GetLabels(),
@@ -168,64 +392,80 @@ func (c *Client) QueryTimeSeries(ctx context.Context, params QueryTimeSeriesPara
 				Labels: ts.GetResource().GetLabels(),
 			},
 			Points: points,
-		})
+		}
+		if !params.SeriesOnly {
+			s.Summary = &summary
+			// 鮮度は現在の期間の終端と比べる（比較用の系列は時刻を戻してあるので同じ終端になる）
+			s.Freshness = DetectFreshness(ts.GetPoints(), req.GetInterval().GetEndTime().AsTime().Add(shift), outputPeriod(req), shift)
+			if s.Freshness != nil && s.Freshness.Stale {
+				acc.stale++
+			}
+		}
+		if shift > 0 {
+			s.TimeShift = params.TimeShift
+		} else {
+			// 比較用の系列は元の系列と同じリソースなので付けない
+			s.SuggestedLogFilter = LogFilter(s.Resource)
+			if exemplars := exemplarsOf(ts.GetPoints()); len(exemplars) > 0 {
+				s.Exemplars = exemplars
+				acc.exemplars += len(exemplars)
+			}
+		}
+		acc.series = append(acc.series, s)
 
-		totalPoints += len(points)
+		acc.totalPoints += len(points)
 
-		if len(series) >= maxSeries {
+		count++
+		if count >= maxSeries {
 			break
 		}
 	}
-
-	return &QueryTimeSeriesResult{
-		QueryMeta: QueryMeta{
-			ProjectID:  params.ProjectID,
-			MetricType: params.MetricType,
-			Start:      startTime.Format(time.RFC3339),
-			End:        endTime.Format(time.RFC3339),
-		},
-		Series: series,
-		Stats: ResultStats{
-			SeriesCount:     len(series),
-			PointCountTotal: totalPoints,
-		},
-	}, nil
+	return nil
 }
 
-func parseTimeRange(tr TimeRange) (time.Time, time.Time, error) {
-	now := time.Now()
-	var startTime, endTime time.Time
-	var err error
-
-	// Parse end time
-	if tr.End == "" || tr.End == "now" {
-		endTime = now
-	} else {
-		endTime, err = time.Parse(time.RFC3339, tr.End)
-		if err != nil {
-			return time.Time{}, time.Time{}, fmt.Errorf("invalid end time: %w", err)
+// buildAggregation はアライナ・リデューサ名からAggregationを組み立てる
+// 名前は "ALIGN_RATE" / "RATE" のどちらの形式でも受け付ける
+func buildAggregation(alignmentPeriodSec int, aligner, reducer string, groupBy []string) (*monitoringpb.Aggregation, error) {
+	agg := &monitoringpb.Aggregation{
+		AlignmentPeriod:  durationpb.New(time.Duration(alignmentPeriodSec) * time.Second),
+		PerSeriesAligner: monitoringpb.Aggregation_ALIGN_MEAN,
+		GroupByFields:    groupBy,
+	}
+
+	if aligner != "" {
+		name := strings.ToUpper(aligner)
+		if !strings.HasPrefix(name, "ALIGN_") {
+			name = "ALIGN_" + name
 		}
+		v, ok := monitoringpb.Aggregation_Aligner_value[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown per_series_aligner: %s", aligner)
+		}
+		agg.PerSeriesAligner = monitoringpb.Aggregation_Aligner(v)
 	}
 
-	// Parse start time
-	switch {
-	case tr.Start == "":
-		startTime = now.Add(-30 * time.Minute) // default: 30 minutes ago
-	case len(tr.Start) > 0 && tr.Start[0] == '-':
-		// Relative time (e.g., "-1h", "-30m")
-		duration, err := time.ParseDuration(tr.Start[1:])
-		if err != nil {
-			return time.Time{}, time.Time{}, fmt.Errorf("invalid relative start time: %w", err)
+	if reducer != "" {
+		name := strings.ToUpper(reducer)
+		if !strings.HasPrefix(name, "REDUCE_") {
+			name = "REDUCE_" + name
 		}
-		startTime = now.Add(-duration)
-	default:
-		startTime, err = time.Parse(time.RFC3339, tr.Start)
-		if err != nil {
-			return time.Time{}, time.Time{}, fmt.Errorf("invalid start time: %w", err)
+		v, ok := monitoringpb.Aggregation_Reducer_value[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown cross_series_reducer: %s", reducer)
 		}
+		agg.CrossSeriesReducer = monitoringpb.Aggregation_Reducer(v)
 	}
 
-	return startTime, endTime, nil
+	return agg, nil
+}
+
+// ParseTimeRange は相対/絶対指定の時間範囲をパースする
+func ParseTimeRange(tr TimeRange) (time.Time, time.Time, error) {
+	return parseTimeRange(tr)
+}
+
+func parseTimeRange(tr TimeRange) (time.Time, time.Time, error) {
+	return timerange.Parse(tr.Start, tr.End)
 }
 
 func extractValue(v *monitoringpb.TypedValue) float64 {
@@ -239,6 +479,9 @@ func extractValue(v *monitoringpb.TypedValue) float64 {
 			return 1
 		}
 		return 0
+	case *monitoringpb.TypedValue_DistributionValue:
+		// 分布のまま整列した（ALIGN_DELTA など）ポイントは平均を値とする
+		return v.DistributionValue.GetMean()
 	default:
 		return 0
 	}
@@ -265,9 +508,9 @@ func (c *Client) QueryTimeSeriesHandler() func(ctx context.Context, args json.Ra
 
 // Validator はガードレール検証用インターフェース
 type Validator interface {
-	ValidateProjectID(projectID string) error
-	ValidateTimeRange(start, end time.Time) error
-	ClampTimeSeriesLimit(limit int) int
+	ValidateTimeRange(ctx context.Context, start, end time.Time) error
+	ClampTimeSeriesLimit(ctx context.Context, limit int) int
+	ClampPointsPerSeries(ctx context.Context, limit int) int
 }
 
 // QueryTimeSeriesHandlerWithGuardrail returns a handler with guardrail validation
@@ -278,32 +521,52 @@ func (c *Client) QueryTimeSeriesHandlerWithGuardrail(v Validator) func(ctx conte
 			return nil, fmt.Errorf("failed to parse arguments: %w", err)
 		}
 
-		if params.ProjectID == "" {
-			return nil, fmt.Errorf("project_id is required")
-		}
 		if params.MetricType == "" {
 			return nil, fmt.Errorf("metric_type is required")
 		}
 
-		// ガードレール: プロジェクトID検証
-		if err := v.ValidateProjectID(params.ProjectID); err != nil {
+		// ガードレール: 系列数制限
+		params.MaxSeries = v.ClampTimeSeriesLimit(ctx, params.MaxSeries)
+
+		// ガードレール: 1系列あたりのポイント数制限
+		params.MaxPointsPerSeries = v.ClampPointsPerSeries(ctx, params.MaxPointsPerSeries)
+		if err := validateDownsample(params.Downsample); err != nil {
 			return nil, err
 		}
 
-		// 時間範囲のパース
-		startTime, endTime, err := parseTimeRange(params.TimeRange)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse time range: %w", err)
+		switch params.Render {
+		case "", "points", "sparkline", "chart":
+			if params.StatsOnly && params.Render != "" && params.Render != "points" {
+				return nil, fmt.Errorf("stats_only cannot be combined with render: %s", params.Render)
+			}
+			if params.SeriesOnly && params.Render != "" && params.Render != "points" {
+				return nil, fmt.Errorf("series_only cannot be combined with render: %s", params.Render)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported render: %s (supported: points, sparkline, chart)", params.Render)
 		}
 
-		// ガードレール: 時間範囲検証
-		if err := v.ValidateTimeRange(startTime, endTime); err != nil {
-			return nil, err
+		if params.SeriesOnly && (params.StatsOnly || params.TimeShift != "") {
+			return nil, fmt.Errorf("series_only cannot be combined with stats_only or time_shift")
 		}
 
-		// ガードレール: 系列数制限
-		params.MaxSeries = v.ClampTimeSeriesLimit(params.MaxSeries)
+		result, err := c.QueryTimeSeries(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		if !params.SeriesOnly {
+			c.annotateUnit(ctx, params, result)
+		}
 
-		return c.QueryTimeSeries(ctx, params)
+		switch params.Render {
+		case "sparkline":
+			return SparklineContent(result)
+		case "chart":
+			if len(result.Series) == 0 {
+				return result, nil
+			}
+			return ChartContent(result)
+		}
+		return result, nil
 	}
 }
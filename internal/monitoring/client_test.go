@@ -0,0 +1,90 @@
+package monitoring_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/fake"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+)
+
+func TestResourceRulesSurviveFilters(t *testing.T) {
+	const restriction = `resource.type = one_of("cloud_run_revision")`
+	injection := `x" OR resource.type = "gce_instance`
+
+	tests := []struct {
+		name    string
+		filters map[string]string
+		filter  string
+		want    string // Part of the filter sent to the API
+	}{
+		{"quoted value", map[string]string{"resource.labels.service_name": injection}, "", "resource.labels.service_name = " + strconv.Quote(injection)},
+		{"sorted keys", map[string]string{"metric.labels.response_code_class": "5xx", "metadata.user_labels.team": "a"}, "", `metadata.user_labels.team = "a" AND metric.labels.response_code_class = "5xx"`},
+		{"unbalanced raw filter", nil, `metric.labels.a = "1") OR (resource.type = "gce_instance"`, `metric.labels.a = "1") OR (resource.type = "gce_instance"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := fake.NewMonitoring()
+			client := monitoring.NewClientWithAPI(api)
+			client.SetResourceRules(func(string) []config.ResourceRule {
+				return []config.ResourceRule{{ResourceTypes: []string{"cloud_run_revision"}}}
+			})
+			_, err := client.QueryTimeSeries(context.Background(), monitoring.QueryTimeSeriesParams{
+				ProjectID:  fake.Project,
+				MetricType: "run.googleapis.com/request_count",
+				Filters:    tt.filters,
+				Filter:     tt.filter,
+				TimeRange:  monitoring.TimeRange{Start: "-1h"},
+				SeriesOnly: true,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sent := listTimeSeriesFilter(t, api)
+			if !strings.Contains(sent, tt.want) {
+				t.Errorf("filter %q does not contain %q", sent, tt.want)
+			}
+			// ルールの条件は、呼び出し元の条件をすべて囲んだ括弧の外に付く
+			if !strings.HasPrefix(sent, "(") || !strings.HasSuffix(sent, ") AND "+restriction) {
+				t.Errorf("filter %q does not AND the restriction to the parenthesized query", sent)
+			}
+		})
+	}
+}
+
+func TestFiltersRejectsInvalidKeys(t *testing.T) {
+	for _, key := range []string{`resource.type = "gce_instance" OR metric.labels.x`, "service_name", "metric.labels.", "resource.labels.a b"} {
+		t.Run(key, func(t *testing.T) {
+			api := fake.NewMonitoring()
+			_, err := monitoring.NewClientWithAPI(api).QueryTimeSeries(context.Background(), monitoring.QueryTimeSeriesParams{
+				ProjectID:  fake.Project,
+				MetricType: "run.googleapis.com/request_count",
+				Filters:    map[string]string{key: "v"},
+				TimeRange:  monitoring.TimeRange{Start: "-1h"},
+			})
+			if err == nil || !strings.Contains(err.Error(), "invalid filters key") {
+				t.Errorf("err = %v, want invalid filters key", err)
+			}
+			if len(api.Requests()) != 0 {
+				t.Errorf("sent %d requests for an invalid key", len(api.Requests()))
+			}
+		})
+	}
+}
+
+func listTimeSeriesFilter(t *testing.T, api *fake.Monitoring) string {
+	t.Helper()
+	for _, req := range api.Requests() {
+		if req, ok := req.(*monitoringpb.ListTimeSeriesRequest); ok {
+			return req.Filter
+		}
+	}
+	t.Fatal("no ListTimeSeries request was sent")
+	return ""
+}
@@ -0,0 +1,81 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	"google.golang.org/api/iterator"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/provider"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/telemetry"
+)
+
+// metricCatalogTTL はメトリクス種別の一覧を再取得するまでの時間
+// 補完のたびに数千件のディスクリプタを列挙しないようにキャッシュする
+const metricCatalogTTL = time.Hour
+
+// metricCatalog はプロジェクトのメトリクス種別の一覧
+type metricCatalog struct {
+	types   []string
+	fetched time.Time
+}
+
+// MetricTypes returns the metric types of the project's descriptors, cached for an hour
+func (c *Client) MetricTypes(ctx context.Context, projectID string) ([]string, error) {
+	c.catalogMu.Lock()
+	cached, ok := c.catalogs[projectID]
+	c.catalogMu.Unlock()
+	ok = ok && time.Since(cached.fetched) < metricCatalogTTL
+	telemetry.RecordCacheLookup(ctx, "metric_catalog", ok)
+	if ok {
+		return cached.types, nil
+	}
+
+	it := c.api.ListMetricDescriptors(ctx, &monitoringpb.ListMetricDescriptorsRequest{
+		Name: fmt.Sprintf("projects/%s", projectID),
+	})
+	types := []string{}
+	for {
+		desc, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate metric descriptors: %w", err)
+		}
+		types = append(types, desc.GetType())
+	}
+
+	c.catalogMu.Lock()
+	if c.catalogs == nil {
+		c.catalogs = map[string]metricCatalog{}
+	}
+	c.catalogs[projectID] = metricCatalog{types: types, fetched: time.Now()}
+	c.catalogMu.Unlock()
+	return types, nil
+}
+
+// Complete completes metric_type from the descriptor catalog of project_id (see provider.Completer)
+func (p *toolProvider) Complete(ctx context.Context, argument, prefix string, args map[string]string) ([]string, bool, error) {
+	if argument != "metric_type" {
+		return nil, false, nil
+	}
+	projectID := p.guard.ResolveProjectID(args["project_id"])
+	if projectID == "" {
+		return []string{}, true, nil
+	}
+	if err := p.guard.ValidateProjectID(ctx, projectID); err != nil {
+		return nil, true, err
+	}
+	client, err := p.client.Get()
+	if err != nil {
+		return nil, true, err
+	}
+	types, err := client.MetricTypes(ctx, projectID)
+	if err != nil {
+		return nil, true, err
+	}
+	return provider.MatchCompletions(types, prefix), true, nil
+}
@@ -0,0 +1,155 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	metricpb "google.golang.org/genproto/googleapis/api/metric"
+	monitoredrespb "google.golang.org/genproto/googleapis/api/monitoredres"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// CustomMetricPrefix は monitoring.write_custom_metric が書き込むメトリクスタイプの接頭辞
+// 既存のカスタムメトリクスを上書きしないよう、書き込み先はこの下に固定する
+const CustomMetricPrefix = "custom.googleapis.com/mcp/"
+
+// maxCustomMetricLabels はカスタムメトリクスに付けられるラベル数の上限（API の制限）
+const maxCustomMetricLabels = 10
+
+var (
+	// customMetricNamePattern は接頭辞の後ろに付けるメトリクス名（英数字と _ /）
+	customMetricNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+(/[A-Za-z0-9_]+)*$`)
+	// metricLabelKeyPattern はメトリクスラベルのキー（小文字で始まる英小文字・数字・_、100文字以内）
+	metricLabelKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,99}$`)
+)
+
+// WriteCustomMetricParams are the parameters for monitoring.write_custom_metric
+type WriteCustomMetricParams struct {
+	ProjectID    string            `json:"project_id" required:"true" description:"GCP project ID"`
+	Name         string            `json:"name" required:"true" description:"Metric name under custom.googleapis.com/mcp/ (e.g., 'investigation_started')"`
+	Value        *float64          `json:"value,omitempty" description:"Point value (default: 1)"`
+	Labels       map[string]string `json:"labels,omitempty" description:"Metric labels (e.g., {'service': 'checkout', 'note': 'rollback started'}); at most 10, keys in lower_snake_case"`
+	ConfirmToken string            `json:"confirm_token,omitempty" description:"Token returned by the preview call. Omit to preview."`
+}
+
+// WriteCustomMetricResult is the result of monitoring.write_custom_metric
+// ConfirmToken が返った場合は未実行（プレビュー）。同じ引数に confirm_token を付けて再度呼ぶと実行される
+type WriteCustomMetricResult struct {
+	Executed     bool        `json:"executed"`
+	Preview      string      `json:"preview,omitempty"`
+	ConfirmToken string      `json:"confirm_token,omitempty"`
+	Point        CustomPoint `json:"point"`
+}
+
+// CustomPoint is the written (or to-be-written) point
+type CustomPoint struct {
+	MetricType string            `json:"metric_type"` // Use with monitoring.query_time_series / dashboards
+	Resource   ResourceLabels    `json:"resource"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Time       string            `json:"time,omitempty"`
+	Value      float64           `json:"value"`
+}
+
+// WriteCustomMetric writes one GAUGE point of the custom metric on the global resource
+// (the metric descriptor is created automatically on the first write)
+func (c *Client) WriteCustomMetric(ctx context.Context, params WriteCustomMetricParams, at time.Time) (*CustomPoint, error) {
+	point := customPoint(params)
+	point.Time = at.Format(time.RFC3339)
+	err := c.api.CreateTimeSeries(ctx, &monitoringpb.CreateTimeSeriesRequest{
+		Name: fmt.Sprintf("projects/%s", params.ProjectID),
+		TimeSeries: []*monitoringpb.TimeSeries{{
+			Metric: &metricpb.Metric{
+				Type:   point.MetricType,
+				Labels: point.Labels,
+			},
+			Resource: &monitoredrespb.MonitoredResource{
+				Type:   point.Resource.Type,
+				Labels: point.Resource.Labels,
+			},
+			MetricKind: metricpb.MetricDescriptor_GAUGE,
+			ValueType:  metricpb.MetricDescriptor_DOUBLE,
+			Points: []*monitoringpb.Point{{
+				Interval: &monitoringpb.TimeInterval{EndTime: timestamppb.New(at)},
+				Value:    &monitoringpb.TypedValue{Value: &monitoringpb.TypedValue_DoubleValue{DoubleValue: point.Value}},
+			}},
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write custom metric: %w", err)
+	}
+	return &point, nil
+}
+
+// customPoint は書き込むポイント（時刻以外）を組み立てる
+func customPoint(params WriteCustomMetricParams) CustomPoint {
+	value := 1.0
+	if params.Value != nil {
+		value = *params.Value
+	}
+	return CustomPoint{
+		MetricType: CustomMetricPrefix + params.Name,
+		Resource: ResourceLabels{
+			Type:   "global",
+			Labels: map[string]string{"project_id": params.ProjectID},
+		},
+		Labels: params.Labels,
+		Value:  value,
+	}
+}
+
+// WriteCustomMetricHandlerWithGuardrail returns a handler with guardrail validation and confirmation
+func (c *Client) WriteCustomMetricHandlerWithGuardrail(v WriteValidator) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params WriteCustomMetricParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.Name == "" {
+			return nil, fmt.Errorf("name is required")
+		}
+		if !customMetricNamePattern.MatchString(params.Name) {
+			return nil, fmt.Errorf("invalid metric name: %s (letters, digits, '_' and '/')", params.Name)
+		}
+		if len(params.Labels) > maxCustomMetricLabels {
+			return nil, fmt.Errorf("too many labels: %d (max: %d)", len(params.Labels), maxCustomMetricLabels)
+		}
+		for key := range params.Labels {
+			if !metricLabelKeyPattern.MatchString(key) {
+				return nil, fmt.Errorf("invalid label key: %s (lower_snake_case, starting with a letter)", key)
+			}
+		}
+
+		// 確認トークンは実行内容（トークン以外の引数）に紐づける
+		token := params.ConfirmToken
+		params.ConfirmToken = ""
+		preview := customPoint(params)
+
+		// ガードレール: 確認トークンがなければプレビューのみ返す
+		if token == "" {
+			issued, err := v.IssueConfirmToken("monitoring.write_custom_metric", params)
+			if err != nil {
+				return nil, err
+			}
+			return &WriteCustomMetricResult{
+				Executed:     false,
+				Preview:      fmt.Sprintf("Will write %g to %s now. Call again with the same arguments and confirm_token to execute.", preview.Value, preview.MetricType),
+				ConfirmToken: issued,
+				Point:        preview,
+			}, nil
+		}
+		if err := v.VerifyConfirmToken(token, "monitoring.write_custom_metric", params); err != nil {
+			return nil, err
+		}
+
+		point, err := c.WriteCustomMetric(ctx, params, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		return &WriteCustomMetricResult{Executed: true, Point: *point}, nil
+	}
+}
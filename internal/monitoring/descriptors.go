@@ -69,7 +69,7 @@ func (c *Client) ListMetricDescriptors(ctx context.Context, params ListMetricDes
 	}
 
 	// Execute query
-	it := c.metricClient.ListMetricDescriptors(ctx, req)
+	it := c.api.ListMetricDescriptors(ctx, req)
 
 	descriptors := []MetricDescriptor{}
 	truncated := false
@@ -129,31 +129,6 @@ func (c *Client) ListMetricDescriptorsHandler() func(ctx context.Context, args j
 			return nil, fmt.Errorf("failed to parse arguments: %w", err)
 		}
 
-		if params.ProjectID == "" {
-			return nil, fmt.Errorf("project_id is required")
-		}
-
-		return c.ListMetricDescriptors(ctx, params)
-	}
-}
-
-// ListMetricDescriptorsHandlerWithGuardrail returns a handler with guardrail validation
-func (c *Client) ListMetricDescriptorsHandlerWithGuardrail(v Validator) func(ctx context.Context, args json.RawMessage) (any, error) {
-	return func(ctx context.Context, args json.RawMessage) (any, error) {
-		var params ListMetricDescriptorsParams
-		if err := json.Unmarshal(args, &params); err != nil {
-			return nil, fmt.Errorf("failed to parse arguments: %w", err)
-		}
-
-		if params.ProjectID == "" {
-			return nil, fmt.Errorf("project_id is required")
-		}
-
-		// ガードレール: プロジェクトID検証
-		if err := v.ValidateProjectID(params.ProjectID); err != nil {
-			return nil, err
-		}
-
 		return c.ListMetricDescriptors(ctx, params)
 	}
 }
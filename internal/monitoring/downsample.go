@@ -0,0 +1,57 @@
+package monitoring
+
+import (
+	"fmt"
+	"math"
+)
+
+// DownsampleMethods are the supported bucket aggregations for downsampling
+var DownsampleMethods = []string{"mean", "min", "max"}
+
+// Downsample は連続するポイントを maxPoints 個のバケットにまとめる
+// バケットの時刻はAPI順で先頭（= 最新）のポイントの時刻を使う
+func Downsample(points []DataPoint, maxPoints int, method string) []DataPoint {
+	if maxPoints <= 0 || len(points) <= maxPoints {
+		return points
+	}
+
+	out := make([]DataPoint, 0, maxPoints)
+	for b := 0; b < maxPoints; b++ {
+		lo := b * len(points) / maxPoints
+		hi := (b + 1) * len(points) / maxPoints
+		bucket := points[lo:hi]
+
+		value := bucket[0].Value
+		switch method {
+		case "min":
+			for _, p := range bucket {
+				value = math.Min(value, p.Value)
+			}
+		case "max":
+			for _, p := range bucket {
+				value = math.Max(value, p.Value)
+			}
+		default:
+			sum := 0.0
+			for _, p := range bucket {
+				sum += p.Value
+			}
+			value = sum / float64(len(bucket))
+		}
+		out = append(out, DataPoint{Time: bucket[0].Time, Value: value})
+	}
+	return out
+}
+
+// validateDownsample は downsample の指定を検証する
+func validateDownsample(method string) error {
+	if method == "" {
+		return nil
+	}
+	for _, m := range DownsampleMethods {
+		if m == method {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported downsample: %s (supported: mean, min, max)", method)
+}
@@ -0,0 +1,62 @@
+package monitoring
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+)
+
+// maxExemplarsPerSeries は系列ごとに返すエグザンプラの数（値の大きい順。遅いリクエストから見るため）
+const maxExemplarsPerSeries = 5
+
+// Exemplar is a sample request attached to a distribution point, usually with the trace that produced it
+type Exemplar struct {
+	Time    string            `json:"time"`
+	Value   float64           `json:"value"`              // e.g. the latency of that request
+	Trace   string            `json:"trace,omitempty"`    // projects/P/traces/TRACE_ID, as in log entries' trace field
+	TraceID string            `json:"trace_id,omitempty"` // For the trace tools
+	SpanID  string            `json:"span_id,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"` // Labels dropped by the aggregation (DroppedLabels)
+}
+
+// exemplarsOf は分布のポイントに付いたエグザンプラを値の大きい順に最大 maxExemplarsPerSeries 件返す
+// エグザンプラが残るのは分布のまま整列した場合（ALIGN_DELTA / ALIGN_SUM など）だけで、
+// ALIGN_PERCENTILE_* や ALIGN_MEAN では数値になって失われる
+func exemplarsOf(points []*monitoringpb.Point) []Exemplar {
+	out := []Exemplar{}
+	for _, p := range points {
+		for _, e := range p.GetValue().GetDistributionValue().GetExemplars() {
+			ex := Exemplar{Value: e.GetValue()}
+			if t := e.GetTimestamp(); t != nil {
+				ex.Time = t.AsTime().Format(time.RFC3339)
+			} else {
+				ex.Time = p.GetInterval().GetEndTime().AsTime().Format(time.RFC3339)
+			}
+			for _, a := range e.GetAttachments() {
+				var span monitoringpb.SpanContext
+				if a.MessageIs(&span) && a.UnmarshalTo(&span) == nil {
+					ex.Trace, ex.TraceID, ex.SpanID = parseSpanName(span.GetSpanName())
+					continue
+				}
+				var dropped monitoringpb.DroppedLabels
+				if a.MessageIs(&dropped) && a.UnmarshalTo(&dropped) == nil && len(dropped.GetLabel()) > 0 {
+					ex.Labels = dropped.GetLabel()
+				}
+			}
+			out = append(out, ex)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Value > out[j].Value })
+	return out[:min(len(out), maxExemplarsPerSeries)]
+}
+
+// parseSpanName は "projects/P/traces/TRACE_ID/spans/SPAN_ID" をトレース名・トレース ID・スパン ID に分ける
+func parseSpanName(name string) (trace, traceID, spanID string) {
+	trace, spanID, _ = strings.Cut(name, "/spans/")
+	if i := strings.LastIndex(trace, "/traces/"); i >= 0 {
+		traceID = trace[i+len("/traces/"):]
+	}
+	return trace, traceID, spanID
+}
@@ -0,0 +1,398 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/timerange"
+)
+
+const (
+	// spikeDetectionRange はスパイクを探す期間（time_range も spike_time も省略したとき）
+	spikeDetectionRange = "-6h"
+	// spikeContext は spike_time だけ指定したときに前後で読むメトリクスの期間
+	spikeContext = time.Hour
+	// maxSpikeScan は窓ごとに走査するログの上限
+	maxSpikeScan = 1000
+	// maxSpikeMessageLen はグループのキーにするメッセージの長さ
+	maxSpikeMessageLen = 120
+)
+
+// messageVariables はメッセージのうちリクエストごとに変わる部分（UUID、16進のID、数値）
+// 同じエラーが値違いで別のグループにならないように置き換える
+var messageVariables = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|\b[0-9a-fA-F]{16,}\b|\d+(\.\d+)?`)
+
+// ExplainSpikeParams are the parameters for monitoring.explain_spike
+type ExplainSpikeParams struct {
+	QueryTimeSeriesParams
+	SpikeTime       string `json:"spike_time,omitempty"`       // RFC3339; detected from the series when omitted
+	WindowMinutes   int    `json:"window_minutes,omitempty"`   // Logs are read within ± this many minutes (default: 5)
+	BaselineMinutes int    `json:"baseline_minutes,omitempty"` // Length of the comparison window before the spike window (default: 30)
+	MaxCandidates   int    `json:"max_candidates,omitempty"`   // default: 10
+}
+
+// ExplainSpikeResult is the result of monitoring.explain_spike
+type ExplainSpikeResult struct {
+	QueryMeta  SpikeQueryMeta   `json:"query_meta"`
+	Spike      Spike            `json:"spike"`
+	Candidates []SpikeCandidate `json:"candidates"` // Most correlated first
+	Stats      SpikeStats       `json:"stats"`
+	Note       string           `json:"note,omitempty"`
+}
+
+type SpikeQueryMeta struct {
+	QueryMeta
+	LogFilter     string `json:"log_filter"` // Filter used for the logs (without the time range)
+	WindowStart   string `json:"window_start"`
+	WindowEnd     string `json:"window_end"`
+	BaselineStart string `json:"baseline_start"`
+	BaselineEnd   string `json:"baseline_end"` // = window_start
+}
+
+// Spike is the point the logs are correlated with
+type Spike struct {
+	Detected bool           `json:"detected"` // true = found as the largest deviation in the series, false = spike_time was given
+	Time     string         `json:"time"`
+	Label    string         `json:"label"`
+	Metric   MetricLabels   `json:"metric"`
+	Resource ResourceLabels `json:"resource"`
+	Value    float64        `json:"value"`
+	Mean     float64        `json:"mean"`    // Mean of the series over the query range
+	ZScore   float64        `json:"z_score"` // (value - mean) / stddev; 0 when the series is flat
+}
+
+// SpikeCandidate is a group of ERROR logs (same log and message) around the spike
+type SpikeCandidate struct {
+	Message       string  `json:"message"` // Message with numbers and IDs replaced by '#'
+	LogName       string  `json:"log_name"`
+	Severity      string  `json:"severity"` // Highest severity in the group
+	WindowCount   int     `json:"window_count"`
+	BaselineCount int     `json:"baseline_count"`
+	Lift          float64 `json:"lift"`          // (window_count + 1) / (baseline_count scaled to the window length + 1)
+	NewInWindow   bool    `json:"new_in_window"` // Not seen in the baseline window
+	FirstSeen     string  `json:"first_seen"`
+	LastSeen      string  `json:"last_seen"`
+	Sample        string  `json:"sample"` // One original message of the group
+	InsertID      string  `json:"insert_id,omitempty"`
+}
+
+type SpikeStats struct {
+	SeriesCount     int  `json:"series_count"`
+	WindowEntries   int  `json:"window_entries"`
+	BaselineEntries int  `json:"baseline_entries"`
+	Groups          int  `json:"groups"`
+	Truncated       bool `json:"truncated"` // A window had more than 1000 entries; counts are lower bounds
+}
+
+// spikeGroup は集計中のログのグループ
+type spikeGroup struct {
+	candidate SpikeCandidate
+	rank      int // 重大度の順位（大きいほど重い）
+}
+
+// severityRank は候補の重大度をまとめるときの順位
+var severityRank = map[string]int{"ERROR": 1, "CRITICAL": 2, "ALERT": 3, "EMERGENCY": 4}
+
+// ExplainSpike finds the spike of the metric (or uses spike_time), reads the ERROR logs of the spiking
+// resource within ±window_minutes and ranks the log groups by how much more often they occur than in
+// the preceding baseline window
+func (c *Client) ExplainSpike(ctx context.Context, logs *logging.Client, params ExplainSpikeParams) (*ExplainSpikeResult, error) {
+	var spikeAt time.Time
+	if params.SpikeTime != "" {
+		t, err := time.Parse(time.RFC3339, params.SpikeTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid spike_time: %w", err)
+		}
+		spikeAt = t
+	}
+	window := time.Duration(params.WindowMinutes) * time.Minute
+	baseline := time.Duration(params.BaselineMinutes) * time.Minute
+
+	// 相関を見るには全ポイントが必要なので、ダウンサンプリングや表示用の指定は無視する
+	query := params.QueryTimeSeriesParams
+	query.MaxPointsPerSeries = 0
+	query.StatsOnly = false
+	query.SeriesOnly = false
+	query.TimeShift = ""
+	query.Render = ""
+
+	result, err := c.QueryTimeSeries(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	spike, ok := findSpike(result.Series, spikeAt)
+	if !ok {
+		return nil, fmt.Errorf("no data points for %s in the time range; widen time_range or check the filter", params.MetricType)
+	}
+	at, _ := time.Parse(time.RFC3339, spike.Time)
+	if !spikeAt.IsZero() {
+		at = spikeAt
+		spike.Time = spikeAt.UTC().Format(time.RFC3339)
+	}
+
+	windowStart, windowEnd := at.Add(-window), at.Add(window)
+	baselineStart := windowStart.Add(-baseline)
+	filter := spikeLogFilter(spike.Resource)
+
+	out := &ExplainSpikeResult{
+		QueryMeta: SpikeQueryMeta{
+			QueryMeta:     result.QueryMeta,
+			LogFilter:     filter,
+			WindowStart:   windowStart.UTC().Format(time.RFC3339),
+			WindowEnd:     windowEnd.UTC().Format(time.RFC3339),
+			BaselineStart: baselineStart.UTC().Format(time.RFC3339),
+			BaselineEnd:   windowStart.UTC().Format(time.RFC3339),
+		},
+		Spike:      spike,
+		Candidates: []SpikeCandidate{},
+	}
+	out.Stats.SeriesCount = len(result.Series)
+
+	groups := map[string]*spikeGroup{}
+	scanned, err := logs.ScanEntries(ctx, params.ProjectID, filter, windowStart, windowEnd, maxSpikeScan, func(e logging.LogEntry) {
+		key := e.LogName + "\x00" + messageKey(e)
+		g, ok := groups[key]
+		if !ok {
+			g = &spikeGroup{candidate: SpikeCandidate{
+				Message:  messageKey(e),
+				LogName:  e.LogName,
+				LastSeen: e.Timestamp, // 新しい順に読む
+				Sample:   truncateMessage(entryMessage(e), 300),
+				InsertID: e.InsertID,
+			}}
+			groups[key] = g
+		}
+		g.candidate.WindowCount++
+		g.candidate.FirstSeen = e.Timestamp
+		if r := severityRank[e.Severity]; r >= g.rank {
+			g.rank, g.candidate.Severity = r, e.Severity
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logs around the spike: %w", err)
+	}
+	out.Stats.WindowEntries = scanned
+	out.Stats.Truncated = scanned >= maxSpikeScan
+
+	if baseline > 0 && len(groups) > 0 {
+		scanned, err := logs.ScanEntries(ctx, params.ProjectID, filter, baselineStart, windowStart, maxSpikeScan, func(e logging.LogEntry) {
+			if g, ok := groups[e.LogName+"\x00"+messageKey(e)]; ok {
+				g.candidate.BaselineCount++
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read logs before the spike: %w", err)
+		}
+		out.Stats.BaselineEntries = scanned
+		out.Stats.Truncated = out.Stats.Truncated || scanned >= maxSpikeScan
+	}
+
+	// ベースラインの件数を窓の長さに換算した期待値と比べる（+1 で件数の少ないグループの比を抑える）
+	scale := 0.0
+	if baseline > 0 {
+		scale = float64(2*window) / float64(baseline)
+	}
+	for _, g := range groups {
+		expected := float64(g.candidate.BaselineCount) * scale
+		g.candidate.Lift = math.Round(float64(g.candidate.WindowCount+1)/(expected+1)*100) / 100
+		g.candidate.NewInWindow = baseline > 0 && g.candidate.BaselineCount == 0
+		out.Candidates = append(out.Candidates, g.candidate)
+	}
+	sort.Slice(out.Candidates, func(i, j int) bool {
+		a, b := out.Candidates[i], out.Candidates[j]
+		if a.Lift != b.Lift {
+			return a.Lift > b.Lift
+		}
+		if a.WindowCount != b.WindowCount {
+			return a.WindowCount > b.WindowCount
+		}
+		return a.Message < b.Message
+	})
+	out.Stats.Groups = len(out.Candidates)
+	out.Candidates = out.Candidates[:min(len(out.Candidates), params.MaxCandidates)]
+
+	switch {
+	case len(out.Candidates) == 0:
+		out.Note = "No ERROR logs of the spiking resource around the spike. The cause may be upstream or in another resource; try logging.query with a broader filter."
+	case out.Candidates[0].Lift < 2:
+		out.Note = "No log group stands out against the baseline; the errors may be unrelated background noise."
+	}
+	return out, nil
+}
+
+// findSpike は spikeAt（ゼロなら全期間）に最も近いポイントのうち、平均からの偏差（z スコア）が最大の系列とポイントを返す
+func findSpike(series []TimeSeries, spikeAt time.Time) (Spike, bool) {
+	var best Spike
+	found := false
+	for _, ts := range series {
+		if ts.TimeShift != "" || len(ts.Points) == 0 {
+			continue
+		}
+		mean, stddev := meanStddev(ts.Points)
+		candidates := ts.Points
+		if !spikeAt.IsZero() {
+			i := nearestPoint(ts.Points, spikeAt)
+			if i < 0 {
+				continue
+			}
+			candidates = ts.Points[i : i+1]
+		}
+		for _, p := range candidates {
+			z := 0.0
+			if stddev > 0 {
+				z = (p.Value - mean) / stddev
+			}
+			if found && z <= best.ZScore {
+				continue
+			}
+			found = true
+			best = Spike{
+				Detected: spikeAt.IsZero(),
+				Time:     p.Time,
+				Label:    SeriesLabel(ts),
+				Metric:   ts.Metric,
+				Resource: ts.Resource,
+				Value:    p.Value,
+				Mean:     math.Round(mean*1000) / 1000,
+				ZScore:   math.Round(z*100) / 100,
+			}
+		}
+	}
+	return best, found
+}
+
+func meanStddev(points []DataPoint) (float64, float64) {
+	sum := 0.0
+	for _, p := range points {
+		sum += p.Value
+	}
+	mean := sum / float64(len(points))
+	variance := 0.0
+	for _, p := range points {
+		variance += (p.Value - mean) * (p.Value - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(points)))
+}
+
+// nearestPoint は t に最も近いポイントの位置を返す（時刻を読めるポイントがなければ -1）
+func nearestPoint(points []DataPoint, t time.Time) int {
+	best, bestDiff := -1, time.Duration(math.MaxInt64)
+	for i, p := range points {
+		pt, err := time.Parse(time.RFC3339, p.Time)
+		if err != nil {
+			continue
+		}
+		diff := pt.Sub(t)
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff < bestDiff {
+			best, bestDiff = i, diff
+		}
+	}
+	return best
+}
+
+// spikeLogFilter はスパイクした系列のリソースの ERROR 以上のログを読むフィルタ
+func spikeLogFilter(resource ResourceLabels) string {
+	if filter := LogFilter(resource); filter != "" {
+		return filter + " AND severity >= ERROR"
+	}
+	return "severity >= ERROR"
+}
+
+// entryMessage はエントリのメッセージ（textPayload、なければ jsonPayload.message）
+func entryMessage(e logging.LogEntry) string {
+	if e.TextPayload != "" {
+		return e.TextPayload
+	}
+	for _, key := range []string{"message", "msg", "error"} {
+		if s, ok := e.JSONPayload[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	if e.JSONPayload != nil {
+		data, _ := json.Marshal(e.JSONPayload)
+		return string(data)
+	}
+	return ""
+}
+
+// messageKey はメッセージの1行目の可変部分を '#' に置き換えた、グループのキー
+func messageKey(e logging.LogEntry) string {
+	msg, _, _ := strings.Cut(entryMessage(e), "\n")
+	msg = messageVariables.ReplaceAllString(strings.TrimSpace(msg), "#")
+	return truncateMessage(msg, maxSpikeMessageLen)
+}
+
+func truncateMessage(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n]) + "…"
+	}
+	return s
+}
+
+// ExplainSpikeHandlerWithGuardrail returns a handler for the monitoring.explain_spike tool
+func (c *Client) ExplainSpikeHandlerWithGuardrail(v Validator, logs *logging.Client) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params ExplainSpikeParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.MetricType == "" {
+			return nil, fmt.Errorf("metric_type is required")
+		}
+		if logs == nil {
+			return nil, fmt.Errorf("monitoring.explain_spike needs a logging client")
+		}
+		if params.WindowMinutes <= 0 {
+			params.WindowMinutes = 5
+		}
+		if params.BaselineMinutes < 0 {
+			return nil, fmt.Errorf("baseline_minutes must not be negative")
+		}
+		if params.BaselineMinutes == 0 {
+			params.BaselineMinutes = 30
+		}
+		if params.MaxCandidates <= 0 {
+			params.MaxCandidates = 10
+		}
+		params.MaxCandidates = min(params.MaxCandidates, 50)
+
+		// 省略時は、spike_time があればその前後1時間、なければ直近6時間からスパイクを探す
+		if params.TimeRange.Start == "" {
+			if params.SpikeTime != "" {
+				at, err := time.Parse(time.RFC3339, params.SpikeTime)
+				if err != nil {
+					return nil, fmt.Errorf("invalid spike_time: %w", err)
+				}
+				params.TimeRange.Start = at.Add(-spikeContext).UTC().Format(time.RFC3339)
+				if end := at.Add(spikeContext); end.Before(timerange.Now()) {
+					params.TimeRange.End = end.UTC().Format(time.RFC3339)
+				}
+			} else {
+				params.TimeRange.Start = spikeDetectionRange
+			}
+			start, end, err := ParseTimeRange(params.TimeRange)
+			if err != nil {
+				return nil, err
+			}
+			if err := v.ValidateTimeRange(ctx, start, end); err != nil {
+				return nil, err
+			}
+		}
+
+		// ガードレール: 系列数制限
+		params.MaxSeries = v.ClampTimeSeriesLimit(ctx, params.MaxSeries)
+
+		return c.ExplainSpike(ctx, logs, params)
+	}
+}
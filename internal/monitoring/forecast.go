@@ -0,0 +1,295 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// ForecastModels は monitoring.forecast で使えるモデル
+var ForecastModels = []string{"linear", "holt"}
+
+// forecastZ は信頼度ごとの正規分布の両側 z 値
+var forecastZ = map[float64]float64{0.8: 1.2816, 0.9: 1.6449, 0.95: 1.96, 0.99: 2.5758}
+
+// forecastHorizon は見積もる先の上限（それより先に達する見込みは「達しない」とする）
+const forecastHorizon = 365 * 24 * time.Hour
+
+// Holt 法（トレンド付き指数平滑化）の平滑化係数
+const (
+	holtAlpha = 0.5 // 水準
+	holtBeta  = 0.1 // トレンド
+)
+
+// ForecastParams are the parameters for monitoring.forecast
+type ForecastParams struct {
+	QueryTimeSeriesParams
+	Threshold  *float64 `json:"threshold"`
+	Model      string   `json:"model,omitempty"`      // "linear" (default) or "holt"
+	Confidence float64  `json:"confidence,omitempty"` // 0.8, 0.9, 0.95 (default) or 0.99
+}
+
+// ForecastResult is the result of monitoring.forecast
+type ForecastResult struct {
+	QueryMeta ForecastQueryMeta `json:"query_meta"`
+	Series    []SeriesForecast  `json:"series"`
+}
+
+type ForecastQueryMeta struct {
+	QueryMeta
+	Model      string  `json:"model"`
+	Threshold  float64 `json:"threshold"`
+	Confidence float64 `json:"confidence"`
+}
+
+// SeriesForecast is the forecast of one series
+type SeriesForecast struct {
+	Label    string         `json:"label"`
+	Metric   MetricLabels   `json:"metric"`
+	Resource ResourceLabels `json:"resource"`
+	// 最新ポイントの時刻と、モデルが当てはめたその時点の値
+	LastPoint    string  `json:"last_point,omitempty"`
+	Current      float64 `json:"current"`
+	SlopePerHour float64 `json:"slope_per_hour"` // Fitted trend per hour
+	Residual     float64 `json:"residual"`       // Standard deviation of the fit errors
+	// 閾値に達する見込み（達しない場合は reaches_threshold=false と理由）
+	ReachesThreshold bool   `json:"reaches_threshold"`
+	ReachesAt        string `json:"reaches_at,omitempty"`
+	TimeToThreshold  int64  `json:"time_to_threshold_sec,omitempty"`
+	Earliest         string `json:"earliest,omitempty"` // Lower confidence bound of the crossing time
+	Latest           string `json:"latest,omitempty"`   // Upper confidence bound (omitted beyond a year)
+	Note             string `json:"note,omitempty"`
+	PointCount       int    `json:"point_count"`
+}
+
+// trendFit はモデルの当てはめ結果（時刻 last での値 level と毎秒の傾き slope、誤差の標準偏差 residual）
+type trendFit struct {
+	last     time.Time
+	level    float64
+	slope    float64
+	residual float64
+}
+
+// fitLinear は最小二乗法で直線を当てはめる（ポイントは古い順）
+func fitLinear(times []time.Time, values []float64) trendFit {
+	n := float64(len(values))
+	origin := times[0]
+	var sx, sy, sxx, sxy float64
+	for i, v := range values {
+		x := times[i].Sub(origin).Seconds()
+		sx += x
+		sy += v
+		sxx += x * x
+		sxy += x * v
+	}
+	var slope float64
+	if d := n*sxx - sx*sx; d != 0 {
+		slope = (n*sxy - sx*sy) / d
+	}
+	intercept := (sy - slope*sx) / n
+
+	var sse float64
+	for i, v := range values {
+		e := v - (intercept + slope*times[i].Sub(origin).Seconds())
+		sse += e * e
+	}
+	last := times[len(times)-1]
+	return trendFit{
+		last:     last,
+		level:    intercept + slope*last.Sub(origin).Seconds(),
+		slope:    slope,
+		residual: math.Sqrt(sse / math.Max(n-2, 1)),
+	}
+}
+
+// fitHolt は Holt 法で水準とトレンドを求める（ポイントは古い順、間隔は period で揃っているとみなす）
+// 誤差は1期先予測の誤差
+func fitHolt(times []time.Time, values []float64, period time.Duration) trendFit {
+	level := values[0]
+	trend := values[1] - values[0]
+	var sse float64
+	for _, v := range values[1:] {
+		e := v - (level + trend)
+		sse += e * e
+		prev := level
+		level = holtAlpha*v + (1-holtAlpha)*(level+trend)
+		trend = holtBeta*(level-prev) + (1-holtBeta)*trend
+	}
+	return trendFit{
+		last:     times[len(times)-1],
+		level:    level,
+		slope:    trend / period.Seconds(),
+		residual: math.Sqrt(sse / math.Max(float64(len(values)-1), 1)),
+	}
+}
+
+// crossing は level + slope·t が target に達するまでの時間（forecastHorizon 以内に達しなければ false）
+func (f trendFit) crossing(target float64) (time.Duration, bool) {
+	gap := target - f.level
+	if gap == 0 {
+		return 0, true
+	}
+	if f.slope == 0 || (gap > 0) != (f.slope > 0) {
+		return 0, false
+	}
+	sec := gap / f.slope
+	if sec > forecastHorizon.Seconds() {
+		return 0, false
+	}
+	return time.Duration(sec * float64(time.Second)), true
+}
+
+// ForecastPoints は API 順（新しい順）のポイントにモデルを当てはめ、threshold に達する時刻を見積もる
+// 信頼区間は当てはめ誤差の z 倍の帯が閾値に達する時刻（楽観側・悲観側）
+func ForecastPoints(points []DataPoint, model string, threshold, z float64, period time.Duration) SeriesForecast {
+	times := make([]time.Time, 0, len(points))
+	values := make([]float64, 0, len(points))
+	for i := len(points) - 1; i >= 0; i-- {
+		t, err := time.Parse(time.RFC3339, points[i].Time)
+		if err != nil || math.IsNaN(points[i].Value) {
+			continue
+		}
+		times = append(times, t)
+		values = append(values, points[i].Value)
+	}
+
+	out := SeriesForecast{PointCount: len(values)}
+	if len(values) < 3 {
+		out.Note = "not enough points to fit a trend (need at least 3)"
+		return out
+	}
+
+	var fit trendFit
+	if model == "holt" {
+		fit = fitHolt(times, values, period)
+	} else {
+		fit = fitLinear(times, values)
+	}
+	out.LastPoint = fit.last.Format(time.RFC3339)
+	out.Current = fit.level
+	out.SlopePerHour = fit.slope * 3600
+	out.Residual = fit.residual
+
+	// 閾値の方向（上限なら上向きの帯が先に達する）
+	band := z * fit.residual
+	if threshold < fit.level {
+		band = -band
+	}
+	if (threshold-fit.level)*(threshold-fit.level-band) <= 0 {
+		out.Note = "the value is already within the confidence band of the threshold"
+	}
+
+	d, ok := fit.crossing(threshold)
+	if !ok {
+		switch {
+		case out.Note != "":
+		case fit.slope == 0 || (threshold > fit.level) != (fit.slope > 0):
+			out.Note = "the trend is flat or moving away from the threshold"
+		default:
+			out.Note = "the trend does not reach the threshold within a year"
+		}
+		return out
+	}
+	out.ReachesThreshold = true
+	out.ReachesAt = fit.last.Add(d).Format(time.RFC3339)
+	out.TimeToThreshold = int64(d / time.Second)
+
+	earliest, _ := fit.crossing(threshold - band)
+	out.Earliest = fit.last.Add(max(earliest, 0)).Format(time.RFC3339)
+	// 悲観側の帯が上限の先まで達しなければ latest は省く
+	if latest, ok := fit.crossing(threshold + band); ok {
+		out.Latest = fit.last.Add(latest).Format(time.RFC3339)
+	}
+	return out
+}
+
+// Forecast queries the series and projects, per series, when it reaches the threshold
+func (c *Client) Forecast(ctx context.Context, params ForecastParams) (*ForecastResult, error) {
+	if params.Threshold == nil {
+		return nil, fmt.Errorf("threshold is required")
+	}
+	model := strings.ToLower(params.Model)
+	if model == "" {
+		model = "linear"
+	}
+	if model != "linear" && model != "holt" {
+		return nil, fmt.Errorf("unknown model: %s (supported: linear, holt)", params.Model)
+	}
+	confidence := params.Confidence
+	if confidence == 0 {
+		confidence = 0.95
+	}
+	z, ok := forecastZ[confidence]
+	if !ok {
+		return nil, fmt.Errorf("unsupported confidence: %g (supported: 0.8, 0.9, 0.95, 0.99)", params.Confidence)
+	}
+
+	// 当てはめには全ポイントが必要なので、ダウンサンプリングや表示用の指定は無視する
+	query := params.QueryTimeSeriesParams
+	query.MaxPointsPerSeries = 0
+	query.StatsOnly = false
+	query.SeriesOnly = false
+	query.TimeShift = ""
+	query.Render = ""
+
+	result, err := c.QueryTimeSeries(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	period := evaluationPeriod(query.AlignmentPeriodSec, query.SecondaryAggregation)
+	out := &ForecastResult{
+		QueryMeta: ForecastQueryMeta{
+			QueryMeta:  result.QueryMeta,
+			Model:      model,
+			Threshold:  *params.Threshold,
+			Confidence: confidence,
+		},
+		Series: []SeriesForecast{},
+	}
+	for _, ts := range result.Series {
+		f := ForecastPoints(ts.Points, model, *params.Threshold, z, period)
+		f.Label = SeriesLabel(ts)
+		f.Metric = ts.Metric
+		f.Resource = ts.Resource
+		out.Series = append(out.Series, f)
+	}
+	return out, nil
+}
+
+// ForecastHandlerWithGuardrail returns a handler for the monitoring.forecast tool
+func (c *Client) ForecastHandlerWithGuardrail(v Validator) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params ForecastParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.MetricType == "" {
+			return nil, fmt.Errorf("metric_type is required")
+		}
+
+		// トレンドを見るには長めの履歴が要るので、省略時は直近24時間を5分単位で使う
+		if params.TimeRange.Start == "" {
+			params.TimeRange.Start = "-24h"
+			start, end, err := ParseTimeRange(params.TimeRange)
+			if err != nil {
+				return nil, err
+			}
+			if err := v.ValidateTimeRange(ctx, start, end); err != nil {
+				return nil, err
+			}
+		}
+		if params.AlignmentPeriodSec <= 0 {
+			params.AlignmentPeriodSec = 300
+		}
+
+		// ガードレール: 系列数制限
+		params.MaxSeries = v.ClampTimeSeriesLimit(ctx, params.MaxSeries)
+
+		return c.Forecast(ctx, params)
+	}
+}
@@ -0,0 +1,205 @@
+package monitoring
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var forecastStart = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// hourlyPoints は forecastStart から1時間ごとの値を API 順（新しい順）のポイントにする
+func hourlyPoints(values ...float64) []DataPoint {
+	points := make([]DataPoint, len(values))
+	for i, v := range values {
+		points[len(values)-1-i] = DataPoint{Time: forecastStart.Add(time.Duration(i) * time.Hour).Format(time.RFC3339), Value: v}
+	}
+	return points
+}
+
+func hourlyTimes(n int) []time.Time {
+	times := make([]time.Time, n)
+	for i := range times {
+		times[i] = forecastStart.Add(time.Duration(i) * time.Hour)
+	}
+	return times
+}
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9*math.Max(1, math.Abs(b))
+}
+
+func TestFitLinear(t *testing.T) {
+	tests := []struct {
+		name                          string
+		values                        []float64
+		level, slopePerHour, residual float64
+	}{
+		{"exact line", []float64{10, 20, 30, 40}, 40, 10, 0},
+		{"flat", []float64{5, 5, 5}, 5, 0, 0},
+		// 最小二乗: 傾き 10.2/h、最後の時点で 40.8、残差平方和 0.8 を n-2 で割る
+		{"noisy line", []float64{10, 21, 30, 41}, 40.8, 10.2, math.Sqrt(0.4)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fit := fitLinear(hourlyTimes(len(tt.values)), tt.values)
+			if !approxEqual(fit.level, tt.level) || !approxEqual(fit.slope*3600, tt.slopePerHour) || !approxEqual(fit.residual, tt.residual) {
+				t.Errorf("fit = level %g, slope %g/h, residual %g; want %g, %g/h, %g", fit.level, fit.slope*3600, fit.residual, tt.level, tt.slopePerHour, tt.residual)
+			}
+			if !fit.last.Equal(forecastStart.Add(time.Duration(len(tt.values)-1) * time.Hour)) {
+				t.Errorf("last = %v, want the time of the last point", fit.last)
+			}
+		})
+	}
+}
+
+func TestFitHolt(t *testing.T) {
+	tests := []struct {
+		name                          string
+		values                        []float64
+		level, slopePerHour, residual float64
+	}{
+		// 直線なら1期先予測は外れず、水準とトレンドはそのまま進む
+		{"exact line", []float64{10, 20, 30, 40}, 40, 10, 0},
+		{"falling line", []float64{40, 30, 20, 10}, 10, -10, 0},
+		{"flat", []float64{5, 5, 5}, 5, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fit := fitHolt(hourlyTimes(len(tt.values)), tt.values, time.Hour)
+			if !approxEqual(fit.level, tt.level) || !approxEqual(fit.slope*3600, tt.slopePerHour) || !approxEqual(fit.residual, tt.residual) {
+				t.Errorf("fit = level %g, slope %g/h, residual %g; want %g, %g/h, %g", fit.level, fit.slope*3600, fit.residual, tt.level, tt.slopePerHour, tt.residual)
+			}
+		})
+	}
+
+	// 1期先予測の誤差: 2点目まででトレンドを決めるので、3点目のずれがそのまま残差に入る
+	fit := fitHolt(hourlyTimes(3), []float64{0, 10, 30}, time.Hour)
+	if fit.residual == 0 {
+		t.Error("residual = 0 for a series that departs from its initial trend")
+	}
+}
+
+func TestTrendFitCrossing(t *testing.T) {
+	perHour := 1 / 3600.0
+	tests := []struct {
+		name   string
+		fit    trendFit
+		target float64
+		want   time.Duration
+		ok     bool
+	}{
+		{"rising to a higher target", trendFit{level: 10, slope: 10 * perHour}, 40, 3 * time.Hour, true},
+		{"falling to a lower target", trendFit{level: 40, slope: -10 * perHour}, 10, 3 * time.Hour, true},
+		{"already at the target", trendFit{level: 10}, 10, 0, true},
+		{"flat", trendFit{level: 10}, 40, 0, false},
+		{"rising away from a lower target", trendFit{level: 10, slope: 10 * perHour}, 0, 0, false},
+		{"falling away from a higher target", trendFit{level: 10, slope: -10 * perHour}, 20, 0, false},
+		{"beyond the horizon", trendFit{level: 0, slope: perHour}, forecastHorizon.Hours() + 1, 0, false},
+		{"just within the horizon", trendFit{level: 0, slope: perHour}, forecastHorizon.Hours(), forecastHorizon, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.fit.crossing(tt.target)
+			if ok != tt.ok || (ok && (got-tt.want).Abs() > time.Second) {
+				t.Errorf("crossing(%g) = %v, %v; want %v, %v", tt.target, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestForecastPoints(t *testing.T) {
+	rising := hourlyPoints(10, 21, 30, 41) // 最後の時点で 40.8、傾き 10.2/h、残差 √0.4
+	falling := hourlyPoints(-10, -21, -30, -41)
+	last := forecastStart.Add(3 * time.Hour)
+	at := func(hours float64) string {
+		return last.Add(time.Duration(hours * float64(time.Hour))).Format(time.RFC3339)
+	}
+	band := 1.96 * math.Sqrt(0.4)
+
+	tests := []struct {
+		name      string
+		points    []DataPoint
+		model     string
+		threshold float64
+		z         float64
+		reaches   bool
+		reachesAt string
+		earliest  string
+		latest    string
+		note      string
+		count     int
+	}{
+		{
+			name: "rising to an upper threshold", points: rising, threshold: 100, z: 1.96,
+			reaches: true, reachesAt: at(59.2 / 10.2), earliest: at((59.2 - band) / 10.2), latest: at((59.2 + band) / 10.2), count: 4,
+		},
+		{
+			// 下限の閾値では帯の向きが逆になり、上の場合と同じ時刻になる
+			name: "falling to a lower threshold", points: falling, threshold: -100, z: 1.96,
+			reaches: true, reachesAt: at(59.2 / 10.2), earliest: at((59.2 - band) / 10.2), latest: at((59.2 + band) / 10.2), count: 4,
+		},
+		{
+			// 悲観側の帯は1年の先まで達しないので latest は省く
+			name: "latest beyond the horizon", points: rising, threshold: 40.8 + 89000, z: 1000,
+			reaches: true, reachesAt: at(89000 / 10.2), earliest: at((89000 - 1000*math.Sqrt(0.4)) / 10.2), count: 4,
+		},
+		{
+			name: "within the band below the threshold", points: rising, threshold: 41.5, z: 1.96,
+			reaches: true, reachesAt: at(0.7 / 10.2), earliest: at(0), latest: at((0.7 + band) / 10.2), count: 4,
+			note: "the value is already within the confidence band of the threshold",
+		},
+		{
+			// 閾値が現在値より下なら帯は下向きに取る
+			name: "within the band of a lower threshold", points: rising, threshold: 40, z: 1.96,
+			note: "the value is already within the confidence band of the threshold", count: 4,
+		},
+		{
+			name: "moving away from the threshold", points: rising, threshold: 0, z: 1.96,
+			note: "the trend is flat or moving away from the threshold", count: 4,
+		},
+		{
+			name: "flat", points: hourlyPoints(5, 5, 5), threshold: 10, z: 1.96,
+			note: "the trend is flat or moving away from the threshold", count: 3,
+		},
+		{
+			name: "beyond a year", points: rising, threshold: 40.8 + 10.2*9000, z: 1.96,
+			note: "the trend does not reach the threshold within a year", count: 4,
+		},
+		{
+			name: "holt on an exact line", points: hourlyPoints(10, 20, 30, 40), model: "holt", threshold: 100, z: 1.96,
+			reaches: true, reachesAt: at(6), earliest: at(6), latest: at(6), count: 4,
+		},
+		{
+			name: "fewer than 3 points", points: hourlyPoints(10, 20), threshold: 100, z: 1.96,
+			note: "not enough points to fit a trend (need at least 3)", count: 2,
+		},
+		{
+			name: "NaN points are skipped", points: hourlyPoints(10, math.NaN(), 30), threshold: 100, z: 1.96,
+			note: "not enough points to fit a trend (need at least 3)", count: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ForecastPoints(tt.points, tt.model, tt.threshold, tt.z, time.Hour)
+			if got.ReachesThreshold != tt.reaches || got.Note != tt.note || got.PointCount != tt.count {
+				t.Fatalf("reaches = %v, note = %q, point_count = %d; want %v, %q, %d", got.ReachesThreshold, got.Note, got.PointCount, tt.reaches, tt.note, tt.count)
+			}
+			if got.ReachesAt != tt.reachesAt || got.Earliest != tt.earliest || got.Latest != tt.latest {
+				t.Errorf("reaches_at %q, earliest %q, latest %q; want %q, %q, %q", got.ReachesAt, got.Earliest, got.Latest, tt.reachesAt, tt.earliest, tt.latest)
+			}
+			if tt.reaches && got.TimeToThreshold != int64(mustParse(t, got.ReachesAt).Sub(last)/time.Second) {
+				t.Errorf("time_to_threshold_sec = %d does not match reaches_at %s", got.TimeToThreshold, got.ReachesAt)
+			}
+		})
+	}
+}
+
+func mustParse(t *testing.T, s string) time.Time {
+	t.Helper()
+	v, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
@@ -0,0 +1,83 @@
+package monitoring
+
+import (
+	"sort"
+	"time"
+
+	"cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+)
+
+// maxReportedGaps は1系列あたりに列挙する欠損区間の上限（件数は gap_count に全数を入れる）
+const maxReportedGaps = 10
+
+// Freshness reports how recent a series' data is and where it stopped reporting.
+// A gap is an interval longer than 2× the alignment period without points.
+type Freshness struct {
+	LastPoint string `json:"last_point"`      // Time of the newest point
+	AgeSec    int64  `json:"age_sec"`         // Range end − last_point
+	Stale     bool   `json:"stale,omitempty"` // The series stopped reporting before the range end (age > 2× alignment period)
+	GapCount  int    `json:"gap_count"`       // Gaps between points (the trailing stale interval is not counted)
+	Gaps      []Gap  `json:"gaps,omitempty"`  // Longest first, at most 10
+}
+
+// Gap is an interval between two consecutive points with no data
+type Gap struct {
+	Start       string `json:"start"` // Last point before the gap
+	End         string `json:"end"`   // First point after the gap
+	DurationSec int64  `json:"duration_sec"`
+}
+
+// DetectFreshness は API 順（新しい順）のポイントから鮮度と欠損区間を求める
+// ポイントの時刻は shift だけ進めて扱い、end は範囲の終端（ずらす前の期間の時刻）
+func DetectFreshness(points []*monitoringpb.Point, end time.Time, period, shift time.Duration) *Freshness {
+	if len(points) == 0 {
+		return nil
+	}
+
+	pointTime := func(i int) time.Time {
+		return points[i].GetInterval().GetEndTime().AsTime().Add(shift)
+	}
+	threshold := 2 * period
+
+	last := pointTime(0)
+	f := &Freshness{
+		LastPoint: last.Format(time.RFC3339),
+		AgeSec:    int64(max(end.Sub(last), 0) / time.Second),
+		Stale:     period > 0 && end.Sub(last) > threshold,
+	}
+	if period <= 0 {
+		return f
+	}
+
+	gaps := []Gap{}
+	for i := 1; i < len(points); i++ {
+		newer, older := pointTime(i-1), pointTime(i)
+		if d := newer.Sub(older); d > threshold {
+			gaps = append(gaps, Gap{
+				Start:       older.Format(time.RFC3339),
+				End:         newer.Format(time.RFC3339),
+				DurationSec: int64(d / time.Second),
+			})
+		}
+	}
+	f.GapCount = len(gaps)
+
+	// 長い順に上限件数まで
+	sort.SliceStable(gaps, func(i, j int) bool { return gaps[i].DurationSec > gaps[j].DurationSec })
+	if len(gaps) > maxReportedGaps {
+		gaps = gaps[:maxReportedGaps]
+	}
+	if len(gaps) > 0 {
+		f.Gaps = gaps
+	}
+	return f
+}
+
+// outputPeriod は返されるポイントの間隔（二次集約があればその期間）
+func outputPeriod(req *monitoringpb.ListTimeSeriesRequest) time.Duration {
+	period := req.GetAggregation().GetAlignmentPeriod().AsDuration()
+	if secondary := req.GetSecondaryAggregation(); secondary != nil {
+		period = max(period, secondary.GetAlignmentPeriod().AsDuration())
+	}
+	return period
+}
@@ -0,0 +1,226 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ListGroupsParams are the parameters for monitoring.list_groups
+type ListGroupsParams struct {
+	ProjectID string `json:"project_id"`
+	Limit     int    `json:"limit"` // Maximum number of groups to return
+}
+
+// ListGroupsResult is the result of monitoring.list_groups
+type ListGroupsResult struct {
+	QueryMeta GroupsQueryMeta `json:"query_meta"`
+	Groups    []Group         `json:"groups"`
+	Stats     GroupsStats     `json:"stats"`
+}
+
+type GroupsQueryMeta struct {
+	ProjectID string `json:"project_id"`
+}
+
+type Group struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	ParentID    string `json:"parent_id,omitempty"`
+	Filter      string `json:"filter"`
+	IsCluster   bool   `json:"is_cluster"`
+}
+
+type GroupsStats struct {
+	ReturnedCount int  `json:"returned_count"`
+	Truncated     bool `json:"truncated"`
+}
+
+// ListGroupMembersParams are the parameters for monitoring.list_group_members
+type ListGroupMembersParams struct {
+	ProjectID string    `json:"project_id"`
+	GroupID   string    `json:"group_id"`
+	Filter    string    `json:"filter"` // Optional filter (e.g., 'resource.type = "gce_instance"')
+	TimeRange TimeRange `json:"time_range"`
+	Limit     int       `json:"limit"`
+}
+
+// ListGroupMembersResult is the result of monitoring.list_group_members
+type ListGroupMembersResult struct {
+	QueryMeta GroupMembersQueryMeta `json:"query_meta"`
+	Members   []ResourceLabels      `json:"members"`
+	Stats     GroupsStats           `json:"stats"`
+}
+
+type GroupMembersQueryMeta struct {
+	ProjectID string `json:"project_id"`
+	GroupID   string `json:"group_id"`
+	Filter    string `json:"filter,omitempty"`
+	Start     string `json:"start"`
+	End       string `json:"end"`
+}
+
+// ListGroups lists monitoring groups in a project
+func (c *Client) ListGroups(ctx context.Context, params ListGroupsParams) (*ListGroupsResult, error) {
+	// Set defaults
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	req := &monitoringpb.ListGroupsRequest{
+		Name: fmt.Sprintf("projects/%s", params.ProjectID),
+	}
+
+	it := c.api.ListGroups(ctx, req)
+
+	groups := []Group{}
+	truncated := false
+
+	for {
+		g, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate groups: %w", err)
+		}
+
+		groups = append(groups, Group{
+			ID:          groupID(g.GetName()),
+			Name:        g.GetName(),
+			DisplayName: g.GetDisplayName(),
+			ParentID:    groupID(g.GetParentName()),
+			Filter:      g.GetFilter(),
+			IsCluster:   g.GetIsCluster(),
+		})
+
+		if len(groups) >= limit {
+			truncated = true
+			break
+		}
+	}
+
+	return &ListGroupsResult{
+		QueryMeta: GroupsQueryMeta{
+			ProjectID: params.ProjectID,
+		},
+		Groups: groups,
+		Stats: GroupsStats{
+			ReturnedCount: len(groups),
+			Truncated:     truncated,
+		},
+	}, nil
+}
+
+// ListGroupMembers lists monitored resources that belong to a group
+func (c *Client) ListGroupMembers(ctx context.Context, params ListGroupMembersParams) (*ListGroupMembersResult, error) {
+	startTime, endTime, err := parseTimeRange(params.TimeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse time range: %w", err)
+	}
+
+	// Set defaults
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	req := &monitoringpb.ListGroupMembersRequest{
+		Name:   fmt.Sprintf("projects/%s/groups/%s", params.ProjectID, groupID(params.GroupID)),
+		Filter: params.Filter,
+		Interval: &monitoringpb.TimeInterval{
+			StartTime: timestamppb.New(startTime),
+			EndTime:   timestamppb.New(endTime),
+		},
+	}
+
+	it := c.api.ListGroupMembers(ctx, req)
+
+	members := []ResourceLabels{}
+	truncated := false
+
+	for {
+		r, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate group members: %w", err)
+		}
+
+		members = append(members, ResourceLabels{
+			Type:   r.GetType(),
+			Labels: r.GetLabels(),
+		})
+
+		if len(members) >= limit {
+			truncated = true
+			break
+		}
+	}
+
+	return &ListGroupMembersResult{
+		QueryMeta: GroupMembersQueryMeta{
+			ProjectID: params.ProjectID,
+			GroupID:   groupID(params.GroupID),
+			Filter:    params.Filter,
+			Start:     startTime.Format(time.RFC3339),
+			End:       endTime.Format(time.RFC3339),
+		},
+		Members: members,
+		Stats: GroupsStats{
+			ReturnedCount: len(members),
+			Truncated:     truncated,
+		},
+	}, nil
+}
+
+// groupID はリソース名（projects/X/groups/ID）からグループIDを取り出す
+func groupID(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// ListGroupsHandler returns a handler for the monitoring.list_groups tool
+func (c *Client) ListGroupsHandler() func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params ListGroupsParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		return c.ListGroups(ctx, params)
+	}
+}
+
+// ListGroupMembersHandler returns a handler for the monitoring.list_group_members tool
+func (c *Client) ListGroupMembersHandler() func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params ListGroupMembersParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.GroupID == "" {
+			return nil, fmt.Errorf("group_id is required")
+		}
+
+		return c.ListGroupMembers(ctx, params)
+	}
+}
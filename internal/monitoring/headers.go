@@ -0,0 +1,57 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ListSeriesHeaders はポイントを含まない時系列ヘッダー（metric/resourceラベルのみ）を取得する
+// 指定期間にデータを出していたリソースの探索に使う
+func (c *Client) ListSeriesHeaders(ctx context.Context, projectID, filter string, start, end time.Time, limit int) ([]TimeSeries, error) {
+	filter, _ = c.withRestriction(projectID, filter)
+	req := &monitoringpb.ListTimeSeriesRequest{
+		Name:   fmt.Sprintf("projects/%s", projectID),
+		Filter: filter,
+		Interval: &monitoringpb.TimeInterval{
+			StartTime: timestamppb.New(start),
+			EndTime:   timestamppb.New(end),
+		},
+		View: monitoringpb.ListTimeSeriesRequest_HEADERS,
+	}
+
+	it := c.api.ListTimeSeries(ctx, req)
+
+	series := []TimeSeries{}
+	for {
+		ts, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate time series: %w", err)
+		}
+
+		series = append(series, TimeSeries{
+			Metric: MetricLabels{
+				Type:   ts.GetMetric().GetType(),
+				Labels: ts.GetMetric().GetLabels(),
+			},
+			Resource: ResourceLabels{
+				Type:   ts.GetResource().GetType(),
+				Labels: ts.GetResource().GetLabels(),
+			},
+			Points: []DataPoint{},
+		})
+
+		if len(series) >= limit {
+			break
+		}
+	}
+
+	return series, nil
+}
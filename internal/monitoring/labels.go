@@ -0,0 +1,166 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxLabelScanSeries は label values の探索で読む時系列ヘッダーの上限
+const maxLabelScanSeries = 5000
+
+// ListLabelValuesParams are the parameters for monitoring.list_label_values
+type ListLabelValuesParams struct {
+	ProjectID    string    `json:"project_id"`
+	MetricType   string    `json:"metric_type"`
+	LabelKey     string    `json:"label_key"` // "resource.labels.service_name", "metric.labels.response_code_class" or a bare key
+	ResourceType string    `json:"resource_type,omitempty"`
+	Filter       string    `json:"filter,omitempty"` // Raw Monitoring filter ANDed to the query
+	TimeRange    TimeRange `json:"time_range"`
+	Limit        int       `json:"limit"` // Maximum number of values to return
+}
+
+// ListLabelValuesResult is the result of monitoring.list_label_values
+type ListLabelValuesResult struct {
+	QueryMeta LabelValuesQueryMeta `json:"query_meta"`
+	Values    []LabelValue         `json:"values"`
+	Stats     LabelValuesStats     `json:"stats"`
+}
+
+type LabelValuesQueryMeta struct {
+	ProjectID  string `json:"project_id"`
+	MetricType string `json:"metric_type"`
+	LabelKey   string `json:"label_key"`
+	Start      string `json:"start"`
+	End        string `json:"end"`
+}
+
+// LabelValue is a distinct label value and the number of series that carry it
+type LabelValue struct {
+	Value       string `json:"value"`
+	SeriesCount int    `json:"series_count"`
+}
+
+type LabelValuesStats struct {
+	ScannedSeries  int  `json:"scanned_series"`
+	DistinctValues int  `json:"distinct_values"`
+	MissingLabel   int  `json:"missing_label,omitempty"` // ラベルを持たない系列の数
+	Truncated      bool `json:"truncated"`               // 値の数が limit を超えた、または系列の走査を打ち切った
+}
+
+// ListLabelValues returns the distinct values of a label among the series that reported the metric
+// in the time range, using a headers-only ListTimeSeries (no points are transferred)
+func (c *Client) ListLabelValues(ctx context.Context, params ListLabelValuesParams) (*ListLabelValuesResult, error) {
+	startTime, endTime, err := parseTimeRange(params.TimeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse time range: %w", err)
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	filter := "metric.type = " + strconv.Quote(params.MetricType)
+	if params.ResourceType != "" {
+		filter += " AND resource.type = " + strconv.Quote(params.ResourceType)
+	}
+	if params.Filter != "" {
+		filter += fmt.Sprintf(` AND (%s)`, params.Filter)
+	}
+
+	series, err := c.ListSeriesHeaders(ctx, params.ProjectID, filter, startTime, endTime, maxLabelScanSeries)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	missing := 0
+	for _, ts := range series {
+		value, ok := labelValue(ts, params.LabelKey)
+		if !ok {
+			missing++
+			continue
+		}
+		counts[value]++
+	}
+
+	values := make([]LabelValue, 0, len(counts))
+	for v, n := range counts {
+		values = append(values, LabelValue{Value: v, SeriesCount: n})
+	}
+	// 系列数の多い順（同数なら値の昇順）
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].SeriesCount != values[j].SeriesCount {
+			return values[i].SeriesCount > values[j].SeriesCount
+		}
+		return values[i].Value < values[j].Value
+	})
+
+	truncated := len(series) >= maxLabelScanSeries
+	if len(values) > limit {
+		values = values[:limit]
+		truncated = true
+	}
+
+	return &ListLabelValuesResult{
+		QueryMeta: LabelValuesQueryMeta{
+			ProjectID:  params.ProjectID,
+			MetricType: params.MetricType,
+			LabelKey:   params.LabelKey,
+			Start:      startTime.Format(time.RFC3339),
+			End:        endTime.Format(time.RFC3339),
+		},
+		Values: values,
+		Stats: LabelValuesStats{
+			ScannedSeries:  len(series),
+			DistinctValues: len(counts),
+			MissingLabel:   missing,
+			Truncated:      truncated,
+		},
+	}, nil
+}
+
+// labelValue は系列から key のラベル値を取り出す
+// "resource.labels.X" / "metric.labels.X" はそれぞれのラベル、プレフィックスのないキーは resource → metric の順に探す
+func labelValue(ts TimeSeries, key string) (string, bool) {
+	if k, ok := strings.CutPrefix(key, "resource.labels."); ok {
+		v, ok := ts.Resource.Labels[k]
+		return v, ok
+	}
+	if k, ok := strings.CutPrefix(key, "metric.labels."); ok {
+		v, ok := ts.Metric.Labels[k]
+		return v, ok
+	}
+	if v, ok := ts.Resource.Labels[key]; ok {
+		return v, true
+	}
+	v, ok := ts.Metric.Labels[key]
+	return v, ok
+}
+
+// ListLabelValuesHandler returns a handler for the monitoring.list_label_values tool
+func (c *Client) ListLabelValuesHandler() func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params ListLabelValuesParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.MetricType == "" {
+			return nil, fmt.Errorf("metric_type is required")
+		}
+		if params.LabelKey == "" {
+			return nil, fmt.Errorf("label_key is required")
+		}
+
+		return c.ListLabelValues(ctx, params)
+	}
+}
@@ -0,0 +1,48 @@
+package monitoring
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// logResourceTypes は Monitoring と Logging で名前の違うリソースタイプと、Logging 側で使えるラベル
+// ここにないタイプは同じ名前・同じラベルで Logging にも現れる（cloud_run_revision、k8s_container、gce_instance など）
+var logResourceTypes = map[string]struct {
+	logType string
+	labels  []string
+}{
+	// 外部アプリケーション LB のリクエストログは http_load_balancer（backend_target_name などは Logging にない）
+	"https_lb_rule": {"http_load_balancer", []string{"forwarding_rule_name", "url_map_name", "target_proxy_name"}},
+}
+
+// LogFilter returns an LQL filter selecting the logs of the series' monitored resource
+// (resource type and labels; project_id is covered by the project the logs are read from).
+// Empty when the series has no resource type, e.g. after a cross-series reducer.
+func LogFilter(resource ResourceLabels) string {
+	if resource.Type == "" {
+		return ""
+	}
+	logType, keys := resource.Type, []string{}
+	if t, ok := logResourceTypes[resource.Type]; ok {
+		logType = t.logType
+		for _, k := range t.labels {
+			if _, ok := resource.Labels[k]; ok {
+				keys = append(keys, k)
+			}
+		}
+	} else {
+		for k := range resource.Labels {
+			if k != "project_id" {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+	}
+
+	clauses := []string{fmt.Sprintf(`resource.type = "%s"`, logType)}
+	for _, k := range keys {
+		clauses = append(clauses, fmt.Sprintf(`resource.labels.%s = "%s"`, k, strings.ReplaceAll(resource.Labels[k], `"`, `\"`)))
+	}
+	return strings.Join(clauses, " AND ")
+}
@@ -0,0 +1,720 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/guardrail"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/provider"
+)
+
+func init() {
+	provider.Register("monitoring", newProvider)
+}
+
+// toolProvider は Cloud Monitoring のツール（monitoring.*）を提供する
+type toolProvider struct {
+	client *provider.Lazy[*Client]
+	// logs は monitoring.explain_spike がスパイク前後のエラーログを読むクライアント
+	// （logging プロバイダを無効にしても使えるよう自前で作る）
+	logs  *provider.Lazy[*logging.Client]
+	cfg   *config.Config
+	guard *guardrail.Guardrail
+}
+
+func newProvider(ctx context.Context, env provider.Env) (provider.ToolProvider, error) {
+	client := provider.NewLazy(ctx, "monitoring", func(ctx context.Context) (*Client, error) {
+		client, err := NewClient(ctx, env.GRPCOptionsFor("monitoring")...)
+		if err != nil {
+			return nil, err
+		}
+		client.SetResourceRules(env.Config.ResourceRulesFor)
+		return client, nil
+	})
+	logs := provider.NewLazy(ctx, "logging", func(ctx context.Context) (*logging.Client, error) {
+		client, err := logging.NewClient(ctx, env.GRPCOptionsFor("logging")...)
+		if err != nil {
+			return nil, err
+		}
+		client.SetAllowedLogViews(env.Config.AllowedLogViews)
+		client.SetExcludeFilters(env.Config.Logging.ExcludeFilters)
+		client.SetResourceRules(env.Config.ResourceRulesFor)
+		return client, nil
+	})
+	return &toolProvider{client: client, logs: logs, cfg: env.Config, guard: env.Guard}, nil
+}
+
+// NewProviderWithClient returns the provider backed by an existing client
+// (e.g. NewClientWithAPI with a fake), for tests. logs is used by monitoring.explain_spike
+// and may be nil when that tool is not exercised
+func NewProviderWithClient(env provider.Env, client *Client, logs *logging.Client) provider.ToolProvider {
+	client.SetResourceRules(env.Config.ResourceRulesFor)
+	return &toolProvider{client: provider.Ready(client), logs: provider.Ready(logs), cfg: env.Config, guard: env.Guard}
+}
+
+func (p *toolProvider) Name() string {
+	return "monitoring"
+}
+
+func (p *toolProvider) RequiredAPIs() []string {
+	return []string{"monitoring.googleapis.com", "logging.googleapis.com"}
+}
+
+func (p *toolProvider) Tools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "monitoring.query_time_series",
+			Description: "Query Cloud Monitoring time series data.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: p.queryProperties(map[string]mcp.Property{
+					"time_shift": {
+						Type:        "string",
+						Description: "Also return the same series shifted back by this period for comparison (e.g., '1d', '7d', '1w'). Shifted series have time_shift set and their timestamps moved onto the current range so they overlay",
+					},
+					"max_points_per_series": {
+						Type:        "integer",
+						Description: fmt.Sprintf("Maximum data points per series; longer series are downsampled into buckets (default/max: %d). stats shows original vs returned point counts", p.cfg.Limits.MaxPointsPerSeries),
+						Default:     p.cfg.Limits.MaxPointsPerSeries,
+					},
+					"stats_only": {
+						Type:        "boolean",
+						Description: "Return only per-series summary stats (count/min/max/avg/p95/last) without data points",
+						Default:     false,
+					},
+					"series_only": {
+						Type:        "boolean",
+						Description: "Return only the identities (metric/resource labels) of matching series without points or summaries (headers-only query), e.g. to see which revisions are emitting a metric",
+						Default:     false,
+					},
+					"normalize": {
+						Type:        "boolean",
+						Description: "Also return each point converted to query_meta.unit.display_unit (e.g. bytes→MiB, s/ns→ms, ratio→%) as 'normalized'",
+						Default:     false,
+					},
+					"downsample": {
+						Type:        "string",
+						Description: "Bucket aggregation used when downsampling (mean keeps the trend, max keeps spikes)",
+						Enum:        DownsampleMethods,
+						Default:     "mean",
+					},
+					"render": {
+						Type:        "string",
+						Description: "points (default): every data point. sparkline: one line per series with min/max/avg/last and a sparkline, for questions about the shape. chart: the same summary plus a PNG line chart image",
+						Enum:        []string{"points", "sparkline", "chart"},
+						Default:     "points",
+					},
+				}),
+				Required: []string{"project_id", "metric_type"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[QueryTimeSeriesResult](),
+			Examples:     queryTimeSeriesExamples,
+		},
+		{
+			Name:        "monitoring.evaluate_threshold",
+			Description: "Dry-run an alert condition: query a metric like monitoring.query_time_series and return, per series, the intervals where 'value <comparison> threshold' held for at least duration_sec (with when it would have fired and the peak value). Useful when tuning alert policy thresholds.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: p.queryProperties(map[string]mcp.Property{
+					"comparison": {
+						Type:        "string",
+						Description: "Comparison between the value and the threshold (short forms like 'GT' or '>' are also accepted)",
+						Enum:        Comparisons,
+					},
+					"threshold": {
+						Type:        "number",
+						Description: "Threshold value, in the unit of the aligned values (e.g. per-second rate with ALIGN_RATE)",
+					},
+					"duration_sec": {
+						Type:        "integer",
+						Description: "How long the condition must hold continuously, like an alert condition's duration (default: 0 = any single point)",
+						Default:     0,
+					},
+				}),
+				Required: []string{"project_id", "metric_type", "comparison", "threshold"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[EvaluateThresholdResult](),
+			Examples:     evaluateThresholdExamples,
+		},
+		{
+			Name:        "monitoring.forecast",
+			Description: "Fit a trend (linear or Holt) to each series and project when it reaches a threshold, with confidence bounds. Answers capacity questions like 'when will this disk fill up?'. Uses the last 24h at 5-minute alignment unless time_range/alignment_period_sec are given.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: p.queryProperties(map[string]mcp.Property{
+					"threshold": {
+						Type:        "number",
+						Description: "Value to project the time to (e.g. the disk size), in the unit of the aligned values",
+					},
+					"model": {
+						Type:        "string",
+						Description: "linear: least-squares line over the whole range. holt: exponential smoothing with trend, which follows recent changes in the trend",
+						Enum:        ForecastModels,
+						Default:     "linear",
+					},
+					"confidence": {
+						Type:        "number",
+						Description: "Confidence level of the earliest/latest bounds (0.8, 0.9, 0.95 or 0.99)",
+						Default:     0.95,
+					},
+				}),
+				Required: []string{"project_id", "metric_type", "threshold"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[ForecastResult](),
+		},
+		{
+			Name:        "monitoring.backtest_alert_policy",
+			Description: "Replay an existing alert policy (or an inline threshold condition) over the last N days of data and report how many times and when it would have fired. Only metric threshold conditions can be replayed; others are reported as skipped. Useful for reducing alert noise before changing a policy.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"policy_id": {
+						Type:        "string",
+						Description: "Alert policy ID (or full resource name) to replay. Specify either policy_id or condition",
+					},
+					"condition": {
+						Type:        "object",
+						Description: "Inline threshold condition in the shape of an alert policy's conditionThreshold (instead of policy_id)",
+						Properties: map[string]mcp.Property{
+							"display_name": {
+								Type:        "string",
+								Description: "Condition name",
+							},
+							"filter": {
+								Type:        "string",
+								Description: "Monitoring filter; must contain metric.type = \"...\"",
+							},
+							"alignment_period_sec": {
+								Type:        "integer",
+								Description: "Alignment period in seconds (default: 60)",
+							},
+							"per_series_aligner": {
+								Type:        "string",
+								Description: "Per-series aligner (default: ALIGN_RATE for counters, ALIGN_MEAN otherwise)",
+							},
+							"cross_series_reducer": {
+								Type:        "string",
+								Description: "Cross-series reducer",
+							},
+							"group_by_fields": {
+								Type:        "array",
+								Description: "Fields to preserve when reducing",
+								Items:       &mcp.Property{Type: "string"},
+							},
+							"comparison": {
+								Type:        "string",
+								Description: "Comparison between the value and the threshold",
+								Enum:        Comparisons,
+							},
+							"threshold_value": {
+								Type:        "number",
+								Description: "Threshold value, in the unit of the aligned values",
+							},
+							"duration_sec": {
+								Type:        "integer",
+								Description: "How long the condition must hold continuously (default: 0)",
+							},
+							"trigger_count": {
+								Type:        "integer",
+								Description: "Number of series that must violate at the same time (default: 1)",
+							},
+							"trigger_percent": {
+								Type:        "number",
+								Description: "Or the percentage of series that must violate at the same time",
+							},
+						},
+					},
+					"days": {
+						Type:        "integer",
+						Description: "How many days back to replay (default: 3; limited by the guardrail's maximum time range)",
+						Default:     3,
+					},
+					"max_series": {
+						Type:        "integer",
+						Description: fmt.Sprintf("Maximum number of time series per condition (default: 20, max: %d)", p.cfg.Limits.MaxTimeSeries),
+						Default:     20,
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[BacktestAlertPolicyResult](),
+		},
+		{
+			Name:        "monitoring.explain_spike",
+			Description: "Correlate a metric spike with logs in one call: find the spike in the queried series (or use spike_time), read the ERROR logs of the spiking series' resource (resource type and labels) within ±window_minutes, and rank the log groups (same log and message, numbers and IDs masked) by how much more often they occur than in the preceding baseline window. Searches the last 6h unless time_range or spike_time is given.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: p.queryProperties(map[string]mcp.Property{
+					"spike_time": {
+						Type:        "string",
+						Description: "Time of the spike (RFC3339), e.g. from an incident or a chart. When omitted, the point with the largest deviation from its series' mean is used",
+					},
+					"window_minutes": {
+						Type:        "integer",
+						Description: "Logs are read within ± this many minutes of the spike (default: 5)",
+						Default:     5,
+					},
+					"baseline_minutes": {
+						Type:        "integer",
+						Description: "Length of the window right before the spike window whose logs are the baseline for ranking (default: 30)",
+						Default:     30,
+					},
+					"max_candidates": {
+						Type:        "integer",
+						Description: "Maximum number of log groups to return (default: 10, max: 50)",
+						Default:     10,
+					},
+				}),
+				Required: []string{"project_id", "metric_type"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[ExplainSpikeResult](),
+		},
+		{
+			Name:        "monitoring.list_metric_descriptors",
+			Description: "List available metric descriptors in a project. Useful for discovering what metrics are available.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"filter": {
+						Type:        "string",
+						Description: "Optional filter (e.g., 'metric.type = starts_with(\"run.googleapis.com\")')",
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of descriptors to return (default: 100, max: 500)",
+						Default:     100,
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[ListMetricDescriptorsResult](),
+		},
+		{
+			Name:        "monitoring.list_label_values",
+			Description: "List the distinct values of a label (e.g. service names, regions, response code classes) among the series that reported a metric in a time range, with the number of series per value. Uses a headers-only query, so no data points are read. Useful before building a grouped or filtered query_time_series.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"metric_type": {
+						Type:        "string",
+						Description: "Metric type (e.g., 'run.googleapis.com/request_count')",
+					},
+					"label_key": {
+						Type:        "string",
+						Description: "Label to enumerate (e.g., 'resource.labels.service_name', 'metric.labels.response_code_class'); a bare key is looked up in resource labels, then metric labels",
+					},
+					"resource_type": {
+						Type:        "string",
+						Description: "Resource type (e.g., 'cloud_run_revision')",
+					},
+					"filter": {
+						Type:        "string",
+						Description: "Additional raw Monitoring filter expression ANDed to the query",
+					},
+					"time_range": {
+						Type:        "object",
+						Description: "Time range in which the series must have reported",
+						Properties: map[string]mcp.Property{
+							"start": {
+								Type:        "string",
+								Description: "Start time (RFC3339 or relative like '-1h', '-30m')",
+							},
+							"end": {
+								Type:        "string",
+								Description: "End time (RFC3339 or 'now')",
+								Default:     "now",
+							},
+						},
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of values to return (default: 100, max: 1000)",
+						Default:     100,
+					},
+				},
+				Required: []string{"project_id", "metric_type", "label_key"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[ListLabelValuesResult](),
+		},
+		{
+			Name:        "monitoring.list_groups",
+			Description: "List Cloud Monitoring groups in a project. Useful for interpreting alerts or dashboards defined against groups.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of groups to return (default: 100, max: 500)",
+						Default:     100,
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[ListGroupsResult](),
+		},
+		{
+			Name:        "monitoring.list_group_members",
+			Description: "List monitored resources that are members of a Cloud Monitoring group.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"group_id": {
+						Type:        "string",
+						Description: "Group ID (or full resource name 'projects/X/groups/ID')",
+					},
+					"filter": {
+						Type:        "string",
+						Description: "Optional filter on members (e.g., 'resource.type = \"gce_instance\"')",
+					},
+					"time_range": {
+						Type:        "object",
+						Description: "Time range in which members were part of the group",
+						Properties: map[string]mcp.Property{
+							"start": {
+								Type:        "string",
+								Description: "Start time (RFC3339 or relative like '-1h', '-30m')",
+							},
+							"end": {
+								Type:        "string",
+								Description: "End time (RFC3339 or 'now')",
+								Default:     "now",
+							},
+						},
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of members to return (default: 100, max: 500)",
+						Default:     100,
+					},
+				},
+				Required: []string{"project_id", "group_id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[ListGroupMembersResult](),
+		},
+		{
+			Name:        "monitoring.list_services",
+			Description: "List Service Monitoring services (custom and auto-detected Cloud Run, GKE workloads, Istio, etc.) with their telemetry identifiers.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"filter": {
+						Type:        "string",
+						Description: "Optional filter (e.g., 'identifier_case=\"CLOUD_RUN\"')",
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of services to return (default: 100, max: 500)",
+						Default:     100,
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[ListServicesResult](),
+		},
+		{
+			Name:        "monitoring.list_snoozes",
+			Description: "List alert snoozes in a project with their policies and active window.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"active_only": {
+						Type:        "boolean",
+						Description: "Only return snoozes that have not ended yet (default: false)",
+						Default:     false,
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of snoozes to return (default: 50, max: 500)",
+						Default:     50,
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[ListSnoozesResult](),
+		},
+		{
+			Name:        "monitoring.create_snooze",
+			Description: "Snooze alert policies from now for a given duration. Two-step: the first call returns a preview and confirm_token; call again with the same arguments plus confirm_token to execute. Every executed snooze is audit-logged.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"policy_ids": {
+						Type:        "array",
+						Description: "Alert policy IDs (or full resource names) to snooze",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"duration_minutes": {
+						Type:        "integer",
+						Description: "Snooze duration from now in minutes (max: 10080 = 7 days)",
+					},
+					"reason": {
+						Type:        "string",
+						Description: "Why the alert is being snoozed (recorded in the audit log)",
+					},
+					"display_name": {
+						Type:        "string",
+						Description: "Snooze display name (default: 'gcp-ops-mcp: <reason>')",
+					},
+					"confirm_token": {
+						Type:        "string",
+						Description: "Token returned by the preview call. Omit to preview.",
+					},
+				},
+				Required: []string{"project_id", "policy_ids", "duration_minutes", "reason"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[SnoozeWriteResult](),
+			Annotations:  &mcp.ToolAnnotations{ReadOnlyHint: false, DestructiveHint: false},
+		},
+		mcp.ToolFor[WriteCustomMetricParams](mcp.Tool{
+			Name:         "monitoring.write_custom_metric",
+			Description:  "Write one annotation-style point (e.g. 'investigation started') to a custom metric under custom.googleapis.com/mcp/ on the global resource, so it can be charted next to the real data. Two-step: the first call returns a preview and confirm_token; call again with the same arguments plus confirm_token to execute.",
+			OutputSchema: mcp.OutputSchemaFor[WriteCustomMetricResult](),
+			Annotations:  &mcp.ToolAnnotations{ReadOnlyHint: false, DestructiveHint: false},
+		}),
+		{
+			Name:        "monitoring.delete_snooze",
+			Description: "End a snooze immediately (the API has no delete; the snooze interval is shortened to now). Two-step with confirm_token like monitoring.create_snooze.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"snooze_id": {
+						Type:        "string",
+						Description: "Snooze ID (or full resource name)",
+					},
+					"confirm_token": {
+						Type:        "string",
+						Description: "Token returned by the preview call. Omit to preview.",
+					},
+				},
+				Required: []string{"project_id", "snooze_id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[SnoozeWriteResult](),
+			Annotations:  &mcp.ToolAnnotations{ReadOnlyHint: false, DestructiveHint: true, IdempotentHint: true},
+		},
+	}
+}
+
+// queryProperties は時系列クエリを組み立てるツール（query_time_series など）に共通の入力と、ツール固有の入力 extra を合わせる
+func (p *toolProvider) queryProperties(extra map[string]mcp.Property) map[string]mcp.Property {
+	props := map[string]mcp.Property{
+		"project_id": {
+			Type:        "string",
+			Description: "GCP project ID",
+		},
+		"metric_type": {
+			Type:        "string",
+			Description: "Metric type (e.g., 'run.googleapis.com/request_count')",
+		},
+		"resource_type": {
+			Type:        "string",
+			Description: "Resource type (e.g., 'cloud_run_revision')",
+		},
+		"filters": {
+			Type:        "object",
+			Description: "Additional label filters as key-value pairs; keys are label paths (e.g., {'resource.labels.service_name': 'checkout'})",
+		},
+		"filter": {
+			Type:        "string",
+			Description: "Additional raw Monitoring filter expression ANDed to the query (e.g., 'metric.labels.response_code_class = \"5xx\"')",
+		},
+		"alignment_period_sec": {
+			Type:        "integer",
+			Description: "Alignment period in seconds (default: 60)",
+			Default:     60,
+		},
+		"per_series_aligner": {
+			Type:        "string",
+			Description: "Per-series aligner (e.g., 'ALIGN_MEAN', 'ALIGN_RATE', 'ALIGN_PERCENTILE_99'; default: ALIGN_RATE for CUMULATIVE/DELTA counters, ALIGN_MEAN otherwise)",
+		},
+		"cross_series_reducer": {
+			Type:        "string",
+			Description: "Cross-series reducer (e.g., 'REDUCE_SUM', 'REDUCE_MEAN'; default: none)",
+		},
+		"group_by_fields": {
+			Type:        "array",
+			Description: "Fields to preserve when reducing (e.g., ['resource.labels.service_name'])",
+			Items:       &mcp.Property{Type: "string"},
+		},
+		"secondary_aggregation": {
+			Type:        "object",
+			Description: "Second aggregation applied to the result of the first (e.g., sum per-zone rates, then take the max across zones)",
+			Properties: map[string]mcp.Property{
+				"alignment_period_sec": {
+					Type:        "integer",
+					Description: "Alignment period in seconds (default: same as alignment_period_sec)",
+				},
+				"per_series_aligner": {
+					Type:        "string",
+					Description: "Per-series aligner (default: ALIGN_MEAN)",
+				},
+				"cross_series_reducer": {
+					Type:        "string",
+					Description: "Cross-series reducer",
+				},
+				"group_by_fields": {
+					Type:        "array",
+					Description: "Fields to preserve when reducing",
+					Items:       &mcp.Property{Type: "string"},
+				},
+			},
+		},
+		"time_range": {
+			Type:        "object",
+			Description: "Time range for the query",
+			Properties: map[string]mcp.Property{
+				"start": {
+					Type:        "string",
+					Description: "Start time (RFC3339 or relative like '-1h', '-30m')",
+				},
+				"end": {
+					Type:        "string",
+					Description: "End time (RFC3339 or 'now')",
+					Default:     "now",
+				},
+			},
+		},
+		"max_series": {
+			Type:        "integer",
+			Description: fmt.Sprintf("Maximum number of time series to return (default: 20, max: %d)", p.cfg.Limits.MaxTimeSeries),
+			Default:     20,
+		},
+	}
+	for k, v := range extra {
+		props[k] = v
+	}
+	return props
+}
+
+func (p *toolProvider) Handlers() map[string]mcp.ToolHandler {
+	return map[string]mcp.ToolHandler{
+		"monitoring.query_time_series":     p.client.Handler(func(c *Client) mcp.ToolHandler { return c.QueryTimeSeriesHandlerWithGuardrail(p.guard) }),
+		"monitoring.evaluate_threshold":    p.client.Handler(func(c *Client) mcp.ToolHandler { return c.EvaluateThresholdHandlerWithGuardrail(p.guard) }),
+		"monitoring.forecast":              p.client.Handler(func(c *Client) mcp.ToolHandler { return c.ForecastHandlerWithGuardrail(p.guard) }),
+		"monitoring.backtest_alert_policy": p.client.Handler(func(c *Client) mcp.ToolHandler { return c.BacktestAlertPolicyHandlerWithGuardrail(p.guard) }),
+		"monitoring.explain_spike": p.client.Handler(func(c *Client) mcp.ToolHandler {
+			return p.logs.Handler(func(l *logging.Client) mcp.ToolHandler { return c.ExplainSpikeHandlerWithGuardrail(p.guard, l) })
+		}),
+		"monitoring.list_metric_descriptors": p.client.Handler(func(c *Client) mcp.ToolHandler { return c.ListMetricDescriptorsHandler() }),
+		"monitoring.list_label_values":       p.client.Handler(func(c *Client) mcp.ToolHandler { return c.ListLabelValuesHandler() }),
+		"monitoring.list_groups":             p.client.Handler(func(c *Client) mcp.ToolHandler { return c.ListGroupsHandler() }),
+		"monitoring.list_group_members":      p.client.Handler(func(c *Client) mcp.ToolHandler { return c.ListGroupMembersHandler() }),
+		"monitoring.list_services":           p.client.Handler(func(c *Client) mcp.ToolHandler { return c.ListServicesHandler() }),
+		"monitoring.list_snoozes":            p.client.Handler(func(c *Client) mcp.ToolHandler { return c.ListSnoozesHandler() }),
+		"monitoring.create_snooze":           p.client.Handler(func(c *Client) mcp.ToolHandler { return c.CreateSnoozeHandlerWithGuardrail(p.guard) }),
+		"monitoring.write_custom_metric":     p.client.Handler(func(c *Client) mcp.ToolHandler { return c.WriteCustomMetricHandlerWithGuardrail(p.guard) }),
+		"monitoring.delete_snooze":           p.client.Handler(func(c *Client) mcp.ToolHandler { return c.DeleteSnoozeHandlerWithGuardrail(p.guard) }),
+	}
+}
+
+func (p *toolProvider) Close() error {
+	return errors.Join(p.client.Close((*Client).Close), p.logs.Close(func(l *logging.Client) error {
+		if l == nil { // NewProviderWithClient で logs を渡さなかった
+			return nil
+		}
+		return l.Close()
+	}))
+}
+
+// queryTimeSeriesExamples は monitoring.query_time_series の呼び出し例
+// aligner・reducer・group_by_fields の組み合わせをモデルが間違えやすいので、よく使う集計を示す
+var queryTimeSeriesExamples = []mcp.ToolExample{
+	{
+		Description: "Request rate per Cloud Run service",
+		Arguments: map[string]any{
+			"project_id":           "my-project",
+			"metric_type":          "run.googleapis.com/request_count",
+			"resource_type":        "cloud_run_revision",
+			"per_series_aligner":   "ALIGN_RATE",
+			"cross_series_reducer": "REDUCE_SUM",
+			"group_by_fields":      []string{"resource.labels.service_name"},
+			"time_range":           map[string]any{"start": "-1h"},
+		},
+		Result: "one series per service, in requests per second",
+	},
+	{
+		Description: "p99 latency per service, for the shape only",
+		Arguments: map[string]any{
+			"project_id":           "my-project",
+			"metric_type":          "run.googleapis.com/request_latencies",
+			"per_series_aligner":   "ALIGN_PERCENTILE_99",
+			"cross_series_reducer": "REDUCE_MAX",
+			"group_by_fields":      []string{"resource.labels.service_name"},
+			"alignment_period_sec": 300,
+			"time_range":           map[string]any{"start": "-6h"},
+			"render":               "sparkline",
+		},
+		Result: "one line per service with min/max/avg/last of the p99 latency (ms) and a sparkline",
+	},
+	{
+		Description: "5xx responses compared with the same time last week",
+		Arguments: map[string]any{
+			"project_id":           "my-project",
+			"metric_type":          "run.googleapis.com/request_count",
+			"filter":               `metric.labels.response_code_class = "5xx"`,
+			"per_series_aligner":   "ALIGN_RATE",
+			"cross_series_reducer": "REDUCE_SUM",
+			"time_range":           map[string]any{"start": "-3h"},
+			"time_shift":           "7d",
+			"stats_only":           true,
+		},
+		Result: "summary stats of the current series and of the series shifted back 7 days (time_shift: 7d)",
+	},
+}
+
+// evaluateThresholdExamples は monitoring.evaluate_threshold の呼び出し例
+var evaluateThresholdExamples = []mcp.ToolExample{
+	{
+		Description: "Would 'p99 latency above 1s for 5 minutes' have fired yesterday",
+		Arguments: map[string]any{
+			"project_id":           "my-project",
+			"metric_type":          "run.googleapis.com/request_latencies",
+			"per_series_aligner":   "ALIGN_PERCENTILE_99",
+			"alignment_period_sec": 60,
+			"comparison":           "COMPARISON_GT",
+			"threshold":            1000,
+			"duration_sec":         300,
+			"time_range":           map[string]any{"start": "-24h"},
+		},
+		Result: "per series, the intervals over 1000 ms lasting 5 minutes or more, with when the alert would have fired and the peak",
+	},
+}
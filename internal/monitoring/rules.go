@@ -0,0 +1,58 @@
+package monitoring
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+)
+
+// SetResourceRules sets the lookup of resource_rules; every time series read of a matching
+// project is restricted to the allowed resource types and excludes the denied labels
+func (c *Client) SetResourceRules(rulesFor func(projectID string) []config.ResourceRule) {
+	c.rulesFor = rulesFor
+}
+
+// restriction はプロジェクトのリソースルールを Monitoring のフィルタ条件にする（ルールがなければ空）
+func (c *Client) restriction(projectID string) string {
+	if c.rulesFor == nil {
+		return ""
+	}
+	var conds []string
+	for _, r := range c.rulesFor(projectID) {
+		if len(r.ResourceTypes) > 0 {
+			types := make([]string, len(r.ResourceTypes))
+			for i, t := range r.ResourceTypes {
+				types[i] = strconv.Quote(t)
+			}
+			conds = append(conds, "resource.type = one_of("+strings.Join(types, ", ")+")")
+		}
+		keys := make([]string, 0, len(r.DeniedLabels))
+		for k := range r.DeniedLabels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			conds = append(conds, "NOT "+k+" = "+strconv.Quote(r.DeniedLabels[k]))
+		}
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// labelPathPattern は filters のキーに使えるラベルのパス（値の比較だけを組み立てるため）
+var labelPathPattern = regexp.MustCompile(`^(metric\.labels|resource\.labels|metadata\.system_labels|metadata\.user_labels)\.[A-Za-z_][A-Za-z0-9_]*$`)
+
+// withRestriction は filter にプロジェクトのリソースルールの条件を付け足す
+// filter は括弧で囲み、OR や対応しない括弧でルールの条件が組み変わらないようにする
+func (c *Client) withRestriction(projectID, filter string) (string, string) {
+	restriction := c.restriction(projectID)
+	if restriction == "" {
+		return filter, ""
+	}
+	if filter == "" {
+		return restriction, restriction
+	}
+	return "(" + filter + ") AND " + restriction, restriction
+}
@@ -0,0 +1,191 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	"google.golang.org/api/iterator"
+)
+
+// ListServicesParams are the parameters for monitoring.list_services
+type ListServicesParams struct {
+	ProjectID string `json:"project_id"`
+	Filter    string `json:"filter"` // Optional filter (e.g., 'identifier_case="CLOUD_RUN"')
+	Limit     int    `json:"limit"`
+}
+
+// ListServicesResult is the result of monitoring.list_services
+type ListServicesResult struct {
+	QueryMeta ServicesQueryMeta `json:"query_meta"`
+	Services  []Service         `json:"services"`
+	Stats     GroupsStats       `json:"stats"`
+}
+
+type ServicesQueryMeta struct {
+	ProjectID string `json:"project_id"`
+	Filter    string `json:"filter,omitempty"`
+}
+
+type Service struct {
+	ID                    string            `json:"id"`
+	Name                  string            `json:"name"`
+	DisplayName           string            `json:"display_name,omitempty"`
+	Kind                  string            `json:"kind"`
+	Identifiers           map[string]string `json:"identifiers,omitempty"`
+	TelemetryResourceName string            `json:"telemetry_resource_name,omitempty"`
+	UserLabels            map[string]string `json:"user_labels,omitempty"`
+}
+
+// ListServices lists Service Monitoring services (custom and auto-detected)
+func (c *Client) ListServices(ctx context.Context, params ListServicesParams) (*ListServicesResult, error) {
+	// Set defaults
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	req := &monitoringpb.ListServicesRequest{
+		Parent: fmt.Sprintf("projects/%s", params.ProjectID),
+		Filter: params.Filter,
+	}
+
+	it := c.api.ListServices(ctx, req)
+
+	services := []Service{}
+	truncated := false
+
+	for {
+		svc, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate services: %w", err)
+		}
+
+		kind, identifiers := serviceIdentifiers(svc)
+		services = append(services, Service{
+			ID:                    groupID(svc.GetName()),
+			Name:                  svc.GetName(),
+			DisplayName:           svc.GetDisplayName(),
+			Kind:                  kind,
+			Identifiers:           identifiers,
+			TelemetryResourceName: svc.GetTelemetry().GetResourceName(),
+			UserLabels:            svc.GetUserLabels(),
+		})
+
+		if len(services) >= limit {
+			truncated = true
+			break
+		}
+	}
+
+	return &ListServicesResult{
+		QueryMeta: ServicesQueryMeta{
+			ProjectID: params.ProjectID,
+			Filter:    params.Filter,
+		},
+		Services: services,
+		Stats: GroupsStats{
+			ReturnedCount: len(services),
+			Truncated:     truncated,
+		},
+	}, nil
+}
+
+// serviceIdentifiers はサービスの種類と、テレメトリを特定するための識別子を返す
+func serviceIdentifiers(svc *monitoringpb.Service) (string, map[string]string) {
+	switch {
+	case svc.GetCloudRun() != nil:
+		s := svc.GetCloudRun()
+		return "cloud_run", map[string]string{
+			"service_name": s.GetServiceName(),
+			"location":     s.GetLocation(),
+		}
+	case svc.GetGkeWorkload() != nil:
+		s := svc.GetGkeWorkload()
+		return "gke_workload", map[string]string{
+			"project_id":                s.GetProjectId(),
+			"location":                  s.GetLocation(),
+			"cluster_name":              s.GetClusterName(),
+			"namespace_name":            s.GetNamespaceName(),
+			"top_level_controller_type": s.GetTopLevelControllerType(),
+			"top_level_controller_name": s.GetTopLevelControllerName(),
+		}
+	case svc.GetGkeService() != nil:
+		s := svc.GetGkeService()
+		return "gke_service", map[string]string{
+			"project_id":     s.GetProjectId(),
+			"location":       s.GetLocation(),
+			"cluster_name":   s.GetClusterName(),
+			"namespace_name": s.GetNamespaceName(),
+			"service_name":   s.GetServiceName(),
+		}
+	case svc.GetGkeNamespace() != nil:
+		s := svc.GetGkeNamespace()
+		return "gke_namespace", map[string]string{
+			"project_id":     s.GetProjectId(),
+			"location":       s.GetLocation(),
+			"cluster_name":   s.GetClusterName(),
+			"namespace_name": s.GetNamespaceName(),
+		}
+	case svc.GetIstioCanonicalService() != nil:
+		s := svc.GetIstioCanonicalService()
+		return "istio_canonical_service", map[string]string{
+			"mesh_uid":                    s.GetMeshUid(),
+			"canonical_service_namespace": s.GetCanonicalServiceNamespace(),
+			"canonical_service":           s.GetCanonicalService(),
+		}
+	case svc.GetMeshIstio() != nil:
+		s := svc.GetMeshIstio()
+		return "mesh_istio", map[string]string{
+			"mesh_uid":          s.GetMeshUid(),
+			"service_namespace": s.GetServiceNamespace(),
+			"service_name":      s.GetServiceName(),
+		}
+	case svc.GetClusterIstio() != nil:
+		s := svc.GetClusterIstio()
+		return "cluster_istio", map[string]string{
+			"location":          s.GetLocation(),
+			"cluster_name":      s.GetClusterName(),
+			"service_namespace": s.GetServiceNamespace(),
+			"service_name":      s.GetServiceName(),
+		}
+	case svc.GetAppEngine() != nil:
+		return "app_engine", map[string]string{
+			"module_id": svc.GetAppEngine().GetModuleId(),
+		}
+	case svc.GetCloudEndpoints() != nil:
+		return "cloud_endpoints", map[string]string{
+			"service": svc.GetCloudEndpoints().GetService(),
+		}
+	case svc.GetBasicService() != nil:
+		s := svc.GetBasicService()
+		identifiers := map[string]string{"service_type": s.GetServiceType()}
+		for k, v := range s.GetServiceLabels() {
+			identifiers[k] = v
+		}
+		return "basic_service", identifiers
+	case svc.GetCustom() != nil:
+		return "custom", nil
+	default:
+		return "unknown", nil
+	}
+}
+
+// ListServicesHandler returns a handler for the monitoring.list_services tool
+func (c *Client) ListServicesHandler() func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params ListServicesParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		return c.ListServices(ctx, params)
+	}
+}
@@ -0,0 +1,131 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// sloLookback は SLO の達成率・残りバジェットを読む区間（終了時刻の直前の最新のポイントを使う）
+const sloLookback = 10 * time.Minute
+
+// SLOStatus is an SLO of a Service Monitoring service with its compliance at a point in time
+type SLOStatus struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	DisplayName string   `json:"display_name,omitempty"`
+	Goal        float64  `json:"goal"`
+	Period      string   `json:"period"`                     // e.g. "rolling 28d", "calendar MONTH"
+	Compliance  *float64 `json:"compliance,omitempty"`       // Fraction of good service over the period
+	BudgetLeft  *float64 `json:"budget_remaining,omitempty"` // Fraction of the error budget left (negative = exhausted)
+	Error       string   `json:"error,omitempty"`
+}
+
+// FindService returns the full name of the Service Monitoring service whose ID, display name
+// or identifier (e.g. the Cloud Run service name) is name, or "" if there is none
+func (c *Client) FindService(ctx context.Context, projectID, name string) (string, error) {
+	it := c.api.ListServices(ctx, &monitoringpb.ListServicesRequest{Parent: fmt.Sprintf("projects/%s", projectID)})
+	for {
+		svc, err := it.Next()
+		if err == iterator.Done {
+			return "", nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to iterate services: %w", err)
+		}
+		if groupID(svc.GetName()) == name || svc.GetDisplayName() == name {
+			return svc.GetName(), nil
+		}
+		_, identifiers := serviceIdentifiers(svc)
+		for _, v := range identifiers {
+			if v == name {
+				return svc.GetName(), nil
+			}
+		}
+	}
+}
+
+// ServiceSLOs lists the SLOs of a service ("projects/X/services/ID") with their compliance
+// and remaining error budget as of end
+func (c *Client) ServiceSLOs(ctx context.Context, service string, end time.Time) ([]SLOStatus, error) {
+	it := c.api.ListServiceLevelObjectives(ctx, &monitoringpb.ListServiceLevelObjectivesRequest{Parent: service})
+	slos := []SLOStatus{}
+	for {
+		slo, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate service level objectives: %w", err)
+		}
+
+		status := SLOStatus{
+			ID:          groupID(slo.GetName()),
+			Name:        slo.GetName(),
+			DisplayName: slo.GetDisplayName(),
+			Goal:        slo.GetGoal(),
+			Period:      sloPeriod(slo),
+		}
+		// 片方が取れなくてももう片方は返す
+		if v, err := c.sloValue(ctx, service, "select_slo_compliance", slo.GetName(), end); err != nil {
+			status.Error = err.Error()
+		} else {
+			status.Compliance = v
+		}
+		if v, err := c.sloValue(ctx, service, "select_slo_budget_fraction", slo.GetName(), end); err != nil {
+			status.Error = err.Error()
+		} else {
+			status.BudgetLeft = v
+		}
+		slos = append(slos, status)
+	}
+	return slos, nil
+}
+
+// sloValue は SLO のセレクタ（select_slo_compliance など）の end 時点の値を返す（データがなければ nil）
+func (c *Client) sloValue(ctx context.Context, service, selector, sloName string, end time.Time) (*float64, error) {
+	project := service
+	if i := strings.Index(service, "/services/"); i >= 0 {
+		project = service[:i]
+	}
+	it := c.api.ListTimeSeries(ctx, &monitoringpb.ListTimeSeriesRequest{
+		Name:   project,
+		Filter: fmt.Sprintf(`%s("%s")`, selector, sloName),
+		Interval: &monitoringpb.TimeInterval{
+			StartTime: timestamppb.New(end.Add(-sloLookback)),
+			EndTime:   timestamppb.New(end),
+		},
+		Aggregation: &monitoringpb.Aggregation{
+			AlignmentPeriod:  durationpb.New(sloLookback),
+			PerSeriesAligner: monitoringpb.Aggregation_ALIGN_NEXT_OLDER,
+		},
+		View: monitoringpb.ListTimeSeriesRequest_FULL,
+	})
+	ts, err := it.Next()
+	if err == iterator.Done {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", selector, err)
+	}
+	if len(ts.GetPoints()) == 0 {
+		return nil, nil
+	}
+	// ポイントは新しい順
+	v := extractValue(ts.GetPoints()[0].GetValue())
+	return &v, nil
+}
+
+// sloPeriod は SLO の評価期間を "rolling 28d" / "calendar MONTH" の形で返す
+func sloPeriod(slo *monitoringpb.ServiceLevelObjective) string {
+	if d := slo.GetRollingPeriod(); d != nil {
+		return fmt.Sprintf("rolling %dd", int(d.AsDuration().Hours()/24))
+	}
+	return "calendar " + slo.GetCalendarPeriod().String()
+}
@@ -0,0 +1,320 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxSnoozeDuration はスヌーズ期間の上限（消し忘れ防止）
+const maxSnoozeDuration = 7 * 24 * time.Hour
+
+// CreateSnoozeParams are the parameters for monitoring.create_snooze
+type CreateSnoozeParams struct {
+	ProjectID       string   `json:"project_id"`
+	PolicyIDs       []string `json:"policy_ids"`       // Alert policy IDs or full resource names
+	DurationMinutes int      `json:"duration_minutes"` // From now
+	DisplayName     string   `json:"display_name"`
+	Reason          string   `json:"reason"`
+	ConfirmToken    string   `json:"confirm_token,omitempty"`
+}
+
+// DeleteSnoozeParams are the parameters for monitoring.delete_snooze
+type DeleteSnoozeParams struct {
+	ProjectID    string `json:"project_id"`
+	SnoozeID     string `json:"snooze_id"`
+	ConfirmToken string `json:"confirm_token,omitempty"`
+}
+
+// ListSnoozesParams are the parameters for monitoring.list_snoozes
+type ListSnoozesParams struct {
+	ProjectID  string `json:"project_id"`
+	ActiveOnly bool   `json:"active_only"`
+	Limit      int    `json:"limit"`
+}
+
+// SnoozeWriteResult is the result of monitoring.create_snooze / monitoring.delete_snooze
+// ConfirmToken が返った場合は未実行（プレビュー）。同じ引数に confirm_token を付けて再度呼ぶと実行される
+type SnoozeWriteResult struct {
+	Executed     bool   `json:"executed"`
+	Preview      string `json:"preview,omitempty"`
+	ConfirmToken string `json:"confirm_token,omitempty"`
+	Snooze       Snooze `json:"snooze"`
+}
+
+// ListSnoozesResult is the result of monitoring.list_snoozes
+type ListSnoozesResult struct {
+	QueryMeta GroupsQueryMeta `json:"query_meta"`
+	Snoozes   []Snooze        `json:"snoozes"`
+	Stats     GroupsStats     `json:"stats"`
+}
+
+type Snooze struct {
+	ID          string   `json:"id,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	DisplayName string   `json:"display_name"`
+	Policies    []string `json:"policies"`
+	Start       string   `json:"start"`
+	End         string   `json:"end"`
+	Active      bool     `json:"active"`
+}
+
+// WriteValidator は書き込みツール用のガードレール検証インターフェース
+type WriteValidator interface {
+	IssueConfirmToken(action string, payload any) (string, error)
+	VerifyConfirmToken(token, action string, payload any) error
+}
+
+// CreateSnooze creates a snooze for the given alert policies starting now
+func (c *Client) CreateSnooze(ctx context.Context, params CreateSnoozeParams, start time.Time) (*Snooze, error) {
+	end := start.Add(time.Duration(params.DurationMinutes) * time.Minute)
+	snooze, err := c.api.CreateSnooze(ctx, &monitoringpb.CreateSnoozeRequest{
+		Parent: fmt.Sprintf("projects/%s", params.ProjectID),
+		Snooze: &monitoringpb.Snooze{
+			DisplayName: params.DisplayName,
+			Criteria: &monitoringpb.Snooze_Criteria{
+				Policies: policyNames(params.ProjectID, params.PolicyIDs),
+			},
+			Interval: &monitoringpb.TimeInterval{
+				StartTime: timestamppb.New(start),
+				EndTime:   timestamppb.New(end),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snooze: %w", err)
+	}
+	s := toSnooze(snooze, time.Now())
+	return &s, nil
+}
+
+// EndSnooze ends a snooze immediately (the API has no delete; the interval is shortened to now)
+func (c *Client) EndSnooze(ctx context.Context, projectID, snoozeID string) (*Snooze, error) {
+	name := fmt.Sprintf("projects/%s/snoozes/%s", projectID, groupID(snoozeID))
+	current, err := c.api.GetSnooze(ctx, &monitoringpb.GetSnoozeRequest{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snooze: %w", err)
+	}
+
+	now := time.Now()
+	interval := &monitoringpb.TimeInterval{
+		StartTime: current.GetInterval().GetStartTime(),
+		EndTime:   timestamppb.New(now),
+	}
+	paths := []string{"interval.end_time"}
+	// 開始前のスヌーズは開始時刻も now にする
+	if current.GetInterval().GetStartTime().AsTime().After(now) {
+		interval.StartTime = timestamppb.New(now)
+		paths = append(paths, "interval.start_time")
+	}
+
+	updated, err := c.api.UpdateSnooze(ctx, &monitoringpb.UpdateSnoozeRequest{
+		Snooze: &monitoringpb.Snooze{
+			Name:     name,
+			Interval: interval,
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: paths},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to end snooze: %w", err)
+	}
+	s := toSnooze(updated, now)
+	return &s, nil
+}
+
+// ListSnoozes lists snoozes in a project
+func (c *Client) ListSnoozes(ctx context.Context, params ListSnoozesParams) (*ListSnoozesResult, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	req := &monitoringpb.ListSnoozesRequest{
+		Parent: fmt.Sprintf("projects/%s", params.ProjectID),
+	}
+	now := time.Now()
+	if params.ActiveOnly {
+		req.Filter = fmt.Sprintf(`interval.end_time > "%s"`, now.UTC().Format(time.RFC3339))
+	}
+
+	it := c.api.ListSnoozes(ctx, req)
+
+	snoozes := []Snooze{}
+	truncated := false
+	for {
+		s, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate snoozes: %w", err)
+		}
+		if len(snoozes) >= limit {
+			truncated = true
+			break
+		}
+		snoozes = append(snoozes, toSnooze(s, now))
+	}
+
+	return &ListSnoozesResult{
+		QueryMeta: GroupsQueryMeta{ProjectID: params.ProjectID},
+		Snoozes:   snoozes,
+		Stats: GroupsStats{
+			ReturnedCount: len(snoozes),
+			Truncated:     truncated,
+		},
+	}, nil
+}
+
+func toSnooze(s *monitoringpb.Snooze, now time.Time) Snooze {
+	start := s.GetInterval().GetStartTime().AsTime()
+	end := s.GetInterval().GetEndTime().AsTime()
+	return Snooze{
+		ID:          groupID(s.GetName()),
+		Name:        s.GetName(),
+		DisplayName: s.GetDisplayName(),
+		Policies:    s.GetCriteria().GetPolicies(),
+		Start:       start.Format(time.RFC3339),
+		End:         end.Format(time.RFC3339),
+		Active:      !now.Before(start) && now.Before(end),
+	}
+}
+
+// policyNames はアラートポリシーIDをリソース名に変換する
+func policyNames(projectID string, ids []string) []string {
+	names := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if strings.HasPrefix(id, "projects/") {
+			names = append(names, id)
+			continue
+		}
+		names = append(names, fmt.Sprintf("projects/%s/alertPolicies/%s", projectID, id))
+	}
+	return names
+}
+
+// CreateSnoozeHandlerWithGuardrail returns a handler with guardrail validation and confirmation
+func (c *Client) CreateSnoozeHandlerWithGuardrail(v WriteValidator) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params CreateSnoozeParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if len(params.PolicyIDs) == 0 {
+			return nil, fmt.Errorf("policy_ids is required")
+		}
+		if params.DurationMinutes <= 0 {
+			return nil, fmt.Errorf("duration_minutes must be positive")
+		}
+		if params.Reason == "" {
+			return nil, fmt.Errorf("reason is required")
+		}
+
+		// ガードレール: スヌーズ期間の上限
+		if time.Duration(params.DurationMinutes)*time.Minute > maxSnoozeDuration {
+			return nil, fmt.Errorf("duration_minutes %d exceeds maximum %d", params.DurationMinutes, int(maxSnoozeDuration.Minutes()))
+		}
+
+		if params.DisplayName == "" {
+			params.DisplayName = "gcp-ops-mcp: " + params.Reason
+		}
+
+		// 確認トークンは実行内容（トークン以外の引数）に紐づける
+		token := params.ConfirmToken
+		params.ConfirmToken = ""
+		now := time.Now()
+		preview := Snooze{
+			DisplayName: params.DisplayName,
+			Policies:    policyNames(params.ProjectID, params.PolicyIDs),
+			Start:       now.Format(time.RFC3339),
+			End:         now.Add(time.Duration(params.DurationMinutes) * time.Minute).Format(time.RFC3339),
+		}
+
+		// ガードレール: 確認トークンがなければプレビューのみ返す
+		if token == "" {
+			issued, err := v.IssueConfirmToken("monitoring.create_snooze", params)
+			if err != nil {
+				return nil, err
+			}
+			return &SnoozeWriteResult{
+				Executed:     false,
+				Preview:      fmt.Sprintf("Will snooze %d alert policies for %d minutes. Call again with the same arguments and confirm_token to execute.", len(params.PolicyIDs), params.DurationMinutes),
+				ConfirmToken: issued,
+				Snooze:       preview,
+			}, nil
+		}
+		if err := v.VerifyConfirmToken(token, "monitoring.create_snooze", params); err != nil {
+			return nil, err
+		}
+
+		snooze, err := c.CreateSnooze(ctx, params, now)
+		if err != nil {
+			return nil, err
+		}
+		return &SnoozeWriteResult{Executed: true, Snooze: *snooze}, nil
+	}
+}
+
+// DeleteSnoozeHandlerWithGuardrail returns a handler with guardrail validation and confirmation
+func (c *Client) DeleteSnoozeHandlerWithGuardrail(v WriteValidator) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params DeleteSnoozeParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.SnoozeID == "" {
+			return nil, fmt.Errorf("snooze_id is required")
+		}
+
+		token := params.ConfirmToken
+		params.ConfirmToken = ""
+		params.SnoozeID = groupID(params.SnoozeID)
+
+		// ガードレール: 確認トークンがなければプレビューのみ返す
+		if token == "" {
+			issued, err := v.IssueConfirmToken("monitoring.delete_snooze", params)
+			if err != nil {
+				return nil, err
+			}
+			return &SnoozeWriteResult{
+				Executed:     false,
+				Preview:      fmt.Sprintf("Will end snooze %s now. Call again with the same arguments and confirm_token to execute.", params.SnoozeID),
+				ConfirmToken: issued,
+				Snooze:       Snooze{ID: params.SnoozeID},
+			}, nil
+		}
+		if err := v.VerifyConfirmToken(token, "monitoring.delete_snooze", params); err != nil {
+			return nil, err
+		}
+
+		snooze, err := c.EndSnooze(ctx, params.ProjectID, params.SnoozeID)
+		if err != nil {
+			return nil, err
+		}
+		return &SnoozeWriteResult{Executed: true, Snooze: *snooze}, nil
+	}
+}
+
+// ListSnoozesHandler returns a handler for the monitoring.list_snoozes tool
+func (c *Client) ListSnoozesHandler() func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params ListSnoozesParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		return c.ListSnoozes(ctx, params)
+	}
+}
@@ -0,0 +1,118 @@
+package monitoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+)
+
+// sparkTicks はスパークラインに使う8段階の文字
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// SparklineResult is the result of monitoring.query_time_series with render="sparkline"
+type SparklineResult struct {
+	QueryMeta QueryMeta         `json:"query_meta"`
+	Series    []SparklineSeries `json:"series"`
+	Stats     ResultStats       `json:"stats"`
+}
+
+// SparklineSeries は1系列を1行に要約したもの
+type SparklineSeries struct {
+	Label string `json:"label"`
+	SeriesSummary
+	Sparkline string     `json:"sparkline"` // 古い → 新しい
+	Freshness *Freshness `json:"freshness,omitempty"`
+}
+
+// SparklineContent renders query_time_series results as sparkline text.
+// The structured content stays the full result.
+func SparklineContent(result *QueryTimeSeriesResult) (mcp.Structured, error) {
+	text, err := json.MarshalIndent(ToSparklines(result), "", "  ")
+	if err != nil {
+		return mcp.Structured{}, fmt.Errorf("failed to marshal sparklines: %w", err)
+	}
+	return mcp.Structured{Content: mcp.Text(string(text)), Result: result}, nil
+}
+
+// ToSparklines は各系列をラベル・要約統計・スパークラインの1行に要約する
+func ToSparklines(result *QueryTimeSeriesResult) *SparklineResult {
+	series := make([]SparklineSeries, 0, len(result.Series))
+	for _, ts := range result.Series {
+		// APIは新しい順にポイントを返すので古い順に並べ替える
+		values := make([]float64, len(ts.Points))
+		for i, p := range ts.Points {
+			values[len(ts.Points)-1-i] = p.Value
+		}
+
+		summary := Summarize(ts.Points)
+		if ts.Summary != nil {
+			summary = *ts.Summary
+		}
+		series = append(series, SparklineSeries{
+			Label:         SeriesLabel(ts),
+			SeriesSummary: summary,
+			Sparkline:     Sparkline(values),
+			Freshness:     ts.Freshness,
+		})
+	}
+
+	return &SparklineResult{
+		QueryMeta: result.QueryMeta,
+		Series:    series,
+		Stats:     result.Stats,
+	}
+}
+
+// Sparkline は値の列を ▁▂▃▄▅▆▇█ の文字列に変換する
+func Sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		idx := 0
+		if hi > lo {
+			idx = int((v - lo) / (hi - lo) * float64(len(sparkTicks)-1))
+		}
+		b.WriteRune(sparkTicks[idx])
+	}
+	return b.String()
+}
+
+// SeriesLabel は系列を識別するラベル文字列を返す（resource → metric ラベルの順、キー昇順）
+// time_shift の比較用の系列には " (-7d)" のように期間を付ける
+func SeriesLabel(ts TimeSeries) string {
+	label := seriesLabels(ts)
+	if ts.TimeShift != "" {
+		label += fmt.Sprintf(" (-%s)", ts.TimeShift)
+	}
+	return label
+}
+
+func seriesLabels(ts TimeSeries) string {
+	parts := []string{}
+	for _, labels := range []map[string]string{ts.Resource.Labels, ts.Metric.Labels} {
+		keys := make([]string, 0, len(labels))
+		for k := range labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			parts = append(parts, fmt.Sprintf("%s=%s", k, labels[k]))
+		}
+	}
+	if len(parts) == 0 {
+		return ts.Metric.Type
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,42 @@
+package monitoring
+
+import (
+	"math"
+	"sort"
+)
+
+// SeriesSummary holds summary statistics of one time series
+type SeriesSummary struct {
+	Count int     `json:"count"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Avg   float64 `json:"avg"`
+	P95   float64 `json:"p95"`
+	Last  float64 `json:"last"` // 最新のポイントの値
+}
+
+// Summarize はポイント列（API順 = 新しい順）の要約統計を計算する
+func Summarize(points []DataPoint) SeriesSummary {
+	s := SeriesSummary{Count: len(points)}
+	if len(points) == 0 {
+		return s
+	}
+
+	values := make([]float64, len(points))
+	sum := 0.0
+	s.Min, s.Max = points[0].Value, points[0].Value
+	for i, p := range points {
+		values[i] = p.Value
+		sum += p.Value
+		s.Min = math.Min(s.Min, p.Value)
+		s.Max = math.Max(s.Max, p.Value)
+	}
+	s.Avg = sum / float64(len(points))
+	s.Last = points[0].Value
+
+	// p95 は nearest-rank 法
+	sort.Float64s(values)
+	rank := int(math.Ceil(0.95*float64(len(values)))) - 1
+	s.P95 = values[max(rank, 0)]
+	return s
+}
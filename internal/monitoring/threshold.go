@@ -0,0 +1,251 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Comparisons は閾値評価で使える比較（アラートポリシーの comparison と同じ名前）
+var Comparisons = []string{"COMPARISON_GT", "COMPARISON_GE", "COMPARISON_LT", "COMPARISON_LE", "COMPARISON_EQ", "COMPARISON_NE"}
+
+// EvaluateThresholdParams are the parameters for monitoring.evaluate_threshold
+type EvaluateThresholdParams struct {
+	QueryTimeSeriesParams
+	Comparison  string   `json:"comparison"` // "COMPARISON_GT", "GT" or ">"
+	Threshold   *float64 `json:"threshold"`
+	DurationSec int      `json:"duration_sec"` // How long the condition must hold (0 = a single point is enough)
+}
+
+// EvaluateThresholdResult is the result of monitoring.evaluate_threshold
+type EvaluateThresholdResult struct {
+	QueryMeta ThresholdQueryMeta `json:"query_meta"`
+	Series    []ThresholdSeries  `json:"series"`
+	Stats     ThresholdStats     `json:"stats"`
+}
+
+type ThresholdQueryMeta struct {
+	QueryMeta
+	Condition string `json:"condition"` // e.g. "value > 0.9 for 300s"
+}
+
+// ThresholdSeries is the evaluation result of one series
+type ThresholdSeries struct {
+	Label         string              `json:"label"`
+	Metric        MetricLabels        `json:"metric"`
+	Resource      ResourceLabels      `json:"resource"`
+	Intervals     []ThresholdInterval `json:"intervals"`
+	MatchedPoints int                 `json:"matched_points"` // Points that met the comparison (regardless of duration)
+	TotalPoints   int                 `json:"total_points"`
+}
+
+// ThresholdInterval is a run of consecutive points that met the comparison for at least the duration
+type ThresholdInterval struct {
+	Start       string  `json:"start"`    // First point of the run
+	End         string  `json:"end"`      // Last point of the run
+	FiredAt     string  `json:"fired_at"` // When the condition had held for duration_sec
+	DurationSec int64   `json:"duration_sec"`
+	Peak        float64 `json:"peak"` // Most extreme value in the direction of the comparison
+}
+
+type ThresholdStats struct {
+	SeriesCount   int `json:"series_count"`
+	FiringSeries  int `json:"firing_series"` // Series with at least one interval
+	IntervalCount int `json:"interval_count"`
+}
+
+// ParseComparison は "COMPARISON_GT" / "GT" / ">" 形式の比較を "COMPARISON_GT" 形式にする
+func ParseComparison(s string) (string, error) {
+	symbols := map[string]string{">": "GT", ">=": "GE", "<": "LT", "<=": "LE", "==": "EQ", "=": "EQ", "!=": "NE"}
+	name := strings.ToUpper(strings.TrimSpace(s))
+	if sym, ok := symbols[name]; ok {
+		name = sym
+	}
+	if !strings.HasPrefix(name, "COMPARISON_") {
+		name = "COMPARISON_" + name
+	}
+	for _, c := range Comparisons {
+		if c == name {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("unknown comparison: %s (supported: GT, GE, LT, LE, EQ, NE)", s)
+}
+
+// compare は comparison（COMPARISON_*）で value と threshold を比べる
+func compare(comparison string, value, threshold float64) bool {
+	switch comparison {
+	case "COMPARISON_GT":
+		return value > threshold
+	case "COMPARISON_GE":
+		return value >= threshold
+	case "COMPARISON_LT":
+		return value < threshold
+	case "COMPARISON_LE":
+		return value <= threshold
+	case "COMPARISON_EQ":
+		return value == threshold
+	case "COMPARISON_NE":
+		return value != threshold
+	}
+	return false
+}
+
+// comparisonSymbol は条件の表示に使う記号
+func comparisonSymbol(comparison string) string {
+	return map[string]string{
+		"COMPARISON_GT": ">", "COMPARISON_GE": ">=", "COMPARISON_LT": "<",
+		"COMPARISON_LE": "<=", "COMPARISON_EQ": "==", "COMPARISON_NE": "!=",
+	}[comparison]
+}
+
+// EvaluateSeries は API 順（新しい順）のポイントについて、条件を満たす点が duration 以上続いた区間を返す
+// 各ポイントはアライメント期間 period を代表するので、n 点の連続は n×period 続いたとみなす
+// ポイントの間が period の2倍を超えて空いた場合は連続が途切れたものとする
+func EvaluateSeries(points []DataPoint, comparison string, threshold float64, duration, period time.Duration) ([]ThresholdInterval, int) {
+	intervals := []ThresholdInterval{}
+	matched := 0
+
+	var runStart, runEnd time.Time
+	var peak float64
+	inRun := false
+	closeRun := func() {
+		if !inRun {
+			return
+		}
+		inRun = false
+		held := runEnd.Sub(runStart) + period
+		if held < duration {
+			return
+		}
+		fired := runStart.Add(max(duration-period, 0))
+		intervals = append(intervals, ThresholdInterval{
+			Start:       runStart.Format(time.RFC3339),
+			End:         runEnd.Format(time.RFC3339),
+			FiredAt:     fired.Format(time.RFC3339),
+			DurationSec: int64(held / time.Second),
+			Peak:        peak,
+		})
+	}
+
+	// 古い順に走査する
+	for i := len(points) - 1; i >= 0; i-- {
+		t, err := time.Parse(time.RFC3339, points[i].Time)
+		if err != nil {
+			continue
+		}
+		v := points[i].Value
+		if !compare(comparison, v, threshold) {
+			closeRun()
+			continue
+		}
+		matched++
+
+		if inRun && period > 0 && t.Sub(runEnd) > 2*period {
+			closeRun()
+		}
+		if !inRun {
+			inRun, runStart, peak = true, t, v
+		}
+		runEnd = t
+		switch comparison {
+		case "COMPARISON_LT", "COMPARISON_LE":
+			peak = min(peak, v)
+		default:
+			peak = max(peak, v)
+		}
+	}
+	closeRun()
+	return intervals, matched
+}
+
+// evaluationPeriod は各ポイントが代表する期間（二次集約の期間の方が長ければそちら）
+func evaluationPeriod(alignmentPeriodSec int, secondary *Aggregation) time.Duration {
+	if alignmentPeriodSec <= 0 {
+		alignmentPeriodSec = 60
+	}
+	if secondary != nil && secondary.AlignmentPeriodSec > alignmentPeriodSec {
+		alignmentPeriodSec = secondary.AlignmentPeriodSec
+	}
+	return time.Duration(alignmentPeriodSec) * time.Second
+}
+
+// EvaluateThreshold queries the series and returns, per series, the intervals where the condition held
+func (c *Client) EvaluateThreshold(ctx context.Context, params EvaluateThresholdParams) (*EvaluateThresholdResult, error) {
+	comparison, err := ParseComparison(params.Comparison)
+	if err != nil {
+		return nil, err
+	}
+	if params.Threshold == nil {
+		return nil, fmt.Errorf("threshold is required")
+	}
+	threshold := *params.Threshold
+	if params.DurationSec < 0 {
+		return nil, fmt.Errorf("duration_sec must not be negative")
+	}
+
+	// 評価には全ポイントが必要なので、ダウンサンプリングや表示用の指定は無視する
+	query := params.QueryTimeSeriesParams
+	query.MaxPointsPerSeries = 0
+	query.StatsOnly = false
+	query.SeriesOnly = false
+	query.TimeShift = ""
+	query.Render = ""
+
+	result, err := c.QueryTimeSeries(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	period := evaluationPeriod(query.AlignmentPeriodSec, query.SecondaryAggregation)
+	duration := time.Duration(params.DurationSec) * time.Second
+
+	out := &EvaluateThresholdResult{
+		QueryMeta: ThresholdQueryMeta{
+			QueryMeta: result.QueryMeta,
+			Condition: fmt.Sprintf("value %s %g for %ds", comparisonSymbol(comparison), threshold, params.DurationSec),
+		},
+		Series: []ThresholdSeries{},
+	}
+	for _, ts := range result.Series {
+		intervals, matched := EvaluateSeries(ts.Points, comparison, threshold, duration, period)
+		out.Series = append(out.Series, ThresholdSeries{
+			Label:         SeriesLabel(ts),
+			Metric:        ts.Metric,
+			Resource:      ts.Resource,
+			Intervals:     intervals,
+			MatchedPoints: matched,
+			TotalPoints:   len(ts.Points),
+		})
+		if len(intervals) > 0 {
+			out.Stats.FiringSeries++
+		}
+		out.Stats.IntervalCount += len(intervals)
+	}
+	out.Stats.SeriesCount = len(out.Series)
+	return out, nil
+}
+
+// EvaluateThresholdHandlerWithGuardrail returns a handler for the monitoring.evaluate_threshold tool
+func (c *Client) EvaluateThresholdHandlerWithGuardrail(v Validator) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params EvaluateThresholdParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.MetricType == "" {
+			return nil, fmt.Errorf("metric_type is required")
+		}
+		if params.Comparison == "" {
+			return nil, fmt.Errorf("comparison is required")
+		}
+
+		// ガードレール: 系列数制限
+		params.MaxSeries = v.ClampTimeSeriesLimit(ctx, params.MaxSeries)
+
+		return c.EvaluateThreshold(ctx, params)
+	}
+}
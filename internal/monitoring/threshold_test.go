@@ -0,0 +1,134 @@
+package monitoring
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var thresholdStart = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// minutePoints は「開始からの分数と値」の組を API 順（新しい順）のポイントにする
+func minutePoints(pairs ...float64) []DataPoint {
+	points := make([]DataPoint, 0, len(pairs)/2)
+	for i := len(pairs) - 2; i >= 0; i -= 2 {
+		points = append(points, DataPoint{Time: atMinute(pairs[i]), Value: pairs[i+1]})
+	}
+	return points
+}
+
+func atMinute(m float64) string {
+	return thresholdStart.Add(time.Duration(m * float64(time.Minute))).Format(time.RFC3339)
+}
+
+func TestEvaluateSeries(t *testing.T) {
+	tests := []struct {
+		name       string
+		points     []DataPoint
+		comparison string
+		duration   time.Duration
+		want       []ThresholdInterval
+		matched    int
+	}{
+		{
+			name:       "run held for the duration",
+			points:     minutePoints(0, 1, 1, 5, 2, 7, 3, 6, 4, 1),
+			comparison: "COMPARISON_GT",
+			duration:   3 * time.Minute,
+			// 3点で 3×period 続いたとみなし、最初の点から duration−period 後に発火する
+			want:    []ThresholdInterval{{Start: atMinute(1), End: atMinute(3), FiredAt: atMinute(3), DurationSec: 180, Peak: 7}},
+			matched: 3,
+		},
+		{
+			name:       "run shorter than the duration",
+			points:     minutePoints(0, 5, 1, 5, 2, 1),
+			comparison: "COMPARISON_GT",
+			duration:   3 * time.Minute,
+			want:       []ThresholdInterval{},
+			matched:    2,
+		},
+		{
+			// 2×period を超える空きで連続は途切れ、それぞれの区間で duration を判定する
+			name:       "run broken by a gap",
+			points:     minutePoints(0, 5, 1, 6, 2, 5, 6, 9, 7, 8),
+			comparison: "COMPARISON_GT",
+			duration:   2 * time.Minute,
+			want: []ThresholdInterval{
+				{Start: atMinute(0), End: atMinute(2), FiredAt: atMinute(1), DurationSec: 180, Peak: 6},
+				{Start: atMinute(6), End: atMinute(7), FiredAt: atMinute(7), DurationSec: 120, Peak: 9},
+			},
+			matched: 5,
+		},
+		{
+			name:       "gap after which the rest is too short",
+			points:     minutePoints(0, 5, 1, 6, 5, 9),
+			comparison: "COMPARISON_GT",
+			duration:   2 * time.Minute,
+			want:       []ThresholdInterval{{Start: atMinute(0), End: atMinute(1), FiredAt: atMinute(1), DurationSec: 120, Peak: 6}},
+			matched:    3,
+		},
+		{
+			// 1点欠けた程度（ちょうど 2×period）の空きは連続とみなす
+			name:       "missing point within 2 periods",
+			points:     minutePoints(0, 5, 2, 6, 3, 5),
+			comparison: "COMPARISON_GT",
+			duration:   4 * time.Minute,
+			want:       []ThresholdInterval{{Start: atMinute(0), End: atMinute(3), FiredAt: atMinute(3), DurationSec: 240, Peak: 6}},
+			matched:    3,
+		},
+		{
+			// 2.5 分は3点（3 分）続いて初めて満たし、発火は最初の点から 1.5 分後
+			name:       "duration not a multiple of the period",
+			points:     minutePoints(0, 5, 1, 5, 2, 5, 3, 1),
+			comparison: "COMPARISON_GT",
+			duration:   150 * time.Second,
+			want:       []ThresholdInterval{{Start: atMinute(0), End: atMinute(2), FiredAt: atMinute(1.5), DurationSec: 180, Peak: 5}},
+			matched:    3,
+		},
+		{
+			name:       "duration not a multiple of the period, too short",
+			points:     minutePoints(0, 5, 1, 5, 2, 1),
+			comparison: "COMPARISON_GT",
+			duration:   150 * time.Second,
+			want:       []ThresholdInterval{},
+			matched:    2,
+		},
+		{
+			// 期間の終わりでまだ続いている区間も、最後の点までとして返す
+			name:       "run still open at the end of the window",
+			points:     minutePoints(0, 1, 1, 1, 2, 5, 3, 8, 4, 6),
+			comparison: "COMPARISON_GT",
+			duration:   2 * time.Minute,
+			want:       []ThresholdInterval{{Start: atMinute(2), End: atMinute(4), FiredAt: atMinute(3), DurationSec: 180, Peak: 8}},
+			matched:    3,
+		},
+		{
+			name:       "zero duration fires at the first point",
+			points:     minutePoints(0, 5, 1, 1),
+			comparison: "COMPARISON_GE",
+			want:       []ThresholdInterval{{Start: atMinute(0), End: atMinute(0), FiredAt: atMinute(0), DurationSec: 60, Peak: 5}},
+			matched:    1,
+		},
+		{
+			// 下回る条件では最も低い値を peak にする
+			name:       "peak of a below-threshold condition",
+			points:     minutePoints(0, 3, 1, 0.5, 2, 2, 3, 9),
+			comparison: "COMPARISON_LT",
+			duration:   2 * time.Minute,
+			want:       []ThresholdInterval{{Start: atMinute(0), End: atMinute(2), FiredAt: atMinute(1), DurationSec: 180, Peak: 0.5}},
+			matched:    3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 閾値はどのケースも 4、ポイントの間隔（period）は1分
+			got, matched := EvaluateSeries(tt.points, tt.comparison, 4, tt.duration, time.Minute)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("intervals = %+v\nwant %+v", got, tt.want)
+			}
+			if matched != tt.matched {
+				t.Errorf("matched = %d, want %d", matched, tt.matched)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/telemetry"
+)
+
+// UnitInfo describes the unit of returned values and how to display them.
+// display value = value * scale (when scale is set)
+type UnitInfo struct {
+	Unit        string  `json:"unit"`                   // UCUM unit after alignment (e.g. "s", "By/s", "1")
+	ValueType   string  `json:"value_type,omitempty"`   // Descriptor value type (INT64, DOUBLE, DISTRIBUTION, ...)
+	Kind        string  `json:"kind"`                   // "bytes", "bytes_rate", "duration", "ratio", "percent", "count", "rate", "other"
+	DisplayUnit string  `json:"display_unit,omitempty"` // e.g. "MiB", "ms", "%"
+	Scale       float64 `json:"scale,omitempty"`
+	Note        string  `json:"note"`
+}
+
+// unitAnnotation は UCUM の {annotation} 部分（例: "s{CPU}", "{request}"）
+var unitAnnotation = regexp.MustCompile(`\{[^}]*\}`)
+
+// bytesScale はバイト系単位を MiB に換算する倍率
+var bytesScale = map[string]float64{
+	"By":   1.0 / (1 << 20),
+	"kBy":  1e3 / (1 << 20),
+	"KiBy": 1.0 / (1 << 10),
+	"MBy":  1e6 / (1 << 20),
+	"MiBy": 1,
+	"GBy":  1e9 / (1 << 20),
+	"GiBy": 1 << 10,
+	"TiBy": 1 << 20,
+}
+
+// durationScale は時間系単位を ms に換算する倍率
+var durationScale = map[string]float64{
+	"ns":  1e-6,
+	"us":  1e-3,
+	"ms":  1,
+	"s":   1e3,
+	"min": 60e3,
+	"h":   3600e3,
+	"d":   86400e3,
+}
+
+// alignedUnit はアライナ・リデューサ適用後の単位と値型を返す（レートは "/s"、件数・割合は "1"）
+// 平均化で INT64 が小数になっても件数は件数のままなので、値型はディスクリプタのものを基本とする
+func alignedUnit(unit, valueType, aligner, reducer string) (string, string) {
+	switch strings.TrimPrefix(strings.ToUpper(aligner), "ALIGN_") {
+	case "RATE":
+		if unit == "" || unit == "1" {
+			unit = "1"
+		}
+		unit += "/s"
+	case "COUNT", "COUNT_TRUE", "COUNT_FALSE":
+		unit, valueType = "1", "INT64"
+	case "FRACTION_TRUE":
+		unit, valueType = "1", "DOUBLE"
+	case "PERCENT_CHANGE":
+		unit, valueType = "%", "DOUBLE"
+	}
+	switch strings.TrimPrefix(strings.ToUpper(reducer), "REDUCE_") {
+	case "COUNT", "COUNT_TRUE", "COUNT_FALSE":
+		unit, valueType = "1", "INT64"
+	case "FRACTION_TRUE":
+		unit, valueType = "1", "DOUBLE"
+	}
+	return unit, valueType
+}
+
+// DescribeUnit はメトリクスの単位・値型から表示用の単位情報を組み立てる
+func DescribeUnit(unit, valueType string) *UnitInfo {
+	info := &UnitInfo{Unit: unit, ValueType: valueType, Kind: "other"}
+	base := unitAnnotation.ReplaceAllString(unit, "")
+
+	switch {
+	case base == "%" || base == "10^2.%":
+		info.Kind = "percent"
+		info.DisplayUnit = "%"
+		info.Note = "values are already percentages (0-100)"
+	case (base == "1" || base == "") && valueType == "DOUBLE":
+		info.Kind = "ratio"
+		info.DisplayUnit = "%"
+		info.Scale = 100
+		info.Note = "values are ratios (0-1); multiply by 100 for percent"
+	case base == "1" || base == "":
+		info.Kind = "count"
+		info.Note = "values are counts"
+	case strings.HasSuffix(base, "/s") && bytesScale[strings.TrimSuffix(base, "/s")] != 0:
+		info.Kind = "bytes_rate"
+		info.DisplayUnit = "MiB/s"
+		info.Scale = bytesScale[strings.TrimSuffix(base, "/s")]
+		info.Note = fmt.Sprintf("values are in %s; multiply by scale for MiB/s", unit)
+	case bytesScale[base] != 0:
+		info.Kind = "bytes"
+		info.DisplayUnit = "MiB"
+		info.Scale = bytesScale[base]
+		info.Note = fmt.Sprintf("values are in %s; multiply by scale for MiB", unit)
+	case durationScale[base] != 0:
+		info.Kind = "duration"
+		info.DisplayUnit = "ms"
+		info.Scale = durationScale[base]
+		info.Note = fmt.Sprintf("values are in %s; multiply by scale for milliseconds", unit)
+	case base == "s/s":
+		info.Kind = "ratio"
+		info.Note = "values are seconds per second (e.g. CPU cores in use); 1 = one core fully busy"
+	case strings.HasSuffix(base, "/s"):
+		info.Kind = "rate"
+		info.DisplayUnit = "/s"
+		info.Note = "values are per-second rates"
+	default:
+		info.Note = fmt.Sprintf("values are in %s", unit)
+	}
+	return info
+}
+
+// metricInfo はメトリクスディスクリプタのうち単位・値型・種類（GAUGE / DELTA / CUMULATIVE）
+type metricInfo struct {
+	unit      string
+	valueType string
+	kind      string
+}
+
+// metricInfo はメトリクスディスクリプタの単位・値型・種類を返す（プロセス内でキャッシュ）
+func (c *Client) metricInfo(ctx context.Context, projectID, metricType string) (metricInfo, error) {
+	key := projectID + "/" + metricType
+	c.unitMu.Lock()
+	cached, ok := c.units[key]
+	c.unitMu.Unlock()
+	telemetry.RecordCacheLookup(ctx, "metric_unit", ok)
+	if ok {
+		return cached, nil
+	}
+
+	desc, err := c.api.GetMetricDescriptor(ctx, &monitoringpb.GetMetricDescriptorRequest{
+		Name: fmt.Sprintf("projects/%s/metricDescriptors/%s", projectID, metricType),
+	})
+	if err != nil {
+		return metricInfo{}, fmt.Errorf("failed to get metric descriptor: %w", err)
+	}
+	info := metricInfo{
+		unit:      desc.GetUnit(),
+		valueType: strings.TrimPrefix(desc.GetValueType().String(), "VALUE_TYPE_UNSPECIFIED"),
+		kind:      strings.TrimPrefix(desc.GetMetricKind().String(), "METRIC_KIND_UNSPECIFIED"),
+	}
+
+	c.unitMu.Lock()
+	if c.units == nil {
+		c.units = map[string]metricInfo{}
+	}
+	c.units[key] = info
+	c.unitMu.Unlock()
+	return info, nil
+}
+
+// annotateUnit は結果に単位情報を付与する（ディスクリプタが取れない場合は付与しない）
+func (c *Client) annotateUnit(ctx context.Context, params QueryTimeSeriesParams, result *QueryTimeSeriesResult) {
+	metric, err := c.metricInfo(ctx, params.ProjectID, params.MetricType)
+	if err != nil {
+		return
+	}
+	unit, valueType := alignedUnit(metric.unit, metric.valueType, result.QueryMeta.PerSeriesAligner, params.CrossSeriesReducer)
+	info := DescribeUnit(unit, valueType)
+	result.QueryMeta.Unit = info
+
+	if !params.Normalize || info.Scale == 0 {
+		return
+	}
+	for i := range result.Series {
+		for j := range result.Series[i].Points {
+			v := result.Series[i].Points[j].Value * info.Scale
+			result.Series[i].Points[j].Normalized = &v
+		}
+	}
+}
+
+// alignerOrDefault は buildAggregation と同じデフォルトアライナを補う
+func alignerOrDefault(aligner string) string {
+	if aligner == "" {
+		return "ALIGN_MEAN"
+	}
+	return aligner
+}
+
+// counterAligner はカウンタ（CUMULATIVE / DELTA）のメトリクスに合ったアライナを選ぶ
+// 省略時は ALIGN_MEAN の代わりに ALIGN_RATE を使い、その旨を警告として返す
+// 累積値の平均は単調に増えるだけで意味がないため、明示された ALIGN_MEAN も警告する
+// ディスクリプタが取れない場合や数値でないメトリクスでは何もしない
+func (c *Client) counterAligner(ctx context.Context, params QueryTimeSeriesParams) (string, []string) {
+	aligner := alignerOrDefault(params.PerSeriesAligner)
+	mean := strings.TrimPrefix(strings.ToUpper(aligner), "ALIGN_") == "MEAN"
+	if !mean {
+		return aligner, nil
+	}
+	metric, err := c.metricInfo(ctx, params.ProjectID, params.MetricType)
+	if err != nil || (metric.kind != "CUMULATIVE" && metric.kind != "DELTA") {
+		return aligner, nil
+	}
+	if metric.valueType != "INT64" && metric.valueType != "DOUBLE" {
+		return aligner, nil
+	}
+
+	if params.PerSeriesAligner == "" {
+		return "ALIGN_RATE", []string{fmt.Sprintf(
+			"%s is a %s counter: per_series_aligner defaulted to ALIGN_RATE (per-second rate) instead of ALIGN_MEAN; set per_series_aligner to override (e.g. ALIGN_DELTA for counts per period)",
+			params.MetricType, metric.kind)}
+	}
+	if metric.kind == "CUMULATIVE" {
+		return aligner, []string{fmt.Sprintf(
+			"%s is a CUMULATIVE counter: ALIGN_MEAN averages the running total, which only ever grows; use ALIGN_RATE or ALIGN_DELTA",
+			params.MetricType)}
+	}
+	return aligner, nil
+}
@@ -0,0 +1,151 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+)
+
+const (
+	// sendTimeout は1件の送信の上限
+	sendTimeout = 10 * time.Second
+	// repeatWindow は同じキーの通知を送り直さない期間（SendOnce）
+	repeatWindow = 10 * time.Minute
+)
+
+// Event is one notification
+type Event struct {
+	Kind  string `json:"kind"`  // config.NotifyWatch or config.NotifyGuardrail
+	Level string `json:"level"` // notice, warning or error
+	Title string `json:"title"`
+	Text  string `json:"text"`
+	Time  string `json:"time"`
+	Data  any    `json:"data,omitempty"` // Kind-specific details (e.g. the watch event)
+}
+
+// sink は URL を解決済みの通知先
+type sink struct {
+	config.NotificationSink
+	url string
+}
+
+// Notifier は設定の通知先（Slack・汎用 HTTP）にイベントを送る
+// 送信はバックグラウンドで行い、失敗はログに残すだけ（ツール呼び出しや監視を止めない）
+type Notifier struct {
+	ctx        context.Context
+	sinks      []sink
+	httpClient *http.Client
+
+	mu   sync.Mutex
+	sent map[string]time.Time // SendOnce のキー → 最後に送った時刻
+}
+
+// New resolves the sinks' URLs (url_env must be set) and returns a notifier that stops sending when ctx is done
+func New(ctx context.Context, cfg config.Notifications) (*Notifier, error) {
+	n := &Notifier{ctx: ctx, httpClient: &http.Client{Timeout: sendTimeout}, sent: map[string]time.Time{}}
+	for _, s := range cfg.Sinks {
+		url := s.URL
+		if s.URLEnv != "" {
+			url = os.Getenv(s.URLEnv)
+			if url == "" {
+				return nil, fmt.Errorf("notification sink %q: environment variable %s is not set", s.Name, s.URLEnv)
+			}
+		}
+		n.sinks = append(n.sinks, sink{NotificationSink: s, url: url})
+	}
+	return n, nil
+}
+
+// Wants reports whether any sink receives events of kind
+func (n *Notifier) Wants(kind string) bool {
+	for _, s := range n.sinks {
+		if s.Wants(kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// Send sends e to every sink that receives its kind
+func (n *Notifier) Send(e Event) {
+	if e.Time == "" {
+		e.Time = time.Now().UTC().Format(time.RFC3339)
+	}
+	for _, s := range n.sinks {
+		if !s.Wants(e.Kind) {
+			continue
+		}
+		go n.post(s, e)
+	}
+}
+
+// SendOnce sends e unless an event with the same key was sent within the last 10 minutes
+// (e.g. a client retrying a call the guardrail rejects)
+func (n *Notifier) SendOnce(key string, e Event) {
+	now := time.Now()
+	n.mu.Lock()
+	if last, ok := n.sent[key]; ok && now.Sub(last) < repeatWindow {
+		n.mu.Unlock()
+		return
+	}
+	n.sent[key] = now
+	for k, t := range n.sent {
+		if now.Sub(t) >= repeatWindow {
+			delete(n.sent, k)
+		}
+	}
+	n.mu.Unlock()
+	n.Send(e)
+}
+
+// post はイベントを通知先の形式で POST する
+func (n *Notifier) post(s sink, e Event) {
+	var payload any = e
+	if s.Type == "slack" {
+		payload = map[string]string{"text": slackText(e)}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		slog.Warn("failed to encode notification", "sink", s.Name, "error", err)
+		return
+	}
+	req, err := http.NewRequestWithContext(n.ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		slog.Warn("failed to create notification request", "sink", s.Name, "error", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		// URL はトークンを含むことが多いのでログに出さない
+		slog.Warn("failed to send notification", "sink", s.Name, "kind", e.Kind, "error", redactURL(err, s.url))
+		return
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		slog.Warn("notification sink returned an error", "sink", s.Name, "kind", e.Kind, "status", resp.Status)
+	}
+}
+
+// slackText は Slack の mrkdwn のメッセージ（1行目にレベルとタイトル）
+func slackText(e Event) string {
+	icon := map[string]string{"warning": ":warning:", "error": ":x:"}[e.Level]
+	if icon == "" {
+		icon = ":information_source:"
+	}
+	return fmt.Sprintf("%s *%s*\n%s", icon, e.Title, e.Text)
+}
+
+// redactURL はエラーメッセージ中の URL を伏せる（net/http のエラーは URL を含む）
+func redactURL(err error, url string) string {
+	return strings.ReplaceAll(err.Error(), url, "(sink url)")
+}
@@ -0,0 +1,220 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+)
+
+// APIGatewayOverviewParams are the parameters for ops.api_gateway_overview
+type APIGatewayOverviewParams struct {
+	ProjectID          string               `json:"project_id"`
+	Platform           string               `json:"platform"`      // "api_gateway" (default) or "apigee"
+	API                string               `json:"api,omitempty"` // Managed service name (API Gateway) or proxy name (Apigee)
+	Location           string               `json:"location,omitempty"`
+	TimeRange          monitoring.TimeRange `json:"time_range"`
+	AlignmentPeriodSec int                  `json:"alignment_period_sec"`
+	MaxSeries          int                  `json:"max_series"` // Per signal
+	Limit              int                  `json:"limit"`      // Max failing request logs
+}
+
+// APIGatewayOverviewResult is the result of ops.api_gateway_overview
+type APIGatewayOverviewResult struct {
+	QueryMeta   APIGatewayQueryMeta `json:"query_meta"`
+	Configs     []APIConfigSummary  `json:"configs"` // Most errors first
+	Signals     []Signal            `json:"signals"`
+	FailingLogs []logging.LogEntry  `json:"failing_logs"`
+	Errors      map[string]string   `json:"errors,omitempty"` // section -> error
+}
+
+type APIGatewayQueryMeta struct {
+	OverviewQueryMeta
+	Platform string `json:"platform"`
+}
+
+// APIConfigSummary は API 構成（API Gateway はサービスと構成 ID、Apigee は環境とプロキシ）ごとの要約
+type APIConfigSummary struct {
+	API             string           `json:"api"`
+	Config          string           `json:"config"` // API config ID (API Gateway) or environment (Apigee)
+	Requests        int64            `json:"requests"`
+	ByClass         map[string]int64 `json:"by_class"`                    // e.g. "2xx", "4xx", "5xx"
+	ErrorRate       *float64         `json:"error_rate,omitempty"`        // 5xx / requests
+	ClientErrorRate *float64         `json:"client_error_rate,omitempty"` // 4xx / requests
+	PeakLatencyP99  *float64         `json:"peak_latency_p99_ms,omitempty"`
+}
+
+// apiPlatform は API 基盤ごとのメトリクスの読み方
+// メトリクスとログの定義は同名のリソース種別（resource_kinds）にあり、name_label が API を照合する
+type apiPlatform struct {
+	configLabel string // 構成を表すリソースラベル
+	codeLabel   string // レスポンスコード（またはそのクラス）のメトリクスラベル
+	latencyToMs float64
+	logByAPI    bool // ログのリソースも API のラベルを持つ（持たなければ location だけで絞る）
+}
+
+// apiPlatforms は API 基盤 → 読み方のレジストリ
+var apiPlatforms = map[string]apiPlatform{
+	// API Gateway（と Cloud Endpoints）はマネージドサービスとして serviceruntime のメトリクスを書く
+	"api_gateway": {
+		configLabel: "version",
+		codeLabel:   "response_code_class",
+		latencyToMs: 1000,
+		logByAPI:    true,
+	},
+	// Apigee のリクエストのログは MessageLogging ポリシーで書き出した場合のみ
+	"apigee": {
+		configLabel: "env",
+		codeLabel:   "response_code",
+		latencyToMs: 1,
+	},
+}
+
+// APIPlatforms はサポートする API 基盤を返す
+func APIPlatforms() []string {
+	platforms := make([]string, 0, len(apiPlatforms))
+	for p := range apiPlatforms {
+		platforms = append(platforms, p)
+	}
+	sort.Strings(platforms)
+	return platforms
+}
+
+// APIGatewayOverview aggregates request counts, error classes and p99 latency per API config
+// and returns sample logs of failing requests
+func (c *Client) APIGatewayOverview(ctx context.Context, params APIGatewayOverviewParams) (*APIGatewayOverviewResult, error) {
+	startTime, endTime, err := monitoring.ParseTimeRange(params.TimeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse time range: %w", err)
+	}
+
+	platformName := params.Platform
+	if platformName == "" {
+		platformName = "api_gateway"
+	}
+	platform, ok := apiPlatforms[platformName]
+	if !ok {
+		return nil, fmt.Errorf("unsupported platform: %s (supported: %s)", platformName, strings.Join(APIPlatforms(), ", "))
+	}
+
+	kind := c.kinds[platformName]
+	filter := kindFilter(kind, params.API, params.Location)
+	signals := c.querySignals(ctx, params.ProjectID, kind, filter, params.TimeRange, params.AlignmentPeriodSec, params.MaxSeries)
+	result := &APIGatewayOverviewResult{
+		QueryMeta: APIGatewayQueryMeta{
+			OverviewQueryMeta: OverviewQueryMeta{
+				ProjectID: params.ProjectID,
+				Target:    params.API,
+				Start:     startTime.Format(time.RFC3339),
+				End:       endTime.Format(time.RFC3339),
+			},
+			Platform: platformName,
+		},
+		Configs:     apiConfigs(platform, strings.TrimPrefix(kind.NameLabel, "resource.labels."), signals),
+		Signals:     signals,
+		FailingLogs: []logging.LogEntry{},
+	}
+
+	logClause := filter
+	if !platform.logByAPI {
+		logClause = kindFilter(kind, "", params.Location)
+	}
+	logs, err := c.queryKindLogs(ctx, params.ProjectID, kind, params.API, logClause, params.TimeRange, params.Limit)
+	if err != nil {
+		result.Errors = map[string]string{"failing_logs": err.Error()}
+	} else {
+		result.FailingLogs = logs
+	}
+
+	return result, nil
+}
+
+// apiConfigs はシグナルを API 構成ごとのリクエスト数・エラークラス・p99 レイテンシにまとめる
+func apiConfigs(platform apiPlatform, apiLabel string, signals []Signal) []APIConfigSummary {
+	configs := map[string]*APIConfigSummary{}
+	for _, s := range signals {
+		for _, ts := range s.Series {
+			api, config := ts.Resource.Labels[apiLabel], ts.Resource.Labels[platform.configLabel]
+			key := api + "/" + config
+			cfg, ok := configs[key]
+			if !ok {
+				cfg = &APIConfigSummary{API: api, Config: config, ByClass: map[string]int64{}}
+				configs[key] = cfg
+			}
+			switch s.Name {
+			case "requests":
+				class := responseClass(ts.Metric.Labels[platform.codeLabel])
+				for _, p := range ts.Points {
+					cfg.Requests += int64(p.Value)
+					cfg.ByClass[class] += int64(p.Value)
+				}
+			case "latency_p99":
+				for _, p := range ts.Points {
+					v := p.Value * platform.latencyToMs
+					if cfg.PeakLatencyP99 == nil || v > *cfg.PeakLatencyP99 {
+						cfg.PeakLatencyP99 = &v
+					}
+				}
+			}
+		}
+	}
+
+	result := make([]APIConfigSummary, 0, len(configs))
+	for _, cfg := range configs {
+		if cfg.Requests > 0 {
+			errRate := float64(cfg.ByClass["5xx"]) / float64(cfg.Requests)
+			clientRate := float64(cfg.ByClass["4xx"]) / float64(cfg.Requests)
+			cfg.ErrorRate, cfg.ClientErrorRate = &errRate, &clientRate
+		}
+		result = append(result, *cfg)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.ByClass["5xx"] != b.ByClass["5xx"] {
+			return a.ByClass["5xx"] > b.ByClass["5xx"]
+		}
+		if a.ByClass["4xx"] != b.ByClass["4xx"] {
+			return a.ByClass["4xx"] > b.ByClass["4xx"]
+		}
+		return a.Requests > b.Requests
+	})
+	return result
+}
+
+// responseClass はレスポンスコード（"503"）またはクラス（"5xx"）をクラスにそろえる
+func responseClass(code string) string {
+	if code == "" {
+		return "unknown"
+	}
+	if strings.HasSuffix(code, "xx") {
+		return code
+	}
+	return code[:1] + "xx"
+}
+
+// APIGatewayOverviewHandlerWithGuardrail returns a handler with guardrail validation
+func (c *Client) APIGatewayOverviewHandlerWithGuardrail(v Validator) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params APIGatewayOverviewParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		// ガードレール: 系列数・件数制限
+		if params.MaxSeries <= 0 {
+			params.MaxSeries = 50
+		}
+		params.MaxSeries = v.ClampTimeSeriesLimit(ctx, params.MaxSeries)
+		if params.Limit <= 0 {
+			params.Limit = 20
+		}
+		params.Limit = v.ClampLogLimit(ctx, params.Limit)
+
+		return c.APIGatewayOverview(ctx, params)
+	}
+}
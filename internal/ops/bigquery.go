@@ -0,0 +1,141 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	bigquery "google.golang.org/api/bigquery/v2"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+)
+
+// BigQueryOverviewParams are the parameters for ops.bigquery_overview
+type BigQueryOverviewParams struct {
+	ProjectID          string               `json:"project_id"`
+	TimeRange          monitoring.TimeRange `json:"time_range"`
+	AlignmentPeriodSec int                  `json:"alignment_period_sec"`
+	IncludeJobs        bool                 `json:"include_jobs"` // Query INFORMATION_SCHEMA.JOBS (billed)
+	Region             string               `json:"region"`       // e.g. "us", "asia-northeast1" (default: "us")
+	Limit              int                  `json:"limit"`        // Max error logs / jobs
+}
+
+// BigQueryOverviewResult is the result of ops.bigquery_overview
+type BigQueryOverviewResult struct {
+	QueryMeta OverviewQueryMeta  `json:"query_meta"`
+	Signals   []Signal           `json:"signals"`
+	ErrorLogs []logging.LogEntry `json:"error_logs"`
+	Jobs      []map[string]any   `json:"jobs,omitempty"`
+	Errors    map[string]string  `json:"errors,omitempty"` // section -> error
+}
+
+var bigQueryRegionPattern = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// BigQueryOverview combines slot/scan metrics, job error logs and (optionally) INFORMATION_SCHEMA.JOBS
+func (c *Client) BigQueryOverview(ctx context.Context, params BigQueryOverviewParams) (*BigQueryOverviewResult, error) {
+	startTime, endTime, err := monitoring.ParseTimeRange(params.TimeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse time range: %w", err)
+	}
+
+	region := params.Region
+	if region == "" {
+		region = "us"
+	}
+	if !bigQueryRegionPattern.MatchString(region) {
+		return nil, fmt.Errorf("invalid region: %s", region)
+	}
+
+	// スロット・スキャン関連のメトリクスとジョブのエラーログ（リソース種別 bigquery）
+	kind := c.kinds["bigquery"]
+	result := &BigQueryOverviewResult{
+		QueryMeta: OverviewQueryMeta{
+			ProjectID: params.ProjectID,
+			Start:     startTime.Format(time.RFC3339),
+			End:       endTime.Format(time.RFC3339),
+		},
+		Signals:   c.querySignals(ctx, params.ProjectID, kind, "", params.TimeRange, params.AlignmentPeriodSec, 1),
+		ErrorLogs: []logging.LogEntry{},
+	}
+	errs := map[string]string{}
+
+	logs, err := c.queryKindLogs(ctx, params.ProjectID, kind, "", "", params.TimeRange, params.Limit)
+	if err != nil {
+		errs["error_logs"] = err.Error()
+	} else {
+		result.ErrorLogs = logs
+	}
+
+	if params.IncludeJobs {
+		jobs, err := c.queryJobs(ctx, params.ProjectID, region, startTime, endTime, params.Limit)
+		if err != nil {
+			errs["jobs"] = err.Error()
+		} else {
+			result.Jobs = jobs
+		}
+	}
+
+	if len(errs) > 0 {
+		result.Errors = errs
+	}
+	return result, nil
+}
+
+// queryJobs はINFORMATION_SCHEMA.JOBSからスロット消費の大きいジョブを取得する
+func (c *Client) queryJobs(ctx context.Context, projectID, region string, start, end time.Time, limit int) ([]map[string]any, error) {
+	query := fmt.Sprintf("SELECT job_id, user_email, job_type, state, error_result.reason AS error_reason, "+
+		"total_slot_ms, total_bytes_processed, "+
+		"TIMESTAMP_DIFF(start_time, creation_time, MILLISECOND) AS queued_ms, "+
+		"TIMESTAMP_DIFF(end_time, start_time, MILLISECOND) AS run_ms, "+
+		"FORMAT_TIMESTAMP('%%Y-%%m-%%dT%%H:%%M:%%SZ', creation_time) AS creation_time "+
+		"FROM `region-%s`.INFORMATION_SCHEMA.JOBS "+
+		"WHERE creation_time BETWEEN TIMESTAMP('%s') AND TIMESTAMP('%s') "+
+		"ORDER BY total_slot_ms DESC LIMIT %d",
+		region, start.UTC().Format(time.RFC3339), end.UTC().Format(time.RFC3339), limit)
+
+	useLegacySQL := false
+	resp, err := c.bigquery.Jobs.Query(projectID, &bigquery.QueryRequest{
+		Query:        query,
+		UseLegacySql: &useLegacySQL,
+		TimeoutMs:    30000,
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query INFORMATION_SCHEMA.JOBS: %w", err)
+	}
+
+	jobs := []map[string]any{}
+	if resp.Schema == nil {
+		return jobs, nil
+	}
+	for _, row := range resp.Rows {
+		job := map[string]any{}
+		for i, cell := range row.F {
+			if i < len(resp.Schema.Fields) && cell.V != nil {
+				job[resp.Schema.Fields[i].Name] = cell.V
+			}
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// BigQueryOverviewHandlerWithGuardrail returns a handler with guardrail validation
+func (c *Client) BigQueryOverviewHandlerWithGuardrail(v Validator) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params BigQueryOverviewParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		// ガードレール: 件数制限（エラーログ・ジョブは少なめに）
+		if params.Limit <= 0 {
+			params.Limit = 20
+		}
+		params.Limit = v.ClampLogLimit(ctx, params.Limit)
+
+		return c.BigQueryOverview(ctx, params)
+	}
+}
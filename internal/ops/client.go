@@ -0,0 +1,114 @@
+package ops
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	bigquery "google.golang.org/api/bigquery/v2"
+	cloudbuild "google.golang.org/api/cloudbuild/v1"
+	clouddeploy "google.golang.org/api/clouddeploy/v1"
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v3"
+	"google.golang.org/api/option"
+	recommender "google.golang.org/api/recommender/v1"
+	"google.golang.org/api/storage/v1"
+	htransport "google.golang.org/api/transport/http"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+)
+
+// Client は複数のAPIを組み合わせた運用向けツール（ops.*）を提供する
+type Client struct {
+	monitoring  *monitoring.Client
+	logging     *logging.Client
+	bigquery    *bigquery.Service
+	recommender *recommender.Service
+	cloudbuild  *cloudbuild.Service
+	clouddeploy *clouddeploy.Service
+	resourceMgr *cloudresourcemanager.Service
+	storage     *storage.Service // ops.export_result の書き出し先
+	httpClient  *http.Client     // Goクライアントのない REST API 用（ADC認証付き）
+	kinds       config.ResourceKinds
+}
+
+// NewClient は既存のMonitoring/Loggingクライアントを使ってopsクライアントを作成
+func NewClient(ctx context.Context, monitoringClient *monitoring.Client, loggingClient *logging.Client, opts ...option.ClientOption) (*Client, error) {
+	bq, err := bigquery.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bigquery client: %w", err)
+	}
+	rec, err := recommender.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recommender client: %w", err)
+	}
+	cb, err := cloudbuild.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cloud build client: %w", err)
+	}
+	cd, err := clouddeploy.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cloud deploy client: %w", err)
+	}
+	crm, err := cloudresourcemanager.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource manager client: %w", err)
+	}
+	st, err := storage.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage client: %w", err)
+	}
+	httpClient, _, err := htransport.NewClient(ctx, append([]option.ClientOption{option.WithScopes("https://www.googleapis.com/auth/cloud-platform")}, opts...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create http client: %w", err)
+	}
+	return &Client{
+		monitoring:  monitoringClient,
+		logging:     loggingClient,
+		bigquery:    bq,
+		recommender: rec,
+		cloudbuild:  cb,
+		clouddeploy: cd,
+		resourceMgr: crm,
+		storage:     st,
+		httpClient:  httpClient,
+		kinds:       config.BuiltinResourceKinds(),
+	}, nil
+}
+
+// SetResourceKinds はシグナル・ログの定義を読むリソース種別のレジストリを設定する（設定の resource_kinds を重ねたもの）
+func (c *Client) SetResourceKinds(kinds config.ResourceKinds) {
+	c.kinds = kinds
+}
+
+// OverviewQueryMeta は ops.*_overview 系ツール共通のクエリ情報
+type OverviewQueryMeta struct {
+	ProjectID string `json:"project_id"`
+	Target    string `json:"target,omitempty"`
+	Start     string `json:"start"`
+	End       string `json:"end"`
+}
+
+// queryLogs はエラーログなどのサンプルを取得する
+func (c *Client) queryLogs(ctx context.Context, projectID, filter string, tr monitoring.TimeRange, limit int) ([]logging.LogEntry, error) {
+	result, err := c.logging.Query(ctx, logging.QueryParams{
+		ProjectID: projectID,
+		Filter:    filter,
+		TimeRange: logging.TimeRange(tr),
+		Limit:     limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Entries, nil
+}
+
+// Validator はガードレール検証用インターフェース
+type Validator interface {
+	ValidateProjectID(ctx context.Context, projectID string) error
+	ValidateTimeRange(ctx context.Context, start, end time.Time) error
+	ClampTimeSeriesLimit(ctx context.Context, limit int) int
+	ClampLogLimit(ctx context.Context, limit int) int
+}
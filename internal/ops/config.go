@@ -0,0 +1,67 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/auth"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+)
+
+// GetConfigResult is the result of ops.get_config
+type GetConfigResult struct {
+	Config          map[string]any `json:"config"`                     // config.yaml と同じキー構造
+	Profile         *ProfileConfig `json:"profile,omitempty"`          // 呼び出しに適用されたプロファイル
+	Client          *ClientConfig  `json:"client,omitempty"`           // HTTP トランスポートの接続元ごとの制限
+	EffectiveLimits *config.Limits `json:"effective_limits,omitempty"` // プロファイル・接続元で絞り込んだ上限
+}
+
+// ProfileConfig is the guardrail profile applied to the call on top of config
+type ProfileConfig struct {
+	Name              string   `json:"name"`
+	AllowedProjectIDs []string `json:"allowed_project_ids,omitempty"`
+	DeniedProjectIDs  []string `json:"denied_project_ids,omitempty"`
+	RedactionPatterns int      `json:"redaction_patterns,omitempty"` // Number of extra redaction patterns
+}
+
+// ClientConfig is the restrictions applied to the authenticated HTTP client on top of config
+type ClientConfig struct {
+	Name              string   `json:"name"`
+	AllowedProjectIDs []string `json:"allowed_project_ids,omitempty"`
+}
+
+// GetConfigHandler returns a handler that exposes the sanitized effective config.
+// limits returns the limits applied to the call (see guardrail.Guardrail.Limits)
+func GetConfigHandler(cfg *config.Config, limits func(ctx context.Context) config.Limits) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		// yaml タグのキー名で返すため YAML を経由してマップに変換する
+		out, err := cfg.Sanitized().YAML()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode config: %w", err)
+		}
+		m := map[string]any{}
+		if err := yaml.Unmarshal(out, &m); err != nil {
+			return nil, fmt.Errorf("failed to encode config: %w", err)
+		}
+		result := &GetConfigResult{Config: m}
+		if name, profile := auth.ProfileFrom(ctx); profile != nil {
+			result.Profile = &ProfileConfig{
+				Name:              name,
+				AllowedProjectIDs: profile.AllowedProjectIDs,
+				DeniedProjectIDs:  profile.DeniedProjectIDs,
+				RedactionPatterns: len(profile.Redaction.Patterns),
+			}
+		}
+		if client := auth.ClientFrom(ctx); client != nil {
+			result.Client = &ClientConfig{Name: client.Name, AllowedProjectIDs: client.AllowedProjectIDs}
+		}
+		if result.Profile != nil || result.Client != nil {
+			effective := limits(ctx)
+			result.EffectiveLimits = &effective
+		}
+		return result, nil
+	}
+}
@@ -0,0 +1,174 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	bigquery "google.golang.org/api/bigquery/v2"
+)
+
+// CostSignalParams are the parameters for ops.cost_signal
+type CostSignalParams struct {
+	ProjectID  string  `json:"project_id"`
+	Days       int     `json:"days"`              // Lookback in days (default: 14, max: 90)
+	Service    string  `json:"service,omitempty"` // Optional service description filter (e.g., "Cloud Logging")
+	SpikeRatio float64 `json:"spike_ratio"`       // Latest day / baseline ratio to flag (default: 1.5)
+}
+
+// CostSignalResult is the result of ops.cost_signal
+type CostSignalResult struct {
+	QueryMeta CostSignalQueryMeta `json:"query_meta"`
+	Services  []ServiceCost       `json:"services"`
+	Daily     []DailyCost         `json:"daily"`
+}
+
+type CostSignalQueryMeta struct {
+	ProjectID   string  `json:"project_id"`
+	ExportTable string  `json:"export_table"`
+	Days        int     `json:"days"`
+	SpikeRatio  float64 `json:"spike_ratio"`
+}
+
+type DailyCost struct {
+	Date     string  `json:"date"`
+	Service  string  `json:"service"`
+	Cost     float64 `json:"cost"`
+	Currency string  `json:"currency"`
+}
+
+// ServiceCost は直近日のコストとそれ以前の平均（baseline）の比較
+type ServiceCost struct {
+	Service    string  `json:"service"`
+	LatestDate string  `json:"latest_date"`
+	Latest     float64 `json:"latest"`
+	Baseline   float64 `json:"baseline"`
+	Ratio      float64 `json:"ratio,omitempty"`
+	Spike      bool    `json:"spike"`
+}
+
+var billingTablePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+\.[A-Za-z0-9_]+\.[A-Za-z0-9_]+$`)
+
+// CostSignal reports daily cost by service from the billing export and flags spikes
+func (c *Client) CostSignal(ctx context.Context, exportTable string, params CostSignalParams) (*CostSignalResult, error) {
+	if !billingTablePattern.MatchString(exportTable) {
+		return nil, fmt.Errorf("invalid billing export table: %s (expected 'project.dataset.table')", exportTable)
+	}
+
+	days := params.Days
+	if days <= 0 {
+		days = 14
+	}
+	if days > 90 {
+		days = 90
+	}
+	spikeRatio := params.SpikeRatio
+	if spikeRatio <= 0 {
+		spikeRatio = 1.5
+	}
+
+	query := fmt.Sprintf("SELECT FORMAT_DATE('%%F', DATE(usage_start_time)) AS day, service.description AS service, currency, "+
+		"SUM(cost) + SUM(IFNULL((SELECT SUM(c.amount) FROM UNNEST(credits) c), 0)) AS cost "+
+		"FROM `%s` "+
+		"WHERE project.id = @project_id AND usage_start_time >= TIMESTAMP_SUB(CURRENT_TIMESTAMP(), INTERVAL @days DAY) "+
+		"AND (@service = '' OR service.description = @service) "+
+		"GROUP BY day, service, currency ORDER BY day, service", exportTable)
+
+	useLegacySQL := false
+	queryProject := strings.SplitN(exportTable, ".", 2)[0]
+	resp, err := c.bigquery.Jobs.Query(queryProject, &bigquery.QueryRequest{
+		Query:         query,
+		UseLegacySql:  &useLegacySQL,
+		ParameterMode: "NAMED",
+		QueryParameters: []*bigquery.QueryParameter{
+			stringParam("project_id", params.ProjectID),
+			{Name: "days", ParameterType: &bigquery.QueryParameterType{Type: "INT64"}, ParameterValue: &bigquery.QueryParameterValue{Value: strconv.Itoa(days)}},
+			stringParam("service", params.Service),
+		},
+		TimeoutMs: 30000,
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query billing export: %w", err)
+	}
+
+	daily := []DailyCost{}
+	byService := map[string][]DailyCost{}
+	for _, row := range resp.Rows {
+		if len(row.F) < 4 {
+			continue
+		}
+		cost, _ := strconv.ParseFloat(fmt.Sprint(row.F[3].V), 64)
+		d := DailyCost{
+			Date:     fmt.Sprint(row.F[0].V),
+			Service:  fmt.Sprint(row.F[1].V),
+			Currency: fmt.Sprint(row.F[2].V),
+			Cost:     cost,
+		}
+		daily = append(daily, d)
+		byService[d.Service] = append(byService[d.Service], d)
+	}
+
+	services := []ServiceCost{}
+	for name, costs := range byService {
+		// 行は日付順。最終日を直近、それ以前の平均をbaselineとする
+		latest := costs[len(costs)-1]
+		sc := ServiceCost{
+			Service:    name,
+			LatestDate: latest.Date,
+			Latest:     latest.Cost,
+		}
+		if len(costs) > 1 {
+			sum := 0.0
+			for _, d := range costs[:len(costs)-1] {
+				sum += d.Cost
+			}
+			sc.Baseline = sum / float64(len(costs)-1)
+		}
+		if sc.Baseline > 0 {
+			sc.Ratio = sc.Latest / sc.Baseline
+			sc.Spike = sc.Ratio >= spikeRatio
+		}
+		services = append(services, sc)
+	}
+	sort.Slice(services, func(i, j int) bool {
+		if services[i].Spike != services[j].Spike {
+			return services[i].Spike
+		}
+		return services[i].Latest > services[j].Latest
+	})
+
+	return &CostSignalResult{
+		QueryMeta: CostSignalQueryMeta{
+			ProjectID:   params.ProjectID,
+			ExportTable: exportTable,
+			Days:        days,
+			SpikeRatio:  spikeRatio,
+		},
+		Services: services,
+		Daily:    daily,
+	}, nil
+}
+
+func stringParam(name, value string) *bigquery.QueryParameter {
+	return &bigquery.QueryParameter{
+		Name:           name,
+		ParameterType:  &bigquery.QueryParameterType{Type: "STRING"},
+		ParameterValue: &bigquery.QueryParameterValue{Value: value, ForceSendFields: []string{"Value"}},
+	}
+}
+
+// CostSignalHandler returns a handler for the ops.cost_signal tool
+func (c *Client) CostSignalHandler(exportTable string) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params CostSignalParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		return c.CostSignal(ctx, exportTable, params)
+	}
+}
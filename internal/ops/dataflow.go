@@ -0,0 +1,172 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+)
+
+// DataflowOverviewParams are the parameters for ops.dataflow_overview
+type DataflowOverviewParams struct {
+	ProjectID          string               `json:"project_id"`
+	JobID              string               `json:"job_id"`
+	Region             string               `json:"region,omitempty"`
+	TimeRange          monitoring.TimeRange `json:"time_range"`
+	AlignmentPeriodSec int                  `json:"alignment_period_sec"`
+	Limit              int                  `json:"limit"` // Max job messages
+}
+
+// DataflowOverviewResult is the result of ops.dataflow_overview
+type DataflowOverviewResult struct {
+	QueryMeta   OverviewQueryMeta  `json:"query_meta"`
+	Summary     DataflowSummary    `json:"summary"`
+	Signals     []Signal           `json:"signals"`
+	Autoscaling []AutoscalingEvent `json:"autoscaling"`  // Newest first
+	JobMessages []logging.LogEntry `json:"job_messages"` // Warnings and errors, newest first
+	Errors      map[string]string  `json:"errors,omitempty"`
+}
+
+type DataflowSummary struct {
+	PeakSystemLagSec    *float64 `json:"peak_system_lag_sec,omitempty"`
+	PeakWatermarkAgeSec *float64 `json:"peak_watermark_age_sec,omitempty"` // Streaming jobs only
+	LatestVCPUs         *float64 `json:"latest_vcpus,omitempty"`
+	Failed              bool     `json:"failed"`
+}
+
+// AutoscalingEvent はジョブメッセージのうちワーカー数の変更1件
+type AutoscalingEvent struct {
+	Time    string `json:"time"`
+	Workers *int   `json:"workers,omitempty"` // Target number of workers, when the message states it
+	Message string `json:"message"`
+}
+
+// autoscalingWorkersPattern はオートスケーリングのメッセージ中の目標ワーカー数
+// （例: "Autoscaling: Raised the number of workers to 12 based on ..."）
+var autoscalingWorkersPattern = regexp.MustCompile(`number of workers to (\d+)`)
+
+// DataflowOverview returns lag, watermark and vCPU signals of a Dataflow job together with
+// its autoscaling events and recent warning/error job messages
+func (c *Client) DataflowOverview(ctx context.Context, params DataflowOverviewParams) (*DataflowOverviewResult, error) {
+	startTime, endTime, err := monitoring.ParseTimeRange(params.TimeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse time range: %w", err)
+	}
+
+	kind := c.kinds["dataflow_job"]
+	filter := kindFilter(kind, params.JobID, params.Region)
+	signals := c.querySignals(ctx, params.ProjectID, kind, filter, params.TimeRange, params.AlignmentPeriodSec, 1)
+	result := &DataflowOverviewResult{
+		QueryMeta: OverviewQueryMeta{
+			ProjectID: params.ProjectID,
+			Target:    params.JobID,
+			Start:     startTime.Format(time.RFC3339),
+			End:       endTime.Format(time.RFC3339),
+		},
+		Summary:     dataflowSummary(signals),
+		Signals:     signals,
+		Autoscaling: []AutoscalingEvent{},
+		JobMessages: []logging.LogEntry{},
+	}
+	errs := map[string]string{}
+
+	// ジョブメッセージ（コンソールの「ジョブのログ」）はジョブ・ステップのリソースに書かれる
+	scaling, err := c.queryKindLogs(ctx, params.ProjectID, kind, params.JobID, filter+` AND "Autoscaling"`, params.TimeRange, params.Limit)
+	if err != nil {
+		errs["autoscaling"] = err.Error()
+	} else {
+		for _, e := range scaling {
+			result.Autoscaling = append(result.Autoscaling, autoscalingEvent(e))
+		}
+	}
+	logs, err := c.queryKindLogs(ctx, params.ProjectID, kind, params.JobID, filter+" AND severity >= WARNING", params.TimeRange, params.Limit)
+	if err != nil {
+		errs["job_messages"] = err.Error()
+	} else {
+		result.JobMessages = logs
+	}
+
+	if len(errs) > 0 {
+		result.Errors = errs
+	}
+	return result, nil
+}
+
+// dataflowSummary はシグナルからピークの遅延と最新の vCPU 数を取り出す
+func dataflowSummary(signals []Signal) DataflowSummary {
+	var summary DataflowSummary
+	for _, s := range signals {
+		for _, ts := range s.Series {
+			if len(ts.Points) == 0 {
+				continue
+			}
+			switch s.Name {
+			case "system_lag":
+				summary.PeakSystemLagSec = peakValue(summary.PeakSystemLagSec, ts.Points)
+			case "data_watermark_age":
+				summary.PeakWatermarkAgeSec = peakValue(summary.PeakWatermarkAgeSec, ts.Points)
+			case "vcpus":
+				// ポイントは新しい順
+				v := ts.Points[0].Value
+				summary.LatestVCPUs = &v
+			case "is_failed":
+				summary.Failed = summary.Failed || ts.Points[0].Value > 0
+			}
+		}
+	}
+	return summary
+}
+
+// peakValue は current とポイントの最大値を返す
+func peakValue(current *float64, points []monitoring.DataPoint) *float64 {
+	for _, p := range points {
+		if current == nil || p.Value > *current {
+			v := p.Value
+			current = &v
+		}
+	}
+	return current
+}
+
+// autoscalingEvent はオートスケーリングのジョブメッセージを正規化する
+func autoscalingEvent(e logging.LogEntry) AutoscalingEvent {
+	message := e.TextPayload
+	if message == "" {
+		message = payloadString(e.JSONPayload["message"])
+	}
+	event := AutoscalingEvent{Time: e.Timestamp, Message: strings.TrimSpace(message)}
+	if m := autoscalingWorkersPattern.FindStringSubmatch(message); m != nil {
+		if n, err := strconv.Atoi(m[1]); err == nil {
+			event.Workers = &n
+		}
+	}
+	return event
+}
+
+// DataflowOverviewHandlerWithGuardrail returns a handler with guardrail validation
+func (c *Client) DataflowOverviewHandlerWithGuardrail(v Validator) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params DataflowOverviewParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.JobID == "" {
+			return nil, fmt.Errorf("job_id is required")
+		}
+
+		// ガードレール: 件数制限
+		if params.Limit <= 0 {
+			params.Limit = 20
+		}
+		params.Limit = v.ClampLogLimit(ctx, params.Limit)
+
+		return c.DataflowOverview(ctx, params)
+	}
+}
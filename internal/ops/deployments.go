@@ -0,0 +1,262 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	cloudbuild "google.golang.org/api/cloudbuild/v1"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+)
+
+// RecentDeploymentsParams are the parameters for ops.recent_deployments
+type RecentDeploymentsParams struct {
+	ProjectID string               `json:"project_id"`
+	Location  string               `json:"location"` // Region for Cloud Deploy / regional builds (optional)
+	TimeRange monitoring.TimeRange `json:"time_range"`
+	Limit     int                  `json:"limit"`
+}
+
+// RecentDeploymentsResult is the result of ops.recent_deployments
+type RecentDeploymentsResult struct {
+	QueryMeta   OverviewQueryMeta `json:"query_meta"`
+	Deployments []DeploymentEvent `json:"deployments"` // Newest first
+	Stats       DeploymentsStats  `json:"stats"`
+	Errors      map[string]string `json:"errors,omitempty"` // source -> error
+}
+
+// DeploymentEvent は Cloud Build / Cloud Deploy の1イベント
+type DeploymentEvent struct {
+	Source     string   `json:"source"` // "cloud_build", "cloud_deploy_release", "cloud_deploy_rollout"
+	ID         string   `json:"id"`
+	Status     string   `json:"status"`
+	Time       string   `json:"time"` // 並び順の基準（開始 or 作成時刻）
+	FinishTime string   `json:"finish_time,omitempty"`
+	Pipeline   string   `json:"pipeline,omitempty"`
+	Release    string   `json:"release,omitempty"`
+	Target     string   `json:"target,omitempty"`
+	Trigger    string   `json:"trigger,omitempty"`
+	Images     []string `json:"images,omitempty"`
+	Detail     string   `json:"detail,omitempty"`
+}
+
+type DeploymentsStats struct {
+	CountBySource map[string]int `json:"count_by_source"`
+	ReturnedCount int            `json:"returned_count"`
+	Truncated     bool           `json:"truncated"`
+}
+
+// RecentDeployments lists recent Cloud Build builds and Cloud Deploy releases/rollouts
+func (c *Client) RecentDeployments(ctx context.Context, params RecentDeploymentsParams) (*RecentDeploymentsResult, error) {
+	startTime, endTime, err := monitoring.ParseTimeRange(params.TimeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse time range: %w", err)
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+
+	events := []DeploymentEvent{}
+	errs := map[string]string{}
+
+	// 取得元ごとに失敗しても他の結果は返す
+	builds, err := c.listBuilds(ctx, params.ProjectID, params.Location, startTime, endTime, limit)
+	if err != nil {
+		errs["cloud_build"] = err.Error()
+	}
+	events = append(events, builds...)
+
+	// Cloud Deploy はリージョンリソースなので location 指定時のみ
+	if params.Location != "" {
+		deploys, err := c.listDeployEvents(ctx, params.ProjectID, params.Location, startTime, endTime, limit)
+		if err != nil {
+			errs["cloud_deploy"] = err.Error()
+		}
+		events = append(events, deploys...)
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Time > events[j].Time
+	})
+	truncated := false
+	if len(events) > limit {
+		events = events[:limit]
+		truncated = true
+	}
+
+	countBySource := map[string]int{}
+	for _, e := range events {
+		countBySource[e.Source]++
+	}
+
+	result := &RecentDeploymentsResult{
+		QueryMeta: OverviewQueryMeta{
+			ProjectID: params.ProjectID,
+			Target:    params.Location,
+			Start:     startTime.Format(time.RFC3339),
+			End:       endTime.Format(time.RFC3339),
+		},
+		Deployments: events,
+		Stats: DeploymentsStats{
+			CountBySource: countBySource,
+			ReturnedCount: len(events),
+			Truncated:     truncated,
+		},
+	}
+	if len(errs) > 0 {
+		result.Errors = errs
+	}
+	return result, nil
+}
+
+// listBuilds は期間内に作成されたビルドを取得する
+func (c *Client) listBuilds(ctx context.Context, projectID, location string, start, end time.Time, limit int) ([]DeploymentEvent, error) {
+	filter := fmt.Sprintf(`create_time>="%s" AND create_time<="%s"`, start.Format(time.RFC3339), end.Format(time.RFC3339))
+
+	events := []DeploymentEvent{}
+	pageToken := ""
+	for len(events) < limit {
+		var resp *cloudbuild.ListBuildsResponse
+		var err error
+		if location != "" {
+			resp, err = c.cloudbuild.Projects.Locations.Builds.List(fmt.Sprintf("projects/%s/locations/%s", projectID, location)).
+				Context(ctx).Filter(filter).PageSize(int64(limit)).PageToken(pageToken).Do()
+		} else {
+			resp, err = c.cloudbuild.Projects.Builds.List(projectID).
+				Context(ctx).Filter(filter).PageSize(int64(limit)).PageToken(pageToken).Do()
+		}
+		if err != nil {
+			return events, fmt.Errorf("failed to list builds: %w", err)
+		}
+
+		for _, b := range resp.Builds {
+			t := b.StartTime
+			if t == "" {
+				t = b.CreateTime
+			}
+			e := DeploymentEvent{
+				Source:     "cloud_build",
+				ID:         b.Id,
+				Status:     b.Status,
+				Time:       t,
+				FinishTime: b.FinishTime,
+				Trigger:    b.BuildTriggerId,
+				Images:     b.Images,
+				Detail:     b.StatusDetail,
+			}
+			if b.FailureInfo != nil && b.FailureInfo.Detail != "" {
+				e.Detail = b.FailureInfo.Detail
+			}
+			events = append(events, e)
+		}
+
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	return events, nil
+}
+
+// listDeployEvents は全デリバリーパイプラインのリリース・ロールアウトを取得する
+func (c *Client) listDeployEvents(ctx context.Context, projectID, location string, start, end time.Time, limit int) ([]DeploymentEvent, error) {
+	// "-" で全パイプライン・全リリースを横断する
+	pipelines := fmt.Sprintf("projects/%s/locations/%s/deliveryPipelines/-", projectID, location)
+	filter := fmt.Sprintf(`create_time>="%s"`, start.Format(time.RFC3339))
+	inRange := func(ts string) bool {
+		t, err := time.Parse(time.RFC3339Nano, ts)
+		return err == nil && !t.Before(start) && !t.After(end)
+	}
+
+	events := []DeploymentEvent{}
+
+	releases, err := c.clouddeploy.Projects.Locations.DeliveryPipelines.Releases.List(pipelines).
+		Context(ctx).Filter(filter).OrderBy("create_time desc").PageSize(int64(limit)).Do()
+	if err != nil {
+		return events, fmt.Errorf("failed to list releases: %w", err)
+	}
+	for _, r := range releases.Releases {
+		if !inRange(r.CreateTime) {
+			continue
+		}
+		p := parseDeployName(r.Name)
+		images := []string{}
+		for _, a := range r.BuildArtifacts {
+			images = append(images, a.Tag)
+		}
+		events = append(events, DeploymentEvent{
+			Source:   "cloud_deploy_release",
+			ID:       p["releases"],
+			Status:   r.RenderState,
+			Time:     r.CreateTime,
+			Pipeline: p["deliveryPipelines"],
+			Release:  p["releases"],
+			Images:   images,
+			Detail:   r.Description,
+		})
+	}
+
+	rollouts, err := c.clouddeploy.Projects.Locations.DeliveryPipelines.Releases.Rollouts.List(pipelines + "/releases/-").
+		Context(ctx).Filter(filter).OrderBy("create_time desc").PageSize(int64(limit)).Do()
+	if err != nil {
+		return events, fmt.Errorf("failed to list rollouts: %w", err)
+	}
+	for _, r := range rollouts.Rollouts {
+		t := r.DeployStartTime
+		if t == "" {
+			t = r.CreateTime
+		}
+		if !inRange(t) {
+			continue
+		}
+		p := parseDeployName(r.Name)
+		e := DeploymentEvent{
+			Source:     "cloud_deploy_rollout",
+			ID:         p["rollouts"],
+			Status:     r.State,
+			Time:       t,
+			FinishTime: r.DeployEndTime,
+			Pipeline:   p["deliveryPipelines"],
+			Release:    p["releases"],
+			Target:     r.TargetId,
+		}
+		if r.FailureReason != "" {
+			e.Detail = r.FailureReason
+		}
+		events = append(events, e)
+	}
+
+	return events, nil
+}
+
+// parseDeployName は Cloud Deploy のリソース名を コレクション → ID のマップに分解する
+// 例: projects/P/locations/L/deliveryPipelines/X/releases/Y/rollouts/Z
+func parseDeployName(name string) map[string]string {
+	m := map[string]string{}
+	parts := strings.Split(name, "/")
+	for i := 0; i+1 < len(parts); i += 2 {
+		m[parts[i]] = parts[i+1]
+	}
+	return m
+}
+
+// RecentDeploymentsHandler returns a handler for the ops.recent_deployments tool
+func (c *Client) RecentDeploymentsHandler() func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params RecentDeploymentsParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		return c.RecentDeployments(ctx, params)
+	}
+}
@@ -0,0 +1,473 @@
+package ops
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	bigquery "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/storage/v1"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+)
+
+// exportObjectPrefix は GCS に書き出すオブジェクトの接頭辞
+const exportObjectPrefix = "gcp-ops-mcp/exports/"
+
+// exportJobTimeout は BigQuery の読み込みジョブの完了を待つ上限
+const exportJobTimeout = 5 * time.Minute
+
+// 書き出す列（ネストした値は JSON 文字列 / JSON 型にする）
+var (
+	logExportColumns    = []string{"timestamp", "severity", "log_name", "insert_id", "trace", "span_id", "resource_type", "resource_labels", "labels", "text_payload", "json_payload"}
+	metricExportColumns = []string{"time", "value", "metric_type", "metric_labels", "resource_type", "resource_labels"}
+)
+
+// exportSchemas は BigQuery に作るテーブルのスキーマ（列は logExportColumns / metricExportColumns と同じ順）
+var exportSchemas = map[string][]*bigquery.TableFieldSchema{
+	"logs": {
+		{Name: "timestamp", Type: "TIMESTAMP"},
+		{Name: "severity", Type: "STRING"},
+		{Name: "log_name", Type: "STRING"},
+		{Name: "insert_id", Type: "STRING"},
+		{Name: "trace", Type: "STRING"},
+		{Name: "span_id", Type: "STRING"},
+		{Name: "resource_type", Type: "STRING"},
+		{Name: "resource_labels", Type: "JSON"},
+		{Name: "labels", Type: "JSON"},
+		{Name: "text_payload", Type: "STRING"},
+		{Name: "json_payload", Type: "JSON"},
+	},
+	"metrics": {
+		{Name: "time", Type: "TIMESTAMP"},
+		{Name: "value", Type: "FLOAT"},
+		{Name: "metric_type", Type: "STRING"},
+		{Name: "metric_labels", Type: "JSON"},
+		{Name: "resource_type", Type: "STRING"},
+		{Name: "resource_labels", Type: "JSON"},
+	},
+}
+
+// ExportResultParams are the parameters for ops.export_result
+type ExportResultParams struct {
+	Kind      string               `json:"kind"` // "logs" or "metrics"
+	ProjectID string               `json:"project_id"`
+	Filter    string               `json:"filter,omitempty"` // LQL (logs) or a Monitoring filter ANDed to the query (metrics)
+	TimeRange monitoring.TimeRange `json:"time_range"`
+	// metrics のみ
+	MetricType         string   `json:"metric_type,omitempty"`
+	ResourceType       string   `json:"resource_type,omitempty"`
+	AlignmentPeriodSec int      `json:"alignment_period_sec,omitempty"`
+	PerSeriesAligner   string   `json:"per_series_aligner,omitempty"`
+	CrossSeriesReducer string   `json:"cross_series_reducer,omitempty"`
+	GroupByFields      []string `json:"group_by_fields,omitempty"`
+	// 書き出し先と形式
+	Destination  string `json:"destination,omitempty"` // "gcs" or "bigquery" (default: whichever is configured, gcs first)
+	Format       string `json:"format,omitempty"`      // "ndjson" (default) or "csv"; gcs only
+	Limit        int    `json:"limit,omitempty"`       // Log entries (logs) or series (metrics); 0 = the export cap
+	ConfirmToken string `json:"confirm_token,omitempty"`
+}
+
+// ExportResultResult is the result of ops.export_result
+// ConfirmToken が返った場合は未実行（プレビュー）。同じ引数に confirm_token を付けて再度呼ぶと実行される
+type ExportResultResult struct {
+	Executed     bool   `json:"executed"`
+	Preview      string `json:"preview,omitempty"`
+	ConfirmToken string `json:"confirm_token,omitempty"`
+	Destination  string `json:"destination"`
+	Format       string `json:"format"`
+	// 実行後のみ
+	URI       string   `json:"uri,omitempty"` // gs://bucket/object or bq://project.dataset.table
+	JobID     string   `json:"job_id,omitempty"`
+	Start     string   `json:"start,omitempty"`
+	End       string   `json:"end,omitempty"`
+	Rows      int      `json:"rows"`
+	Series    int      `json:"series,omitempty"`    // metrics only
+	Truncated bool     `json:"truncated,omitempty"` // The export cap was reached; narrow the filter or time range for the rest
+	Columns   []string `json:"columns,omitempty"`
+}
+
+// WriteValidator は書き込みツール用のガードレール検証インターフェース
+type WriteValidator interface {
+	IssueConfirmToken(action string, payload any) (string, error)
+	VerifyConfirmToken(token, action string, payload any) error
+}
+
+// exportRows は書き出す行（列名 → 値）と、その期間・系列数・上限に達したか
+type exportRows struct {
+	rows      []map[string]any
+	start     string
+	end       string
+	series    int
+	truncated bool
+}
+
+// ExportResult runs the query up to the export cap and writes every row to the destination
+func (c *Client) ExportResult(ctx context.Context, params ExportResultParams, cfg config.Export, at time.Time) (*ExportResultResult, error) {
+	var data *exportRows
+	var err error
+	if params.Kind == "logs" {
+		data, err = c.exportLogRows(ctx, params)
+	} else {
+		data, err = c.exportMetricRows(ctx, params)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ExportResultResult{
+		Executed:    true,
+		Destination: params.Destination,
+		Format:      params.Format,
+		Start:       data.start,
+		End:         data.end,
+		Rows:        len(data.rows),
+		Series:      data.series,
+		Truncated:   data.truncated,
+		Columns:     exportColumns(params.Kind),
+	}
+	stamp := at.UTC().Format("20060102_150405")
+
+	if params.Destination == "gcs" {
+		body, contentType, err := encodeExportRows(data.rows, params.Kind, params.Format)
+		if err != nil {
+			return nil, err
+		}
+		bucket := strings.TrimPrefix(cfg.GCSBucket, "gs://")
+		object := fmt.Sprintf("%s%s-%s-%s.%s", exportObjectPrefix, params.Kind, params.ProjectID, stamp, params.Format)
+		obj := &storage.Object{Name: object, ContentType: contentType}
+		if _, err := c.storage.Objects.Insert(bucket, obj).Media(bytes.NewReader(body)).Context(ctx).Do(); err != nil {
+			return nil, fmt.Errorf("failed to upload to gs://%s: %w", bucket, err)
+		}
+		result.URI = fmt.Sprintf("gs://%s/%s", bucket, object)
+		return result, nil
+	}
+
+	// BigQuery: NDJSON を読み込みジョブでテーブルに入れる（テーブルは書き出しごとに作る）
+	body, _, err := encodeExportRows(data.rows, params.Kind, "ndjson")
+	if err != nil {
+		return nil, err
+	}
+	project, dataset := splitDataset(cfg.BigQueryDataset)
+	table := fmt.Sprintf("mcp_export_%s_%s", params.Kind, stamp)
+	job := &bigquery.Job{
+		Configuration: &bigquery.JobConfiguration{
+			Labels: map[string]string{"created-by": "gcp-ops-mcp"},
+			Load: &bigquery.JobConfigurationLoad{
+				DestinationTable:  &bigquery.TableReference{ProjectId: project, DatasetId: dataset, TableId: table},
+				Schema:            &bigquery.TableSchema{Fields: exportSchemas[params.Kind]},
+				SourceFormat:      "NEWLINE_DELIMITED_JSON",
+				CreateDisposition: "CREATE_IF_NEEDED",
+				WriteDisposition:  "WRITE_EMPTY",
+			},
+		},
+	}
+	inserted, err := c.bigquery.Jobs.Insert(project, job).Media(bytes.NewReader(body)).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start BigQuery load job: %w", err)
+	}
+	if err := c.waitBigQueryJob(ctx, project, inserted); err != nil {
+		return nil, err
+	}
+	result.URI = fmt.Sprintf("bq://%s.%s.%s", project, dataset, table)
+	result.JobID = inserted.JobReference.JobId
+	return result, nil
+}
+
+// waitBigQueryJob は読み込みジョブの完了を待ち、失敗していればエラーを返す
+func (c *Client) waitBigQueryJob(ctx context.Context, project string, job *bigquery.Job) error {
+	ctx, cancel := context.WithTimeout(ctx, exportJobTimeout)
+	defer cancel()
+	for {
+		if job.Status != nil && job.Status.State == "DONE" {
+			if job.Status.ErrorResult != nil {
+				return fmt.Errorf("BigQuery load job %s failed: %s", job.JobReference.JobId, job.Status.ErrorResult.Message)
+			}
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("BigQuery load job %s did not finish: %w", job.JobReference.JobId, ctx.Err())
+		case <-time.After(2 * time.Second):
+		}
+		var err error
+		job, err = c.bigquery.Jobs.Get(project, job.JobReference.JobId).Location(job.JobReference.Location).Context(ctx).Do()
+		if err != nil {
+			return fmt.Errorf("failed to get BigQuery job: %w", err)
+		}
+	}
+}
+
+// exportLogRows はログを上限まで読み、行に変換する
+func (c *Client) exportLogRows(ctx context.Context, params ExportResultParams) (*exportRows, error) {
+	result, err := c.logging.QueryAll(ctx, logging.QueryParams{
+		ProjectID: params.ProjectID,
+		Filter:    params.Filter,
+		TimeRange: logging.TimeRange(params.TimeRange),
+	}, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+	out := &exportRows{
+		rows:      make([]map[string]any, 0, len(result.Entries)),
+		start:     result.QueryMeta.Start,
+		end:       result.QueryMeta.End,
+		truncated: len(result.Entries) >= params.Limit,
+	}
+	for _, e := range result.Entries {
+		out.rows = append(out.rows, map[string]any{
+			"timestamp":       e.Timestamp,
+			"severity":        e.Severity,
+			"log_name":        e.LogName,
+			"insert_id":       e.InsertID,
+			"trace":           e.Trace,
+			"span_id":         e.SpanID,
+			"resource_type":   e.Resource.Type,
+			"resource_labels": e.Resource.Labels,
+			"labels":          e.Labels,
+			"text_payload":    e.TextPayload,
+			"json_payload":    e.JSONPayload,
+		})
+	}
+	return out, nil
+}
+
+// exportMetricRows は時系列を上限まで読み、ポイントごとの行に変換する
+func (c *Client) exportMetricRows(ctx context.Context, params ExportResultParams) (*exportRows, error) {
+	result, err := c.monitoring.QueryTimeSeriesAll(ctx, monitoring.QueryTimeSeriesParams{
+		ProjectID:          params.ProjectID,
+		MetricType:         params.MetricType,
+		ResourceType:       params.ResourceType,
+		Filter:             params.Filter,
+		AlignmentPeriodSec: params.AlignmentPeriodSec,
+		PerSeriesAligner:   params.PerSeriesAligner,
+		CrossSeriesReducer: params.CrossSeriesReducer,
+		GroupByFields:      params.GroupByFields,
+		TimeRange:          params.TimeRange,
+	}, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+	out := &exportRows{
+		rows:      []map[string]any{},
+		start:     result.QueryMeta.Start,
+		end:       result.QueryMeta.End,
+		series:    len(result.Series),
+		truncated: len(result.Series) >= params.Limit,
+	}
+	for _, ts := range result.Series {
+		for _, p := range ts.Points {
+			out.rows = append(out.rows, map[string]any{
+				"time":            p.Time,
+				"value":           p.Value,
+				"metric_type":     ts.Metric.Type,
+				"metric_labels":   ts.Metric.Labels,
+				"resource_type":   ts.Resource.Type,
+				"resource_labels": ts.Resource.Labels,
+			})
+		}
+	}
+	return out, nil
+}
+
+// exportColumns は kind の列名
+func exportColumns(kind string) []string {
+	if kind == "logs" {
+		return logExportColumns
+	}
+	return metricExportColumns
+}
+
+// encodeExportRows は行を NDJSON か CSV にする（CSV ではネストした値を JSON 文字列にする）
+func encodeExportRows(rows []map[string]any, kind, format string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if format == "csv" {
+		columns := exportColumns(kind)
+		w := csv.NewWriter(&buf)
+		if err := w.Write(columns); err != nil {
+			return nil, "", err
+		}
+		for _, row := range rows {
+			record := make([]string, len(columns))
+			for i, col := range columns {
+				record[i] = csvValue(row[col])
+			}
+			if err := w.Write(record); err != nil {
+				return nil, "", err
+			}
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return nil, "", fmt.Errorf("failed to encode csv: %w", err)
+		}
+		return buf.Bytes(), "text/csv", nil
+	}
+
+	enc := json.NewEncoder(&buf)
+	for _, row := range rows {
+		// 空の値は省く（BigQuery では NULL になる）
+		for k, v := range row {
+			if isEmptyExportValue(v) {
+				delete(row, k)
+			}
+		}
+		if err := enc.Encode(row); err != nil {
+			return nil, "", fmt.Errorf("failed to encode row: %w", err)
+		}
+	}
+	return buf.Bytes(), "application/x-ndjson", nil
+}
+
+// csvValue は CSV のセルの文字列（マップは JSON にする）
+func csvValue(v any) string {
+	if isEmptyExportValue(v) {
+		return ""
+	}
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return fmt.Sprintf("%g", v)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	}
+}
+
+func isEmptyExportValue(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case map[string]string:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
+
+// splitDataset は "project.dataset" を分ける（ドメイン付きプロジェクト "example.com:proj" を考慮して最後の "." で分ける）
+func splitDataset(s string) (project, dataset string) {
+	i := strings.LastIndex(s, ".")
+	return s[:i], s[i+1:]
+}
+
+// ExportResultHandlerWithGuardrail returns a handler with guardrail validation and confirmation
+func (c *Client) ExportResultHandlerWithGuardrail(v Validator, w WriteValidator, cfg config.Export) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params ExportResultParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.ProjectID == "" {
+			return nil, fmt.Errorf("project_id is required")
+		}
+		switch params.Kind {
+		case "logs":
+		case "metrics":
+			if params.MetricType == "" {
+				return nil, fmt.Errorf("metric_type is required for kind metrics")
+			}
+		default:
+			return nil, fmt.Errorf("unsupported kind: %q (supported: logs, metrics)", params.Kind)
+		}
+
+		// 書き出し先: 省略時は設定されているほう（両方なら GCS）
+		if params.Destination == "" {
+			params.Destination = "gcs"
+			if cfg.GCSBucket == "" {
+				params.Destination = "bigquery"
+			}
+		}
+		switch params.Destination {
+		case "gcs":
+			if cfg.GCSBucket == "" {
+				return nil, fmt.Errorf("export.gcs_bucket is not configured")
+			}
+			if params.Format == "" {
+				params.Format = "ndjson"
+			}
+			if params.Format != "ndjson" && params.Format != "csv" {
+				return nil, fmt.Errorf("unsupported format: %q (supported: ndjson, csv)", params.Format)
+			}
+		case "bigquery":
+			if cfg.BigQueryDataset == "" {
+				return nil, fmt.Errorf("export.bigquery_dataset is not configured")
+			}
+			if params.Format != "" && params.Format != "ndjson" {
+				return nil, fmt.Errorf("format is only for destination gcs (BigQuery tables are loaded from NDJSON)")
+			}
+			params.Format = "ndjson"
+		default:
+			return nil, fmt.Errorf("unsupported destination: %q (supported: gcs, bigquery)", params.Destination)
+		}
+
+		// ガードレール: プロジェクトID検証
+		if err := v.ValidateProjectID(ctx, params.ProjectID); err != nil {
+			return nil, err
+		}
+
+		// ガードレール: 時間範囲検証
+		startTime, endTime, err := monitoring.ParseTimeRange(params.TimeRange)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse time range: %w", err)
+		}
+		if err := v.ValidateTimeRange(ctx, startTime, endTime); err != nil {
+			return nil, err
+		}
+
+		// 件数の上限は通常のクエリ（limits.*）ではなく書き出し用の上限（export.*）
+		exportCap := cfg.MaxLogEntries
+		if params.Kind == "metrics" {
+			exportCap = cfg.MaxTimeSeries
+		}
+		if params.Limit <= 0 || params.Limit > exportCap {
+			params.Limit = exportCap
+		}
+
+		// 確認トークンは実行内容（トークン以外の引数）に紐づける
+		token := params.ConfirmToken
+		params.ConfirmToken = ""
+
+		// ガードレール: 確認トークンがなければプレビューのみ返す
+		if token == "" {
+			issued, err := w.IssueConfirmToken("ops.export_result", params)
+			if err != nil {
+				return nil, err
+			}
+			unit := "log entries"
+			if params.Kind == "metrics" {
+				unit = "time series"
+			}
+			target := fmt.Sprintf("gs://%s/%s", strings.TrimPrefix(cfg.GCSBucket, "gs://"), exportObjectPrefix)
+			if params.Destination == "bigquery" {
+				target = "a new table in " + cfg.BigQueryDataset
+			}
+			return &ExportResultResult{
+				Executed:     false,
+				Preview:      fmt.Sprintf("Will export up to %d %s (%s to %s) as %s to %s. Call again with the same arguments and confirm_token to execute.", params.Limit, unit, startTime.Format(time.RFC3339), endTime.Format(time.RFC3339), params.Format, target),
+				ConfirmToken: issued,
+				Destination:  params.Destination,
+				Format:       params.Format,
+				Columns:      exportColumns(params.Kind),
+			}, nil
+		}
+		if err := w.VerifyConfirmToken(token, "ops.export_result", params); err != nil {
+			return nil, err
+		}
+
+		return c.ExportResult(ctx, params, cfg, time.Now())
+	}
+}
@@ -0,0 +1,140 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+)
+
+// FunctionsOverviewParams are the parameters for ops.functions_overview
+type FunctionsOverviewParams struct {
+	ProjectID          string               `json:"project_id"`
+	FunctionName       string               `json:"function_name"`
+	Region             string               `json:"region,omitempty"`
+	Generation         string               `json:"generation"` // "gen2" (Cloud Run functions, default) or "gen1"
+	TimeRange          monitoring.TimeRange `json:"time_range"`
+	AlignmentPeriodSec int                  `json:"alignment_period_sec"`
+	Limit              int                  `json:"limit"` // Max crash logs
+}
+
+// FunctionsOverviewResult is the result of ops.functions_overview
+type FunctionsOverviewResult struct {
+	QueryMeta FunctionsQueryMeta `json:"query_meta"`
+	Summary   FunctionsSummary   `json:"summary"`
+	Signals   []Signal           `json:"signals"`
+	CrashLogs []logging.LogEntry `json:"crash_logs"`
+	Errors    map[string]string  `json:"errors,omitempty"`
+}
+
+type FunctionsQueryMeta struct {
+	OverviewQueryMeta
+	Generation string `json:"generation"`
+}
+
+type FunctionsSummary struct {
+	ErrorRate        *float64 `json:"error_rate,omitempty"` // errors / executions over the window
+	ColdStartTracked bool     `json:"cold_start_tracked"`   // whether cold-start latency is available
+}
+
+// FunctionsOverview returns execution, error and latency signals plus crash logs for a function
+func (c *Client) FunctionsOverview(ctx context.Context, params FunctionsOverviewParams) (*FunctionsOverviewResult, error) {
+	startTime, endTime, err := monitoring.ParseTimeRange(params.TimeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse time range: %w", err)
+	}
+
+	generation := params.Generation
+	if generation == "" {
+		generation = "gen2"
+	}
+
+	// 第2世代（Cloud Run functions）は cloud_run_revision、第1世代は cloud_function のメトリクスを使う
+	var kind config.ResourceKind
+	switch generation {
+	case "gen2":
+		kind = c.kinds["cloud_functions_gen2"]
+	case "gen1":
+		kind = c.kinds["cloud_functions_gen1"]
+	default:
+		return nil, fmt.Errorf("unsupported generation: %s (supported: gen1, gen2)", generation)
+	}
+
+	filter := kindFilter(kind, params.FunctionName, params.Region)
+	signals := c.querySignals(ctx, params.ProjectID, kind, filter, params.TimeRange, params.AlignmentPeriodSec, 5)
+
+	result := &FunctionsOverviewResult{
+		QueryMeta: FunctionsQueryMeta{
+			OverviewQueryMeta: OverviewQueryMeta{
+				ProjectID: params.ProjectID,
+				Target:    params.FunctionName,
+				Start:     startTime.Format(time.RFC3339),
+				End:       endTime.Format(time.RFC3339),
+			},
+			Generation: generation,
+		},
+		Summary: FunctionsSummary{
+			ErrorRate:        errorRate(signals, "executions", "errors"),
+			ColdStartTracked: generation == "gen2",
+		},
+		Signals:   signals,
+		CrashLogs: []logging.LogEntry{},
+	}
+
+	logs, err := c.queryKindLogs(ctx, params.ProjectID, kind, params.FunctionName, filter, params.TimeRange, params.Limit)
+	if err != nil {
+		result.Errors = map[string]string{"crash_logs": err.Error()}
+	} else {
+		result.CrashLogs = logs
+	}
+
+	return result, nil
+}
+
+// errorRate は同じアライメントのレート系列から エラー数 / 総数 を求める
+func errorRate(signals []Signal, totalName, errorName string) *float64 {
+	var total, errs float64
+	for _, s := range signals {
+		for _, ts := range s.Series {
+			for _, p := range ts.Points {
+				switch s.Name {
+				case totalName:
+					total += p.Value
+				case errorName:
+					errs += p.Value
+				}
+			}
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+	rate := errs / total
+	return &rate
+}
+
+// FunctionsOverviewHandlerWithGuardrail returns a handler with guardrail validation
+func (c *Client) FunctionsOverviewHandlerWithGuardrail(v Validator) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params FunctionsOverviewParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.FunctionName == "" {
+			return nil, fmt.Errorf("function_name is required")
+		}
+
+		// ガードレール: 件数制限
+		if params.Limit <= 0 {
+			params.Limit = 20
+		}
+		params.Limit = v.ClampLogLimit(ctx, params.Limit)
+
+		return c.FunctionsOverview(ctx, params)
+	}
+}
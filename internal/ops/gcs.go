@@ -0,0 +1,209 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/code"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+)
+
+// GCSOverviewParams are the parameters for ops.gcs_overview
+type GCSOverviewParams struct {
+	ProjectID  string               `json:"project_id"`
+	Bucket     string               `json:"bucket"`
+	ErrorsOnly *bool                `json:"errors_only,omitempty"` // Only aggregate failed requests from the audit logs (default: true)
+	TimeRange  monitoring.TimeRange `json:"time_range"`
+	Limit      int                  `json:"limit"` // Top callers and sample logs
+}
+
+// GCSOverviewResult is the result of ops.gcs_overview
+type GCSOverviewResult struct {
+	QueryMeta  GCSQueryMeta       `json:"query_meta"`
+	Requests   []GCSRequestCount  `json:"requests"`    // By method and response code, most first
+	TopCallers []GCSCaller        `json:"top_callers"` // From the data access audit logs
+	SampleLogs []logging.LogEntry `json:"sample_logs"`
+	Stats      GCSStats           `json:"stats"`
+	Errors     map[string]string  `json:"errors,omitempty"` // section -> error
+}
+
+type GCSQueryMeta struct {
+	OverviewQueryMeta
+	AuditFilter string `json:"audit_filter"` // Reusable with logging.query
+}
+
+type GCSRequestCount struct {
+	Method       string `json:"method"`
+	ResponseCode string `json:"response_code"`
+	Count        int64  `json:"count"`
+}
+
+// GCSCaller は監査ログの呼び出し元（プリンシパルと送信元 IP）ごとの集計
+type GCSCaller struct {
+	Principal string         `json:"principal"`
+	CallerIP  string         `json:"caller_ip,omitempty"`
+	UserAgent string         `json:"user_agent,omitempty"` // Of the latest request
+	Requests  int            `json:"requests"`
+	Errors    int            `json:"errors"`
+	Methods   map[string]int `json:"methods"`            // e.g. "storage.objects.get"
+	Statuses  map[string]int `json:"statuses,omitempty"` // Failed requests by status (e.g. "PERMISSION_DENIED")
+}
+
+type GCSStats struct {
+	TotalRequests int64    `json:"total_requests"`
+	ErrorRequests int64    `json:"error_requests"`
+	ErrorRate     *float64 `json:"error_rate,omitempty"`
+	ScannedLogs   int      `json:"scanned_logs"`
+	// データアクセス監査ログが無効なバケットはログが0件になる
+	AuditLogsFound bool `json:"audit_logs_found"`
+}
+
+// gcsMaxScan は呼び出し元の集計のために走査する監査ログの上限
+const gcsMaxScan = 5000
+
+// GCSOverview aggregates a bucket's request counts by method and response code and its data access
+// audit logs by caller, to find who is sending the (failing) requests
+func (c *Client) GCSOverview(ctx context.Context, params GCSOverviewParams) (*GCSOverviewResult, error) {
+	startTime, endTime, err := monitoring.ParseTimeRange(params.TimeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse time range: %w", err)
+	}
+
+	// リクエスト数（メソッド・レスポンスコード別）とデータアクセス監査ログ（リソース種別 gcs_bucket）
+	kind := c.kinds["gcs_bucket"]
+	auditFilter, err := kindLogFilter(kind, params.ProjectID, params.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	if params.ErrorsOnly == nil || *params.ErrorsOnly {
+		auditFilter += " AND protoPayload.status.code > 0"
+	}
+
+	result := &GCSOverviewResult{
+		QueryMeta: GCSQueryMeta{
+			OverviewQueryMeta: OverviewQueryMeta{
+				ProjectID: params.ProjectID,
+				Target:    params.Bucket,
+				Start:     startTime.Format(time.RFC3339),
+				End:       endTime.Format(time.RFC3339),
+			},
+			AuditFilter: auditFilter,
+		},
+		Requests:   []GCSRequestCount{},
+		TopCallers: []GCSCaller{},
+		SampleLogs: []logging.LogEntry{},
+	}
+	errs := map[string]string{}
+
+	// 期間全体の1点に集約する（メソッド × レスポンスコードの合計だけが要る）
+	alignment := max(int(endTime.Sub(startTime).Seconds()), 60)
+	signals := c.querySignals(ctx, params.ProjectID, kind, kindFilter(kind, params.Bucket, ""), params.TimeRange, alignment, 100)
+	for _, signal := range signals {
+		if signal.Error != "" {
+			errs[signal.Name] = signal.Error
+		}
+	}
+	for _, ts := range signalSeries(signals, "requests") {
+		rc := GCSRequestCount{Method: ts.Metric.Labels["method"], ResponseCode: ts.Metric.Labels["response_code"]}
+		for _, p := range ts.Points {
+			rc.Count += int64(p.Value)
+		}
+		result.Stats.TotalRequests += rc.Count
+		if rc.ResponseCode != "OK" {
+			result.Stats.ErrorRequests += rc.Count
+		}
+		result.Requests = append(result.Requests, rc)
+	}
+	sort.Slice(result.Requests, func(i, j int) bool { return result.Requests[i].Count > result.Requests[j].Count })
+	if result.Stats.TotalRequests > 0 {
+		rate := float64(result.Stats.ErrorRequests) / float64(result.Stats.TotalRequests)
+		result.Stats.ErrorRate = &rate
+	}
+
+	callers := map[string]*GCSCaller{}
+	scanned, err := c.logging.ScanEntries(ctx, params.ProjectID, auditFilter, startTime, endTime, gcsMaxScan, func(e logging.LogEntry) {
+		p := e.ProtoPayload
+		auth, _ := p["authenticationInfo"].(map[string]any)
+		meta, _ := p["requestMetadata"].(map[string]any)
+		principal := payloadString(auth["principalEmail"])
+		if principal == "" {
+			principal = "(anonymous)"
+		}
+		ip := payloadString(meta["callerIp"])
+
+		key := principal + "|" + ip
+		caller, ok := callers[key]
+		if !ok {
+			caller = &GCSCaller{Principal: principal, CallerIP: ip, Methods: map[string]int{}}
+			callers[key] = caller
+		}
+		caller.Requests++
+		caller.Methods[payloadString(p["methodName"])]++
+		if caller.UserAgent == "" {
+			caller.UserAgent = payloadString(meta["callerSuppliedUserAgent"]) // 新しい順
+		}
+		status, _ := p["status"].(map[string]any)
+		if n, _ := status["code"].(float64); n > 0 {
+			caller.Errors++
+			if caller.Statuses == nil {
+				caller.Statuses = map[string]int{}
+			}
+			caller.Statuses[code.Code(n).String()]++
+		}
+
+		if len(result.SampleLogs) < params.Limit {
+			result.SampleLogs = append(result.SampleLogs, e)
+		}
+	})
+	if err != nil {
+		errs["audit_logs"] = err.Error()
+	}
+	result.Stats.ScannedLogs = scanned
+	result.Stats.AuditLogsFound = scanned > 0
+
+	for _, caller := range callers {
+		result.TopCallers = append(result.TopCallers, *caller)
+	}
+	sort.Slice(result.TopCallers, func(i, j int) bool {
+		a, b := result.TopCallers[i], result.TopCallers[j]
+		if a.Errors != b.Errors {
+			return a.Errors > b.Errors
+		}
+		return a.Requests > b.Requests
+	})
+	if len(result.TopCallers) > params.Limit {
+		result.TopCallers = result.TopCallers[:params.Limit]
+	}
+
+	if len(errs) > 0 {
+		result.Errors = errs
+	}
+	return result, nil
+}
+
+// GCSOverviewHandlerWithGuardrail returns a handler with guardrail validation
+func (c *Client) GCSOverviewHandlerWithGuardrail(v Validator) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params GCSOverviewParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.Bucket == "" {
+			return nil, fmt.Errorf("bucket is required")
+		}
+
+		// ガードレール: 件数制限
+		if params.Limit <= 0 {
+			params.Limit = 20
+		}
+		params.Limit = v.ClampLogLimit(ctx, params.Limit)
+
+		return c.GCSOverview(ctx, params)
+	}
+}
@@ -0,0 +1,253 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+)
+
+// GoldenSignalsParams are the parameters for ops.golden_signals
+type GoldenSignalsParams struct {
+	ProjectID          string               `json:"project_id"`
+	Kind               string               `json:"kind"` // "cloud_run", "gke_workload", "http_lb" or a kind added in resource_kinds
+	Name               string               `json:"name"` // Service / workload / URL map name
+	TimeRange          monitoring.TimeRange `json:"time_range"`
+	AlignmentPeriodSec int                  `json:"alignment_period_sec"`
+	MaxSeries          int                  `json:"max_series"`       // Per signal
+	Render             string               `json:"render,omitempty"` // "points" (default) or "chart"
+}
+
+// GoldenSignalsResult is the result of ops.golden_signals
+type GoldenSignalsResult struct {
+	QueryMeta GoldenSignalsQueryMeta `json:"query_meta"`
+	Signals   []Signal               `json:"signals"`
+}
+
+type GoldenSignalsQueryMeta struct {
+	ProjectID string `json:"project_id"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Start     string `json:"start"`
+	End       string `json:"end"`
+}
+
+type Signal struct {
+	Name         string                  `json:"name"` // "traffic", "errors", "latency", "saturation"
+	MetricType   string                  `json:"metric_type"`
+	ResourceType string                  `json:"resource_type"`
+	Aligner      string                  `json:"aligner"`
+	Reducer      string                  `json:"reducer,omitempty"`
+	Series       []monitoring.TimeSeries `json:"series"`
+	Error        string                  `json:"error,omitempty"`
+}
+
+// querySignals は種別の各シグナルの時系列を取得する
+// 一部のメトリクスが存在しなくても他のシグナルは返す（エラーはSignal.Errorに記録）
+func (c *Client) querySignals(ctx context.Context, projectID string, kind config.ResourceKind, baseFilter string,
+	tr monitoring.TimeRange, alignmentPeriodSec, maxSeries int) []Signal {
+	signals := []Signal{}
+	for _, spec := range kind.Signals {
+		rt := spec.ResourceType
+		if rt == "" {
+			rt = kind.ResourceType
+		}
+		filter := baseFilter
+		if spec.Filter != "" {
+			if filter != "" {
+				filter += " AND "
+			}
+			filter += spec.Filter
+		}
+
+		signal := Signal{
+			Name:         spec.Name,
+			MetricType:   spec.MetricType,
+			ResourceType: rt,
+			Aligner:      spec.Aligner,
+			Reducer:      spec.Reducer,
+			Series:       []monitoring.TimeSeries{},
+		}
+
+		result, err := c.monitoring.QueryTimeSeries(ctx, monitoring.QueryTimeSeriesParams{
+			ProjectID:          projectID,
+			MetricType:         spec.MetricType,
+			ResourceType:       rt,
+			Filter:             filter,
+			AlignmentPeriodSec: alignmentPeriodSec,
+			PerSeriesAligner:   spec.Aligner,
+			CrossSeriesReducer: spec.Reducer,
+			GroupByFields:      spec.GroupBy,
+			TimeRange:          tr,
+			MaxSeries:          maxSeries,
+		})
+		if err != nil {
+			signal.Error = err.Error()
+		} else {
+			signal.Series = result.Series
+		}
+
+		signals = append(signals, signal)
+	}
+	return signals
+}
+
+// signalSeries は name のシグナルの系列を返す（定義から外されていれば nil）
+func signalSeries(signals []Signal, name string) []monitoring.TimeSeries {
+	for _, s := range signals {
+		if s.Name == name {
+			return s.Series
+		}
+	}
+	return nil
+}
+
+// kindFilter は種別の名前・ロケーションのラベルで絞るフィルタを返す（空の値・ラベルは条件にしない）
+func kindFilter(kind config.ResourceKind, name, location string) string {
+	filter := ""
+	if kind.NameLabel != "" {
+		filter = joinFilter(filter, name, kind.NameLabel+` = "%s"`)
+	}
+	if kind.LocationLabel != "" {
+		filter = joinFilter(filter, location, kind.LocationLabel+` = "%s"`)
+	}
+	return filter
+}
+
+// kindLogFilter は種別のログのフィルタの {{name}} と {{project_id}} を置換する
+func kindLogFilter(kind config.ResourceKind, projectID, name string) (string, error) {
+	if kind.LogFilter == "" {
+		return "", fmt.Errorf("resource kind %s has no log_filter", kind.ResourceType)
+	}
+	filter, err := substitute(kind.LogFilter, map[string]string{"name": name, "project_id": projectID})
+	if err != nil {
+		return "", fmt.Errorf("invalid log_filter of resource kind: %w", err)
+	}
+	return filter, nil
+}
+
+// queryKindLogs は種別のログのフィルタに filter を AND したログを取得する
+func (c *Client) queryKindLogs(ctx context.Context, projectID string, kind config.ResourceKind, name, filter string,
+	tr monitoring.TimeRange, limit int) ([]logging.LogEntry, error) {
+	logFilter, err := kindLogFilter(kind, projectID, name)
+	if err != nil {
+		return nil, err
+	}
+	return c.queryLogs(ctx, projectID, joinFilter(logFilter, filter, "%s"), tr, limit)
+}
+
+// goldenSignalKind は ops.golden_signals / ops.generate_report に指定できる種別の定義を返す
+func (c *Client) goldenSignalKind(name string) (config.ResourceKind, error) {
+	kind, ok := c.kinds[name]
+	if !ok || !kind.GoldenSignals {
+		return config.ResourceKind{}, fmt.Errorf("unsupported kind: %s (supported: %v)", name, c.kinds.GoldenSignalKinds())
+	}
+	return kind, nil
+}
+
+// GoldenSignals fetches traffic, errors, latency and saturation series for a resource
+func (c *Client) GoldenSignals(ctx context.Context, params GoldenSignalsParams) (*GoldenSignalsResult, error) {
+	kind, err := c.goldenSignalKind(params.Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	startTime, endTime, err := monitoring.ParseTimeRange(params.TimeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse time range: %w", err)
+	}
+
+	signals := c.querySignals(ctx, params.ProjectID, kind, kindFilter(kind, params.Name, ""),
+		params.TimeRange, params.AlignmentPeriodSec, params.MaxSeries)
+
+	return &GoldenSignalsResult{
+		QueryMeta: GoldenSignalsQueryMeta{
+			ProjectID: params.ProjectID,
+			Kind:      params.Kind,
+			Name:      params.Name,
+			Start:     startTime.Format(time.RFC3339),
+			End:       endTime.Format(time.RFC3339),
+		},
+		Signals: signals,
+	}, nil
+}
+
+// GoldenSignalsHandlerWithGuardrail returns a handler with guardrail validation
+func (c *Client) GoldenSignalsHandlerWithGuardrail(v Validator) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params GoldenSignalsParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.Kind == "" {
+			return nil, fmt.Errorf("kind is required")
+		}
+		if params.Name == "" {
+			return nil, fmt.Errorf("name is required")
+		}
+
+		// ガードレール: 系列数制限
+		params.MaxSeries = v.ClampTimeSeriesLimit(ctx, params.MaxSeries)
+
+		if params.Render != "" && params.Render != "points" && params.Render != "chart" {
+			return nil, fmt.Errorf("unsupported render: %s (supported: points, chart)", params.Render)
+		}
+
+		result, err := c.GoldenSignals(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		if params.Render == "chart" {
+			return goldenSignalsChart(result)
+		}
+		return result, nil
+	}
+}
+
+// GoldenSignalsChartResult is the text part of ops.golden_signals with render="chart"
+type GoldenSignalsChartResult struct {
+	QueryMeta GoldenSignalsQueryMeta `json:"query_meta"`
+	Signals   []SignalChart          `json:"signals"`
+}
+
+type SignalChart struct {
+	Name       string                       `json:"name"`
+	MetricType string                       `json:"metric_type"`
+	Chart      *monitoring.Chart            `json:"chart,omitempty"` // Image order follows signals with a chart
+	Series     []monitoring.SparklineSeries `json:"series"`
+	Error      string                       `json:"error,omitempty"`
+}
+
+// goldenSignalsChart はシグナルごとのPNGチャートと、その凡例・要約のテキストを返す（structuredContent は元の結果）
+func goldenSignalsChart(result *GoldenSignalsResult) (mcp.Structured, error) {
+	summary := GoldenSignalsChartResult{QueryMeta: result.QueryMeta, Signals: []SignalChart{}}
+	var images mcp.Content
+	for _, s := range result.Signals {
+		sc := SignalChart{
+			Name:       s.Name,
+			MetricType: s.MetricType,
+			Series:     monitoring.ToSparklines(&monitoring.QueryTimeSeriesResult{Series: s.Series}).Series,
+			Error:      s.Error,
+		}
+		if len(s.Series) > 0 {
+			data, chart, err := monitoring.RenderChart(s.Name+": "+s.MetricType, s.Series)
+			if err == nil {
+				sc.Chart = &chart
+				images = append(images, mcp.Image(data, "image/png"))
+			}
+		}
+		summary.Signals = append(summary.Signals, sc)
+	}
+
+	text, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return mcp.Structured{}, fmt.Errorf("failed to marshal chart summary: %w", err)
+	}
+	return mcp.Structured{Content: append(mcp.Text(string(text)), images...), Result: result}, nil
+}
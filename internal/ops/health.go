@@ -0,0 +1,338 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v3"
+	"google.golang.org/api/option"
+	"google.golang.org/api/transport"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+)
+
+// HealthParams are the parameters for ops.health
+type HealthParams struct {
+	ProjectID string `json:"project_id,omitempty"` // 省略時は認証情報と設定のみ確認
+}
+
+// HealthResult is the result of ops.health
+type HealthResult struct {
+	OK          bool              `json:"ok"`
+	Credentials CredentialsCheck  `json:"credentials"`
+	ProjectID   string            `json:"project_id,omitempty"`
+	Permissions *PermissionsCheck `json:"permissions,omitempty"`
+	APIs        []APICheck        `json:"apis,omitempty"`
+	Config      HealthConfig      `json:"config"`
+	Problems    []string          `json:"problems"` // What to fix, in order of importance
+	Notes       []string          `json:"notes,omitempty"`
+}
+
+type CredentialsCheck struct {
+	OK           bool   `json:"ok"`
+	Type         string `json:"type,omitempty"`          // "service_account", "authorized_user", "external_account", "metadata_server"
+	Principal    string `json:"principal,omitempty"`     // Email (when the token exposes it)
+	QuotaProject string `json:"quota_project,omitempty"` // Project of the credentials / ADC quota project
+	TokenExpiry  string `json:"token_expiry,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+type PermissionsCheck struct {
+	Granted []string            `json:"granted"`
+	Missing []MissingPermission `json:"missing"`
+	Error   string              `json:"error,omitempty"`
+}
+
+type MissingPermission struct {
+	Permission string   `json:"permission"`
+	Tools      []string `json:"tools"` // Tools that need the permission
+}
+
+type APICheck struct {
+	API       string `json:"api"`
+	OK        bool   `json:"ok"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+type HealthConfig struct {
+	Mode              string        `json:"mode"`
+	WriteToolsEnabled bool          `json:"write_tools_enabled"`
+	Limits            config.Limits `json:"limits"`
+	AllowedProjects   int           `json:"allowed_project_rules"` // 0 = all projects (unless denied)
+	DefaultProjectID  string        `json:"default_project_id,omitempty"`
+}
+
+// healthPermissions は確認するIAM権限と、その権限を必要とするツール
+var healthPermissions = map[string][]string{
+	"logging.logEntries.list":                                    {"logging.query", "logging.top_errors", "logging.count", "gke.query_events", "gke.crash_report", "monitoring.explain_spike", "ops.*"},
+	"monitoring.timeSeries.list":                                 {"monitoring.query_time_series", "monitoring.list_label_values", "monitoring.evaluate_threshold", "monitoring.forecast", "monitoring.backtest_alert_policy", "monitoring.explain_spike", "ops.golden_signals", "gke.crash_report", "ops.*"},
+	"monitoring.metricDescriptors.list":                          {"monitoring.list_metric_descriptors"},
+	"monitoring.groups.list":                                     {"monitoring.list_groups"},
+	"monitoring.services.list":                                   {"monitoring.list_services", "ops.generate_report"},
+	"monitoring.snoozes.list":                                    {"monitoring.list_snoozes"},
+	"monitoring.snoozes.create":                                  {"monitoring.create_snooze"},
+	"monitoring.timeSeries.create":                               {"monitoring.write_custom_metric"},
+	"monitoring.alertPolicies.get":                               {"monitoring.backtest_alert_policy"},
+	"logging.logMetrics.create":                                  {"logging.create_log_metric"},
+	"logging.logEntries.create":                                  {"logging.write_entry"},
+	"cloudasset.assets.searchAllResources":                       {"assets.search"},
+	"recommender.computeInstanceMachineTypeRecommendations.list": {"ops.list_recommendations"},
+	"cloudbuild.builds.list":                                     {"ops.recent_deployments", "ops.generate_report"},
+	"clouddeploy.releases.list":                                  {"ops.recent_deployments", "ops.generate_report"},
+	"container.clusters.get":                                     {"gke.describe_cluster"},
+	"run.services.get":                                           {"run.describe_service"},
+	"resourcemanager.projects.get":                               {"ops.list_projects"},
+	"bigquery.jobs.create":                                       {"ops.cost_signal", "ops.bigquery_overview", "ops.export_result"},
+	"bigquery.tables.create":                                     {"ops.export_result"},
+	"storage.objects.create":                                     {"ops.export_result"},
+	"logging.privateLogEntries.list":                             {"ops.gcs_overview"},
+}
+
+// corePermissions は基本ツールに必須の権限（欠けていれば ok=false）
+var corePermissions = []string{"logging.logEntries.list", "monitoring.timeSeries.list"}
+
+// Health checks credentials, IAM permissions and API reachability.
+// credOpts are the credentials the API clients use (empty for Application Default Credentials)
+func (c *Client) Health(ctx context.Context, params HealthParams, cfg *config.Config, credOpts ...option.ClientOption) (*HealthResult, error) {
+	result := newHealthResult(params, cfg)
+
+	result.Credentials = checkCredentials(ctx, credOpts...)
+	if !result.Credentials.OK {
+		result.Problems = append(result.Problems, "credentials: "+result.Credentials.Error+credentialsHint(cfg))
+		return result, nil
+	}
+
+	if params.ProjectID == "" {
+		result.Notes = append(result.Notes, "project_id not given: permissions and API reachability were not checked")
+		result.OK = true
+		return result, nil
+	}
+
+	result.Permissions = c.checkPermissions(ctx, params.ProjectID)
+	if result.Permissions.Error != "" {
+		result.Problems = append(result.Problems, "permissions: "+result.Permissions.Error)
+	}
+	for _, m := range result.Permissions.Missing {
+		isCore := false
+		for _, core := range corePermissions {
+			isCore = isCore || m.Permission == core
+		}
+		if isCore {
+			result.Problems = append(result.Problems, fmt.Sprintf("missing %s (needed by %v)", m.Permission, m.Tools))
+		} else {
+			result.Notes = append(result.Notes, fmt.Sprintf("missing %s: %v will fail", m.Permission, m.Tools))
+		}
+	}
+
+	result.APIs = []APICheck{
+		timeAPICheck("logging.googleapis.com", func() error {
+			_, err := c.logging.Query(ctx, logging.QueryParams{
+				ProjectID: params.ProjectID,
+				TimeRange: logging.TimeRange{Start: "-5m"},
+				Limit:     1,
+			})
+			return err
+		}),
+		timeAPICheck("monitoring.googleapis.com", func() error {
+			_, err := c.monitoring.ListMetricDescriptors(ctx, monitoring.ListMetricDescriptorsParams{
+				ProjectID: params.ProjectID,
+				Limit:     1,
+			})
+			return err
+		}),
+	}
+	for _, a := range result.APIs {
+		if !a.OK {
+			result.Problems = append(result.Problems, fmt.Sprintf("%s: %s", a.API, a.Error))
+		}
+	}
+
+	result.OK = len(result.Problems) == 0
+	return result, nil
+}
+
+func newHealthResult(params HealthParams, cfg *config.Config) *HealthResult {
+	return &HealthResult{
+		ProjectID: params.ProjectID,
+		Config: HealthConfig{
+			Mode:              cfg.Mode,
+			WriteToolsEnabled: cfg.WriteEnabled(),
+			Limits:            cfg.Limits,
+			AllowedProjects:   len(cfg.AllowedProjectIDs) + len(cfg.AllowedFolders) + len(cfg.AllowedOrgs),
+			DefaultProjectID:  cfg.DefaultProjectID,
+		},
+		Problems: []string{},
+	}
+}
+
+// clientErrorHealth は API クライアントを作れなかった場合の結果（認証情報と設定のみ確認する）
+func clientErrorHealth(ctx context.Context, params HealthParams, cfg *config.Config, clientErr error, credOpts ...option.ClientOption) *HealthResult {
+	result := newHealthResult(params, cfg)
+	result.Credentials = checkCredentials(ctx, credOpts...)
+	if !result.Credentials.OK {
+		result.Problems = append(result.Problems, "credentials: "+result.Credentials.Error+credentialsHint(cfg))
+	}
+	result.Problems = append(result.Problems, "clients: "+clientErr.Error())
+	return result
+}
+
+// credentialsHint は認証情報が使えない場合の対処を返す
+func credentialsHint(cfg *config.Config) string {
+	if cfg.CredentialsFile != "" {
+		return " (check credentials_file " + cfg.CredentialsFile + ")"
+	}
+	return " (run `gcloud auth application-default login` or set GOOGLE_APPLICATION_CREDENTIALS)"
+}
+
+// checkCredentials は認証情報（credOpts が空なら ADC）を取得し、トークンが発行できるか確認する
+func checkCredentials(ctx context.Context, credOpts ...option.ClientOption) CredentialsCheck {
+	check, _ := inspectCredentials(ctx, credOpts...)
+	return check
+}
+
+// inspectCredentials は checkCredentials の結果と発行したアクセストークンを返す
+func inspectCredentials(ctx context.Context, credOpts ...option.ClientOption) (CredentialsCheck, string) {
+	check := CredentialsCheck{}
+	opts := append([]option.ClientOption{option.WithScopes("https://www.googleapis.com/auth/cloud-platform")}, credOpts...)
+	creds, err := transport.Creds(ctx, opts...)
+	if err != nil {
+		if len(credOpts) > 0 {
+			check.Error = fmt.Sprintf("invalid credentials: %v", err)
+		} else {
+			check.Error = fmt.Sprintf("no default credentials: %v", err)
+		}
+		return check, ""
+	}
+	check.QuotaProject = creds.ProjectID
+
+	check.Type = "metadata_server"
+	if len(creds.JSON) > 0 {
+		var f struct {
+			Type                           string `json:"type"`
+			ClientEmail                    string `json:"client_email"`
+			ServiceAccountImpersonationURL string `json:"service_account_impersonation_url"`
+		}
+		if json.Unmarshal(creds.JSON, &f) == nil {
+			check.Type = f.Type
+			check.Principal = f.ClientEmail
+			// Workload Identity 連携（external_account）はなりすまし先のサービスアカウントで動く
+			if check.Principal == "" {
+				check.Principal = impersonatedAccount(f.ServiceAccountImpersonationURL)
+			}
+		}
+	}
+
+	token, err := creds.TokenSource.Token()
+	if err != nil {
+		check.Error = fmt.Sprintf("failed to obtain access token: %v", err)
+		return check, ""
+	}
+	check.OK = true
+	if !token.Expiry.IsZero() {
+		check.TokenExpiry = token.Expiry.UTC().Format(time.RFC3339)
+	}
+
+	// 利用者アカウント・メタデータサーバーの場合はトークン情報からメールアドレスを取得（取れなくても失敗にしない）
+	if check.Principal == "" {
+		check.Principal = lookupTokenInfo(ctx, token.AccessToken).Email
+	}
+	return check, token.AccessToken
+}
+
+// impersonatedAccount はなりすまし URL（.../serviceAccounts/EMAIL:generateAccessToken）からサービスアカウントを返す（不明なら空）
+func impersonatedAccount(impersonationURL string) string {
+	_, rest, ok := strings.Cut(impersonationURL, "/serviceAccounts/")
+	if !ok {
+		return ""
+	}
+	email, _, _ := strings.Cut(rest, ":")
+	return email
+}
+
+// tokenInfo はアクセストークンの情報（oauth2.googleapis.com/tokeninfo）
+type tokenInfo struct {
+	Email     string `json:"email"`
+	Scope     string `json:"scope"`      // スペース区切り
+	ExpiresIn string `json:"expires_in"` // 残り秒数
+}
+
+// lookupTokenInfo はアクセストークンに紐づくメールアドレス・スコープを返す（取れなければ空）
+func lookupTokenInfo(ctx context.Context, accessToken string) tokenInfo {
+	var info tokenInfo
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		"https://oauth2.googleapis.com/tokeninfo?access_token="+url.QueryEscape(accessToken), nil)
+	if err != nil {
+		return info
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return info
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&info) != nil {
+		return tokenInfo{}
+	}
+	return info
+}
+
+// checkPermissions は testIamPermissions で関連する権限の付与状況を確認する
+func (c *Client) checkPermissions(ctx context.Context, projectID string) *PermissionsCheck {
+	check := &PermissionsCheck{Granted: []string{}, Missing: []MissingPermission{}}
+
+	perms := make([]string, 0, len(healthPermissions))
+	for p := range healthPermissions {
+		perms = append(perms, p)
+	}
+	sort.Strings(perms)
+
+	grantedList, err := c.TestPermissions(ctx, projectID, perms)
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+
+	granted := map[string]bool{}
+	for _, p := range grantedList {
+		granted[p] = true
+	}
+	for _, p := range perms {
+		if granted[p] {
+			check.Granted = append(check.Granted, p)
+		} else {
+			check.Missing = append(check.Missing, MissingPermission{Permission: p, Tools: healthPermissions[p]})
+		}
+	}
+	return check
+}
+
+// TestPermissions returns the permissions in perms that the credentials have on the project
+func (c *Client) TestPermissions(ctx context.Context, projectID string, perms []string) ([]string, error) {
+	resp, err := c.resourceMgr.Projects.TestIamPermissions("projects/"+projectID,
+		&cloudresourcemanager.TestIamPermissionsRequest{Permissions: perms}).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("testIamPermissions failed: %w", err)
+	}
+	return resp.Permissions, nil
+}
+
+// timeAPICheck はAPI呼び出しの成否とレイテンシを記録する
+func timeAPICheck(api string, call func() error) APICheck {
+	start := time.Now()
+	err := call()
+	check := APICheck{API: api, OK: err == nil, LatencyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		check.Error = err.Error()
+	}
+	return check
+}
@@ -0,0 +1,199 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+)
+
+// NATOverviewParams are the parameters for ops.nat_overview
+type NATOverviewParams struct {
+	ProjectID          string               `json:"project_id"`
+	Region             string               `json:"region,omitempty"`
+	Router             string               `json:"router,omitempty"`  // Cloud Router name
+	Gateway            string               `json:"gateway,omitempty"` // NAT gateway name
+	TimeRange          monitoring.TimeRange `json:"time_range"`
+	AlignmentPeriodSec int                  `json:"alignment_period_sec"`
+	MaxSeries          int                  `json:"max_series"` // Per signal
+	Limit              int                  `json:"limit"`      // Max NAT log entries
+}
+
+// NATOverviewResult is the result of ops.nat_overview
+type NATOverviewResult struct {
+	QueryMeta OverviewQueryMeta  `json:"query_meta"`
+	Gateways  []NATGateway       `json:"gateways"` // Most dropped packets first
+	Signals   []Signal           `json:"signals"`
+	Logs      []logging.LogEntry `json:"logs"`             // NAT logs of dropped connections (allocation_status = DROPPED)
+	Errors    map[string]string  `json:"errors,omitempty"` // section -> error
+}
+
+// NATGateway は NAT ゲートウェイごとの要約
+type NATGateway struct {
+	Gateway            string         `json:"gateway"`
+	Router             string         `json:"router"`
+	Region             string         `json:"region"`
+	AllocationFailures int            `json:"allocation_failures"`            // Intervals in which NAT IP/port allocation failed
+	DroppedSent        map[string]int `json:"dropped_sent_packets,omitempty"` // reason -> packets
+	DroppedReceived    int            `json:"dropped_received_packets"`
+	PeakPortUsage      float64        `json:"peak_port_usage"`      // Max ports used by one VM
+	PeakAllocatedPorts float64        `json:"peak_allocated_ports"` // Max ports allocated to one VM
+	PortUtilization    *float64       `json:"port_utilization,omitempty"`
+	DroppedLogs        int            `json:"dropped_logs"` // Among the returned logs
+	Findings           []string       `json:"findings,omitempty"`
+}
+
+// natPortUtilizationWarn は VM あたりのポート使用率がこれ以上なら枯渇しかけとみなす
+const natPortUtilizationWarn = 0.8
+
+// NATOverview returns Cloud NAT allocation errors, dropped packets and port usage per gateway
+// together with the NAT logs of dropped connections
+func (c *Client) NATOverview(ctx context.Context, params NATOverviewParams) (*NATOverviewResult, error) {
+	startTime, endTime, err := monitoring.ParseTimeRange(params.TimeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse time range: %w", err)
+	}
+
+	kind := c.kinds["nat_gateway"]
+	filter := joinFilter(kindFilter(kind, params.Gateway, params.Region), params.Router, `resource.labels.router_id = "%s"`)
+	signals := c.querySignals(ctx, params.ProjectID, kind, filter, params.TimeRange, params.AlignmentPeriodSec, params.MaxSeries)
+	result := &NATOverviewResult{
+		QueryMeta: OverviewQueryMeta{
+			ProjectID: params.ProjectID,
+			Target:    params.Gateway,
+			Start:     startTime.Format(time.RFC3339),
+			End:       endTime.Format(time.RFC3339),
+		},
+		Signals: signals,
+		Logs:    []logging.LogEntry{},
+	}
+
+	// NAT ログは接続単位（ログ記録を有効にしたゲートウェイのみ）。DROPPED はポート不足などで捨てた接続
+	logs, err := c.queryKindLogs(ctx, params.ProjectID, kind, params.Gateway, filter, params.TimeRange, params.Limit)
+	if err != nil {
+		result.Errors = map[string]string{"logs": err.Error()}
+	} else {
+		result.Logs = logs
+	}
+
+	result.Gateways = natGateways(signals, result.Logs)
+	return result, nil
+}
+
+// natGateways はシグナルとログをゲートウェイごとにまとめ、所見を付ける
+func natGateways(signals []Signal, logs []logging.LogEntry) []NATGateway {
+	gateways := map[string]*NATGateway{}
+	get := func(labels map[string]string) *NATGateway {
+		key := labels["region"] + "/" + labels["router_id"] + "/" + labels["gateway_name"]
+		if g, ok := gateways[key]; ok {
+			return g
+		}
+		g := &NATGateway{Gateway: labels["gateway_name"], Router: labels["router_id"], Region: labels["region"]}
+		gateways[key] = g
+		return g
+	}
+
+	for _, s := range signals {
+		for _, ts := range s.Series {
+			g := get(ts.Resource.Labels)
+			for _, p := range ts.Points {
+				switch s.Name {
+				case "allocation_failed":
+					if p.Value > 0 {
+						g.AllocationFailures++
+					}
+				case "dropped_sent_packets":
+					if p.Value > 0 {
+						if g.DroppedSent == nil {
+							g.DroppedSent = map[string]int{}
+						}
+						g.DroppedSent[ts.Metric.Labels["reason"]] += int(p.Value)
+					}
+				case "dropped_received_packets":
+					g.DroppedReceived += int(p.Value)
+				case "port_usage":
+					g.PeakPortUsage = max(g.PeakPortUsage, p.Value)
+				case "allocated_ports":
+					g.PeakAllocatedPorts = max(g.PeakAllocatedPorts, p.Value)
+				}
+			}
+		}
+	}
+	for _, e := range logs {
+		get(e.Resource.Labels).DroppedLogs++
+	}
+
+	result := make([]NATGateway, 0, len(gateways))
+	for _, g := range gateways {
+		if g.PeakAllocatedPorts > 0 {
+			u := g.PeakPortUsage / g.PeakAllocatedPorts
+			g.PortUtilization = &u
+		}
+		g.Findings = natFindings(g)
+		result = append(result, *g)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := natDropped(result[i]), natDropped(result[j])
+		if a != b {
+			return a > b
+		}
+		return result[i].Gateway < result[j].Gateway
+	})
+	return result
+}
+
+// natDropped はゲートウェイで捨てたパケットの合計
+func natDropped(g NATGateway) int {
+	total := g.DroppedReceived
+	for _, n := range g.DroppedSent {
+		total += n
+	}
+	return total
+}
+
+// natFindings は断続的な外向き通信のタイムアウトの原因になりやすい状態を挙げる
+func natFindings(g *NATGateway) []string {
+	var findings []string
+	if g.AllocationFailures > 0 {
+		findings = append(findings, fmt.Sprintf("NAT IP/port allocation failed in %d intervals: add NAT IPs or enable dynamic port allocation", g.AllocationFailures))
+	}
+	if n := g.DroppedSent["OUT_OF_RESOURCES"]; n > 0 {
+		findings = append(findings, fmt.Sprintf("%d packets dropped because VMs ran out of NAT ports: raise min ports per VM", n))
+	}
+	if n := g.DroppedSent["ENDPOINT_INDEPENDENT_CONFLICT"]; n > 0 {
+		findings = append(findings, fmt.Sprintf("%d packets dropped by endpoint-independent mapping conflicts: consider disabling endpoint-independent mapping", n))
+	}
+	if g.PortUtilization != nil && *g.PortUtilization >= natPortUtilizationWarn {
+		findings = append(findings, fmt.Sprintf("peak port usage is %.0f%% of the ports allocated to a VM", *g.PortUtilization*100))
+	}
+	if g.DroppedReceived > 0 {
+		findings = append(findings, fmt.Sprintf("%d received packets dropped: replies arriving after the NAT mapping expired show up here (check the TCP/UDP idle timeouts)", g.DroppedReceived))
+	}
+	return findings
+}
+
+// NATOverviewHandlerWithGuardrail returns a handler with guardrail validation
+func (c *Client) NATOverviewHandlerWithGuardrail(v Validator) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params NATOverviewParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		// ガードレール: 系列数・件数制限
+		if params.MaxSeries <= 0 {
+			params.MaxSeries = 20
+		}
+		params.MaxSeries = v.ClampTimeSeriesLimit(ctx, params.MaxSeries)
+		if params.Limit <= 0 {
+			params.Limit = 20
+		}
+		params.Limit = v.ClampLogLimit(ctx, params.Limit)
+
+		return c.NATOverview(ctx, params)
+	}
+}
@@ -0,0 +1,255 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+)
+
+// NetworkFlowsParams are the parameters for ops.network_flows
+type NetworkFlowsParams struct {
+	ProjectID string               `json:"project_id"`
+	Source    string               `json:"source"`              // "firewall" (default) or "vpc_flows"
+	SrcIP     string               `json:"src_ip,omitempty"`    // IP or CIDR
+	DestIP    string               `json:"dest_ip,omitempty"`   // IP or CIDR
+	DestPort  int                  `json:"dest_port,omitempty"` // Destination port
+	Protocol  int                  `json:"protocol,omitempty"`  // IANA protocol number (6=TCP, 17=UDP)
+	Action    string               `json:"action,omitempty"`    // "ALLOWED" or "DENIED" (firewall only)
+	TimeRange monitoring.TimeRange `json:"time_range"`
+	Limit     int                  `json:"limit"` // Top N talkers
+}
+
+// NetworkFlowsResult is the result of ops.network_flows
+type NetworkFlowsResult struct {
+	QueryMeta    NetworkFlowsQueryMeta `json:"query_meta"`
+	TopTalkers   []Talker              `json:"top_talkers"`
+	DeniedByRule []RuleCount           `json:"denied_by_rule,omitempty"`
+	Stats        NetworkFlowsStats     `json:"stats"`
+}
+
+type NetworkFlowsQueryMeta struct {
+	ProjectID string `json:"project_id"`
+	Source    string `json:"source"`
+	Filter    string `json:"filter"`
+	Start     string `json:"start"`
+	End       string `json:"end"`
+}
+
+type Talker struct {
+	SrcIP       string `json:"src_ip"`
+	DestIP      string `json:"dest_ip"`
+	DestPort    string `json:"dest_port"`
+	Protocol    string `json:"protocol"`
+	Connections int    `json:"connections"`
+	Denied      int    `json:"denied,omitempty"`
+	BytesSent   int64  `json:"bytes_sent,omitempty"`
+}
+
+type RuleCount struct {
+	Rule  string `json:"rule"`
+	Count int    `json:"count"`
+}
+
+type NetworkFlowsStats struct {
+	ScannedLogs int `json:"scanned_logs"`
+	Allowed     int `json:"allowed"`
+	Denied      int `json:"denied"`
+}
+
+// networkFlowsMaxScan は集計のために走査するログ件数の上限
+const networkFlowsMaxScan = 5000
+
+// NetworkFlows aggregates VPC flow logs / firewall logs into top talkers and denied counts
+func (c *Client) NetworkFlows(ctx context.Context, params NetworkFlowsParams) (*NetworkFlowsResult, error) {
+	startTime, endTime, err := monitoring.ParseTimeRange(params.TimeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse time range: %w", err)
+	}
+
+	source := params.Source
+	if source == "" {
+		source = "firewall"
+	}
+	filter, err := buildNetworkFilter(params.ProjectID, source, params)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	talkers := map[string]*Talker{}
+	denied := map[string]int{}
+	stats := NetworkFlowsStats{}
+
+	scanned, err := c.logging.ScanEntries(ctx, params.ProjectID, filter, startTime, endTime, networkFlowsMaxScan, func(e logging.LogEntry) {
+		conn, _ := e.JSONPayload["connection"].(map[string]any)
+		t := Talker{
+			SrcIP:    payloadString(conn["src_ip"]),
+			DestIP:   payloadString(conn["dest_ip"]),
+			DestPort: payloadString(conn["dest_port"]),
+			Protocol: payloadString(conn["protocol"]),
+		}
+		key := strings.Join([]string{t.SrcIP, t.DestIP, t.DestPort, t.Protocol}, "|")
+		agg, ok := talkers[key]
+		if !ok {
+			agg = &t
+			talkers[key] = agg
+		}
+		agg.Connections++
+
+		if bytes, err := strconv.ParseInt(payloadString(e.JSONPayload["bytes_sent"]), 10, 64); err == nil {
+			agg.BytesSent += bytes
+		}
+
+		if payloadString(e.JSONPayload["disposition"]) == "DENIED" {
+			stats.Denied++
+			agg.Denied++
+			rule, _ := e.JSONPayload["rule_details"].(map[string]any)
+			denied[payloadString(rule["reference"])]++
+		} else {
+			stats.Allowed++
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	stats.ScannedLogs = scanned
+
+	topTalkers := make([]Talker, 0, len(talkers))
+	for _, t := range talkers {
+		topTalkers = append(topTalkers, *t)
+	}
+	sort.Slice(topTalkers, func(i, j int) bool {
+		if topTalkers[i].Connections != topTalkers[j].Connections {
+			return topTalkers[i].Connections > topTalkers[j].Connections
+		}
+		return topTalkers[i].BytesSent > topTalkers[j].BytesSent
+	})
+	if len(topTalkers) > limit {
+		topTalkers = topTalkers[:limit]
+	}
+
+	var deniedByRule []RuleCount
+	for rule, count := range denied {
+		deniedByRule = append(deniedByRule, RuleCount{Rule: rule, Count: count})
+	}
+	sort.Slice(deniedByRule, func(i, j int) bool {
+		return deniedByRule[i].Count > deniedByRule[j].Count
+	})
+
+	return &NetworkFlowsResult{
+		QueryMeta: NetworkFlowsQueryMeta{
+			ProjectID: params.ProjectID,
+			Source:    source,
+			Filter:    filter,
+			Start:     startTime.Format(time.RFC3339),
+			End:       endTime.Format(time.RFC3339),
+		},
+		TopTalkers:   topTalkers,
+		DeniedByRule: deniedByRule,
+		Stats:        stats,
+	}, nil
+}
+
+// buildNetworkFilter は構造化パラメータからLQLフィルタを組み立てる
+func buildNetworkFilter(projectID, source string, params NetworkFlowsParams) (string, error) {
+	var logID string
+	switch source {
+	case "firewall":
+		logID = "compute.googleapis.com%2Ffirewall"
+	case "vpc_flows":
+		logID = "compute.googleapis.com%2Fvpc_flows"
+	default:
+		return "", fmt.Errorf("unsupported source: %s (supported: firewall, vpc_flows)", source)
+	}
+
+	clauses := []string{fmt.Sprintf(`logName = "projects/%s/logs/%s"`, projectID, logID)}
+
+	for field, value := range map[string]string{
+		"jsonPayload.connection.src_ip":  params.SrcIP,
+		"jsonPayload.connection.dest_ip": params.DestIP,
+	} {
+		if value == "" {
+			continue
+		}
+		clause, err := ipClause(field, value)
+		if err != nil {
+			return "", err
+		}
+		clauses = append(clauses, clause)
+	}
+
+	if params.DestPort > 0 {
+		clauses = append(clauses, fmt.Sprintf("jsonPayload.connection.dest_port = %d", params.DestPort))
+	}
+	if params.Protocol > 0 {
+		clauses = append(clauses, fmt.Sprintf("jsonPayload.connection.protocol = %d", params.Protocol))
+	}
+	if params.Action != "" {
+		action := strings.ToUpper(params.Action)
+		if action != "ALLOWED" && action != "DENIED" {
+			return "", fmt.Errorf("invalid action: %s (supported: ALLOWED, DENIED)", params.Action)
+		}
+		if source != "firewall" {
+			return "", fmt.Errorf("action filter is only supported for source 'firewall'")
+		}
+		clauses = append(clauses, fmt.Sprintf(`jsonPayload.disposition = "%s"`, action))
+	}
+
+	sort.Strings(clauses[1:]) // フィルタ文字列を安定させる
+	return strings.Join(clauses, " AND "), nil
+}
+
+// ipClause はIPまたはCIDR指定をLQLに変換する
+func ipClause(field, value string) (string, error) {
+	if strings.Contains(value, "/") {
+		if _, _, err := net.ParseCIDR(value); err != nil {
+			return "", fmt.Errorf("invalid CIDR: %s", value)
+		}
+		return fmt.Sprintf(`ip_in_net(%s, "%s")`, field, value), nil
+	}
+	if net.ParseIP(value) == nil {
+		return "", fmt.Errorf("invalid IP address: %s", value)
+	}
+	return fmt.Sprintf(`%s = "%s"`, field, value), nil
+}
+
+// payloadString はjsonPayloadの値（文字列・数値）を文字列化する
+func payloadString(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// NetworkFlowsHandler returns a handler for the ops.network_flows tool
+func (c *Client) NetworkFlowsHandler() func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params NetworkFlowsParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		return c.NetworkFlows(ctx, params)
+	}
+}
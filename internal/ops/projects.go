@@ -0,0 +1,169 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ListProjectsParams are the parameters for ops.list_projects
+type ListProjectsParams struct {
+	Query string `json:"query"` // Optional: matched against project ID, name and labels (case-insensitive)
+	Limit int    `json:"limit"`
+}
+
+// ListProjectsResult is the result of ops.list_projects
+type ListProjectsResult struct {
+	Projects []Project     `json:"projects"`
+	Stats    ProjectsStats `json:"stats"`
+}
+
+type Project struct {
+	ProjectID   string            `json:"project_id"`
+	DisplayName string            `json:"display_name"`
+	Aliases     []string          `json:"aliases,omitempty"`
+	State       string            `json:"state"`
+	Parent      string            `json:"parent,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+}
+
+type ProjectsStats struct {
+	ReturnedCount int  `json:"returned_count"`
+	FilteredCount int  `json:"filtered_count"` // 許可リスト外で除外した件数
+	Truncated     bool `json:"truncated"`
+}
+
+// ListProjects lists projects visible to the credentials that pass the allow-list
+func (c *Client) ListProjects(ctx context.Context, params ListProjectsParams, allowed func(projectID string) bool, aliases map[string]string) (*ListProjectsResult, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	// 実ID → エイリアスの逆引き
+	aliasesByID := map[string][]string{}
+	for alias, id := range aliases {
+		aliasesByID[id] = append(aliasesByID[id], alias)
+	}
+
+	query := strings.ToLower(params.Query)
+	projects := []Project{}
+	filtered := 0
+	truncated := false
+	pageToken := ""
+
+	for !truncated {
+		resp, err := c.resourceMgr.Projects.Search().
+			Context(ctx).
+			Query("state:ACTIVE").
+			PageSize(500).
+			PageToken(pageToken).
+			Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to search projects: %w", err)
+		}
+
+		for _, p := range resp.Projects {
+			if !allowed(p.ProjectId) {
+				filtered++
+				continue
+			}
+			projectAliases := aliasesByID[p.ProjectId]
+			sort.Strings(projectAliases)
+			if query != "" && !matchesProject(p.ProjectId, p.DisplayName, projectAliases, p.Labels, query) {
+				continue
+			}
+			if len(projects) >= limit {
+				truncated = true
+				break
+			}
+			projects = append(projects, Project{
+				ProjectID:   p.ProjectId,
+				DisplayName: p.DisplayName,
+				Aliases:     projectAliases,
+				State:       p.State,
+				Parent:      p.Parent,
+				Labels:      p.Labels,
+			})
+		}
+
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].ProjectID < projects[j].ProjectID
+	})
+
+	return &ListProjectsResult{
+		Projects: projects,
+		Stats: ProjectsStats{
+			ReturnedCount: len(projects),
+			FilteredCount: filtered,
+			Truncated:     truncated,
+		},
+	}, nil
+}
+
+// matchesProject はID・表示名・エイリアス・ラベルのいずれかにqueryが含まれるか判定する
+func matchesProject(projectID, displayName string, aliases []string, labels map[string]string, query string) bool {
+	candidates := append([]string{projectID, displayName}, aliases...)
+	for k, v := range labels {
+		candidates = append(candidates, k, v)
+	}
+	for _, s := range candidates {
+		if strings.Contains(strings.ToLower(s), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListProjectsHandlerWithGuardrail returns a handler with guardrail validation
+func (c *Client) ListProjectsHandlerWithGuardrail(v Validator, aliases map[string]string) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params ListProjectsParams
+		if len(args) > 0 {
+			if err := json.Unmarshal(args, &params); err != nil {
+				return nil, fmt.Errorf("failed to parse arguments: %w", err)
+			}
+		}
+
+		// ガードレール: 許可リスト外のプロジェクトは返さない
+		allowed := func(projectID string) bool {
+			return v.ValidateProjectID(ctx, projectID) == nil
+		}
+
+		return c.ListProjects(ctx, params, allowed, aliases)
+	}
+}
+
+// ProjectAncestors はプロジェクトの親を辿り、フォルダ・組織のリソース名を近い順に返す
+func (c *Client) ProjectAncestors(ctx context.Context, projectID string) ([]string, error) {
+	p, err := c.resourceMgr.Projects.Get("projects/" + projectID).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	ancestors := []string{}
+	parent := p.Parent
+	for parent != "" {
+		ancestors = append(ancestors, parent)
+		if !strings.HasPrefix(parent, "folders/") {
+			break // organizations/ に到達
+		}
+		f, err := c.resourceMgr.Folders.Get(parent).Context(ctx).Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get folder %s: %w", parent, err)
+		}
+		parent = f.Parent
+	}
+	return ancestors, nil
+}
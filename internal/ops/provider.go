@@ -0,0 +1,1201 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/api/option"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/guardrail"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/provider"
+)
+
+func init() {
+	provider.Register("ops", newProvider)
+}
+
+// toolProvider は複数APIを組み合わせた運用ツール（ops.*）を提供する
+type toolProvider struct {
+	client *provider.Lazy[*Client]
+	cfg    *config.Config
+	guard  *guardrail.Guardrail
+	// credOpts は credentials_file の認証情報（ヘルスチェックで確認する。空なら ADC）
+	credOpts []option.ClientOption
+}
+
+func newProvider(ctx context.Context, env provider.Env) (provider.ToolProvider, error) {
+	client := provider.NewLazy(ctx, "ops", func(ctx context.Context) (*Client, error) {
+		return newOwnedClient(ctx, env)
+	})
+
+	// フォルダ・組織単位の許可ルールは Resource Manager で祖先を解決して判定する
+	if env.Config.HasAncestorRules() {
+		env.Guard.SetAncestryLookup(func(projectID string) ([]string, error) {
+			c, err := client.Get()
+			if err != nil {
+				return nil, err
+			}
+			lookupCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			defer cancel()
+			return c.ProjectAncestors(lookupCtx, projectID)
+		})
+	}
+
+	// 重いクエリの前の IAM 権限の確認（preflight）も Resource Manager で行う
+	if env.Config.Preflight.Enabled {
+		env.Guard.SetPermissionTester(func(ctx context.Context, projectID string, perms []string) ([]string, error) {
+			c, err := client.Get()
+			if err != nil {
+				return nil, err
+			}
+			return c.TestPermissions(ctx, projectID, perms)
+		})
+	}
+
+	// VPC Service Controls の拒否の詳細はポリシー監査ログと Access Context Manager から引く
+	env.Guard.SetVPCSCLookup(func(ctx context.Context, projectID, uniqueID string) (*guardrail.VPCSCDetails, error) {
+		c, err := client.Get()
+		if err != nil {
+			return nil, err
+		}
+		lookupCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		return c.VPCSCViolation(lookupCtx, projectID, uniqueID)
+	})
+
+	return &toolProvider{client: client, cfg: env.Config, guard: env.Guard, credOpts: env.CredentialOptions}, nil
+}
+
+// newOwnedClient は logging / monitoring のクライアントも自前で作る
+// （logging / monitoring プロバイダを無効にしても ops.* は使える）
+func newOwnedClient(ctx context.Context, env provider.Env) (*Client, error) {
+	loggingClient, err := logging.NewClient(ctx, env.GRPCOptionsFor("logging")...)
+	if err != nil {
+		return nil, err
+	}
+	loggingClient.SetAllowedLogViews(env.Config.AllowedLogViews)
+	loggingClient.SetExcludeFilters(env.Config.Logging.ExcludeFilters)
+	loggingClient.SetResourceRules(env.Config.ResourceRulesFor)
+	monitoringClient, err := monitoring.NewClient(ctx, env.GRPCOptionsFor("monitoring")...)
+	if err != nil {
+		_ = loggingClient.Close()
+		return nil, err
+	}
+	monitoringClient.SetResourceRules(env.Config.ResourceRulesFor)
+	client, err := NewClient(ctx, monitoringClient, loggingClient, env.HTTPOptions...)
+	if err != nil {
+		_ = monitoringClient.Close()
+		_ = loggingClient.Close()
+		return nil, err
+	}
+	client.SetResourceKinds(env.Config.ResourceKinds)
+	return client, nil
+}
+
+func (p *toolProvider) Name() string {
+	return "ops"
+}
+
+func (p *toolProvider) RequiredAPIs() []string {
+	return []string{"monitoring.googleapis.com", "logging.googleapis.com", "bigquery.googleapis.com", "recommender.googleapis.com", "cloudbuild.googleapis.com", "clouddeploy.googleapis.com", "cloudresourcemanager.googleapis.com", "servicehealth.googleapis.com"}
+}
+
+func (p *toolProvider) Tools() []mcp.Tool {
+	tools := []mcp.Tool{
+		{
+			Name:        "ops.golden_signals",
+			Description: "Fetch golden signals (traffic, errors, latency, saturation) for a resource without knowing metric types.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"kind": {
+						Type:        "string",
+						Description: "Resource kind (built-in or added under resource_kinds in config)",
+						Enum:        p.cfg.ResourceKinds.GoldenSignalKinds(),
+					},
+					"name": {
+						Type:        "string",
+						Description: "Resource name (Cloud Run service name, GKE top-level controller name, or URL map name)",
+					},
+					"time_range": {
+						Type:        "object",
+						Description: "Time range for the query",
+						Properties: map[string]mcp.Property{
+							"start": {
+								Type:        "string",
+								Description: "Start time (RFC3339 or relative like '-1h', '-30m')",
+							},
+							"end": {
+								Type:        "string",
+								Description: "End time (RFC3339 or 'now')",
+								Default:     "now",
+							},
+						},
+					},
+					"alignment_period_sec": {
+						Type:        "integer",
+						Description: "Alignment period in seconds (default: 60)",
+						Default:     60,
+					},
+					"max_series": {
+						Type:        "integer",
+						Description: fmt.Sprintf("Maximum number of time series per signal (default: 20, max: %d)", p.cfg.Limits.MaxTimeSeries),
+						Default:     20,
+					},
+					"render": {
+						Type:        "string",
+						Description: "points (default): every data point. chart: one PNG line chart per signal plus a legend/summary text",
+						Enum:        []string{"points", "chart"},
+						Default:     "points",
+					},
+				},
+				Required: []string{"project_id", "kind", "name"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[GoldenSignalsResult](),
+		},
+		{
+			Name:        "ops.list_resources",
+			Description: "Discover resources (Cloud Run services, GKE clusters, GCE instances, Cloud SQL, etc.) that emitted telemetry recently in a project.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"kinds": {
+						Type:        "array",
+						Description: "Resource kinds to discover (default: all)",
+						Items:       &mcp.Property{Type: "string", Enum: DiscoveryKinds()},
+					},
+					"time_range": {
+						Type:        "object",
+						Description: "Time window in which resources must have emitted telemetry",
+						Properties: map[string]mcp.Property{
+							"start": {
+								Type:        "string",
+								Description: "Start time (RFC3339 or relative like '-1h', '-30m')",
+							},
+							"end": {
+								Type:        "string",
+								Description: "End time (RFC3339 or 'now')",
+								Default:     "now",
+							},
+						},
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of resources per kind (default: 50, max: 200)",
+						Default:     50,
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[ListResourcesResult](),
+		},
+		{
+			Name:        "ops.api_gateway_overview",
+			Description: "API platform on-call view for API Gateway (and Cloud Endpoints) or Apigee: request counts by response class, 5xx/4xx error rates and peak p99 latency per API config (API Gateway: service and config ID, Apigee: proxy and environment), plus sample logs of failing requests.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"platform": {
+						Type:        "string",
+						Description: "API platform (default: api_gateway)",
+						Enum:        APIPlatforms(),
+						Default:     "api_gateway",
+					},
+					"api": {
+						Type:        "string",
+						Description: "API to look at: managed service name for API Gateway (e.g., 'my-api-abc123.apigateway.my-project.cloud.goog') or proxy name for Apigee (default: all)",
+					},
+					"location": {
+						Type:        "string",
+						Description: "Location (default: all)",
+					},
+					"time_range": {
+						Type:        "object",
+						Description: "Time range for the query",
+						Properties: map[string]mcp.Property{
+							"start": {
+								Type:        "string",
+								Description: "Start time (RFC3339 or relative like '-1h', '-30m')",
+							},
+							"end": {
+								Type:        "string",
+								Description: "End time (RFC3339 or 'now')",
+								Default:     "now",
+							},
+						},
+					},
+					"alignment_period_sec": {
+						Type:        "integer",
+						Description: "Alignment period in seconds (default: 60)",
+						Default:     60,
+					},
+					"max_series": {
+						Type:        "integer",
+						Description: "Maximum number of series per signal (default: 50)",
+						Default:     50,
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of failing request logs to return (default: 20)",
+						Default:     20,
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[APIGatewayOverviewResult](),
+		},
+		{
+			Name:        "ops.vertex_overview",
+			Description: "Vertex AI serving health: for prediction endpoints, prediction rate, errors, p99 latency (overhead vs model) and replicas plus error logs; for generative AI models (Gemini etc.), invocation rate by response code, p99 and first-token latency, and input/output token throughput. Summarizes error rates (429 = quota/throughput exhausted) and mean tokens per second.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"endpoint_id": {
+						Type:        "string",
+						Description: "Prediction endpoint ID (only this endpoint; skips generative AI models)",
+					},
+					"model": {
+						Type:        "string",
+						Description: "Publisher model ID, e.g. 'gemini-2.0-flash' (only this model; skips prediction endpoints)",
+					},
+					"location": {
+						Type:        "string",
+						Description: "Location (e.g., 'asia-northeast1'; default: all)",
+					},
+					"time_range": {
+						Type:        "object",
+						Description: "Time range for the query",
+						Properties: map[string]mcp.Property{
+							"start": {
+								Type:        "string",
+								Description: "Start time (RFC3339 or relative like '-1h', '-30m')",
+							},
+							"end": {
+								Type:        "string",
+								Description: "End time (RFC3339 or 'now')",
+								Default:     "now",
+							},
+						},
+					},
+					"alignment_period_sec": {
+						Type:        "integer",
+						Description: "Alignment period in seconds (default: 60)",
+						Default:     60,
+					},
+					"max_series": {
+						Type:        "integer",
+						Description: "Maximum number of series (endpoints / models) per signal (default: 10)",
+						Default:     10,
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of prediction error logs to return (default: 20)",
+						Default:     20,
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[VertexOverviewResult](),
+		},
+		{
+			Name:        "ops.scheduler_failures",
+			Description: "Find silently failing cron jobs and task queues: failed Cloud Scheduler job executions (from their ERROR logs) grouped by job with status counts and the latest failure detail, and Cloud Tasks queues with failed (retried) attempts by response code and queue depth, plus sample task error logs.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"location": {
+						Type:        "string",
+						Description: "Location of the jobs / queues (e.g., 'asia-northeast1'; default: all)",
+					},
+					"job": {
+						Type:        "string",
+						Description: "Cloud Scheduler job ID (only this job; skips Cloud Tasks)",
+					},
+					"queue": {
+						Type:        "string",
+						Description: "Cloud Tasks queue ID (only this queue; skips Cloud Scheduler)",
+					},
+					"time_range": {
+						Type:        "object",
+						Description: "Time range for the query",
+						Properties: map[string]mcp.Property{
+							"start": {
+								Type:        "string",
+								Description: "Start time (RFC3339 or relative like '-1h', '-30m')",
+							},
+							"end": {
+								Type:        "string",
+								Description: "End time (RFC3339 or 'now')",
+								Default:     "now",
+							},
+						},
+					},
+					"alignment_period_sec": {
+						Type:        "integer",
+						Description: "Alignment period in seconds (default: 60)",
+						Default:     60,
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of jobs / queues / error logs to return (default: 20)",
+						Default:     20,
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[SchedulerFailuresResult](),
+		},
+		{
+			Name:        "ops.gcs_overview",
+			Description: "Find who is hammering a Cloud Storage bucket (e.g. with 403s): request counts by method and response code from metrics, plus the bucket's data access audit logs aggregated by caller (principal and IP) with methods and failure statuses, and sample log entries. Requires Data Access audit logs to be enabled for Cloud Storage.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"bucket": {
+						Type:        "string",
+						Description: "Bucket name",
+					},
+					"errors_only": {
+						Type:        "boolean",
+						Description: "Only aggregate failed requests from the audit logs (default: true); set false to see all callers",
+						Default:     true,
+					},
+					"time_range": {
+						Type:        "object",
+						Description: "Time range for the query",
+						Properties: map[string]mcp.Property{
+							"start": {
+								Type:        "string",
+								Description: "Start time (RFC3339 or relative like '-1h', '-30m')",
+							},
+							"end": {
+								Type:        "string",
+								Description: "End time (RFC3339 or 'now')",
+								Default:     "now",
+							},
+						},
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of callers / sample logs to return (default: 20)",
+						Default:     20,
+					},
+				},
+				Required: []string{"project_id", "bucket"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[GCSOverviewResult](),
+		},
+		{
+			Name:        "ops.dataflow_overview",
+			Description: "Triage a Dataflow job in one call: system lag, data watermark age, vCPU usage and failure state from metrics, plus autoscaling events (target worker counts) and recent warning/error job messages from the job logs.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"job_id": {
+						Type:        "string",
+						Description: "Dataflow job ID (e.g., '2024-01-01_00_00_00-1234567890123456789')",
+					},
+					"region": {
+						Type:        "string",
+						Description: "Region of the job (e.g., 'asia-northeast1')",
+					},
+					"time_range": {
+						Type:        "object",
+						Description: "Time range for the query",
+						Properties: map[string]mcp.Property{
+							"start": {
+								Type:        "string",
+								Description: "Start time (RFC3339 or relative like '-1h', '-30m')",
+							},
+							"end": {
+								Type:        "string",
+								Description: "End time (RFC3339 or 'now')",
+								Default:     "now",
+							},
+						},
+					},
+					"alignment_period_sec": {
+						Type:        "integer",
+						Description: "Alignment period in seconds (default: 60)",
+						Default:     60,
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of autoscaling events / job messages to return (default: 20)",
+						Default:     20,
+					},
+				},
+				Required: []string{"project_id", "job_id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[DataflowOverviewResult](),
+		},
+		{
+			Name:        "ops.nat_overview",
+			Description: "Investigate intermittent egress timeouts through Cloud NAT: per gateway, NAT IP/port allocation failures, dropped sent/received packets (by reason), connections, and peak port usage vs ports allocated per VM, plus NAT logs of dropped connections and findings.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"region": {
+						Type:        "string",
+						Description: "Region of the Cloud Router (default: all regions)",
+					},
+					"router": {
+						Type:        "string",
+						Description: "Cloud Router name (default: all routers)",
+					},
+					"gateway": {
+						Type:        "string",
+						Description: "NAT gateway name (default: all gateways)",
+					},
+					"time_range": {
+						Type:        "object",
+						Description: "Time range for the query",
+						Properties: map[string]mcp.Property{
+							"start": {
+								Type:        "string",
+								Description: "Start time (RFC3339 or relative like '-1h', '-30m')",
+							},
+							"end": {
+								Type:        "string",
+								Description: "End time (RFC3339 or 'now')",
+								Default:     "now",
+							},
+						},
+					},
+					"alignment_period_sec": {
+						Type:        "integer",
+						Description: "Alignment period in seconds (default: 60)",
+						Default:     60,
+					},
+					"max_series": {
+						Type:        "integer",
+						Description: "Maximum number of series (gateways) per signal (default: 20)",
+						Default:     20,
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of NAT log entries to return (default: 20)",
+						Default:     20,
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[NATOverviewResult](),
+		},
+		{
+			Name:        "ops.bigquery_overview",
+			Description: "Triage BigQuery slowness/queueing: slot and scan metrics, recent job error logs, and optionally top jobs from INFORMATION_SCHEMA.JOBS.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"time_range": {
+						Type:        "object",
+						Description: "Time range for the query",
+						Properties: map[string]mcp.Property{
+							"start": {
+								Type:        "string",
+								Description: "Start time (RFC3339 or relative like '-1h', '-30m')",
+							},
+							"end": {
+								Type:        "string",
+								Description: "End time (RFC3339 or 'now')",
+								Default:     "now",
+							},
+						},
+					},
+					"alignment_period_sec": {
+						Type:        "integer",
+						Description: "Alignment period in seconds (default: 60)",
+						Default:     60,
+					},
+					"include_jobs": {
+						Type:        "boolean",
+						Description: "Also query INFORMATION_SCHEMA.JOBS for top jobs by slot usage (runs a billed query)",
+						Default:     false,
+					},
+					"region": {
+						Type:        "string",
+						Description: "BigQuery region for INFORMATION_SCHEMA (e.g., 'us', 'asia-northeast1'; default: 'us')",
+						Default:     "us",
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of error logs / jobs to return (default: 20)",
+						Default:     20,
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[BigQueryOverviewResult](),
+		},
+		{
+			Name:        "ops.functions_overview",
+			Description: "Snapshot of a Cloud Function / Cloud Run function: execution counts, error rate, execution time percentiles, cold-start latency (gen2), and recent crash logs.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"function_name": {
+						Type:        "string",
+						Description: "Function name",
+					},
+					"region": {
+						Type:        "string",
+						Description: "Optional region (e.g., 'asia-northeast1')",
+					},
+					"generation": {
+						Type:        "string",
+						Description: "Function generation (default: gen2)",
+						Enum:        []string{"gen2", "gen1"},
+						Default:     "gen2",
+					},
+					"time_range": {
+						Type:        "object",
+						Description: "Time range for the query",
+						Properties: map[string]mcp.Property{
+							"start": {
+								Type:        "string",
+								Description: "Start time (RFC3339 or relative like '-1h', '-30m')",
+							},
+							"end": {
+								Type:        "string",
+								Description: "End time (RFC3339 or 'now')",
+								Default:     "now",
+							},
+						},
+					},
+					"alignment_period_sec": {
+						Type:        "integer",
+						Description: "Alignment period in seconds (default: 60)",
+						Default:     60,
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of crash logs to return (default: 20)",
+						Default:     20,
+					},
+				},
+				Required: []string{"project_id", "function_name"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[FunctionsOverviewResult](),
+		},
+		{
+			Name:        "ops.network_flows",
+			Description: "Analyze firewall logs / VPC Flow Logs with structured filters and return top talkers and denied-connection counts.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"source": {
+						Type:        "string",
+						Description: "Log source (default: firewall)",
+						Enum:        []string{"firewall", "vpc_flows"},
+						Default:     "firewall",
+					},
+					"src_ip": {
+						Type:        "string",
+						Description: "Source IP or CIDR (e.g., '10.0.0.5', '10.0.0.0/8')",
+					},
+					"dest_ip": {
+						Type:        "string",
+						Description: "Destination IP or CIDR",
+					},
+					"dest_port": {
+						Type:        "integer",
+						Description: "Destination port",
+					},
+					"protocol": {
+						Type:        "integer",
+						Description: "IANA protocol number (6=TCP, 17=UDP)",
+					},
+					"action": {
+						Type:        "string",
+						Description: "Firewall disposition (firewall source only)",
+						Enum:        []string{"ALLOWED", "DENIED"},
+					},
+					"time_range": {
+						Type:        "object",
+						Description: "Time range for the query",
+						Properties: map[string]mcp.Property{
+							"start": {
+								Type:        "string",
+								Description: "Start time (RFC3339 or relative like '-1h', '-30m')",
+							},
+							"end": {
+								Type:        "string",
+								Description: "End time (RFC3339 or 'now')",
+								Default:     "now",
+							},
+						},
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Number of top talkers to return (default: 20, max: 100)",
+						Default:     20,
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[NetworkFlowsResult](),
+		},
+		{
+			Name:        "ops.check_quotas",
+			Description: "Compare allocation and rate quota usage against limits (serviceruntime quota metrics) and flag quotas above a threshold.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"service": {
+						Type:        "string",
+						Description: "Optional service to check (e.g., 'compute.googleapis.com')",
+					},
+					"threshold": {
+						Type:        "number",
+						Description: "Usage ratio (0-1) at which a quota is flagged (default: 0.8)",
+						Default:     0.8,
+					},
+					"only_flagged": {
+						Type:        "boolean",
+						Description: "Return only quotas above the threshold",
+						Default:     false,
+					},
+					"time_range": {
+						Type:        "object",
+						Description: "Time range for the query",
+						Properties: map[string]mcp.Property{
+							"start": {
+								Type:        "string",
+								Description: "Start time (RFC3339 or relative like '-1h', '-30m')",
+							},
+							"end": {
+								Type:        "string",
+								Description: "End time (RFC3339 or 'now')",
+								Default:     "now",
+							},
+						},
+					},
+					"max_series": {
+						Type:        "integer",
+						Description: fmt.Sprintf("Maximum number of quota series per metric (default: 20, max: %d)", p.cfg.Limits.MaxTimeSeries),
+						Default:     20,
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[CheckQuotasResult](),
+		},
+		{
+			Name:        "ops.list_recommendations",
+			Description: "List Recommender API findings (idle VMs, rightsizing, IAM, etc.) for a project.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"recommender": {
+						Type: "string",
+						Description: fmt.Sprintf("Recommender alias (%s) or full recommender ID (e.g., 'google.compute.instance.IdleResourceRecommender')",
+							strings.Join(RecommenderAliases(), ", ")),
+					},
+					"location": {
+						Type:        "string",
+						Description: "Location of the recommender (e.g., 'global' for IAM, a zone like 'us-central1-a' for VMs; default: 'global')",
+						Default:     "global",
+					},
+					"state": {
+						Type:        "string",
+						Description: "Recommendation state (default: ACTIVE)",
+						Enum:        []string{"ACTIVE", "CLAIMED", "SUCCEEDED", "FAILED", "DISMISSED"},
+						Default:     "ACTIVE",
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of recommendations to return (default: 50, max: 200)",
+						Default:     50,
+					},
+				},
+				Required: []string{"project_id", "recommender"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[ListRecommendationsResult](),
+		},
+		{
+			Name:        "ops.gcp_service_health",
+			Description: "List active Google Cloud incidents affecting the project (Personalized Service Health, falling back to the public status feed). Answers 'is it us or Google?'.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"products": {
+						Type:        "array",
+						Description: "Optional product name filter (substring match, e.g., ['Cloud Run', 'Cloud SQL'])",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"locations": {
+						Type:        "array",
+						Description: "Optional location filter (e.g., ['asia-northeast1']); global incidents are always included",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"source": {
+						Type:        "string",
+						Description: "Incident source (default: auto = personalized with public fallback)",
+						Enum:        []string{"auto", "personalized", "public"},
+						Default:     "auto",
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[ServiceHealthResult](),
+		},
+		{
+			Name:        "ops.recent_deployments",
+			Description: "List recent Cloud Build builds and Cloud Deploy releases/rollouts with status and timestamps, newest first. Use to add CI/CD context to a change timeline.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"location": {
+						Type:        "string",
+						Description: "Region for Cloud Deploy and regional builds (e.g., 'asia-northeast1'). If omitted, only global Cloud Build builds are listed.",
+					},
+					"time_range": {
+						Type:        "object",
+						Description: "Time window for builds and rollouts",
+						Properties: map[string]mcp.Property{
+							"start": {
+								Type:        "string",
+								Description: "Start time (RFC3339 or relative like '-24h', '-30m')",
+							},
+							"end": {
+								Type:        "string",
+								Description: "End time (RFC3339 or 'now')",
+								Default:     "now",
+							},
+						},
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of events to return (default: 50, max: 200)",
+						Default:     50,
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[RecentDeploymentsResult](),
+		},
+		{
+			Name:        "ops.generate_report",
+			Description: "Compose a Markdown incident/health report of a resource over a window, ready to paste into a postmortem: golden signals with sparklines, top errors from its logs, a change timeline (Cloud Build / Cloud Deploy events mentioning it) and SLO status. Sections that fail show the error instead of failing the report.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"kind": {
+						Type:        "string",
+						Description: "Resource kind (as in ops.golden_signals)",
+						Enum:        p.cfg.ResourceKinds.GoldenSignalKinds(),
+					},
+					"name": {
+						Type:        "string",
+						Description: "Resource name (Cloud Run service name, GKE top-level controller name, or URL map name)",
+					},
+					"time_range": {
+						Type:        "object",
+						Description: "Window the report covers (e.g., the incident)",
+						Properties: map[string]mcp.Property{
+							"start": {
+								Type:        "string",
+								Description: "Start time (RFC3339 or relative like '-1h', '-30m')",
+							},
+							"end": {
+								Type:        "string",
+								Description: "End time (RFC3339 or 'now')",
+								Default:     "now",
+							},
+						},
+					},
+					"title": {
+						Type:        "string",
+						Description: "Report title (default: '<name> report: <start>')",
+					},
+					"service_id": {
+						Type:        "string",
+						Description: "Service Monitoring service ID whose SLOs to show (default: the service whose ID, display name or identifier matches name)",
+					},
+					"location": {
+						Type:        "string",
+						Description: "Region for Cloud Deploy and regional builds in the change timeline (e.g., 'asia-northeast1')",
+					},
+					"alignment_period_sec": {
+						Type:        "integer",
+						Description: "Alignment period in seconds (default: about 60 points over the window, min 60)",
+					},
+					"max_series": {
+						Type:        "integer",
+						Description: fmt.Sprintf("Maximum number of time series per signal (default: 5, max: %d)", p.cfg.Limits.MaxTimeSeries),
+						Default:     5,
+					},
+					"error_limit": {
+						Type:        "integer",
+						Description: "Number of error groups to list (default: 10, max: 50)",
+						Default:     10,
+					},
+				},
+				Required: []string{"project_id", "kind", "name"},
+			},
+		},
+		{
+			Name:        "ops.list_projects",
+			Description: "List GCP projects the credentials can access, limited to the allow-list. Returns project ID, display name, configured aliases and labels. Use to resolve a name like 'the staging project' to a concrete project ID.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"query": {
+						Type:        "string",
+						Description: "Optional case-insensitive substring matched against project ID, display name, aliases and labels (e.g., 'staging')",
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum number of projects to return (default: 100, max: 500)",
+						Default:     100,
+					},
+				},
+			},
+			OutputSchema: mcp.OutputSchemaFor[ListProjectsResult](),
+		},
+		{
+			Name:        "ops.get_config",
+			Description: "Show the effective server configuration (allowed projects, aliases, limits, enabled features). Use to understand why a query was rejected or clamped.",
+			InputSchema: mcp.ToolSchema{
+				Type:       "object",
+				Properties: map[string]mcp.Property{},
+			},
+			OutputSchema: mcp.OutputSchemaFor[GetConfigResult](),
+		},
+		{
+			Name:        "ops.health",
+			Description: "Self-diagnostics: verify credentials, list granted/missing IAM permissions (testIamPermissions), check Logging/Monitoring API reachability and show configured limits. Run this first when tools fail unexpectedly.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID to check permissions and API reachability against (optional; without it only credentials and config are checked)",
+					},
+				},
+			},
+			OutputSchema: mcp.OutputSchemaFor[HealthResult](),
+		},
+		{
+			Name:        "ops.whoami",
+			Description: "Show who the server calls GCP as: principal (user or service account), credential type and source, OAuth scopes, token expiry and remaining lifetime, plus API clients re-created after authentication errors. Use when calls fail with authentication errors or to check which identity needs IAM roles.",
+			InputSchema: mcp.ToolSchema{
+				Type:       "object",
+				Properties: map[string]mcp.Property{},
+			},
+			OutputSchema: mcp.OutputSchemaFor[WhoAmIResult](),
+		},
+		{
+			Name:        "ops.list_saved_queries",
+			Description: "List the team's saved queries (named log filters and metric queries) with their parameters. Run one with ops.run_saved_query.",
+			InputSchema: mcp.ToolSchema{
+				Type:       "object",
+				Properties: map[string]mcp.Property{},
+			},
+			OutputSchema: mcp.OutputSchemaFor[ListSavedQueriesResult](),
+		},
+		{
+			Name:        "ops.run_saved_query",
+			Description: "Run a saved query by name, substituting {{param}} placeholders. Log queries return entries like logging.query; metric queries return series like monitoring.query_time_series.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"name": {
+						Type:        "string",
+						Description: "Saved query name (see ops.list_saved_queries)",
+					},
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID (default: the saved query's project)",
+					},
+					"params": {
+						Type:        "object",
+						Description: "Values for {{param}} placeholders (e.g., {'service': 'checkout'})",
+					},
+					"time_range": {
+						Type:        "object",
+						Description: "Time range for the query (default: the saved query's time_range, or last 30 minutes)",
+						Properties: map[string]mcp.Property{
+							"start": {
+								Type:        "string",
+								Description: "Start time (RFC3339 or relative like '-1h', '-30m')",
+							},
+							"end": {
+								Type:        "string",
+								Description: "End time (RFC3339 or 'now')",
+								Default:     "now",
+							},
+						},
+					},
+					"limit": {
+						Type:        "integer",
+						Description: "Maximum log entries (logs) or time series (metrics) to return",
+					},
+				},
+				Required: []string{"name"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[RunSavedQueryResult](),
+		},
+	}
+
+	// 課金エクスポートが設定されている場合のみ
+	if p.cfg.Billing.ExportTable != "" {
+		tools = append(tools, mcp.Tool{
+			Name:        "ops.cost_signal",
+			Description: "Report daily cost by service from the Cloud Billing BigQuery export and flag cost spikes.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID whose costs to report",
+					},
+					"days": {
+						Type:        "integer",
+						Description: "Lookback window in days (default: 14, max: 90)",
+						Default:     14,
+					},
+					"service": {
+						Type:        "string",
+						Description: "Optional service description filter (e.g., 'Cloud Logging')",
+					},
+					"spike_ratio": {
+						Type:        "number",
+						Description: "Ratio of latest day to baseline average at which a service is flagged (default: 1.5)",
+						Default:     1.5,
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[CostSignalResult](),
+		})
+	}
+
+	// 書き出し先が設定されている場合のみ（書き込みツールなので mode: standard でのみ登録される）
+	if p.cfg.Export.Enabled() {
+		var destinations []string
+		if p.cfg.Export.GCSBucket != "" {
+			destinations = append(destinations, "gcs")
+		}
+		if p.cfg.Export.BigQueryDataset != "" {
+			destinations = append(destinations, "bigquery")
+		}
+		tools = append(tools, mcp.Tool{
+			Name:        "ops.export_result",
+			Description: "Re-run a logging or monitoring query without the usual result limits (up to the export cap) and write every row as NDJSON/CSV to the configured GCS bucket or to a new BigQuery table; returns the destination URI for handing off to humans or batch analysis. Two-step: the first call returns a preview and confirm_token; call again with the same arguments plus confirm_token to execute.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"kind": {
+						Type:        "string",
+						Description: "logs: log entries matching filter. metrics: every point of the time series of metric_type",
+						Enum:        []string{"logs", "metrics"},
+					},
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"filter": {
+						Type:        "string",
+						Description: "Logging Query Language filter (logs), or a Monitoring filter ANDed to the query (metrics)",
+					},
+					"time_range": {
+						Type:        "object",
+						Description: "Time range for the query",
+						Properties: map[string]mcp.Property{
+							"start": {
+								Type:        "string",
+								Description: "Start time (RFC3339 or relative like '-1h', '-30m')",
+							},
+							"end": {
+								Type:        "string",
+								Description: "End time (RFC3339 or 'now')",
+								Default:     "now",
+							},
+						},
+					},
+					"metric_type": {
+						Type:        "string",
+						Description: "Metric type (metrics only, e.g., 'run.googleapis.com/request_count')",
+					},
+					"resource_type": {
+						Type:        "string",
+						Description: "Monitored resource type (metrics only)",
+					},
+					"alignment_period_sec": {
+						Type:        "integer",
+						Description: "Alignment period in seconds (metrics only, default: 60)",
+						Default:     60,
+					},
+					"per_series_aligner": {
+						Type:        "string",
+						Description: "Per-series aligner (metrics only, e.g., 'ALIGN_RATE')",
+					},
+					"cross_series_reducer": {
+						Type:        "string",
+						Description: "Cross-series reducer (metrics only, e.g., 'REDUCE_SUM')",
+					},
+					"group_by_fields": {
+						Type:        "array",
+						Description: "Fields to group by with cross_series_reducer (metrics only)",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"destination": {
+						Type:        "string",
+						Description: "Where to write (default: the configured GCS bucket, else the BigQuery dataset)",
+						Enum:        destinations,
+					},
+					"format": {
+						Type:        "string",
+						Description: "File format for destination gcs (BigQuery tables are always loaded from NDJSON)",
+						Enum:        []string{"ndjson", "csv"},
+						Default:     "ndjson",
+					},
+					"limit": {
+						Type:        "integer",
+						Description: fmt.Sprintf("Maximum log entries (logs, max: %d) or time series (metrics, max: %d) to export (default: the max)", p.cfg.Export.MaxLogEntries, p.cfg.Export.MaxTimeSeries),
+					},
+					"confirm_token": {
+						Type:        "string",
+						Description: "Token returned by the preview call. Omit to preview.",
+					},
+				},
+				Required: []string{"kind", "project_id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[ExportResultResult](),
+			Annotations:  &mcp.ToolAnnotations{ReadOnlyHint: false, DestructiveHint: false},
+		})
+	}
+	return tools
+}
+
+func (p *toolProvider) Handlers() map[string]mcp.ToolHandler {
+	return map[string]mcp.ToolHandler{
+		"ops.golden_signals":       p.client.Handler(func(c *Client) mcp.ToolHandler { return c.GoldenSignalsHandlerWithGuardrail(p.guard) }),
+		"ops.list_resources":       p.client.Handler(func(c *Client) mcp.ToolHandler { return c.ListResourcesHandler() }),
+		"ops.bigquery_overview":    p.client.Handler(func(c *Client) mcp.ToolHandler { return c.BigQueryOverviewHandlerWithGuardrail(p.guard) }),
+		"ops.functions_overview":   p.client.Handler(func(c *Client) mcp.ToolHandler { return c.FunctionsOverviewHandlerWithGuardrail(p.guard) }),
+		"ops.network_flows":        p.client.Handler(func(c *Client) mcp.ToolHandler { return c.NetworkFlowsHandler() }),
+		"ops.nat_overview":         p.client.Handler(func(c *Client) mcp.ToolHandler { return c.NATOverviewHandlerWithGuardrail(p.guard) }),
+		"ops.dataflow_overview":    p.client.Handler(func(c *Client) mcp.ToolHandler { return c.DataflowOverviewHandlerWithGuardrail(p.guard) }),
+		"ops.gcs_overview":         p.client.Handler(func(c *Client) mcp.ToolHandler { return c.GCSOverviewHandlerWithGuardrail(p.guard) }),
+		"ops.scheduler_failures":   p.client.Handler(func(c *Client) mcp.ToolHandler { return c.SchedulerFailuresHandlerWithGuardrail(p.guard) }),
+		"ops.vertex_overview":      p.client.Handler(func(c *Client) mcp.ToolHandler { return c.VertexOverviewHandlerWithGuardrail(p.guard) }),
+		"ops.api_gateway_overview": p.client.Handler(func(c *Client) mcp.ToolHandler { return c.APIGatewayOverviewHandlerWithGuardrail(p.guard) }),
+		"ops.check_quotas":         p.client.Handler(func(c *Client) mcp.ToolHandler { return c.CheckQuotasHandlerWithGuardrail(p.guard) }),
+		"ops.cost_signal":          p.client.Handler(func(c *Client) mcp.ToolHandler { return c.CostSignalHandler(p.cfg.Billing.ExportTable) }),
+		"ops.list_recommendations": p.client.Handler(func(c *Client) mcp.ToolHandler { return c.ListRecommendationsHandler() }),
+		"ops.gcp_service_health":   p.client.Handler(func(c *Client) mcp.ToolHandler { return c.GCPServiceHealthHandler() }),
+		"ops.recent_deployments":   p.client.Handler(func(c *Client) mcp.ToolHandler { return c.RecentDeploymentsHandler() }),
+		"ops.generate_report":      p.client.Handler(func(c *Client) mcp.ToolHandler { return c.GenerateReportHandlerWithGuardrail(p.guard) }),
+		"ops.list_projects": p.client.Handler(func(c *Client) mcp.ToolHandler {
+			return c.ListProjectsHandlerWithGuardrail(p.guard, p.cfg.ProjectAliases)
+		}),
+		"ops.get_config":         GetConfigHandler(p.cfg, p.guard.Limits),
+		"ops.health":             p.healthHandler(),
+		"ops.whoami":             WhoAmIHandler(p.cfg, p.credOpts...),
+		"ops.list_saved_queries": ListSavedQueriesHandler(p.cfg),
+		"ops.run_saved_query":    p.client.Handler(func(c *Client) mcp.ToolHandler { return c.RunSavedQueryHandlerWithGuardrail(p.guard, p.cfg) }),
+		"ops.export_result": p.client.Handler(func(c *Client) mcp.ToolHandler {
+			return c.ExportResultHandlerWithGuardrail(p.guard, p.guard, p.cfg.Export)
+		}),
+	}
+}
+
+func (p *toolProvider) Close() error {
+	return p.client.Close(func(c *Client) error {
+		return errors.Join(c.monitoring.Close(), c.logging.Close())
+	})
+}
+
+// healthHandler はクライアントを作れない（認証情報が壊れている等）場合も
+// 認証情報と設定の診断結果を返す
+func (p *toolProvider) healthHandler() mcp.ToolHandler {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params HealthParams
+		if len(args) > 0 {
+			if err := json.Unmarshal(args, &params); err != nil {
+				return nil, fmt.Errorf("failed to parse arguments: %w", err)
+			}
+		}
+
+		c, err := p.client.Get()
+		if err != nil {
+			return clientErrorHealth(ctx, params, p.cfg, err, p.credOpts...), nil
+		}
+		return c.Health(ctx, params, p.cfg, p.credOpts...)
+	}
+}
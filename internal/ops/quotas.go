@@ -0,0 +1,203 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+)
+
+// CheckQuotasParams are the parameters for ops.check_quotas
+type CheckQuotasParams struct {
+	ProjectID   string               `json:"project_id"`
+	Service     string               `json:"service,omitempty"` // e.g. "compute.googleapis.com"
+	Threshold   float64              `json:"threshold"`         // Usage ratio to flag (default: 0.8)
+	OnlyFlagged bool                 `json:"only_flagged"`
+	TimeRange   monitoring.TimeRange `json:"time_range"`
+	MaxSeries   int                  `json:"max_series"`
+}
+
+// CheckQuotasResult is the result of ops.check_quotas
+type CheckQuotasResult struct {
+	QueryMeta CheckQuotasQueryMeta `json:"query_meta"`
+	Quotas    []QuotaUsage         `json:"quotas"`
+	Stats     CheckQuotasStats     `json:"stats"`
+}
+
+type CheckQuotasQueryMeta struct {
+	OverviewQueryMeta
+	Threshold float64 `json:"threshold"`
+}
+
+type QuotaUsage struct {
+	Service     string  `json:"service"`
+	QuotaMetric string  `json:"quota_metric"`
+	LimitName   string  `json:"limit_name"`
+	Location    string  `json:"location"`
+	Kind        string  `json:"kind"` // "allocation" or "rate"
+	Usage       float64 `json:"usage"`
+	Limit       float64 `json:"limit"`
+	Ratio       float64 `json:"ratio"`
+	Flagged     bool    `json:"flagged"`
+}
+
+type CheckQuotasStats struct {
+	CheckedCount int `json:"checked_count"`
+	FlaggedCount int `json:"flagged_count"`
+}
+
+// CheckQuotas compares quota usage against limits and flags those above the threshold
+func (c *Client) CheckQuotas(ctx context.Context, params CheckQuotasParams) (*CheckQuotasResult, error) {
+	startTime, endTime, err := monitoring.ParseTimeRange(params.TimeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse time range: %w", err)
+	}
+
+	threshold := params.Threshold
+	if threshold <= 0 {
+		threshold = 0.8
+	}
+
+	filter := ""
+	if params.Service != "" {
+		filter = fmt.Sprintf(`resource.labels.service = "%s"`, params.Service)
+	}
+
+	// 期間全体を1点に集約する
+	windowSec := max(int(endTime.Sub(startTime).Seconds()), 60)
+
+	query := func(metricType, aligner string, alignmentSec int) ([]monitoring.TimeSeries, error) {
+		result, err := c.monitoring.QueryTimeSeries(ctx, monitoring.QueryTimeSeriesParams{
+			ProjectID:          params.ProjectID,
+			MetricType:         metricType,
+			ResourceType:       "consumer_quota",
+			Filter:             filter,
+			AlignmentPeriodSec: alignmentSec,
+			PerSeriesAligner:   aligner,
+			TimeRange:          params.TimeRange,
+			MaxSeries:          params.MaxSeries,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result.Series, nil
+	}
+
+	limits, err := query("serviceruntime.googleapis.com/quota/limit", "ALIGN_MAX", windowSec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query quota limits: %w", err)
+	}
+	allocation, err := query("serviceruntime.googleapis.com/quota/allocation/usage", "ALIGN_MAX", windowSec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query allocation quota usage: %w", err)
+	}
+	// レートクォータは1分あたりの使用量で比較する
+	rate, err := query("serviceruntime.googleapis.com/quota/rate/net_usage", "ALIGN_DELTA", 60)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rate quota usage: %w", err)
+	}
+
+	usage := map[string]float64{}
+	usageKind := map[string]string{}
+	for kind, series := range map[string][]monitoring.TimeSeries{"allocation": allocation, "rate": rate} {
+		for _, ts := range series {
+			key := quotaKey(ts)
+			usage[key] = max(usage[key], maxPoint(ts))
+			usageKind[key] = kind
+		}
+	}
+
+	quotas := []QuotaUsage{}
+	flagged := 0
+	for _, ts := range limits {
+		key := quotaKey(ts)
+		kind, ok := usageKind[key]
+		if !ok {
+			continue
+		}
+		limitName := ts.Metric.Labels["limit_name"]
+		// レートクォータは分単位の制限のみ比較可能
+		if kind == "rate" && !strings.Contains(strings.ToLower(limitName), "minute") {
+			continue
+		}
+		limit := maxPoint(ts)
+		if limit <= 0 {
+			continue
+		}
+
+		q := QuotaUsage{
+			Service:     ts.Resource.Labels["service"],
+			QuotaMetric: ts.Metric.Labels["quota_metric"],
+			LimitName:   limitName,
+			Location:    ts.Resource.Labels["location"],
+			Kind:        kind,
+			Usage:       usage[key],
+			Limit:       limit,
+			Ratio:       usage[key] / limit,
+		}
+		q.Flagged = q.Ratio >= threshold
+		if q.Flagged {
+			flagged++
+		} else if params.OnlyFlagged {
+			continue
+		}
+		quotas = append(quotas, q)
+	}
+
+	sort.Slice(quotas, func(i, j int) bool {
+		return quotas[i].Ratio > quotas[j].Ratio
+	})
+
+	return &CheckQuotasResult{
+		QueryMeta: CheckQuotasQueryMeta{
+			OverviewQueryMeta: OverviewQueryMeta{
+				ProjectID: params.ProjectID,
+				Target:    params.Service,
+				Start:     startTime.Format(time.RFC3339),
+				End:       endTime.Format(time.RFC3339),
+			},
+			Threshold: threshold,
+		},
+		Quotas: quotas,
+		Stats: CheckQuotasStats{
+			CheckedCount: len(quotas),
+			FlaggedCount: flagged,
+		},
+	}, nil
+}
+
+// quotaKey はservice/quota_metric/locationで使用量と制限を突き合わせるキー
+func quotaKey(ts monitoring.TimeSeries) string {
+	return strings.Join([]string{
+		ts.Resource.Labels["service"],
+		ts.Metric.Labels["quota_metric"],
+		ts.Resource.Labels["location"],
+	}, "|")
+}
+
+func maxPoint(ts monitoring.TimeSeries) float64 {
+	v := 0.0
+	for _, p := range ts.Points {
+		v = max(v, p.Value)
+	}
+	return v
+}
+
+// CheckQuotasHandlerWithGuardrail returns a handler with guardrail validation
+func (c *Client) CheckQuotasHandlerWithGuardrail(v Validator) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params CheckQuotasParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		// ガードレール: 系列数制限
+		params.MaxSeries = v.ClampTimeSeriesLimit(ctx, params.MaxSeries)
+
+		return c.CheckQuotas(ctx, params)
+	}
+}
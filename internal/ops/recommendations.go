@@ -0,0 +1,180 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ListRecommendationsParams are the parameters for ops.list_recommendations
+type ListRecommendationsParams struct {
+	ProjectID   string `json:"project_id"`
+	Recommender string `json:"recommender"` // Alias (e.g. "idle_vm") or full recommender ID
+	Location    string `json:"location"`    // e.g. "global", "us-central1-a" (default: "global")
+	State       string `json:"state"`       // "ACTIVE" (default), "CLAIMED", "SUCCEEDED", "FAILED", "DISMISSED"
+	Limit       int    `json:"limit"`
+}
+
+// ListRecommendationsResult is the result of ops.list_recommendations
+type ListRecommendationsResult struct {
+	QueryMeta       RecommendationsQueryMeta `json:"query_meta"`
+	Recommendations []Recommendation         `json:"recommendations"`
+	Stats           RecommendationsStats     `json:"stats"`
+}
+
+type RecommendationsQueryMeta struct {
+	ProjectID   string `json:"project_id"`
+	Recommender string `json:"recommender"`
+	Location    string `json:"location"`
+	State       string `json:"state"`
+}
+
+type Recommendation struct {
+	Name            string   `json:"name"`
+	Description     string   `json:"description"`
+	Subtype         string   `json:"subtype,omitempty"`
+	Priority        string   `json:"priority,omitempty"`
+	State           string   `json:"state,omitempty"`
+	Category        string   `json:"category,omitempty"`
+	MonthlyCost     *Money   `json:"cost_projection,omitempty"` // Negative = savings
+	TargetResources []string `json:"target_resources,omitempty"`
+	LastRefreshTime string   `json:"last_refresh_time,omitempty"`
+}
+
+type Money struct {
+	CurrencyCode string  `json:"currency_code"`
+	Amount       float64 `json:"amount"`
+	Duration     string  `json:"duration,omitempty"`
+}
+
+type RecommendationsStats struct {
+	ReturnedCount int  `json:"returned_count"`
+	Truncated     bool `json:"truncated"`
+}
+
+// recommenderAliases はよく使うRecommenderの短縮名
+var recommenderAliases = map[string]string{
+	"idle_vm":       "google.compute.instance.IdleResourceRecommender",
+	"rightsizing":   "google.compute.instance.MachineTypeRecommender",
+	"idle_disk":     "google.compute.disk.IdleResourceRecommender",
+	"idle_ip":       "google.compute.address.IdleResourceRecommender",
+	"idle_cloudsql": "google.cloudsql.instance.IdleRecommender",
+	"iam":           "google.iam.policy.Recommender",
+}
+
+// RecommenderAliases はRecommenderの短縮名一覧を返す
+func RecommenderAliases() []string {
+	aliases := make([]string, 0, len(recommenderAliases))
+	for k := range recommenderAliases {
+		aliases = append(aliases, k)
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// ListRecommendations lists Recommender API findings for a recommender type
+func (c *Client) ListRecommendations(ctx context.Context, params ListRecommendationsParams) (*ListRecommendationsResult, error) {
+	recommender := params.Recommender
+	if id, ok := recommenderAliases[recommender]; ok {
+		recommender = id
+	}
+	location := params.Location
+	if location == "" {
+		location = "global"
+	}
+	state := strings.ToUpper(params.State)
+	if state == "" {
+		state = "ACTIVE"
+	}
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+
+	parent := fmt.Sprintf("projects/%s/locations/%s/recommenders/%s", params.ProjectID, location, recommender)
+
+	recommendations := []Recommendation{}
+	truncated := false
+	pageToken := ""
+
+	for {
+		resp, err := c.recommender.Projects.Locations.Recommenders.Recommendations.List(parent).
+			Context(ctx).
+			Filter(fmt.Sprintf("stateInfo.state = %s", state)).
+			PageSize(int64(limit)).
+			PageToken(pageToken).
+			Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list recommendations: %w", err)
+		}
+
+		for _, r := range resp.Recommendations {
+			if len(recommendations) >= limit {
+				truncated = true
+				break
+			}
+			rec := Recommendation{
+				Name:            r.Name,
+				Description:     r.Description,
+				Subtype:         r.RecommenderSubtype,
+				Priority:        r.Priority,
+				TargetResources: r.TargetResources,
+				LastRefreshTime: r.LastRefreshTime,
+			}
+			if r.StateInfo != nil {
+				rec.State = r.StateInfo.State
+			}
+			if impact := r.PrimaryImpact; impact != nil {
+				rec.Category = impact.Category
+				if p := impact.CostProjection; p != nil && p.Cost != nil {
+					rec.MonthlyCost = &Money{
+						CurrencyCode: p.Cost.CurrencyCode,
+						Amount:       float64(p.Cost.Units) + float64(p.Cost.Nanos)/1e9,
+						Duration:     p.Duration,
+					}
+				}
+			}
+			recommendations = append(recommendations, rec)
+		}
+
+		if truncated || resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	return &ListRecommendationsResult{
+		QueryMeta: RecommendationsQueryMeta{
+			ProjectID:   params.ProjectID,
+			Recommender: recommender,
+			Location:    location,
+			State:       state,
+		},
+		Recommendations: recommendations,
+		Stats: RecommendationsStats{
+			ReturnedCount: len(recommendations),
+			Truncated:     truncated,
+		},
+	}, nil
+}
+
+// ListRecommendationsHandler returns a handler for the ops.list_recommendations tool
+func (c *Client) ListRecommendationsHandler() func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params ListRecommendationsParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.Recommender == "" {
+			return nil, fmt.Errorf("recommender is required")
+		}
+
+		return c.ListRecommendations(ctx, params)
+	}
+}
@@ -0,0 +1,290 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+)
+
+const (
+	// reportPoints はスパークラインの目安の長さ（alignment_period_sec の省略時に期間から決める）
+	reportPoints = 60
+	// reportDeployments は変更履歴に載せるデプロイの上限
+	reportDeployments = 20
+)
+
+// GenerateReportParams are the parameters for ops.generate_report
+type GenerateReportParams struct {
+	ProjectID          string               `json:"project_id"`
+	Kind               string               `json:"kind"` // Same as ops.golden_signals
+	Name               string               `json:"name"`
+	TimeRange          monitoring.TimeRange `json:"time_range"`
+	Title              string               `json:"title,omitempty"`
+	ServiceID          string               `json:"service_id,omitempty"`           // Service Monitoring service for SLOs (default: looked up by name)
+	Location           string               `json:"location,omitempty"`             // Region for Cloud Deploy / regional builds in the change timeline
+	AlignmentPeriodSec int                  `json:"alignment_period_sec,omitempty"` // Default: about 60 points over the window
+	MaxSeries          int                  `json:"max_series,omitempty"`           // Per signal (default: 5)
+	ErrorLimit         int                  `json:"error_limit,omitempty"`          // Error groups (default: 10)
+}
+
+// GenerateReport composes a Markdown report of a resource over a window: golden signals with
+// sparklines, top errors, deployments and SLO status. A section that fails shows its error instead.
+func (c *Client) GenerateReport(ctx context.Context, params GenerateReportParams) (string, error) {
+	kind, err := c.goldenSignalKind(params.Kind)
+	if err != nil {
+		return "", err
+	}
+	logFilter, err := kindLogFilter(kind, params.ProjectID, params.Name)
+	if err != nil {
+		return "", err
+	}
+	start, end, err := monitoring.ParseTimeRange(params.TimeRange)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse time range: %w", err)
+	}
+	// 各セクションが同じ期間を見るよう、相対指定を解決済みの時刻で渡す
+	tr := monitoring.TimeRange{Start: start.UTC().Format(time.RFC3339), End: end.UTC().Format(time.RFC3339)}
+
+	alignment := params.AlignmentPeriodSec
+	if alignment <= 0 {
+		alignment = max(60, int(end.Sub(start).Seconds())/reportPoints/60*60)
+	}
+	maxSeries := params.MaxSeries
+	if maxSeries <= 0 {
+		maxSeries = 5
+	}
+	errorLimit := params.ErrorLimit
+	if errorLimit <= 0 {
+		errorLimit = 10
+	}
+	title := params.Title
+	if title == "" {
+		title = fmt.Sprintf("%s report: %s", params.Name, start.UTC().Format("2006-01-02 15:04 MST"))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	fmt.Fprintf(&b, "- **Project**: `%s`\n", params.ProjectID)
+	fmt.Fprintf(&b, "- **Resource**: %s `%s`\n", params.Kind, params.Name)
+	fmt.Fprintf(&b, "- **Window**: %s → %s (%s)\n", tr.Start, tr.End, end.Sub(start).Round(time.Minute))
+	fmt.Fprintf(&b, "- **Generated**: %s\n", time.Now().UTC().Format(time.RFC3339))
+
+	// 主要メトリクス（ゴールデンシグナル）
+	signals := c.querySignals(ctx, params.ProjectID, kind, kindFilter(kind, params.Name, ""), tr, alignment, maxSeries)
+	b.WriteString("\n## Key metrics\n\n")
+	rows := [][]string{}
+	for _, s := range signals {
+		if s.Error != "" {
+			rows = append(rows, []string{s.Name, "_error: " + s.Error + "_", "", "", "", "", ""})
+			continue
+		}
+		if len(s.Series) == 0 {
+			rows = append(rows, []string{s.Name, "_no data_", "", "", "", "", ""})
+			continue
+		}
+		for _, ss := range monitoring.ToSparklines(&monitoring.QueryTimeSeriesResult{Series: s.Series}).Series {
+			rows = append(rows, []string{s.Name, ss.Label, "`" + ss.Sparkline + "`",
+				reportNumber(ss.Min), reportNumber(ss.Avg), reportNumber(ss.Max), reportNumber(ss.Last)})
+		}
+	}
+	writeMarkdownTable(&b, []string{"Signal", "Series", "Trend", "Min", "Avg", "Max", "Last"}, rows)
+	fmt.Fprintf(&b, "\nTrend runs oldest → newest, one character per %ds.\n", alignment)
+
+	// エラーの上位
+	b.WriteString("\n## Top errors\n\n")
+	topErrors, err := c.logging.TopErrors(ctx, logging.TopErrorsParams{
+		ProjectID: params.ProjectID,
+		TimeRange: logging.TimeRange{Start: tr.Start, End: tr.End},
+		GroupBy:   "message",
+		Limit:     errorLimit,
+		Filter:    logFilter,
+	})
+	switch {
+	case err != nil:
+		fmt.Fprintf(&b, "_Unavailable: %s_\n", err)
+	case len(topErrors.ErrorGroups) == 0:
+		fmt.Fprintf(&b, "No entries at ERROR or above (`%s`).\n", logFilter)
+	default:
+		fmt.Fprintf(&b, "%d entries at ERROR or above in %d groups (`%s`, first %d entries scanned).\n\n",
+			topErrors.Stats.TotalErrors, topErrors.Stats.UniqueGroups, logFilter, topErrors.Stats.ScannedLogs)
+		rows = [][]string{}
+		for _, g := range topErrors.ErrorGroups {
+			message := g.Key
+			if message == "" {
+				message = "_(no message)_"
+			}
+			rows = append(rows, []string{fmt.Sprint(g.Count), fmt.Sprintf("%.1f%%", g.Percentage), g.FirstSeen, g.LastSeen, message})
+		}
+		writeMarkdownTable(&b, []string{"Count", "Share", "First seen", "Last seen", "Message"}, rows)
+	}
+
+	// 変更履歴（プロジェクトのデプロイのうち name を含むもの）
+	b.WriteString("\n## Change timeline\n\n")
+	deployments, err := c.RecentDeployments(ctx, RecentDeploymentsParams{ProjectID: params.ProjectID, Location: params.Location, TimeRange: tr})
+	if err != nil {
+		fmt.Fprintf(&b, "_Unavailable: %s_\n", err)
+	} else {
+		matched := []DeploymentEvent{}
+		for _, d := range deployments.Deployments {
+			if strings.Contains(deploymentText(d), params.Name) && len(matched) < reportDeployments {
+				matched = append(matched, d)
+			}
+		}
+		if len(matched) == 0 {
+			fmt.Fprintf(&b, "No Cloud Build / Cloud Deploy events mentioning `%s` (%d in the project).\n", params.Name, len(deployments.Deployments))
+		} else {
+			rows = [][]string{}
+			// 古い順に並べる（RecentDeployments は新しい順）
+			for i := len(matched) - 1; i >= 0; i-- {
+				d := matched[i]
+				rows = append(rows, []string{d.Time, d.Source, d.Status, deploymentSummary(d)})
+			}
+			writeMarkdownTable(&b, []string{"Time", "Source", "Status", "Change"}, rows)
+		}
+		for _, source := range slices.Sorted(maps.Keys(deployments.Errors)) {
+			fmt.Fprintf(&b, "\n_%s unavailable: %s_\n", source, deployments.Errors[source])
+		}
+	}
+
+	// SLO の状態
+	b.WriteString("\n## SLO status\n\n")
+	b.WriteString(c.reportSLOs(ctx, params, end))
+
+	return b.String(), nil
+}
+
+// reportSLOs は SLO セクションの本文を返す（サービスが見つからなければその旨）
+func (c *Client) reportSLOs(ctx context.Context, params GenerateReportParams, end time.Time) string {
+	service := ""
+	if params.ServiceID != "" {
+		service = fmt.Sprintf("projects/%s/services/%s", params.ProjectID, params.ServiceID)
+	} else {
+		found, err := c.monitoring.FindService(ctx, params.ProjectID, params.Name)
+		if err != nil {
+			return fmt.Sprintf("_Unavailable: %s_\n", err)
+		}
+		if found == "" {
+			return fmt.Sprintf("No Service Monitoring service matches `%s` (set service_id).\n", params.Name)
+		}
+		service = found
+	}
+
+	slos, err := c.monitoring.ServiceSLOs(ctx, service, end)
+	if err != nil {
+		return fmt.Sprintf("_Unavailable: %s_\n", err)
+	}
+	if len(slos) == 0 {
+		return fmt.Sprintf("Service `%s` has no SLOs.\n", service)
+	}
+	var b strings.Builder
+	rows := [][]string{}
+	for _, s := range slos {
+		name := s.DisplayName
+		if name == "" {
+			name = s.ID
+		}
+		compliance, budget, status := "n/a", "n/a", "no data"
+		if s.Compliance != nil {
+			compliance = fmt.Sprintf("%.3f%%", *s.Compliance*100)
+			status = "met"
+			if *s.Compliance < s.Goal {
+				status = "**below goal**"
+			}
+		}
+		if s.BudgetLeft != nil {
+			budget = fmt.Sprintf("%.1f%%", *s.BudgetLeft*100)
+			if *s.BudgetLeft <= 0 {
+				status = "**budget exhausted**"
+			}
+		}
+		if s.Error != "" && s.Compliance == nil && s.BudgetLeft == nil {
+			status = "error: " + s.Error
+		}
+		rows = append(rows, []string{name, fmt.Sprintf("%.2f%%", s.Goal*100), s.Period, compliance, budget, status})
+	}
+	fmt.Fprintf(&b, "As of %s, service `%s`.\n\n", end.UTC().Format(time.RFC3339), monitoringServiceID(service))
+	writeMarkdownTable(&b, []string{"SLO", "Goal", "Period", "Compliance", "Error budget left", "Status"}, rows)
+	return b.String()
+}
+
+// monitoringServiceID はサービスのリソース名の最後の要素を返す
+func monitoringServiceID(name string) string {
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+// deploymentText はデプロイが name を含むか調べる対象の文字列
+func deploymentText(d DeploymentEvent) string {
+	return strings.Join(append([]string{d.ID, d.Pipeline, d.Release, d.Target, d.Trigger, d.Detail}, d.Images...), " ")
+}
+
+// deploymentSummary は変更履歴の1行の説明
+func deploymentSummary(d DeploymentEvent) string {
+	parts := []string{}
+	for _, p := range [][2]string{{"pipeline", d.Pipeline}, {"release", d.Release}, {"target", d.Target}, {"trigger", d.Trigger}} {
+		if p[1] != "" {
+			parts = append(parts, p[0]+" "+p[1])
+		}
+	}
+	if len(d.Images) > 0 {
+		parts = append(parts, "image "+strings.Join(d.Images, ", "))
+	}
+	if len(parts) == 0 {
+		parts = append(parts, d.ID)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// reportNumber は表の数値（有効数字4桁）
+func reportNumber(v float64) string {
+	return fmt.Sprintf("%.4g", v)
+}
+
+// writeMarkdownTable は Markdown の表を書く（セルの | と改行はエスケープする）
+func writeMarkdownTable(b *strings.Builder, header []string, rows [][]string) {
+	cell := strings.NewReplacer("|", `\|`, "\n", " ", "\r", "")
+	b.WriteString("| " + strings.Join(header, " | ") + " |\n")
+	b.WriteString("|" + strings.Repeat(" --- |", len(header)) + "\n")
+	for _, row := range rows {
+		escaped := make([]string, len(row))
+		for i, v := range row {
+			escaped[i] = cell.Replace(v)
+		}
+		b.WriteString("| " + strings.Join(escaped, " | ") + " |\n")
+	}
+}
+
+// GenerateReportHandlerWithGuardrail returns a handler with guardrail validation
+func (c *Client) GenerateReportHandlerWithGuardrail(v Validator) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params GenerateReportParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.Kind == "" {
+			return nil, fmt.Errorf("kind is required")
+		}
+		if params.Name == "" {
+			return nil, fmt.Errorf("name is required")
+		}
+
+		// ガードレール: 系列数制限（エラーグループ数は logging.top_errors と同じく50まで）
+		if params.MaxSeries > 0 {
+			params.MaxSeries = v.ClampTimeSeriesLimit(ctx, params.MaxSeries)
+		}
+
+		report, err := c.GenerateReport(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return mcp.Text(report), nil
+	}
+}
@@ -0,0 +1,210 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+)
+
+// ListResourcesParams are the parameters for ops.list_resources
+type ListResourcesParams struct {
+	ProjectID string               `json:"project_id"`
+	Kinds     []string             `json:"kinds,omitempty"` // Optional: restrict to these kinds
+	TimeRange monitoring.TimeRange `json:"time_range"`
+	Limit     int                  `json:"limit"` // Per kind
+}
+
+// ListResourcesResult is the result of ops.list_resources
+type ListResourcesResult struct {
+	QueryMeta ListResourcesQueryMeta `json:"query_meta"`
+	Resources []DiscoveredResource   `json:"resources"`
+	Stats     ListResourcesStats     `json:"stats"`
+	Errors    map[string]string      `json:"errors,omitempty"` // kind -> error
+}
+
+type ListResourcesQueryMeta struct {
+	ProjectID string   `json:"project_id"`
+	Kinds     []string `json:"kinds"`
+	Start     string   `json:"start"`
+	End       string   `json:"end"`
+}
+
+type DiscoveredResource struct {
+	Kind         string            `json:"kind"`
+	ResourceType string            `json:"resource_type"`
+	Labels       map[string]string `json:"labels"`
+}
+
+type ListResourcesStats struct {
+	CountByKind   map[string]int `json:"count_by_kind"`
+	ReturnedCount int            `json:"returned_count"`
+}
+
+// discoveryKind はテレメトリからリソースを探索するための定義
+type discoveryKind struct {
+	resourceType string
+	metricType   string   // 対象リソースが常に出しているメトリクス
+	labelKeys    []string // リソースを識別するラベル（この組で重複排除する）
+}
+
+// discoveryRegistry は探索対象のリソース種別
+var discoveryRegistry = map[string]discoveryKind{
+	"cloud_run_service": {
+		resourceType: "cloud_run_revision",
+		metricType:   "run.googleapis.com/container/instance_count",
+		labelKeys:    []string{"service_name", "location"},
+	},
+	"gke_cluster": {
+		resourceType: "k8s_node",
+		metricType:   "kubernetes.io/node/cpu/allocatable_utilization",
+		labelKeys:    []string{"cluster_name", "location"},
+	},
+	"gce_instance": {
+		resourceType: "gce_instance",
+		metricType:   "compute.googleapis.com/instance/uptime",
+		labelKeys:    []string{"instance_id", "zone"},
+	},
+	"cloudsql_database": {
+		resourceType: "cloudsql_database",
+		metricType:   "cloudsql.googleapis.com/database/up",
+		labelKeys:    []string{"database_id", "region"},
+	},
+	"cloud_function": {
+		resourceType: "cloud_function",
+		metricType:   "cloudfunctions.googleapis.com/function/execution_count",
+		labelKeys:    []string{"function_name", "region"},
+	},
+	"pubsub_topic": {
+		resourceType: "pubsub_topic",
+		metricType:   "pubsub.googleapis.com/topic/send_request_count",
+		labelKeys:    []string{"topic_id"},
+	},
+	"pubsub_subscription": {
+		resourceType: "pubsub_subscription",
+		metricType:   "pubsub.googleapis.com/subscription/num_undelivered_messages",
+		labelKeys:    []string{"subscription_id"},
+	},
+	"gcs_bucket": {
+		resourceType: "gcs_bucket",
+		metricType:   "storage.googleapis.com/api/request_count",
+		labelKeys:    []string{"bucket_name", "location"},
+	},
+	"http_lb": {
+		resourceType: "https_lb_rule",
+		metricType:   "loadbalancing.googleapis.com/https/request_count",
+		labelKeys:    []string{"url_map_name", "forwarding_rule_name"},
+	},
+}
+
+// DiscoveryKinds は探索可能なリソース種別を返す
+func DiscoveryKinds() []string {
+	kinds := make([]string, 0, len(discoveryRegistry))
+	for k := range discoveryRegistry {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// ListResources lists resources that emitted telemetry in the given window
+func (c *Client) ListResources(ctx context.Context, params ListResourcesParams) (*ListResourcesResult, error) {
+	startTime, endTime, err := monitoring.ParseTimeRange(params.TimeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse time range: %w", err)
+	}
+
+	kinds := params.Kinds
+	if len(kinds) == 0 {
+		kinds = DiscoveryKinds()
+	}
+	for _, k := range kinds {
+		if _, ok := discoveryRegistry[k]; !ok {
+			return nil, fmt.Errorf("unsupported kind: %s (supported: %v)", k, DiscoveryKinds())
+		}
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+
+	resources := []DiscoveredResource{}
+	countByKind := map[string]int{}
+	errs := map[string]string{}
+
+	for _, k := range kinds {
+		kind := discoveryRegistry[k]
+		filter := fmt.Sprintf(`metric.type = "%s" AND resource.type = "%s"`, kind.metricType, kind.resourceType)
+
+		// 系列はリソースより細かい（revision・metricラベル単位）ので多めに取得して重複排除する
+		series, err := c.monitoring.ListSeriesHeaders(ctx, params.ProjectID, filter, startTime, endTime, limit*10)
+		if err != nil {
+			errs[k] = err.Error()
+			continue
+		}
+
+		seen := map[string]bool{}
+		for _, ts := range series {
+			labels := make(map[string]string, len(kind.labelKeys))
+			parts := make([]string, 0, len(kind.labelKeys))
+			for _, key := range kind.labelKeys {
+				labels[key] = ts.Resource.Labels[key]
+				parts = append(parts, ts.Resource.Labels[key])
+			}
+			id := strings.Join(parts, "\x00")
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			resources = append(resources, DiscoveredResource{
+				Kind:         k,
+				ResourceType: kind.resourceType,
+				Labels:       labels,
+			})
+			countByKind[k]++
+
+			if countByKind[k] >= limit {
+				break
+			}
+		}
+	}
+
+	result := &ListResourcesResult{
+		QueryMeta: ListResourcesQueryMeta{
+			ProjectID: params.ProjectID,
+			Kinds:     kinds,
+			Start:     startTime.Format(time.RFC3339),
+			End:       endTime.Format(time.RFC3339),
+		},
+		Resources: resources,
+		Stats: ListResourcesStats{
+			CountByKind:   countByKind,
+			ReturnedCount: len(resources),
+		},
+	}
+	if len(errs) > 0 {
+		result.Errors = errs
+	}
+	return result, nil
+}
+
+// ListResourcesHandler returns a handler for the ops.list_resources tool
+func (c *Client) ListResourcesHandler() func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params ListResourcesParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		return c.ListResources(ctx, params)
+	}
+}
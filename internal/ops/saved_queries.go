@@ -0,0 +1,234 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+)
+
+// placeholderPattern は保存クエリ内の {{param}} プレースホルダ
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// ListSavedQueriesResult is the result of ops.list_saved_queries
+type ListSavedQueriesResult struct {
+	Queries []SavedQueryInfo `json:"queries"`
+}
+
+type SavedQueryInfo struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Kind        string            `json:"kind"`
+	ProjectID   string            `json:"project_id,omitempty"`
+	Params      []string          `json:"params"`
+	Defaults    map[string]string `json:"defaults,omitempty"`
+	Filter      string            `json:"filter,omitempty"`
+	MetricType  string            `json:"metric_type,omitempty"`
+}
+
+// RunSavedQueryParams are the parameters for ops.run_saved_query
+type RunSavedQueryParams struct {
+	Name      string               `json:"name"`
+	ProjectID string               `json:"project_id"`
+	Params    map[string]string    `json:"params,omitempty"`
+	TimeRange monitoring.TimeRange `json:"time_range"`
+	Limit     int                  `json:"limit"` // Log entries (logs) or series (metrics)
+}
+
+// RunSavedQueryResult is the result of ops.run_saved_query
+type RunSavedQueryResult struct {
+	Query   SavedQueryInfo                    `json:"query"`
+	Logs    *logging.QueryResult              `json:"logs,omitempty"`
+	Metrics *monitoring.QueryTimeSeriesResult `json:"metrics,omitempty"`
+}
+
+// ListSavedQueries は保存クエリの一覧を返す
+func ListSavedQueries(cfg *config.Config) *ListSavedQueriesResult {
+	queries := make([]SavedQueryInfo, 0, len(cfg.SavedQueries))
+	for _, q := range cfg.SavedQueries {
+		queries = append(queries, savedQueryInfo(q))
+	}
+	sort.Slice(queries, func(i, j int) bool {
+		return queries[i].Name < queries[j].Name
+	})
+	return &ListSavedQueriesResult{Queries: queries}
+}
+
+func savedQueryInfo(q config.SavedQuery) SavedQueryInfo {
+	return SavedQueryInfo{
+		Name:        q.Name,
+		Description: q.Description,
+		Kind:        q.Kind,
+		ProjectID:   q.ProjectID,
+		Params:      placeholders(q.Filter, q.MetricType, q.Resource),
+		Defaults:    q.Defaults,
+		Filter:      q.Filter,
+		MetricType:  q.MetricType,
+	}
+}
+
+// placeholders はテンプレートに含まれるパラメータ名を重複なしで返す
+func placeholders(templates ...string) []string {
+	seen := map[string]bool{}
+	names := []string{}
+	for _, t := range templates {
+		for _, m := range placeholderPattern.FindAllStringSubmatch(t, -1) {
+			if !seen[m[1]] {
+				seen[m[1]] = true
+				names = append(names, m[1])
+			}
+		}
+	}
+	return names
+}
+
+// substitute は {{param}} を値で置換する。値はフィルタの文字列リテラル内に入る前提でエスケープする
+func substitute(template string, values map[string]string) (string, error) {
+	var missing []string
+	out := placeholderPattern.ReplaceAllStringFunc(template, func(m string) string {
+		name := placeholderPattern.FindStringSubmatch(m)[1]
+		v, ok := values[name]
+		if !ok {
+			missing = append(missing, name)
+			return m
+		}
+		return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v)
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing params: %s", strings.Join(missing, ", "))
+	}
+	return out, nil
+}
+
+// RunSavedQuery executes a saved query with parameter substitution
+func (c *Client) RunSavedQuery(ctx context.Context, q config.SavedQuery, params RunSavedQueryParams) (*RunSavedQueryResult, error) {
+	values := map[string]string{}
+	for k, v := range q.Defaults {
+		values[k] = v
+	}
+	for k, v := range params.Params {
+		values[k] = v
+	}
+
+	filter, err := substitute(q.Filter, values)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RunSavedQueryResult{Query: savedQueryInfo(q)}
+	result.Query.Filter = filter
+
+	switch q.Kind {
+	case config.SavedQueryLogs:
+		logs, err := c.logging.Query(ctx, logging.QueryParams{
+			ProjectID: params.ProjectID,
+			Filter:    filter,
+			TimeRange: logging.TimeRange(params.TimeRange),
+			Limit:     params.Limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		result.Logs = logs
+
+	case config.SavedQueryMetrics:
+		metricType, err := substitute(q.MetricType, values)
+		if err != nil {
+			return nil, err
+		}
+		resourceType, err := substitute(q.Resource, values)
+		if err != nil {
+			return nil, err
+		}
+		result.Query.MetricType = metricType
+		metrics, err := c.monitoring.QueryTimeSeries(ctx, monitoring.QueryTimeSeriesParams{
+			ProjectID:          params.ProjectID,
+			MetricType:         metricType,
+			ResourceType:       resourceType,
+			Filter:             filter,
+			PerSeriesAligner:   q.Aligner,
+			CrossSeriesReducer: q.Reducer,
+			GroupByFields:      q.GroupBy,
+			TimeRange:          params.TimeRange,
+			MaxSeries:          params.Limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		result.Metrics = metrics
+
+	default:
+		return nil, fmt.Errorf("unsupported saved query kind: %s", q.Kind)
+	}
+
+	return result, nil
+}
+
+// ListSavedQueriesHandler returns a handler that lists saved queries
+func ListSavedQueriesHandler(cfg *config.Config) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		return ListSavedQueries(cfg), nil
+	}
+}
+
+// RunSavedQueryHandlerWithGuardrail returns a handler with guardrail validation
+func (c *Client) RunSavedQueryHandlerWithGuardrail(v Validator, cfg *config.Config) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params RunSavedQueryParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.Name == "" {
+			return nil, fmt.Errorf("name is required")
+		}
+		q, ok := cfg.FindSavedQuery(params.Name)
+		if !ok {
+			return nil, fmt.Errorf("saved query not found: %s", params.Name)
+		}
+
+		// プロジェクト: 引数 > 保存クエリの定義
+		if params.ProjectID == "" {
+			params.ProjectID = cfg.ResolveProjectAlias(q.ProjectID)
+		}
+		if params.ProjectID == "" {
+			return nil, fmt.Errorf("project_id is required")
+		}
+
+		// ガードレール: プロジェクトID検証
+		if err := v.ValidateProjectID(ctx, params.ProjectID); err != nil {
+			return nil, err
+		}
+
+		// 時間範囲: 未指定なら保存クエリのデフォルト
+		if params.TimeRange.Start == "" && q.TimeRange != "" {
+			params.TimeRange.Start = q.TimeRange
+		}
+
+		// 時間範囲のパース
+		startTime, endTime, err := monitoring.ParseTimeRange(params.TimeRange)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse time range: %w", err)
+		}
+
+		// ガードレール: 時間範囲検証
+		if err := v.ValidateTimeRange(ctx, startTime, endTime); err != nil {
+			return nil, err
+		}
+
+		// ガードレール: 件数制限
+		if q.Kind == config.SavedQueryMetrics {
+			params.Limit = v.ClampTimeSeriesLimit(ctx, params.Limit)
+		} else {
+			params.Limit = v.ClampLogLimit(ctx, params.Limit)
+		}
+
+		return c.RunSavedQuery(ctx, q, params)
+	}
+}
@@ -0,0 +1,241 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+)
+
+// SchedulerFailuresParams are the parameters for ops.scheduler_failures
+type SchedulerFailuresParams struct {
+	ProjectID          string               `json:"project_id"`
+	Location           string               `json:"location,omitempty"`
+	Job                string               `json:"job,omitempty"`   // Cloud Scheduler job ID
+	Queue              string               `json:"queue,omitempty"` // Cloud Tasks queue ID
+	TimeRange          monitoring.TimeRange `json:"time_range"`
+	AlignmentPeriodSec int                  `json:"alignment_period_sec"`
+	Limit              int                  `json:"limit"` // Max jobs / queues / error logs
+}
+
+// SchedulerFailuresResult is the result of ops.scheduler_failures
+type SchedulerFailuresResult struct {
+	QueryMeta     OverviewQueryMeta     `json:"query_meta"`
+	SchedulerJobs []SchedulerJobFailure `json:"scheduler_jobs"` // Most failures first
+	Queues        []QueueFailure        `json:"queues"`         // Most failed attempts first
+	QueueSignals  []Signal              `json:"queue_signals"`
+	TaskErrorLogs []logging.LogEntry    `json:"task_error_logs"`
+	Stats         SchedulerStats        `json:"stats"`
+	Errors        map[string]string     `json:"errors,omitempty"` // section -> error
+}
+
+// SchedulerJobFailure は Cloud Scheduler のジョブごとの失敗した実行
+type SchedulerJobFailure struct {
+	Job         string         `json:"job"`
+	Location    string         `json:"location,omitempty"`
+	Failures    int            `json:"failures"`
+	Statuses    map[string]int `json:"statuses"` // e.g. "UNAVAILABLE", "DEADLINE_EXCEEDED"
+	LastFailure string         `json:"last_failure"`
+	LastStatus  string         `json:"last_status,omitempty"`
+	LastDetail  string         `json:"last_detail,omitempty"` // debugInfo of the latest failure
+	Target      string         `json:"target,omitempty"`      // Target URL / topic of the latest failure
+}
+
+// QueueFailure は Cloud Tasks のキューごとの試行と失敗
+type QueueFailure struct {
+	Queue          string         `json:"queue"`
+	Location       string         `json:"location,omitempty"`
+	Attempts       int64          `json:"attempts"`
+	FailedAttempts int64          `json:"failed_attempts"`
+	FailedByCode   map[string]int `json:"failed_by_code,omitempty"`
+	PeakDepth      float64        `json:"peak_depth"` // Tasks waiting (growing = retries piling up)
+}
+
+type SchedulerStats struct {
+	SchedulerFailures  int   `json:"scheduler_failures"`
+	ScannedLogs        int   `json:"scanned_logs"`
+	TaskAttempts       int64 `json:"task_attempts"`
+	FailedTaskAttempts int64 `json:"failed_task_attempts"`
+}
+
+// schedulerMaxScan はジョブごとの集計のために走査する失敗ログの上限
+const schedulerMaxScan = 2000
+
+// SchedulerFailures lists failed Cloud Scheduler job executions and Cloud Tasks queues
+// with failed (retried) attempts, together with the queues' error logs
+func (c *Client) SchedulerFailures(ctx context.Context, params SchedulerFailuresParams) (*SchedulerFailuresResult, error) {
+	startTime, endTime, err := monitoring.ParseTimeRange(params.TimeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse time range: %w", err)
+	}
+
+	result := &SchedulerFailuresResult{
+		QueryMeta: OverviewQueryMeta{
+			ProjectID: params.ProjectID,
+			Start:     startTime.Format(time.RFC3339),
+			End:       endTime.Format(time.RFC3339),
+		},
+		SchedulerJobs: []SchedulerJobFailure{},
+		Queues:        []QueueFailure{},
+		TaskErrorLogs: []logging.LogEntry{},
+	}
+	errs := map[string]string{}
+
+	// Cloud Scheduler: 失敗した実行は AttemptFinished のログが ERROR で残る（メトリクスはない）
+	// キューだけを指定したときは Cloud Scheduler を見ない（逆も同様）
+	if params.Queue == "" {
+		kind := c.kinds["cloud_scheduler_job"]
+		filter, err := kindLogFilter(kind, params.ProjectID, params.Job)
+		if err != nil {
+			return nil, err
+		}
+		filter = joinFilter(filter, kindFilter(kind, params.Job, params.Location), "%s")
+		jobs := map[string]*SchedulerJobFailure{}
+		scanned, err := c.logging.ScanEntries(ctx, params.ProjectID, filter, startTime, endTime, schedulerMaxScan, func(e logging.LogEntry) {
+			name := e.Resource.Labels["job_id"]
+			job, ok := jobs[name]
+			if !ok {
+				// 新しい順なので最初のエントリが直近の失敗
+				job = &SchedulerJobFailure{
+					Job:         name,
+					Location:    e.Resource.Labels["location"],
+					Statuses:    map[string]int{},
+					LastFailure: e.Timestamp,
+					LastStatus:  payloadString(e.JSONPayload["status"]),
+					LastDetail:  payloadString(e.JSONPayload["debugInfo"]),
+					Target:      payloadString(e.JSONPayload["url"]),
+				}
+				if job.Target == "" {
+					job.Target = payloadString(e.JSONPayload["topic"])
+				}
+				if job.LastDetail == "" {
+					job.LastDetail = e.TextPayload
+				}
+				jobs[name] = job
+			}
+			job.Failures++
+			status := payloadString(e.JSONPayload["status"])
+			if status == "" {
+				status = "UNKNOWN"
+			}
+			job.Statuses[status]++
+		})
+		if err != nil {
+			errs["scheduler"] = err.Error()
+		}
+		result.Stats.ScannedLogs = scanned
+		result.Stats.SchedulerFailures = scanned
+		for _, job := range jobs {
+			result.SchedulerJobs = append(result.SchedulerJobs, *job)
+		}
+		sort.Slice(result.SchedulerJobs, func(i, j int) bool {
+			a, b := result.SchedulerJobs[i], result.SchedulerJobs[j]
+			if a.Failures != b.Failures {
+				return a.Failures > b.Failures
+			}
+			return a.LastFailure > b.LastFailure
+		})
+		if len(result.SchedulerJobs) > params.Limit {
+			result.SchedulerJobs = result.SchedulerJobs[:params.Limit]
+		}
+	}
+
+	// Cloud Tasks: 試行の失敗（リトライされる）をレスポンスコード別に、滞留をキューごとに見る
+	if params.Job == "" {
+		kind := c.kinds["cloud_tasks_queue"]
+		filter := kindFilter(kind, params.Queue, params.Location)
+		result.QueueSignals = c.querySignals(ctx, params.ProjectID, kind, filter, params.TimeRange, params.AlignmentPeriodSec, 100)
+		result.Queues = queueFailures(result.QueueSignals)
+		for _, q := range result.Queues {
+			result.Stats.TaskAttempts += q.Attempts
+			result.Stats.FailedTaskAttempts += q.FailedAttempts
+		}
+		if len(result.Queues) > params.Limit {
+			result.Queues = result.Queues[:params.Limit]
+		}
+
+		// タスクのログはキューでログ記録を有効にした場合のみ
+		logs, err := c.queryKindLogs(ctx, params.ProjectID, kind, params.Queue, filter, params.TimeRange, params.Limit)
+		if err != nil {
+			errs["task_error_logs"] = err.Error()
+		} else {
+			result.TaskErrorLogs = logs
+		}
+	} else {
+		result.QueueSignals = []Signal{}
+	}
+
+	if len(errs) > 0 {
+		result.Errors = errs
+	}
+	return result, nil
+}
+
+// queueFailures はキューのシグナルをキューごとの試行・失敗・滞留にまとめる
+func queueFailures(signals []Signal) []QueueFailure {
+	queues := map[string]*QueueFailure{}
+	for _, s := range signals {
+		for _, ts := range s.Series {
+			key := ts.Resource.Labels["location"] + "/" + ts.Resource.Labels["queue_id"]
+			q, ok := queues[key]
+			if !ok {
+				q = &QueueFailure{Queue: ts.Resource.Labels["queue_id"], Location: ts.Resource.Labels["location"]}
+				queues[key] = q
+			}
+			for _, p := range ts.Points {
+				switch s.Name {
+				case "task_attempts":
+					n := int64(p.Value)
+					q.Attempts += n
+					if code := ts.Metric.Labels["response_code"]; code != "OK" && n > 0 {
+						q.FailedAttempts += n
+						if q.FailedByCode == nil {
+							q.FailedByCode = map[string]int{}
+						}
+						q.FailedByCode[code] += int(n)
+					}
+				case "depth":
+					q.PeakDepth = max(q.PeakDepth, p.Value)
+				}
+			}
+		}
+	}
+
+	result := make([]QueueFailure, 0, len(queues))
+	for _, q := range queues {
+		result = append(result, *q)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].FailedAttempts != result[j].FailedAttempts {
+			return result[i].FailedAttempts > result[j].FailedAttempts
+		}
+		return result[i].PeakDepth > result[j].PeakDepth
+	})
+	return result
+}
+
+// SchedulerFailuresHandlerWithGuardrail returns a handler with guardrail validation
+func (c *Client) SchedulerFailuresHandlerWithGuardrail(v Validator) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params SchedulerFailuresParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.Job != "" && params.Queue != "" {
+			return nil, fmt.Errorf("specify either job or queue, not both")
+		}
+
+		// ガードレール: 件数制限
+		if params.Limit <= 0 {
+			params.Limit = 20
+		}
+		params.Limit = v.ClampLogLimit(ctx, params.Limit)
+
+		return c.SchedulerFailures(ctx, params)
+	}
+}
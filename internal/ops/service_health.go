@@ -0,0 +1,259 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	serviceHealthEndpoint = "https://servicehealth.googleapis.com/v1"
+	publicIncidentsURL    = "https://status.cloud.google.com/incidents.json"
+)
+
+// ServiceHealthParams are the parameters for ops.gcp_service_health
+type ServiceHealthParams struct {
+	ProjectID string   `json:"project_id"`
+	Products  []string `json:"products,omitempty"`  // Optional product name filter (substring, e.g. "Cloud Run")
+	Locations []string `json:"locations,omitempty"` // Optional location filter (e.g. "asia-northeast1")
+	Source    string   `json:"source"`              // "auto" (default), "personalized", "public"
+}
+
+// ServiceHealthResult is the result of ops.gcp_service_health
+type ServiceHealthResult struct {
+	QueryMeta ServiceHealthQueryMeta `json:"query_meta"`
+	Incidents []Incident             `json:"incidents"`
+	Errors    map[string]string      `json:"errors,omitempty"`
+}
+
+type ServiceHealthQueryMeta struct {
+	ProjectID string `json:"project_id"`
+	Source    string `json:"source"` // Source actually used
+}
+
+type Incident struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	State       string   `json:"state"`
+	Relevance   string   `json:"relevance,omitempty"` // Personalized Service Health only
+	Products    []string `json:"products"`
+	Locations   []string `json:"locations"`
+	StartTime   string   `json:"start_time,omitempty"`
+	UpdateTime  string   `json:"update_time,omitempty"`
+	URI         string   `json:"uri,omitempty"`
+}
+
+// GCPServiceHealth returns active Google-side incidents relevant to the project
+func (c *Client) GCPServiceHealth(ctx context.Context, params ServiceHealthParams) (*ServiceHealthResult, error) {
+	source := params.Source
+	if source == "" {
+		source = "auto"
+	}
+
+	result := &ServiceHealthResult{
+		QueryMeta: ServiceHealthQueryMeta{ProjectID: params.ProjectID},
+	}
+
+	var (
+		incidents []Incident
+		err       error
+	)
+	switch source {
+	case "personalized":
+		incidents, err = c.personalizedIncidents(ctx, params.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		result.QueryMeta.Source = "personalized"
+	case "public":
+		incidents, err = c.publicIncidents(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result.QueryMeta.Source = "public"
+	case "auto":
+		// Personalized Service Health が使えなければ公開フィードにフォールバック
+		incidents, err = c.personalizedIncidents(ctx, params.ProjectID)
+		result.QueryMeta.Source = "personalized"
+		if err != nil {
+			result.Errors = map[string]string{"personalized": err.Error()}
+			incidents, err = c.publicIncidents(ctx)
+			if err != nil {
+				return nil, err
+			}
+			result.QueryMeta.Source = "public"
+		}
+	default:
+		return nil, fmt.Errorf("unsupported source: %s (supported: auto, personalized, public)", source)
+	}
+
+	result.Incidents = filterIncidents(incidents, params.Products, params.Locations)
+	return result, nil
+}
+
+// personalizedIncidents はPersonalized Service Health APIからACTIVEなイベントを取得する
+func (c *Client) personalizedIncidents(ctx context.Context, projectID string) ([]Incident, error) {
+	u := fmt.Sprintf("%s/projects/%s/locations/global/events?filter=%s",
+		serviceHealthEndpoint, url.PathEscape(projectID), url.QueryEscape("state=ACTIVE"))
+
+	var resp struct {
+		Events []struct {
+			Name         string `json:"name"`
+			Title        string `json:"title"`
+			Description  string `json:"description"`
+			State        string `json:"state"`
+			Relevance    string `json:"relevance"`
+			StartTime    string `json:"startTime"`
+			UpdateTime   string `json:"updateTime"`
+			EventImpacts []struct {
+				Product struct {
+					ProductName string `json:"productName"`
+				} `json:"product"`
+				Location struct {
+					LocationName string `json:"locationName"`
+				} `json:"location"`
+			} `json:"eventImpacts"`
+		} `json:"events"`
+	}
+	if err := getJSON(ctx, c.httpClient, u, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list service health events: %w", err)
+	}
+
+	incidents := []Incident{}
+	for _, e := range resp.Events {
+		inc := Incident{
+			ID:          e.Name,
+			Title:       e.Title,
+			Description: e.Description,
+			State:       e.State,
+			Relevance:   e.Relevance,
+			StartTime:   e.StartTime,
+			UpdateTime:  e.UpdateTime,
+			Products:    []string{},
+			Locations:   []string{},
+		}
+		for _, impact := range e.EventImpacts {
+			inc.Products = appendUnique(inc.Products, impact.Product.ProductName)
+			inc.Locations = appendUnique(inc.Locations, impact.Location.LocationName)
+		}
+		incidents = append(incidents, inc)
+	}
+	return incidents, nil
+}
+
+// publicIncidents は公開ステータスフィードから継続中のインシデントを取得する
+func (c *Client) publicIncidents(ctx context.Context) ([]Incident, error) {
+	var resp []struct {
+		ID                         string                   `json:"id"`
+		ExternalDesc               string                   `json:"external_desc"`
+		Begin                      string                   `json:"begin"`
+		End                        string                   `json:"end"`
+		Modified                   string                   `json:"modified"`
+		Severity                   string                   `json:"severity"`
+		URI                        string                   `json:"uri"`
+		MostRecentUpdate           struct{ Text string }    `json:"most_recent_update"`
+		AffectedProducts           []struct{ Title string } `json:"affected_products"`
+		CurrentlyAffectedLocations []struct{ ID string }    `json:"currently_affected_locations"`
+	}
+	if err := getJSON(ctx, http.DefaultClient, publicIncidentsURL, &resp); err != nil {
+		return nil, fmt.Errorf("failed to fetch public incidents feed: %w", err)
+	}
+
+	incidents := []Incident{}
+	for _, i := range resp {
+		if i.End != "" {
+			continue // 終了済み
+		}
+		inc := Incident{
+			ID:          i.ID,
+			Title:       i.ExternalDesc,
+			Description: i.MostRecentUpdate.Text,
+			State:       "ACTIVE",
+			StartTime:   i.Begin,
+			UpdateTime:  i.Modified,
+			URI:         "https://status.cloud.google.com/" + i.URI,
+			Products:    []string{},
+			Locations:   []string{},
+		}
+		for _, p := range i.AffectedProducts {
+			inc.Products = appendUnique(inc.Products, p.Title)
+		}
+		for _, l := range i.CurrentlyAffectedLocations {
+			inc.Locations = appendUnique(inc.Locations, l.ID)
+		}
+		incidents = append(incidents, inc)
+	}
+	return incidents, nil
+}
+
+// filterIncidents はプロダクト名（部分一致）・ロケーションで絞り込む
+func filterIncidents(incidents []Incident, products, locations []string) []Incident {
+	matches := func(values, wants []string) bool {
+		if len(wants) == 0 {
+			return true
+		}
+		for _, v := range values {
+			for _, w := range wants {
+				if strings.Contains(strings.ToLower(v), strings.ToLower(w)) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	filtered := []Incident{}
+	for _, inc := range incidents {
+		// "global" はすべてのロケーションに影響する
+		if matches(inc.Products, products) && (matches(inc.Locations, locations) || matches(inc.Locations, []string{"global"})) {
+			filtered = append(filtered, inc)
+		}
+	}
+	return filtered
+}
+
+func appendUnique(values []string, v string) []string {
+	if v == "" {
+		return values
+	}
+	for _, existing := range values {
+		if existing == v {
+			return values
+		}
+	}
+	return append(values, v)
+}
+
+// getJSON はGETしたJSONレスポンスをデコードする
+func getJSON(ctx context.Context, client *http.Client, u string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// GCPServiceHealthHandler returns a handler for the ops.gcp_service_health tool
+func (c *Client) GCPServiceHealthHandler() func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params ServiceHealthParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		return c.GCPServiceHealth(ctx, params)
+	}
+}
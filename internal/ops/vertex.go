@@ -0,0 +1,194 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+)
+
+// VertexOverviewParams are the parameters for ops.vertex_overview
+type VertexOverviewParams struct {
+	ProjectID          string               `json:"project_id"`
+	EndpointID         string               `json:"endpoint_id,omitempty"` // Only this prediction endpoint
+	Model              string               `json:"model,omitempty"`       // Only this publisher model (e.g. "gemini-2.0-flash")
+	Location           string               `json:"location,omitempty"`
+	TimeRange          monitoring.TimeRange `json:"time_range"`
+	AlignmentPeriodSec int                  `json:"alignment_period_sec"`
+	MaxSeries          int                  `json:"max_series"` // Per signal
+	Limit              int                  `json:"limit"`      // Max error logs
+}
+
+// VertexOverviewResult is the result of ops.vertex_overview
+type VertexOverviewResult struct {
+	QueryMeta       OverviewQueryMeta  `json:"query_meta"`
+	Summary         VertexSummary      `json:"summary"`
+	EndpointSignals []Signal           `json:"endpoint_signals"` // Prediction endpoints (custom / deployed models)
+	GenAISignals    []Signal           `json:"genai_signals"`    // Publisher models (Gemini etc.)
+	ErrorLogs       []logging.LogEntry `json:"error_logs"`       // Prediction endpoint error logs
+	Errors          map[string]string  `json:"errors,omitempty"` // section -> error
+}
+
+type VertexSummary struct {
+	EndpointErrorRate *float64 `json:"endpoint_error_rate,omitempty"` // errors / predictions
+	GenAIErrorRate    *float64 `json:"genai_error_rate,omitempty"`    // Non-2xx invocations / invocations
+	// 失敗した呼び出しのレスポンスコード別の割合（429 ならクォータ・スループット不足）
+	GenAIErrorsByCode  map[string]float64 `json:"genai_errors_by_code,omitempty"`
+	InputTokensPerSec  *float64           `json:"input_tokens_per_sec,omitempty"` // Mean over the window
+	OutputTokensPerSec *float64           `json:"output_tokens_per_sec,omitempty"`
+}
+
+// VertexOverview returns request, latency, error and token throughput signals of Vertex AI prediction
+// endpoints and generative AI models, plus recent prediction error logs
+func (c *Client) VertexOverview(ctx context.Context, params VertexOverviewParams) (*VertexOverviewResult, error) {
+	startTime, endTime, err := monitoring.ParseTimeRange(params.TimeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse time range: %w", err)
+	}
+
+	target := params.EndpointID
+	if target == "" {
+		target = params.Model
+	}
+	result := &VertexOverviewResult{
+		QueryMeta: OverviewQueryMeta{
+			ProjectID: params.ProjectID,
+			Target:    target,
+			Start:     startTime.Format(time.RFC3339),
+			End:       endTime.Format(time.RFC3339),
+		},
+		EndpointSignals: []Signal{},
+		GenAISignals:    []Signal{},
+		ErrorLogs:       []logging.LogEntry{},
+	}
+
+	// モデルだけを指定したときはエンドポイントを見ない（逆も同様）
+	if params.Model == "" {
+		kind := c.kinds["vertex_endpoint"]
+		filter := kindFilter(kind, params.EndpointID, params.Location)
+		result.EndpointSignals = c.querySignals(ctx, params.ProjectID, kind, filter,
+			params.TimeRange, params.AlignmentPeriodSec, params.MaxSeries)
+		result.Summary.EndpointErrorRate = errorRate(result.EndpointSignals, "predictions", "errors")
+
+		logs, err := c.queryKindLogs(ctx, params.ProjectID, kind, params.EndpointID, filter, params.TimeRange, params.Limit)
+		if err != nil {
+			result.Errors = map[string]string{"error_logs": err.Error()}
+		} else {
+			result.ErrorLogs = logs
+		}
+	}
+	if params.EndpointID == "" {
+		// 生成 AI（パブリッシャーモデル）
+		kind := c.kinds["vertex_genai"]
+		result.GenAISignals = c.querySignals(ctx, params.ProjectID, kind, kindFilter(kind, params.Model, params.Location),
+			params.TimeRange, params.AlignmentPeriodSec, params.MaxSeries)
+		genAISummary(&result.Summary, result.GenAISignals)
+	}
+
+	return result, nil
+}
+
+// joinFilter は value が空でなければ format の条件を filter に AND する
+func joinFilter(filter, value, format string) string {
+	if value == "" {
+		return filter
+	}
+	clause := fmt.Sprintf(format, value)
+	if filter == "" {
+		return clause
+	}
+	return filter + " AND " + clause
+}
+
+// genAISummary はレスポンスコード別の呼び出し数からエラー率を、トークン数から平均スループットを求める
+func genAISummary(summary *VertexSummary, signals []Signal) {
+	var total float64
+	failed := map[string]float64{}
+	for _, s := range signals {
+		if s.Name != "invocations" {
+			continue
+		}
+		for _, ts := range s.Series {
+			code := ts.Metric.Labels["response_code"]
+			for _, p := range ts.Points {
+				total += p.Value
+				if !strings.HasPrefix(code, "2") {
+					failed[code] += p.Value
+				}
+			}
+		}
+	}
+
+	if total > 0 {
+		var errs float64
+		for _, v := range failed {
+			errs += v
+		}
+		rate := errs / total
+		summary.GenAIErrorRate = &rate
+		if errs > 0 {
+			summary.GenAIErrorsByCode = map[string]float64{}
+			for code, v := range failed {
+				summary.GenAIErrorsByCode[code] = v / total
+			}
+		}
+	}
+	summary.InputTokensPerSec = meanRateSum(signals, "tokens", "input")
+	summary.OutputTokensPerSec = meanRateSum(signals, "tokens", "output")
+}
+
+// meanRateSum は name のシグナルのうちメトリクスラベル type が kind の系列について、系列ごとの平均レートの和を返す
+func meanRateSum(signals []Signal, name, kind string) *float64 {
+	var sum float64
+	found := false
+	for _, s := range signals {
+		if s.Name != name {
+			continue
+		}
+		for _, ts := range s.Series {
+			if ts.Metric.Labels["type"] != kind || len(ts.Points) == 0 {
+				continue
+			}
+			var total float64
+			for _, p := range ts.Points {
+				total += p.Value
+			}
+			sum += total / float64(len(ts.Points))
+			found = true
+		}
+	}
+	if !found {
+		return nil
+	}
+	return &sum
+}
+
+// VertexOverviewHandlerWithGuardrail returns a handler with guardrail validation
+func (c *Client) VertexOverviewHandlerWithGuardrail(v Validator) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params VertexOverviewParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.EndpointID != "" && params.Model != "" {
+			return nil, fmt.Errorf("specify either endpoint_id or model, not both")
+		}
+
+		// ガードレール: 系列数・件数制限
+		if params.MaxSeries <= 0 {
+			params.MaxSeries = 10
+		}
+		params.MaxSeries = v.ClampTimeSeriesLimit(ctx, params.MaxSeries)
+		if params.Limit <= 0 {
+			params.Limit = 20
+		}
+		params.Limit = v.ClampLogLimit(ctx, params.Limit)
+
+		return c.VertexOverview(ctx, params)
+	}
+}
@@ -0,0 +1,90 @@
+package ops
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/guardrail"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/timerange"
+)
+
+const (
+	accessContextManagerEndpoint = "https://accesscontextmanager.googleapis.com/v1"
+	// vpcscLookback は拒否の監査ログを探す範囲（拒否の直後に引くので短くてよい）
+	vpcscLookback = time.Hour
+)
+
+// VPCSCViolation は VPC Service Controls の拒否の一意 ID から、projectID のポリシー監査ログ
+// （cloudaudit.googleapis.com/policy）の境界・理由・呼び出し元を返す（見つからなければ nil）
+// 境界の access level は Access Context Manager で読めた場合のみ付ける
+func (c *Client) VPCSCViolation(ctx context.Context, projectID, uniqueID string) (*guardrail.VPCSCDetails, error) {
+	end := timerange.Now()
+	var found *logging.LogEntry
+	_, err := c.logging.ScanEntries(ctx, projectID, guardrail.VPCSCAuditFilter(projectID, uniqueID), end.Add(-vpcscLookback), end, 10, func(e logging.LogEntry) {
+		// dry run の境界の違反は拒否していないので除く
+		if found == nil && !boolAt(e.ProtoPayload, "metadata", "dryRun") {
+			found = &e
+		}
+	})
+	if err != nil || found == nil {
+		return nil, err
+	}
+
+	p := found.ProtoPayload
+	details := &guardrail.VPCSCDetails{
+		Perimeter:       stringAt(p, "metadata", "securityPolicyInfo", "servicePerimeterName"),
+		ViolationReason: stringAt(p, "metadata", "violationReason"),
+		Principal:       stringAt(p, "authenticationInfo", "principalEmail"),
+		CallerIP:        stringAt(p, "requestMetadata", "callerIp"),
+	}
+	// ingress / egress ルールの違反は境界が violations 側にだけ入る
+	for _, key := range []string{"ingressViolations", "egressViolations"} {
+		if details.Perimeter != "" {
+			break
+		}
+		if violations, ok := mapAt(p, "metadata")[key].([]any); ok && len(violations) > 0 {
+			if v, ok := violations[0].(map[string]any); ok {
+				details.Perimeter, _ = v["servicePerimeter"].(string)
+			}
+		}
+	}
+
+	if details.Perimeter != "" {
+		var perimeter struct {
+			Status struct {
+				AccessLevels []string `json:"accessLevels"`
+			} `json:"status"`
+		}
+		// 組織の accesscontextmanager.servicePerimeters.get がないことが多いので失敗しても続ける
+		if err := getJSON(ctx, c.httpClient, accessContextManagerEndpoint+"/"+details.Perimeter, &perimeter); err != nil {
+			slog.Debug("failed to read service perimeter", "perimeter", details.Perimeter, "error", err)
+		} else {
+			details.AccessLevels = perimeter.Status.AccessLevels
+		}
+	}
+	return details, nil
+}
+
+// mapAt は入れ子の JSON オブジェクトをたどる（途中になければ nil）
+func mapAt(m map[string]any, keys ...string) map[string]any {
+	for _, k := range keys {
+		next, ok := m[k].(map[string]any)
+		if !ok {
+			return nil
+		}
+		m = next
+	}
+	return m
+}
+
+func stringAt(m map[string]any, keys ...string) string {
+	s, _ := mapAt(m, keys[:len(keys)-1]...)[keys[len(keys)-1]].(string)
+	return s
+}
+
+func boolAt(m map[string]any, keys ...string) bool {
+	b, _ := mapAt(m, keys[:len(keys)-1]...)[keys[len(keys)-1]].(bool)
+	return b
+}
@@ -0,0 +1,81 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/api/option"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/auth"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/provider"
+)
+
+// WhoAmIResult is the result of ops.whoami
+type WhoAmIResult struct {
+	Principal       string                   `json:"principal,omitempty"`        // Email of the account the GCP API calls run as
+	Type            string                   `json:"type,omitempty"`             // "service_account", "authorized_user", "external_account", "metadata_server"
+	Source          string                   `json:"source"`                     // "credentials_file" or "application_default"
+	CredentialsFile string                   `json:"credentials_file,omitempty"` // When source is credentials_file
+	QuotaProject    string                   `json:"quota_project,omitempty"`
+	Scopes          []string                 `json:"scopes,omitempty"`
+	TokenExpiry     string                   `json:"token_expiry,omitempty"`
+	TokenTTLSeconds int                      `json:"token_ttl_sec,omitempty"`
+	MCPClient       string                   `json:"mcp_client,omitempty"` // HTTP transport client the call came from
+	Profile         string                   `json:"profile,omitempty"`    // Guardrail profile applied to the call
+	ClientRefreshes []provider.ClientRefresh `json:"client_refreshes"`     // API clients re-created after authentication errors
+	Error           string                   `json:"error,omitempty"`
+	Note            string                   `json:"note,omitempty"`
+}
+
+// WhoAmI returns the principal, scopes and token lifetime of the credentials the API clients use
+func WhoAmI(ctx context.Context, cfg *config.Config, credOpts ...option.ClientOption) *WhoAmIResult {
+	check, accessToken := inspectCredentials(ctx, credOpts...)
+	result := &WhoAmIResult{
+		Principal:       check.Principal,
+		Type:            check.Type,
+		Source:          "application_default",
+		QuotaProject:    check.QuotaProject,
+		TokenExpiry:     check.TokenExpiry,
+		MCPClient:       auth.ClientName(ctx),
+		ClientRefreshes: provider.ClientRefreshes(),
+		Error:           check.Error,
+	}
+	if cfg.CredentialsFile != "" {
+		result.Source = "credentials_file"
+		result.CredentialsFile = cfg.CredentialsFile
+	}
+	result.Profile, _ = auth.ProfileFrom(ctx)
+	if !check.OK {
+		result.Error += credentialsHint(cfg)
+		return result
+	}
+
+	info := lookupTokenInfo(ctx, accessToken)
+	if result.Principal == "" {
+		result.Principal = info.Email
+	}
+	if info.Scope != "" {
+		result.Scopes = strings.Fields(info.Scope)
+	}
+	// 残り時間はトークン情報を優先し、なければ発行時の有効期限から計算する
+	if ttl, err := strconv.Atoi(info.ExpiresIn); err == nil {
+		result.TokenTTLSeconds = ttl
+	} else if expiry, err := time.Parse(time.RFC3339, check.TokenExpiry); err == nil {
+		result.TokenTTLSeconds = max(int(time.Until(expiry).Seconds()), 0)
+	}
+	result.Note = "The token above is the one the credentials issue now; each API client caches and refreshes its own. " +
+		"When a call fails with an authentication error, the client is re-created from the credentials and the call retried once (at most once a minute per provider)."
+	return result
+}
+
+// WhoAmIHandler returns a handler for the ops.whoami tool
+// API クライアントを使わないので、クライアントを作れない場合も認証情報を返す
+func WhoAmIHandler(cfg *config.Config, credOpts ...option.ClientOption) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		return WhoAmI(ctx, cfg, credOpts...), nil
+	}
+}
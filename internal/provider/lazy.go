@@ -0,0 +1,199 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+)
+
+// refreshInterval は認証エラーでクライアントを作り直す間隔の下限
+// （認証情報そのものが無効な場合に呼び出しのたびに作り直さない）
+const refreshInterval = time.Minute
+
+// Lazy creates an API client on first use, so that the server starts (and tools/list works)
+// without credentials. A creation error is cached and returned by every later call.
+// When a call fails with an authentication error (e.g. the token expired and could not be
+// refreshed in a long-lived session), the client is re-created from the credentials and the
+// call retried once.
+type Lazy[T any] struct {
+	ctx    context.Context
+	name   string
+	create func(ctx context.Context) (T, error)
+
+	mu          sync.Mutex
+	created     bool
+	client      T
+	err         error
+	generation  int       // クライアントを作り直すたびに増える
+	refreshedAt time.Time // 最後に作り直した時刻
+	stale       []T       // 作り直す前のクライアント（処理中の呼び出しがあるので Close まで閉じない）
+}
+
+// NewLazy returns a Lazy that creates the client with ctx (the server's lifetime, not a request's)
+func NewLazy[T any](ctx context.Context, name string, create func(ctx context.Context) (T, error)) *Lazy[T] {
+	return &Lazy[T]{ctx: ctx, name: name, create: create}
+}
+
+// Ready returns a Lazy holding an already created client (e.g. one backed by internal/fake)
+func Ready[T any](client T) *Lazy[T] {
+	return &Lazy[T]{created: true, client: client}
+}
+
+var errClosed = errors.New("provider is closed")
+
+// Get returns the client, creating it on the first call
+func (l *Lazy[T]) Get() (T, error) {
+	client, _, err := l.get()
+	return client, err
+}
+
+func (l *Lazy[T]) get() (T, int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.created {
+		l.created = true
+		l.client, l.err = l.create(l.ctx)
+		if l.err != nil {
+			l.err = withHint(fmt.Errorf("%s tools are unavailable: %w", l.name, l.err))
+		}
+	}
+	return l.client, l.generation, l.err
+}
+
+// Handler returns a tool handler that gets the client and delegates to the handler built from it
+func (l *Lazy[T]) Handler(handler func(client T) mcp.ToolHandler) mcp.ToolHandler {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		client, generation, err := l.get()
+		if err != nil {
+			return nil, err
+		}
+		result, err := handler(client)(ctx, args)
+		if err == nil || !IsAuthError(err) || !l.refresh(generation, err) {
+			return result, err
+		}
+		if client, _, err = l.get(); err != nil {
+			return nil, err
+		}
+		return handler(client)(ctx, args)
+	}
+}
+
+// refresh は generation のクライアントで認証エラーが起きたときにクライアントを作り直し、
+// 作り直せたか（同時に失敗した別の呼び出しが作り直した場合も含む）を返す
+func (l *Lazy[T]) refresh(generation int, cause error) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.create == nil || l.err != nil {
+		return false
+	}
+	if l.generation != generation {
+		return true
+	}
+	if time.Since(l.refreshedAt) < refreshInterval {
+		return false
+	}
+	client, err := l.create(l.ctx)
+	l.refreshedAt = time.Now()
+	if err != nil {
+		slog.Warn("failed to re-create API client after an authentication error", "provider", l.name, "cause", cause, "error", err)
+		return false
+	}
+	slog.Warn("re-created API client after an authentication error", "provider", l.name, "cause", cause)
+	l.stale = append(l.stale, l.client)
+	l.client = client
+	l.generation++
+	recordRefresh(l.name, l.refreshedAt)
+	return true
+}
+
+// Close closes the client if it has been created; later calls to Get fail
+func (l *Lazy[T]) Close(close func(client T) error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.created {
+		l.created = true
+		l.err = errClosed
+		return nil
+	}
+	if l.err != nil {
+		return nil
+	}
+	errs := []error{close(l.client)}
+	for _, c := range l.stale {
+		errs = append(errs, close(c))
+	}
+	l.stale = nil
+	l.err = errClosed
+	return errors.Join(errs...)
+}
+
+// IsAuthError reports whether err is an authentication failure of a GCP API call
+// (as opposed to a missing permission), such as an expired or revoked token
+func IsAuthError(err error) bool {
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) && gerr.Code == http.StatusUnauthorized {
+		return true
+	}
+	if s, ok := status.FromError(err); ok && s.Code() == codes.Unauthenticated {
+		return true
+	}
+	// トークンの更新の失敗（oauth2 の RetrieveError など）はメッセージでしか見分けられない
+	msg := err.Error()
+	return strings.Contains(msg, "oauth2: ") || strings.Contains(msg, "invalid_grant")
+}
+
+// ClientRefresh is how often the API clients of a provider were re-created after authentication errors
+type ClientRefresh struct {
+	Provider string `json:"provider"`
+	Count    int    `json:"count"`
+	Last     string `json:"last"`
+}
+
+var (
+	refreshMu sync.Mutex
+	refreshes = map[string]*ClientRefresh{}
+)
+
+// recordRefresh はクライアントの作り直しを記録する（ops.whoami で返す）
+func recordRefresh(name string, at time.Time) {
+	refreshMu.Lock()
+	defer refreshMu.Unlock()
+	r, ok := refreshes[name]
+	if !ok {
+		r = &ClientRefresh{Provider: name}
+		refreshes[name] = r
+	}
+	r.Count++
+	r.Last = at.UTC().Format(time.RFC3339)
+}
+
+// ClientRefreshes returns the API client re-creations since the server started, by provider name
+func ClientRefreshes() []ClientRefresh {
+	refreshMu.Lock()
+	defer refreshMu.Unlock()
+	result := make([]ClientRefresh, 0, len(refreshes))
+	for _, r := range refreshes {
+		result = append(result, *r)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Provider < result[j].Provider })
+	return result
+}
+
+// withHint はクライアント生成の失敗に対処方法を添える
+// （生成時に GCP を呼ぶのは認証情報の読み込みだけなので、原因はほぼ ADC）
+func withHint(err error) error {
+	return fmt.Errorf("%w (check Application Default Credentials: run `gcloud auth application-default login` or set GOOGLE_APPLICATION_CREDENTIALS, then restart the server; ops.health shows details)", err)
+}
@@ -0,0 +1,200 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"google.golang.org/api/option"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/guardrail"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+)
+
+// ToolProvider is a GCP integration that contributes a set of tools (e.g. logging.*).
+// Providers that hold API clients may also implement io.Closer.
+type ToolProvider interface {
+	// Name is the provider name used in providers.disabled (e.g. "logging")
+	Name() string
+	// Tools returns the tool definitions; descriptions may depend on the config
+	Tools() []mcp.Tool
+	// Handlers returns the handler for each tool returned by Tools, keyed by tool name
+	Handlers() map[string]mcp.ToolHandler
+	// RequiredAPIs lists the Google APIs the tools call (e.g. "logging.googleapis.com")
+	RequiredAPIs() []string
+}
+
+// Completer is implemented by providers (and others, e.g. the guardrail for project_id)
+// that complete tool arguments for completion/complete.
+type Completer interface {
+	// Complete returns the values of argument that start with prefix, or ok=false for arguments
+	// it does not complete. args are the arguments already filled in (e.g. project_id).
+	Complete(ctx context.Context, argument, prefix string, args map[string]string) (values []string, ok bool, err error)
+}
+
+// Env is what a provider is built from
+type Env struct {
+	Config *config.Config
+	Guard  *guardrail.Guardrail
+	// GRPCOptions / HTTPOptions are passed to gRPC (Logging, Monitoring) and REST API clients
+	// (e.g. to record or replay the API calls, see internal/replay)
+	GRPCOptions []option.ClientOption
+	HTTPOptions []option.ClientOption
+	// CredentialOptions are the credentials the clients above use (empty for ADC).
+	// They are also used for calls made outside the API clients (e.g. the health check)
+	CredentialOptions []option.ClientOption
+}
+
+// GRPCOptionsFor returns GRPCOptions plus the endpoint configured for service
+// ("logging" or "monitoring") in grpc.endpoints
+func (e Env) GRPCOptionsFor(service string) []option.ClientOption {
+	if e.Config == nil || e.Config.GRPC.Endpoints[service] == "" {
+		return e.GRPCOptions
+	}
+	return append(slices.Clone(e.GRPCOptions), option.WithEndpoint(e.Config.GRPC.Endpoints[service]))
+}
+
+// Factory builds a provider. It should not call GCP: API clients are created
+// on first use (see Lazy) so that startup does not depend on credentials.
+// It returns a nil provider when the provider does not apply to the config
+// (e.g. security.enabled is false).
+type Factory func(ctx context.Context, env Env) (ToolProvider, error)
+
+var (
+	mu        sync.Mutex
+	factories = map[string]Factory{}
+)
+
+// Register は各連携パッケージの init から呼び、プロバイダを登録する
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := factories[name]; ok {
+		panic(fmt.Sprintf("provider: %q is registered twice", name))
+	}
+	factories[name] = factory
+}
+
+// Names returns the registered provider names in sorted order
+func Names() []string {
+	mu.Lock()
+	defer mu.Unlock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Build builds the registered providers except those in providers.disabled, in name order.
+// On error, the providers built so far are closed.
+func Build(ctx context.Context, env Env) ([]ToolProvider, error) {
+	names := Names()
+	for _, name := range env.Config.Providers.Disabled {
+		if !slices.Contains(names, name) {
+			return nil, fmt.Errorf("unknown provider in providers.disabled: %q (available: %v)", name, names)
+		}
+	}
+
+	var providers []ToolProvider
+	for _, name := range names {
+		if slices.Contains(env.Config.Providers.Disabled, name) {
+			continue
+		}
+		mu.Lock()
+		factory := factories[name]
+		mu.Unlock()
+
+		p, err := factory(ctx, env)
+		if err != nil {
+			_ = Close(providers)
+			return nil, fmt.Errorf("failed to set up provider %s: %w", name, err)
+		}
+		if p != nil {
+			providers = append(providers, p)
+		}
+	}
+	return providers, nil
+}
+
+// RegisterTools registers the tools of the providers on the server
+func RegisterTools(server *mcp.Server, providers []ToolProvider) error {
+	for _, p := range providers {
+		handlers := p.Handlers()
+		for _, tool := range p.Tools() {
+			handler, ok := handlers[tool.Name]
+			if !ok {
+				return fmt.Errorf("provider %s has no handler for tool %s", p.Name(), tool.Name)
+			}
+			server.RegisterTool(tool, handler)
+		}
+		slog.Debug("registered tool provider", "provider", p.Name(), "required_apis", p.RequiredAPIs())
+	}
+	return nil
+}
+
+// Close closes the providers that implement io.Closer
+func Close(providers []ToolProvider) error {
+	var errs []error
+	for _, p := range providers {
+		if c, ok := p.(io.Closer); ok {
+			if err := c.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("failed to close provider %s: %w", p.Name(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Completions returns the server's completion provider: the first of completers and the providers
+// implementing Completer that handles an argument answers. Completions are keyed by argument name
+// only, since the same argument (e.g. metric_type) means the same thing in every tool.
+func Completions(providers []ToolProvider, completers ...Completer) mcp.CompletionProvider {
+	for _, p := range providers {
+		if c, ok := p.(Completer); ok {
+			completers = append(completers, c)
+		}
+	}
+	return completions(completers)
+}
+
+type completions []Completer
+
+func (cs completions) Complete(ctx context.Context, params mcp.CompleteParams) ([]string, error) {
+	var args map[string]string
+	if params.Context != nil {
+		args = params.Context.Arguments
+	}
+	for _, c := range cs {
+		values, ok, err := c.Complete(ctx, params.Argument.Name, params.Argument.Value, args)
+		if ok || err != nil {
+			return values, err
+		}
+	}
+	return nil, nil
+}
+
+// MatchCompletions returns the values that start with prefix, followed by those that only contain it
+// (e.g. "request_count" matches "run.googleapis.com/request_count"), each in sorted order
+func MatchCompletions(values []string, prefix string) []string {
+	var starts, contains []string
+	for _, v := range values {
+		switch {
+		case strings.HasPrefix(v, prefix):
+			starts = append(starts, v)
+		case strings.Contains(v, prefix):
+			contains = append(contains, v)
+		}
+	}
+	sort.Strings(starts)
+	sort.Strings(contains)
+	return append(starts, contains...)
+}
@@ -0,0 +1,137 @@
+package redact
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/auth"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+)
+
+// Mask は一致した部分を置き換える文字列
+const Mask = "[REDACTED]"
+
+// Redactor はツール結果の文字列値から設定されたパターンに一致する部分をマスクする
+// ログのペイロード等に含まれるトークン・個人情報をアシスタントに渡さないため
+type Redactor struct {
+	patterns []*regexp.Regexp
+	profiles map[string][]*regexp.Regexp // プロファイル名 → そのプロファイルの呼び出しに追加で適用するパターン
+}
+
+// New は正規表現のリストからRedactorを作成する
+func New(patterns []string) (*Redactor, error) {
+	compiled, err := compile(patterns)
+	if err != nil {
+		return nil, err
+	}
+	return &Redactor{patterns: compiled, profiles: map[string][]*regexp.Regexp{}}, nil
+}
+
+// AddProfile はプロファイル name の呼び出しにだけ追加で適用するパターンを設定する
+func (r *Redactor) AddProfile(name string, patterns []string) error {
+	compiled, err := compile(patterns)
+	if err != nil {
+		return fmt.Errorf("profile %s: %w", name, err)
+	}
+	r.profiles[name] = append(append([]*regexp.Regexp{}, r.patterns...), compiled...)
+	return nil
+}
+
+func compile(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", p, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// patternsFor は呼び出しに適用するパターンを返す（全体のパターンとプロファイルのパターン）
+func (r *Redactor) patternsFor(ctx context.Context) []*regexp.Regexp {
+	if name, _ := auth.ProfileFrom(ctx); name != "" {
+		if patterns, ok := r.profiles[name]; ok {
+			return patterns
+		}
+	}
+	return r.patterns
+}
+
+// Middleware は結果をマスクするミドルウェアを返す
+func (r *Redactor) Middleware() mcp.Middleware {
+	return func(tool *mcp.Tool, next mcp.ToolHandler) mcp.ToolHandler {
+		return func(ctx context.Context, args json.RawMessage) (any, error) {
+			result, err := next(ctx, args)
+			patterns := r.patternsFor(ctx)
+			if err != nil || result == nil || len(patterns) == 0 {
+				return result, err
+			}
+
+			switch r := result.(type) {
+			case mcp.Content:
+				return redactContent(patterns, r), nil
+			case mcp.Structured:
+				// 描画済みのコンテンツと structuredContent になる元の結果の両方をマスクする
+				v, err := redactResult(patterns, r.Result)
+				if err != nil {
+					return nil, err
+				}
+				return mcp.Structured{Content: redactContent(patterns, r.Content), Result: v}, nil
+			}
+			return redactResult(patterns, result)
+		}
+	}
+}
+
+// redactContent は描画済みのコンテンツのテキストブロックをマスクする
+func redactContent(patterns []*regexp.Regexp, content mcp.Content) mcp.Content {
+	redacted := make(mcp.Content, len(content))
+	for i, block := range content {
+		block.Text = redactString(patterns, block.Text)
+		redacted[i] = block
+	}
+	return redacted
+}
+
+// redactResult は構造体のままでは文字列を辿れないので JSON の汎用表現に変換してからマスクする
+func redactResult(patterns []*regexp.Regexp, result any) (any, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode result for redaction: %w", err)
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("failed to decode result for redaction: %w", err)
+	}
+	return redactValue(patterns, v), nil
+}
+
+func redactValue(patterns []*regexp.Regexp, v any) any {
+	switch v := v.(type) {
+	case string:
+		return redactString(patterns, v)
+	case map[string]any:
+		for k, item := range v {
+			v[k] = redactValue(patterns, item)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(patterns, item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func redactString(patterns []*regexp.Regexp, s string) string {
+	for _, re := range patterns {
+		s = re.ReplaceAllString(s, Mask)
+	}
+	return s
+}
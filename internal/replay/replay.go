@@ -0,0 +1,282 @@
+// Package replay records GCP API responses to a directory (a cassette) and serves them back
+// without touching GCP, for offline demos and deterministic integration tests.
+//
+// Each call is stored as <dir>/<sha256 of the request>.json. gRPC calls (Cloud Logging,
+// Cloud Monitoring) are captured with a client interceptor and REST calls with an
+// http.RoundTripper. Relative time ranges are resolved against the recording time
+// (timerange.Now is frozen to it) so that replayed requests hash the same.
+package replay
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Mode is whether a cassette is being recorded or replayed
+type Mode int
+
+const (
+	Record Mode = iota // Call GCP and store the responses
+	Replay             // Serve stored responses only
+)
+
+// manifestFile はカセットの録画時刻などを保持するファイル
+const manifestFile = "cassette.json"
+
+type manifest struct {
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// entry は1回分の API 呼び出しの記録
+type entry struct {
+	Kind     string          `json:"kind"`   // "grpc" or "http"
+	Method   string          `json:"method"` // gRPC メソッド名 or HTTP メソッド
+	URL      string          `json:"url,omitempty"`
+	Request  json.RawMessage `json:"request,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+	// gRPC のエラー（NotFound 等もそのまま再生する）
+	Code    codes.Code `json:"code,omitempty"`
+	Message string     `json:"message,omitempty"`
+	// HTTP のレスポンス
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body,omitempty"`
+}
+
+// Cassette is a directory of recorded API calls
+type Cassette struct {
+	dir        string
+	mode       Mode
+	recordedAt time.Time
+
+	mu sync.Mutex // 記録の書き込み
+}
+
+// Open opens the cassette in dir. Recording into a new directory starts a cassette at the
+// current time; recording into an existing cassette adds to it (keeping its recording time).
+func Open(dir string, mode Mode) (*Cassette, error) {
+	c := &Cassette{dir: dir, mode: mode}
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	switch {
+	case err == nil:
+		var m manifest
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("invalid cassette %s: %w", dir, err)
+		}
+		c.recordedAt = m.RecordedAt
+	case errors.Is(err, os.ErrNotExist) && mode == Record:
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create cassette directory: %w", err)
+		}
+		c.recordedAt = time.Now().UTC().Truncate(time.Second)
+		data, err := json.MarshalIndent(manifest{RecordedAt: c.recordedAt}, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(filepath.Join(dir, manifestFile), data, 0o600); err != nil {
+			return nil, fmt.Errorf("failed to write cassette manifest: %w", err)
+		}
+	case errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("no cassette in %s (record one with -record first)", dir)
+	default:
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	return c, nil
+}
+
+// RecordedAt is the time relative time ranges are resolved against
+func (c *Cassette) RecordedAt() time.Time {
+	return c.recordedAt
+}
+
+// GRPCOptions returns client options for gRPC API clients.
+// auth (e.g. a credentials file) is used while recording.
+// When replaying, no credentials are needed and no connection is made.
+func (c *Cassette) GRPCOptions(auth ...option.ClientOption) []option.ClientOption {
+	opts := []option.ClientOption{option.WithGRPCDialOption(grpc.WithChainUnaryInterceptor(c.intercept))}
+	if c.mode == Replay {
+		return append(opts,
+			option.WithoutAuthentication(),
+			option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+		)
+	}
+	return append(opts, auth...)
+}
+
+// HTTPOptions returns client options for REST API clients.
+// auth (e.g. a credentials file) is used while recording.
+func (c *Cassette) HTTPOptions(auth ...option.ClientOption) []option.ClientOption {
+	return []option.ClientOption{option.WithHTTPClient(&http.Client{Transport: &roundTripper{cassette: c, auth: auth}})}
+}
+
+func (c *Cassette) intercept(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	reqMsg, ok := req.(proto.Message)
+	replyMsg, ok2 := reply.(proto.Message)
+	if !ok || !ok2 {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	reqData, err := proto.MarshalOptions{Deterministic: true}.Marshal(reqMsg)
+	if err != nil {
+		return err
+	}
+	key := hash("grpc", method, reqData)
+
+	if c.mode == Replay {
+		e, err := c.load(key)
+		if err != nil {
+			return status.Errorf(codes.FailedPrecondition, "replay: no recorded response for %s: %v", method, err)
+		}
+		if e.Code != codes.OK {
+			return status.Error(e.Code, e.Message)
+		}
+		return protojson.Unmarshal(e.Response, replyMsg)
+	}
+
+	callErr := invoker(ctx, method, req, reply, cc, opts...)
+	e := &entry{Kind: "grpc", Method: method}
+	e.Request, _ = protojson.Marshal(reqMsg)
+	if callErr != nil {
+		st := status.Convert(callErr)
+		// キャンセル・タイムアウト・認証や接続の失敗は録画環境の事情なので記録しない
+		switch st.Code() {
+		case codes.Canceled, codes.DeadlineExceeded, codes.Unauthenticated, codes.Unavailable:
+			return callErr
+		}
+		e.Code, e.Message = st.Code(), st.Message()
+	} else if e.Response, err = protojson.Marshal(replyMsg); err != nil {
+		return err
+	}
+	if err := c.store(key, e); err != nil {
+		return err
+	}
+	return callErr
+}
+
+// roundTripper は REST API の呼び出しを記録・再生する
+type roundTripper struct {
+	cassette *Cassette
+	auth     []option.ClientOption
+
+	once sync.Once
+	next http.RoundTripper // 記録時の認証付きトランスポート（初回に作る）
+	err  error
+}
+
+func (t *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	key := hash("http", req.Method+" "+req.URL.String(), body)
+	c := t.cassette
+
+	if c.mode == Replay {
+		e, err := c.load(key)
+		if err != nil {
+			return nil, fmt.Errorf("replay: no recorded response for %s %s: %w", req.Method, req.URL.Redacted(), err)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+			StatusCode:    e.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": []string{e.ContentType}},
+			Body:          io.NopCloser(strings.NewReader(e.Body)),
+			ContentLength: int64(len(e.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	t.once.Do(func() {
+		opts := append([]option.ClientOption{option.WithScopes("https://www.googleapis.com/auth/cloud-platform")}, t.auth...)
+		t.next, t.err = htransport.NewTransport(context.Background(), http.DefaultTransport, opts...)
+	})
+	if t.err != nil {
+		return nil, t.err
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	e := &entry{
+		Kind:        "http",
+		Method:      req.Method,
+		URL:         req.URL.Redacted(),
+		Status:      resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        string(respBody),
+	}
+	if len(body) > 0 && json.Valid(body) {
+		e.Request = body
+	}
+	if err := c.store(key, e); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func hash(kind, method string, data []byte) string {
+	h := sha256.New()
+	h.Write([]byte(kind + "\n" + method + "\n"))
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *Cassette) load(key string) (*entry, error) {
+	data, err := os.ReadFile(filepath.Join(c.dir, key+".json"))
+	if err != nil {
+		return nil, err
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("invalid recording %s: %w", key, err)
+	}
+	return &e, nil
+}
+
+func (c *Cassette) store(key string, e *entry) error {
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := os.WriteFile(filepath.Join(c.dir, key+".json"), data, 0o600); err != nil {
+		return fmt.Errorf("failed to record response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package security
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/option"
+	securitycenter "google.golang.org/api/securitycenter/v1"
+)
+
+// ListFindingsParams are the parameters for security.list_findings
+type ListFindingsParams struct {
+	ProjectID  string   `json:"project_id"`
+	Severities []string `json:"severities,omitempty"` // e.g. ["CRITICAL", "HIGH"]
+	Categories []string `json:"categories,omitempty"` // e.g. ["PUBLIC_BUCKET_ACL"]
+	State      string   `json:"state"`                // "ACTIVE" (default), "INACTIVE", "ANY"
+	Limit      int      `json:"limit"`
+}
+
+// ListFindingsResult is the result of security.list_findings
+type ListFindingsResult struct {
+	QueryMeta QueryMeta   `json:"query_meta"`
+	Findings  []Finding   `json:"findings"`
+	Stats     ResultStats `json:"stats"`
+}
+
+type QueryMeta struct {
+	ProjectID string `json:"project_id"`
+	Filter    string `json:"filter"`
+	Limit     int    `json:"limit"`
+}
+
+type Finding struct {
+	Name         string `json:"name"`
+	Category     string `json:"category"`
+	Severity     string `json:"severity"`
+	State        string `json:"state"`
+	FindingClass string `json:"finding_class,omitempty"`
+	ResourceName string `json:"resource_name"`
+	ResourceType string `json:"resource_type,omitempty"`
+	Description  string `json:"description,omitempty"`
+	EventTime    string `json:"event_time,omitempty"`
+	CreateTime   string `json:"create_time,omitempty"`
+	Mute         string `json:"mute,omitempty"`
+	ExternalURI  string `json:"external_uri,omitempty"`
+}
+
+type ResultStats struct {
+	ReturnedCount   int            `json:"returned_count"`
+	Truncated       bool           `json:"truncated"`
+	CountBySeverity map[string]int `json:"count_by_severity"`
+}
+
+// Client is the Security Command Center client
+type Client struct {
+	service *securitycenter.Service
+}
+
+// NewClient creates a new Security Command Center client
+func NewClient(ctx context.Context, opts ...option.ClientOption) (*Client, error) {
+	service, err := securitycenter.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create security command center client: %w", err)
+	}
+	return &Client{service: service}, nil
+}
+
+// ListFindings lists SCC findings for a project across all sources
+func (c *Client) ListFindings(ctx context.Context, params ListFindingsParams) (*ListFindingsResult, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	filter, err := buildFindingsFilter(params)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := []Finding{}
+	countBySeverity := map[string]int{}
+	truncated := false
+	pageToken := ""
+
+	for {
+		resp, err := c.service.Projects.Sources.Findings.List(fmt.Sprintf("projects/%s/sources/-", params.ProjectID)).
+			Context(ctx).
+			Filter(filter).
+			PageSize(int64(min(limit, 1000))).
+			PageToken(pageToken).
+			Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list findings: %w", err)
+		}
+
+		for _, r := range resp.ListFindingsResults {
+			if r.Finding == nil {
+				continue
+			}
+			if len(findings) >= limit {
+				truncated = true
+				break
+			}
+			f := Finding{
+				Name:         r.Finding.Name,
+				Category:     r.Finding.Category,
+				Severity:     r.Finding.Severity,
+				State:        r.Finding.State,
+				FindingClass: r.Finding.FindingClass,
+				ResourceName: r.Finding.ResourceName,
+				Description:  r.Finding.Description,
+				EventTime:    r.Finding.EventTime,
+				CreateTime:   r.Finding.CreateTime,
+				Mute:         r.Finding.Mute,
+				ExternalURI:  r.Finding.ExternalUri,
+			}
+			if r.Resource != nil {
+				f.ResourceType = r.Resource.Type
+			}
+			findings = append(findings, f)
+			countBySeverity[f.Severity]++
+		}
+
+		if truncated || resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	return &ListFindingsResult{
+		QueryMeta: QueryMeta{
+			ProjectID: params.ProjectID,
+			Filter:    filter,
+			Limit:     limit,
+		},
+		Findings: findings,
+		Stats: ResultStats{
+			ReturnedCount:   len(findings),
+			Truncated:       truncated,
+			CountBySeverity: countBySeverity,
+		},
+	}, nil
+}
+
+var validSeverities = map[string]bool{"CRITICAL": true, "HIGH": true, "MEDIUM": true, "LOW": true}
+
+// buildFindingsFilter は構造化パラメータからSCCのフィルタを組み立てる
+func buildFindingsFilter(params ListFindingsParams) (string, error) {
+	clauses := []string{}
+
+	state := strings.ToUpper(params.State)
+	switch state {
+	case "", "ACTIVE":
+		clauses = append(clauses, `state="ACTIVE"`)
+	case "INACTIVE":
+		clauses = append(clauses, `state="INACTIVE"`)
+	case "ANY":
+	default:
+		return "", fmt.Errorf("invalid state: %s (supported: ACTIVE, INACTIVE, ANY)", params.State)
+	}
+
+	if len(params.Severities) > 0 {
+		ors := make([]string, 0, len(params.Severities))
+		for _, s := range params.Severities {
+			s = strings.ToUpper(s)
+			if !validSeverities[s] {
+				return "", fmt.Errorf("invalid severity: %s (supported: CRITICAL, HIGH, MEDIUM, LOW)", s)
+			}
+			ors = append(ors, fmt.Sprintf(`severity="%s"`, s))
+		}
+		clauses = append(clauses, "("+strings.Join(ors, " OR ")+")")
+	}
+
+	if len(params.Categories) > 0 {
+		ors := make([]string, 0, len(params.Categories))
+		for _, cat := range params.Categories {
+			ors = append(ors, fmt.Sprintf(`category="%s"`, strings.ReplaceAll(cat, `"`, `\"`)))
+		}
+		clauses = append(clauses, "("+strings.Join(ors, " OR ")+")")
+	}
+
+	return strings.Join(clauses, " AND "), nil
+}
+
+// Validator はガードレール検証用インターフェース
+type Validator interface {
+	ClampFindingsLimit(limit int) int
+}
+
+// ListFindingsHandlerWithGuardrail returns a handler with guardrail validation
+func (c *Client) ListFindingsHandlerWithGuardrail(v Validator) func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params ListFindingsParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		// ガードレール: 件数制限
+		params.Limit = v.ClampFindingsLimit(params.Limit)
+
+		return c.ListFindings(ctx, params)
+	}
+}
@@ -0,0 +1,87 @@
+package security
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/guardrail"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/provider"
+)
+
+func init() {
+	provider.Register("security", newProvider)
+}
+
+// toolProvider は Security Command Center のツール（security.*）を提供する
+type toolProvider struct {
+	client *provider.Lazy[*Client]
+	cfg    *config.Config
+	guard  *guardrail.Guardrail
+}
+
+// security.enabled が false の場合は登録しない
+func newProvider(ctx context.Context, env provider.Env) (provider.ToolProvider, error) {
+	if !env.Config.Security.Enabled {
+		return nil, nil
+	}
+	return &toolProvider{client: provider.NewLazy(ctx, "security", func(ctx context.Context) (*Client, error) {
+		return NewClient(ctx, env.HTTPOptions...)
+	}), cfg: env.Config, guard: env.Guard}, nil
+}
+
+func (p *toolProvider) Name() string {
+	return "security"
+}
+
+func (p *toolProvider) RequiredAPIs() []string {
+	return []string{"securitycenter.googleapis.com"}
+}
+
+func (p *toolProvider) Tools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "security.list_findings",
+			Description: "List Security Command Center findings for a project with severity/category/state filters.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"severities": {
+						Type:        "array",
+						Description: "Severities to include (default: all)",
+						Items:       &mcp.Property{Type: "string", Enum: []string{"CRITICAL", "HIGH", "MEDIUM", "LOW"}},
+					},
+					"categories": {
+						Type:        "array",
+						Description: "Finding categories to include (e.g., ['PUBLIC_BUCKET_ACL', 'OPEN_FIREWALL'])",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"state": {
+						Type:        "string",
+						Description: "Finding state (default: ACTIVE)",
+						Enum:        []string{"ACTIVE", "INACTIVE", "ANY"},
+						Default:     "ACTIVE",
+					},
+					"limit": {
+						Type:        "integer",
+						Description: fmt.Sprintf("Maximum number of findings to return (default: 50, max: %d)", p.cfg.Security.MaxFindings),
+						Default:     50,
+					},
+				},
+				Required: []string{"project_id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[ListFindingsResult](),
+		},
+	}
+}
+
+func (p *toolProvider) Handlers() map[string]mcp.ToolHandler {
+	return map[string]mcp.ToolHandler{
+		"security.list_findings": p.client.Handler(func(c *Client) mcp.ToolHandler { return c.ListFindingsHandlerWithGuardrail(p.guard) }),
+	}
+}
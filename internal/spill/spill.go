@@ -0,0 +1,265 @@
+package spill
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/storage/v1"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/auth"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+)
+
+// URIPrefix は退避した結果のリソースURIのプレフィックス
+const URIPrefix = "gcp-ops://results/"
+
+// previewItems は要約に残す配列の先頭要素数
+const previewItems = 3
+
+// Result is returned instead of a tool result that exceeded the byte budget
+type Result struct {
+	Spilled     bool           `json:"spilled"`
+	ResourceURI string         `json:"resource_uri"` // Read with MCP resources/read
+	Location    string         `json:"location"`     // Local path or gs:// URI
+	Bytes       int            `json:"bytes"`
+	Summary     map[string]any `json:"summary"` // Top-level fields; arrays keep only the first items
+	Note        string         `json:"note"`
+}
+
+// item は退避済み結果1件のメタデータ
+type item struct {
+	id      string
+	tool    string
+	client  string // HTTP トランスポートの接続元（他の接続元には見せない）
+	session string // 退避したセッション（他のセッションには見せない）
+	created time.Time
+	bytes   int
+	path    string // ローカル退避時
+	object  string // GCS退避時
+}
+
+// visibleTo は ctx の接続元・セッションが退避した結果か
+func (it *item) visibleTo(ctx context.Context) bool {
+	return it.client == auth.ClientName(ctx) && it.session == mcp.SessionID(ctx)
+}
+
+// Store は大きなツール結果をローカルファイルまたはGCSに退避し、MCPリソースとして公開する
+type Store struct {
+	cfg      config.Spillover
+	authOpts []option.ClientOption // GCS クライアントの認証情報（空なら ADC）
+
+	storageOnce sync.Once
+	storage     *storage.Service
+	storageErr  error
+
+	mu    sync.Mutex
+	items map[string]*item
+	order []string
+}
+
+// NewStore はSpillover設定からStoreを作成する。authOpts は GCS への退避に使う認証情報
+func NewStore(cfg config.Spillover, authOpts ...option.ClientOption) *Store {
+	return &Store{cfg: cfg, authOpts: authOpts, items: map[string]*item{}}
+}
+
+// Middleware は結果がバイト上限を超えた場合に退避し、要約とリソースURIを返すミドルウェアを返す
+func (s *Store) Middleware() mcp.Middleware {
+	return func(tool *mcp.Tool, next mcp.ToolHandler) mcp.ToolHandler {
+		name := tool.Name
+		return func(ctx context.Context, args json.RawMessage) (any, error) {
+			result, err := next(ctx, args)
+			if err != nil {
+				return result, err
+			}
+			// 描画済みのコンテンツ（チャート画像など）は対象外
+			switch result.(type) {
+			case mcp.Content, mcp.Structured:
+				return result, nil
+			}
+
+			data, err := json.MarshalIndent(result, "", "  ")
+			if err != nil || len(data) <= s.cfg.MaxResultBytes {
+				return result, nil
+			}
+
+			it, err := s.save(ctx, name, data)
+			if err != nil {
+				return nil, fmt.Errorf("result is %d bytes (limit %d) and spillover failed: %w", len(data), s.cfg.MaxResultBytes, err)
+			}
+
+			location := it.path
+			if it.object != "" {
+				location = fmt.Sprintf("gs://%s/%s", s.bucket(), it.object)
+			}
+			return &Result{
+				Spilled:     true,
+				ResourceURI: URIPrefix + it.id,
+				Location:    location,
+				Bytes:       len(data),
+				Summary:     summarize(data),
+				Note:        fmt.Sprintf("result exceeded %d bytes; the full result is available via resources/read on resource_uri. Narrow the query (filter, limit, time range) to get it inline.", s.cfg.MaxResultBytes),
+			}, nil
+		}
+	}
+}
+
+// save は結果を退避先に書き込む
+func (s *Store) save(ctx context.Context, tool string, data []byte) (*item, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	it := &item{id: id, tool: tool, client: auth.ClientName(ctx), session: mcp.SessionID(ctx), created: time.Now(), bytes: len(data)}
+	fileName := fmt.Sprintf("%s-%s.json", strings.ReplaceAll(tool, ".", "_"), id)
+
+	if s.cfg.GCSBucket != "" {
+		svc, err := s.storageService(ctx)
+		if err != nil {
+			return nil, err
+		}
+		it.object = "gcp-ops-mcp/" + fileName
+		obj := &storage.Object{Name: it.object, ContentType: "application/json"}
+		if _, err := svc.Objects.Insert(s.bucket(), obj).Media(bytes.NewReader(data)).Context(ctx).Do(); err != nil {
+			return nil, fmt.Errorf("failed to upload to gs://%s: %w", s.bucket(), err)
+		}
+	} else {
+		dir := s.cfg.Dir
+		if dir == "" {
+			dir = filepath.Join(os.TempDir(), "gcp-ops-mcp")
+		}
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create spillover dir: %w", err)
+		}
+		it.path = filepath.Join(dir, fileName)
+		if err := os.WriteFile(it.path, data, 0o600); err != nil {
+			return nil, fmt.Errorf("failed to write spillover file: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	s.items[id] = it
+	s.order = append(s.order, id)
+	s.mu.Unlock()
+	return it, nil
+}
+
+// ListResources implements mcp.ResourceProvider
+func (s *Store) ListResources(ctx context.Context) []mcp.Resource {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resources := make([]mcp.Resource, 0, len(s.order))
+	// 新しい順
+	for i := len(s.order) - 1; i >= 0; i-- {
+		it := s.items[s.order[i]]
+		if !it.visibleTo(ctx) {
+			continue
+		}
+		resources = append(resources, mcp.Resource{
+			URI:         URIPrefix + it.id,
+			Name:        fmt.Sprintf("%s result (%s)", it.tool, it.created.UTC().Format(time.RFC3339)),
+			Description: fmt.Sprintf("Full result of %s (%d bytes)", it.tool, it.bytes),
+			MimeType:    "application/json",
+		})
+	}
+	return resources
+}
+
+// ReadResource implements mcp.ResourceProvider
+func (s *Store) ReadResource(ctx context.Context, uri string) (*mcp.ResourceContents, error) {
+	id := strings.TrimPrefix(uri, URIPrefix)
+	s.mu.Lock()
+	it, ok := s.items[id]
+	s.mu.Unlock()
+	if !ok || !strings.HasPrefix(uri, URIPrefix) || !it.visibleTo(ctx) {
+		return nil, fmt.Errorf("unknown resource: %s", uri)
+	}
+
+	var data []byte
+	if it.object != "" {
+		svc, err := s.storageService(ctx)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := svc.Objects.Get(s.bucket(), it.object).Context(ctx).Download()
+		if err != nil {
+			return nil, fmt.Errorf("failed to download gs://%s/%s: %w", s.bucket(), it.object, err)
+		}
+		defer resp.Body.Close()
+		if data, err = io.ReadAll(resp.Body); err != nil {
+			return nil, fmt.Errorf("failed to read gs://%s/%s: %w", s.bucket(), it.object, err)
+		}
+	} else {
+		var err error
+		if data, err = os.ReadFile(it.path); err != nil {
+			return nil, fmt.Errorf("failed to read spillover file: %w", err)
+		}
+	}
+
+	return &mcp.ResourceContents{URI: uri, MimeType: "application/json", Text: string(data)}, nil
+}
+
+func (s *Store) bucket() string {
+	return strings.TrimPrefix(s.cfg.GCSBucket, "gs://")
+}
+
+// storageService はGCSクライアントを遅延生成する（ローカル退避のみなら認証不要）
+func (s *Store) storageService(ctx context.Context) (*storage.Service, error) {
+	s.storageOnce.Do(func() {
+		s.storage, s.storageErr = storage.NewService(context.WithoutCancel(ctx), s.authOpts...)
+		if s.storageErr != nil {
+			s.storageErr = fmt.Errorf("failed to create storage client: %w", s.storageErr)
+		}
+	})
+	return s.storage, s.storageErr
+}
+
+// summarize はトップレベルのフィールドを残し、配列は先頭数件と件数に縮める
+func summarize(data []byte) map[string]any {
+	var top map[string]any
+	if err := json.Unmarshal(data, &top); err != nil {
+		return map[string]any{}
+	}
+
+	keys := make([]string, 0, len(top))
+	for k := range top {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	summary := map[string]any{}
+	for _, k := range keys {
+		arr, ok := top[k].([]any)
+		if !ok {
+			summary[k] = top[k]
+			continue
+		}
+		summary[k+"_count"] = len(arr)
+		if len(arr) > previewItems {
+			arr = arr[:previewItems]
+		}
+		summary[k+"_preview"] = arr
+	}
+	return summary
+}
+
+func newID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate resource id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,99 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// ServePrometheus は cfg.PrometheusAddr で /metrics（Prometheus テキスト形式）を公開する
+// 設定がなければ何もしない。ctx の終了でサーバーを停止する
+func (t *Telemetry) ServePrometheus(ctx context.Context) {
+	if t.cfg.PrometheusAddr == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		rm, err := t.collect(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		writePrometheus(w, rm)
+	})
+	srv := &http.Server{Addr: t.cfg.PrometheusAddr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		// MCP は stdout を使うためログは stderr に出す
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("prometheus endpoint stopped", "addr", t.cfg.PrometheusAddr, "error", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+}
+
+// writePrometheus は累積値を Prometheus テキスト形式で書き出す
+func writePrometheus(w io.Writer, rm *metricdata.ResourceMetrics) {
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			name := strings.ReplaceAll(m.Name, ".", "_")
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				name += "_total"
+				fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, m.Description, name)
+				for _, dp := range data.DataPoints {
+					fmt.Fprintf(w, "%s%s %d\n", name, labels(dp.Attributes, ""), dp.Value)
+				}
+			case metricdata.Histogram[float64]:
+				switch m.Unit {
+				case "ms":
+					name += "_milliseconds"
+				case "By":
+					name += "_bytes"
+				}
+				fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", name, m.Description, name)
+				for _, dp := range data.DataPoints {
+					var cumulative uint64
+					for i, bound := range dp.Bounds {
+						cumulative += dp.BucketCounts[i]
+						fmt.Fprintf(w, "%s_bucket%s %d\n", name, labels(dp.Attributes, strconv.FormatFloat(bound, 'g', -1, 64)), cumulative)
+					}
+					fmt.Fprintf(w, "%s_bucket%s %d\n", name, labels(dp.Attributes, "+Inf"), dp.Count)
+					fmt.Fprintf(w, "%s_sum%s %g\n", name, labels(dp.Attributes, ""), dp.Sum)
+					fmt.Fprintf(w, "%s_count%s %d\n", name, labels(dp.Attributes, ""), dp.Count)
+				}
+			}
+		}
+	}
+}
+
+// labels は属性を {k="v",...} 形式にする（le はヒストグラムのバケット境界）
+func labels(set attribute.Set, le string) string {
+	parts := []string{}
+	for _, kv := range set.ToSlice() {
+		parts = append(parts, fmt.Sprintf("%s=%q", kv.Key, kv.Value.Emit()))
+	}
+	sort.Strings(parts)
+	if le != "" {
+		parts = append(parts, fmt.Sprintf("le=%q", le))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
@@ -0,0 +1,428 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/status"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+)
+
+// ToolName はサーバー統計ツールの名前
+const ToolName = "ops.server_stats"
+
+const meterName = "github.com/kaz-under-the-bridge/google-cloud-ops-mcp"
+
+// メトリクス名（Prometheus では "." を "_" に置き換える）
+const (
+	metricToolCalls    = "gcp_ops_mcp.tool.calls"
+	metricToolDuration = "gcp_ops_mcp.tool.duration"
+	metricCacheLookups = "gcp_ops_mcp.cache.lookups"
+	metricParamsSize   = "gcp_ops_mcp.tool.params_size"
+	metricResultSize   = "gcp_ops_mcp.tool.result_size"
+	metricRejected     = "gcp_ops_mcp.tool.rejected"
+)
+
+// ツール呼び出しの結果
+const (
+	StatusOK       = "ok"
+	StatusError    = "error"     // 引数・ガードレールなどサーバー側で拒否したもの
+	StatusAPIError = "api_error" // GCP API がエラーを返したもの
+)
+
+// durationBuckets はツール実行時間（ms）のヒストグラム境界
+var durationBuckets = []float64{50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000}
+
+// sizeBuckets は引数・結果のサイズ（バイト）のヒストグラム境界
+var sizeBuckets = []float64{256, 1 << 10, 4 << 10, 16 << 10, 64 << 10, 256 << 10, 1 << 20, 4 << 20}
+
+// Telemetry はサーバー自身のメトリクスを OpenTelemetry で計測する
+// 値は ManualReader で ops.server_stats / Prometheus から読み出し、設定があれば OTLP にも送る
+type Telemetry struct {
+	cfg      config.Telemetry
+	provider *sdkmetric.MeterProvider
+	reader   *sdkmetric.ManualReader
+	started  time.Time
+
+	calls      metric.Int64Counter
+	duration   metric.Float64Histogram
+	paramsSize metric.Float64Histogram
+	resultSize metric.Float64Histogram
+	rejected   metric.Int64Counter
+}
+
+// New はMeterProviderを作成し、グローバルに登録する
+func New(ctx context.Context, cfg config.Telemetry, serviceVersion string) (*Telemetry, error) {
+	reader := sdkmetric.NewManualReader()
+	opts := []sdkmetric.Option{
+		sdkmetric.WithReader(reader),
+		sdkmetric.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", "gcp-ops-mcp"),
+			attribute.String("service.version", serviceVersion),
+		)),
+		sdkmetric.WithView(
+			sdkmetric.NewView(
+				sdkmetric.Instrument{Name: metricToolDuration},
+				sdkmetric.Stream{Aggregation: sdkmetric.AggregationExplicitBucketHistogram{Boundaries: durationBuckets}},
+			),
+			sdkmetric.NewView(
+				sdkmetric.Instrument{Name: "gcp_ops_mcp.tool.*_size"},
+				sdkmetric.Stream{Aggregation: sdkmetric.AggregationExplicitBucketHistogram{Boundaries: sizeBuckets}},
+			),
+		),
+	}
+	if cfg.OTLPEndpoint != "" {
+		exporter, err := otlpmetrichttp.New(ctx, otlpmetrichttp.WithEndpointURL(cfg.OTLPEndpoint))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		opts = append(opts, sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter)))
+	}
+
+	provider := sdkmetric.NewMeterProvider(opts...)
+	otel.SetMeterProvider(provider)
+
+	meter := provider.Meter(meterName)
+	calls, err := meter.Int64Counter(metricToolCalls, metric.WithDescription("Tool calls by tool and status"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create counter: %w", err)
+	}
+	duration, err := meter.Float64Histogram(metricToolDuration, metric.WithDescription("Tool call duration"), metric.WithUnit("ms"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create histogram: %w", err)
+	}
+	paramsSize, err := meter.Float64Histogram(metricParamsSize, metric.WithDescription("Tool call params size"), metric.WithUnit("By"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create histogram: %w", err)
+	}
+	resultSize, err := meter.Float64Histogram(metricResultSize, metric.WithDescription("Tool call result size"), metric.WithUnit("By"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create histogram: %w", err)
+	}
+	rejected, err := meter.Int64Counter(metricRejected, metric.WithDescription("Tool calls rejected before running by tool and reason"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create counter: %w", err)
+	}
+
+	return &Telemetry{
+		cfg:        cfg,
+		provider:   provider,
+		reader:     reader,
+		started:    time.Now(),
+		calls:      calls,
+		duration:   duration,
+		paramsSize: paramsSize,
+		resultSize: resultSize,
+		rejected:   rejected,
+	}, nil
+}
+
+// Shutdown は未送信のメトリクスを送ってからMeterProviderを停止する
+func (t *Telemetry) Shutdown(ctx context.Context) error {
+	return t.provider.Shutdown(ctx)
+}
+
+// Middleware はツール呼び出しの回数・所要時間・結果を記録するミドルウェアを返す
+func (t *Telemetry) Middleware() mcp.Middleware {
+	return func(tool *mcp.Tool, next mcp.ToolHandler) mcp.ToolHandler {
+		name := tool.Name
+		return func(ctx context.Context, args json.RawMessage) (any, error) {
+			start := time.Now()
+			result, err := next(ctx, args)
+
+			attrs := metric.WithAttributes(attribute.String("tool", name), attribute.String("status", callStatus(err)))
+			t.calls.Add(ctx, 1, attrs)
+			t.duration.Record(ctx, float64(time.Since(start).Microseconds())/1000, attrs)
+			return result, err
+		}
+	}
+}
+
+// Observe records the params and result sizes of a tools/call request and whether it was
+// rejected for its size (see mcp.Server.SetRequestObserver)
+func (t *Telemetry) Observe(ctx context.Context, stats mcp.RequestStats) {
+	tool := metric.WithAttributes(attribute.String("tool", stats.Tool))
+	t.paramsSize.Record(ctx, float64(stats.ParamsBytes), tool)
+	if stats.Rejected {
+		t.rejected.Add(ctx, 1, metric.WithAttributes(attribute.String("tool", stats.Tool), attribute.String("reason", "params_too_large")))
+		return
+	}
+	t.resultSize.Record(ctx, float64(stats.ResultBytes), tool)
+}
+
+// callStatus はエラーがGCP APIによるものか判定する
+func callStatus(err error) string {
+	if err == nil {
+		return StatusOK
+	}
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		return StatusAPIError
+	}
+	if _, ok := status.FromError(err); ok {
+		return StatusAPIError
+	}
+	return StatusError
+}
+
+var (
+	cacheOnce    sync.Once
+	cacheCounter metric.Int64Counter
+)
+
+// RecordCacheLookup はキャッシュのヒット/ミスを記録する（グローバルのMeterProviderを使う）
+func RecordCacheLookup(ctx context.Context, cache string, hit bool) {
+	cacheOnce.Do(func() {
+		var err error
+		cacheCounter, err = otel.Meter(meterName).Int64Counter(metricCacheLookups, metric.WithDescription("Cache lookups by cache and result"))
+		if err != nil {
+			slog.Warn("failed to create cache counter", "error", err)
+		}
+	})
+	if cacheCounter == nil {
+		return
+	}
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("cache", cache), attribute.String("result", result)))
+}
+
+// collect は現在の累積値を読み出す
+func (t *Telemetry) collect(ctx context.Context) (*metricdata.ResourceMetrics, error) {
+	var rm metricdata.ResourceMetrics
+	if err := t.reader.Collect(ctx, &rm); err != nil {
+		return nil, fmt.Errorf("failed to collect metrics: %w", err)
+	}
+	return &rm, nil
+}
+
+// ServerStats is the result of ops.server_stats
+type ServerStats struct {
+	StartedAt string       `json:"started_at"`
+	UptimeSec int64        `json:"uptime_sec"`
+	Tools     []ToolStats  `json:"tools"`
+	Caches    []CacheStats `json:"caches"`
+	Exporters Exporters    `json:"exporters"`
+}
+
+type ToolStats struct {
+	Tool             string          `json:"tool"`
+	Calls            int64           `json:"calls"`
+	Errors           int64           `json:"errors"`
+	APIErrors        int64           `json:"api_errors"`
+	Rejected         int64           `json:"rejected_too_large"` // Calls rejected because the params exceeded max_params_bytes (not in calls)
+	AvgMs            float64         `json:"avg_ms"`
+	P50Ms            float64         `json:"p50_ms"` // Percentiles estimated from the latency histogram
+	P95Ms            float64         `json:"p95_ms"`
+	P99Ms            float64         `json:"p99_ms"`
+	MaxMs            float64         `json:"max_ms"`
+	LatencyHistogram []LatencyBucket `json:"latency_histogram"`
+	MaxParamsBytes   int64           `json:"max_params_bytes"`
+	AvgResultBytes   int64           `json:"avg_result_bytes"`
+	MaxResultBytes   int64           `json:"max_result_bytes"`
+
+	latencyCounts []uint64 // Counts per durationBuckets (+Inf last) across statuses
+	resultBytes   float64  // Sum of the result sizes
+	resultCount   uint64
+}
+
+// LatencyBucket is the number of calls that took at most LeMs (and more than the previous bucket)
+type LatencyBucket struct {
+	LeMs  string `json:"le_ms"` // Upper bound, "+Inf" for the last bucket
+	Count uint64 `json:"count"`
+}
+
+type CacheStats struct {
+	Cache    string  `json:"cache"`
+	Hits     int64   `json:"hits"`
+	Misses   int64   `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+type Exporters struct {
+	OTLP       bool   `json:"otlp"`
+	Prometheus string `json:"prometheus,omitempty"` // Listen address of /metrics
+}
+
+// Handler returns a handler for ops.server_stats
+func (t *Telemetry) Handler() func(ctx context.Context, args json.RawMessage) (any, error) {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		rm, err := t.collect(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		tools := map[string]*ToolStats{}
+		caches := map[string]*CacheStats{}
+		toolStats := func(name string) *ToolStats {
+			if tools[name] == nil {
+				tools[name] = &ToolStats{Tool: name}
+			}
+			return tools[name]
+		}
+
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				switch data := m.Data.(type) {
+				case metricdata.Sum[int64]:
+					for _, dp := range data.DataPoints {
+						switch m.Name {
+						case metricToolCalls:
+							ts := toolStats(attr(dp.Attributes, "tool"))
+							ts.Calls += dp.Value
+							switch attr(dp.Attributes, "status") {
+							case StatusError:
+								ts.Errors += dp.Value
+							case StatusAPIError:
+								ts.APIErrors += dp.Value
+							}
+						case metricRejected:
+							toolStats(attr(dp.Attributes, "tool")).Rejected += dp.Value
+						case metricCacheLookups:
+							name := attr(dp.Attributes, "cache")
+							if caches[name] == nil {
+								caches[name] = &CacheStats{Cache: name}
+							}
+							if attr(dp.Attributes, "result") == "hit" {
+								caches[name].Hits += dp.Value
+							} else {
+								caches[name].Misses += dp.Value
+							}
+						}
+					}
+				case metricdata.Histogram[float64]:
+					for _, dp := range data.DataPoints {
+						ts := toolStats(attr(dp.Attributes, "tool"))
+						maxValue, _ := dp.Max.Value()
+						switch m.Name {
+						case metricToolDuration:
+							// status ごとのデータポイントをツール単位にまとめる（平均はここで一旦合計を持つ）
+							ts.AvgMs += dp.Sum
+							ts.MaxMs = max(ts.MaxMs, maxValue)
+							if ts.latencyCounts == nil {
+								ts.latencyCounts = make([]uint64, len(dp.BucketCounts))
+							}
+							for i, c := range dp.BucketCounts {
+								if i < len(ts.latencyCounts) {
+									ts.latencyCounts[i] += c
+								}
+							}
+						case metricParamsSize:
+							ts.MaxParamsBytes = max(ts.MaxParamsBytes, int64(maxValue))
+						case metricResultSize:
+							ts.MaxResultBytes = max(ts.MaxResultBytes, int64(maxValue))
+							ts.resultBytes += dp.Sum
+							ts.resultCount += dp.Count
+						}
+					}
+				}
+			}
+		}
+
+		stats := &ServerStats{
+			StartedAt: t.started.UTC().Format(time.RFC3339),
+			UptimeSec: int64(time.Since(t.started).Seconds()),
+			Tools:     []ToolStats{},
+			Caches:    []CacheStats{},
+			Exporters: Exporters{OTLP: t.cfg.OTLPEndpoint != "", Prometheus: t.cfg.PrometheusAddr},
+		}
+		for _, ts := range tools {
+			if ts.Calls > 0 {
+				ts.AvgMs = round(ts.AvgMs / float64(ts.Calls))
+			}
+			ts.P50Ms = round(percentile(ts.latencyCounts, ts.MaxMs, 0.5))
+			ts.P95Ms = round(percentile(ts.latencyCounts, ts.MaxMs, 0.95))
+			ts.P99Ms = round(percentile(ts.latencyCounts, ts.MaxMs, 0.99))
+			ts.MaxMs = round(ts.MaxMs)
+			ts.LatencyHistogram = latencyHistogram(ts.latencyCounts)
+			if ts.resultCount > 0 {
+				ts.AvgResultBytes = int64(ts.resultBytes / float64(ts.resultCount))
+			}
+			stats.Tools = append(stats.Tools, *ts)
+		}
+		sort.Slice(stats.Tools, func(i, j int) bool { return stats.Tools[i].Calls > stats.Tools[j].Calls })
+		for _, cs := range caches {
+			if total := cs.Hits + cs.Misses; total > 0 {
+				cs.HitRatio = round(float64(cs.Hits) / float64(total))
+			}
+			stats.Caches = append(stats.Caches, *cs)
+		}
+		sort.Slice(stats.Caches, func(i, j int) bool { return stats.Caches[i].Cache < stats.Caches[j].Cache })
+
+		return stats, nil
+	}
+}
+
+// percentile は遅延のヒストグラムから q 分位の値を見積もる（バケット内は一様とみなし、最後のバケットの上限は最大値）
+func percentile(counts []uint64, maxMs, q float64) float64 {
+	var total uint64
+	for _, c := range counts {
+		total += c
+	}
+	if total == 0 {
+		return 0
+	}
+	rank := q * float64(total)
+	var cumulative float64
+	for i, c := range counts {
+		if c == 0 {
+			continue
+		}
+		if cumulative+float64(c) >= rank {
+			lower, upper := 0.0, maxMs
+			if i > 0 {
+				lower = durationBuckets[i-1]
+			}
+			if i < len(durationBuckets) {
+				upper = min(durationBuckets[i], maxMs)
+			}
+			return min(lower+(rank-cumulative)/float64(c)*(upper-lower), maxMs)
+		}
+		cumulative += float64(c)
+	}
+	return maxMs
+}
+
+// latencyHistogram は呼び出しのあったバケットだけを返す
+func latencyHistogram(counts []uint64) []LatencyBucket {
+	buckets := []LatencyBucket{}
+	for i, c := range counts {
+		if c == 0 {
+			continue
+		}
+		le := "+Inf"
+		if i < len(durationBuckets) {
+			le = strconv.FormatFloat(durationBuckets[i], 'g', -1, 64)
+		}
+		buckets = append(buckets, LatencyBucket{LeMs: le, Count: c})
+	}
+	return buckets
+}
+
+func attr(set attribute.Set, key string) string {
+	v, _ := set.Value(attribute.Key(key))
+	return v.AsString()
+}
+
+func round(v float64) float64 {
+	return float64(int64(v*1000+0.5)) / 1000
+}
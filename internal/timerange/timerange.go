@@ -0,0 +1,79 @@
+package timerange
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultStart は start 省略時の開始時刻（現在からの遡り）
+const DefaultStart = 30 * time.Minute
+
+// Now は相対指定の基準時刻（-replay / -record では録画時刻に固定し、同じ API リクエストにする）
+var Now = time.Now
+
+// Parse は相対/絶対指定の時間範囲をパースする
+// start: RFC3339 or relative ("-1h", "-30m")、省略時は30分前
+// end: RFC3339 or "now"、省略時は現在
+func Parse(start, end string) (time.Time, time.Time, error) {
+	now := Now()
+	var startTime, endTime time.Time
+	var err error
+
+	// Parse end time
+	if end == "" || end == "now" {
+		endTime = now
+	} else {
+		endTime, err = time.Parse(time.RFC3339, end)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end time: %w", err)
+		}
+	}
+
+	// Parse start time
+	switch {
+	case start == "":
+		startTime = now.Add(-DefaultStart)
+	case start[0] == '-':
+		// Relative time (e.g., "-1h", "-30m")
+		duration, err := time.ParseDuration(start[1:])
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid relative start time: %w", err)
+		}
+		startTime = now.Add(-duration)
+	default:
+		startTime, err = time.Parse(time.RFC3339, start)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start time: %w", err)
+		}
+	}
+
+	return startTime, endTime, nil
+}
+
+// ParseShift は time_shift の指定（"1d", "7d", "1w" や "12h" など）を期間に変換する
+// time.ParseDuration の単位に加えて日（d）・週（w）を受け付ける
+func ParseShift(s string) (time.Duration, error) {
+	var d time.Duration
+	var err error
+	switch {
+	case strings.HasSuffix(s, "d") || strings.HasSuffix(s, "w"):
+		unit := 24 * time.Hour
+		if strings.HasSuffix(s, "w") {
+			unit *= 7
+		}
+		var n int
+		n, err = strconv.Atoi(s[:len(s)-1])
+		d = time.Duration(n) * unit
+	default:
+		d, err = time.ParseDuration(s)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid time_shift %q (e.g. '1d', '7d', '1w', '12h')", s)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("time_shift must be positive: %s", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,142 @@
+package tokens
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/auth"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+)
+
+// PageToolName is the tool that pages through results replaced by a summary
+const PageToolName = "ops.result_page"
+
+// maxStoredResults は ops.result_page で読めるように保持する要約前の結果の数（古いものから捨てる）
+const maxStoredResults = 20
+
+// stored は要約に置き換えた結果1件
+type stored struct {
+	tool    string
+	client  string // HTTP トランスポートの接続元（他の接続元には見せない）
+	session string // 要約したセッション（他のセッションには見せない）
+	data    []byte
+}
+
+// PageParams are the parameters for ops.result_page
+type PageParams struct {
+	ResultID string `json:"result_id" required:"true" description:"result_id of a summarized result"`
+	Path     string `json:"path" required:"true" description:"Field to read, as listed in the summary note (e.g. 'entries'); nested fields and array items with dots (e.g. 'signals.0.series')"`
+	Offset   int    `json:"offset,omitempty" description:"Index of the first item to return (default: 0)"`
+	Limit    int    `json:"limit,omitempty" default:"20" description:"Maximum number of items to return (default: 20, max: 200)"`
+}
+
+// PageResult is the result of ops.result_page
+type PageResult struct {
+	ResultID   string `json:"result_id"`
+	Tool       string `json:"tool"`
+	Path       string `json:"path"`
+	Offset     int    `json:"offset"`
+	Total      int    `json:"total"` // Items in the array (1 for a field that is not an array)
+	Items      []any  `json:"items"`
+	NextOffset *int   `json:"next_offset,omitempty"` // Absent on the last page
+}
+
+// PageTool returns the definition of ops.result_page
+func PageTool() mcp.Tool {
+	return mcp.ToolFor[PageParams](mcp.Tool{
+		Name:         PageToolName,
+		Description:  "Page through the full data of a result that was replaced by a summary because it exceeded the token budget (see its note and result_id). Returns a slice of one array field.",
+		OutputSchema: mcp.OutputSchemaFor[PageResult](),
+	})
+}
+
+// save は要約前の結果を保持し、ID を返す
+func (e *Estimator) save(ctx context.Context, tool string, data []byte) (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate result id: %w", err)
+	}
+	id := hex.EncodeToString(b)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.results[id] = &stored{tool: tool, client: auth.ClientName(ctx), session: mcp.SessionID(ctx), data: data}
+	e.order = append(e.order, id)
+	if len(e.order) > maxStoredResults {
+		delete(e.results, e.order[0])
+		e.order = e.order[1:]
+	}
+	return id, nil
+}
+
+// PageHandler returns the handler of ops.result_page
+func (e *Estimator) PageHandler() mcp.ToolHandler {
+	return func(ctx context.Context, args json.RawMessage) (any, error) {
+		var params PageParams
+		if err := json.Unmarshal(args, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+		if params.Limit <= 0 {
+			params.Limit = 20
+		}
+		params.Limit = min(params.Limit, 200)
+		params.Offset = max(params.Offset, 0)
+
+		e.mu.Lock()
+		r, ok := e.results[params.ResultID]
+		e.mu.Unlock()
+		if !ok || r.client != auth.ClientName(ctx) || r.session != mcp.SessionID(ctx) {
+			return nil, fmt.Errorf("result not found: %s (only the last %d summarized results are kept; re-run the query)", params.ResultID, maxStoredResults)
+		}
+
+		var v any
+		dec := json.NewDecoder(bytes.NewReader(r.data))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err != nil {
+			return nil, fmt.Errorf("failed to decode result: %w", err)
+		}
+		for _, key := range strings.Split(params.Path, ".") {
+			switch node := v.(type) {
+			case map[string]any:
+				if v, ok = node[key]; !ok {
+					return nil, fmt.Errorf("path %q: no field %q", params.Path, key)
+				}
+			case []any:
+				i, err := strconv.Atoi(key)
+				if err != nil || i < 0 || i >= len(node) {
+					return nil, fmt.Errorf("path %q: %q is not an index of an array of %d items", params.Path, key, len(node))
+				}
+				v = node[i]
+			default:
+				return nil, fmt.Errorf("path %q: %q is not a field of an object", params.Path, key)
+			}
+		}
+
+		items, isArray := v.([]any)
+		if !isArray {
+			items = []any{v}
+		}
+		result := &PageResult{
+			ResultID: params.ResultID,
+			Tool:     r.tool,
+			Path:     params.Path,
+			Offset:   params.Offset,
+			Total:    len(items),
+			Items:    []any{},
+		}
+		if params.Offset < len(items) {
+			end := min(params.Offset+params.Limit, len(items))
+			result.Items = items[params.Offset:end]
+			if end < len(items) {
+				result.NextOffset = &end
+			}
+		}
+		return result, nil
+	}
+}
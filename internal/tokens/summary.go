@@ -0,0 +1,235 @@
+package tokens
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	sampleItems      = 3   // 要約に残す配列の要素数
+	maxStringLen     = 300 // サンプル中の文字列の最大文字数
+	maxDepth         = 3   // サンプルに残す入れ子の深さ
+	histogramBuckets = 10
+	maxGroupFields   = 8  // top_groups に載せるフィールド数
+	maxGroupValues   = 5  // フィールドごとの値の数
+	maxDistinct      = 50 // これより値の種類が多いフィールドはグループにしない
+)
+
+// timeFields は要素の時刻として histogram に使うフィールド（先に見つかったもの）
+var timeFields = []string{"timestamp", "time", "start_time", "create_time"}
+
+// ArraySummary summarizes one array of the result
+type ArraySummary struct {
+	Path      string                  `json:"path"` // Pass to ops.result_page
+	Count     int                     `json:"count"`
+	Histogram []Bucket                `json:"histogram,omitempty"`  // Items over time
+	TopGroups map[string][]GroupCount `json:"top_groups,omitempty"` // Field -> most frequent values
+	Sample    []any                   `json:"sample"`               // First items, nested arrays and long strings shortened
+}
+
+type Bucket struct {
+	Start string `json:"start"`
+	Count int    `json:"count"`
+}
+
+type GroupCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// summarize は結果（JSON オブジェクト）の配列を <field>_summary に置き換えたフィールドと、置き換えたフィールド名を返す
+// 配列以外のフィールドも大きければ（budget を超えれば）短くした <field>_sample に置き換える
+// 元のフィールド名で型を変えないので、結果はツールの出力スキーマに合ったままになる
+func summarize(top object, budget int, tokenizer Tokenizer) (object, []string) {
+	out := object{}
+	replaced := []string{}
+	for _, f := range top {
+		var v any
+		dec := json.NewDecoder(bytes.NewReader(f.value))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err != nil {
+			continue
+		}
+		if arr, ok := v.([]any); ok {
+			out = out.set(f.key+"_summary", summarizeArray(f.key, arr))
+			replaced = append(replaced, f.key)
+			continue
+		}
+		if tokenizer.Count(string(f.value)) > budget {
+			out = out.set(f.key+"_sample", shorten(v, 0))
+			replaced = append(replaced, f.key)
+			continue
+		}
+		out = append(out, f)
+	}
+	return out, replaced
+}
+
+func summarizeArray(path string, arr []any) ArraySummary {
+	s := ArraySummary{Path: path, Count: len(arr), Sample: []any{}}
+	for _, item := range arr[:min(len(arr), sampleItems)] {
+		s.Sample = append(s.Sample, shorten(item, 0))
+	}
+	s.Histogram = histogram(arr)
+	s.TopGroups = topGroups(arr)
+	return s
+}
+
+// histogram は要素の時刻を期間で等分したバケットごとの件数を返す（時刻のない配列は nil）
+func histogram(arr []any) []Bucket {
+	times := []time.Time{}
+	for _, item := range arr {
+		m, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, name := range timeFields {
+			s, _ := m[name].(string)
+			if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+				times = append(times, t)
+				break
+			}
+		}
+	}
+	if len(times) < 2 {
+		return nil
+	}
+
+	first, last := times[0], times[0]
+	for _, t := range times {
+		if t.Before(first) {
+			first = t
+		}
+		if t.After(last) {
+			last = t
+		}
+	}
+	width := last.Sub(first) / histogramBuckets
+	if width <= 0 {
+		return []Bucket{{Start: first.UTC().Format(time.RFC3339), Count: len(times)}}
+	}
+	buckets := make([]Bucket, histogramBuckets)
+	for i := range buckets {
+		buckets[i].Start = first.Add(time.Duration(i) * width).UTC().Format(time.RFC3339)
+	}
+	for _, t := range times {
+		i := min(int(t.Sub(first)/width), histogramBuckets-1)
+		buckets[i].Count++
+	}
+	return buckets
+}
+
+// topGroups は要素の文字列フィールド（1段下のオブジェクトは "a.b" で）のうち、値の種類が少ないものの頻出値を返す
+func topGroups(arr []any) map[string][]GroupCount {
+	counts := map[string]map[string]int{}
+	add := func(field string, v any) {
+		s, ok := v.(string)
+		if !ok || s == "" {
+			return
+		}
+		if counts[field] == nil {
+			counts[field] = map[string]int{}
+		}
+		counts[field][s]++
+	}
+	for _, item := range arr {
+		m, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		for k, v := range m {
+			if nested, ok := v.(map[string]any); ok {
+				for nk, nv := range nested {
+					add(k+"."+nk, nv)
+				}
+				continue
+			}
+			add(k, v)
+		}
+	}
+
+	fields := []string{}
+	for field, values := range counts {
+		// 時刻・ID のように要素ごとに違う値はグループにならない
+		if len(values) > maxDistinct || len(values) == len(arr) && len(arr) > 1 || isTimeField(field) {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	// 値の種類が少ないフィールド（severity など）を優先する
+	sort.Slice(fields, func(i, j int) bool {
+		if len(counts[fields[i]]) != len(counts[fields[j]]) {
+			return len(counts[fields[i]]) < len(counts[fields[j]])
+		}
+		return fields[i] < fields[j]
+	})
+	groups := map[string][]GroupCount{}
+	for _, field := range fields[:min(len(fields), maxGroupFields)] {
+		values := []GroupCount{}
+		for v, n := range counts[field] {
+			values = append(values, GroupCount{Value: v, Count: n})
+		}
+		sort.Slice(values, func(i, j int) bool {
+			if values[i].Count != values[j].Count {
+				return values[i].Count > values[j].Count
+			}
+			return values[i].Value < values[j].Value
+		})
+		groups[field] = values[:min(len(values), maxGroupValues)]
+	}
+	return groups
+}
+
+func isTimeField(field string) bool {
+	for _, name := range timeFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// shorten はサンプル用に入れ子の配列を先頭の要素に、長い文字列を先頭部分に切り詰め、
+// maxDepth より深いオブジェクト・配列は大きさだけにする
+func shorten(v any, depth int) any {
+	switch v := v.(type) {
+	case map[string]any:
+		if depth >= maxDepth {
+			return fmt.Sprintf("(object with %d fields)", len(v))
+		}
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			out[k] = shorten(item, depth+1)
+		}
+		return out
+	case []any:
+		if depth >= maxDepth {
+			return fmt.Sprintf("(array of %d items)", len(v))
+		}
+		out := []any{}
+		for _, item := range v[:min(len(v), sampleItems)] {
+			out = append(out, shorten(item, depth+1))
+		}
+		return out
+	case string:
+		if r := []rune(v); len(r) > maxStringLen {
+			return string(r[:maxStringLen]) + "…"
+		}
+		return v
+	}
+	return v
+}
+
+// summaryNote は要約に付ける説明（paths は置き換えたフィールド）
+func summaryNote(estimated, budget int, paths []string) string {
+	return fmt.Sprintf("The result was estimated at %d tokens, over the budget of %d, so large fields are summarized (count, histogram, top groups, sample). "+
+		"Page through the full data with ops.result_page (result_id, path: %s, offset, limit), or narrow the query as in narrowing_suggestions.",
+		estimated, budget, strings.Join(paths, " / "))
+}
@@ -0,0 +1,255 @@
+package tokens
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+)
+
+// Tokenizer counts the tokens of a serialized tool result
+type Tokenizer interface {
+	Count(text string) int
+}
+
+// CharsPerToken estimates 4 characters per token, which is close enough for JSON and English text
+type CharsPerToken struct{}
+
+func (CharsPerToken) Count(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// Estimator adds the estimated token count of each tool result to its stats (stats.estimated_tokens),
+// with suggestions for narrowing the call when the count exceeds the soft limit.
+// Results over max_tokens are replaced by a summary; the full data can be paged with ops.result_page.
+type Estimator struct {
+	cfg       config.TokenBudget
+	tokenizer Tokenizer
+
+	mu      sync.Mutex
+	results map[string]*stored // result_id → 要約前の結果（ops.result_page）
+	order   []string
+}
+
+// NewEstimator returns an Estimator counting with tokenizer (nil = CharsPerToken)
+func NewEstimator(cfg config.TokenBudget, tokenizer Tokenizer) *Estimator {
+	if tokenizer == nil {
+		tokenizer = CharsPerToken{}
+	}
+	return &Estimator{cfg: cfg, tokenizer: tokenizer, results: map[string]*stored{}}
+}
+
+// Middleware estimates the tokens of the JSON result as the client receives it (indented text).
+// Rendered content (charts, sparklines) is returned as is.
+func (e *Estimator) Middleware() mcp.Middleware {
+	return func(tool *mcp.Tool, next mcp.ToolHandler) mcp.ToolHandler {
+		name, props := tool.Name, tool.InputSchema.Properties
+		return func(ctx context.Context, args json.RawMessage) (any, error) {
+			result, err := next(ctx, args)
+			if err != nil {
+				return result, err
+			}
+			switch result.(type) {
+			case mcp.Content, mcp.Structured:
+				return result, nil
+			}
+
+			data, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				return result, nil // エラーはサーバーがシリアライズするときに返す
+			}
+			top, ok := decodeObject(data)
+			if !ok {
+				return result, nil // オブジェクト以外の結果には stats を付けない
+			}
+			stats := object{}
+			if raw, ok := top.get("stats"); ok {
+				if stats, ok = decodeObject(raw); !ok {
+					return result, nil
+				}
+			}
+
+			estimated := e.tokenizer.Count(string(data))
+			if e.cfg.Summarize && estimated > e.cfg.MaxTokens && name != PageToolName {
+				if summary, ok := e.summary(ctx, name, top, data, estimated, suggestions(props, args, float64(e.cfg.SoftLimit)/float64(estimated))); ok {
+					return summary, nil
+				}
+			}
+			stats = stats.set("estimated_tokens", estimated)
+			if estimated > e.cfg.SoftLimit {
+				stats = stats.set("token_soft_limit", e.cfg.SoftLimit)
+				stats = stats.set("narrowing_suggestions", suggestions(props, args, float64(e.cfg.SoftLimit)/float64(estimated)))
+			}
+			encoded, err := json.Marshal(stats)
+			if err != nil {
+				return result, nil
+			}
+			out, err := json.Marshal(top.set("stats", json.RawMessage(encoded)))
+			if err != nil {
+				return result, nil
+			}
+			return json.RawMessage(out), nil
+		}
+	}
+}
+
+// summary は結果を要約に置き換える（要約できるフィールドがなければ false）
+// フィールドごとの上限は全体の予算の 1/4 とし、小さなフィールド（query_meta、stats など）はそのまま残す
+func (e *Estimator) summary(ctx context.Context, tool string, top object, data []byte, estimated int, narrowing []string) (json.RawMessage, bool) {
+	fields, paths := summarize(top, e.cfg.MaxTokens/4, e.tokenizer)
+	if len(paths) == 0 {
+		return nil, false
+	}
+	id, err := e.save(ctx, tool, data)
+	if err != nil {
+		return nil, false
+	}
+	out := object{}.
+		set("summarized", true).
+		set("result_id", id).
+		set("estimated_tokens", estimated)
+	out = append(out, fields...)
+	out = out.
+		set("narrowing_suggestions", narrowing).
+		set("note", summaryNote(estimated, e.cfg.MaxTokens, paths))
+	b, err := json.Marshal(out)
+	if err != nil {
+		return nil, false
+	}
+	return b, true
+}
+
+// suggestions は引数の定義から、結果を小さくする方法を返す（ratio は小さくしたい割合）
+func suggestions(props map[string]mcp.Property, args json.RawMessage, ratio float64) []string {
+	var given map[string]any
+	_ = json.Unmarshal(args, &given)
+
+	out := []string{}
+	// 件数の上限は今の値（なければデフォルト）をソフトリミットに収まる割合まで下げる
+	for _, name := range []string{"limit", "max_series"} {
+		prop, ok := props[name]
+		if !ok {
+			continue
+		}
+		current, ok := number(given[name])
+		if !ok || current <= 0 {
+			current, ok = number(prop.Default)
+		}
+		if ok && current > 1 {
+			out = append(out, fmt.Sprintf("%s: lower it from %g to about %d", name, current, max(1, int(current*ratio))))
+		} else {
+			out = append(out, fmt.Sprintf("%s: pass a smaller value", name))
+		}
+	}
+	if _, ok := props["stats_only"]; ok {
+		out = append(out, "stats_only: true returns summary statistics per series without the points")
+	}
+	if prop, ok := props["render"]; ok && slices.Contains(prop.Enum, "sparkline") {
+		out = append(out, "render: sparkline returns one line per series instead of every point")
+	}
+	if _, ok := props["flatten_json"]; ok {
+		out = append(out, "flatten_json: true with a smaller max_value_length truncates long payload values")
+	}
+	if _, ok := props["min_severity"]; ok {
+		out = append(out, "min_severity / filter: read only the entries that matter")
+	} else if _, ok := props["filter"]; ok {
+		out = append(out, "filter: narrow it down")
+	}
+	if _, ok := props["time_range"]; ok {
+		out = append(out, "time_range: use a shorter window")
+	}
+	out = append(out, "output_format: compact or csv takes fewer tokens than the indented JSON")
+	return out
+}
+
+// number は引数（JSON の数値）またはスキーマのデフォルト値を float64 で返す
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// object はキーの順序を保った JSON オブジェクト（結果の構造体のフィールド順を崩さないため）
+type object []field
+
+type field struct {
+	key   string
+	value json.RawMessage
+}
+
+// decodeObject は data が JSON オブジェクトならトップレベルのキーを順に読む
+func decodeObject(data []byte) (object, bool) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil, false
+	}
+	obj := object{}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, false
+		}
+		key, _ := t.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, false
+		}
+		obj = append(obj, field{key: key, value: value})
+	}
+	return obj, true
+}
+
+func (o object) get(key string) (json.RawMessage, bool) {
+	for _, f := range o {
+		if f.key == key {
+			return f.value, true
+		}
+	}
+	return nil, false
+}
+
+// set は key の値を置き換える（なければ末尾に足す）
+func (o object) set(key string, value any) object {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return o
+	}
+	for i, f := range o {
+		if f.key == key {
+			o[i].value = raw
+			return o
+		}
+	}
+	return append(o, field{key: key, value: raw})
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(f.key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(f.value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
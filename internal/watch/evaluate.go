@@ -0,0 +1,153 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+)
+
+// maxDetailSeries は通知に含める発火中の系列の数
+const maxDetailSeries = 10
+
+// evaluationCall は監視の評価に呼ぶツールと引数、条件の説明を組み立てる
+func evaluationCall(params CreateParams) (string, json.RawMessage, string, error) {
+	timeRange := map[string]string{"start": fmt.Sprintf("-%ds", params.WindowSec)}
+	switch params.Kind {
+	case "metric":
+		if params.MetricType == "" {
+			return "", nil, "", fmt.Errorf("metric_type is required for kind metric")
+		}
+		if params.Comparison == "" || params.Threshold == nil {
+			return "", nil, "", fmt.Errorf("comparison and threshold are required for kind metric")
+		}
+		args, err := json.Marshal(map[string]any{
+			"project_id":           params.ProjectID,
+			"metric_type":          params.MetricType,
+			"resource_type":        params.ResourceType,
+			"filter":               params.Filter,
+			"alignment_period_sec": params.AlignmentPeriodSec,
+			"per_series_aligner":   params.PerSeriesAligner,
+			"cross_series_reducer": params.CrossSeriesReducer,
+			"group_by_fields":      params.GroupByFields,
+			"comparison":           params.Comparison,
+			"threshold":            *params.Threshold,
+			"duration_sec":         params.DurationSec,
+			"time_range":           timeRange,
+		})
+		if err != nil {
+			return "", nil, "", err
+		}
+		condition := fmt.Sprintf("%s %s %g", params.MetricType, params.Comparison, *params.Threshold)
+		if params.DurationSec > 0 {
+			condition += fmt.Sprintf(" for %ds", params.DurationSec)
+		}
+		condition += fmt.Sprintf(" within the last %ds", params.WindowSec)
+		return metricTool, args, condition, nil
+
+	case "logs":
+		if params.Filter == "" {
+			return "", nil, "", fmt.Errorf("filter is required for kind logs")
+		}
+		if params.MinCount <= 0 {
+			params.MinCount = 1
+		}
+		args, err := json.Marshal(map[string]any{
+			"project_id": params.ProjectID,
+			"filter":     params.Filter,
+			"limit":      params.MinCount,
+			"time_range": timeRange,
+		})
+		if err != nil {
+			return "", nil, "", err
+		}
+		condition := fmt.Sprintf("at least %d log entries matching %q within the last %ds", params.MinCount, params.Filter, params.WindowSec)
+		return logsTool, args, condition, nil
+
+	default:
+		return "", nil, "", fmt.Errorf("unsupported kind: %q (supported: metric, logs)", params.Kind)
+	}
+}
+
+// thresholdResult は monitoring.evaluate_threshold の結果のうち評価に使う部分
+type thresholdResult struct {
+	Series []struct {
+		Label     string `json:"label"`
+		Intervals []struct {
+			FiredAt string  `json:"fired_at"`
+			Peak    float64 `json:"peak"`
+		} `json:"intervals"`
+	} `json:"series"`
+	Stats struct {
+		SeriesCount  int `json:"series_count"`
+		FiringSeries int `json:"firing_series"`
+	} `json:"stats"`
+}
+
+// logsResult は logging.query の結果のうち評価に使う部分
+// （通知にログの本文は含めない。マスキングの外側を通るため）
+type logsResult struct {
+	QueryMeta struct {
+		Limit int `json:"limit"`
+	} `json:"query_meta"`
+	Stats struct {
+		ReturnedCount int `json:"returned_count"`
+	} `json:"stats"`
+}
+
+// FiringSeries is a series that met the condition (details of a metric watch event)
+type FiringSeries struct {
+	Label   string  `json:"label"`
+	FiredAt string  `json:"fired_at"`
+	Peak    float64 `json:"peak"`
+}
+
+// evaluate は監視を1回評価し、発火しているか・要約・詳細を返す
+func (m *Manager) evaluate(ctx context.Context, handler mcp.ToolHandler, w *watch) (bool, string, any, error) {
+	ctx, cancel := context.WithTimeout(ctx, evaluateTimeout)
+	defer cancel()
+	result, err := handler(ctx, w.args)
+	if err != nil {
+		return false, "", nil, err
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return false, "", nil, fmt.Errorf("failed to read %s result: %w", w.tool, err)
+	}
+
+	if w.kind == "logs" {
+		var r logsResult
+		if err := json.Unmarshal(data, &r); err != nil {
+			return false, "", nil, fmt.Errorf("failed to read %s result: %w", w.tool, err)
+		}
+		window := w.info.WindowSec
+		if r.Stats.ReturnedCount >= r.QueryMeta.Limit {
+			return true, fmt.Sprintf("at least %d matching log entries in the last %ds", r.Stats.ReturnedCount, window), nil, nil
+		}
+		return false, fmt.Sprintf("%d matching log entries in the last %ds", r.Stats.ReturnedCount, window), nil, nil
+	}
+
+	var r thresholdResult
+	if err := json.Unmarshal(data, &r); err != nil {
+		return false, "", nil, fmt.Errorf("failed to read %s result: %w", w.tool, err)
+	}
+	if r.Stats.SeriesCount == 0 {
+		return false, "no data in the window", nil, nil
+	}
+	if r.Stats.FiringSeries == 0 {
+		return false, fmt.Sprintf("0 of %d series met the condition", r.Stats.SeriesCount), nil, nil
+	}
+	firing := []FiringSeries{}
+	labels := []string{}
+	for _, s := range r.Series {
+		if len(s.Intervals) == 0 || len(firing) >= maxDetailSeries {
+			continue
+		}
+		last := s.Intervals[len(s.Intervals)-1]
+		firing = append(firing, FiringSeries{Label: s.Label, FiredAt: last.FiredAt, Peak: last.Peak})
+		labels = append(labels, fmt.Sprintf("%s: peak %g", s.Label, last.Peak))
+	}
+	return true, fmt.Sprintf("%d of %d series met the condition (%s)", r.Stats.FiringSeries, r.Stats.SeriesCount, strings.Join(labels, ", ")), firing, nil
+}
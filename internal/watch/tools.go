@@ -0,0 +1,155 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+)
+
+// Tools returns the watch tools
+func (m *Manager) Tools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        CreateToolName,
+			Description: fmt.Sprintf("Keep an eye on something in the background, e.g. 'the checkout error rate for the next hour': a metric condition (as in monitoring.evaluate_threshold) or a log filter is re-evaluated every interval_sec, and a notification (MCP notifications/message, plus the configured notification sinks) is sent when it starts firing or resolves. Returns the first evaluation. Up to %d active watches; see ops.list_watches.", m.cfg.MaxWatches),
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"name": {
+						Type:        "string",
+						Description: "Human-readable name shown in notifications (default: the watch ID)",
+					},
+					"kind": {
+						Type:        "string",
+						Description: "metric: fires while a series meets comparison/threshold. logs: fires while at least min_count entries match filter",
+						Enum:        []string{"metric", "logs"},
+					},
+					"project_id": {
+						Type:        "string",
+						Description: "GCP project ID",
+					},
+					"filter": {
+						Type:        "string",
+						Description: "Logging Query Language filter (logs, required), or a Monitoring filter ANDed to the query (metric)",
+					},
+					"metric_type": {
+						Type:        "string",
+						Description: "Metric type (metric, e.g., 'run.googleapis.com/request_count')",
+					},
+					"resource_type": {
+						Type:        "string",
+						Description: "Monitored resource type (metric)",
+					},
+					"alignment_period_sec": {
+						Type:        "integer",
+						Description: "Alignment period in seconds (metric, default: 60)",
+						Default:     60,
+					},
+					"per_series_aligner": {
+						Type:        "string",
+						Description: "Per-series aligner (metric, e.g., 'ALIGN_RATE')",
+					},
+					"cross_series_reducer": {
+						Type:        "string",
+						Description: "Cross-series reducer (metric, e.g., 'REDUCE_SUM')",
+					},
+					"group_by_fields": {
+						Type:        "array",
+						Description: "Fields to group by with cross_series_reducer (metric)",
+						Items:       &mcp.Property{Type: "string"},
+					},
+					"comparison": {
+						Type:        "string",
+						Description: "Comparison (metric): GT, GE, LT, LE, EQ, NE (or >, >=, <, <=)",
+					},
+					"threshold": {
+						Type:        "number",
+						Description: "Threshold (metric)",
+					},
+					"duration_sec": {
+						Type:        "integer",
+						Description: "How long the comparison must hold before the watch fires (metric, default: 0)",
+					},
+					"min_count": {
+						Type:        "integer",
+						Description: "Matching entries within window_sec needed to fire (logs, default: 1)",
+						Default:     1,
+					},
+					"window_sec": {
+						Type:        "integer",
+						Description: "Each evaluation looks at the last window_sec seconds (default: 300, longer for metric when duration_sec needs it)",
+						Default:     300,
+					},
+					"interval_sec": {
+						Type:        "integer",
+						Description: fmt.Sprintf("Seconds between evaluations (default: 60, min: %d)", m.cfg.MinIntervalSec),
+						Default:     60,
+					},
+					"expires_in_minutes": {
+						Type:        "integer",
+						Description: fmt.Sprintf("How long to keep watching (default: 60, max: %d)", m.cfg.MaxDurationMinutes),
+						Default:     60,
+					},
+				},
+				Required: []string{"kind", "project_id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[Info](),
+		},
+		{
+			Name:        ListToolName,
+			Description: "List the background watches created with ops.create_watch in this session: condition, current state (ok, firing, error, expired), last evaluation and recent state changes.",
+			InputSchema: mcp.ToolSchema{
+				Type:       "object",
+				Properties: map[string]mcp.Property{},
+			},
+			OutputSchema: mcp.OutputSchemaFor[ListResult](),
+		},
+		{
+			Name:        DeleteToolName,
+			Description: "Stop and remove a background watch created in this session.",
+			InputSchema: mcp.ToolSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"id": {
+						Type:        "string",
+						Description: "Watch ID (see ops.list_watches)",
+					},
+				},
+				Required: []string{"id"},
+			},
+			OutputSchema: mcp.OutputSchemaFor[DeleteResult](),
+		},
+	}
+}
+
+// Handlers returns the handlers of the watch tools
+func (m *Manager) Handlers() map[string]mcp.ToolHandler {
+	return map[string]mcp.ToolHandler{
+		CreateToolName: func(ctx context.Context, args json.RawMessage) (any, error) {
+			var params CreateParams
+			if err := json.Unmarshal(args, &params); err != nil {
+				return nil, fmt.Errorf("failed to parse arguments: %w", err)
+			}
+			return m.Create(ctx, params)
+		},
+		ListToolName: func(ctx context.Context, args json.RawMessage) (any, error) {
+			return &ListResult{Watches: m.List(ctx)}, nil
+		},
+		DeleteToolName: func(ctx context.Context, args json.RawMessage) (any, error) {
+			var params DeleteParams
+			if err := json.Unmarshal(args, &params); err != nil {
+				return nil, fmt.Errorf("failed to parse arguments: %w", err)
+			}
+			if params.ID == "" {
+				return nil, fmt.Errorf("id is required")
+			}
+			info, err := m.Delete(ctx, params.ID)
+			if err != nil {
+				return nil, err
+			}
+			return &DeleteResult{Deleted: *info}, nil
+		},
+	}
+}
@@ -0,0 +1,449 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/auth"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/notify"
+)
+
+// 監視ツールの名前（結果はサーバーの状態なのでキャッシュしない）
+const (
+	CreateToolName = "ops.create_watch"
+	ListToolName   = "ops.list_watches"
+	DeleteToolName = "ops.delete_watch"
+)
+
+// ToolNames は監視ツールの名前
+var ToolNames = []string{CreateToolName, ListToolName, DeleteToolName}
+
+// 評価に使うツール（ガードレールを通した読み取りツールをそのまま呼ぶ）
+const (
+	metricTool = "monitoring.evaluate_threshold"
+	logsTool   = "logging.query"
+)
+
+// 監視の状態
+const (
+	StateOK      = "ok"
+	StateFiring  = "firing"
+	StateError   = "error"
+	StateExpired = "expired"
+)
+
+const (
+	// maxEvents は監視ごとに保持する状態変化の数
+	maxEvents = 20
+	// evaluateTimeout は1回の評価の上限
+	evaluateTimeout = time.Minute
+	// notifyLogger は MCP 通知（notifications/message）の logger
+	notifyLogger = "gcp-ops-mcp.watch"
+)
+
+// CreateParams are the parameters for ops.create_watch
+type CreateParams struct {
+	Name      string `json:"name,omitempty"`
+	Kind      string `json:"kind"` // "metric" or "logs"
+	ProjectID string `json:"project_id"`
+	Filter    string `json:"filter,omitempty"` // LQL (logs) or a Monitoring filter ANDed to the query (metrics)
+	// metric のみ（monitoring.evaluate_threshold と同じ）
+	MetricType         string   `json:"metric_type,omitempty"`
+	ResourceType       string   `json:"resource_type,omitempty"`
+	AlignmentPeriodSec int      `json:"alignment_period_sec,omitempty"`
+	PerSeriesAligner   string   `json:"per_series_aligner,omitempty"`
+	CrossSeriesReducer string   `json:"cross_series_reducer,omitempty"`
+	GroupByFields      []string `json:"group_by_fields,omitempty"`
+	Comparison         string   `json:"comparison,omitempty"`
+	Threshold          *float64 `json:"threshold,omitempty"`
+	DurationSec        int      `json:"duration_sec,omitempty"`
+	// logs のみ: window_sec の間に一致したエントリがこの数以上で発火
+	MinCount int `json:"min_count,omitempty"`
+	// 評価のスケジュール
+	WindowSec        int `json:"window_sec,omitempty"`         // Each evaluation looks at the last window_sec (default: 300)
+	IntervalSec      int `json:"interval_sec,omitempty"`       // Evaluation interval (default: 60)
+	ExpiresInMinutes int `json:"expires_in_minutes,omitempty"` // How long to keep watching (default: 60)
+}
+
+// DeleteParams are the parameters for ops.delete_watch
+type DeleteParams struct {
+	ID string `json:"id"`
+}
+
+// Info is a watch as returned by ops.create_watch / ops.list_watches
+type Info struct {
+	ID             string  `json:"id"`
+	Name           string  `json:"name"`
+	Kind           string  `json:"kind"`
+	ProjectID      string  `json:"project_id"`
+	Condition      string  `json:"condition"`
+	IntervalSec    int     `json:"interval_sec"`
+	WindowSec      int     `json:"window_sec"`
+	CreatedAt      string  `json:"created_at"`
+	ExpiresAt      string  `json:"expires_at"`
+	State          string  `json:"state"`
+	Summary        string  `json:"summary,omitempty"` // Result of the last evaluation
+	LastEvaluated  string  `json:"last_evaluated,omitempty"`
+	Evaluations    int     `json:"evaluations"`
+	TriggeredCount int     `json:"triggered_count"` // Times the watch went from ok to firing
+	Events         []Event `json:"events,omitempty"`
+}
+
+// Event is a state change of a watch (also the payload of MCP notifications and notification sinks)
+type Event struct {
+	WatchID   string `json:"watch_id"`
+	Name      string `json:"name"`
+	ProjectID string `json:"project_id"`
+	Condition string `json:"condition"`
+	State     string `json:"state"`
+	Previous  string `json:"previous,omitempty"`
+	Time      string `json:"time"`
+	Summary   string `json:"summary"`
+	Details   any    `json:"details,omitempty"`
+}
+
+// ListResult is the result of ops.list_watches
+type ListResult struct {
+	Watches []Info `json:"watches"`
+}
+
+// DeleteResult is the result of ops.delete_watch
+type DeleteResult struct {
+	Deleted Info `json:"deleted"`
+}
+
+// watch は登録済みの監視1件
+type watch struct {
+	info    Info
+	client  string // 作成した接続元（HTTP では他の接続元の監視は見せない）
+	session string // 作成したセッション（mcp.SessionID。他のセッションの監視は見せない）
+	tool    string
+	args    json.RawMessage
+	kind    string
+	cancel  context.CancelFunc
+}
+
+// visibleTo は ctx の接続元・セッションが作った監視か
+func (w *watch) visibleTo(ctx context.Context) bool {
+	return w.client == auth.ClientName(ctx) && w.session == mcp.SessionID(ctx)
+}
+
+// Manager は監視を登録し、バックグラウンドで評価する
+// 評価は監視を作った呼び出しと同じ接続元・プロファイルで、ガードレールを通した読み取りツールを呼んで行う
+type Manager struct {
+	cfg config.Watches
+	ctx context.Context // サーバーの寿命（終了で全ての監視を止める）
+
+	mu       sync.Mutex
+	nextID   int
+	watches  map[string]*watch
+	handlers map[string]mcp.ToolHandler // 評価に使うツール
+	notify   func(level, logger string, data any)
+	sinks    *notify.Notifier
+}
+
+// NewManager creates a manager whose watches stop when ctx is done
+func NewManager(ctx context.Context, cfg config.Watches) *Manager {
+	return &Manager{
+		cfg:      cfg,
+		ctx:      ctx,
+		nextID:   1,
+		watches:  map[string]*watch{},
+		handlers: map[string]mcp.ToolHandler{},
+	}
+}
+
+// SetNotifier sets the function that sends MCP notifications (stdio only; over HTTP only notification sinks are used)
+func (m *Manager) SetNotifier(send func(level, logger string, data any)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.notify = send
+}
+
+// SetSinks sets the notification sinks (notifications.sinks) that receive state changes
+func (m *Manager) SetSinks(sinks *notify.Notifier) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sinks = sinks
+}
+
+// Middleware は評価に使うツールのハンドラを控えるミドルウェアを返す
+// キャッシュより内側・ガードレールより外側に置く（評価のたびに最新の結果を取り、許可判定は通す）
+func (m *Manager) Middleware() mcp.Middleware {
+	return func(tool *mcp.Tool, next mcp.ToolHandler) mcp.ToolHandler {
+		if tool.Name == metricTool || tool.Name == logsTool {
+			m.mu.Lock()
+			m.handlers[tool.Name] = next
+			m.mu.Unlock()
+		}
+		return next
+	}
+}
+
+// Create validates params, evaluates the watch once and schedules it
+func (m *Manager) Create(ctx context.Context, params CreateParams) (*Info, error) {
+	if err := m.normalize(&params); err != nil {
+		return nil, err
+	}
+	tool, args, condition, err := evaluationCall(params)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	handler := m.handlers[tool]
+	active := 0
+	for _, w := range m.watches {
+		if w.info.State != StateExpired {
+			active++
+		}
+	}
+	m.mu.Unlock()
+	if handler == nil {
+		return nil, fmt.Errorf("%s is not available (its provider is disabled)", tool)
+	}
+	if active >= m.cfg.MaxWatches {
+		return nil, fmt.Errorf("too many active watches (max: %d); delete one with %s first", m.cfg.MaxWatches, DeleteToolName)
+	}
+
+	// 評価は呼び出しの接続元・プロファイルを引き継ぎ、サーバーの終了か削除・期限切れで止める
+	watchCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(m.ctx, cancel)
+
+	now := time.Now()
+	w := &watch{
+		client:  auth.ClientName(ctx),
+		session: mcp.SessionID(ctx),
+		tool:    tool,
+		args:    args,
+		kind:    params.Kind,
+		cancel:  func() { stop(); cancel() },
+		info: Info{
+			Name:        params.Name,
+			Kind:        params.Kind,
+			ProjectID:   params.ProjectID,
+			Condition:   condition,
+			IntervalSec: params.IntervalSec,
+			WindowSec:   params.WindowSec,
+			CreatedAt:   now.UTC().Format(time.RFC3339),
+			ExpiresAt:   now.Add(time.Duration(params.ExpiresInMinutes) * time.Minute).UTC().Format(time.RFC3339),
+		},
+	}
+
+	// 最初の評価は同期的に行い、引数の誤りはここでエラーにする
+	firing, summary, _, err := m.evaluate(watchCtx, handler, w)
+	if err != nil {
+		w.cancel()
+		return nil, err
+	}
+	w.info.State = StateOK
+	if firing {
+		w.info.State = StateFiring
+		w.info.TriggeredCount = 1
+	}
+	w.info.Summary = summary
+	w.info.LastEvaluated = now.UTC().Format(time.RFC3339)
+	w.info.Evaluations = 1
+
+	m.mu.Lock()
+	w.info.ID = fmt.Sprintf("w%d", m.nextID)
+	m.nextID++
+	if w.info.Name == "" {
+		w.info.Name = w.info.ID
+	}
+	m.pruneExpired()
+	m.watches[w.info.ID] = w
+	info := w.info
+	m.mu.Unlock()
+
+	slog.Info("watch created", "id", info.ID, "condition", info.Condition, "interval_sec", info.IntervalSec, "expires_at", info.ExpiresAt)
+	go m.run(watchCtx, w, handler, now.Add(time.Duration(params.ExpiresInMinutes)*time.Minute))
+	return &info, nil
+}
+
+// normalize は既定値を補い、設定の範囲に収まるか確認する
+func (m *Manager) normalize(params *CreateParams) error {
+	if params.ProjectID == "" {
+		return fmt.Errorf("project_id is required")
+	}
+	if params.IntervalSec == 0 {
+		params.IntervalSec = max(60, m.cfg.MinIntervalSec)
+	}
+	if params.IntervalSec < m.cfg.MinIntervalSec {
+		return fmt.Errorf("interval_sec must be at least %d (got %d)", m.cfg.MinIntervalSec, params.IntervalSec)
+	}
+	if params.ExpiresInMinutes == 0 {
+		params.ExpiresInMinutes = min(60, m.cfg.MaxDurationMinutes)
+	}
+	if params.ExpiresInMinutes < 0 || params.ExpiresInMinutes > m.cfg.MaxDurationMinutes {
+		return fmt.Errorf("expires_in_minutes must be between 1 and %d (got %d)", m.cfg.MaxDurationMinutes, params.ExpiresInMinutes)
+	}
+	if params.WindowSec == 0 {
+		params.WindowSec = 300
+		// duration_sec だけ条件が続くのを見られる長さにする
+		if params.Kind == "metric" {
+			alignment := params.AlignmentPeriodSec
+			if alignment <= 0 {
+				alignment = 60
+			}
+			params.WindowSec = max(params.WindowSec, params.DurationSec+2*alignment)
+		}
+	}
+	if params.WindowSec < 0 {
+		return fmt.Errorf("window_sec must be positive (got %d)", params.WindowSec)
+	}
+	return nil
+}
+
+// run は監視を interval ごとに評価し、期限が来たら止める
+func (m *Manager) run(ctx context.Context, w *watch, handler mcp.ToolHandler, expires time.Time) {
+	ticker := time.NewTicker(time.Duration(w.info.IntervalSec) * time.Second)
+	defer ticker.Stop()
+	expiry := time.NewTimer(time.Until(expires))
+	defer expiry.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-expiry.C:
+			m.mu.Lock()
+			w.info.State = StateExpired
+			m.mu.Unlock()
+			w.cancel()
+			slog.Info("watch expired", "id", w.info.ID)
+			return
+		case <-ticker.C:
+		}
+
+		firing, summary, details, err := m.evaluate(ctx, handler, w)
+		if ctx.Err() != nil {
+			return
+		}
+		state := StateOK
+		switch {
+		case err != nil:
+			state, summary = StateError, err.Error()
+		case firing:
+			state = StateFiring
+		}
+		m.record(w, state, summary, details)
+	}
+}
+
+// record は評価結果を反映し、状態が変わったら通知する
+func (m *Manager) record(w *watch, state, summary string, details any) {
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	m.mu.Lock()
+	previous := w.info.State
+	w.info.State = state
+	w.info.Summary = summary
+	w.info.LastEvaluated = now
+	w.info.Evaluations++
+	if state == previous {
+		m.mu.Unlock()
+		return
+	}
+	if state == StateFiring && previous != StateError {
+		w.info.TriggeredCount++
+	}
+	event := Event{
+		WatchID:   w.info.ID,
+		Name:      w.info.Name,
+		ProjectID: w.info.ProjectID,
+		Condition: w.info.Condition,
+		State:     state,
+		Previous:  previous,
+		Time:      now,
+		Summary:   summary,
+		Details:   details,
+	}
+	w.info.Events = append(w.info.Events, event)
+	if len(w.info.Events) > maxEvents {
+		w.info.Events = w.info.Events[len(w.info.Events)-maxEvents:]
+	}
+	send, sinks := m.notify, m.sinks
+	m.mu.Unlock()
+
+	level := "notice"
+	switch state {
+	case StateFiring:
+		level = "warning"
+	case StateError:
+		level = "error"
+	}
+	slog.Info("watch state changed", "id", event.WatchID, "state", state, "previous", previous, "summary", summary)
+	if send != nil {
+		send(level, notifyLogger, event)
+	}
+	if sinks != nil {
+		sinks.Send(notify.Event{
+			Kind:  config.NotifyWatch,
+			Level: level,
+			Title: fmt.Sprintf("Watch %s is %s", event.Name, state),
+			Text:  fmt.Sprintf("%s (project %s)\n%s", event.Condition, event.ProjectID, summary),
+			Time:  now,
+			Data:  event,
+		})
+	}
+}
+
+// List returns the watches created by the client and session of ctx, oldest first
+func (m *Manager) List(ctx context.Context) []Info {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	watches := []Info{}
+	for _, w := range m.watches {
+		if w.visibleTo(ctx) {
+			watches = append(watches, w.info)
+		}
+	}
+	sort.Slice(watches, func(i, j int) bool {
+		return watches[i].CreatedAt < watches[j].CreatedAt || watches[i].CreatedAt == watches[j].CreatedAt && watches[i].ID < watches[j].ID
+	})
+	return watches
+}
+
+// Delete stops and removes a watch created by the client and session of ctx
+func (m *Manager) Delete(ctx context.Context, id string) (*Info, error) {
+	m.mu.Lock()
+	w, ok := m.watches[id]
+	if ok && !w.visibleTo(ctx) {
+		ok = false
+	}
+	var info Info
+	if ok {
+		delete(m.watches, id)
+		info = w.info
+	}
+	m.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("watch not found: %s", id)
+	}
+	w.cancel()
+	slog.Info("watch deleted", "id", id)
+	return &info, nil
+}
+
+// pruneExpired は期限切れの監視を古い順に捨て、max_watches 件までにする（m.mu を持って呼ぶ）
+func (m *Manager) pruneExpired() {
+	var expired []*watch
+	for _, w := range m.watches {
+		if w.info.State == StateExpired {
+			expired = append(expired, w)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool { return expired[i].info.ExpiresAt < expired[j].info.ExpiresAt })
+	for len(expired) >= m.cfg.MaxWatches {
+		delete(m.watches, expired[0].info.ID)
+		expired = expired[1:]
+	}
+}
@@ -2,17 +2,51 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"sort"
+	"strings"
 	"syscall"
+	"time"
 
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/keepalive"
+
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/auth"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/cache"
 	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/config"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/format"
 	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/guardrail"
-	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/history"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/i18n"
 	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/mcp"
-	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/notify"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/provider"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/redact"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/replay"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/spill"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/telemetry"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/timerange"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/tokens"
+	"github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/watch"
+
+	// ツールプロバイダ（init で provider.Register する）
+	_ "github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/assets"
+	_ "github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/cloudrun"
+	_ "github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/gke"
+	_ "github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/logging"
+	_ "github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/monitoring"
+	_ "github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/ops"
+	_ "github.com/kaz-under-the-bridge/google-cloud-ops-mcp/internal/security"
 )
 
 const (
@@ -27,212 +61,586 @@ func main() {
 func realMain() int {
 	// Parse flags
 	configPath := flag.String("config", "", "Path to config file (optional)")
+	recordDir := flag.String("record", "", "Record GCP API responses into this directory (a cassette) for -replay")
+	replayDir := flag.String("replay", "", "Serve GCP API responses recorded with -record from this directory, without calling GCP")
+	validateConfig := flag.Bool("validate-config", false, "Validate the config (unknown keys, types, ranges), print the effective config and exit")
+	overrides := map[string]*string{}
+	for _, o := range config.Overrides {
+		usage := fmt.Sprintf("%s (env: %s)", o.Usage, o.EnvName())
+		if o.IsSwitch() {
+			v := new(string)
+			flag.Var(switchFlag{v}, o.Key, usage)
+			overrides[o.Key] = v
+			continue
+		}
+		overrides[o.Key] = flag.String(o.Key, "", usage)
+	}
 	flag.Parse()
 
+	// 明示的に指定されたフラグのみ設定を上書きする
+	flagValues := map[string]string{}
+	flag.Visit(func(f *flag.Flag) {
+		if p, ok := overrides[f.Name]; ok {
+			flagValues[f.Name] = *p
+		}
+	})
+
+	if *validateConfig {
+		return runValidateConfig(*configPath, flagValues)
+	}
+
+	// stdout は JSON-RPC 専用にする。以降の os.Stdout への書き込み（ライブラリの fmt.Print など）は
+	// stderr に向け、プロトコルのストリームを壊さない
+	protocolOut := os.Stdout
+	os.Stdout = os.Stderr
+
+	cfg, err := config.Load(*configPath, flagValues)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to load config: %v\n", err)
+		return 1
+	}
+	// MCP は stdout を使うためログは stderr（log_file があればそのファイル）に JSON で出す
+	closeLog, err := setupLogger(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer closeLog()
+
+	cassette, err := openCassette(*recordDir, *replayDir)
+	if err != nil {
+		slog.Error("failed to open cassette", "error", err)
+		return 1
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// Handle signals
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-	go func() {
-		<-sigCh
-		cancel()
-	}()
+	// シグナルでは新しいリクエストの受け付けだけを止め、処理中のツール呼び出しは
+	// shutdown_timeout_sec まで待つ（APIクライアントが使う ctx はその後に閉じる）
+	stopCtx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	if err := run(ctx, *configPath); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	if err := run(ctx, stopCtx, cfg, cassette, protocolOut); err != nil {
+		slog.Error("server stopped", "error", err)
 		return 1
 	}
 	return 0
 }
 
-func run(ctx context.Context, configPath string) error {
-	// Load config
-	cfg, err := config.Load(configPath)
+// switchFlag は値なしで指定できる（-quiet = -quiet=true）文字列のフラグ
+type switchFlag struct{ value *string }
+
+func (f switchFlag) String() string {
+	if f.value == nil {
+		return ""
+	}
+	return *f.value
+}
+
+func (f switchFlag) Set(v string) error {
+	*f.value = v
+	return nil
+}
+
+func (f switchFlag) IsBoolFlag() bool { return true }
+
+// openCassette は -record / -replay のカセットを開く（どちらも指定がなければ nil）
+// 相対指定の時間範囲は録画時刻を基準にし、再生時も同じリクエストになるようにする
+func openCassette(recordDir, replayDir string) (*replay.Cassette, error) {
+	var cassette *replay.Cassette
+	var err error
+	switch {
+	case recordDir != "" && replayDir != "":
+		return nil, fmt.Errorf("-record and -replay cannot be used together")
+	case recordDir != "":
+		cassette, err = replay.Open(recordDir, replay.Record)
+	case replayDir != "":
+		cassette, err = replay.Open(replayDir, replay.Replay)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	recordedAt := cassette.RecordedAt()
+	timerange.Now = func() time.Time { return recordedAt }
+	slog.Info("using API cassette", "record", recordDir, "replay", replayDir, "recorded_at", recordedAt)
+	return cassette, nil
+}
+
+// setupLogger は stderr（log_file があればそのファイル）への構造化ログ（JSON）をデフォルトのロガーにする
+// log パッケージの出力も slog を通る。返す関数でログファイルを閉じる
+func setupLogger(cfg *config.Config) (func(), error) {
+	var out io.Writer = os.Stderr
+	closeLog := func() {}
+	if cfg.LogFile != "" {
+		f, err := os.OpenFile(cfg.LogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open log_file: %w", err)
+		}
+		out = f
+		closeLog = func() { _ = f.Close() }
+	}
+	slog.SetDefault(slog.New(slog.NewJSONHandler(out, &slog.HandlerOptions{
+		Level: cfg.SlogLevel(),
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			// 監査ログは "ERROR+4" ではなく "AUDIT" と出す
+			if a.Key == slog.LevelKey && a.Value.Any() == guardrail.AuditLevel {
+				a.Value = slog.StringValue("AUDIT")
+			}
+			return a
+		},
+	})))
+	return closeLog, nil
+}
+
+// runValidateConfig は設定を検証し、問題があれば stderr に出力する
+// 問題がなければ正規化した実効設定を stdout に出力する
+func runValidateConfig(configPath string, flagValues map[string]string) int {
+	exitCode := 0
+
+	if configPath != "" {
+		if _, err := os.Stat(configPath); err == nil {
+			problems, err := config.CheckUnknownKeys(configPath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 1
+			}
+			for _, p := range problems {
+				fmt.Fprintf(os.Stderr, "Error: %s\n", p)
+				exitCode = 1
+			}
+		}
+	}
+
+	cfg, err := config.Load(configPath, flagValues)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	out, err := cfg.YAML()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Print(string(out))
+
+	return exitCode
+}
+
+// credentialOptions は credentials_file の認証情報を使うクライアントオプションを返す（未指定なら ADC を使うので nil）
+func credentialOptions(cfg *config.Config) ([]option.ClientOption, error) {
+	if cfg.CredentialsFile == "" {
+		return nil, nil
+	}
+	typ, err := cfg.CredentialsFileType()
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("using credentials file", "path", cfg.CredentialsFile, "type", typ)
+	return []option.ClientOption{option.WithAuthCredentialsFile(option.CredentialsType(typ), cfg.CredentialsFile)}, nil
+}
+
+// grpcDialOptions は grpc の接続設定（接続確立のタイムアウト・keepalive）のクライアントオプションを返す
+// 接続先（grpc.endpoints）はサービスごとに違うので provider.Env.GRPCOptionsFor で付ける
+func grpcDialOptions(cfg config.GRPC) []option.ClientOption {
+	var opts []option.ClientOption
+	if cfg.DialTimeoutSec > 0 {
+		opts = append(opts, option.WithGRPCDialOption(grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           backoff.DefaultConfig,
+			MinConnectTimeout: time.Duration(cfg.DialTimeoutSec) * time.Second,
+		})))
+	}
+	if cfg.KeepaliveTimeSec > 0 {
+		params := keepalive.ClientParameters{Time: time.Duration(cfg.KeepaliveTimeSec) * time.Second}
+		if cfg.KeepaliveTimeoutSec > 0 {
+			params.Timeout = time.Duration(cfg.KeepaliveTimeoutSec) * time.Second
+		}
+		opts = append(opts, option.WithGRPCDialOption(grpc.WithKeepaliveParams(params)))
+	}
+	return opts
+}
+
+// out は stdio トランスポートの JSON-RPC の出力先（元の stdout）
+func run(ctx, stopCtx context.Context, cfg *config.Config, cassette *replay.Cassette, out io.Writer) error {
+	slog.Info("starting server", "version", serverVersion, "mode", cfg.Mode, "log_level", cfg.LogLevel)
+
+	credOpts, err := credentialOptions(cfg)
+	if err != nil {
+		return err
+	}
+
+	// サーバー自身のメトリクス（ops.server_stats、OTLP / Prometheus エクスポート）
+	telem, err := telemetry.New(ctx, cfg.Telemetry, serverVersion)
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return fmt.Errorf("failed to set up telemetry: %w", err)
 	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = telem.Shutdown(shutdownCtx)
+	}()
+	telem.ServePrometheus(ctx)
 
 	// Create guardrail
 	guard := guardrail.New(cfg)
 
+	// 監視の状態変化・ガードレールの拒否を Slack / HTTP に通知する（notifications.sinks）
+	sinks, err := notify.New(ctx, cfg.Notifications)
+	if err != nil {
+		return err
+	}
+	if sinks.Wants(config.NotifyGuardrail) {
+		guard.SetViolationHook(func(ctx context.Context, tool string, err error) {
+			client := auth.ClientName(ctx)
+			if client == "" {
+				client = "stdio"
+			}
+			// 同じ接続元が同じ呼び出しを繰り返しても10分に1回だけ通知する
+			sinks.SendOnce(client+"\x00"+tool+"\x00"+err.Error(), notify.Event{
+				Kind:  config.NotifyGuardrail,
+				Level: "warning",
+				Title: fmt.Sprintf("Guardrail rejected %s", tool),
+				Text:  fmt.Sprintf("client %s: %v", client, err),
+				Data:  map[string]string{"tool": tool, "client": client, "error": err.Error()},
+			})
+		})
+	}
+
 	// Create MCP server
 	server := mcp.NewServer(serverName, serverVersion)
-
-	// Create Cloud Logging client
-	loggingClient, err := logging.NewClient(ctx)
+	server.SetIO(os.Stdin, out)
+	// logging/setLevel を呼んだクライアントにはログを notifications/message でも送る（HTTP・ソケットでは送れないので stdio のみ）
+	if cfg.EffectiveTransport() == config.TransportStdio {
+		slog.SetDefault(slog.New(server.LogHandler(slog.Default().Handler())))
+	}
+	server.AllowWriteTools(cfg.WriteEnabled())
+	// 設定で書き換えた説明は翻訳より優先するので、翻訳のさらに外側
+	described := map[string]bool{}
+	if len(cfg.Tools.Descriptions) > 0 {
+		server.Use(describeTools(cfg.Tools.Descriptions, described))
+	}
+	if len(cfg.Tools.Hidden) > 0 {
+		server.SetToolFilter(func(ctx context.Context, name string) bool {
+			if !cfg.Tools.IsHidden(name) {
+				return true
+			}
+			client := auth.ClientFrom(ctx)
+			return client != nil && client.ShowsTool(name)
+		})
+	}
+	// 説明とエラーメッセージの翻訳（他のミドルウェアが足した説明・返したエラーも訳すため最も外側）
+	catalog, err := i18n.Load(cfg.Locale)
 	if err != nil {
-		return fmt.Errorf("failed to create logging client: %w", err)
+		return err
+	}
+	if catalog != nil {
+		server.Use(catalog.Middleware())
+	}
+	server.Use(telem.Middleware())
+	// 呼び出しに適用するガードレールプロファイル（接続元の profile か設定の profile）
+	server.Use(guard.ProfileMiddleware())
+	server.Use(resolveProjectID(cfg, guard))
+	server.Use(format.Middleware())
+	// 結果の stats にトークン数の見積もりを付ける（大きすぎれば絞り込み方も）
+	// token_budget.max_tokens を超えた結果は要約に置き換え、全体は ops.result_page で読めるようにする
+	estimator := tokens.NewEstimator(cfg.TokenBudget, nil)
+	server.Use(estimator.Middleware())
+
+	// 大きな結果はファイル/GCSに退避し、要約とリソースURIを返す
+	if cfg.Spillover.Enabled {
+		spillStore := spill.NewStore(cfg.Spillover, credOpts...)
+		server.SetResourceProvider(spillStore)
+		server.Use(spillStore.Middleware())
+	}
+
+	// ツール呼び出し履歴（エイリアス解決後の引数を記録する）
+	recorder := history.NewRecorder(cfg.History.MaxEntries)
+	server.Use(recorder.Middleware())
+
+	// 結果のマスキング（キャッシュ・履歴・退避先にもマスク後の結果だけが渡る）
+	if cfg.HasRedaction() {
+		redactor, err := redact.New(cfg.Redaction.Patterns)
+		if err != nil {
+			return err
+		}
+		for name, p := range cfg.Profiles {
+			if err := redactor.AddProfile(name, p.Redaction.Patterns); err != nil {
+				return err
+			}
+		}
+		server.Use(redactor.Middleware())
+	}
+
+	// 同じ引数の読み取りツール呼び出しに結果を再利用する（サーバー自身の状態を返すツールは対象外）
+	if cfg.Cache.TTLSeconds > 0 {
+		uncached := append([]string{telemetry.ToolName, history.ToolName, "ops.health", "ops.whoami"}, watch.ToolNames...)
+		server.Use(cache.New(cfg.Cache, uncached...).Middleware())
+	}
+
+	// バックグラウンドの監視（評価はキャッシュを通さず最新の結果を取り、ガードレールは通す）
+	// 状態変化は stdio ならクライアントに notifications/message で送る（HTTP・ソケットでは notifications.sinks のみ）
+	var watcher *watch.Manager
+	if cfg.Watches.Enabled {
+		watcher = watch.NewManager(ctx, cfg.Watches)
+		if cfg.EffectiveTransport() == config.TransportStdio {
+			watcher.SetNotifier(server.SendLogMessage)
+		}
+		if sinks.Wants(config.NotifyWatch) {
+			watcher.SetSinks(sinks)
+		}
+		server.Use(watcher.Middleware())
 	}
-	defer func() { _ = loggingClient.Close() }()
 
-	// Create Cloud Monitoring client
-	monitoringClient, err := monitoring.NewClient(ctx)
+	// 共通のガードレール（project_id の許可判定・time_range の検証）、IAM 権限の事前確認と書き込みツールの監査ログ
+	// エイリアス解決の後、各ツールのハンドラの直前で動く
+	server.Use(guard.Middleware())
+	// VPC Service Controls の境界による拒否に境界の名前・理由を添える（事前確認の拒否も対象）
+	server.Use(guard.VPCSCMiddleware())
+	server.Use(guard.PreflightMiddleware())
+	server.Use(guard.AuditMiddleware())
+
+	// GCP連携ごとのツールプロバイダ（各パッケージの init で登録される）
+	env := provider.Env{Config: cfg, Guard: guard, CredentialOptions: credOpts}
+	env.GRPCOptions, env.HTTPOptions = credOpts, credOpts
+	if cassette != nil {
+		env.GRPCOptions = cassette.GRPCOptions(credOpts...)
+		env.HTTPOptions = cassette.HTTPOptions(credOpts...)
+	}
+	// 呼び出しごとの billing_project を quota project として送る（Logging / Monitoring）
+	env.GRPCOptions = append(append([]option.ClientOption{}, env.GRPCOptions...), guardrail.BillingGRPCOptions()...)
+	env.GRPCOptions = append(env.GRPCOptions, grpcDialOptions(cfg.GRPC)...)
+	providers, err := provider.Build(ctx, env)
 	if err != nil {
-		return fmt.Errorf("failed to create monitoring client: %w", err)
+		return err
+	}
+	defer func() { _ = provider.Close(providers) }()
+	if err := provider.RegisterTools(server, providers); err != nil {
+		return err
 	}
-	defer func() { _ = monitoringClient.Close() }()
+	// 引数の補完（completion/complete）: project_id は許可リスト、metric_type / log_name は各プロバイダが返す
+	server.SetCompletionProvider(provider.Completions(providers, guard))
 
-	// Register logging.query tool (with guardrail)
-	server.RegisterTool(mcp.Tool{
-		Name:        "logging.query",
-		Description: "Search Cloud Logging logs. Equivalent to Logs Explorer.",
-		InputSchema: mcp.ToolSchema{
-			Type: "object",
-			Properties: map[string]mcp.Property{
-				"project_id": {
-					Type:        "string",
-					Description: "GCP project ID",
-				},
-				"filter": {
-					Type:        "string",
-					Description: "Logging Query Language filter (e.g., 'severity>=ERROR')",
-				},
-				"time_range": {
-					Type:        "object",
-					Description: "Time range for the query",
-					Properties: map[string]mcp.Property{
-						"start": {
-							Type:        "string",
-							Description: "Start time (RFC3339 or relative like '-1h', '-30m')",
-						},
-						"end": {
-							Type:        "string",
-							Description: "End time (RFC3339 or 'now')",
-							Default:     "now",
-						},
-					},
-				},
-				"limit": {
-					Type:        "integer",
-					Description: fmt.Sprintf("Maximum number of entries to return (default: 200, max: %d)", cfg.Limits.MaxLogEntries),
-					Default:     200,
-				},
-			},
-			Required: []string{"project_id"},
-		},
-	}, loggingClient.QueryHandlerWithGuardrail(guard))
+	// Register ops.create_watch / ops.list_watches / ops.delete_watch tools
+	if watcher != nil {
+		handlers := watcher.Handlers()
+		for _, tool := range watcher.Tools() {
+			server.RegisterTool(tool, handlers[tool.Name])
+		}
+	}
 
-	// Register monitoring.query_time_series tool (with guardrail)
+	// Register ops.server_stats tool
 	server.RegisterTool(mcp.Tool{
-		Name:        "monitoring.query_time_series",
-		Description: "Query Cloud Monitoring time series data.",
+		Name:        telemetry.ToolName,
+		Description: "Show this MCP server's own metrics since startup: calls, errors, GCP API errors, latency (histogram and p50/p95/p99), params and result sizes and calls rejected as too large per tool, and cache hit ratios. For operators of a shared deployment.",
 		InputSchema: mcp.ToolSchema{
-			Type: "object",
-			Properties: map[string]mcp.Property{
-				"project_id": {
-					Type:        "string",
-					Description: "GCP project ID",
-				},
-				"metric_type": {
-					Type:        "string",
-					Description: "Metric type (e.g., 'run.googleapis.com/request_count')",
-				},
-				"resource_type": {
-					Type:        "string",
-					Description: "Resource type (e.g., 'cloud_run_revision')",
-				},
-				"filters": {
-					Type:        "object",
-					Description: "Additional filters as key-value pairs",
-				},
-				"alignment_period_sec": {
-					Type:        "integer",
-					Description: "Alignment period in seconds (default: 60)",
-					Default:     60,
-				},
-				"time_range": {
-					Type:        "object",
-					Description: "Time range for the query",
-					Properties: map[string]mcp.Property{
-						"start": {
-							Type:        "string",
-							Description: "Start time (RFC3339 or relative like '-1h', '-30m')",
-						},
-						"end": {
-							Type:        "string",
-							Description: "End time (RFC3339 or 'now')",
-							Default:     "now",
-						},
-					},
-				},
-				"max_series": {
-					Type:        "integer",
-					Description: fmt.Sprintf("Maximum number of time series to return (default: 20, max: %d)", cfg.Limits.MaxTimeSeries),
-					Default:     20,
-				},
-			},
-			Required: []string{"project_id", "metric_type"},
+			Type:       "object",
+			Properties: map[string]mcp.Property{},
 		},
-	}, monitoringClient.QueryTimeSeriesHandlerWithGuardrail(guard))
+		OutputSchema: mcp.OutputSchemaFor[telemetry.ServerStats](),
+	}, telem.Handler())
 
-	// Register logging.top_errors tool (with guardrail)
+	// Register ops.recent_queries tool
 	server.RegisterTool(mcp.Tool{
-		Name:        "logging.top_errors",
-		Description: "Aggregate error logs and return top N most frequent errors. Useful for identifying common issues.",
+		Name:        history.ToolName,
+		Description: "Recall recent tool calls of this session (tool, arguments, time, stats) to see what has already been looked at. Pass rerun_index to re-run a previous read-only call with the same arguments.",
 		InputSchema: mcp.ToolSchema{
 			Type: "object",
 			Properties: map[string]mcp.Property{
-				"project_id": {
-					Type:        "string",
-					Description: "GCP project ID",
-				},
-				"time_range": {
-					Type:        "object",
-					Description: "Time range for the query",
-					Properties: map[string]mcp.Property{
-						"start": {
-							Type:        "string",
-							Description: "Start time (RFC3339 or relative like '-1h', '-30m')",
-						},
-						"end": {
-							Type:        "string",
-							Description: "End time (RFC3339 or 'now')",
-							Default:     "now",
-						},
-					},
-				},
-				"group_by": {
-					Type:        "string",
-					Description: "How to group errors: 'log_name', 'resource_type', or 'message' (default: 'log_name')",
-					Default:     "log_name",
-				},
 				"limit": {
 					Type:        "integer",
-					Description: "Number of top error groups to return (default: 10, max: 50)",
-					Default:     10,
-				},
-			},
-			Required: []string{"project_id"},
-		},
-	}, loggingClient.TopErrorsHandlerWithGuardrail(guard))
-
-	// Register monitoring.list_metric_descriptors tool (with guardrail)
-	server.RegisterTool(mcp.Tool{
-		Name:        "monitoring.list_metric_descriptors",
-		Description: "List available metric descriptors in a project. Useful for discovering what metrics are available.",
-		InputSchema: mcp.ToolSchema{
-			Type: "object",
-			Properties: map[string]mcp.Property{
-				"project_id": {
-					Type:        "string",
-					Description: "GCP project ID",
+					Description: "Maximum number of entries to return, newest first (default: 20)",
+					Default:     20,
 				},
-				"filter": {
+				"tool": {
 					Type:        "string",
-					Description: "Optional filter (e.g., 'metric.type = starts_with(\"run.googleapis.com\")')",
+					Description: "Only return calls of this tool (e.g., 'logging.query')",
 				},
-				"limit": {
+				"rerun_index": {
 					Type:        "integer",
-					Description: "Maximum number of descriptors to return (default: 100, max: 500)",
-					Default:     100,
+					Description: "Index of a previous call to re-run with the same arguments",
 				},
 			},
-			Required: []string{"project_id"},
 		},
-	}, monitoringClient.ListMetricDescriptorsHandlerWithGuardrail(guard))
+	}, recorder.Handler())
+
+	// Register ops.diff_results tool
+	server.RegisterTool(history.DiffTool(), recorder.DiffHandler())
+
+	// Register ops.result_page tool
+	server.RegisterTool(tokens.PageTool(), estimator.PageHandler())
+
+	// 登録されなかったツール（名前の誤り・無効なプロバイダ・読み取り専用モード）の説明は使われない
+	for name := range cfg.Tools.Descriptions {
+		if !described[name] {
+			slog.Warn("tools.descriptions: no such tool is registered", "tool", name)
+		}
+	}
 
 	// Run server
-	return server.Run(ctx)
+	server.SetDrainTimeout(time.Duration(cfg.ShutdownTimeout) * time.Second)
+	server.SetMaxParamsBytes(cfg.MaxParamsBytes)
+	server.SetRequestObserver(telem.Observe)
+	switch cfg.EffectiveTransport() {
+	case config.TransportHTTP:
+		return serveHTTP(stopCtx, cfg, server)
+	case config.TransportUnix:
+		return serveUnix(stopCtx, cfg, server)
+	}
+	return server.Run(stopCtx)
+}
+
+// serveHTTP は MCP を HTTP で待ち受ける（http.clients があれば bearer トークンで接続元を認証する）
+// 終了シグナル後は新しいリクエストを受け付けず、処理中のリクエストを shutdown_timeout_sec まで待つ
+func serveHTTP(stopCtx context.Context, cfg *config.Config, server *mcp.Server) error {
+	// WebSocket も同じ認証を通す（接続時の Authorization ヘッダで認証し、接続中の呼び出しはそのクライアントとして扱う）
+	protect := func(h http.Handler) http.Handler { return h }
+	if len(cfg.HTTP.Clients) > 0 {
+		authenticator, err := auth.New(cfg)
+		if err != nil {
+			return err
+		}
+		protect = authenticator.Middleware
+	} else {
+		slog.Warn("serving HTTP without authentication (http.allow_unauthenticated)")
+	}
+	// HTTP セッション（Mcp-Session-Id）は接続元ごとに分け、他の接続元の ID は知らないものとして扱う
+	server.SetSessionOwner(auth.ClientName)
+	mux := http.NewServeMux()
+	mux.Handle(cfg.HTTP.Path, protect(server.HTTPHandler(stopCtx)))
+	if cfg.HTTP.WebSocketPath != "" {
+		mux.Handle(cfg.HTTP.WebSocketPath, protect(server.WebSocketHandler(stopCtx)))
+	}
+	srv := &http.Server{Addr: cfg.HTTP.Listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- srv.ListenAndServe() }()
+	slog.Info("serving MCP over HTTP", "listen", cfg.HTTP.Listen, "path", cfg.HTTP.Path, "websocket_path", cfg.HTTP.WebSocketPath, "clients", len(cfg.HTTP.Clients))
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-stopCtx.Done():
+	}
+	drainCtx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownTimeout)*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(drainCtx); err != nil {
+		slog.Warn("drain timeout exceeded: cancelling in-flight requests")
+		_ = srv.Close()
+	}
+	// Shutdown は WebSocket の接続を待たないので、処理中の呼び出しが終わって閉じるのを別に待つ
+	if err := server.DrainWebSockets(drainCtx); err != nil {
+		slog.Warn("drain timeout exceeded: closing WebSocket connections with in-flight requests")
+	}
+	slog.Info("shut down: stopped accepting requests and drained in-flight requests")
+	return nil
+}
+
+// serveUnix は MCP を Unix ドメインソケットで待ち受ける（接続ごとに stdio と同じ形式でやりとりする）
+// ソケットは所有者だけが読み書きできる権限で作り、終了時に削除する
+func serveUnix(stopCtx context.Context, cfg *config.Config, server *mcp.Server) error {
+	if fi, err := os.Lstat(cfg.Socket); err == nil {
+		// 前回のプロセスが残したソケットは消すが、動いているサーバーのソケットやソケット以外のファイルは消さない
+		if fi.Mode()&os.ModeSocket == 0 {
+			return fmt.Errorf("socket %s exists and is not a socket", cfg.Socket)
+		}
+		if conn, err := net.Dial("unix", cfg.Socket); err == nil {
+			_ = conn.Close()
+			return fmt.Errorf("socket %s is in use by another server", cfg.Socket)
+		}
+		if err := os.Remove(cfg.Socket); err != nil {
+			return fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+	}
+	ln, err := net.Listen("unix", cfg.Socket)
+	if err != nil {
+		return fmt.Errorf("failed to listen on socket: %w", err)
+	}
+	defer ln.Close()
+	if err := os.Chmod(cfg.Socket, 0o600); err != nil {
+		return fmt.Errorf("failed to restrict socket permissions: %w", err)
+	}
+	slog.Info("serving MCP over Unix domain socket", "socket", cfg.Socket)
+	if err := server.ServeUnix(stopCtx, ln); err != nil {
+		return err
+	}
+	slog.Info("shut down: stopped accepting requests and drained in-flight requests")
+	return nil
+}
+
+// describeTools はツールの説明を設定（tools.descriptions）の説明に置き換え、置き換えたツールを described に記録する
+func describeTools(descriptions map[string]string, described map[string]bool) mcp.Middleware {
+	return func(tool *mcp.Tool, next mcp.ToolHandler) mcp.ToolHandler {
+		if description, ok := descriptions[tool.Name]; ok {
+			tool.Description = description
+			described[tool.Name] = true
+		}
+		return next
+	}
+}
+
+// resolveProjectID は project_id を持つツールに対し、エイリアス展開とデフォルトプロジェクト補完を行う
+// デフォルトプロジェクトが設定されている場合は project_id を必須から外す
+func resolveProjectID(cfg *config.Config, guard *guardrail.Guardrail) mcp.Middleware {
+	return func(tool *mcp.Tool, next mcp.ToolHandler) mcp.ToolHandler {
+		prop, ok := tool.InputSchema.Properties["project_id"]
+		if !ok {
+			return next
+		}
+
+		if len(cfg.ProjectAliases) > 0 {
+			aliases := make([]string, 0, len(cfg.ProjectAliases))
+			for alias := range cfg.ProjectAliases {
+				aliases = append(aliases, alias)
+			}
+			sort.Strings(aliases)
+			prop.Description += fmt.Sprintf(" (aliases: %s)", strings.Join(aliases, ", "))
+		}
+		if cfg.DefaultProjectID != "" {
+			prop.Description += fmt.Sprintf(" (default: %s)", cfg.DefaultProjectID)
+			required := []string{}
+			for _, r := range tool.InputSchema.Required {
+				if r != "project_id" {
+					required = append(required, r)
+				}
+			}
+			tool.InputSchema.Required = required
+		}
+		tool.InputSchema.Properties["project_id"] = prop
+
+		return func(ctx context.Context, args json.RawMessage) (any, error) {
+			fields := map[string]json.RawMessage{}
+			if len(args) > 0 {
+				if err := json.Unmarshal(args, &fields); err != nil {
+					return nil, fmt.Errorf("failed to parse arguments: %w", err)
+				}
+			}
+
+			var projectID string
+			if raw, ok := fields["project_id"]; ok {
+				if err := json.Unmarshal(raw, &projectID); err != nil {
+					return nil, fmt.Errorf("project_id must be a string")
+				}
+			}
+
+			resolved := guard.ResolveProjectID(projectID)
+			if resolved == projectID {
+				return next(ctx, args)
+			}
+
+			raw, err := json.Marshal(resolved)
+			if err != nil {
+				return nil, err
+			}
+			fields["project_id"] = raw
+			rewritten, err := json.Marshal(fields)
+			if err != nil {
+				return nil, err
+			}
+			return next(ctx, rewritten)
+		}
+	}
 }